@@ -0,0 +1,83 @@
+package v1
+
+// ClusterAlertsSpec defines the alerting rules generated for the cluster. They are scoped to the
+// metrics of the cluster and replace the shipped alerts with the same names in its PrometheusRule
+type ClusterAlertsSpec struct {
+	// Duration the health of the cluster must be RED before alerting. Defaults to 7m
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	RedFor string `json:"redFor,omitempty"`
+
+	// Duration the health of the cluster must be YELLOW before alerting. Defaults to 20m
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	YellowFor string `json:"yellowFor,omitempty"`
+
+	// Duration the disk usage of a node must exceed a disk watermark of the cluster before
+	// alerting. The watermarks themselves are the ones of the cluster settings. Defaults to 5m
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	DiskWatermarkFor string `json:"diskWatermarkFor,omitempty"`
+
+	// Percentage of the disk used by a node above which to alert for the low watermark, e.g. to
+	// alert before the watermark is reached. Defaults to the low watermark of the cluster settings
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +nullable
+	// +optional
+	DiskLowPercent *int32 `json:"diskLowPercent,omitempty"`
+
+	// Percentage of the disk used by a node above which to alert for the high watermark. Defaults
+	// to the high watermark of the cluster settings
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +nullable
+	// +optional
+	DiskHighPercent *int32 `json:"diskHighPercent,omitempty"`
+
+	// Percentage of the disk used by a node above which to alert for the flood stage watermark.
+	// Defaults to the flood stage watermark of the cluster settings
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +nullable
+	// +optional
+	DiskFloodStagePercent *int32 `json:"diskFloodStagePercent,omitempty"`
+
+	// Number of pending tasks of the master above which to alert. Defaults to 20
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +nullable
+	// +optional
+	PendingTasks *int32 `json:"pendingTasks,omitempty"`
+
+	// Duration the pending tasks must exceed their threshold before alerting. Defaults to 10m
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	PendingTasksFor string `json:"pendingTasksFor,omitempty"`
+
+	// Percentage of the JVM heap used by a node above which to alert. Defaults to 75
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +nullable
+	// +optional
+	HeapUsagePercent *int32 `json:"heapUsagePercent,omitempty"`
+
+	// Duration the heap usage must exceed its threshold before alerting. Defaults to 10m
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	HeapUsageFor string `json:"heapUsageFor,omitempty"`
+
+	// Labels added to the generated alerts, e.g. to route them to the owners of the cluster
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
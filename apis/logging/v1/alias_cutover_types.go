@@ -0,0 +1,130 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchaliascutovers,categories=logging,shortName=escutover
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="Alias",JSONPath=".spec.alias",type=string
+// +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// A migration of an alias from its current index to a new index, e.g. one with changed mappings
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Alias Cutover"
+type ElasticsearchAliasCutover struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchAliasCutoverSpec   `json:"spec,omitempty"`
+	Status ElasticsearchAliasCutoverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchAliasCutoverList contains a list of ElasticsearchAliasCutover
+type ElasticsearchAliasCutoverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchAliasCutover `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchAliasCutover{}, &ElasticsearchAliasCutoverList{})
+}
+
+// ElasticsearchAliasCutoverSpec defines the alias to migrate. The documents of the source index are
+// copied to the target index before the target index becomes the write index of the alias. During the
+// dual write window the alias reads from both indices while the documents written to the source index
+// in the meantime are copied. The source index is removed from the alias once the document counts and
+// a sample of the documents of both indices match, otherwise the writes move back to the source index.
+// The cutover is started once, changes to the spec after it started are ignored. Deleting the resource
+// stops the cutover without changing the alias
+type ElasticsearchAliasCutoverSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace holding the alias
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The alias to migrate. Its write index is the source index
+	//
+	// +kubebuilder:validation:MinLength=1
+	Alias string `json:"alias"`
+
+	// The index to migrate the alias to. It must be created with its mappings before the cutover starts
+	//
+	// +kubebuilder:validation:MinLength=1
+	TargetIndex string `json:"targetIndex"`
+
+	// How long the alias reads from both indices before the documents are verified (e.g. 30m). Defaults to 10m
+	//
+	// +optional
+	DualWriteWindow *metav1.Duration `json:"dualWriteWindow,omitempty"`
+
+	// Number of random documents of the source index compared with the target index. Defaults to 100
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SampleSize *int32 `json:"sampleSize,omitempty"`
+}
+
+// ElasticsearchAliasCutoverPhase is the state of an alias cutover
+type ElasticsearchAliasCutoverPhase string
+
+const (
+	ElasticsearchAliasCutoverPhasePending     ElasticsearchAliasCutoverPhase = "Pending"
+	ElasticsearchAliasCutoverPhaseBackfilling ElasticsearchAliasCutoverPhase = "Backfilling"
+	ElasticsearchAliasCutoverPhaseDualWrite   ElasticsearchAliasCutoverPhase = "DualWrite"
+	ElasticsearchAliasCutoverPhaseCatchingUp  ElasticsearchAliasCutoverPhase = "CatchingUp"
+	ElasticsearchAliasCutoverPhaseCompleted   ElasticsearchAliasCutoverPhase = "Completed"
+	ElasticsearchAliasCutoverPhaseFailed      ElasticsearchAliasCutoverPhase = "Failed"
+)
+
+// ElasticsearchAliasCutoverStatus represents the progress of an alias cutover
+type ElasticsearchAliasCutoverStatus struct {
+	// +optional
+	Phase ElasticsearchAliasCutoverPhase `json:"phase,omitempty"`
+
+	// The write index of the alias when the cutover started
+	//
+	// +optional
+	SourceIndex string `json:"sourceIndex,omitempty"`
+
+	// ID of the running reindex task in the cluster
+	//
+	// +optional
+	TaskID string `json:"taskID,omitempty"`
+
+	// Start of the dual write window
+	//
+	// +optional
+	DualWriteStartTime *metav1.Time `json:"dualWriteStartTime,omitempty"`
+
+	// Number of documents of the source index when verified
+	//
+	// +optional
+	SourceCount int64 `json:"sourceCount,omitempty"`
+
+	// Number of documents of the target index when verified
+	//
+	// +optional
+	TargetCount int64 `json:"targetCount,omitempty"`
+
+	// Number of sampled documents compared between the indices
+	//
+	// +optional
+	SampledDocuments int32 `json:"sampledDocuments,omitempty"`
+
+	// Number of sampled documents missing from the target index or differing from the source index
+	//
+	// +optional
+	MismatchedDocuments int32 `json:"mismatchedDocuments,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message about a pending or failed cutover
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnalysisFilesUpdatePolicy defines how the nodes load the changed files of an analysis ConfigMap
+//
+// +kubebuilder:validation:Enum=Reload;Restart
+type AnalysisFilesUpdatePolicy string
+
+const (
+	// AnalysisFilesReload reloads the search analyzers of the indices. Only analyzers using
+	// updateable token filters (e.g. synonyms) load the changed files
+	AnalysisFilesReload AnalysisFilesUpdatePolicy = "Reload"
+	// AnalysisFilesRestart restarts the nodes to load the changed files
+	AnalysisFilesRestart AnalysisFilesUpdatePolicy = "Restart"
+)
+
+// AnalysisFilesSpec references a ConfigMap whose keys are mounted as files into the config/analysis
+// directory of the Elasticsearch nodes (e.g. synonyms, stopwords or dictionaries). The keys must be
+// unique across the ConfigMaps
+type AnalysisFilesSpec struct {
+	// Name of the ConfigMap in the namespace of the cluster
+	//
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+
+	// How the nodes load the changed files. Defaults to Reload
+	//
+	// +optional
+	UpdatePolicy AnalysisFilesUpdatePolicy `json:"updatePolicy,omitempty"`
+}
+
+// AnalysisFilesStatus represents the reloadable analysis files loaded by the nodes
+type AnalysisFilesStatus struct {
+	// Hash of the reloadable analysis files of the ConfigMaps
+	Hash string `json:"hash"`
+
+	// Time the reloadable analysis files changed. The search analyzers are reloaded once the
+	// kubelets updated the files of the nodes
+	//
+	// +optional
+	ChangedSince *metav1.Time `json:"changedSince,omitempty"`
+}
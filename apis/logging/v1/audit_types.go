@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AuditOutputType is where the security plugin of the nodes writes the audit events to
+//
+// +kubebuilder:validation:Enum=Internal;Log4j
+type AuditOutputType string
+
+const (
+	// AuditOutputInternal writes the audit events to the daily security-auditlog indices of the
+	// cluster
+	AuditOutputInternal AuditOutputType = "Internal"
+
+	// AuditOutputLog4j writes the audit events to the audit logger of the nodes. It logs to the
+	// console unless the audit log volume is set
+	AuditOutputLog4j AuditOutputType = "Log4j"
+)
+
+// AuditEventType is a category of the audit events of the security plugin
+//
+// +kubebuilder:validation:Enum=FAILED_LOGIN;AUTHENTICATED;MISSING_PRIVILEGES;GRANTED_PRIVILEGES;SSL_EXCEPTION;BAD_HEADERS
+type AuditEventType string
+
+const (
+	AuditEventFailedLogin       AuditEventType = "FAILED_LOGIN"
+	AuditEventAuthenticated     AuditEventType = "AUTHENTICATED"
+	AuditEventMissingPrivileges AuditEventType = "MISSING_PRIVILEGES"
+	AuditEventGrantedPrivileges AuditEventType = "GRANTED_PRIVILEGES"
+	AuditEventSSLException      AuditEventType = "SSL_EXCEPTION"
+	AuditEventBadHeaders        AuditEventType = "BAD_HEADERS"
+)
+
+// AuditLoggingSpec defines the audit logging of the security plugin of the nodes. Changed
+// settings restart the nodes
+type AuditLoggingSpec struct {
+	Enabled bool `json:"enabled"`
+
+	// Categories of the events audited on the REST and transport layers. Defaults to the
+	// categories of the security plugin, all but AUTHENTICATED and GRANTED_PRIVILEGES
+	//
+	// +optional
+	EventTypes []AuditEventType `json:"eventTypes,omitempty"`
+
+	// Defaults to Internal
+	//
+	// +optional
+	Output AuditOutputType `json:"output,omitempty"`
+
+	// Dedicated volume of the nodes the audit logger writes its rolling file to. Only valid with
+	// the Log4j output
+	//
+	// +nullable
+	// +optional
+	Volume *AuditLogVolumeSpec `json:"volume,omitempty"`
+}
+
+// AuditLogVolumeSpec defines the emptyDir volume of the audit log of the nodes, e.g. read by a
+// sidecar shipping the audit log
+type AuditLogVolumeSpec struct {
+	// The size limit of the volume. The audit log is rolled over at a tenth of it keeping up to
+	// eight rolled files. Defaults to 1Gi
+	//
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
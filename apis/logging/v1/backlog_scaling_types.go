@@ -0,0 +1,43 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BacklogScalingSpec defines the temporary scale up of the hot data nodes while the ingestion of
+// logs falls behind. The collectors or the receivers of their buffer alerts signal the backlog by
+// setting the elasticsearch.openshift.io/ingest-backlog annotation of the cluster to true. The
+// added nodes are drained and removed once the backlog is cleared for the cooldown period
+type BacklogScalingSpec struct {
+	// Number of data nodes added while the backlog is signalled
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxExtraNodes int32 `json:"maxExtraNodes"`
+
+	// How long the backlog must be cleared before the added nodes are removed (e.g. 1h).
+	// Defaults to 15m
+	//
+	// +optional
+	CooldownPeriod *metav1.Duration `json:"cooldownPeriod,omitempty"`
+}
+
+// BacklogScalingStatus represents the data nodes added for an ingestion backlog
+type BacklogScalingStatus struct {
+	// Number of data nodes added to the last hot data node
+	ExtraNodes int32 `json:"extraNodes"`
+
+	// Time the backlog was cleared
+	//
+	// +optional
+	ClearedSince *metav1.Time `json:"clearedSince,omitempty"`
+
+	// Number of shards left on the added nodes being removed
+	//
+	// +optional
+	RemainingShards int32 `json:"remainingShards,omitempty"`
+
+	// Message about the added nodes waiting on the cluster
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
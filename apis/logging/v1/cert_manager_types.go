@@ -0,0 +1,41 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertManagerSpec defines the certificates of the cluster issued by cert-manager instead of the
+// pre-populated secret of the cluster. The operator requests the transport, HTTP and admin
+// certificates and assembles the secret of the cluster from them. Renewed certificates restart
+// the nodes. The issuer must include its CA in the issued secrets (e.g. a CA issuer)
+type CertManagerSpec struct {
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+
+	// Duration of the certificates (e.g. 2160h). Defaults to the duration of cert-manager
+	//
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Time before the expiry the certificates are renewed (e.g. 360h). Defaults to the renewal
+	// time of cert-manager
+	//
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerReference references the issuer of the certificates
+type CertManagerIssuerReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer. Defaults to Issuer
+	//
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// API group of the issuer. Defaults to cert-manager.io
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientCertificatesSpec defines the client certificates issued by the operator for the
+// applications consuming the cluster. The certificates are renewed before they expire and
+// reissued once the CA changes
+type ClientCertificatesSpec struct {
+	// Name of a Secret in the namespace of the cluster with the certificate (ca.crt) and the
+	// private key (ca.key) of the CA signing the certificates. The nodes must trust the CA
+	CASecretName string `json:"caSecretName"`
+
+	// The validity of the issued certificates (e.g. 720h). Defaults to 2160h
+	//
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+
+	// The certificates to issue
+	//
+	// +optional
+	Certificates []ClientCertificateSpec `json:"certificates,omitempty"`
+}
+
+// ClientCertificateSpec defines a client certificate delivered as a Secret with the keys
+// tls.crt, tls.key and ca.crt. The distinguished name of the certificate is granted its roles
+// through the role mappings of the security plugin
+type ClientCertificateSpec struct {
+	// Name of the Secret to deliver the certificate in
+	//
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
+
+	// Namespace of the Secret. Defaults to the namespace of the cluster
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Common name (CN) of the subject of the certificate
+	//
+	// +kubebuilder:validation:MinLength:=1
+	CommonName string `json:"commonName"`
+
+	// Organizational units (OU) of the subject of the certificate
+	//
+	// +optional
+	OrganizationalUnits []string `json:"organizationalUnits,omitempty"`
+
+	// Organizations (O) of the subject of the certificate
+	//
+	// +optional
+	Organizations []string `json:"organizations,omitempty"`
+}
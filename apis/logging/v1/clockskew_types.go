@@ -0,0 +1,19 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClockSkewSpec defines the detection of clock skew between the Elasticsearch nodes
+type ClockSkewSpec struct {
+	// The maximum tolerated offset of a node clock from the other nodes (e.g. 10s). Defaults to 10s
+	//
+	// +optional
+	MaxSkew *metav1.Duration `json:"maxSkew,omitempty"`
+
+	// Verify the node clock against the Kubernetes API server in an init container before
+	// starting Elasticsearch. Pods on nodes exceeding the maximum skew fail to start
+	//
+	// +optional
+	VerifyOnStartup bool `json:"verifyOnStartup,omitempty"`
+}
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ComponentTemplateSpec defines a component template, a reusable block of settings, mappings
+// and aliases of index templates
+type ComponentTemplateSpec struct {
+	// The unique name of the component template
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Flat index settings (e.g. index.refresh_interval: 30s)
+	//
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Typeless mappings of the indices
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Mappings *runtime.RawExtension `json:"mappings,omitempty"`
+
+	// Aliases of the indices
+	//
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ComposableIndexTemplateSpec defines an index template composed of component templates
+type ComposableIndexTemplateSpec struct {
+	// The unique name of the index template
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Patterns of the names of the indices the template applies to
+	//
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Names of the component templates the template is composed of in the order they are
+	// merged. Later components override the settings and mappings of earlier ones. The
+	// components are either declared by componentTemplates or exist in the cluster
+	//
+	// +optional
+	ComposedOf []string `json:"composedOf,omitempty"`
+
+	// Priority of the template among the index templates matching an index. Only the template
+	// with the highest priority applies. Overlapping templates must not have the same priority
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Flat index settings overriding the settings of the components
+	//
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Typeless mappings overriding the mappings of the components
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Mappings *runtime.RawExtension `json:"mappings,omitempty"`
+}
+
+// ComposableTemplateKind is the kind of a composable template
+type ComposableTemplateKind string
+
+const (
+	ComposableTemplateKindComponent ComposableTemplateKind = "ComponentTemplate"
+	ComposableTemplateKindIndex     ComposableTemplateKind = "IndexTemplate"
+)
+
+// ComposableTemplateState is the state of a composable template in the cluster
+type ComposableTemplateState string
+
+const (
+	// ComposableTemplateStateApplied when the template is created or updated in the cluster
+	ComposableTemplateStateApplied ComposableTemplateState = "Applied"
+
+	// ComposableTemplateStateUnresolved when the index template is composed of component
+	// templates which are neither declared nor exist in the cluster
+	ComposableTemplateStateUnresolved ComposableTemplateState = "Unresolved"
+
+	// ComposableTemplateStateConflict when the index template overlaps another index template
+	// with the same priority
+	ComposableTemplateStateConflict ComposableTemplateState = "Conflict"
+
+	// ComposableTemplateStateUnsupported when the cluster runs a version prior to 7.8
+	ComposableTemplateStateUnsupported ComposableTemplateState = "Unsupported"
+
+	// ComposableTemplateStateFailed when the cluster rejected the template
+	ComposableTemplateStateFailed ComposableTemplateState = "Failed"
+)
+
+// ComposableTemplateStatus represents the state of a declared composable template
+type ComposableTemplateStatus struct {
+	Name string `json:"name"`
+
+	Kind ComposableTemplateKind `json:"kind"`
+
+	State ComposableTemplateState `json:"state"`
+
+	// Details of the state, e.g. the conflicting templates or the rejection of the cluster
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
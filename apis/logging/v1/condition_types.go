@@ -0,0 +1,30 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceConditionType is a valid value for ResourceCondition.Type
+type ResourceConditionType string
+
+const (
+	// ResourceReady when the resource is in sync with the Elasticsearch cluster
+	ResourceReady ResourceConditionType = "Ready"
+	// ImmutableChangeRejected when the spec requests a change Elasticsearch does not allow
+	ImmutableChangeRejected ResourceConditionType = "ImmutableChangeRejected"
+)
+
+// ResourceCondition describes the state of an object the operator manages inside an Elasticsearch cluster
+type ResourceCondition struct {
+	Type   ResourceConditionType  `json:"type"`
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Unique, one-word, CamelCase reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	Message string `json:"message,omitempty"`
+}
+
+type ResourceConditions []ResourceCondition
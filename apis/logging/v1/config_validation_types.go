@@ -0,0 +1,16 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigValidationSpec defines the validation of a new configuration of the nodes before it is
+// rolled out. A transient pod boots a single Elasticsearch node without data from a candidate
+// configmap holding the new configuration. The configuration is rolled out once the node starts
+// and kept back if it fails to start
+type ConfigValidationSpec struct {
+	// How long the node of the validation pod may take to start (e.g. 15m). Defaults to 10m
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
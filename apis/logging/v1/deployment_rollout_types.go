@@ -0,0 +1,13 @@
+package v1
+
+// DeploymentRolloutStatus is the cursor of the rolling update or restart of the deployments of a
+// node of the spec, the equivalent of the partition of a StatefulSet. The deployments of the node
+// are rolled out one after the other from the highest ordinal down
+type DeploymentRolloutStatus struct {
+	// Name of the deployments of the node without their ordinal
+	Group string `json:"group"`
+
+	// Lowest ordinal of the deployments rolled out so far. An interrupted rollout resumes with
+	// the deployments below it
+	Partition int32 `json:"partition"`
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DisasterRecoveryExportSpec defines the periodic export of a manifest able to rebuild an
+// equivalent cluster elsewhere
+type DisasterRecoveryExportSpec struct {
+	// How often to export the manifest (e.g. 12h). Defaults to 24h
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Name of the secret holding the manifest. Defaults to <cluster>-disaster-recovery. The secret
+	// is not owned by the cluster so it outlives the deletion of the cluster
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// DisasterRecoveryExportStatus represents the last export of the disaster recovery manifest
+type DisasterRecoveryExportStatus struct {
+	// Name of the secret holding the manifest
+	SecretName string `json:"secretName"`
+
+	// LastAttempt is the last time the manifest was exported
+	LastAttempt metav1.Time `json:"lastAttempt"`
+
+	// LastExported is the last time the manifest was exported successfully
+	//
+	// +optional
+	LastExported *metav1.Time `json:"lastExported,omitempty"`
+
+	// Value of the export request annotation honored by the last export
+	//
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// Message about the last failed export
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
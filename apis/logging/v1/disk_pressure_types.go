@@ -0,0 +1,73 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DiskPressureOrder defines which write aliases are blocked first under disk pressure
+//
+// +kubebuilder:validation:Enum=OldestFirst;LargestFirst
+type DiskPressureOrder string
+
+const (
+	// DiskPressureOldestFirst blocks the aliases with the oldest write index first
+	DiskPressureOldestFirst DiskPressureOrder = "OldestFirst"
+	// DiskPressureLargestFirst blocks the aliases with the largest write index first
+	DiskPressureLargestFirst DiskPressureOrder = "LargestFirst"
+)
+
+// DiskPressureSpec defines the write blocks added before a node reaches the flood-stage watermark,
+// which makes every index with a shard on the node read-only. While a node uses more than the
+// block threshold, one write alias after the other is rolled over and its new write index is
+// blocked so the ingestion of the critical aliases goes on. The blocks are removed once every node
+// uses less than the release threshold
+type DiskPressureSpec struct {
+	// Percentage of the disk of a node used to block a write alias. Must be lower than the
+	// flood-stage watermark. Defaults to 92
+	//
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	BlockThreshold *int32 `json:"blockThreshold,omitempty"`
+
+	// Percentage of the disk every node must use less than to remove the blocks. Defaults to 85
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	ReleaseThreshold *int32 `json:"releaseThreshold,omitempty"`
+
+	// Which write aliases are blocked first. Defaults to OldestFirst
+	//
+	// +optional
+	Order DiskPressureOrder `json:"order,omitempty"`
+
+	// Patterns of the critical write aliases never blocked (e.g. audit*)
+	//
+	// +optional
+	ProtectedAliases []string `json:"protectedAliases,omitempty"`
+}
+
+// DiskPressureStatus represents the write aliases blocked under disk pressure
+type DiskPressureStatus struct {
+	// Highest percentage of the disk used by a node at the last check
+	UsedPercent int32 `json:"usedPercent"`
+
+	// +optional
+	BlockedAliases []BlockedAliasStatus `json:"blockedAliases,omitempty"`
+
+	// Message about the last failed or impossible block
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BlockedAliasStatus is a write alias whose write index is blocked under disk pressure
+type BlockedAliasStatus struct {
+	Alias string `json:"alias"`
+
+	// The blocked write index created by rolling over the alias
+	Index string `json:"index"`
+
+	BlockedAt metav1.Time `json:"blockedAt"`
+}
@@ -0,0 +1,55 @@
+package v1
+
+// EffectiveConfig summarizes the configuration the operator rendered for the cluster, to verify it
+// against the spec
+type EffectiveConfig struct {
+	// Lowest Elasticsearch version of the nodes
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Redundancy policy applied to the indices
+	RedundancyPolicy RedundancyPolicyType `json:"redundancyPolicy"`
+
+	// Number of replicas of the indices derived from the redundancy policy
+	IndexReplicas int32 `json:"indexReplicas"`
+
+	// Low disk watermark of the cluster, as a percentage or an absolute free space
+	//
+	// +optional
+	DiskWatermarkLow string `json:"diskWatermarkLow,omitempty"`
+
+	// High disk watermark of the cluster, as a percentage or an absolute free space
+	//
+	// +optional
+	DiskWatermarkHigh string `json:"diskWatermarkHigh,omitempty"`
+
+	// Configuration of the nodes in the order of the spec
+	//
+	// +optional
+	Nodes []EffectiveNodeConfig `json:"nodes,omitempty"`
+}
+
+// EffectiveNodeConfig summarizes the configuration rendered for the nodes with the same spec
+type EffectiveNodeConfig struct {
+	Roles []ElasticsearchNodeRole `json:"roles"`
+
+	// Number of nodes including the ones added by the operator
+	NodeCount int32 `json:"nodeCount"`
+
+	// JVM heap of each node (e.g. 8192Mi)
+	Heap string `json:"heap"`
+
+	// Memory limit of the Elasticsearch container of each node
+	//
+	// +optional
+	MemoryLimit string `json:"memoryLimit,omitempty"`
+
+	// Size of the claim of each node or ephemeral if the nodes use an emptyDir
+	Storage string `json:"storage"`
+
+	// Storage class of the claims of the nodes
+	//
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+}
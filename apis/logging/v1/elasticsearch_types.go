@@ -16,12 +16,16 @@ const (
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:categories=logging;tracing,shortName=es
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.dataNodeCount,statuspath=.status.cluster.numDataNodes
 // +kubebuilder:printcolumn:name="Management State",JSONPath=".spec.managementState",type=string
 // +kubebuilder:printcolumn:name="Health",JSONPath=".status.cluster.status",type=string
 // +kubebuilder:printcolumn:name="Nodes",JSONPath=".status.cluster.numNodes",type=integer
 // +kubebuilder:printcolumn:name="Data Nodes",JSONPath=".status.cluster.numDataNodes",type=integer
 // +kubebuilder:printcolumn:name="Shard Allocation",JSONPath=".status.shardAllocationEnabled",type=string
 // +kubebuilder:printcolumn:name="Index Management",JSONPath=".status.indexManagement.State",type=string
+// +kubebuilder:printcolumn:name="Version",JSONPath=".status.version",type=string
+// +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
 //
 // An Elasticsearch cluster instance
 // +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch",resources={{Pod,v1},{Deployment,v1},{StatefulSet,v1},{ReplicaSet,v1},{ConfigMap,v1},{Service,v1},{Route,v1},{CronJob,v1beta1},{PrometheusRule,v1},{Role,v1},{RoleBinding,v1},{ServiceAccount,v1},{ServiceMonitor,v1},{persistentvolumeclaims,v1}}
@@ -82,11 +86,247 @@ type ElasticsearchSpec struct {
 	// +optional
 	Spec ElasticsearchNodeSpec `json:"nodeSpec"`
 
+	// The total number of data nodes, set by scaling the cluster through the scale subresource.
+	// The node count of the last node with the data role and without the master role, or else of
+	// the last node with the data role, is adjusted for the data nodes to add up to it
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +nullable
+	// +optional
+	DataNodeCount *int32 `json:"dataNodeCount,omitempty"`
+
 	// Management spec for indicies
 	//
 	// +nullable
 	// +optional
 	IndexManagement *IndexManagementSpec `json:"indexManagement"`
+
+	// Write aliases whose indices are rolled over
+	//
+	// +optional
+	RolloverAliases []RolloverAliasSpec `json:"rolloverAliases,omitempty"`
+
+	// Rules for closing and deleting old indices
+	//
+	// +nullable
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+
+	// Periodic health checks of the snapshot repositories registered in the cluster
+	//
+	// +nullable
+	// +optional
+	SnapshotRepositoryHealthCheck *SnapshotRepositoryHealthCheckSpec `json:"snapshotRepositoryHealthCheck,omitempty"`
+
+	// Migration of data from an external Elasticsearch cluster
+	//
+	// +nullable
+	// +optional
+	Migration *ElasticsearchMigrationSpec `json:"migration,omitempty"`
+
+	// Connections to remote clusters for cross-cluster replication
+	//
+	// +optional
+	RemoteClusters []RemoteClusterSpec `json:"remoteClusters,omitempty"`
+
+	// Shutdown of the cluster when the custom resource is deleted
+	//
+	// +nullable
+	// +optional
+	DeletionPolicy *DeletionPolicySpec `json:"deletionPolicy,omitempty"`
+
+	// Detection of clock skew between the nodes
+	//
+	// +nullable
+	// +optional
+	ClockSkew *ClockSkewSpec `json:"clockSkew,omitempty"`
+
+	// Lock the memory of the nodes to keep the heap from being swapped out. The Elasticsearch
+	// containers get the IPC_LOCK capability which the pod security admission level of the
+	// namespace must allow. The memlock ulimit of the container runtime must allow locking the heap
+	// or the nodes fail their bootstrap checks
+	//
+	// +optional
+	MemoryLock bool `json:"memoryLock,omitempty"`
+
+	// Raise the kernel settings of the Kubernetes nodes below the minimums of Elasticsearch, e.g.
+	// vm.max_map_count, with a privileged init container for distributions without pre-tuned nodes.
+	// The pod security admission level of the namespace must allow privileged containers. The
+	// ulimits of the containers are set by the container runtime and cannot be raised by the pods
+	//
+	// +optional
+	HostTuning bool `json:"hostTuning,omitempty"`
+
+	// Security contexts of the pods of the nodes, e.g. to comply with the restricted pod security
+	// admission level. If the pods cannot raise vm.max_map_count and the nodes fail their bootstrap
+	// checks, the MaxMapCountTooLow condition tells how to resolve it
+	//
+	// +nullable
+	// +optional
+	PodSecurity *PodSecuritySpec `json:"podSecurity,omitempty"`
+
+	// Shard allocation awareness of the zones of the Kubernetes nodes running the Elasticsearch nodes.
+	// An init container reads the zone of its Kubernetes node and fails if the node has none
+	//
+	// +nullable
+	// +optional
+	ZoneAwareness *ZoneAwarenessSpec `json:"zoneAwareness,omitempty"`
+
+	// Limit the failed attempts of each phase of a node upgrade. Phases are retried
+	// indefinitely if unset
+	//
+	// +nullable
+	// +optional
+	UpgradeRetryBudget *UpgradeRetryBudgetSpec `json:"upgradeRetryBudget,omitempty"`
+
+	// Periodic export of the cluster spec and of the index templates, aliases, lifecycle policies
+	// and persistent settings of the cluster to a secret. Setting the annotation
+	// elasticsearch.openshift.io/export-disaster-recovery to a new value exports them immediately
+	//
+	// +nullable
+	// +optional
+	DisasterRecoveryExport *DisasterRecoveryExportSpec `json:"disasterRecoveryExport,omitempty"`
+
+	// Periodic backup of the index templates, aliases, lifecycle policies and persistent settings
+	// of the cluster to a config map and optionally to a snapshot without indices, so the
+	// configuration can be restored without a full data snapshot restore
+	//
+	// +nullable
+	// +optional
+	MetadataBackup *MetadataBackupSpec `json:"metadataBackup,omitempty"`
+
+	// Detection of shards dominating the indexing and search operations of their node. The hot shards
+	// are reported in the status with a suggested action and in the eo_es_hot_shard_load_ratio metric
+	//
+	// +nullable
+	// +optional
+	HotShardDetection *HotShardDetectionSpec `json:"hotShardDetection,omitempty"`
+
+	// Temporary scale up of the hot data nodes while the ingestion of logs is backlogged
+	//
+	// +nullable
+	// +optional
+	BacklogScaling *BacklogScalingSpec `json:"backlogScaling,omitempty"`
+
+	// Write blocks of the least critical write aliases before a node reaches the flood-stage
+	// watermark. No blocks are added if unset
+	//
+	// +nullable
+	// +optional
+	DiskPressure *DiskPressureSpec `json:"diskPressure,omitempty"`
+
+	// Deletion of the oldest indices while a node uses more of its disk than a critical threshold.
+	// No indices are deleted if unset
+	//
+	// +nullable
+	// +optional
+	EmergencyRetention *EmergencyRetentionSpec `json:"emergencyRetention,omitempty"`
+
+	// Deletion of the claims of the cluster no node uses anymore. Claims are kept if unset
+	//
+	// +nullable
+	// +optional
+	OrphanedClaimCollection *OrphanedClaimCollectionSpec `json:"orphanedClaimCollection,omitempty"`
+
+	// Policies raising the refresh interval of the write indices during high ingest
+	//
+	// +optional
+	RefreshIntervalPolicies []RefreshIntervalPolicySpec `json:"refreshIntervalPolicies,omitempty"`
+
+	// Volume snapshots of the claims of the data nodes taken before a node is restarted by a rolling
+	// restart or update. Requires a CSI driver supporting snapshots
+	//
+	// +nullable
+	// +optional
+	UpgradeSnapshots *UpgradeSnapshotSpec `json:"upgradeSnapshots,omitempty"`
+
+	// Warm-up queries run against a node restarted by a rolling restart or update before the next
+	// node is restarted
+	//
+	// +nullable
+	// +optional
+	RestartWarmup *RestartWarmupSpec `json:"restartWarmup,omitempty"`
+
+	// Validation of a new configuration of the nodes in a transient pod before it is rolled out.
+	// The pod runs with the resources of the first node. New configurations are rolled out
+	// immediately if unset
+	//
+	// +nullable
+	// +optional
+	ConfigValidation *ConfigValidationSpec `json:"configValidation,omitempty"`
+
+	// Certificates of the cluster issued by cert-manager. The secret of the cluster is expected
+	// to be pre-populated if unset
+	//
+	// +nullable
+	// +optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+
+	// Security of the cluster
+	//
+	// +nullable
+	// +optional
+	Security *SecuritySpec `json:"security,omitempty"`
+
+	// Secrets whose keys are added to the keystores of the nodes as secure settings
+	//
+	// +optional
+	SecureSettings []SecureSettingsSpec `json:"secureSettings,omitempty"`
+
+	// ConfigMaps whose keys are mounted into the config/analysis directory of the nodes for
+	// analyzers with file-based resources
+	//
+	// +optional
+	AnalysisFiles []AnalysisFilesSpec `json:"analysisFiles,omitempty"`
+
+	// Additional settings of elasticsearch.yml in YAML (e.g. thread pools or circuit breakers).
+	// They are merged into the configuration generated by the operator and must not set any of
+	// its settings
+	//
+	// +optional
+	AdditionalConfig string `json:"additionalConfig,omitempty"`
+
+	// Loggers and appenders of the nodes. The settings override the log level annotations
+	//
+	// +optional
+	Logging *ElasticsearchLoggingSpec `json:"logging,omitempty"`
+
+	// Client certificates issued to the applications consuming the cluster
+	//
+	// +optional
+	ClientCertificates *ClientCertificatesSpec `json:"clientCertificates,omitempty"`
+
+	// NetworkPolicies restricting the REST API and transport ports of the nodes
+	//
+	// +nullable
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Services terminating the TLS of legacy clients unable to authenticate with a client
+	// certificate. Their ports are opened to the consumers of the network policy
+	//
+	// +optional
+	LegacyClientServices []LegacyClientServiceSpec `json:"legacyClientServices,omitempty"`
+
+	// Metrics Service and ServiceMonitor of the cluster scraped through the proxy of the nodes
+	//
+	// +nullable
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Alerting rules generated for the cluster with overridable thresholds in the PrometheusRule
+	// of the cluster
+	//
+	// +nullable
+	// +optional
+	Alerts *ClusterAlertsSpec `json:"alerts,omitempty"`
+
+	// Versioned record of every accepted spec of the cluster and of the workloads generated for
+	// it in an S3 compatible bucket, as an audit trail of the evolution of the topology
+	//
+	// +nullable
+	// +optional
+	TopologyHistory *TopologyHistorySpec `json:"topologyHistory,omitempty"`
 }
 
 // ElasticsearchStatus defines the observed state of Elasticsearch
@@ -106,8 +346,90 @@ type ElasticsearchStatus struct {
 	Conditions ClusterConditions `json:"conditions,omitempty"`
 	// +optional
 	IndexManagementStatus *IndexManagementStatus `json:"indexManagement,omitempty"`
+	// +optional
+	RolloverAliases []RolloverAliasStatus `json:"rolloverAliases,omitempty"`
+	// +optional
+	Retention []RetentionRuleStatus `json:"retention,omitempty"`
+	// +optional
+	SnapshotRepositories []SnapshotRepositoryStatus `json:"snapshotRepositories,omitempty"`
+	// +optional
+	Migration *ElasticsearchMigrationStatus `json:"migration,omitempty"`
+	// +optional
+	RemoteClusters []RemoteClusterStatus `json:"remoteClusters,omitempty"`
+	// +optional
+	Shutdown *ShutdownStatus `json:"shutdown,omitempty"`
+	// +optional
+	WorkloadMigrations []WorkloadMigrationStatus `json:"workloadMigrations,omitempty"`
+	// +optional
+	ZoneAwareness *ZoneAwarenessStatus `json:"zoneAwareness,omitempty"`
+	// +optional
+	DisasterRecoveryExport *DisasterRecoveryExportStatus `json:"disasterRecoveryExport,omitempty"`
+	// +optional
+	MetadataBackup *MetadataBackupStatus `json:"metadataBackup,omitempty"`
+	// +optional
+	TopologyHistory *TopologyHistoryStatus `json:"topologyHistory,omitempty"`
+	// +optional
+	HotShardDetection *HotShardDetectionStatus `json:"hotShardDetection,omitempty"`
+	// +optional
+	BacklogScaling *BacklogScalingStatus `json:"backlogScaling,omitempty"`
+	// +optional
+	DiskPressure *DiskPressureStatus `json:"diskPressure,omitempty"`
+	// +optional
+	EmergencyRetention *EmergencyRetentionStatus `json:"emergencyRetention,omitempty"`
+	// +optional
+	Certificates *CertificatesStatus `json:"certificates,omitempty"`
+	// +optional
+	RaisedRefreshIntervals []RaisedRefreshIntervalStatus `json:"raisedRefreshIntervals,omitempty"`
+	// +optional
+	DeploymentRollouts []DeploymentRolloutStatus `json:"deploymentRollouts,omitempty"`
+	// +optional
+	UpgradeSnapshots []UpgradeSnapshotStatus `json:"upgradeSnapshots,omitempty"`
+	// +optional
+	SecureSettings *SecureSettingsStatus `json:"secureSettings,omitempty"`
+	// +optional
+	AnalysisFiles *AnalysisFilesStatus `json:"analysisFiles,omitempty"`
+	// +optional
+	Operator *OperatorVersionStatus `json:"operator,omitempty"`
+	// +optional
+	ComposableTemplates []ComposableTemplateStatus `json:"composableTemplates,omitempty"`
+	// The NetworkPolicies the operator created for the cluster
+	// +optional
+	NetworkPolicies []string `json:"networkPolicies,omitempty"`
+	// The lowest Elasticsearch version of the nodes of the cluster
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Phase ElasticsearchPhase `json:"phase,omitempty"`
+	// The pod of the elected master node. Restarts of the nodes restart the node group running it
+	// last. Within a node group run by a StatefulSet the pods restart in the order of their
+	// ordinals, so the elected master pod may restart before the other pods of its group
+	// +optional
+	ElectedMaster string `json:"electedMaster,omitempty"`
+	// Summary of the configuration rendered for the cluster
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+	// The generation of the spec the Ready, Progressing and Degraded conditions were last
+	// summarized for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// ElasticsearchPhase summarizes the state of the cluster
+type ElasticsearchPhase string
+
+const (
+	// ElasticsearchPhasePending when no node of the cluster is ready
+	ElasticsearchPhasePending ElasticsearchPhase = "Pending"
+	// ElasticsearchPhaseRunning when the cluster serves requests
+	ElasticsearchPhaseRunning ElasticsearchPhase = "Running"
+	// ElasticsearchPhaseUpgrading when nodes of the cluster are restarted or upgraded
+	ElasticsearchPhaseUpgrading ElasticsearchPhase = "Upgrading"
+	// ElasticsearchPhaseDegraded when the cluster is red or degraded
+	ElasticsearchPhaseDegraded ElasticsearchPhase = "Degraded"
+	// ElasticsearchPhaseInvalid when the spec of the cluster is invalid
+	ElasticsearchPhaseInvalid ElasticsearchPhase = "Invalid"
+)
+
 type ClusterHealth struct {
 	// The current Status of the Elasticsearch Cluster
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
@@ -142,35 +464,178 @@ type ClusterHealth struct {
 type ElasticsearchNode struct {
 	// The specific Elasticsearch cluster roles the node should perform
 	//
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
 	// +optional
 	Roles []ElasticsearchNodeRole `json:"roles"`
 
 	// Number of nodes to deploy
 	//
+	// +kubebuilder:validation:Minimum=0
 	// +optional
 	NodeCount int32 `json:"nodeCount"`
 
-	// The resource requirements for the Elasticsearch node
+	// The data tier of the nodes. Nodes without a tier are hot nodes once a node
+	// of the cluster has a tier. New indices are allocated to the hot nodes
+	//
+	// +optional
+	Tier ElasticsearchDataTier `json:"tier,omitempty"`
+
+	// The workload running the nodes. Data nodes run as one Deployment per node
+	// by default. Changing the workload of data nodes from Deployment to StatefulSet
+	// migrates the shards to the new nodes before the deployments are removed
+	//
+	// +optional
+	Workload ElasticsearchNodeWorkload `json:"workload,omitempty"`
+
+	// The maximum number of pods of a StatefulSet. The pods of nodes running as a StatefulSet
+	// are split into several StatefulSets of at most this many pods, which create and restart
+	// their pods independently. The first StatefulSets are filled first, so scaling the nodes
+	// only changes the last one. The pods are not split if unset. The maximum cannot change once the
+	// pods are split, nor drop below the pods of an existing StatefulSet
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxStatefulSetReplicas int32 `json:"maxStatefulSetReplicas,omitempty"`
+
+	// The resource requirements for the Elasticsearch node. The requests and limits
+	// set here override the ones of the node spec
 	//
 	// +nullable
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources"`
 
-	// Define which Nodes the Pods are scheduled on.
+	// Define which Nodes the Pods are scheduled on. The node selector overrides the keys of
+	// the one of the node spec and the tolerations are added to the ones of the node spec,
+	// e.g. to pin data nodes to a storage optimized pool and masters to infra nodes
 	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
 	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
 
-	// The type of backing storage that should be used for the node
+	// The Kubernetes node running the pods of the node, bypassing the scheduler. The node
+	// selector and the tolerations still have to match the Kubernetes node
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// The affinity of the pods of the node replacing the generated one, which prefers running
+	// the pods of nodes with the same roles on different hosts. The affinity must keep these
+	// pods apart with a pod anti-affinity on kubernetes.io/hostname unless allowColocation is set
+	//
+	// +nullable
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Allow an affinity not keeping the pods of nodes with the same roles on different hosts
+	//
+	// +optional
+	AllowColocation bool `json:"allowColocation,omitempty"`
+
+	// How the pods of the node are spread across the topology of the Kubernetes nodes.
+	// Defaults to spreading the pods of nodes with the same roles across zones and hosts
+	// where possible
+	//
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// The scheduler dispatching the pods of the node, e.g. a custom scheduler
+	// like kube-batch. Pods use the default scheduler if not set
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// The RuntimeClass running the pods of the node, e.g. gVisor or Kata
+	// Containers. Pods use the default container runtime if not set
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// The PriorityClass of the pods of the node, e.g. system-cluster-critical to
+	// keep them from being evicted first under node pressure
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// The labels and annotations of the pods of the node. They are merged onto the ones of
+	// the node spec
+	//
+	// +optional
+	PodTemplate *ElasticsearchPodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// The placement profile of the pods of the node, overriding the one of the node spec
+	//
+	// +optional
+	PlacementProfile string `json:"placementProfile,omitempty"`
+
+	// Additional containers running in the pods of the node next to Elasticsearch, e.g. log
+	// shippers or metrics exporters. They cannot be named elasticsearch or proxy
+	//
+	// +nullable
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Additional init containers of the pods of the node, running after the init containers of
+	// the operator. They cannot be named like the init containers of the operator
+	//
+	// +nullable
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Additional environment variables of the Elasticsearch container of the node, e.g. from
+	// Secrets. They override the ones of the node spec with the same name but not the ones
+	// set by the operator
+	//
+	// +nullable
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Limits of the caches of the node, overriding the ones of the node spec
+	//
+	// +nullable
+	// +optional
+	Caches *ElasticsearchCacheSpec `json:"caches,omitempty"`
+
+	// Thread pool sizing of the node, overriding the one of the node spec
+	//
+	// +nullable
+	// +optional
+	ThreadPools *ElasticsearchThreadPoolSpec `json:"threadPools,omitempty"`
+
+	// The scratch space of the temporary files of the node, overriding the one of the node spec
+	//
+	// +nullable
+	// +optional
+	ScratchSpace *ElasticsearchScratchSpaceSpec `json:"scratchSpace,omitempty"`
+
+	// The JVM of the node, e.g. an explicit heap size
+	//
+	// +nullable
+	// +optional
+	JVM *ElasticsearchJVMSpec `json:"jvm,omitempty"`
+
+	// The type of backing storage that should be used for the node. Each node has its own
+	// size and storage class, e.g. a fast storage class for hot nodes and a cheaper one for
+	// warm nodes. Nodes without a size use an emptyDir volume. Increasing the size expands the
+	// claims of the node if its storage class allows volume expansion
 	//
 	// +optional
 	Storage ElasticsearchStorageSpec `json:"storage,omitempty"`
 
-	// GenUUID will be populated by the operator if not provided
+	// GenUUID will be populated by the operator if not provided. It is part of the names of the
+	// workloads of the nodes
 	//
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
 	// +nullable
 	GenUUID *string `json:"genUUID,omitempty"`
 
-	// The resource requirements for the Elasticsearch proxy
+	// The resource requirements for the Elasticsearch proxy. The requests and limits
+	// set here override the ones of the node spec
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
 }
 
@@ -195,23 +660,115 @@ type ElasticsearchNodeSpec struct {
 	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
 	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// The scheduler dispatching the pods of the Elasticsearch nodes, e.g. a custom scheduler
+	// like kube-batch. Pods use the default scheduler if not set
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// The RuntimeClass running the pods of the Elasticsearch nodes, e.g. gVisor or Kata
+	// Containers. Pods use the default container runtime if not set
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+
+	// The PriorityClass of the pods of the Elasticsearch nodes, e.g. system-cluster-critical
+	// to keep them from being evicted first under node pressure
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// The labels and annotations of the pods of the Elasticsearch nodes
+	//
+	// +optional
+	PodTemplate *ElasticsearchPodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// The name of a placement profile of the operator configuration, e.g. dedicated-es-nodes.
+	// The node selector and tolerations of the profile are added to the ones of the pods
+	//
+	// +optional
+	PlacementProfile string `json:"placementProfile,omitempty"`
+
+	// Additional environment variables of the Elasticsearch containers, e.g. from Secrets.
+	// Variables set by the operator cannot be overridden
+	//
+	// +nullable
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
 	// The resource requirements for the Elasticsearch proxy
 	//
 	// +nullable
 	// +optional
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
+
+	// Limits of the caches of the Elasticsearch nodes. The limits are rendered for all nodes
+	// once a node or the node spec sets one, using the Elasticsearch defaults for the others
+	//
+	// +nullable
+	// +optional
+	Caches *ElasticsearchCacheSpec `json:"caches,omitempty"`
+
+	// Thread pool sizing of the Elasticsearch nodes. The processors are rendered for all nodes
+	// once a node has a CPU limit or the node spec or a node sets the thread pools
+	//
+	// +nullable
+	// +optional
+	ThreadPools *ElasticsearchThreadPoolSpec `json:"threadPools,omitempty"`
+
+	// A size-limited emptyDir volume mounted as the temporary directory ES_TMPDIR of the
+	// Elasticsearch nodes. Temporary files are kept in the container otherwise
+	//
+	// +nullable
+	// +optional
+	ScratchSpace *ElasticsearchScratchSpaceSpec `json:"scratchSpace,omitempty"`
 }
 
 type ElasticsearchStorageSpec struct {
 	// The name of the storage class to use with creating the node's PVC.
-	// More info: https://kubernetes.io/docs/concepts/storage/storage-classes/
+	// More info: https://kubernetes.io/docs/concepts/storage/storage-classes/. An empty name
+	// disables dynamic provisioning
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*)?$`
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
 
 	// The max storage capacity for the node to provision.
 	Size *resource.Quantity `json:"size,omitempty"`
+
+	// Whether the claims of the pods of data nodes are deleted when the node count is reduced, the
+	// node is removed or the cluster is deleted. Defaults to Retain
+	//
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	ReclaimPolicy StorageReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// Keeps the data of the pods in an emptyDir volume which is lost when the pod is restarted, e.g. for
+	// CI and development clusters. Takes precedence over the size. Nodes without a size use an empty
+	// emptyDir as well. The restarts of nodes without persistent storage neither wait for the health
+	// of the cluster nor require flushing the shards
+	//
+	// +nullable
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
 }
 
+// StorageReclaimPolicy defines what happens to the claims of the pods no longer needed
+type StorageReclaimPolicy string
+
+const (
+	// StorageReclaimPolicyRetain keeps the claims and their data
+	StorageReclaimPolicyRetain StorageReclaimPolicy = "Retain"
+	// StorageReclaimPolicyDelete deletes the claims
+	StorageReclaimPolicyDelete StorageReclaimPolicy = "Delete"
+)
+
 // ElasticsearchNodeStatus represents the status of individual Elasticsearch node
 type ElasticsearchNodeStatus struct {
 	// +optional
@@ -226,6 +783,12 @@ type ElasticsearchNodeStatus struct {
 	Roles []ElasticsearchNodeRole `json:"roles,omitempty"`
 	// +optional
 	Conditions ClusterConditions `json:"conditions,omitempty"`
+	// DesiredReplicas is the number of pods the deployment or statefulset of the node should run
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// ReadyReplicas is the number of ready pods of the deployment or statefulset of the node
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 }
 
 type ElasticsearchNodeUpgradeStatus struct {
@@ -234,6 +797,17 @@ type ElasticsearchNodeUpgradeStatus struct {
 	ScheduledForCertRedeploy corev1.ConditionStatus    `json:"scheduledCertRedeploy,omitempty"`
 	UnderUpgrade             corev1.ConditionStatus    `json:"underUpgrade,omitempty"`
 	UpgradePhase             ElasticsearchUpgradePhase `json:"upgradePhase,omitempty"`
+	// ScheduledChanges lists the fields of the pod template which differ from the running node
+	// and cause the scheduled upgrade
+	// +optional
+	ScheduledChanges []string `json:"scheduledChanges,omitempty"`
+	// PhaseAttempts counts the failed attempts of the current upgrade phase
+	// +optional
+	PhaseAttempts int32 `json:"phaseAttempts,omitempty"`
+	// PhaseStartTime is the time the node entered the current upgrade phase. External alerting
+	// may fire when a node stays in a phase for too long
+	// +optional
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
 }
 
 type ClusterCondition struct {
@@ -265,13 +839,45 @@ const (
 	ZeroRedundancy RedundancyPolicyType = "ZeroRedundancy"
 )
 
-// +kubebuilder:validation:Enum:=master;client;data
+// +kubebuilder:validation:Enum:=master;client;data;ingest;ml;voting_only
 type ElasticsearchNodeRole string
 
 const (
+	// ElasticsearchRoleClient nodes serve the REST API. Nodes with the client role only are
+	// coordinating nodes which hold no data and run no ingest pipelines
 	ElasticsearchRoleClient ElasticsearchNodeRole = "client"
 	ElasticsearchRoleData   ElasticsearchNodeRole = "data"
 	ElasticsearchRoleMaster ElasticsearchNodeRole = "master"
+	// ElasticsearchRoleIngest nodes run the ingest pipelines. Once a node has the ingest role,
+	// the nodes without it no longer run ingest pipelines
+	ElasticsearchRoleIngest ElasticsearchNodeRole = "ingest"
+	// ElasticsearchRoleML nodes run the machine learning jobs. Once a node has the ml role,
+	// the nodes without it no longer run machine learning jobs
+	ElasticsearchRoleML ElasticsearchNodeRole = "ml"
+	// ElasticsearchRoleVotingOnly nodes take part in the election of the master without becoming
+	// master themselves. They require the master role and hold no data
+	ElasticsearchRoleVotingOnly ElasticsearchNodeRole = "voting_only"
+)
+
+// ElasticsearchDataTier is rendered as the data attribute of the nodes
+//
+// +kubebuilder:validation:Enum:=hot;warm;cold
+type ElasticsearchDataTier string
+
+const (
+	ElasticsearchDataTierHot  ElasticsearchDataTier = "hot"
+	ElasticsearchDataTierWarm ElasticsearchDataTier = "warm"
+	ElasticsearchDataTierCold ElasticsearchDataTier = "cold"
+)
+
+// ElasticsearchNodeWorkload is the kind of workload running the nodes
+//
+// +kubebuilder:validation:Enum:=Deployment;StatefulSet
+type ElasticsearchNodeWorkload string
+
+const (
+	ElasticsearchNodeWorkloadDeployment  ElasticsearchNodeWorkload = "Deployment"
+	ElasticsearchNodeWorkloadStatefulSet ElasticsearchNodeWorkload = "StatefulSet"
 )
 
 type ShardAllocationState string
@@ -328,6 +934,8 @@ const (
 	InvalidData              ClusterConditionType = "InvalidData"
 	InvalidRedundancy        ClusterConditionType = "InvalidRedundancy"
 	InvalidUUID              ClusterConditionType = "InvalidUUID"
+	InvalidMemoryLock        ClusterConditionType = "InvalidMemoryLock"
+	InvalidWorkload          ClusterConditionType = "InvalidWorkload"
 	ESContainerWaiting       ClusterConditionType = "ElasticsearchContainerWaiting"
 	ESContainerTerminated    ClusterConditionType = "ElasticsearchContainerTerminated"
 	ProxyContainerWaiting    ClusterConditionType = "ProxyContainerWaiting"
@@ -336,4 +944,43 @@ const (
 	NodeStorage              ClusterConditionType = "NodeStorage"
 	CustomImage              ClusterConditionType = "CustomImageIgnored"
 	DegradedState            ClusterConditionType = "Degraded"
+	ClockSkewDetected        ClusterConditionType = "ClockSkewDetected"
+	Blocked                  ClusterConditionType = "Blocked"
+	FailedUpgrade            ClusterConditionType = "FailedUpgrade"
+	InvalidVotingOnly        ClusterConditionType = "InvalidVotingOnly"
+	SpecChangeQueued         ClusterConditionType = "SpecChangeQueued"
+	InvalidAffinity          ClusterConditionType = "InvalidAffinity"
+	ConfigValidationFailed   ClusterConditionType = "ConfigValidationFailed"
+	InvalidPlacementProfile  ClusterConditionType = "InvalidPlacementProfile"
+	InvalidSidecars          ClusterConditionType = "InvalidSidecars"
+	InvalidInitContainers    ClusterConditionType = "InvalidInitContainers"
+	InvalidHostTuning        ClusterConditionType = "InvalidHostTuning"
+	InvalidJVMHeap           ClusterConditionType = "InvalidJVMHeap"
+	InvalidJVMOptions        ClusterConditionType = "InvalidJVMOptions"
+	FullRestartScheduled     ClusterConditionType = "FullRestartScheduled"
+	InvalidAdditionalConfig  ClusterConditionType = "InvalidAdditionalConfig"
+	InvalidLogging           ClusterConditionType = "InvalidLogging"
+	InvalidCertificates      ClusterConditionType = "InvalidCertificates"
+	InvalidTLS               ClusterConditionType = "InvalidTLS"
+	InvalidCredentials       ClusterConditionType = "InvalidCredentials"
+	InvalidRealms            ClusterConditionType = "InvalidRealms"
+	InvalidPodSecurity       ClusterConditionType = "InvalidPodSecurity"
+	InvalidLegacyClients     ClusterConditionType = "InvalidLegacyClientServices"
+	InvalidAuditLogging      ClusterConditionType = "InvalidAuditLogging"
+	InvalidThreadPools       ClusterConditionType = "InvalidThreadPools"
+	MaxMapCountTooLow        ClusterConditionType = "MaxMapCountTooLow"
+	DisruptionDeferred       ClusterConditionType = "DisruptionDeferred"
+	ReadyState               ClusterConditionType = "Ready"
+	ProgressingState         ClusterConditionType = "Progressing"
+	PrometheusRulesMissing   ClusterConditionType = "PrometheusRulesMissing"
+	ReconcileSuspended       ClusterConditionType = "ReconcileSuspended"
+)
+
+// Reasons of the Blocked condition naming the kind of external dependency the cluster waits on
+const (
+	BlockedReasonMissingSecret                 = "MissingSecret"
+	BlockedReasonMissingConfigMap              = "MissingConfigMap"
+	BlockedReasonMissingStorageClass           = "MissingStorageClass"
+	BlockedReasonImagePullFailure              = "ImagePullFailure"
+	BlockedReasonSnapshotRepositoryUnreachable = "SnapshotRepositoryUnreachable"
 )
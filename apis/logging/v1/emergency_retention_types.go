@@ -0,0 +1,65 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EmergencyRetentionSpec defines the deletion of the oldest indices while a node uses more of its
+// disk than the critical threshold. Only indices with shards on such a node are deleted, one at a
+// time and at most once per deletion interval, so the freed disk is measured before the next
+// deletion. Every deletion is recorded as an event of the cluster
+type EmergencyRetentionSpec struct {
+	// Percentage of the disk of a node used to delete the oldest index. Must be lower than the
+	// flood-stage watermark and higher than the block threshold of the disk pressure. Defaults to 94
+	//
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	CriticalThreshold *int32 `json:"criticalThreshold,omitempty"`
+
+	// Patterns of the indices which may be deleted (e.g. app-*). Indices starting with a dot are
+	// only matched by patterns starting with a dot. Indices written to through an alias are
+	// never deleted
+	//
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Minimum time between two deletions, so the disk usage reported by the nodes reflects the
+	// space freed by a deletion before the next one (e.g. 5m). Defaults to 5m
+	//
+	// +optional
+	MinDeletionInterval *metav1.Duration `json:"minDeletionInterval,omitempty"`
+
+	// Only record the index which would be deleted
+	//
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// EmergencyRetentionStatus represents the indices deleted under critical disk pressure
+type EmergencyRetentionStatus struct {
+	// Highest percentage of the disk used by a node at the last check
+	UsedPercent int32 `json:"usedPercent"`
+
+	// The last indices deleted or selected in the dry-run mode, the latest last
+	//
+	// +optional
+	DeletedIndices []EmergencyDeletionStatus `json:"deletedIndices,omitempty"`
+
+	// Message about the last failed or impossible deletion
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// EmergencyDeletionStatus is an index deleted under critical disk pressure
+type EmergencyDeletionStatus struct {
+	Index string `json:"index"`
+
+	DeletedAt metav1.Time `json:"deletedAt"`
+
+	// The index was only selected in the dry-run mode
+	//
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
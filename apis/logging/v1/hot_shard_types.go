@@ -0,0 +1,61 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HotShardDetectionSpec defines the detection of shards dominating the indexing and search
+// operations of their node. The operations of the shards are sampled at every interval and the
+// shards serving more than the threshold of the operations of their node since the previous
+// sample are reported
+type HotShardDetectionSpec struct {
+	// Percentage of the operations of a node a shard must serve to be reported. Defaults to 50
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Threshold *int32 `json:"threshold,omitempty"`
+
+	// Time between the samples of the shard operations (e.g. 10m). Defaults to 5m
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// HotShardAction is the suggested action to spread the load of a hot shard
+type HotShardAction string
+
+const (
+	// HotShardActionRolloverSooner suggests rolling over the write index more often
+	HotShardActionRolloverSooner HotShardAction = "RolloverSooner"
+	// HotShardActionIncreasePrimaryShards suggests spreading the writes over more primary shards
+	HotShardActionIncreasePrimaryShards HotShardAction = "IncreasePrimaryShards"
+	// HotShardActionReroute suggests moving the shard to a less loaded node
+	HotShardActionReroute HotShardAction = "Reroute"
+)
+
+// HotShardDetectionStatus represents the hot shards found by the last sample
+type HotShardDetectionStatus struct {
+	// Time of the last sample of the shard operations
+	//
+	// +optional
+	LastSample *metav1.Time `json:"lastSample,omitempty"`
+
+	// +optional
+	Shards []HotShard `json:"shards,omitempty"`
+}
+
+// HotShard is a shard serving most of the operations of its node
+type HotShard struct {
+	Index string `json:"index"`
+	Shard int32  `json:"shard"`
+	Node  string `json:"node"`
+
+	// +optional
+	Primary bool `json:"primary,omitempty"`
+
+	// Percentage of the operations of the node served by the shard
+	LoadPercent int32 `json:"loadPercent"`
+
+	Action HotShardAction `json:"action"`
+}
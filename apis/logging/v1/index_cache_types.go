@@ -0,0 +1,23 @@
+package v1
+
+// ElasticsearchCacheSpec limits the heap used by the caches of the nodes. Sizes are either a
+// percentage of the heap (e.g. 20%) or an absolute size (e.g. 2gb)
+type ElasticsearchCacheSpec struct {
+	// Size of the fielddata cache. The fielddata cache is unbounded by default
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?(%|b|kb|mb|gb|tb)$`
+	// +optional
+	FielddataSize string `json:"fielddataSize,omitempty"`
+
+	// Size of the node query cache. Defaults to 10%
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?(%|b|kb|mb|gb|tb)$`
+	// +optional
+	QuerySize string `json:"querySize,omitempty"`
+
+	// Size of the shard request cache. Defaults to 1%
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?(%|b|kb|mb|gb|tb)$`
+	// +optional
+	RequestSize string `json:"requestSize,omitempty"`
+}
@@ -0,0 +1,244 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchindexlifecyclepolicies,categories=logging,shortName=esilm
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="State",JSONPath=".status.state",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// An index lifecycle policy applied to an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Index Lifecycle Policy"
+type ElasticsearchIndexLifecyclePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchIndexLifecyclePolicySpec   `json:"spec,omitempty"`
+	Status ElasticsearchIndexLifecyclePolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchIndexLifecyclePolicyList contains a list of ElasticsearchIndexLifecyclePolicy
+type ElasticsearchIndexLifecyclePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchIndexLifecyclePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchIndexLifecyclePolicy{}, &ElasticsearchIndexLifecyclePolicyList{})
+}
+
+// ElasticsearchIndexLifecyclePolicySpec defines the desired state of an index lifecycle policy
+type ElasticsearchIndexLifecyclePolicySpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to apply the policy to
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The name of the policy in Elasticsearch. Defaults to the name of this resource
+	//
+	// +optional
+	PolicyName string `json:"policyName,omitempty"`
+
+	// The phases an index transitions through during its lifetime
+	Phases IndexLifecyclePhasesSpec `json:"phases"`
+
+	// Names of index management mappings whose operator managed index templates
+	// should reference this policy
+	//
+	// +optional
+	Mappings []string `json:"mappings,omitempty"`
+}
+
+// IndexLifecyclePhasesSpec defines the hot, warm, cold and delete phases of a policy
+type IndexLifecyclePhasesSpec struct {
+	// +nullable
+	// +optional
+	Hot *IndexLifecyclePhaseSpec `json:"hot,omitempty"`
+	// +nullable
+	// +optional
+	Warm *IndexLifecyclePhaseSpec `json:"warm,omitempty"`
+	// +nullable
+	// +optional
+	Cold *IndexLifecyclePhaseSpec `json:"cold,omitempty"`
+	// +nullable
+	// +optional
+	Delete *IndexLifecycleDeletePhaseSpec `json:"delete,omitempty"`
+}
+
+// IndexLifecyclePhaseSpec defines the entry age and actions of a phase
+type IndexLifecyclePhaseSpec struct {
+	// The minimum age of an index before it enters the phase (e.g. 7d)
+	//
+	// +optional
+	MinAge TimeUnit `json:"minAge,omitempty"`
+
+	// +optional
+	Actions IndexLifecycleActionsSpec `json:"actions,omitempty"`
+}
+
+// IndexLifecycleDeletePhaseSpec defines when an index is deleted
+type IndexLifecycleDeletePhaseSpec struct {
+	// The minimum age of an index before it should be deleted (e.g. 30d)
+	MinAge TimeUnit `json:"minAge"`
+}
+
+// IndexLifecycleActionsSpec defines the actions executed within a phase
+type IndexLifecycleActionsSpec struct {
+	// +nullable
+	// +optional
+	Rollover *IndexLifecycleRolloverSpec `json:"rollover,omitempty"`
+
+	// +nullable
+	// +optional
+	ForceMerge *IndexLifecycleForceMergeSpec `json:"forceMerge,omitempty"`
+
+	// +nullable
+	// +optional
+	Shrink *IndexLifecycleShrinkSpec `json:"shrink,omitempty"`
+
+	// +nullable
+	// +optional
+	Allocate *IndexLifecycleAllocateSpec `json:"allocate,omitempty"`
+
+	// Make the index read-only
+	//
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// The recovery priority of the index
+	//
+	// +nullable
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+}
+
+// IndexLifecycleRolloverSpec defines the conditions to roll over the write index
+type IndexLifecycleRolloverSpec struct {
+	// The maximum age of an index before it should be rolled over (e.g. 7d)
+	//
+	// +optional
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+
+	// The maximum total size of the primary shards of an index before it should be rolled over (e.g. 50gb)
+	//
+	// +kubebuilder:validation:Pattern:="^([0-9]+)(b|kb|mb|gb|tb|pb)$"
+	// +optional
+	MaxSize string `json:"maxSize,omitempty"`
+
+	// The maximum number of documents of an index before it should be rolled over
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxDocs int64 `json:"maxDocs,omitempty"`
+}
+
+// IndexLifecycleForceMergeSpec defines the force merge action
+type IndexLifecycleForceMergeSpec struct {
+	// The number of segments to merge to
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxNumSegments int32 `json:"maxNumSegments"`
+}
+
+// IndexLifecycleShrinkSpec defines the shrink action
+type IndexLifecycleShrinkSpec struct {
+	// The number of primary shards of the shrunken index
+	//
+	// +kubebuilder:validation:Minimum=1
+	NumberOfShards int32 `json:"numberOfShards"`
+}
+
+// IndexLifecycleAllocateSpec defines the allocate action
+type IndexLifecycleAllocateSpec struct {
+	// The number of replicas to assign to the index
+	//
+	// +nullable
+	// +optional
+	NumberOfReplicas *int32 `json:"numberOfReplicas,omitempty"`
+
+	// The data tier the index shards must be allocated to
+	//
+	// +optional
+	Tier ElasticsearchDataTier `json:"tier,omitempty"`
+
+	// Node attributes the index shards must be allocated to
+	//
+	// +optional
+	Require map[string]string `json:"require,omitempty"`
+
+	// Node attributes the index shards may be allocated to
+	//
+	// +optional
+	Include map[string]string `json:"include,omitempty"`
+
+	// Node attributes the index shards must not be allocated to
+	//
+	// +optional
+	Exclude map[string]string `json:"exclude,omitempty"`
+}
+
+// ElasticsearchIndexLifecyclePolicyStatus defines the observed state of an index lifecycle policy
+type ElasticsearchIndexLifecyclePolicyStatus struct {
+	// +optional
+	State IndexLifecyclePolicyState `json:"state,omitempty"`
+
+	// +optional
+	Reason IndexLifecyclePolicyReason `json:"reason,omitempty"`
+
+	// Message about the state of the policy, e.g. a validation error returned by Elasticsearch
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The index templates that reference this policy
+	//
+	// +optional
+	Templates []string `json:"templates,omitempty"`
+
+	// The generation of the spec last applied to Elasticsearch
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdated represents the last time that the status was updated.
+	//
+	// +optional
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// IndexLifecyclePolicyState of an ElasticsearchIndexLifecyclePolicy
+type IndexLifecyclePolicyState string
+
+const (
+	// IndexLifecyclePolicyStateApplied when the policy is stored in Elasticsearch
+	IndexLifecyclePolicyStateApplied IndexLifecyclePolicyState = "Applied"
+
+	// IndexLifecyclePolicyStatePending when the Elasticsearch cluster is not available yet
+	IndexLifecyclePolicyStatePending IndexLifecyclePolicyState = "Pending"
+
+	// IndexLifecyclePolicyStateFailed when Elasticsearch rejected the policy
+	IndexLifecyclePolicyStateFailed IndexLifecyclePolicyState = "Failed"
+)
+
+type IndexLifecyclePolicyReason string
+
+const (
+	IndexLifecyclePolicyReasonApplied            IndexLifecyclePolicyReason = "PolicyApplied"
+	IndexLifecyclePolicyReasonClusterNotFound    IndexLifecyclePolicyReason = "ClusterNotFound"
+	IndexLifecyclePolicyReasonClusterUnavailable IndexLifecyclePolicyReason = "ClusterUnavailable"
+	IndexLifecyclePolicyReasonRejected           IndexLifecyclePolicyReason = "RejectedByElasticsearch"
+	IndexLifecyclePolicyReasonTemplateFailed     IndexLifecyclePolicyReason = "TemplateUpdateFailed"
+)
+
+// GetPolicyName returns the name of the policy in Elasticsearch
+func (policy *ElasticsearchIndexLifecyclePolicy) GetPolicyName() string {
+	if policy.Spec.PolicyName != "" {
+		return policy.Spec.PolicyName
+	}
+	return policy.Name
+}
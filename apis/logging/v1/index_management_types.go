@@ -17,6 +17,16 @@ type IndexManagementSpec struct {
 	//
 	// +optional
 	Mappings []IndexManagementPolicyMappingSpec `json:"mappings"`
+
+	// Component templates the index templates are composed of. Requires Elasticsearch 7.8 or later
+	//
+	// +optional
+	ComponentTemplates []ComponentTemplateSpec `json:"componentTemplates,omitempty"`
+
+	// Composable index templates. Requires Elasticsearch 7.8 or later
+	//
+	// +optional
+	IndexTemplates []ComposableIndexTemplateSpec `json:"indexTemplates,omitempty"`
 }
 
 // TimeUnit is a time unit like h,m,d
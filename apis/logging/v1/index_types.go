@@ -0,0 +1,116 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchindices,categories=logging,shortName=esindex
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="Index",JSONPath=".spec.indexName",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// An index declared within an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Index"
+type ElasticsearchIndex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchIndexSpec   `json:"spec,omitempty"`
+	Status ElasticsearchIndexStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchIndexList contains a list of ElasticsearchIndex
+type ElasticsearchIndexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchIndex `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchIndex{}, &ElasticsearchIndexList{})
+}
+
+// ElasticsearchIndexSpec defines the desired state of an index
+type ElasticsearchIndexSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to create the index in
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The name of the index in Elasticsearch. Defaults to the name of this resource
+	//
+	// +optional
+	IndexName string `json:"indexName,omitempty"`
+
+	// The number of primary shards. Can not be changed once the index is created.
+	// Defaults to the primary shard count of the cluster
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NumberOfShards int32 `json:"numberOfShards,omitempty"`
+
+	// The number of replica shards. Defaults to the replica count of the cluster redundancy policy
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +nullable
+	// +optional
+	NumberOfReplicas *int32 `json:"numberOfReplicas,omitempty"`
+
+	// Additional index settings (e.g. index.refresh_interval: 30s). Dynamic settings
+	// are updated on the existing index
+	//
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// The typeless mappings of the index. New fields are added to the existing index,
+	// changes to existing fields are rejected
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Mappings *runtime.RawExtension `json:"mappings,omitempty"`
+
+	// Aliases of the index
+	//
+	// +optional
+	Aliases []ElasticsearchIndexAliasSpec `json:"aliases,omitempty"`
+}
+
+// ElasticsearchIndexAliasSpec defines an alias pointing to the index
+type ElasticsearchIndexAliasSpec struct {
+	// The name of the alias
+	Name string `json:"name"`
+
+	// Whether the index is the write index of the alias. Elasticsearch decides when unset
+	//
+	// +optional
+	IsWriteIndex *bool `json:"isWriteIndex,omitempty"`
+}
+
+// ElasticsearchIndexStatus defines the observed state of an index
+type ElasticsearchIndexStatus struct {
+	// +optional
+	Conditions ResourceConditions `json:"conditions,omitempty"`
+
+	// The aliases of the spec last applied to the index. Aliases removed from the spec are
+	// removed from the index, other aliases of the index are kept
+	//
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+
+	// The generation of the spec last applied to Elasticsearch
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// GetIndexName returns the name of the index in Elasticsearch
+func (index *ElasticsearchIndex) GetIndexName() string {
+	if index.Spec.IndexName != "" {
+		return index.Spec.IndexName
+	}
+	return index.Name
+}
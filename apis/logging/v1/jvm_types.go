@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ElasticsearchJVMSpec defines the JVM of the Elasticsearch nodes
+type ElasticsearchJVMSpec struct {
+	// The size of the heap, overriding the half of the memory request of the Elasticsearch
+	// container capped at 31Gi. The heap must be smaller than the memory limit of the container
+	//
+	// +optional
+	Heap *resource.Quantity `json:"heap,omitempty"`
+
+	// Additional JVM options of the node, e.g. GC tuning or flight recordings, rendered one per
+	// line into the jvm.options.d directory. The heap is sized with heap instead of -Xms and -Xmx
+	//
+	// +nullable
+	// +optional
+	Options []string `json:"options,omitempty"`
+}
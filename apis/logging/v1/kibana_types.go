@@ -35,6 +35,29 @@ type KibanaSpec struct {
 	//
 	// +optional
 	ProxySpec `json:"proxy,omitempty"`
+
+	// Coordination of the migration of the saved objects in the .kibana index with the upgrades
+	// of Kibana
+	//
+	// +nullable
+	// +optional
+	IndexMigration *KibanaIndexMigrationSpec `json:"indexMigration,omitempty"`
+}
+
+// KibanaIndexMigrationSpec defines how the operator guards the migration of the saved objects.
+// Kibana is only upgraded once all nodes of the Elasticsearch cluster run the same version
+type KibanaIndexMigrationSpec struct {
+	// Snapshot repository the .kibana indices are snapshotted into before Kibana is upgraded.
+	// The upgrade waits for the snapshot to complete. No snapshot is taken if not set
+	//
+	// +optional
+	SnapshotRepository string `json:"snapshotRepository,omitempty"`
+
+	// How long a migration may run before its incomplete target index is deleted for Kibana to
+	// retry the migration (e.g. 30m). Defaults to 15m
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 type ProxySpec struct {
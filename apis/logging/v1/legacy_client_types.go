@@ -0,0 +1,32 @@
+package v1
+
+// LegacyClientServiceSpec defines a Service for clients unable to authenticate with a client
+// certificate. A sidecar of the proxy on the client nodes terminates the TLS of the clients with the
+// certificate of the Service without requesting a client certificate, authenticates their bearer
+// tokens and forwards their requests with the headers of the authenticated user and roles
+type LegacyClientServiceSpec struct {
+	// Name of the Service relative to the cluster. The Service is named <cluster>-<name>
+	//
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+
+	// Port of the Service and of the sidecar. It must differ from the ports of the nodes
+	//
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Name of a Secret of type kubernetes.io/tls in the namespace of the cluster with the serving
+	// certificate of the Service. Defaults to the Secret <cluster>-<name>-tls issued and renewed by
+	// the OpenShift service CA
+	//
+	// +optional
+	CertSecretName string `json:"certSecretName,omitempty"`
+
+	// Role of the authenticated clients without a backend role. Defaults to the default role of
+	// the proxy
+	//
+	// +optional
+	DefaultRole string `json:"defaultRole,omitempty"`
+}
@@ -0,0 +1,71 @@
+package v1
+
+// ElasticsearchLogLevel is the level of a logger of Elasticsearch
+//
+// +kubebuilder:validation:Enum:=trace;debug;info;warn;error;fatal;off
+type ElasticsearchLogLevel string
+
+// ElasticsearchLoggingSpec defines the loggers and the appenders of the nodes rendered into
+// log4j2.properties. Changes of the levels of the loggers are applied without restarting the nodes
+type ElasticsearchLoggingSpec struct {
+	// Level of the root logger. Defaults to the elasticsearch.openshift.io/esloglevel annotation or info
+	//
+	// +optional
+	Level ElasticsearchLogLevel `json:"level,omitempty"`
+
+	// Appender of the root logger. Defaults to the elasticsearch.openshift.io/develLogAppender annotation or console
+	//
+	// +kubebuilder:validation:Enum:=console;rolling
+	// +optional
+	Appender string `json:"appender,omitempty"`
+
+	// Level of the deprecation logger. Defaults to warn
+	//
+	// +optional
+	DeprecationLevel ElasticsearchLogLevel `json:"deprecationLevel,omitempty"`
+
+	// Level of the search slowlog logger. Defaults to trace
+	//
+	// +optional
+	SearchSlowlogLevel ElasticsearchLogLevel `json:"searchSlowlogLevel,omitempty"`
+
+	// Level of the indexing slowlog logger. Defaults to trace
+	//
+	// +optional
+	IndexingSlowlogLevel ElasticsearchLogLevel `json:"indexingSlowlogLevel,omitempty"`
+
+	// Levels of additional loggers by their names (e.g. org.elasticsearch.transport)
+	//
+	// +optional
+	Loggers []ElasticsearchLoggerSpec `json:"loggers,omitempty"`
+
+	// Options of the rolling file appender
+	//
+	// +optional
+	Rolling *ElasticsearchRollingAppenderSpec `json:"rolling,omitempty"`
+}
+
+// ElasticsearchLoggerSpec defines the level of a logger
+type ElasticsearchLoggerSpec struct {
+	// Name of the logger, usually a package or class name
+	//
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	Level ElasticsearchLogLevel `json:"level"`
+}
+
+// ElasticsearchRollingAppenderSpec defines when the log file of the rolling appender is rolled over
+type ElasticsearchRollingAppenderSpec struct {
+	// The size at which the log file is rolled over (e.g. 100MB). Defaults to 100MB
+	//
+	// +kubebuilder:validation:Pattern:=`^[0-9]+(KB|MB|GB)$`
+	// +optional
+	MaxFileSize string `json:"maxFileSize,omitempty"`
+
+	// The number of rolled over log files to keep. Defaults to 5
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxFiles int32 `json:"maxFiles,omitempty"`
+}
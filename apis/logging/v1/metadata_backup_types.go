@@ -0,0 +1,58 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MetadataBackupSpec defines the periodic backup of the index templates, aliases, lifecycle
+// policies and persistent settings of the cluster, separate from the snapshots of the data. The
+// backup is only written when the metadata changed since the last backup
+type MetadataBackupSpec struct {
+	// How often to check the metadata for changes (e.g. 15m). Defaults to 1h
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Name of the config map holding the metadata. Defaults to <cluster>-metadata-backup. The config
+	// map is not owned by the cluster so it outlives the deletion of the cluster
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Name of a registered snapshot repository (e.g. backed by S3) to also store a snapshot of the
+	// global state of the cluster without any index whenever the metadata changed. Such a snapshot
+	// restores the configuration without restoring data
+	//
+	// +optional
+	SnapshotRepository string `json:"snapshotRepository,omitempty"`
+}
+
+// MetadataBackupStatus represents the last backup of the metadata of the cluster
+type MetadataBackupStatus struct {
+	// Name of the config map holding the metadata
+	ConfigMapName string `json:"configMapName"`
+
+	// SHA-256 checksum of the metadata of the last backup
+	//
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+
+	// LastChecked is the last time the metadata was compared with the last backup
+	LastChecked metav1.Time `json:"lastChecked"`
+
+	// LastBackup is the last time changed metadata was backed up
+	//
+	// +optional
+	LastBackup *metav1.Time `json:"lastBackup,omitempty"`
+
+	// Snapshot of the global state taken by the last backup
+	//
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Message about the last failed backup
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
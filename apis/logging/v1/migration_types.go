@@ -0,0 +1,97 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoteReindexState is the state of the migration of a single index
+type RemoteReindexState string
+
+const (
+	RemoteReindexStatePending   RemoteReindexState = "Pending"
+	RemoteReindexStateRunning   RemoteReindexState = "Running"
+	RemoteReindexStateCompleted RemoteReindexState = "Completed"
+	RemoteReindexStateFailed    RemoteReindexState = "Failed"
+)
+
+// ElasticsearchMigrationSpec defines the migration of data from an external cluster
+type ElasticsearchMigrationSpec struct {
+	// Reindex the data of an external Elasticsearch cluster into this cluster
+	//
+	// +nullable
+	// +optional
+	RemoteReindex *RemoteReindexSpec `json:"remoteReindex,omitempty"`
+}
+
+// RemoteReindexSpec defines the indices to copy from an external cluster using reindex from remote.
+// The host is added to reindex.remote.whitelist which restarts the nodes before the migration starts
+type RemoteReindexSpec struct {
+	// URL of the external cluster (e.g. https://elasticsearch.example.com:9200).
+	// The certificate of an https endpoint must be trusted by the JVM of the nodes
+	//
+	// +kubebuilder:validation:Pattern=`^https?://[^/:]+:[0-9]+$`
+	Host string `json:"host"`
+
+	// Name of a secret in the same namespace with the username and password keys
+	// used to authenticate against the external cluster
+	//
+	// +optional
+	Secret string `json:"secret,omitempty"`
+
+	// Names of the indices to copy. Each index is reindexed into an index of the same name,
+	// one at a time in the given order. Documents already present in the destination are kept
+	//
+	// +kubebuilder:validation:MinItems=1
+	Indices []string `json:"indices"`
+
+	// Number of documents fetched from the external cluster per batch. Defaults to 1000
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+}
+
+// ElasticsearchMigrationStatus represents the progress of the migration from an external cluster
+type ElasticsearchMigrationStatus struct {
+	// +optional
+	RemoteReindex []RemoteReindexStatus `json:"remoteReindex,omitempty"`
+}
+
+// RemoteReindexStatus represents the progress of the migration of a single index
+type RemoteReindexStatus struct {
+	// Name of the index
+	Name string `json:"name"`
+
+	State RemoteReindexState `json:"state"`
+
+	// ID of the reindex task in the cluster
+	//
+	// +optional
+	TaskID string `json:"taskID,omitempty"`
+
+	// Number of documents to copy
+	//
+	// +optional
+	Total int64 `json:"total,omitempty"`
+
+	// Number of documents created in the destination index
+	//
+	// +optional
+	Created int64 `json:"created,omitempty"`
+
+	// Number of documents already present in the destination index
+	//
+	// +optional
+	VersionConflicts int64 `json:"versionConflicts,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message about a failed migration
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,24 @@
+package v1
+
+// MonitoringSpec defines the scraping of the metrics of the cluster by the Prometheus Operator
+type MonitoringSpec struct {
+	// Creates the ServiceMonitor of the cluster and labels the metrics Service for scraping.
+	// Disabling it deletes the ServiceMonitor. The metrics Service is kept, since the proxy serves
+	// the metrics with the certificate issued for it. Defaults to true
+	//
+	// +nullable
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Interval between the scrapes of the metrics, e.g. 30s. Defaults to the interval of
+	// Prometheus
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Labels added to the ServiceMonitor, e.g. matching the serviceMonitorSelector of Prometheus
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NetworkPolicySpec defines the NetworkPolicies the operator generates for the cluster
+type NetworkPolicySpec struct {
+	// Restrict the REST API of the nodes to the operator, the index management jobs and the
+	// consumers, and the transport port to the nodes of the cluster and of the managed clusters
+	// declaring it as remote cluster
+	//
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Pods allowed to call the REST API of the nodes, e.g. Kibana or the log collectors
+	//
+	// +optional
+	Consumers []NetworkPolicyPeerSpec `json:"consumers,omitempty"`
+}
+
+// NetworkPolicyPeerSpec selects pods allowed to call the REST API of the nodes
+type NetworkPolicyPeerSpec struct {
+	// Namespaces of the pods. The namespace of the cluster if not set, all namespaces if empty
+	//
+	// +nullable
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Pods of the namespaces. All pods of the namespaces if not set
+	//
+	// +nullable
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorVersionStatus identifies the operator reconciling the cluster
+type OperatorVersionStatus struct {
+	// The build version of the operator
+	Version string `json:"version"`
+
+	// The version of the reconcile logic of the operator. It changes with the resources or settings
+	// the operator reconciles independently of the build version
+	ReconcileSchemaVersion int32 `json:"reconcileSchemaVersion"`
+
+	// When the operator took over the cluster
+	Since metav1.Time `json:"since"`
+}
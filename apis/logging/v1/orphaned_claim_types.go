@@ -0,0 +1,15 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrphanedClaimCollectionSpec defines the deletion of the claims labeled for the cluster which no
+// node uses anymore, e.g. after removing a node. A claim is deleted once it was unused for the
+// grace period and used again before it is kept
+type OrphanedClaimCollectionSpec struct {
+	// How long a claim must be unused before it is deleted (e.g. 72h). Defaults to 24h
+	//
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
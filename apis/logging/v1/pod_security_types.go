@@ -0,0 +1,38 @@
+package v1
+
+// SeccompProfileType is the seccomp profile of the pods of the nodes
+//
+// +kubebuilder:validation:Enum:=RuntimeDefault;Unconfined
+type SeccompProfileType string
+
+const (
+	SeccompProfileRuntimeDefault SeccompProfileType = "RuntimeDefault"
+	SeccompProfileUnconfined     SeccompProfileType = "Unconfined"
+)
+
+// PodSecuritySpec defines the security contexts of the pods of the nodes. Setting all options,
+// with the RuntimeDefault seccomp profile, makes the pods pass the restricted pod security
+// admission level unless memory lock or host tuning is enabled. The security contexts of the
+// sidecars and init containers of the nodes are left to their spec
+type PodSecuritySpec struct {
+	// Seccomp profile of the pods. The restricted level requires RuntimeDefault
+	//
+	// +optional
+	SeccompProfile SeccompProfileType `json:"seccompProfile,omitempty"`
+
+	// Refuse to start the containers of the pods as root. The images must run as a non-root user
+	//
+	// +optional
+	RunAsNonRoot bool `json:"runAsNonRoot,omitempty"`
+
+	// Mount the root filesystems of the containers read-only. The containers write their
+	// temporary files to an emptyDir mounted at /tmp
+	//
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// Drop all capabilities of the containers and forbid the escalation of their privileges
+	//
+	// +optional
+	DropAllCapabilities bool `json:"dropAllCapabilities,omitempty"`
+}
@@ -0,0 +1,21 @@
+package v1
+
+// ElasticsearchPodTemplateSpec defines the metadata of the pods of the Elasticsearch nodes
+type ElasticsearchPodTemplateSpec struct {
+	// The labels and annotations merged onto the pods and their deployments or statefulsets
+	//
+	// +optional
+	Metadata ElasticsearchPodTemplateMetadata `json:"metadata,omitempty"`
+}
+
+// ElasticsearchPodTemplateMetadata defines the labels and annotations of the pods, e.g. for
+// service meshes or cost allocation. The labels set by the operator cannot be overridden
+type ElasticsearchPodTemplateMetadata struct {
+	// +nullable
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +nullable
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
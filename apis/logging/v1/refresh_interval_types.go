@@ -0,0 +1,45 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RefreshIntervalPolicySpec defines when the refresh interval of the write indices matching a
+// pattern is raised to improve the indexing throughput. The refresh interval an index had before
+// is restored once its ingest rate dropped or it is no longer a write index
+type RefreshIntervalPolicySpec struct {
+	// Pattern of the write indices the policy applies to (e.g. app-*)
+	//
+	// +kubebuilder:validation:MinLength=1
+	IndexPattern string `json:"indexPattern"`
+
+	// Refresh interval of the write indices during high ingest. Defaults to 30s
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ms|s|m)$`
+	// +optional
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+
+	// Number of documents indexed per second into a write index raising its refresh interval
+	//
+	// +kubebuilder:validation:Minimum=1
+	HighIngestRate int64 `json:"highIngestRate"`
+
+	// Number of documents indexed per second into a write index below which its refresh interval
+	// is restored. Defaults to half of the high ingest rate
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LowIngestRate *int64 `json:"lowIngestRate,omitempty"`
+}
+
+// RaisedRefreshIntervalStatus represents a write index whose refresh interval is raised
+type RaisedRefreshIntervalStatus struct {
+	Index string `json:"index"`
+
+	// The refresh interval of the index before it was raised. Empty if the index used the default
+	//
+	// +optional
+	OriginalRefreshInterval string `json:"originalRefreshInterval,omitempty"`
+
+	Since metav1.Time `json:"since"`
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchreindexes,categories=logging,shortName=esreindex
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="Phase",JSONPath=".status.phase",type=string
+// +kubebuilder:printcolumn:name="Total",JSONPath=".status.total",type=integer
+// +kubebuilder:printcolumn:name="Created",JSONPath=".status.created",type=integer
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// A reindex of documents into an index of an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Reindex"
+type ElasticsearchReindex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchReindexSpec   `json:"spec,omitempty"`
+	Status ElasticsearchReindexStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchReindexList contains a list of ElasticsearchReindex
+type ElasticsearchReindexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchReindex `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchReindex{}, &ElasticsearchReindexList{})
+}
+
+// ElasticsearchReindexSpec defines the documents to copy. The reindex is started once,
+// changes to the spec after it started are ignored. Deleting the resource cancels a running reindex
+type ElasticsearchReindexSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to run the reindex in
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The index to copy the documents from
+	Source ElasticsearchReindexSource `json:"source"`
+
+	// The index to copy the documents to
+	Dest ElasticsearchReindexDest `json:"dest"`
+
+	// What to do when a document already exists in the destination index. Defaults to abort
+	//
+	// +kubebuilder:validation:Enum:=abort;proceed
+	// +optional
+	Conflicts string `json:"conflicts,omitempty"`
+}
+
+// ElasticsearchReindexSource defines the index to copy the documents from
+type ElasticsearchReindexSource struct {
+	// The name or pattern of the source index
+	//
+	// +kubebuilder:validation:MinLength=1
+	Index string `json:"index"`
+
+	// Reindex from an external cluster instead of the local one. The host is added to
+	// reindex.remote.whitelist which restarts the nodes before the reindex starts
+	//
+	// +nullable
+	// +optional
+	Remote *ElasticsearchReindexRemote `json:"remote,omitempty"`
+
+	// Number of documents copied per batch. Defaults to 1000
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+}
+
+// ElasticsearchReindexRemote defines the external cluster to reindex from
+type ElasticsearchReindexRemote struct {
+	// URL of the external cluster (e.g. https://elasticsearch.example.com:9200).
+	// The certificate of an https endpoint must be trusted by the JVM of the nodes
+	//
+	// +kubebuilder:validation:Pattern=`^https?://[^/:]+:[0-9]+$`
+	Host string `json:"host"`
+
+	// Name of a secret in the same namespace with the username and password keys
+	// used to authenticate against the external cluster
+	//
+	// +optional
+	Secret string `json:"secret,omitempty"`
+}
+
+// ElasticsearchReindexDest defines the index to copy the documents to
+type ElasticsearchReindexDest struct {
+	// The name of the destination index
+	//
+	// +kubebuilder:validation:MinLength=1
+	Index string `json:"index"`
+
+	// Set to create to only copy documents missing from the destination index. Defaults to index
+	//
+	// +kubebuilder:validation:Enum:=index;create
+	// +optional
+	OpType string `json:"opType,omitempty"`
+}
+
+// ElasticsearchReindexPhase is the state of a reindex
+type ElasticsearchReindexPhase string
+
+const (
+	ElasticsearchReindexPhasePending   ElasticsearchReindexPhase = "Pending"
+	ElasticsearchReindexPhaseRunning   ElasticsearchReindexPhase = "Running"
+	ElasticsearchReindexPhaseCompleted ElasticsearchReindexPhase = "Completed"
+	ElasticsearchReindexPhaseFailed    ElasticsearchReindexPhase = "Failed"
+)
+
+// ElasticsearchReindexStatus represents the progress of a reindex
+type ElasticsearchReindexStatus struct {
+	// +optional
+	Phase ElasticsearchReindexPhase `json:"phase,omitempty"`
+
+	// ID of the reindex task in the cluster
+	//
+	// +optional
+	TaskID string `json:"taskID,omitempty"`
+
+	// Number of documents to copy
+	//
+	// +optional
+	Total int64 `json:"total,omitempty"`
+
+	// Number of documents created in the destination index
+	//
+	// +optional
+	Created int64 `json:"created,omitempty"`
+
+	// Number of documents updated in the destination index
+	//
+	// +optional
+	Updated int64 `json:"updated,omitempty"`
+
+	// Number of documents already present in the destination index
+	//
+	// +optional
+	VersionConflicts int64 `json:"versionConflicts,omitempty"`
+
+	// Number of documents which failed to be copied
+	//
+	// +optional
+	Failures int64 `json:"failures,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message about a pending or failed reindex
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,87 @@
+package v1
+
+// RemoteClusterSpec defines a connection to a remote cluster and the indices replicated from it.
+// A remote cluster without follower indices is only used by cross-cluster search
+type RemoteClusterSpec struct {
+	// Name of the remote cluster connection
+	//
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Name string `json:"name"`
+
+	// Name of an Elasticsearch cluster managed by the operator in the same namespace. The remote
+	// cluster has to declare this cluster as remote cluster as well to trust its certificates
+	//
+	// +optional
+	Elasticsearch string `json:"elasticsearch,omitempty"`
+
+	// Transport addresses (host:port) of an external cluster
+	//
+	// +optional
+	Seeds []string `json:"seeds,omitempty"`
+
+	// Name of a secret with the CA certificate (ca.crt) of the external cluster
+	//
+	// +optional
+	Secret string `json:"secret,omitempty"`
+
+	// Whether cross-cluster searches skip the remote cluster if it is unavailable instead of failing
+	//
+	// +optional
+	SkipUnavailable *bool `json:"skipUnavailable,omitempty"`
+
+	// Indices replicated from the remote cluster
+	//
+	// +optional
+	Followers []FollowerIndexSpec `json:"followers,omitempty"`
+}
+
+// FollowerIndexSpec defines an index replicated from a leader index of the remote cluster
+type FollowerIndexSpec struct {
+	// Name of the follower index in this cluster
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Name of the leader index in the remote cluster. Defaults to the name of the follower index
+	//
+	// +optional
+	LeaderIndex string `json:"leaderIndex,omitempty"`
+}
+
+// RemoteClusterStatus represents the connection state of a remote cluster
+type RemoteClusterStatus struct {
+	// Name of the remote cluster connection
+	Name string `json:"name"`
+
+	// Whether the cluster is connected to the remote cluster
+	Ready bool `json:"ready"`
+
+	// Number of nodes of the remote cluster connected to
+	//
+	// +optional
+	ConnectedNodes int32 `json:"connectedNodes,omitempty"`
+
+	// +optional
+	Followers []FollowerIndexStatus `json:"followers,omitempty"`
+
+	// Message about the last failed reconciliation
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FollowerIndexStatus represents the replication state of a follower index
+type FollowerIndexStatus struct {
+	// Name of the follower index
+	Name string `json:"name"`
+
+	// Replication status of the follower index (e.g. active, paused)
+	//
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Message about the last failed reconciliation
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
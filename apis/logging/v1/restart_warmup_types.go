@@ -0,0 +1,29 @@
+package v1
+
+// RestartWarmupSpec defines the queries run against the shards of a node restarted by a rolling
+// restart or update before the next node is restarted. Warming up the caches of the node reduces
+// the latency of the searches served by it right after its restart
+type RestartWarmupSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Queries []WarmupQuerySpec `json:"queries"`
+
+	// Number of times every query is run. Defaults to 1
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Iterations *int32 `json:"iterations,omitempty"`
+}
+
+// WarmupQuerySpec defines a warm-up query
+type WarmupQuerySpec struct {
+	// Pattern of the indices searched (e.g. app-*)
+	//
+	// +kubebuilder:validation:MinLength=1
+	IndexPattern string `json:"indexPattern"`
+
+	// Body of the search request in JSON. Defaults to a match_all query
+	//
+	// +optional
+	Query string `json:"query,omitempty"`
+}
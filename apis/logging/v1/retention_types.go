@@ -0,0 +1,62 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetentionSpec defines the rules for closing and deleting old indices
+type RetentionSpec struct {
+	// How often the operator executes the rules (e.g. 30m). Defaults to 1h
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Rules executed against the indices of the cluster
+	//
+	// +optional
+	Rules []RetentionRuleSpec `json:"rules,omitempty"`
+}
+
+// RetentionRuleSpec defines the retention of the indices matching a pattern
+type RetentionRuleSpec struct {
+	// The unique name of the rule
+	Name string `json:"name"`
+
+	// Pattern of the indices the rule applies to (e.g. app-*). Indices starting with a dot
+	// are only matched by patterns starting with a dot
+	IndexPattern string `json:"indexPattern"`
+
+	// Close the indices older than the given age (e.g. 7d)
+	//
+	// +optional
+	CloseAfter TimeUnit `json:"closeAfter,omitempty"`
+
+	// Delete the indices older than the given age (e.g. 30d)
+	//
+	// +optional
+	DeleteAfter TimeUnit `json:"deleteAfter,omitempty"`
+}
+
+// RetentionRuleStatus represents the result of the last execution of a retention rule
+type RetentionRuleStatus struct {
+	// Name of the rule
+	Name string `json:"name"`
+
+	// LastRun is the last time the rule was executed
+	LastRun metav1.Time `json:"lastRun"`
+
+	// Number of indices closed by the last execution
+	//
+	// +optional
+	Closed int32 `json:"closed,omitempty"`
+
+	// Number of indices deleted by the last execution
+	//
+	// +optional
+	Deleted int32 `json:"deleted,omitempty"`
+
+	// Message about the failures of the last execution
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
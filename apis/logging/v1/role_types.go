@@ -0,0 +1,114 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchroles,categories=logging,shortName=esrole
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="State",JSONPath=".status.state",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// A role of the security plugin of an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch Role"
+type ElasticsearchRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRoleSpec   `json:"spec,omitempty"`
+	Status ElasticsearchRoleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchRoleList contains a list of ElasticsearchRole
+type ElasticsearchRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRole{}, &ElasticsearchRoleList{})
+}
+
+// ElasticsearchRoleSpec defines the desired state of a role
+type ElasticsearchRoleSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to create the role in
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The name of the role in Elasticsearch. Defaults to the name of this resource
+	//
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Cluster wide actions or action groups the role allows (e.g. cluster_monitor)
+	//
+	// +optional
+	ClusterPermissions []string `json:"clusterPermissions,omitempty"`
+
+	// Actions or action groups the role allows on indices
+	//
+	// +optional
+	IndexPermissions []RoleIndexPermissionSpec `json:"indexPermissions,omitempty"`
+}
+
+// RoleIndexPermissionSpec defines the permissions of a role on the indices matching the patterns
+type RoleIndexPermissionSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Actions or action groups allowed on the indices (e.g. read)
+	//
+	// +optional
+	AllowedActions []string `json:"allowedActions,omitempty"`
+
+	// Query restricting the documents visible to the role
+	//
+	// +optional
+	DLS string `json:"dls,omitempty"`
+
+	// Fields visible to the role, or hidden when prefixed with ~
+	//
+	// +optional
+	FLS []string `json:"fls,omitempty"`
+
+	// Fields whose values are hashed for the role
+	//
+	// +optional
+	MaskedFields []string `json:"maskedFields,omitempty"`
+}
+
+// ElasticsearchRoleStatus defines the observed state of a role
+type ElasticsearchRoleStatus struct {
+	// +optional
+	State SecurityResourceState `json:"state,omitempty"`
+
+	// +optional
+	Reason SecurityResourceReason `json:"reason,omitempty"`
+
+	// Message about the state of the role, e.g. a validation error returned by Elasticsearch
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The generation of the spec last applied to Elasticsearch
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdated represents the last time that the status was updated.
+	//
+	// +optional
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// GetRoleName returns the name of the role in Elasticsearch
+func (role *ElasticsearchRole) GetRoleName() string {
+	if role.Spec.RoleName != "" {
+		return role.Spec.RoleName
+	}
+	return role.Name
+}
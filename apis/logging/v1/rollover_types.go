@@ -0,0 +1,92 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloverMode describes who rolls over the indices of an alias
+type RolloverMode string
+
+const (
+	// RolloverModeOperator when the operator calls the rollover API
+	RolloverModeOperator RolloverMode = "Operator"
+	// RolloverModeLifecycle when the lifecycle policy of the indices rolls them over
+	RolloverModeLifecycle RolloverMode = "Lifecycle"
+)
+
+// RolloverAliasSpec defines a write alias whose index is rolled over once one of the conditions is met
+type RolloverAliasSpec struct {
+	// Name of the write alias. The first index is created as <name>-000001
+	Name string `json:"name"`
+
+	// Conditions for rolling over the write index of the alias
+	//
+	// +optional
+	Conditions RolloverConditionsSpec `json:"conditions,omitempty"`
+
+	// How often the operator checks the conditions (e.g. 15m). Defaults to 15m
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Name of a lifecycle policy to delegate the rollover to. The operator falls back
+	// to checking the conditions itself if lifecycle management is not available in the cluster
+	//
+	// +optional
+	LifecyclePolicy string `json:"lifecyclePolicy,omitempty"`
+}
+
+// RolloverConditionsSpec defines when the write index of an alias is rolled over
+type RolloverConditionsSpec struct {
+	// The maximum age of the write index (e.g. 7d)
+	//
+	// +optional
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+
+	// The maximum size of the write index (e.g. 50gb)
+	//
+	// +kubebuilder:validation:Pattern:="^[0-9]+(b|kb|mb|gb|tb|pb)$"
+	// +optional
+	MaxSize string `json:"maxSize,omitempty"`
+
+	// The target size of the primary shards of the write index (e.g. 30gb). The maximum
+	// size of the write index is computed from the number of its primary shards and
+	// replaces maxSize
+	//
+	// +kubebuilder:validation:Pattern:="^[0-9]+(b|kb|mb|gb|tb|pb)$"
+	// +optional
+	MaxShardSize string `json:"maxShardSize,omitempty"`
+
+	// The maximum number of documents in the write index
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxDocs int64 `json:"maxDocs,omitempty"`
+}
+
+// RolloverAliasStatus represents the state of a rollover alias
+type RolloverAliasStatus struct {
+	// Name of the write alias
+	Name string `json:"name"`
+
+	// Who rolls over the indices of the alias
+	Mode RolloverMode `json:"mode"`
+
+	// The index currently written to through the alias
+	//
+	// +optional
+	WriteIndex string `json:"writeIndex,omitempty"`
+
+	// LastChecked is the last time the operator reconciled the alias
+	LastChecked metav1.Time `json:"lastChecked"`
+
+	// LastRollover is the last time the operator rolled over the alias
+	//
+	// +optional
+	LastRollover *metav1.Time `json:"lastRollover,omitempty"`
+
+	// Message about the last failed reconciliation
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
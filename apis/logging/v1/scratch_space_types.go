@@ -0,0 +1,23 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ElasticsearchScratchSpaceSpec defines the emptyDir volume of the temporary files of
+// Elasticsearch, e.g. of large aggregations and plugins, keeping them off the root filesystem
+// of the node
+type ElasticsearchScratchSpaceSpec struct {
+	// The size limit of the volume. Defaults to 1Gi
+	//
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// The storage medium of the volume. Memory keeps the files in a tmpfs counting against the
+	// memory limit of the Elasticsearch container
+	//
+	// +kubebuilder:validation:Enum="";Memory
+	// +optional
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+}
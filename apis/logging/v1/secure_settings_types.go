@@ -0,0 +1,27 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecureSettingsSpec references a Secret whose keys are added to the keystores of the Elasticsearch
+// nodes. The keys are the names of the settings (e.g. s3.client.default.access_key). Changes of
+// reloadable settings are reloaded by the nodes, changes of other settings restart the nodes
+type SecureSettingsSpec struct {
+	// Name of the Secret in the namespace of the cluster
+	//
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// SecureSettingsStatus represents the reloadable secure settings loaded by the nodes
+type SecureSettingsStatus struct {
+	// HMAC of the reloadable secure settings of the Secrets, keyed with a Secret of the cluster
+	Hash string `json:"hash"`
+
+	// Time the reloadable secure settings changed. The nodes reload them once their keystores
+	// are rebuilt from the updated Secrets
+	//
+	// +optional
+	ChangedSince *metav1.Time `json:"changedSince,omitempty"`
+}
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecuritySpec defines the security of the cluster
+type SecuritySpec struct {
+	// Certificates of the cluster supplied by the user instead of the pre-populated secret of
+	// the cluster
+	//
+	// +nullable
+	// +optional
+	Certs *CertsSpec `json:"certs,omitempty"`
+
+	// Restart of the nodes before the certificates of the cluster expire
+	//
+	// +nullable
+	// +optional
+	CertRotation *CertRotationSpec `json:"certRotation,omitempty"`
+
+	// TLS of the transport and HTTP layers of the nodes. The certificates of the layers are
+	// supplied by certs
+	//
+	// +nullable
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// Credentials the operator authenticates to the REST API with instead of its bearer token
+	// and client certificate, e.g. for clusters secured by X-Pack or Open Distro security
+	//
+	// +nullable
+	// +optional
+	OperatorCredentials *OperatorCredentialsSpec `json:"operatorCredentials,omitempty"`
+
+	// Authentication realms of the nodes for single sign-on. The operator renders them into
+	// elasticsearch.yml and their secure settings into the keystores of the nodes. Changed realms
+	// restart the nodes
+	//
+	// +optional
+	Realms []AuthenticationRealmSpec `json:"realms,omitempty"`
+
+	// Restricts the TLS of the nodes and of the clients of the operator to FIPS-approved
+	// protocols and cipher suites. The nodes load their certificates from the PEM files of the
+	// secret of the cluster instead of the JKS keystores. Toggling it restarts the nodes one after
+	// the other. The operator only uses FIPS validated cryptography if it is built for it
+	//
+	// +optional
+	FIPSMode bool `json:"fipsMode,omitempty"`
+
+	// Audit logging of the security plugin of the nodes
+	//
+	// +nullable
+	// +optional
+	Audit *AuditLoggingSpec `json:"audit,omitempty"`
+}
+
+// AuthenticationRealmType is the type of an authentication realm
+//
+// +kubebuilder:validation:Enum=OIDC;SAML
+type AuthenticationRealmType string
+
+const (
+	// AuthenticationRealmOIDC authenticates users with an OpenID Connect provider
+	AuthenticationRealmOIDC AuthenticationRealmType = "OIDC"
+
+	// AuthenticationRealmSAML authenticates users with a SAML identity provider
+	AuthenticationRealmSAML AuthenticationRealmType = "SAML"
+)
+
+// AuthenticationRealmSpec defines an authentication realm of the nodes
+type AuthenticationRealmSpec struct {
+	// Name of the realm, unique among the realms of the cluster
+	//
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9_-]*$`
+	Name string `json:"name"`
+
+	Type AuthenticationRealmType `json:"type"`
+
+	// Position of the realm in the realm chain
+	//
+	// +kubebuilder:validation:Minimum=0
+	Order int32 `json:"order"`
+
+	// Settings of the realm by their names relative to the realm, e.g. rp.client_id or
+	// idp.metadata.path
+	//
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Secure settings of the realm added to the keystores of the nodes, e.g. rp.client_secret
+	//
+	// +optional
+	SecureSettings []RealmSecureSetting `json:"secureSettings,omitempty"`
+}
+
+// RealmSecureSetting references the key of a secret holding a secure setting of a realm
+type RealmSecureSetting struct {
+	// Name of the setting relative to the realm
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Secret in the namespace of the cluster holding the value of the setting
+	SecretRef CertSecretReference `json:"secretRef"`
+
+	// Key of the secret holding the value of the setting
+	//
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// OperatorCredentialsType is the authentication scheme of the credentials of the operator
+//
+// +kubebuilder:validation:Enum=Basic;APIKey
+type OperatorCredentialsType string
+
+const (
+	// OperatorCredentialsBasic authenticates with the username and password keys of the secret
+	OperatorCredentialsBasic OperatorCredentialsType = "Basic"
+
+	// OperatorCredentialsAPIKey authenticates with the apiKey key of the secret holding the
+	// base64 encoded id:api_key, e.g. the encoded field returned by the create API key API
+	OperatorCredentialsAPIKey OperatorCredentialsType = "APIKey"
+)
+
+// OperatorCredentialsSpec defines the credentials of the operator stored in a secret
+type OperatorCredentialsSpec struct {
+	Type OperatorCredentialsType `json:"type"`
+
+	// Secret in the namespace of the cluster holding the credentials. Changed credentials are
+	// used from the next reconcile on
+	SecretRef CertSecretReference `json:"secretRef"`
+}
+
+// TLSSpec defines the TLS of the transport and HTTP layers of the nodes
+type TLSSpec struct {
+	// TLS of the communication of the nodes with each other. The security plugin of the nodes
+	// requires it to be enabled
+	//
+	// +nullable
+	// +optional
+	Transport *LayerTLSSpec `json:"transport,omitempty"`
+
+	// TLS of the REST API of the nodes. Disabling it serves the API over plain HTTP, e.g. behind
+	// a service mesh encrypting the traffic. Clients then authenticate with their bearer token.
+	// Enabling or disabling it restarts all nodes at once
+	//
+	// +nullable
+	// +optional
+	HTTP *LayerTLSSpec `json:"http,omitempty"`
+}
+
+// LayerTLSSpec defines the TLS of a layer of the nodes
+type LayerTLSSpec struct {
+	// Defaults to true
+	//
+	// +nullable
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// CertsSpec defines the certificates of the cluster supplied by the user per purpose. The operator
+// validates them and assembles the secret of the cluster from them. Purposes left unset keep the
+// certificates of the secret of the cluster. Changed certificates restart the nodes
+type CertsSpec struct {
+	// Certificate of the nodes to each other. Its subject must be CN=elasticsearch,OU=OpenShift,O=Logging
+	// and its DNS names must include the <cluster>-cluster service
+	//
+	// +nullable
+	// +optional
+	Transport *CertSource `json:"transport,omitempty"`
+
+	// Certificate of the REST API of the nodes. Its DNS names must include the <cluster> service
+	//
+	// +nullable
+	// +optional
+	HTTP *CertSource `json:"http,omitempty"`
+
+	// Client certificate of the operator. Its subject must be CN=system.admin,OU=OpenShift,O=Logging
+	//
+	// +nullable
+	// +optional
+	Admin *CertSource `json:"admin,omitempty"`
+}
+
+// CertSource references the secret of a certificate
+type CertSource struct {
+	// Secret of type kubernetes.io/tls in the namespace of the cluster. The ca.crt key must hold
+	// the CA chain of the certificate
+	SecretRef CertSecretReference `json:"secretRef"`
+}
+
+// CertSecretReference references a secret in the namespace of the cluster
+type CertSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// CertRotationSpec defines the proactive restart of the nodes before the certificates of the
+// cluster expire. The nodes otherwise only reload the certificates when the secret of the cluster
+// changes
+type CertRotationSpec struct {
+	// Number of days before the first certificate of the secret of the cluster expires the nodes
+	// are restarted to load the certificates of the secret. The nodes are restarted once per
+	// expiry
+	//
+	// +kubebuilder:validation:Minimum=1
+	RedeployBeforeDays int32 `json:"redeployBeforeDays"`
+}
+
+// CertificatesStatus represents the certificates of the secret of the cluster
+type CertificatesStatus struct {
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+
+	// Expiry of the certificates the nodes were last restarted for before they expire
+	//
+	// +nullable
+	// +optional
+	RedeployedFor *metav1.Time `json:"redeployedFor,omitempty"`
+
+	// Message about the certificates which cannot be parsed
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CertificateStatus is a certificate of the secret of the cluster
+type CertificateStatus struct {
+	// Key of the certificate in the secret of the cluster
+	Key string `json:"key"`
+
+	Subject string `json:"subject"`
+
+	NotAfter metav1.Time `json:"notAfter"`
+}
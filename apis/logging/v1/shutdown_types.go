@@ -0,0 +1,66 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicyType defines what happens to the data of the cluster when the custom resource is deleted
+//
+// +kubebuilder:validation:Enum:=Delete;Shutdown
+type DeletionPolicyType string
+
+const (
+	// DeletionPolicyDelete deletes the workloads right away
+	DeletionPolicyDelete DeletionPolicyType = "Delete"
+	// DeletionPolicyShutdown stops the ingestion, flushes the indices and optionally takes a
+	// final snapshot before the workloads are deleted
+	DeletionPolicyShutdown DeletionPolicyType = "Shutdown"
+)
+
+// DeletionPolicySpec defines how the cluster is shut down when the custom resource is deleted.
+// A namespace deletion removes the workloads regardless of the policy, the shutdown only
+// precedes it when the custom resource is deleted first
+type DeletionPolicySpec struct {
+	// Type of the deletion policy. Defaults to Delete
+	//
+	// +optional
+	Type DeletionPolicyType `json:"type,omitempty"`
+
+	// Name of a snapshot repository to take a final snapshot of all indices in before the workloads are deleted
+	//
+	// +optional
+	FinalSnapshotRepository string `json:"finalSnapshotRepository,omitempty"`
+
+	// The maximum duration of the shutdown after which the workloads are deleted anyway (e.g. 30m). Defaults to 10m
+	//
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ShutdownPhase is the progress of the shutdown of a deleted cluster
+type ShutdownPhase string
+
+const (
+	ShutdownPhaseStoppingIngestion ShutdownPhase = "StoppingIngestion"
+	ShutdownPhaseFlushing          ShutdownPhase = "Flushing"
+	ShutdownPhaseSnapshotting      ShutdownPhase = "Snapshotting"
+	ShutdownPhaseCompleted         ShutdownPhase = "Completed"
+)
+
+// ShutdownStatus represents the progress of the shutdown of a deleted cluster
+type ShutdownStatus struct {
+	Phase ShutdownPhase `json:"phase"`
+
+	// StartTime is the time the shutdown started
+	StartTime metav1.Time `json:"startTime"`
+
+	// Name of the final snapshot
+	//
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Message about the last failed step
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotRepositoryHealthCheckSpec defines the periodic health checks of snapshot repositories
+type SnapshotRepositoryHealthCheckSpec struct {
+	// How often to verify the repositories (e.g. 30m). Defaults to 1h
+	//
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Names of the repositories to check. Defaults to all repositories registered in the cluster
+	//
+	// +optional
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// SnapshotRepositoryStatus represents the health of a snapshot repository
+type SnapshotRepositoryStatus struct {
+	// Name of the snapshot repository
+	Name string `json:"name"`
+
+	// Whether the repository passed the last verification and read test
+	Healthy bool `json:"healthy"`
+
+	// LastChecked is the last time the repository was checked
+	LastChecked metav1.Time `json:"lastChecked"`
+
+	// LastSuccessfulCheck is the last time the repository passed a check
+	//
+	// +optional
+	LastSuccessfulCheck *metav1.Time `json:"lastSuccessfulCheck,omitempty"`
+
+	// LatestSnapshot is the end time of the most recent successful snapshot in the repository.
+	// The listing of the repository has no times, so the snapshot with the last name is taken as
+	// the most recent one. This holds for timestamped names, e.g. snapshot-2021.06.01, but not
+	// for names that do not sort in the order of creation
+	//
+	// +optional
+	LatestSnapshot *metav1.Time `json:"latestSnapshot,omitempty"`
+
+	// Number of snapshots stored in the repository
+	//
+	// +optional
+	Snapshots int32 `json:"snapshots,omitempty"`
+
+	// Message about the last failed check
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
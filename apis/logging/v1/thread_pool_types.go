@@ -0,0 +1,21 @@
+package v1
+
+// ElasticsearchThreadPoolSpec sizes the thread pools of the nodes, which Elasticsearch otherwise
+// sizes by the processors of the host rather than the CPU limit of the container
+type ElasticsearchThreadPoolSpec struct {
+	// Number of processors the nodes size their thread pools by. Defaults to the CPU limit of the
+	// Elasticsearch container rounded up, or the allocatable CPUs of the host without a limit
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +nullable
+	// +optional
+	Processors *int32 `json:"processors,omitempty"`
+
+	// Number of threads of the write thread pool. It cannot exceed the processors by more than
+	// one. Defaults to the processors
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +nullable
+	// +optional
+	WriteSize *int32 `json:"writeSize,omitempty"`
+}
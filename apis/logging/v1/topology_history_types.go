@@ -0,0 +1,63 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopologyHistorySpec defines the recording of every accepted spec of the cluster together with the
+// workloads generated for it to an S3 compatible bucket. Each generation of the spec is written to
+// its own object keyed by the time it was recorded, so the shape of the cluster at any point in time
+// can be looked up and reconstructed
+type TopologyHistorySpec struct {
+	// URL of the S3 compatible object storage (e.g. https://s3.eu-west-1.amazonaws.com). Objects are
+	// addressed in path style
+	//
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Bucket to write the records to. The bucket must exist
+	//
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	Bucket string `json:"bucket"`
+
+	// Region used to sign the requests. Defaults to us-east-1
+	//
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Prefix of the keys of the records. Defaults to <namespace>/<cluster>
+	//
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Secret in the namespace of the cluster holding the access_key_id and secret_access_key of
+	// the credentials allowed to write to the bucket
+	CredentialsSecretRef CertSecretReference `json:"credentialsSecretRef"`
+}
+
+// TopologyHistoryStatus represents the last record of the spec of the cluster
+type TopologyHistoryStatus struct {
+	// Generation of the spec of the last record
+	//
+	// +optional
+	RecordedGeneration int64 `json:"recordedGeneration,omitempty"`
+
+	// Key of the object of the last record
+	//
+	// +optional
+	LastKey string `json:"lastKey,omitempty"`
+
+	// LastRecorded is the last time a record was written
+	//
+	// +optional
+	LastRecorded *metav1.Time `json:"lastRecorded,omitempty"`
+
+	// LastAttempt is the last time a record was attempted
+	LastAttempt metav1.Time `json:"lastAttempt"`
+
+	// Message about the last failed record
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
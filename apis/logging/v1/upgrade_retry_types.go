@@ -0,0 +1,15 @@
+package v1
+
+// UpgradeRetryBudgetSpec limits the failed attempts of each phase of a node upgrade. Once a phase
+// exhausted its budget the upgrade stops until the node spec changes or the budget is raised
+type UpgradeRetryBudgetSpec struct {
+	// Failed attempts allowed for each upgrade phase
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int32 `json:"maxAttempts"`
+
+	// Failed attempts allowed for single upgrade phases overriding maxAttempts
+	//
+	// +optional
+	PhaseMaxAttempts map[ElasticsearchUpgradePhase]int32 `json:"phaseMaxAttempts,omitempty"`
+}
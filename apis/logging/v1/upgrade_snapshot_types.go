@@ -0,0 +1,30 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpgradeSnapshotSpec defines the CSI volume snapshots of the claims of the data nodes taken right
+// before the operator restarts a node for a rolling restart or update. The snapshots are kept to
+// recover a failed upgrade and have to be deleted by the administrator
+type UpgradeSnapshotSpec struct {
+	// The VolumeSnapshotClass of the snapshots. Defaults to the default class of the CSI driver
+	//
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// UpgradeSnapshotStatus is the latest snapshot of a claim taken before an upgrade of its node
+type UpgradeSnapshotStatus struct {
+	// The node using the claim
+	Node string `json:"node"`
+
+	// The snapshotted claim
+	ClaimName string `json:"claimName"`
+
+	// The VolumeSnapshot of the claim
+	SnapshotName string `json:"snapshotName"`
+
+	// When the snapshot was requested
+	CreationTime metav1.Time `json:"creationTime"`
+}
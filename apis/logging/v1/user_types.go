@@ -0,0 +1,137 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=elasticsearchusers,categories=logging,shortName=esuser
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Elasticsearch",JSONPath=".spec.elasticsearchName",type=string
+// +kubebuilder:printcolumn:name="State",JSONPath=".status.state",type=string
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date
+//
+// A user of the internal user database of an Elasticsearch cluster
+// +operator-sdk:csv:customresourcedefinitions:displayName="Elasticsearch User"
+type ElasticsearchUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchUserSpec   `json:"spec,omitempty"`
+	Status ElasticsearchUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+//
+// ElasticsearchUserList contains a list of ElasticsearchUser
+type ElasticsearchUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchUser{}, &ElasticsearchUserList{})
+}
+
+// ElasticsearchUserSpec defines the desired state of a user
+type ElasticsearchUserSpec struct {
+	// The name of the Elasticsearch cluster in the same namespace to create the user in
+	ElasticsearchName string `json:"elasticsearchName"`
+
+	// The name of the user in Elasticsearch. Defaults to the name of this resource
+	//
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// The key of the secret in the same namespace holding the password of the user. A changed
+	// password is pushed to Elasticsearch
+	PasswordSecretRef UserPasswordSecretReference `json:"passwordSecretRef"`
+
+	// Roles of the security plugin the user is mapped to, e.g. the names of ElasticsearchRoles
+	//
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+
+	// Backend roles of the user matched by the role mappings of the security plugin
+	//
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+
+	// Attributes of the user available to the document level security queries of the roles
+	//
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// UserPasswordSecretReference references the key of a secret holding a password
+type UserPasswordSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Defaults to password
+	//
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ElasticsearchUserStatus defines the observed state of a user
+type ElasticsearchUserStatus struct {
+	// +optional
+	State SecurityResourceState `json:"state,omitempty"`
+
+	// +optional
+	Reason SecurityResourceReason `json:"reason,omitempty"`
+
+	// Message about the state of the user, e.g. a validation error returned by Elasticsearch
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Salted hash of the password last pushed to Elasticsearch to detect its rotation
+	//
+	// +optional
+	PasswordHash string `json:"passwordHash,omitempty"`
+
+	// The generation of the spec last applied to Elasticsearch
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdated represents the last time that the status was updated.
+	//
+	// +optional
+	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// SecurityResourceState of an ElasticsearchUser or ElasticsearchRole
+type SecurityResourceState string
+
+const (
+	// SecurityResourceStateApplied when the resource is stored in Elasticsearch
+	SecurityResourceStateApplied SecurityResourceState = "Applied"
+
+	// SecurityResourceStatePending when the Elasticsearch cluster or the secret is not available yet
+	SecurityResourceStatePending SecurityResourceState = "Pending"
+
+	// SecurityResourceStateFailed when Elasticsearch rejected the resource
+	SecurityResourceStateFailed SecurityResourceState = "Failed"
+)
+
+type SecurityResourceReason string
+
+const (
+	SecurityResourceReasonApplied            SecurityResourceReason = "Applied"
+	SecurityResourceReasonClusterNotFound    SecurityResourceReason = "ClusterNotFound"
+	SecurityResourceReasonClusterUnavailable SecurityResourceReason = "ClusterUnavailable"
+	SecurityResourceReasonSecretNotFound     SecurityResourceReason = "SecretNotFound"
+	SecurityResourceReasonRejected           SecurityResourceReason = "RejectedByElasticsearch"
+)
+
+// GetUsername returns the name of the user in Elasticsearch
+func (user *ElasticsearchUser) GetUsername() string {
+	if user.Spec.Username != "" {
+		return user.Spec.Username
+	}
+	return user.Name
+}
@@ -0,0 +1,33 @@
+package v1
+
+// WorkloadMigrationPhase is the step of the migration of data nodes to a StatefulSet
+type WorkloadMigrationPhase string
+
+const (
+	// WorkloadMigrationPhaseCreatingNodes waits for the nodes of the StatefulSet to be ready
+	WorkloadMigrationPhaseCreatingNodes WorkloadMigrationPhase = "CreatingNodes"
+	// WorkloadMigrationPhaseDraining moves the shards off the nodes of the deployments
+	WorkloadMigrationPhaseDraining WorkloadMigrationPhase = "Draining"
+)
+
+// WorkloadMigrationStatus represents the progress of the migration of the deployments of
+// data nodes to a StatefulSet
+type WorkloadMigrationStatus struct {
+	// Name of the StatefulSet replacing the deployments
+	StatefulSetName string `json:"statefulSetName"`
+
+	// Names of the deployments left to remove
+	DeploymentNames []string `json:"deploymentNames"`
+
+	Phase WorkloadMigrationPhase `json:"phase"`
+
+	// Number of shards left on the nodes of the deployments
+	//
+	// +optional
+	RemainingShards int32 `json:"remainingShards,omitempty"`
+
+	// Message about a migration waiting on the cluster
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
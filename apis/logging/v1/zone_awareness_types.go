@@ -0,0 +1,47 @@
+package v1
+
+// ZoneAwarenessSpec defines how the zones of the Elasticsearch nodes are discovered to keep the
+// copies of a shard in different zones
+type ZoneAwarenessSpec struct {
+	// The label of the Kubernetes nodes holding their zone. Defaults to topology.kubernetes.io/zone
+	//
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Raise the replicas of critical indices while a zone is unavailable
+	//
+	// +optional
+	OutageReplicas *ZoneOutageReplicasSpec `json:"outageReplicas,omitempty"`
+}
+
+// ZoneOutageReplicasSpec defines the replicas of critical indices kept while a zone is
+// unavailable. The replicas are lowered to the redundancy policy once all zones are back
+type ZoneOutageReplicasSpec struct {
+	// Patterns of the critical indices
+	//
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Number of replicas of the critical indices while a zone is unavailable
+	//
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas"`
+}
+
+// ZoneAwarenessStatus represents the zones of the Elasticsearch nodes
+type ZoneAwarenessStatus struct {
+	// Zones the nodes of the cluster run in
+	//
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// Zones without nodes in the cluster
+	//
+	// +optional
+	UnavailableZones []string `json:"unavailableZones,omitempty"`
+
+	// Patterns of the indices with raised replicas
+	//
+	// +optional
+	RaisedIndexPatterns []string `json:"raisedIndexPatterns,omitempty"`
+}
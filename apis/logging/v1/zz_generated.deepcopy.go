@@ -6,426 +6,2851 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+func (in *AnalysisFilesSpec) DeepCopyInto(out *AnalysisFilesSpec) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
-func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisFilesSpec.
+func (in *AnalysisFilesSpec) DeepCopy() *AnalysisFilesSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterCondition)
+	out := new(AnalysisFilesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ClusterConditions) DeepCopyInto(out *ClusterConditions) {
-	{
-		in := &in
-		*out = make(ClusterConditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConditions.
-func (in ClusterConditions) DeepCopy() ClusterConditions {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterConditions)
-	in.DeepCopyInto(out)
-	return *out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+func (in *AnalysisFilesStatus) DeepCopyInto(out *AnalysisFilesStatus) {
 	*out = *in
+	if in.ChangedSince != nil {
+		in, out := &in.ChangedSince, &out.ChangedSince
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
-func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisFilesStatus.
+func (in *AnalysisFilesStatus) DeepCopy() *AnalysisFilesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterHealth)
+	out := new(AnalysisFilesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Elasticsearch) DeepCopyInto(out *Elasticsearch) {
+func (in *AuditLogVolumeSpec) DeepCopyInto(out *AuditLogVolumeSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Elasticsearch.
-func (in *Elasticsearch) DeepCopy() *Elasticsearch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogVolumeSpec.
+func (in *AuditLogVolumeSpec) DeepCopy() *AuditLogVolumeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Elasticsearch)
+	out := new(AuditLogVolumeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Elasticsearch) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchList) DeepCopyInto(out *ElasticsearchList) {
+func (in *AuditLoggingSpec) DeepCopyInto(out *AuditLoggingSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Elasticsearch, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]AuditEventType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(AuditLogVolumeSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchList.
-func (in *ElasticsearchList) DeepCopy() *ElasticsearchList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLoggingSpec.
+func (in *AuditLoggingSpec) DeepCopy() *AuditLoggingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchList)
+	out := new(AuditLoggingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ElasticsearchList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
+func (in *AuthenticationRealmSpec) DeepCopyInto(out *AuthenticationRealmSpec) {
 	*out = *in
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]ElasticsearchNodeRole, len(*in))
-		copy(*out, *in)
-	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.GenUUID != nil {
-		in, out := &in.GenUUID, &out.GenUUID
-		*out = new(string)
-		**out = **in
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]RealmSecureSetting, len(*in))
+		copy(*out, *in)
 	}
-	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNode.
-func (in *ElasticsearchNode) DeepCopy() *ElasticsearchNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationRealmSpec.
+func (in *AuthenticationRealmSpec) DeepCopy() *AuthenticationRealmSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNode)
+	out := new(AuthenticationRealmSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeSpec) DeepCopyInto(out *ElasticsearchNodeSpec) {
+func (in *BacklogScalingSpec) DeepCopyInto(out *BacklogScalingSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
 	}
-	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeSpec.
-func (in *ElasticsearchNodeSpec) DeepCopy() *ElasticsearchNodeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BacklogScalingSpec.
+func (in *BacklogScalingSpec) DeepCopy() *BacklogScalingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeSpec)
+	out := new(BacklogScalingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeStatus) DeepCopyInto(out *ElasticsearchNodeStatus) {
+func (in *BacklogScalingStatus) DeepCopyInto(out *BacklogScalingStatus) {
 	*out = *in
-	out.UpgradeStatus = in.UpgradeStatus
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]ElasticsearchNodeRole, len(*in))
-		copy(*out, *in)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(ClusterConditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ClearedSince != nil {
+		in, out := &in.ClearedSince, &out.ClearedSince
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeStatus.
-func (in *ElasticsearchNodeStatus) DeepCopy() *ElasticsearchNodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BacklogScalingStatus.
+func (in *BacklogScalingStatus) DeepCopy() *BacklogScalingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeStatus)
+	out := new(BacklogScalingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeUpgradeStatus) DeepCopyInto(out *ElasticsearchNodeUpgradeStatus) {
+func (in *BlockedAliasStatus) DeepCopyInto(out *BlockedAliasStatus) {
 	*out = *in
+	in.BlockedAt.DeepCopyInto(&out.BlockedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeUpgradeStatus.
-func (in *ElasticsearchNodeUpgradeStatus) DeepCopy() *ElasticsearchNodeUpgradeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockedAliasStatus.
+func (in *BlockedAliasStatus) DeepCopy() *BlockedAliasStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeUpgradeStatus)
+	out := new(BlockedAliasStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = make([]ElasticsearchNode, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Spec.DeepCopyInto(&out.Spec)
-	if in.IndexManagement != nil {
-		in, out := &in.IndexManagement, &out.IndexManagement
-		*out = new(IndexManagementSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
-func (in *ElasticsearchSpec) DeepCopy() *ElasticsearchSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchSpec)
+	out := new(CertManagerIssuerReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = make([]ElasticsearchNodeStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	out.Cluster = in.Cluster
-	if in.Pods != nil {
-		in, out := &in.Pods, &out.Pods
-		*out = make(map[ElasticsearchNodeRole]PodStateMap, len(*in))
-		for key, val := range *in {
-			var outVal map[PodStateType][]string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(PodStateMap, len(*in))
-				for key, val := range *in {
-					var outVal []string
-					if val == nil {
-						(*out)[key] = nil
-					} else {
-						in, out := &val, &outVal
-						*out = make([]string, len(*in))
-						copy(*out, *in)
-					}
-					(*out)[key] = outVal
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(ClusterConditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.IndexManagementStatus != nil {
-		in, out := &in.IndexManagementStatus, &out.IndexManagementStatus
-		*out = new(IndexManagementStatus)
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
-func (in *ElasticsearchStatus) DeepCopy() *ElasticsearchStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchStatus)
+	out := new(CertManagerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchStorageSpec) DeepCopyInto(out *ElasticsearchStorageSpec) {
+func (in *CertRotationSpec) DeepCopyInto(out *CertRotationSpec) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
-	if in.Size != nil {
-		in, out := &in.Size, &out.Size
-		x := (*in).DeepCopy()
-		*out = &x
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStorageSpec.
-func (in *ElasticsearchStorageSpec) DeepCopy() *ElasticsearchStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertRotationSpec.
+func (in *CertRotationSpec) DeepCopy() *CertRotationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchStorageSpec)
+	out := new(CertRotationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementActionSpec) DeepCopyInto(out *IndexManagementActionSpec) {
+func (in *CertSecretReference) DeepCopyInto(out *CertSecretReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementActionSpec.
-func (in *IndexManagementActionSpec) DeepCopy() *IndexManagementActionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertSecretReference.
+func (in *CertSecretReference) DeepCopy() *CertSecretReference {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementActionSpec)
+	out := new(CertSecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementActionsSpec) DeepCopyInto(out *IndexManagementActionsSpec) {
+func (in *CertSource) DeepCopyInto(out *CertSource) {
 	*out = *in
-	if in.Rollover != nil {
-		in, out := &in.Rollover, &out.Rollover
-		*out = new(IndexManagementActionSpec)
-		**out = **in
-	}
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementActionsSpec.
-func (in *IndexManagementActionsSpec) DeepCopy() *IndexManagementActionsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertSource.
+func (in *CertSource) DeepCopy() *CertSource {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementActionsSpec)
+	out := new(CertSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementDeletePhaseSpec) DeepCopyInto(out *IndexManagementDeletePhaseSpec) {
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementDeletePhaseSpec.
-func (in *IndexManagementDeletePhaseSpec) DeepCopy() *IndexManagementDeletePhaseSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementDeletePhaseSpec)
+	out := new(CertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementHotPhaseSpec) DeepCopyInto(out *IndexManagementHotPhaseSpec) {
+func (in *CertificatesStatus) DeepCopyInto(out *CertificatesStatus) {
 	*out = *in
-	in.Actions.DeepCopyInto(&out.Actions)
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RedeployedFor != nil {
+		in, out := &in.RedeployedFor, &out.RedeployedFor
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementHotPhaseSpec.
-func (in *IndexManagementHotPhaseSpec) DeepCopy() *IndexManagementHotPhaseSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesStatus.
+func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementHotPhaseSpec)
+	out := new(CertificatesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementMappingCondition) DeepCopyInto(out *IndexManagementMappingCondition) {
+func (in *CertsSpec) DeepCopyInto(out *CertsSpec) {
 	*out = *in
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(CertSource)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(CertSource)
+		**out = **in
+	}
+	if in.Admin != nil {
+		in, out := &in.Admin, &out.Admin
+		*out = new(CertSource)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingCondition.
-func (in *IndexManagementMappingCondition) DeepCopy() *IndexManagementMappingCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertsSpec.
+func (in *CertsSpec) DeepCopy() *CertsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementMappingCondition)
+	out := new(CertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateSpec) DeepCopyInto(out *ClientCertificateSpec) {
+	*out = *in
+	if in.OrganizationalUnits != nil {
+		in, out := &in.OrganizationalUnits, &out.OrganizationalUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateSpec.
+func (in *ClientCertificateSpec) DeepCopy() *ClientCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificatesSpec) DeepCopyInto(out *ClientCertificatesSpec) {
+	*out = *in
+	if in.Validity != nil {
+		in, out := &in.Validity, &out.Validity
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]ClientCertificateSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificatesSpec.
+func (in *ClientCertificatesSpec) DeepCopy() *ClientCertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClockSkewSpec) DeepCopyInto(out *ClockSkewSpec) {
+	*out = *in
+	if in.MaxSkew != nil {
+		in, out := &in.MaxSkew, &out.MaxSkew
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClockSkewSpec.
+func (in *ClockSkewSpec) DeepCopy() *ClockSkewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClockSkewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAlertsSpec) DeepCopyInto(out *ClusterAlertsSpec) {
+	*out = *in
+	if in.DiskLowPercent != nil {
+		in, out := &in.DiskLowPercent, &out.DiskLowPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiskHighPercent != nil {
+		in, out := &in.DiskHighPercent, &out.DiskHighPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiskFloodStagePercent != nil {
+		in, out := &in.DiskFloodStagePercent, &out.DiskFloodStagePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PendingTasks != nil {
+		in, out := &in.PendingTasks, &out.PendingTasks
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HeapUsagePercent != nil {
+		in, out := &in.HeapUsagePercent, &out.HeapUsagePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAlertsSpec.
+func (in *ClusterAlertsSpec) DeepCopy() *ClusterAlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ClusterConditions) DeepCopyInto(out *ClusterConditions) {
+	{
+		in := &in
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterConditions.
+func (in ClusterConditions) DeepCopy() ClusterConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterConditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplateSpec) DeepCopyInto(out *ComponentTemplateSpec) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplateSpec.
+func (in *ComponentTemplateSpec) DeepCopy() *ComponentTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposableIndexTemplateSpec) DeepCopyInto(out *ComposableIndexTemplateSpec) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ComposedOf != nil {
+		in, out := &in.ComposedOf, &out.ComposedOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposableIndexTemplateSpec.
+func (in *ComposableIndexTemplateSpec) DeepCopy() *ComposableIndexTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposableIndexTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposableTemplateStatus) DeepCopyInto(out *ComposableTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposableTemplateStatus.
+func (in *ComposableTemplateStatus) DeepCopy() *ComposableTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposableTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigValidationSpec) DeepCopyInto(out *ConfigValidationSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigValidationSpec.
+func (in *ConfigValidationSpec) DeepCopy() *ConfigValidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigValidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicySpec) DeepCopyInto(out *DeletionPolicySpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicySpec.
+func (in *DeletionPolicySpec) DeepCopy() *DeletionPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentRolloutStatus) DeepCopyInto(out *DeploymentRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRolloutStatus.
+func (in *DeploymentRolloutStatus) DeepCopy() *DeploymentRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryExportSpec) DeepCopyInto(out *DisasterRecoveryExportSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryExportSpec.
+func (in *DisasterRecoveryExportSpec) DeepCopy() *DisasterRecoveryExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryExportStatus) DeepCopyInto(out *DisasterRecoveryExportStatus) {
+	*out = *in
+	in.LastAttempt.DeepCopyInto(&out.LastAttempt)
+	if in.LastExported != nil {
+		in, out := &in.LastExported, &out.LastExported
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryExportStatus.
+func (in *DisasterRecoveryExportStatus) DeepCopy() *DisasterRecoveryExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskPressureSpec) DeepCopyInto(out *DiskPressureSpec) {
+	*out = *in
+	if in.BlockThreshold != nil {
+		in, out := &in.BlockThreshold, &out.BlockThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReleaseThreshold != nil {
+		in, out := &in.ReleaseThreshold, &out.ReleaseThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProtectedAliases != nil {
+		in, out := &in.ProtectedAliases, &out.ProtectedAliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskPressureSpec.
+func (in *DiskPressureSpec) DeepCopy() *DiskPressureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskPressureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskPressureStatus) DeepCopyInto(out *DiskPressureStatus) {
+	*out = *in
+	if in.BlockedAliases != nil {
+		in, out := &in.BlockedAliases, &out.BlockedAliases
+		*out = make([]BlockedAliasStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskPressureStatus.
+func (in *DiskPressureStatus) DeepCopy() *DiskPressureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskPressureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]EffectiveNodeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveNodeConfig) DeepCopyInto(out *EffectiveNodeConfig) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchNodeRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveNodeConfig.
+func (in *EffectiveNodeConfig) DeepCopy() *EffectiveNodeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveNodeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Elasticsearch) DeepCopyInto(out *Elasticsearch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Elasticsearch.
+func (in *Elasticsearch) DeepCopy() *Elasticsearch {
+	if in == nil {
+		return nil
+	}
+	out := new(Elasticsearch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Elasticsearch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchAliasCutover) DeepCopyInto(out *ElasticsearchAliasCutover) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAliasCutover.
+func (in *ElasticsearchAliasCutover) DeepCopy() *ElasticsearchAliasCutover {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchAliasCutover)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchAliasCutover) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchAliasCutoverList) DeepCopyInto(out *ElasticsearchAliasCutoverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchAliasCutover, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAliasCutoverList.
+func (in *ElasticsearchAliasCutoverList) DeepCopy() *ElasticsearchAliasCutoverList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchAliasCutoverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchAliasCutoverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchAliasCutoverSpec) DeepCopyInto(out *ElasticsearchAliasCutoverSpec) {
+	*out = *in
+	if in.DualWriteWindow != nil {
+		in, out := &in.DualWriteWindow, &out.DualWriteWindow
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SampleSize != nil {
+		in, out := &in.SampleSize, &out.SampleSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAliasCutoverSpec.
+func (in *ElasticsearchAliasCutoverSpec) DeepCopy() *ElasticsearchAliasCutoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchAliasCutoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchAliasCutoverStatus) DeepCopyInto(out *ElasticsearchAliasCutoverStatus) {
+	*out = *in
+	if in.DualWriteStartTime != nil {
+		in, out := &in.DualWriteStartTime, &out.DualWriteStartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAliasCutoverStatus.
+func (in *ElasticsearchAliasCutoverStatus) DeepCopy() *ElasticsearchAliasCutoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchAliasCutoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchCacheSpec) DeepCopyInto(out *ElasticsearchCacheSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchCacheSpec.
+func (in *ElasticsearchCacheSpec) DeepCopy() *ElasticsearchCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndex) DeepCopyInto(out *ElasticsearchIndex) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndex.
+func (in *ElasticsearchIndex) DeepCopy() *ElasticsearchIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchIndex) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexAliasSpec) DeepCopyInto(out *ElasticsearchIndexAliasSpec) {
+	*out = *in
+	if in.IsWriteIndex != nil {
+		in, out := &in.IsWriteIndex, &out.IsWriteIndex
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexAliasSpec.
+func (in *ElasticsearchIndexAliasSpec) DeepCopy() *ElasticsearchIndexAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexLifecyclePolicy) DeepCopyInto(out *ElasticsearchIndexLifecyclePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexLifecyclePolicy.
+func (in *ElasticsearchIndexLifecyclePolicy) DeepCopy() *ElasticsearchIndexLifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexLifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchIndexLifecyclePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexLifecyclePolicyList) DeepCopyInto(out *ElasticsearchIndexLifecyclePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchIndexLifecyclePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexLifecyclePolicyList.
+func (in *ElasticsearchIndexLifecyclePolicyList) DeepCopy() *ElasticsearchIndexLifecyclePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexLifecyclePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchIndexLifecyclePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexLifecyclePolicySpec) DeepCopyInto(out *ElasticsearchIndexLifecyclePolicySpec) {
+	*out = *in
+	in.Phases.DeepCopyInto(&out.Phases)
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexLifecyclePolicySpec.
+func (in *ElasticsearchIndexLifecyclePolicySpec) DeepCopy() *ElasticsearchIndexLifecyclePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexLifecyclePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexLifecyclePolicyStatus) DeepCopyInto(out *ElasticsearchIndexLifecyclePolicyStatus) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexLifecyclePolicyStatus.
+func (in *ElasticsearchIndexLifecyclePolicyStatus) DeepCopy() *ElasticsearchIndexLifecyclePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexLifecyclePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexList) DeepCopyInto(out *ElasticsearchIndexList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexList.
+func (in *ElasticsearchIndexList) DeepCopy() *ElasticsearchIndexList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchIndexList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexSpec) DeepCopyInto(out *ElasticsearchIndexSpec) {
+	*out = *in
+	if in.NumberOfReplicas != nil {
+		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]ElasticsearchIndexAliasSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexSpec.
+func (in *ElasticsearchIndexSpec) DeepCopy() *ElasticsearchIndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexStatus) DeepCopyInto(out *ElasticsearchIndexStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ResourceConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexStatus.
+func (in *ElasticsearchIndexStatus) DeepCopy() *ElasticsearchIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchJVMSpec) DeepCopyInto(out *ElasticsearchJVMSpec) {
+	*out = *in
+	if in.Heap != nil {
+		in, out := &in.Heap, &out.Heap
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchJVMSpec.
+func (in *ElasticsearchJVMSpec) DeepCopy() *ElasticsearchJVMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchJVMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchList) DeepCopyInto(out *ElasticsearchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Elasticsearch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchList.
+func (in *ElasticsearchList) DeepCopy() *ElasticsearchList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchLoggerSpec) DeepCopyInto(out *ElasticsearchLoggerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchLoggerSpec.
+func (in *ElasticsearchLoggerSpec) DeepCopy() *ElasticsearchLoggerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchLoggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchLoggingSpec) DeepCopyInto(out *ElasticsearchLoggingSpec) {
+	*out = *in
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make([]ElasticsearchLoggerSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rolling != nil {
+		in, out := &in.Rolling, &out.Rolling
+		*out = new(ElasticsearchRollingAppenderSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchLoggingSpec.
+func (in *ElasticsearchLoggingSpec) DeepCopy() *ElasticsearchLoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchLoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchMigrationSpec) DeepCopyInto(out *ElasticsearchMigrationSpec) {
+	*out = *in
+	if in.RemoteReindex != nil {
+		in, out := &in.RemoteReindex, &out.RemoteReindex
+		*out = new(RemoteReindexSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchMigrationSpec.
+func (in *ElasticsearchMigrationSpec) DeepCopy() *ElasticsearchMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchMigrationStatus) DeepCopyInto(out *ElasticsearchMigrationStatus) {
+	*out = *in
+	if in.RemoteReindex != nil {
+		in, out := &in.RemoteReindex, &out.RemoteReindex
+		*out = make([]RemoteReindexStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchMigrationStatus.
+func (in *ElasticsearchMigrationStatus) DeepCopy() *ElasticsearchMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchNodeRole, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(ElasticsearchPodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Caches != nil {
+		in, out := &in.Caches, &out.Caches
+		*out = new(ElasticsearchCacheSpec)
+		**out = **in
+	}
+	if in.ThreadPools != nil {
+		in, out := &in.ThreadPools, &out.ThreadPools
+		*out = new(ElasticsearchThreadPoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScratchSpace != nil {
+		in, out := &in.ScratchSpace, &out.ScratchSpace
+		*out = new(ElasticsearchScratchSpaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(ElasticsearchJVMSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.GenUUID != nil {
+		in, out := &in.GenUUID, &out.GenUUID
+		*out = new(string)
+		**out = **in
+	}
+	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNode.
+func (in *ElasticsearchNode) DeepCopy() *ElasticsearchNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeSpec) DeepCopyInto(out *ElasticsearchNodeSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(ElasticsearchPodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	if in.Caches != nil {
+		in, out := &in.Caches, &out.Caches
+		*out = new(ElasticsearchCacheSpec)
+		**out = **in
+	}
+	if in.ThreadPools != nil {
+		in, out := &in.ThreadPools, &out.ThreadPools
+		*out = new(ElasticsearchThreadPoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScratchSpace != nil {
+		in, out := &in.ScratchSpace, &out.ScratchSpace
+		*out = new(ElasticsearchScratchSpaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeSpec.
+func (in *ElasticsearchNodeSpec) DeepCopy() *ElasticsearchNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeStatus) DeepCopyInto(out *ElasticsearchNodeStatus) {
+	*out = *in
+	in.UpgradeStatus.DeepCopyInto(&out.UpgradeStatus)
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchNodeRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeStatus.
+func (in *ElasticsearchNodeStatus) DeepCopy() *ElasticsearchNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeUpgradeStatus) DeepCopyInto(out *ElasticsearchNodeUpgradeStatus) {
+	*out = *in
+	if in.ScheduledChanges != nil {
+		in, out := &in.ScheduledChanges, &out.ScheduledChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PhaseStartTime != nil {
+		in, out := &in.PhaseStartTime, &out.PhaseStartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeUpgradeStatus.
+func (in *ElasticsearchNodeUpgradeStatus) DeepCopy() *ElasticsearchNodeUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchPodTemplateMetadata) DeepCopyInto(out *ElasticsearchPodTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchPodTemplateMetadata.
+func (in *ElasticsearchPodTemplateMetadata) DeepCopy() *ElasticsearchPodTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchPodTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchPodTemplateSpec) DeepCopyInto(out *ElasticsearchPodTemplateSpec) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchPodTemplateSpec.
+func (in *ElasticsearchPodTemplateSpec) DeepCopy() *ElasticsearchPodTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchPodTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindex) DeepCopyInto(out *ElasticsearchReindex) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindex.
+func (in *ElasticsearchReindex) DeepCopy() *ElasticsearchReindex {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchReindex) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexDest) DeepCopyInto(out *ElasticsearchReindexDest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexDest.
+func (in *ElasticsearchReindexDest) DeepCopy() *ElasticsearchReindexDest {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexDest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexList) DeepCopyInto(out *ElasticsearchReindexList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchReindex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexList.
+func (in *ElasticsearchReindexList) DeepCopy() *ElasticsearchReindexList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchReindexList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexRemote) DeepCopyInto(out *ElasticsearchReindexRemote) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexRemote.
+func (in *ElasticsearchReindexRemote) DeepCopy() *ElasticsearchReindexRemote {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexRemote)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexSource) DeepCopyInto(out *ElasticsearchReindexSource) {
+	*out = *in
+	if in.Remote != nil {
+		in, out := &in.Remote, &out.Remote
+		*out = new(ElasticsearchReindexRemote)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexSource.
+func (in *ElasticsearchReindexSource) DeepCopy() *ElasticsearchReindexSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexSpec) DeepCopyInto(out *ElasticsearchReindexSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	out.Dest = in.Dest
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexSpec.
+func (in *ElasticsearchReindexSpec) DeepCopy() *ElasticsearchReindexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchReindexStatus) DeepCopyInto(out *ElasticsearchReindexStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReindexStatus.
+func (in *ElasticsearchReindexStatus) DeepCopy() *ElasticsearchReindexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchReindexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRole) DeepCopyInto(out *ElasticsearchRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRole.
+func (in *ElasticsearchRole) DeepCopy() *ElasticsearchRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRoleList) DeepCopyInto(out *ElasticsearchRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRoleList.
+func (in *ElasticsearchRoleList) DeepCopy() *ElasticsearchRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRoleSpec) DeepCopyInto(out *ElasticsearchRoleSpec) {
+	*out = *in
+	if in.ClusterPermissions != nil {
+		in, out := &in.ClusterPermissions, &out.ClusterPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexPermissions != nil {
+		in, out := &in.IndexPermissions, &out.IndexPermissions
+		*out = make([]RoleIndexPermissionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRoleSpec.
+func (in *ElasticsearchRoleSpec) DeepCopy() *ElasticsearchRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRoleStatus) DeepCopyInto(out *ElasticsearchRoleStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRoleStatus.
+func (in *ElasticsearchRoleStatus) DeepCopy() *ElasticsearchRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRollingAppenderSpec) DeepCopyInto(out *ElasticsearchRollingAppenderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRollingAppenderSpec.
+func (in *ElasticsearchRollingAppenderSpec) DeepCopy() *ElasticsearchRollingAppenderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRollingAppenderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchScratchSpaceSpec) DeepCopyInto(out *ElasticsearchScratchSpaceSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchScratchSpaceSpec.
+func (in *ElasticsearchScratchSpaceSpec) DeepCopy() *ElasticsearchScratchSpaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchScratchSpaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ElasticsearchNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.DataNodeCount != nil {
+		in, out := &in.DataNodeCount, &out.DataNodeCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IndexManagement != nil {
+		in, out := &in.IndexManagement, &out.IndexManagement
+		*out = new(IndexManagementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloverAliases != nil {
+		in, out := &in.RolloverAliases, &out.RolloverAliases
+		*out = make([]RolloverAliasSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotRepositoryHealthCheck != nil {
+		in, out := &in.SnapshotRepositoryHealthCheck, &out.SnapshotRepositoryHealthCheck
+		*out = new(SnapshotRepositoryHealthCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(ElasticsearchMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteClusterSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClockSkew != nil {
+		in, out := &in.ClockSkew, &out.ClockSkew
+		*out = new(ClockSkewSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecuritySpec)
+		**out = **in
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeRetryBudget != nil {
+		in, out := &in.UpgradeRetryBudget, &out.UpgradeRetryBudget
+		*out = new(UpgradeRetryBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecoveryExport != nil {
+		in, out := &in.DisasterRecoveryExport, &out.DisasterRecoveryExport
+		*out = new(DisasterRecoveryExportSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataBackup != nil {
+		in, out := &in.MetadataBackup, &out.MetadataBackup
+		*out = new(MetadataBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HotShardDetection != nil {
+		in, out := &in.HotShardDetection, &out.HotShardDetection
+		*out = new(HotShardDetectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BacklogScaling != nil {
+		in, out := &in.BacklogScaling, &out.BacklogScaling
+		*out = new(BacklogScalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskPressure != nil {
+		in, out := &in.DiskPressure, &out.DiskPressure
+		*out = new(DiskPressureSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmergencyRetention != nil {
+		in, out := &in.EmergencyRetention, &out.EmergencyRetention
+		*out = new(EmergencyRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanedClaimCollection != nil {
+		in, out := &in.OrphanedClaimCollection, &out.OrphanedClaimCollection
+		*out = new(OrphanedClaimCollectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshIntervalPolicies != nil {
+		in, out := &in.RefreshIntervalPolicies, &out.RefreshIntervalPolicies
+		*out = make([]RefreshIntervalPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradeSnapshots != nil {
+		in, out := &in.UpgradeSnapshots, &out.UpgradeSnapshots
+		*out = new(UpgradeSnapshotSpec)
+		**out = **in
+	}
+	if in.RestartWarmup != nil {
+		in, out := &in.RestartWarmup, &out.RestartWarmup
+		*out = new(RestartWarmupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigValidation != nil {
+		in, out := &in.ConfigValidation, &out.ConfigValidation
+		*out = new(ConfigValidationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]SecureSettingsSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnalysisFiles != nil {
+		in, out := &in.AnalysisFiles, &out.AnalysisFiles
+		*out = make([]AnalysisFilesSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(ElasticsearchLoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificates != nil {
+		in, out := &in.ClientCertificates, &out.ClientCertificates
+		*out = new(ClientCertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LegacyClientServices != nil {
+		in, out := &in.LegacyClientServices, &out.LegacyClientServices
+		*out = make([]LegacyClientServiceSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(ClusterAlertsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyHistory != nil {
+		in, out := &in.TopologyHistory, &out.TopologyHistory
+		*out = new(TopologyHistorySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
+func (in *ElasticsearchSpec) DeepCopy() *ElasticsearchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ElasticsearchNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Cluster = in.Cluster
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make(map[ElasticsearchNodeRole]PodStateMap, len(*in))
+		for key, val := range *in {
+			var outVal map[PodStateType][]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(PodStateMap, len(*in))
+				for key, val := range *in {
+					var outVal []string
+					if val == nil {
+						(*out)[key] = nil
+					} else {
+						in, out := &val, &outVal
+						*out = make([]string, len(*in))
+						copy(*out, *in)
+					}
+					(*out)[key] = outVal
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndexManagementStatus != nil {
+		in, out := &in.IndexManagementStatus, &out.IndexManagementStatus
+		*out = new(IndexManagementStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloverAliases != nil {
+		in, out := &in.RolloverAliases, &out.RolloverAliases
+		*out = make([]RolloverAliasStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = make([]RetentionRuleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepositoryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(ElasticsearchMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(ShutdownStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadMigrations != nil {
+		in, out := &in.WorkloadMigrations, &out.WorkloadMigrations
+		*out = make([]WorkloadMigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecoveryExport != nil {
+		in, out := &in.DisasterRecoveryExport, &out.DisasterRecoveryExport
+		*out = new(DisasterRecoveryExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataBackup != nil {
+		in, out := &in.MetadataBackup, &out.MetadataBackup
+		*out = new(MetadataBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyHistory != nil {
+		in, out := &in.TopologyHistory, &out.TopologyHistory
+		*out = new(TopologyHistoryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HotShardDetection != nil {
+		in, out := &in.HotShardDetection, &out.HotShardDetection
+		*out = new(HotShardDetectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BacklogScaling != nil {
+		in, out := &in.BacklogScaling, &out.BacklogScaling
+		*out = new(BacklogScalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskPressure != nil {
+		in, out := &in.DiskPressure, &out.DiskPressure
+		*out = new(DiskPressureStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmergencyRetention != nil {
+		in, out := &in.EmergencyRetention, &out.EmergencyRetention
+		*out = new(EmergencyRetentionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RaisedRefreshIntervals != nil {
+		in, out := &in.RaisedRefreshIntervals, &out.RaisedRefreshIntervals
+		*out = make([]RaisedRefreshIntervalStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeploymentRollouts != nil {
+		in, out := &in.DeploymentRollouts, &out.DeploymentRollouts
+		*out = make([]DeploymentRolloutStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeSnapshots != nil {
+		in, out := &in.UpgradeSnapshots, &out.UpgradeSnapshots
+		*out = make([]UpgradeSnapshotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = new(SecureSettingsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnalysisFiles != nil {
+		in, out := &in.AnalysisFiles, &out.AnalysisFiles
+		*out = new(AnalysisFilesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
+		*out = new(OperatorVersionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComposableTemplates != nil {
+		in, out := &in.ComposableTemplates, &out.ComposableTemplates
+		*out = make([]ComposableTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
+func (in *ElasticsearchStatus) DeepCopy() *ElasticsearchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchStorageSpec) DeepCopyInto(out *ElasticsearchStorageSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(corev1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStorageSpec.
+func (in *ElasticsearchStorageSpec) DeepCopy() *ElasticsearchStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchThreadPoolSpec) DeepCopyInto(out *ElasticsearchThreadPoolSpec) {
+	*out = *in
+	if in.Processors != nil {
+		in, out := &in.Processors, &out.Processors
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WriteSize != nil {
+		in, out := &in.WriteSize, &out.WriteSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchThreadPoolSpec.
+func (in *ElasticsearchThreadPoolSpec) DeepCopy() *ElasticsearchThreadPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchThreadPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchUser) DeepCopyInto(out *ElasticsearchUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchUser.
+func (in *ElasticsearchUser) DeepCopy() *ElasticsearchUser {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchUserList) DeepCopyInto(out *ElasticsearchUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ElasticsearchUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchUserList.
+func (in *ElasticsearchUserList) DeepCopy() *ElasticsearchUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ElasticsearchUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchUserSpec) DeepCopyInto(out *ElasticsearchUserSpec) {
+	*out = *in
+	out.PasswordSecretRef = in.PasswordSecretRef
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchUserSpec.
+func (in *ElasticsearchUserSpec) DeepCopy() *ElasticsearchUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchUserStatus) DeepCopyInto(out *ElasticsearchUserStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchUserStatus.
+func (in *ElasticsearchUserStatus) DeepCopy() *ElasticsearchUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmergencyDeletionStatus) DeepCopyInto(out *EmergencyDeletionStatus) {
+	*out = *in
+	in.DeletedAt.DeepCopyInto(&out.DeletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmergencyDeletionStatus.
+func (in *EmergencyDeletionStatus) DeepCopy() *EmergencyDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EmergencyDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmergencyRetentionSpec) DeepCopyInto(out *EmergencyRetentionSpec) {
+	*out = *in
+	if in.CriticalThreshold != nil {
+		in, out := &in.CriticalThreshold, &out.CriticalThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinDeletionInterval != nil {
+		in, out := &in.MinDeletionInterval, &out.MinDeletionInterval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmergencyRetentionSpec.
+func (in *EmergencyRetentionSpec) DeepCopy() *EmergencyRetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmergencyRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmergencyRetentionStatus) DeepCopyInto(out *EmergencyRetentionStatus) {
+	*out = *in
+	if in.DeletedIndices != nil {
+		in, out := &in.DeletedIndices, &out.DeletedIndices
+		*out = make([]EmergencyDeletionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmergencyRetentionStatus.
+func (in *EmergencyRetentionStatus) DeepCopy() *EmergencyRetentionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EmergencyRetentionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FollowerIndexSpec) DeepCopyInto(out *FollowerIndexSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FollowerIndexSpec.
+func (in *FollowerIndexSpec) DeepCopy() *FollowerIndexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FollowerIndexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FollowerIndexStatus) DeepCopyInto(out *FollowerIndexStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FollowerIndexStatus.
+func (in *FollowerIndexStatus) DeepCopy() *FollowerIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FollowerIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotShard) DeepCopyInto(out *HotShard) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotShard.
+func (in *HotShard) DeepCopy() *HotShard {
+	if in == nil {
+		return nil
+	}
+	out := new(HotShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotShardDetectionSpec) DeepCopyInto(out *HotShardDetectionSpec) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotShardDetectionSpec.
+func (in *HotShardDetectionSpec) DeepCopy() *HotShardDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HotShardDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HotShardDetectionStatus) DeepCopyInto(out *HotShardDetectionStatus) {
+	*out = *in
+	if in.LastSample != nil {
+		in, out := &in.LastSample, &out.LastSample
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]HotShard, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HotShardDetectionStatus.
+func (in *HotShardDetectionStatus) DeepCopy() *HotShardDetectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HotShardDetectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleActionsSpec) DeepCopyInto(out *IndexLifecycleActionsSpec) {
+	*out = *in
+	if in.Rollover != nil {
+		in, out := &in.Rollover, &out.Rollover
+		*out = new(IndexLifecycleRolloverSpec)
+		**out = **in
+	}
+	if in.ForceMerge != nil {
+		in, out := &in.ForceMerge, &out.ForceMerge
+		*out = new(IndexLifecycleForceMergeSpec)
+		**out = **in
+	}
+	if in.Shrink != nil {
+		in, out := &in.Shrink, &out.Shrink
+		*out = new(IndexLifecycleShrinkSpec)
+		**out = **in
+	}
+	if in.Allocate != nil {
+		in, out := &in.Allocate, &out.Allocate
+		*out = new(IndexLifecycleAllocateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleActionsSpec.
+func (in *IndexLifecycleActionsSpec) DeepCopy() *IndexLifecycleActionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleActionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleAllocateSpec) DeepCopyInto(out *IndexLifecycleAllocateSpec) {
+	*out = *in
+	if in.NumberOfReplicas != nil {
+		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Require != nil {
+		in, out := &in.Require, &out.Require
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleAllocateSpec.
+func (in *IndexLifecycleAllocateSpec) DeepCopy() *IndexLifecycleAllocateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleAllocateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleDeletePhaseSpec) DeepCopyInto(out *IndexLifecycleDeletePhaseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleDeletePhaseSpec.
+func (in *IndexLifecycleDeletePhaseSpec) DeepCopy() *IndexLifecycleDeletePhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleDeletePhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleForceMergeSpec) DeepCopyInto(out *IndexLifecycleForceMergeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleForceMergeSpec.
+func (in *IndexLifecycleForceMergeSpec) DeepCopy() *IndexLifecycleForceMergeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleForceMergeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecyclePhaseSpec) DeepCopyInto(out *IndexLifecyclePhaseSpec) {
+	*out = *in
+	in.Actions.DeepCopyInto(&out.Actions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecyclePhaseSpec.
+func (in *IndexLifecyclePhaseSpec) DeepCopy() *IndexLifecyclePhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecyclePhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecyclePhasesSpec) DeepCopyInto(out *IndexLifecyclePhasesSpec) {
+	*out = *in
+	if in.Hot != nil {
+		in, out := &in.Hot, &out.Hot
+		*out = new(IndexLifecyclePhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Warm != nil {
+		in, out := &in.Warm, &out.Warm
+		*out = new(IndexLifecyclePhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cold != nil {
+		in, out := &in.Cold, &out.Cold
+		*out = new(IndexLifecyclePhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(IndexLifecycleDeletePhaseSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecyclePhasesSpec.
+func (in *IndexLifecyclePhasesSpec) DeepCopy() *IndexLifecyclePhasesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecyclePhasesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleRolloverSpec) DeepCopyInto(out *IndexLifecycleRolloverSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleRolloverSpec.
+func (in *IndexLifecycleRolloverSpec) DeepCopy() *IndexLifecycleRolloverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleRolloverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexLifecycleShrinkSpec) DeepCopyInto(out *IndexLifecycleShrinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexLifecycleShrinkSpec.
+func (in *IndexLifecycleShrinkSpec) DeepCopy() *IndexLifecycleShrinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexLifecycleShrinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementActionSpec) DeepCopyInto(out *IndexManagementActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementActionSpec.
+func (in *IndexManagementActionSpec) DeepCopy() *IndexManagementActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementActionsSpec) DeepCopyInto(out *IndexManagementActionsSpec) {
+	*out = *in
+	if in.Rollover != nil {
+		in, out := &in.Rollover, &out.Rollover
+		*out = new(IndexManagementActionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementActionsSpec.
+func (in *IndexManagementActionsSpec) DeepCopy() *IndexManagementActionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementActionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementDeletePhaseSpec) DeepCopyInto(out *IndexManagementDeletePhaseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementDeletePhaseSpec.
+func (in *IndexManagementDeletePhaseSpec) DeepCopy() *IndexManagementDeletePhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementDeletePhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementHotPhaseSpec) DeepCopyInto(out *IndexManagementHotPhaseSpec) {
+	*out = *in
+	in.Actions.DeepCopyInto(&out.Actions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementHotPhaseSpec.
+func (in *IndexManagementHotPhaseSpec) DeepCopy() *IndexManagementHotPhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementHotPhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementMappingCondition) DeepCopyInto(out *IndexManagementMappingCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingCondition.
+func (in *IndexManagementMappingCondition) DeepCopy() *IndexManagementMappingCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementMappingCondition)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -433,398 +2858,1395 @@ func (in *IndexManagementMappingCondition) DeepCopy() *IndexManagementMappingCon
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementMappingStatus) DeepCopyInto(out *IndexManagementMappingStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]IndexManagementMappingCondition, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]IndexManagementMappingCondition, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingStatus.
+func (in *IndexManagementMappingStatus) DeepCopy() *IndexManagementMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPhasesSpec) DeepCopyInto(out *IndexManagementPhasesSpec) {
+	*out = *in
+	if in.Hot != nil {
+		in, out := &in.Hot, &out.Hot
+		*out = new(IndexManagementHotPhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Delete != nil {
+		in, out := &in.Delete, &out.Delete
+		*out = new(IndexManagementDeletePhaseSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPhasesSpec.
+func (in *IndexManagementPhasesSpec) DeepCopy() *IndexManagementPhasesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPhasesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPolicyCondition) DeepCopyInto(out *IndexManagementPolicyCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyCondition.
+func (in *IndexManagementPolicyCondition) DeepCopy() *IndexManagementPolicyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPolicyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPolicyMappingSpec) DeepCopyInto(out *IndexManagementPolicyMappingSpec) {
+	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyMappingSpec.
+func (in *IndexManagementPolicyMappingSpec) DeepCopy() *IndexManagementPolicyMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPolicyMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPolicySpec) DeepCopyInto(out *IndexManagementPolicySpec) {
+	*out = *in
+	in.Phases.DeepCopyInto(&out.Phases)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicySpec.
+func (in *IndexManagementPolicySpec) DeepCopy() *IndexManagementPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPolicyStatus) DeepCopyInto(out *IndexManagementPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]IndexManagementPolicyCondition, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyStatus.
+func (in *IndexManagementPolicyStatus) DeepCopy() *IndexManagementPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementSpec) DeepCopyInto(out *IndexManagementSpec) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]IndexManagementPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]IndexManagementPolicyMappingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComponentTemplates != nil {
+		in, out := &in.ComponentTemplates, &out.ComponentTemplates
+		*out = make([]ComponentTemplateSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndexTemplates != nil {
+		in, out := &in.IndexTemplates, &out.IndexTemplates
+		*out = make([]ComposableIndexTemplateSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementSpec.
+func (in *IndexManagementSpec) DeepCopy() *IndexManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementStatus) DeepCopyInto(out *IndexManagementStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]IndexManagementPolicyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]IndexManagementMappingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementStatus.
+func (in *IndexManagementStatus) DeepCopy() *IndexManagementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kibana) DeepCopyInto(out *Kibana) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]KibanaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
+func (in *Kibana) DeepCopy() *Kibana {
+	if in == nil {
+		return nil
+	}
+	out := new(Kibana)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Kibana) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaIndexMigrationSpec) DeepCopyInto(out *KibanaIndexMigrationSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaIndexMigrationSpec.
+func (in *KibanaIndexMigrationSpec) DeepCopy() *KibanaIndexMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaIndexMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaList) DeepCopyInto(out *KibanaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Kibana, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaList.
+func (in *KibanaList) DeepCopy() *KibanaList {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KibanaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
+	if in.IndexMigration != nil {
+		in, out := &in.IndexMigration, &out.IndexMigration
+		*out = new(KibanaIndexMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
+func (in *KibanaSpec) DeepCopy() *KibanaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
+	*out = *in
+	if in.ReplicaSets != nil {
+		in, out := &in.ReplicaSets, &out.ReplicaSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make(PodStateMap, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(map[string]ClusterConditions, len(*in))
+		for key, val := range *in {
+			var outVal []ClusterCondition
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(ClusterConditions, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
+func (in *KibanaStatus) DeepCopy() *KibanaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LayerTLSSpec) DeepCopyInto(out *LayerTLSSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LayerTLSSpec.
+func (in *LayerTLSSpec) DeepCopy() *LayerTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LayerTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LegacyClientServiceSpec) DeepCopyInto(out *LegacyClientServiceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LegacyClientServiceSpec.
+func (in *LegacyClientServiceSpec) DeepCopy() *LegacyClientServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LegacyClientServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataBackupSpec) DeepCopyInto(out *MetadataBackupSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataBackupSpec.
+func (in *MetadataBackupSpec) DeepCopy() *MetadataBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataBackupStatus) DeepCopyInto(out *MetadataBackupStatus) {
+	*out = *in
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+	if in.LastBackup != nil {
+		in, out := &in.LastBackup, &out.LastBackup
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataBackupStatus.
+func (in *MetadataBackupStatus) DeepCopy() *MetadataBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyPeerSpec) DeepCopyInto(out *NetworkPolicyPeerSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyPeerSpec.
+func (in *NetworkPolicyPeerSpec) DeepCopy() *NetworkPolicyPeerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyPeerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]NetworkPolicyPeerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorCredentialsSpec) DeepCopyInto(out *OperatorCredentialsSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorCredentialsSpec.
+func (in *OperatorCredentialsSpec) DeepCopy() *OperatorCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorVersionStatus) DeepCopyInto(out *OperatorVersionStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorVersionStatus.
+func (in *OperatorVersionStatus) DeepCopy() *OperatorVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedClaimCollectionSpec) DeepCopyInto(out *OrphanedClaimCollectionSpec) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedClaimCollectionSpec.
+func (in *OrphanedClaimCollectionSpec) DeepCopy() *OrphanedClaimCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedClaimCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecuritySpec) DeepCopyInto(out *PodSecuritySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecuritySpec.
+func (in *PodSecuritySpec) DeepCopy() *PodSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PodStateMap) DeepCopyInto(out *PodStateMap) {
+	{
+		in := &in
+		*out = make(PodStateMap, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodStateMap.
+func (in PodStateMap) DeepCopy() PodStateMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PodStateMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PolicyMap) DeepCopyInto(out *PolicyMap) {
+	{
+		in := &in
+		*out = make(PolicyMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyMap.
+func (in PolicyMap) DeepCopy() PolicyMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RaisedRefreshIntervalStatus) DeepCopyInto(out *RaisedRefreshIntervalStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RaisedRefreshIntervalStatus.
+func (in *RaisedRefreshIntervalStatus) DeepCopy() *RaisedRefreshIntervalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RaisedRefreshIntervalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealmSecureSetting) DeepCopyInto(out *RealmSecureSetting) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealmSecureSetting.
+func (in *RealmSecureSetting) DeepCopy() *RealmSecureSetting {
+	if in == nil {
+		return nil
+	}
+	out := new(RealmSecureSetting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshIntervalPolicySpec) DeepCopyInto(out *RefreshIntervalPolicySpec) {
+	*out = *in
+	if in.LowIngestRate != nil {
+		in, out := &in.LowIngestRate, &out.LowIngestRate
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshIntervalPolicySpec.
+func (in *RefreshIntervalPolicySpec) DeepCopy() *RefreshIntervalPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshIntervalPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterSpec) DeepCopyInto(out *RemoteClusterSpec) {
+	*out = *in
+	if in.Seeds != nil {
+		in, out := &in.Seeds, &out.Seeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipUnavailable != nil {
+		in, out := &in.SkipUnavailable, &out.SkipUnavailable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Followers != nil {
+		in, out := &in.Followers, &out.Followers
+		*out = make([]FollowerIndexSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterSpec.
+func (in *RemoteClusterSpec) DeepCopy() *RemoteClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
+	*out = *in
+	if in.Followers != nil {
+		in, out := &in.Followers, &out.Followers
+		*out = make([]FollowerIndexStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteReindexSpec) DeepCopyInto(out *RemoteReindexSpec) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteReindexSpec.
+func (in *RemoteReindexSpec) DeepCopy() *RemoteReindexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteReindexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteReindexStatus) DeepCopyInto(out *RemoteReindexStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteReindexStatus.
+func (in *RemoteReindexStatus) DeepCopy() *RemoteReindexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteReindexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceCondition) DeepCopyInto(out *ResourceCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceCondition.
+func (in *ResourceCondition) DeepCopy() *ResourceCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ResourceConditions) DeepCopyInto(out *ResourceConditions) {
+	{
+		in := &in
+		*out = make(ResourceConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceConditions.
+func (in ResourceConditions) DeepCopy() ResourceConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceConditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartWarmupSpec) DeepCopyInto(out *RestartWarmupSpec) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]WarmupQuerySpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartWarmupSpec.
+func (in *RestartWarmupSpec) DeepCopy() *RestartWarmupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartWarmupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionRuleSpec) DeepCopyInto(out *RetentionRuleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionRuleSpec.
+func (in *RetentionRuleSpec) DeepCopy() *RetentionRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionRuleStatus) DeepCopyInto(out *RetentionRuleStatus) {
+	*out = *in
+	in.LastRun.DeepCopyInto(&out.LastRun)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionRuleStatus.
+func (in *RetentionRuleStatus) DeepCopy() *RetentionRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionSpec) DeepCopyInto(out *RetentionSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RetentionRuleSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionSpec.
+func (in *RetentionSpec) DeepCopy() *RetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleIndexPermissionSpec) DeepCopyInto(out *RoleIndexPermissionSpec) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FLS != nil {
+		in, out := &in.FLS, &out.FLS
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaskedFields != nil {
+		in, out := &in.MaskedFields, &out.MaskedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleIndexPermissionSpec.
+func (in *RoleIndexPermissionSpec) DeepCopy() *RoleIndexPermissionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleIndexPermissionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloverAliasSpec) DeepCopyInto(out *RolloverAliasSpec) {
+	*out = *in
+	out.Conditions = in.Conditions
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloverAliasSpec.
+func (in *RolloverAliasSpec) DeepCopy() *RolloverAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloverAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloverAliasStatus) DeepCopyInto(out *RolloverAliasStatus) {
+	*out = *in
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+	if in.LastRollover != nil {
+		in, out := &in.LastRollover, &out.LastRollover
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloverAliasStatus.
+func (in *RolloverAliasStatus) DeepCopy() *RolloverAliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloverAliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloverConditionsSpec) DeepCopyInto(out *RolloverConditionsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloverConditionsSpec.
+func (in *RolloverConditionsSpec) DeepCopy() *RolloverConditionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloverConditionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecureSettingsSpec) DeepCopyInto(out *SecureSettingsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureSettingsSpec.
+func (in *SecureSettingsSpec) DeepCopy() *SecureSettingsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecureSettingsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecureSettingsStatus) DeepCopyInto(out *SecureSettingsStatus) {
+	*out = *in
+	if in.ChangedSince != nil {
+		in, out := &in.ChangedSince, &out.ChangedSince
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureSettingsStatus.
+func (in *SecureSettingsStatus) DeepCopy() *SecureSettingsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecureSettingsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
+	*out = *in
+	if in.Certs != nil {
+		in, out := &in.Certs, &out.Certs
+		*out = new(CertsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertRotation != nil {
+		in, out := &in.CertRotation, &out.CertRotation
+		*out = new(CertRotationSpec)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorCredentials != nil {
+		in, out := &in.OperatorCredentials, &out.OperatorCredentials
+		*out = new(OperatorCredentialsSpec)
+		**out = **in
+	}
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]AuthenticationRealmSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditLoggingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecuritySpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementMappingStatus.
-func (in *IndexManagementMappingStatus) DeepCopy() *IndexManagementMappingStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShutdownStatus) DeepCopyInto(out *ShutdownStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownStatus.
+func (in *ShutdownStatus) DeepCopy() *ShutdownStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementMappingStatus)
+	out := new(ShutdownStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementPhasesSpec) DeepCopyInto(out *IndexManagementPhasesSpec) {
+func (in *SnapshotRepositoryHealthCheckSpec) DeepCopyInto(out *SnapshotRepositoryHealthCheckSpec) {
 	*out = *in
-	if in.Hot != nil {
-		in, out := &in.Hot, &out.Hot
-		*out = new(IndexManagementHotPhaseSpec)
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Delete != nil {
-		in, out := &in.Delete, &out.Delete
-		*out = new(IndexManagementDeletePhaseSpec)
-		**out = **in
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPhasesSpec.
-func (in *IndexManagementPhasesSpec) DeepCopy() *IndexManagementPhasesSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepositoryHealthCheckSpec.
+func (in *SnapshotRepositoryHealthCheckSpec) DeepCopy() *SnapshotRepositoryHealthCheckSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementPhasesSpec)
+	out := new(SnapshotRepositoryHealthCheckSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementPolicyCondition) DeepCopyInto(out *IndexManagementPolicyCondition) {
+func (in *SnapshotRepositoryStatus) DeepCopyInto(out *SnapshotRepositoryStatus) {
 	*out = *in
+	in.LastChecked.DeepCopyInto(&out.LastChecked)
+	if in.LastSuccessfulCheck != nil {
+		in, out := &in.LastSuccessfulCheck, &out.LastSuccessfulCheck
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LatestSnapshot != nil {
+		in, out := &in.LatestSnapshot, &out.LatestSnapshot
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyCondition.
-func (in *IndexManagementPolicyCondition) DeepCopy() *IndexManagementPolicyCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepositoryStatus.
+func (in *SnapshotRepositoryStatus) DeepCopy() *SnapshotRepositoryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementPolicyCondition)
+	out := new(SnapshotRepositoryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementPolicyMappingSpec) DeepCopyInto(out *IndexManagementPolicyMappingSpec) {
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
 	*out = *in
-	if in.Aliases != nil {
-		in, out := &in.Aliases, &out.Aliases
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(LayerTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(LayerTLSSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyMappingSpec.
-func (in *IndexManagementPolicyMappingSpec) DeepCopy() *IndexManagementPolicyMappingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementPolicyMappingSpec)
+	out := new(TLSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementPolicySpec) DeepCopyInto(out *IndexManagementPolicySpec) {
+func (in *TopologyHistorySpec) DeepCopyInto(out *TopologyHistorySpec) {
 	*out = *in
-	in.Phases.DeepCopyInto(&out.Phases)
+	out.CredentialsSecretRef = in.CredentialsSecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicySpec.
-func (in *IndexManagementPolicySpec) DeepCopy() *IndexManagementPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyHistorySpec.
+func (in *TopologyHistorySpec) DeepCopy() *TopologyHistorySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementPolicySpec)
+	out := new(TopologyHistorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementPolicyStatus) DeepCopyInto(out *IndexManagementPolicyStatus) {
+func (in *TopologyHistoryStatus) DeepCopyInto(out *TopologyHistoryStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]IndexManagementPolicyCondition, len(*in))
-		copy(*out, *in)
+	if in.LastRecorded != nil {
+		in, out := &in.LastRecorded, &out.LastRecorded
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
 	}
-	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	in.LastAttempt.DeepCopyInto(&out.LastAttempt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPolicyStatus.
-func (in *IndexManagementPolicyStatus) DeepCopy() *IndexManagementPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyHistoryStatus.
+func (in *TopologyHistoryStatus) DeepCopy() *TopologyHistoryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementPolicyStatus)
+	out := new(TopologyHistoryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementSpec) DeepCopyInto(out *IndexManagementSpec) {
+func (in *UpgradeRetryBudgetSpec) DeepCopyInto(out *UpgradeRetryBudgetSpec) {
 	*out = *in
-	if in.Policies != nil {
-		in, out := &in.Policies, &out.Policies
-		*out = make([]IndexManagementPolicySpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Mappings != nil {
-		in, out := &in.Mappings, &out.Mappings
-		*out = make([]IndexManagementPolicyMappingSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.PhaseMaxAttempts != nil {
+		in, out := &in.PhaseMaxAttempts, &out.PhaseMaxAttempts
+		*out = make(map[ElasticsearchUpgradePhase]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementSpec.
-func (in *IndexManagementSpec) DeepCopy() *IndexManagementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRetryBudgetSpec.
+func (in *UpgradeRetryBudgetSpec) DeepCopy() *UpgradeRetryBudgetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementSpec)
+	out := new(UpgradeRetryBudgetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IndexManagementStatus) DeepCopyInto(out *IndexManagementStatus) {
+func (in *UpgradeSnapshotSpec) DeepCopyInto(out *UpgradeSnapshotSpec) {
 	*out = *in
-	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
-	if in.Policies != nil {
-		in, out := &in.Policies, &out.Policies
-		*out = make([]IndexManagementPolicyStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Mappings != nil {
-		in, out := &in.Mappings, &out.Mappings
-		*out = make([]IndexManagementMappingStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementStatus.
-func (in *IndexManagementStatus) DeepCopy() *IndexManagementStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSnapshotSpec.
+func (in *UpgradeSnapshotSpec) DeepCopy() *UpgradeSnapshotSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IndexManagementStatus)
+	out := new(UpgradeSnapshotSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kibana) DeepCopyInto(out *Kibana) {
+func (in *UpgradeSnapshotStatus) DeepCopyInto(out *UpgradeSnapshotStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = make([]KibanaStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
-func (in *Kibana) DeepCopy() *Kibana {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSnapshotStatus.
+func (in *UpgradeSnapshotStatus) DeepCopy() *UpgradeSnapshotStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Kibana)
+	out := new(UpgradeSnapshotStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Kibana) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KibanaList) DeepCopyInto(out *KibanaList) {
+func (in *UserPasswordSecretReference) DeepCopyInto(out *UserPasswordSecretReference) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Kibana, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaList.
-func (in *KibanaList) DeepCopy() *KibanaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserPasswordSecretReference.
+func (in *UserPasswordSecretReference) DeepCopy() *UserPasswordSecretReference {
 	if in == nil {
 		return nil
 	}
-	out := new(KibanaList)
+	out := new(UserPasswordSecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KibanaList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
+func (in *WarmupQuerySpec) DeepCopyInto(out *WarmupQuerySpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
-func (in *KibanaSpec) DeepCopy() *KibanaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmupQuerySpec.
+func (in *WarmupQuerySpec) DeepCopy() *WarmupQuerySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KibanaSpec)
+	out := new(WarmupQuerySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
+func (in *WorkloadMigrationStatus) DeepCopyInto(out *WorkloadMigrationStatus) {
 	*out = *in
-	if in.ReplicaSets != nil {
-		in, out := &in.ReplicaSets, &out.ReplicaSets
+	if in.DeploymentNames != nil {
+		in, out := &in.DeploymentNames, &out.DeploymentNames
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Pods != nil {
-		in, out := &in.Pods, &out.Pods
-		*out = make(PodStateMap, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(map[string]ClusterConditions, len(*in))
-		for key, val := range *in {
-			var outVal []ClusterCondition
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make(ClusterConditions, len(*in))
-				for i := range *in {
-					(*in)[i].DeepCopyInto(&(*out)[i])
-				}
-			}
-			(*out)[key] = outVal
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
-func (in *KibanaStatus) DeepCopy() *KibanaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMigrationStatus.
+func (in *WorkloadMigrationStatus) DeepCopy() *WorkloadMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KibanaStatus)
+	out := new(WorkloadMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PodStateMap) DeepCopyInto(out *PodStateMap) {
-	{
-		in := &in
-		*out = make(PodStateMap, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+func (in *ZoneAwarenessSpec) DeepCopyInto(out *ZoneAwarenessSpec) {
+	*out = *in
+	if in.OutageReplicas != nil {
+		in, out := &in.OutageReplicas, &out.OutageReplicas
+		*out = new(ZoneOutageReplicasSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodStateMap.
-func (in PodStateMap) DeepCopy() PodStateMap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessSpec.
+func (in *ZoneAwarenessSpec) DeepCopy() *ZoneAwarenessSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PodStateMap)
+	out := new(ZoneAwarenessSpec)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PolicyMap) DeepCopyInto(out *PolicyMap) {
-	{
-		in := &in
-		*out = make(PolicyMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+func (in *ZoneAwarenessStatus) DeepCopyInto(out *ZoneAwarenessStatus) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnavailableZones != nil {
+		in, out := &in.UnavailableZones, &out.UnavailableZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RaisedIndexPatterns != nil {
+		in, out := &in.RaisedIndexPatterns, &out.RaisedIndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyMap.
-func (in PolicyMap) DeepCopy() PolicyMap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessStatus.
+func (in *ZoneAwarenessStatus) DeepCopy() *ZoneAwarenessStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PolicyMap)
+	out := new(ZoneAwarenessStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+func (in *ZoneOutageReplicasSpec) DeepCopyInto(out *ZoneOutageReplicasSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
-func (in *ProxySpec) DeepCopy() *ProxySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneOutageReplicasSpec.
+func (in *ZoneOutageReplicasSpec) DeepCopy() *ZoneOutageReplicasSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ProxySpec)
+	out := new(ZoneOutageReplicasSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/ViaQ/logerr/log"
@@ -10,8 +11,12 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
@@ -20,8 +25,9 @@ import (
 // ElasticsearchReconciler reconciles a Elasticsearch object
 type ElasticsearchReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a Elasticsearch object and makes changes based on the state read
@@ -30,8 +36,44 @@ var (
 	reconcilePeriod = 30 * time.Second
 	// reconcileResult = reconcile.Result{RequeueAfter: reconcilePeriod}
 	reconcileResult = ctrl.Result{RequeueAfter: reconcilePeriod}
+	shutdownResult  = ctrl.Result{RequeueAfter: 5 * time.Second}
+	// userErrorResult delays the next reconciliation of a cluster which cannot be reconciled until
+	// the user changed its spec. The change itself triggers a reconciliation right away
+	userErrorResult = ctrl.Result{RequeueAfter: 5 * time.Minute}
 )
 
+// PendingUpdateScopes records the scopes of the updates of the clusters waiting to be reconciled
+type PendingUpdateScopes struct {
+	scopes map[types.NamespacedName]k8shandler.UpdateScope
+	mux    sync.Mutex
+}
+
+var pendingUpdateScopes PendingUpdateScopes
+
+func (p *PendingUpdateScopes) add(key types.NamespacedName, scope k8shandler.UpdateScope) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.scopes == nil {
+		p.scopes = map[types.NamespacedName]k8shandler.UpdateScope{}
+	}
+	p.scopes[key] = p.scopes[key].Merge(scope)
+}
+
+// pop returns and forgets the scope recorded for the cluster. Requests without a recorded scope,
+// e.g. periodic requeues, require a full reconcile
+func (p *PendingUpdateScopes) pop(key types.NamespacedName) k8shandler.UpdateScope {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	scope, ok := p.scopes[key]
+	if !ok {
+		return k8shandler.UpdateScope{Full: true}
+	}
+	delete(p.scopes, key)
+	return scope
+}
+
 func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	// Fetch the Elasticsearch instance
 	cluster := &loggingv1.Elasticsearch{}
@@ -48,6 +90,25 @@ func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, err
 	}
 
+	scope := pendingUpdateScopes.pop(request.NamespacedName)
+
+	if cluster.GetDeletionTimestamp() != nil {
+		done, err := k8shandler.Shutdown(cluster, r.Client)
+		if err != nil {
+			return shutdownResult, err
+		}
+		if !done {
+			return shutdownResult, nil
+		}
+		if err := k8shandler.ReclaimStorage(cluster, r.Client); err != nil {
+			return shutdownResult, err
+		}
+		if err := k8shandler.RevokeClientCertificates(cluster, r.Client); err != nil {
+			return shutdownResult, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	if cluster.Spec.ManagementState == loggingv1.ManagementStateUnmanaged {
 		return ctrl.Result{}, nil
 	}
@@ -75,16 +136,69 @@ func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result,
 
 	}
 
-	if err = k8shandler.Reconcile(cluster, r.Client); err != nil {
-		return reconcileResult, err
+	if !scope.Full {
+		// errors are retried with a full reconcile since the scope is forgotten
+		if err = k8shandler.ReconcileHandlers(cluster, r.Client, r.Recorder, scope.Handlers); err != nil {
+			return r.errorResult(cluster, err)
+		}
+		return reconcileResult, nil
+	}
+
+	if err = k8shandler.Reconcile(cluster, r.Client, r.Recorder); err != nil {
+		return r.errorResult(cluster, err)
 	}
 
+	if err = k8shandler.UpdateReconcileSuspendedCondition(cluster, nil, r.Client); err != nil {
+		return ctrl.Result{}, err
+	}
 	return reconcileResult, nil
 }
 
+// errorResult returns the result of a failed reconcile. Errors of the user wait for a change of
+// the spec and are reported by the ReconcileSuspended condition
+func (r *ElasticsearchReconciler) errorResult(cluster *loggingv1.Elasticsearch, err error) (ctrl.Result, error) {
+	if !k8shandler.IsUserError(err) {
+		// Transient errors are retried with the exponential backoff of the controller
+		return ctrl.Result{}, err
+	}
+
+	log.Error(err, "Elasticsearch cluster cannot be reconciled until its configuration is fixed",
+		"cluster", cluster.Name,
+		"namespace", cluster.Namespace)
+	if err := k8shandler.UpdateReconcileSuspendedCondition(cluster, err, r.Client); err != nil {
+		return ctrl.Result{}, err
+	}
+	return userErrorResult, nil
+}
+
 func (r *ElasticsearchReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("elasticsearch-controller").
 		For(&loggingv1.Elasticsearch{}).
+		WithEventFilter(elasticsearchUpdatePredicate()).
 		Complete(r)
 }
+
+// elasticsearchUpdatePredicate skips the updates of clusters which do not require a reconcile, e.g.
+// of the status or the labels only, and records the scope of the others
+func elasticsearchUpdatePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*loggingv1.Elasticsearch)
+			if !ok {
+				return true
+			}
+			newCluster, ok := e.ObjectNew.(*loggingv1.Elasticsearch)
+			if !ok {
+				return true
+			}
+
+			scope := k8shandler.ScopeOfUpdate(oldCluster, newCluster)
+			if scope.IsEmpty() {
+				return false
+			}
+			pendingUpdateScopes.add(types.NamespacedName{Name: newCluster.Name, Namespace: newCluster.Namespace}, scope)
+			return true
+		},
+	}
+}
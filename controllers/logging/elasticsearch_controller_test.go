@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// rejectingClient rejects the creation of PrometheusRules like an admission webhook
+type rejectingClient struct {
+	client.Client
+}
+
+func (c rejectingClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if rule, ok := obj.(*monitoringv1.PrometheusRule); ok {
+		return apierrors.NewInvalid(schema.GroupKind{Group: "monitoring.coreos.com", Kind: "PrometheusRule"}, rule.Name, nil)
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := loggingv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitoringv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestCluster() *loggingv1.Elasticsearch {
+	return &loggingv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch",
+			Namespace: "openshift-logging",
+		},
+		Spec: loggingv1.ElasticsearchSpec{
+			ManagementState: loggingv1.ManagementStateManaged,
+			Nodes: []loggingv1.ElasticsearchNode{
+				{
+					Roles:     []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleMaster, loggingv1.ElasticsearchRoleData},
+					NodeCount: 1,
+				},
+			},
+		},
+	}
+}
+
+func TestReconcileMissingSecretIsUserError(t *testing.T) {
+	s := newTestScheme(t)
+	cluster := newTestCluster()
+	key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+	r := &ElasticsearchReconciler{
+		Client:   fake.NewFakeClientWithScheme(s, cluster),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	result, err := r.Reconcile(ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("expected the missing secret to be reported as user error, got %v", err)
+	}
+	if result != userErrorResult {
+		t.Errorf("expected result %v, got %v", userErrorResult, result)
+	}
+
+	current := &loggingv1.Elasticsearch{}
+	if err := r.Get(context.TODO(), key, current); err != nil {
+		t.Fatal(err)
+	}
+	condition := findCondition(current.Status.Conditions, loggingv1.ReconcileSuspended)
+	if condition == nil || condition.Status != v1.ConditionTrue {
+		t.Fatalf("expected the ReconcileSuspended condition to be set, got %v", current.Status.Conditions)
+	}
+	if blocked := findCondition(current.Status.Conditions, loggingv1.Blocked); blocked == nil || blocked.Reason != loggingv1.BlockedReasonMissingSecret {
+		t.Errorf("expected the Blocked condition to name the missing secret, got %v", current.Status.Conditions)
+	}
+}
+
+func TestReconcileHandlersRejectedIsUserError(t *testing.T) {
+	os.Setenv("ALERTS_FILE_PATH", "../../files/prometheus_alerts.yml")
+	os.Setenv("RULES_FILE_PATH", "../../files/prometheus_recording_rules.yml")
+	defer os.Unsetenv("ALERTS_FILE_PATH")
+	defer os.Unsetenv("RULES_FILE_PATH")
+
+	s := newTestScheme(t)
+	cluster := newTestCluster()
+	key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+	r := &ElasticsearchReconciler{
+		Client:   rejectingClient{Client: fake.NewFakeClientWithScheme(s, cluster)},
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+	pendingUpdateScopes.add(key, k8shandler.UpdateScope{Handlers: []string{"alerts"}})
+
+	result, err := r.Reconcile(ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("expected the rejected rule to be reported as user error, got %v", err)
+	}
+	if result != userErrorResult {
+		t.Errorf("expected result %v, got %v", userErrorResult, result)
+	}
+
+	current := &loggingv1.Elasticsearch{}
+	if err := r.Get(context.TODO(), key, current); err != nil {
+		t.Fatal(err)
+	}
+	if condition := findCondition(current.Status.Conditions, loggingv1.ReconcileSuspended); condition == nil || condition.Status != v1.ConditionTrue {
+		t.Fatalf("expected the ReconcileSuspended condition to be set, got %v", current.Status.Conditions)
+	}
+}
+
+func findCondition(conditions []loggingv1.ClusterCondition, conditionType loggingv1.ClusterConditionType) *loggingv1.ClusterCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
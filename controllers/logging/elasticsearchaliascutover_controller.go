@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// ElasticsearchAliasCutoverReconciler reconciles a ElasticsearchAliasCutover object
+type ElasticsearchAliasCutoverReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ElasticsearchAliasCutoverReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	cutover := &loggingv1.ElasticsearchAliasCutover{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, cutover)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileElasticsearchAliasCutover(cutover, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to poll the progress of the cutover
+	return reconcileResult, nil
+}
+
+func (r *ElasticsearchAliasCutoverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchaliascutover-controller").
+		For(&loggingv1.ElasticsearchAliasCutover{}).
+		Complete(r)
+}
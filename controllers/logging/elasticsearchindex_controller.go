@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// ElasticsearchIndexReconciler reconciles a ElasticsearchIndex object
+type ElasticsearchIndexReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ElasticsearchIndexReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	index := &loggingv1.ElasticsearchIndex{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, index)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileElasticsearchIndex(index, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to pick up clusters becoming available and indices being recreated
+	return reconcileResult, nil
+}
+
+func (r *ElasticsearchIndexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchindex-controller").
+		For(&loggingv1.ElasticsearchIndex{}).
+		Complete(r)
+}
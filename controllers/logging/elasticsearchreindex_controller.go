@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// ElasticsearchReindexReconciler reconciles a ElasticsearchReindex object
+type ElasticsearchReindexReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ElasticsearchReindexReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	reindex := &loggingv1.ElasticsearchReindex{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, reindex)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileElasticsearchReindex(reindex, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to poll the progress of the reindex task
+	return reconcileResult, nil
+}
+
+func (r *ElasticsearchReindexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchreindex-controller").
+		For(&loggingv1.ElasticsearchReindex{}).
+		Complete(r)
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// ElasticsearchRoleReconciler reconciles a ElasticsearchRole object
+type ElasticsearchRoleReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ElasticsearchRoleReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	role := &loggingv1.ElasticsearchRole{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, role)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileElasticsearchRole(role, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to pick up clusters becoming available and roles changed in the cluster
+	return reconcileResult, nil
+}
+
+func (r *ElasticsearchRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchrole-controller").
+		For(&loggingv1.ElasticsearchRole{}).
+		Complete(r)
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// ElasticsearchUserReconciler reconciles a ElasticsearchUser object
+type ElasticsearchUserReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *ElasticsearchUserReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	user := &loggingv1.ElasticsearchUser{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, user)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileElasticsearchUser(user, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to pick up clusters becoming available and rotated passwords
+	return reconcileResult, nil
+}
+
+func (r *ElasticsearchUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticsearchuser-controller").
+		For(&loggingv1.ElasticsearchUser{}).
+		Complete(r)
+}
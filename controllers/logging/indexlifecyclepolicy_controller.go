@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// IndexLifecyclePolicyReconciler reconciles a ElasticsearchIndexLifecyclePolicy object
+type IndexLifecyclePolicyReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+func (r *IndexLifecyclePolicyReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	policy := &loggingv1.ElasticsearchIndexLifecyclePolicy{}
+
+	err := r.Get(context.TODO(), request.NamespacedName, policy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if err = k8shandler.ReconcileIndexLifecyclePolicy(policy, r.Client, r.Recorder); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to pick up clusters becoming available and templates being recreated
+	return reconcileResult, nil
+}
+
+func (r *IndexLifecyclePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("indexlifecyclepolicy-controller").
+		For(&loggingv1.ElasticsearchIndexLifecyclePolicy{}).
+		Complete(r)
+}
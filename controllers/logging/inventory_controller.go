@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+// InventoryReconciler maintains the inventory of the clusters managed by the operator
+type InventoryReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Namespace holds the inventory ConfigMap
+	Namespace string
+}
+
+// Reconcile rebuilds the whole inventory on every change of a cluster, regardless of the
+// cluster of the request
+func (r *InventoryReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
+	if err := k8shandler.ReconcileInventory(r.Namespace, r.Client); err != nil {
+		return reconcileResult, err
+	}
+
+	// requeue to pick up status changes the event filter of the cluster controller skips
+	return reconcileResult, nil
+}
+
+func (r *InventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("inventory-controller").
+		For(&loggingv1.Elasticsearch{}).
+		Complete(r)
+}
@@ -14,6 +14,7 @@ require (
 	github.com/onsi/gomega v1.10.1
 	github.com/openshift/api v0.0.0-20200602204738-768b7001fe69
 	github.com/operator-framework/operator-sdk v0.19.4
+	github.com/prometheus/client_golang v1.5.1
 	github.com/sergi/go-diff v1.1.0 // indirect
 	github.com/sirupsen/logrus v1.6.0 // indirect
 	go.uber.org/zap v1.16.0 // indirect
@@ -42,11 +42,13 @@ type Client interface {
 	GetDiskWatermarks() (interface{}, interface{}, error)
 	GetMinMasterNodes() (int32, error)
 	SetMinMasterNodes(numberMasters int32) (bool, error)
+	SetLoggerLevels(levels map[string]interface{}) error
 	DoSynchronizedFlush() (bool, error)
 
 	// Cluster State API
 	GetLowestClusterVersion() (string, error)
 	IsNodeInCluster(nodeName string) (bool, error)
+	GetElectedMasterIP() (string, error)
 
 	// Health API
 	GetClusterHealth() (api.ClusterHealth, error)
@@ -57,19 +59,48 @@ type Client interface {
 	GetIndex(name string) (*estypes.Index, error)
 	CreateIndex(name string, index *estypes.Index) error
 	ReIndex(src, dst, script, lang string) error
+	StartReIndex(reindex *estypes.ReIndexRequest) (string, error)
+	GetReIndexTask(taskID string) (*estypes.ReIndexTask, error)
+	CancelTask(taskID string) error
 	GetAllIndices(name string) (estypes.CatIndicesResponses, error)
+	GetIndexDefinition(name string) (*estypes.IndexDefinition, error)
+	CreateIndexDefinition(name string, index *estypes.IndexDefinition) error
+	PutIndexMappings(name string, mappings map[string]interface{}) error
+	ListIndicesCreationDate(pattern string) (estypes.CatIndicesResponses, error)
+	GetIndexingTotals(pattern string) (map[string]int64, error)
+	GetIndicesStoreSize(pattern string) (map[string]int64, error)
+	CloseIndex(name string) error
+	DeleteIndex(name string) error
+	ReloadSearchAnalyzers(pattern string) error
+
+	// Document API
+	CountDocuments(index string) (int64, error)
+	SampleDocuments(index string, size int32) ([]estypes.Document, error)
+	GetDocuments(index string, ids []string) ([]estypes.Document, error)
+	SearchOnNodes(pattern string, nodes []string, body string) error
 
 	// Index Alias API
 	ListIndicesForAlias(aliasPattern string) ([]string, error)
 	UpdateAlias(actions estypes.AliasActions) error
+	GetWriteIndex(alias string) (string, error)
+	ListWriteIndices() (sets.String, error)
+	ListWriteAliases() (map[string]string, error)
+	RolloverAlias(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error)
 	AddAliasForOldIndices() bool
 
 	// Index Settings API
 	GetIndexSettings(name string) (*estypes.IndexSettings, error)
 	UpdateIndexSettings(name string, settings *estypes.IndexSettings) error
+	PutIndexSettings(name string, settings map[string]string) error
+	GetRefreshIntervals(pattern string) (map[string]string, error)
+	SetRefreshInterval(index, interval string) error
 
 	// Nodes API
 	GetNodeDiskUsage(nodeName string) (string, float64, error)
+	GetNodesDiskUsedPercent() (map[string]float64, error)
+	GetNodesSetting(setting string) (map[string]string, error)
+	GetNodeTimestamps() (map[string]time.Time, error)
+	ReloadSecureSettings() error
 
 	// Replicas
 	UpdateReplicaCount(replicaCount int32) error
@@ -79,6 +110,10 @@ type Client interface {
 	ClearTransientShardAllocation() (bool, error)
 	GetShardAllocation() (string, error)
 	SetShardAllocation(state api.ShardAllocationState) (bool, error)
+	GetAllocationExcludedNodes() ([]string, error)
+	SetAllocationExcludedNodes(names []string) error
+	GetNodeShardCounts() (map[string]int32, error)
+	GetShardStats() (estypes.CatShardsResponses, error)
 
 	// Index Templates API
 	CreateIndexTemplate(name string, template *estypes.IndexTemplate) error
@@ -87,6 +122,44 @@ type Client interface {
 	GetIndexTemplates() (map[string]estypes.GetIndexTemplate, error)
 	UpdateTemplatePrimaryShards(shardCount int32) error
 
+	// Composable Index Templates API
+	CreateOrUpdateComponentTemplate(name string, template *estypes.ComponentTemplate) error
+	DeleteComponentTemplate(name string) error
+	ListComponentTemplates() ([]string, error)
+	CreateOrUpdateComposableIndexTemplate(name string, template *estypes.ComposableIndexTemplate) error
+	DeleteComposableIndexTemplate(name string) error
+
+	// Index Lifecycle Management API
+	CreateOrUpdateLifecyclePolicy(name string, policy *estypes.LifecyclePolicy) error
+	DeleteLifecyclePolicy(name string) error
+	SetTemplateLifecyclePolicy(template, policy, rolloverAlias string) error
+	IsLifecycleManagementAvailable() (bool, error)
+
+	// Security API
+	CreateOrUpdateInternalUser(name string, user *estypes.InternalUser) error
+	DeleteInternalUser(name string) error
+	CreateOrUpdateSecurityRole(name string, role *estypes.SecurityRole) error
+	DeleteSecurityRole(name string) error
+
+	// Snapshot API
+	ListSnapshotRepositories() ([]string, error)
+	VerifySnapshotRepository(repository string) error
+	ListSnapshots(repository string) ([]estypes.Snapshot, error)
+	CreateSnapshot(repository, name string, snapshot *estypes.CreateSnapshot) error
+	GetSnapshot(repository, name string) (*estypes.Snapshot, error)
+
+	// Cluster Metadata API
+	GetClusterMetadata() (*estypes.ClusterMetadata, error)
+
+	// Remote Cluster API
+	UpdateRemoteClusterSettings(name string, seeds []string, skipUnavailable *bool) error
+	GetRemoteClusterInfo() (map[string]estypes.RemoteClusterInfo, error)
+
+	// Cross-Cluster Replication API
+	IsCrossClusterReplicationAvailable() (bool, error)
+	ListFollowerIndices() ([]estypes.FollowerIndexInfo, error)
+	FollowIndex(name string, follow *estypes.FollowIndex) error
+
 	SetSendRequestFn(fn FnEsSendRequest)
 }
 
@@ -133,9 +206,32 @@ func (ec *esClient) errorCtx() kverrors.Context {
 	)
 }
 
+// clusterKey is the key of the per-cluster settings shared by the clients of a cluster
+func clusterKey(cluster, namespace string) string {
+	return fmt.Sprintf("%s/%s", namespace, cluster)
+}
+
 // FIXME: this needs to return an error instead of swallowing
 func sendEsRequest(cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
-	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", cluster, namespace, payload.URI)
+	sendEsRequestWithScheme(httpScheme(cluster, namespace), cluster, namespace, payload, client)
+
+	// the nodes serve the previous scheme until they are restarted for enabling or disabling TLS.
+	// Requests changing the cluster are only sent again if the nodes never received them
+	if alternate := alternateHTTPScheme(cluster, namespace); alternate != "" && isServiceUnreachable(payload) &&
+		(isReadRequest(payload) || isRequestNotSent(payload.Error)) {
+		log.Info("failed sending payload, retrying with the previous scheme", "method", payload.Method, "url", payload.URI, "scheme", alternate)
+		payload.Error = nil
+		sendEsRequestWithScheme(alternate, cluster, namespace, payload, client)
+	}
+
+	// keep the status informative while the service is down but the pods are running
+	if isServiceUnreachable(payload) && isReadRequest(payload) {
+		sendRequestToPods(cluster, namespace, payload, client)
+	}
+}
+
+func sendEsRequestWithScheme(scheme, cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
+	u := fmt.Sprintf("%s://%s.%s.svc:9200/%s", scheme, cluster, namespace, payload.URI)
 	urlURL, err := url.Parse(u)
 	if err != nil {
 		log.Error(err, "failed to parse URL", "url", u)
@@ -172,20 +268,23 @@ func sendEsRequest(cluster, namespace string, payload *EsRequest, client k8sclie
 			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
 		}
 
+	case http.MethodDelete:
+		// no more to do to request...
 	default:
 		// unsupported method -- do nothing
 		return
 	}
 
-	request.Header = ensureTokenHeader(request.Header)
+	request.Header = ensureAuthHeader(cluster, namespace, request.Header)
 	// we use the insecure TLS client here because we are providing the SA token.
 	httpClient := getTLSClient(cluster, namespace, client)
 	resp, err := httpClient.Do(request)
 
 	if resp != nil {
 		// TODO: eventually remove after all ES images have been updated to use SA token auth for EO?
-		if resp.StatusCode == http.StatusForbidden ||
-			resp.StatusCode == http.StatusUnauthorized {
+		// The client certificates cannot be presented without TLS, and would hide rejected credentials
+		if scheme == schemeHTTPS && !hasCredentials(cluster, namespace) && (resp.StatusCode == http.StatusForbidden ||
+			resp.StatusCode == http.StatusUnauthorized) {
 			log.Info("failed sending payload using bearer token", "method", payload.Method, "url", payload.URI)
 			// if we get a 401 that means that we couldn't read from the token and provided
 			// no header.
@@ -249,6 +348,8 @@ func sendRequestWithMTlsClient(clusterName, namespace string, payload *EsRequest
 			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(payload.RequestBody)))
 		}
 
+	case http.MethodDelete:
+		// no more to do to request...
 	default:
 		// unsupported method -- do nothing
 		return
@@ -322,10 +423,7 @@ func getTLSClient(clusterName, namespace string, client client.Client) *http.Cli
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false,
-				RootCAs:            getRootCA(clusterName, namespace),
-			},
+			TLSClientConfig:       newTLSConfig(clusterName, namespace),
 		},
 	}
 }
@@ -336,6 +434,9 @@ func getMTlsClient(clusterName, namespace string, client client.Client) *http.Cl
 	// get the contents of the secret
 	extractSecret(clusterName, namespace, client)
 
+	tlsConfig := newTLSConfig(clusterName, namespace)
+	tlsConfig.Certificates = getClientCertificates(clusterName, namespace)
+
 	// http.Transport sourced from go 1.10.7
 	return &http.Client{
 		Transport: &http.Transport{
@@ -349,11 +450,7 @@ func getMTlsClient(clusterName, namespace string, client client.Client) *http.Cl
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false,
-				RootCAs:            getRootCA(clusterName, namespace),
-				Certificates:       getClientCertificates(clusterName, namespace),
-			},
+			TLSClientConfig:       tlsConfig,
 		},
 	}
 }
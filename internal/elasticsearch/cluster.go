@@ -9,6 +9,7 @@ import (
 
 	"github.com/ViaQ/logerr/kverrors"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
 	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 )
 
@@ -156,6 +157,37 @@ func (ec *esClient) SetMinMasterNodes(numberMasters int32) (bool, error) {
 	return payload.StatusCode == 200 && acknowledged, payload.Error
 }
 
+// SetLoggerLevels updates the levels of the loggers of the nodes by their names. A nil level
+// resets a logger to the level of log4j2.properties. The levels are transient to not outlive a
+// full cluster restart with a changed configuration
+func (ec *esClient) SetLoggerLevels(levels map[string]interface{}) error {
+	settings := map[string]interface{}{}
+	for name, level := range levels {
+		settings["logger."+name] = level
+	}
+	body, err := utils.ToJSON(map[string]interface{}{
+		"transient": settings,
+	})
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update logger levels",
+			"levels", levels,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
 func (ec *esClient) GetMinMasterNodes() (int32, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
@@ -272,3 +304,30 @@ func (ec *esClient) IsNodeInCluster(nodeName string) (bool, error) {
 
 	return false, nil
 }
+
+// GetElectedMasterIP returns the IP address of the elected master node
+func (ec *esClient) GetElectedMasterIP() (string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/master?h=ip&format=json",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return "", ec.errorCtx().New("failed to get elected master",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	masters := []struct {
+		IP string `json:"ip"`
+	}{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &masters); err != nil {
+		return "", kverrors.Wrap(err, "failed to parse _cat/master response body")
+	}
+	if len(masters) == 0 {
+		return "", nil
+	}
+	return masters[0].IP, nil
+}
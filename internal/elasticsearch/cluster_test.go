@@ -135,3 +135,23 @@ func TestIsNodeInCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestGetElectedMasterIP(t *testing.T) {
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"_cat/master?h=ip&format=json": {
+			{
+				StatusCode: 200,
+				Body:       `[{"ip": "10.128.2.15"}]`,
+			},
+		},
+	})
+	esClient := helpers.NewFakeElasticsearchClient("elasticsearch", "test-namespace", fakeClient, chatter)
+
+	got, err := esClient.GetElectedMasterIP()
+	if err != nil {
+		t.Errorf("got err: %s", err)
+	}
+	if got != "10.128.2.15" {
+		t.Errorf("got %q, want %q", got, "10.128.2.15")
+	}
+}
@@ -0,0 +1,102 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// CreateOrUpdateComponentTemplate creates or replaces the component template
+func (ec *esClient) CreateOrUpdateComponentTemplate(name string, template *estypes.ComponentTemplate) error {
+	return ec.putTemplate("_component_template", "component template", name, template)
+}
+
+// DeleteComponentTemplate deletes the component template. Missing templates are ignored
+func (ec *esClient) DeleteComponentTemplate(name string) error {
+	return ec.deleteTemplate("_component_template", "component template", name)
+}
+
+// ListComponentTemplates returns the sorted names of the component templates of the cluster
+func (ec *esClient) ListComponentTemplates() ([]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_component_template",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list component templates",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	response := estypes.ComponentTemplatesResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &response); err != nil {
+		return nil, ec.errorCtx().Wrap(err, "failed to decode component templates",
+			"response_body", payload.RawResponseBody)
+	}
+
+	names := []string{}
+	for _, template := range response.ComponentTemplates {
+		names = append(names, template.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateOrUpdateComposableIndexTemplate creates or replaces the composable index template
+func (ec *esClient) CreateOrUpdateComposableIndexTemplate(name string, template *estypes.ComposableIndexTemplate) error {
+	return ec.putTemplate("_index_template", "composable index template", name, template)
+}
+
+// DeleteComposableIndexTemplate deletes the composable index template. Missing templates are ignored
+func (ec *esClient) DeleteComposableIndexTemplate(name string) error {
+	return ec.deleteTemplate("_index_template", "composable index template", name)
+}
+
+func (ec *esClient) putTemplate(endpoint, kind, name string, template interface{}) error {
+	body, err := utils.ToJSON(template)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/%s", endpoint, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New(fmt.Sprintf("failed to create %s", kind),
+			"template", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+func (ec *esClient) deleteTemplate(endpoint, kind, name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("%s/%s", endpoint, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && (payload.StatusCode == http.StatusNotFound || payload.StatusCode < 300) {
+		return nil
+	}
+
+	return ec.errorCtx().New(fmt.Sprintf("failed to delete %s", kind),
+		"template", name,
+		ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+		"response_error", payload.Error,
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody)
+}
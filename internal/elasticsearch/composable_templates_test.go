@@ -0,0 +1,48 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestListComponentTemplates(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_component_template": {
+				{
+					StatusCode: http.StatusOK,
+					Body:       `{"component_templates": [{"name": "logs-settings", "component_template": {}}, {"name": "logs-mappings", "component_template": {}}]}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	names, err := esClient.ListComponentTemplates()
+	if err != nil {
+		t.Fatalf("Exp. no error but got %v", err)
+	}
+	exp := []string{"logs-mappings", "logs-settings"}
+	if !reflect.DeepEqual(names, exp) {
+		t.Errorf("Exp. the component templates %v but got %v", exp, names)
+	}
+}
+
+func TestDeleteComposableIndexTemplateWhenMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_index_template/foo": {
+				{
+					StatusCode: http.StatusNotFound,
+					Body:       `{"error": {"type": "resource_not_found_exception"}}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.DeleteComposableIndexTemplate("foo"); err != nil {
+		t.Errorf("Exp. a missing template to be ignored but got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package elasticsearch
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var (
+	credentials      = map[string]string{}
+	credentialsMutex sync.RWMutex
+)
+
+// SetCredentials sets the value of the Authorization header the clients of the cluster send
+// instead of the SA token. Requests rejected with these credentials are not retried with the
+// client certificate of the operator
+func SetCredentials(cluster, namespace, authorization string) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+
+	credentials[clusterKey(cluster, namespace)] = authorization
+}
+
+// ForgetCredentials removes the credentials of the cluster, so its clients authenticate with the
+// SA token again
+func ForgetCredentials(cluster, namespace string) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+
+	delete(credentials, clusterKey(cluster, namespace))
+}
+
+// BasicAuthorization returns the Authorization header value of a username and password
+func BasicAuthorization(username, password string) string {
+	return fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+}
+
+// APIKeyAuthorization returns the Authorization header value of a base64 encoded id:api_key
+func APIKeyAuthorization(encoded string) string {
+	return fmt.Sprintf("ApiKey %s", encoded)
+}
+
+func hasCredentials(cluster, namespace string) bool {
+	credentialsMutex.RLock()
+	defer credentialsMutex.RUnlock()
+
+	_, ok := credentials[clusterKey(cluster, namespace)]
+	return ok
+}
+
+// ensureAuthHeader sets the credentials of the cluster or the SA token as Authorization header
+func ensureAuthHeader(cluster, namespace string, header http.Header) http.Header {
+	credentialsMutex.RLock()
+	authorization, ok := credentials[clusterKey(cluster, namespace)]
+	credentialsMutex.RUnlock()
+
+	if !ok {
+		return ensureTokenHeader(header)
+	}
+
+	if header == nil {
+		header = map[string][]string{}
+	}
+	header.Set("Authorization", authorization)
+	return header
+}
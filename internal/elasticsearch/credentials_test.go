@@ -0,0 +1,40 @@
+package elasticsearch
+
+import (
+	"testing"
+)
+
+func TestBasicAuthorization(t *testing.T) {
+	exp := "Basic ZWxhc3RpYzpjaGFuZ2VtZQ=="
+	if authorization := BasicAuthorization("elastic", "changeme"); authorization != exp {
+		t.Errorf("Exp. the authorization %q but got %q", exp, authorization)
+	}
+}
+
+func TestEnsureAuthHeaderWithCredentials(t *testing.T) {
+	defer ForgetCredentials("elasticsearch", "openshift-logging")
+
+	SetCredentials("elasticsearch", "openshift-logging", APIKeyAuthorization("VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="))
+	header := ensureAuthHeader("elasticsearch", "openshift-logging", nil)
+
+	exp := "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="
+	if authorization := header.Get("Authorization"); authorization != exp {
+		t.Errorf("Exp. the authorization %q but got %q", exp, authorization)
+	}
+	if !hasCredentials("elasticsearch", "openshift-logging") {
+		t.Error("Exp. the cluster to have credentials")
+	}
+	if hasCredentials("other", "openshift-logging") {
+		t.Error("Exp. other clusters to keep authenticating with the SA token")
+	}
+}
+
+func TestForgetCredentials(t *testing.T) {
+	SetCredentials("elasticsearch", "openshift-logging", BasicAuthorization("elastic", "changeme"))
+	ForgetCredentials("elasticsearch", "openshift-logging")
+
+	header := ensureAuthHeader("elasticsearch", "openshift-logging", nil)
+	if authorization := header.Get("Authorization"); authorization == BasicAuthorization("elastic", "changeme") {
+		t.Error("Exp. the forgotten credentials to not be sent")
+	}
+}
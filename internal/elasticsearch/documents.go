@@ -0,0 +1,140 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// CountDocuments returns the number of documents of the index after refreshing it
+func (ec *esClient) CountDocuments(index string) (int64, error) {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_refresh", index),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return 0, ec.errorCtx().New("failed to refresh index",
+			"index", index,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	payload = &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_count", index),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return 0, ec.errorCtx().New("failed to count documents",
+			"index", index,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	return int64(parseFloat64("count", payload.ResponseBody)), nil
+}
+
+// SampleDocuments returns up to size random documents of the index
+func (ec *esClient) SampleDocuments(index string, size int32) ([]estypes.Document, error) {
+	body, err := utils.ToJSON(map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"function_score": map[string]interface{}{
+				"random_score": map[string]interface{}{},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_search", index),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to sample documents",
+			"index", index,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	response := &estypes.SearchResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.SearchResponse`",
+			"index", index)
+	}
+	return response.Hits.Hits, nil
+}
+
+// GetDocuments returns the documents of the index with the given IDs. Missing documents are
+// returned as not found
+func (ec *esClient) GetDocuments(index string, ids []string) ([]estypes.Document, error) {
+	body, err := utils.ToJSON(map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_mget", index),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get documents",
+			"index", index,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	response := &estypes.MultiGetResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.MultiGetResponse`",
+			"index", index)
+	}
+	return response.Docs, nil
+}
+
+// SearchOnNodes runs the search against the shards of the indices matching the pattern which are
+// allocated to the nodes. The names of the nodes may contain wildcards
+func (ec *esClient) SearchOnNodes(pattern string, nodes []string, body string) error {
+	if body == "" {
+		body = `{"size": 0, "query": {"match_all": {}}}`
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_search?preference=_only_nodes:%s&ignore_unavailable=true&allow_no_indices=true", pattern, strings.Join(nodes, ",")),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to search on nodes",
+			"pattern", pattern,
+			"nodes", nodes,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+	return nil
+}
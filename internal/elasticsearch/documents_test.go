@@ -0,0 +1,50 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestCountDocumentsRefreshesTheIndex(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app/_refresh": {
+				{StatusCode: http.StatusOK, Body: `{}`},
+			},
+			"app/_count": {
+				{StatusCode: http.StatusOK, Body: `{"count": 42}`},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	count, err := esClient.CountDocuments("app")
+	if err != nil {
+		t.Fatalf("Exp. no error but got %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Exp. 42 documents but got %d", count)
+	}
+	if _, found := chatter.GetRequest("app/_refresh"); !found {
+		t.Error("Exp. the index to be refreshed")
+	}
+}
+
+func TestGetDocumentsWithMissingDocument(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app/_mget": {
+				{StatusCode: http.StatusOK, Body: `{"docs": [{"_id": "1", "found": true, "_source": {"a": 1}}, {"_id": "2", "found": false}]}`},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	docs, err := esClient.GetDocuments("app", []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("Exp. no error but got %v", err)
+	}
+	if len(docs) != 2 || !*docs[0].Found || *docs[1].Found {
+		t.Errorf("Exp. the first document to be found and the second missing but got %v", docs)
+	}
+}
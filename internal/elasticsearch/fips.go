@@ -0,0 +1,68 @@
+package elasticsearch
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+var (
+	fipsModes      = map[string]bool{}
+	fipsModesMutex sync.RWMutex
+
+	// fipsCipherSuites are the FIPS-approved cipher suites of TLS 1.2 also enabled on the nodes
+	fipsCipherSuites = []uint16{
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	}
+
+	// fipsCurves are the FIPS-approved curves of the key exchange
+	fipsCurves = []tls.CurveID{
+		tls.CurveP256,
+		tls.CurveP384,
+		tls.CurveP521,
+	}
+)
+
+// SetFIPSMode sets whether the clients of the cluster are restricted to FIPS-approved TLS
+func SetFIPSMode(cluster, namespace string, enabled bool) {
+	fipsModesMutex.Lock()
+	defer fipsModesMutex.Unlock()
+
+	key := clusterKey(cluster, namespace)
+	if enabled {
+		fipsModes[key] = true
+		return
+	}
+	delete(fipsModes, key)
+}
+
+// ForgetFIPSMode removes the FIPS mode of a deleted cluster
+func ForgetFIPSMode(cluster, namespace string) {
+	SetFIPSMode(cluster, namespace, false)
+}
+
+func fipsModeEnabled(cluster, namespace string) bool {
+	fipsModesMutex.RLock()
+	defer fipsModesMutex.RUnlock()
+
+	return fipsModes[clusterKey(cluster, namespace)]
+}
+
+// newTLSConfig returns the TLS configuration of the clients of the cluster verifying the nodes
+// with the CA of the cluster
+func newTLSConfig(clusterName, namespace string) *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: false,
+		RootCAs:            getRootCA(clusterName, namespace),
+	}
+
+	if fipsModeEnabled(clusterName, namespace) {
+		config.MinVersion = tls.VersionTLS12
+		config.MaxVersion = tls.VersionTLS12
+		config.CipherSuites = fipsCipherSuites
+		config.CurvePreferences = fipsCurves
+	}
+	return config
+}
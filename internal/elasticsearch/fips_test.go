@@ -0,0 +1,35 @@
+package elasticsearch
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+func TestTLSConfigWithoutFIPSMode(t *testing.T) {
+	config := newTLSConfig("elasticsearch", "openshift-logging")
+	if config.MinVersion != 0 || config.CipherSuites != nil {
+		t.Errorf("Exp. the default versions and cipher suites but got %v and %v", config.MinVersion, config.CipherSuites)
+	}
+}
+
+func TestTLSConfigInFIPSMode(t *testing.T) {
+	defer ForgetFIPSMode("elasticsearch", "openshift-logging")
+
+	SetFIPSMode("elasticsearch", "openshift-logging", true)
+	config := newTLSConfig("elasticsearch", "openshift-logging")
+	if config.MinVersion != tls.VersionTLS12 || config.MaxVersion != tls.VersionTLS12 {
+		t.Errorf("Exp. TLS 1.2 only but got the versions %v to %v", config.MinVersion, config.MaxVersion)
+	}
+	if !reflect.DeepEqual(config.CipherSuites, fipsCipherSuites) {
+		t.Errorf("Exp. the cipher suites %v but got %v", fipsCipherSuites, config.CipherSuites)
+	}
+	if other := newTLSConfig("other", "openshift-logging"); other.CipherSuites != nil {
+		t.Errorf("Exp. other clusters to keep the default cipher suites but got %v", other.CipherSuites)
+	}
+
+	SetFIPSMode("elasticsearch", "openshift-logging", false)
+	if config := newTLSConfig("elasticsearch", "openshift-logging"); config.CipherSuites != nil {
+		t.Errorf("Exp. the default cipher suites once FIPS mode is disabled but got %v", config.CipherSuites)
+	}
+}
@@ -1,20 +1,13 @@
 package elasticsearch
 
 import (
-	"net/http"
-
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 )
 
 func (ec *esClient) GetClusterHealth() (api.ClusterHealth, error) {
 	clusterHealth := api.ClusterHealth{}
 
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/health",
-	}
-
-	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	payload := ec.clusterHealth()
 
 	if payload.Error != nil {
 		return clusterHealth, payload.Error
@@ -34,12 +27,7 @@ func (ec *esClient) GetClusterHealth() (api.ClusterHealth, error) {
 }
 
 func (ec *esClient) GetClusterHealthStatus() (string, error) {
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/health",
-	}
-
-	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	payload := ec.clusterHealth()
 
 	status := ""
 	if payload.ResponseBody["status"] != nil {
@@ -52,12 +40,7 @@ func (ec *esClient) GetClusterHealthStatus() (string, error) {
 }
 
 func (ec *esClient) GetClusterNodeCount() (int32, error) {
-	payload := &EsRequest{
-		Method: http.MethodGet,
-		URI:    "_cluster/health",
-	}
-
-	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	payload := ec.clusterHealth()
 
 	nodeCount := int32(0)
 	if nodeCountFloat, ok := payload.ResponseBody["number_of_nodes"].(float64); ok {
@@ -0,0 +1,129 @@
+package elasticsearch
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ViaQ/logerr/log"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultHealthPollInterval = 10 * time.Second
+
+var (
+	healthPollers      = map[string]*healthPoller{}
+	healthPollersMutex sync.Mutex
+)
+
+// healthPoller fetches the health of a cluster in the background and shares the latest
+// response with all clients of the cluster
+type healthPoller struct {
+	interval time.Duration
+	stop     chan struct{}
+
+	mutex     sync.RWMutex
+	payload   *EsRequest
+	fetchedAt time.Time
+}
+
+// healthPollInterval returns the interval the health of the clusters is fetched at,
+// configured with the HEALTH_POLL_INTERVAL environment variable
+func healthPollInterval() time.Duration {
+	value := utils.LookupEnvWithDefault("HEALTH_POLL_INTERVAL", defaultHealthPollInterval.String())
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Info("Invalid health poll interval, using the default", "interval", value)
+		return defaultHealthPollInterval
+	}
+	return interval
+}
+
+// WatchClusterHealth starts fetching the health of the cluster in the background unless it
+// is already watched
+func WatchClusterHealth(cluster, namespace string, k8sClient k8sclient.Client) {
+	healthPollersMutex.Lock()
+	defer healthPollersMutex.Unlock()
+
+	key := clusterKey(cluster, namespace)
+	if _, ok := healthPollers[key]; ok {
+		return
+	}
+
+	poller := &healthPoller{
+		interval: healthPollInterval(),
+		stop:     make(chan struct{}),
+	}
+	healthPollers[key] = poller
+
+	go wait.Until(func() {
+		payload := &EsRequest{
+			Method: http.MethodGet,
+			URI:    "_cluster/health",
+		}
+		sendEsRequest(cluster, namespace, payload, k8sClient)
+		poller.update(payload)
+	}, poller.interval, poller.stop)
+}
+
+// StopWatchingClusterHealth stops fetching the health of a deleted cluster
+func StopWatchingClusterHealth(cluster, namespace string) {
+	healthPollersMutex.Lock()
+	defer healthPollersMutex.Unlock()
+
+	key := clusterKey(cluster, namespace)
+	if poller, ok := healthPollers[key]; ok {
+		close(poller.stop)
+		delete(healthPollers, key)
+	}
+}
+
+func getHealthPoller(cluster, namespace string) *healthPoller {
+	healthPollersMutex.Lock()
+	defer healthPollersMutex.Unlock()
+
+	return healthPollers[clusterKey(cluster, namespace)]
+}
+
+func (p *healthPoller) update(payload *EsRequest) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.payload = payload
+	p.fetchedAt = time.Now()
+}
+
+// latest returns the last response or nil if it is older than the poll interval,
+// e.g. while a request of the poller hangs
+func (p *healthPoller) latest() *EsRequest {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.payload == nil || time.Since(p.fetchedAt) > p.interval {
+		return nil
+	}
+	return p.payload
+}
+
+// clusterHealth returns the response of the health API shared by the poller of the cluster
+// or requests it if the poller has no fresh response
+func (ec *esClient) clusterHealth() *EsRequest {
+	if poller := getHealthPoller(ec.cluster, ec.namespace); poller != nil {
+		if payload := poller.latest(); payload != nil {
+			return payload
+		}
+	}
+
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cluster/health",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+
+	if poller := getHealthPoller(ec.cluster, ec.namespace); poller != nil {
+		poller.update(payload)
+	}
+	return payload
+}
@@ -0,0 +1,73 @@
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClusterHealthSharesPolledResponse(t *testing.T) {
+	requests := 0
+	ec := &esClient{
+		cluster:   "elasticsearch",
+		namespace: "openshift-logging",
+		fnSendEsRequest: func(cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
+			requests++
+			payload.StatusCode = 200
+			payload.ResponseBody = map[string]interface{}{"status": "yellow", "number_of_nodes": float64(2)}
+		},
+	}
+
+	poller := &healthPoller{interval: time.Minute, stop: make(chan struct{})}
+	healthPollers[clusterKey(ec.cluster, ec.namespace)] = poller
+	defer StopWatchingClusterHealth(ec.cluster, ec.namespace)
+
+	poller.update(&EsRequest{
+		StatusCode:   200,
+		ResponseBody: map[string]interface{}{"status": "green", "number_of_nodes": float64(3)},
+	})
+
+	status, err := ec.GetClusterHealthStatus()
+	if err != nil || status != "green" {
+		t.Errorf("Exp. the polled status green but got %q (%v)", status, err)
+	}
+	count, err := ec.GetClusterNodeCount()
+	if err != nil || count != 3 {
+		t.Errorf("Exp. the polled node count 3 but got %d (%v)", count, err)
+	}
+	if requests != 0 {
+		t.Errorf("Exp. no requests while the polled health is fresh but got %d", requests)
+	}
+
+	// a stale response is requested again and shared with the other clients
+	poller.fetchedAt = time.Now().Add(-2 * time.Minute)
+	status, _ = ec.GetClusterHealthStatus()
+	if status != "yellow" || requests != 1 {
+		t.Errorf("Exp. the stale status to be requested again but got %q after %d requests", status, requests)
+	}
+	if payload := poller.latest(); payload == nil || payload.ResponseBody["status"] != "yellow" {
+		t.Errorf("Exp. the requested status to be shared by the poller")
+	}
+}
+
+func TestClusterHealthWithoutPoller(t *testing.T) {
+	requests := 0
+	ec := &esClient{
+		cluster:   "unwatched",
+		namespace: "openshift-logging",
+		fnSendEsRequest: func(cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
+			requests++
+			payload.ResponseBody = map[string]interface{}{"status": "red"}
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		if status, _ := ec.GetClusterHealthStatus(); status != "red" {
+			t.Errorf("Exp. the status red but got %q", status)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Exp. every health check of an unwatched cluster to be requested but got %d requests", requests)
+	}
+}
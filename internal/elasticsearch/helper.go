@@ -38,6 +38,17 @@ func parseFloat64(path string, interfaceMap map[string]interface{}) float64 {
 	}
 }
 
+// parseErrorReason extracts the root cause reason from an Elasticsearch error response
+func parseErrorReason(body map[string]interface{}) string {
+	esError, ok := body["error"].(map[string]interface{})
+	if !ok {
+		reason, _ := body["error"].(string)
+		return reason
+	}
+	reason, _ := esError["reason"].(string)
+	return reason
+}
+
 func walkInterfaceMap(path string, interfaceMap map[string]interface{}) interface{} {
 	current := interfaceMap
 	keys := strings.Split(path, ".")
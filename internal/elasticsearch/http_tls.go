@@ -0,0 +1,86 @@
+package elasticsearch
+
+import (
+	"sync"
+)
+
+const (
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+
+	// proxyPort is the port of the proxy of the pods authenticating the SA token
+	proxyPort = "60000"
+)
+
+var (
+	httpTLSSettings      = map[string]*httpTLSSetting{}
+	httpTLSSettingsMutex sync.RWMutex
+)
+
+// httpTLSSetting is the TLS of the REST API of a cluster
+type httpTLSSetting struct {
+	enabled bool
+
+	// switched is set once TLS is enabled or disabled for the running cluster. The nodes serve
+	// the previous scheme until they are restarted
+	switched bool
+}
+
+// SetHTTPTLS sets whether the REST API of the cluster is served with TLS. The clients of the
+// cluster send their requests with the scheme of the API
+func SetHTTPTLS(cluster, namespace string, enabled bool) {
+	httpTLSSettingsMutex.Lock()
+	defer httpTLSSettingsMutex.Unlock()
+
+	key := clusterKey(cluster, namespace)
+	setting, ok := httpTLSSettings[key]
+	if !ok {
+		if !enabled {
+			httpTLSSettings[key] = &httpTLSSetting{enabled: enabled}
+		}
+		return
+	}
+	if setting.enabled != enabled {
+		setting.enabled = enabled
+		setting.switched = true
+	}
+}
+
+// ForgetHTTPTLS removes the TLS setting of a deleted cluster
+func ForgetHTTPTLS(cluster, namespace string) {
+	httpTLSSettingsMutex.Lock()
+	defer httpTLSSettingsMutex.Unlock()
+
+	delete(httpTLSSettings, clusterKey(cluster, namespace))
+}
+
+// httpScheme returns the scheme of the REST API of the cluster
+func httpScheme(cluster, namespace string) string {
+	httpTLSSettingsMutex.RLock()
+	defer httpTLSSettingsMutex.RUnlock()
+
+	if setting, ok := httpTLSSettings[clusterKey(cluster, namespace)]; ok && !setting.enabled {
+		return schemeHTTP
+	}
+	return schemeHTTPS
+}
+
+// alternateHTTPScheme returns the scheme the nodes of the cluster may still serve while they are
+// restarted for enabling or disabling TLS, or an empty string if the cluster uses one scheme
+func alternateHTTPScheme(cluster, namespace string) string {
+	httpTLSSettingsMutex.RLock()
+	defer httpTLSSettingsMutex.RUnlock()
+
+	setting, ok := httpTLSSettings[clusterKey(cluster, namespace)]
+	if !ok {
+		return ""
+	}
+	// the operator may have been restarted since TLS was disabled
+	if !setting.enabled {
+		return schemeHTTPS
+	}
+	if setting.switched {
+		return schemeHTTP
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package elasticsearch
+
+import (
+	"testing"
+)
+
+func TestHTTPSchemeDefaultsToTLS(t *testing.T) {
+	defer ForgetHTTPTLS("elasticsearch", "openshift-logging")
+
+	SetHTTPTLS("elasticsearch", "openshift-logging", true)
+	if scheme := httpScheme("elasticsearch", "openshift-logging"); scheme != schemeHTTPS {
+		t.Errorf("Exp. the scheme %q but got %q", schemeHTTPS, scheme)
+	}
+	if alternate := alternateHTTPScheme("elasticsearch", "openshift-logging"); alternate != "" {
+		t.Errorf("Exp. no alternate scheme but got %q", alternate)
+	}
+}
+
+func TestHTTPSchemeWithoutTLS(t *testing.T) {
+	defer ForgetHTTPTLS("elasticsearch", "openshift-logging")
+
+	SetHTTPTLS("elasticsearch", "openshift-logging", false)
+	if scheme := httpScheme("elasticsearch", "openshift-logging"); scheme != schemeHTTP {
+		t.Errorf("Exp. the scheme %q but got %q", schemeHTTP, scheme)
+	}
+	// the nodes may still serve TLS until they are restarted
+	if alternate := alternateHTTPScheme("elasticsearch", "openshift-logging"); alternate != schemeHTTPS {
+		t.Errorf("Exp. the alternate scheme %q but got %q", schemeHTTPS, alternate)
+	}
+	if scheme := httpScheme("other", "openshift-logging"); scheme != schemeHTTPS {
+		t.Errorf("Exp. other clusters to keep the scheme %q but got %q", schemeHTTPS, scheme)
+	}
+}
+
+func TestHTTPSchemeReenablingTLS(t *testing.T) {
+	defer ForgetHTTPTLS("elasticsearch", "openshift-logging")
+
+	SetHTTPTLS("elasticsearch", "openshift-logging", false)
+	SetHTTPTLS("elasticsearch", "openshift-logging", true)
+	if scheme := httpScheme("elasticsearch", "openshift-logging"); scheme != schemeHTTPS {
+		t.Errorf("Exp. the scheme %q but got %q", schemeHTTPS, scheme)
+	}
+	// the nodes may still serve plain HTTP until they are restarted
+	if alternate := alternateHTTPScheme("elasticsearch", "openshift-logging"); alternate != schemeHTTP {
+		t.Errorf("Exp. the alternate scheme %q but got %q", schemeHTTP, alternate)
+	}
+}
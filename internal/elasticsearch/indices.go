@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func (ec *esClient) GetIndex(name string) (*estypes.Index, error) {
@@ -223,6 +225,75 @@ func (ec *esClient) ListIndicesForAlias(aliasPattern string) ([]string, error) {
 	return response, nil
 }
 
+// GetWriteIndex returns the index written to through the alias or an empty string if the alias does not exist
+func (ec *esClient) GetWriteIndex(alias string) (string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_alias/%s", alias),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return "", ec.errorCtx().New("failed to get write index of alias",
+			"alias", alias,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	indices := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &indices); err != nil {
+		return "", kverrors.Wrap(err, "failed decoding raw response body into aliases",
+			"alias", alias)
+	}
+
+	for index, value := range indices {
+		isWriteIndex := value.Aliases[alias].IsWriteIndex
+		// an alias pointing to a single index writes to it unless is_write_index is false
+		if (isWriteIndex == nil && len(indices) == 1) || (isWriteIndex != nil && *isWriteIndex) {
+			return index, nil
+		}
+	}
+	return "", nil
+}
+
+// RolloverAlias rolls the write index of the alias over to a new index if one of the conditions is met
+func (ec *esClient) RolloverAlias(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error) {
+	body, err := utils.ToJSON(estypes.Rollover{Conditions: conditions})
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_rollover", alias),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to rollover alias",
+			"alias", alias,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	response := &estypes.RolloverResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.RolloverResponse`",
+			"alias", alias)
+	}
+	return response, nil
+}
+
 func (ec *esClient) AddAliasForOldIndices() bool {
 	// get .operations.*/_alias
 	// get project.*/_alias
@@ -319,3 +390,381 @@ func (ec *esClient) AddAliasForOldIndices() bool {
 
 	return successful
 }
+
+// GetIndexDefinition returns the flat settings, typeless mappings and aliases of the index
+// or nil if the index does not exist
+func (ec *esClient) GetIndexDefinition(name string) (*estypes.IndexDefinition, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s?flat_settings=true&include_type_name=false", name),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	indices := map[string]*estypes.IndexDefinition{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &indices); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.IndexDefinition`",
+			"index", name)
+	}
+	if index, ok := indices[name]; ok {
+		return index, nil
+	}
+	return nil, ec.errorCtx().New("index name resolves to a different index",
+		"index", name,
+		"response_body", payload.ResponseBody)
+}
+
+func (ec *esClient) CreateIndexDefinition(name string, index *estypes.IndexDefinition) error {
+	body, err := utils.ToJSON(index)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s?include_type_name=false", name),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to create index",
+			"index", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// PutIndexSettings updates the given flat settings of an existing index
+func (ec *esClient) PutIndexSettings(name string, settings map[string]string) error {
+	body, err := utils.ToJSON(settings)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_settings", name),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to update index settings",
+			"index", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// PutIndexMappings adds the typeless mappings to an existing index
+func (ec *esClient) PutIndexMappings(name string, mappings map[string]interface{}) error {
+	body, err := utils.ToJSON(mappings)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_mapping?include_type_name=false", name),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to update index mappings",
+			"index", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ListIndicesCreationDate returns the status and creation date of the indices matching the pattern
+func (ec *esClient) ListIndicesCreationDate(pattern string) (estypes.CatIndicesResponses, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_cat/indices/%s?h=index,status,creation.date&format=json", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list indices",
+			"pattern", pattern,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.CatIndicesResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/indices response body",
+			"pattern", pattern)
+	}
+	return res, nil
+}
+
+// ListWriteIndices returns the indices written to through an alias
+func (ec *esClient) ListWriteIndices() (sets.String, error) {
+	aliases, err := ec.ListWriteAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	writeIndices := sets.NewString()
+	for _, index := range aliases {
+		writeIndices.Insert(index)
+	}
+	return writeIndices, nil
+}
+
+// ListWriteAliases returns the write index of every alias written to
+func (ec *esClient) ListWriteAliases() (map[string]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_alias",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list aliases",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	indices := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &indices); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into aliases")
+	}
+
+	aliasIndexCount := map[string]int{}
+	for _, value := range indices {
+		for alias := range value.Aliases {
+			aliasIndexCount[alias]++
+		}
+	}
+
+	writeAliases := map[string]string{}
+	for index, value := range indices {
+		for alias, settings := range value.Aliases {
+			// an alias pointing to a single index writes to it unless is_write_index is false
+			if (settings.IsWriteIndex == nil && aliasIndexCount[alias] == 1) || (settings.IsWriteIndex != nil && *settings.IsWriteIndex) {
+				writeAliases[alias] = index
+			}
+		}
+	}
+	return writeAliases, nil
+}
+
+// CloseIndex closes the index
+func (ec *esClient) CloseIndex(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_close", name),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to close index",
+			"index", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// DeleteIndex deletes the index
+func (ec *esClient) DeleteIndex(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    name,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to delete index",
+			"index", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ReloadSearchAnalyzers reloads the search analyzers of the indices matching the pattern to load
+// the changed files of their updateable token filters
+func (ec *esClient) ReloadSearchAnalyzers(pattern string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_reload_search_analyzers", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to reload search analyzers",
+			"pattern", pattern,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	if failed := parseFloat64("_shards.failed", payload.ResponseBody); failed > 0 {
+		return ec.errorCtx().New("failed to reload search analyzers of shards",
+			"pattern", pattern,
+			"failed", failed,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetIndexingTotals returns the number of documents indexed into the primary shards of the
+// GetIndicesStoreSize returns the size in bytes of the indices matching the pattern including
+// their replicas
+func (ec *esClient) GetIndicesStoreSize(pattern string) (map[string]int64, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_cat/indices/%s?h=index,store.size&bytes=b&format=json", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return map[string]int64{}, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get the store size of indices",
+			"pattern", pattern,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	res := estypes.CatIndicesResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &res); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/indices response body",
+			"pattern", pattern)
+	}
+
+	sizes := map[string]int64{}
+	for _, index := range res {
+		// closed indices have no store size
+		size, err := strconv.ParseInt(index.StoreSize, 10, 64)
+		if err != nil {
+			continue
+		}
+		sizes[index.Index] = size
+	}
+	return sizes, nil
+}
+
+// indices matching the pattern keyed by index
+func (ec *esClient) GetIndexingTotals(pattern string) (map[string]int64, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_stats/indexing?filter_path=indices.*.primaries.indexing.index_total", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get indexing stats",
+			"pattern", pattern,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	totals := map[string]int64{}
+	if indices, ok := payload.ResponseBody["indices"].(map[string]interface{}); ok {
+		for index, stats := range indices {
+			stats, ok := stats.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if total := parseFloat64("primaries.indexing.index_total", stats); total >= 0 {
+				totals[index] = int64(total)
+			}
+		}
+	}
+	return totals, nil
+}
+
+// GetRefreshIntervals returns the refresh interval set on the indices matching the pattern keyed
+// by index. Indices using the default refresh interval map to an empty string
+func (ec *esClient) GetRefreshIntervals(pattern string) (map[string]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_settings/index.refresh_interval", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get refresh intervals",
+			"pattern", pattern,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	intervals := map[string]string{}
+	for index, settings := range payload.ResponseBody {
+		if settings, ok := settings.(map[string]interface{}); ok {
+			intervals[index] = parseString("settings.index.refresh_interval", settings)
+		}
+	}
+	return intervals, nil
+}
+
+// SetRefreshInterval sets the refresh interval of the index or resets it to the default if the
+// interval is empty. Deleted indices are ignored
+func (ec *esClient) SetRefreshInterval(index, interval string) error {
+	var value interface{}
+	if interval != "" {
+		value = interval
+	}
+	body, err := utils.ToJSON(map[string]interface{}{
+		"index.refresh_interval": value,
+	})
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_settings", index),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && payload.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update refresh interval",
+			"index", index,
+			"interval", interval,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+const (
+	// ErrorReasonKey is the error key holding the reason Elasticsearch returned for a rejected request
+	ErrorReasonKey = "error_reason"
+)
+
+func (ec *esClient) CreateOrUpdateLifecyclePolicy(name string, policy *estypes.LifecyclePolicy) error {
+	body, err := utils.ToJSON(policy)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_ilm/policy/%s", name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to create or update lifecycle policy",
+			"policy", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+func (ec *esClient) DeleteLifecyclePolicy(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("_ilm/policy/%s", name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && (payload.StatusCode == http.StatusNotFound || payload.StatusCode < 300) {
+		return nil
+	}
+
+	return ec.errorCtx().New("failed to delete lifecycle policy",
+		"policy", name,
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody,
+		"response_error", payload.Error)
+}
+
+// SetTemplateLifecyclePolicy updates the settings of an existing index template
+// to reference the lifecycle policy and rollover alias
+func (ec *esClient) SetTemplateLifecyclePolicy(template, policy, rolloverAlias string) error {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_template/%s", template),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to get index template",
+			"template", template,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	templates := map[string]map[string]interface{}{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &templates); err != nil {
+		return kverrors.Wrap(err, "failed decoding raw response body into index template",
+			"template", template)
+	}
+
+	current, ok := templates[template]
+	if !ok {
+		return ec.errorCtx().New("index template not found",
+			"template", template)
+	}
+
+	settings, ok := current["settings"].(map[string]interface{})
+	if !ok {
+		settings = map[string]interface{}{}
+	}
+	if getTemplateLifecycleSetting(settings, "name") == policy &&
+		getTemplateLifecycleSetting(settings, "rollover_alias") == rolloverAlias {
+		return nil
+	}
+
+	index, ok := settings["index"].(map[string]interface{})
+	if !ok {
+		index = map[string]interface{}{}
+	}
+	index["lifecycle"] = map[string]interface{}{
+		"name":           policy,
+		"rollover_alias": rolloverAlias,
+	}
+	settings["index"] = index
+	current["settings"] = settings
+
+	body, err := utils.ToJSON(current)
+	if err != nil {
+		return err
+	}
+	payload = &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_template/%s", template),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to attach lifecycle policy to index template",
+			"template", template,
+			"policy", policy,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+	return nil
+}
+
+func getTemplateLifecycleSetting(settings map[string]interface{}, key string) string {
+	index, _ := settings["index"].(map[string]interface{})
+	lifecycle, _ := index["lifecycle"].(map[string]interface{})
+	value, _ := lifecycle[key].(string)
+	return value
+}
+
+// IsLifecycleManagementAvailable returns true if the cluster serves the lifecycle management API
+func (ec *esClient) IsLifecycleManagementAvailable() (bool, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_ilm/status",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return false, payload.Error
+	}
+	switch payload.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusBadRequest, http.StatusNotFound:
+		return false, nil
+	}
+	return false, ec.errorCtx().New("failed to get lifecycle management status",
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody)
+}
@@ -0,0 +1,123 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var lifecyclePolicy = &estypes.LifecyclePolicy{
+	Policy: estypes.LifecyclePolicyBody{
+		Phases: map[string]estypes.LifecyclePhase{
+			"delete": {MinAge: "7d", Actions: map[string]interface{}{"delete": map[string]interface{}{}}},
+		},
+	},
+}
+
+func TestCreateOrUpdateLifecyclePolicyWhenRejected(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ilm/policy/foo": {
+				{
+					StatusCode: http.StatusBadRequest,
+					Body:       `{"error": {"type": "x_content_parse_exception", "reason": "unknown field [foo]"}}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	err := esClient.CreateOrUpdateLifecyclePolicy("foo", lifecyclePolicy)
+	if err == nil {
+		t.Fatal("Exp. to return an error but did not")
+	}
+	if reason := kverrors.KVs(err)[elasticsearch.ErrorReasonKey]; reason != "unknown field [foo]" {
+		t.Errorf("Exp. the Elasticsearch error reason to be returned, got %q", reason)
+	}
+}
+
+func TestCreateOrUpdateLifecyclePolicyWhenResponse200(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ilm/policy/foo": {
+				{
+					StatusCode: http.StatusOK,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.CreateOrUpdateLifecyclePolicy("foo", lifecyclePolicy); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+}
+
+func TestDeleteLifecyclePolicyWhenNotFound(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ilm/policy/foo": {
+				{
+					StatusCode: http.StatusNotFound,
+					Body:       `{}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.DeleteLifecyclePolicy("foo"); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+}
+
+func TestSetTemplateLifecyclePolicyWhenAlreadySet(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template/ocp-gen-app": {
+				{
+					StatusCode: http.StatusOK,
+					Body:       `{"ocp-gen-app": {"settings": {"index": {"lifecycle": {"name": "foo", "rollover_alias": "app-write"}}}}}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.SetTemplateLifecyclePolicy("ocp-gen-app", "foo", "app-write"); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+	if requests := chatter.Requests["_template/ocp-gen-app"]; len(requests) != 1 {
+		t.Errorf("Exp. the template to not be updated, got %d requests", len(requests))
+	}
+}
+
+func TestSetTemplateLifecyclePolicyWhenNotSet(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template/ocp-gen-app": {
+				{
+					StatusCode: http.StatusOK,
+					Body:       `{"ocp-gen-app": {"index_patterns": ["app*"], "settings": {"index": {"number_of_shards": "3"}}}}`,
+				},
+				{
+					StatusCode: http.StatusOK,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.SetTemplateLifecyclePolicy("ocp-gen-app", "foo", "app-write"); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+	requests := chatter.Requests["_template/ocp-gen-app"]
+	if len(requests) != 2 || requests[1].Method != http.MethodPut {
+		t.Fatalf("Exp. the template to be updated, got %v", requests)
+	}
+	exp := `{"index_patterns":["app*"],"settings":{"index":{"lifecycle":{"name":"foo","rollover_alias":"app-write"},"number_of_shards":"3"}}}`
+	if requests[1].Body != exp {
+		t.Errorf("Exp. body %s, got %s", exp, requests[1].Body)
+	}
+}
@@ -0,0 +1,62 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"net/http"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// GetClusterMetadata returns the index templates, aliases, lifecycle policies and persistent
+// settings of the cluster. The lifecycle policies are omitted if the cluster does not serve
+// the lifecycle management API
+func (ec *esClient) GetClusterMetadata() (*estypes.ClusterMetadata, error) {
+	metadata := &estypes.ClusterMetadata{}
+
+	var err error
+	if metadata.IndexTemplates, err = ec.getMetadata("_template"); err != nil {
+		return nil, err
+	}
+	if metadata.Aliases, err = ec.getMetadata("_alias"); err != nil {
+		return nil, err
+	}
+	if metadata.Settings, err = ec.getMetadata("_cluster/settings?filter_path=persistent"); err != nil {
+		return nil, err
+	}
+
+	available, err := ec.IsLifecycleManagementAvailable()
+	if err != nil {
+		return nil, err
+	}
+	if available {
+		if metadata.LifecyclePolicies, err = ec.getMetadata("_ilm/policy"); err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+func (ec *esClient) getMetadata(uri string) (json.RawMessage, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    uri,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get cluster metadata",
+			"uri", uri,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	if !json.Valid([]byte(payload.RawResponseBody)) {
+		return nil, ec.errorCtx().New("invalid cluster metadata",
+			"uri", uri,
+			"response_body", payload.RawResponseBody)
+	}
+	return json.RawMessage(payload.RawResponseBody), nil
+}
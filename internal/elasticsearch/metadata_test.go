@@ -0,0 +1,55 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestGetClusterMetadataWithLifecyclePolicies(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template": {
+				{StatusCode: http.StatusOK, Body: `{"ocp-gen-app": {}}`},
+			},
+			"_alias": {
+				{StatusCode: http.StatusOK, Body: `{}`},
+			},
+			"_cluster/settings?filter_path=persistent": {
+				{StatusCode: http.StatusOK, Body: `{}`},
+			},
+			"_ilm/status": {
+				{StatusCode: http.StatusOK, Body: `{"operation_mode": "RUNNING"}`},
+			},
+			"_ilm/policy": {
+				{StatusCode: http.StatusOK, Body: `{"app": {"version": 1}}`},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	metadata, err := esClient.GetClusterMetadata()
+	if err != nil {
+		t.Fatalf("Exp. no error but got %v", err)
+	}
+	if string(metadata.IndexTemplates) != `{"ocp-gen-app": {}}` {
+		t.Errorf("Exp. the index templates but got %s", metadata.IndexTemplates)
+	}
+	if string(metadata.LifecyclePolicies) != `{"app": {"version": 1}}` {
+		t.Errorf("Exp. the lifecycle policies but got %s", metadata.LifecyclePolicies)
+	}
+}
+
+func TestGetClusterMetadataWhenResponseNot200(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template": {
+				{StatusCode: http.StatusInternalServerError, Body: `{}`},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if _, err := esClient.GetClusterMetadata(); err == nil {
+		t.Error("Exp. to return an error but did not")
+	}
+}
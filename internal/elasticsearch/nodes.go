@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/inhies/go-bytesize"
 )
@@ -36,3 +37,147 @@ func (ec *esClient) GetNodeDiskUsage(nodeName string) (string, float64, error) {
 
 	return usage, percentUsage, payload.Error
 }
+
+// GetNodesDiskUsedPercent returns the percentage of the disk used by every node keyed by node name
+func (ec *esClient) GetNodesDiskUsedPercent() (map[string]float64, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_nodes/stats/fs",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get the disk usage of nodes",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	usage := map[string]float64{}
+	if nodes, ok := payload.ResponseBody["nodes"].(map[string]interface{}); ok {
+		for _, stats := range nodes {
+			stats, ok := stats.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			total := parseFloat64("fs.total.total_in_bytes", stats)
+			available := parseFloat64("fs.total.available_in_bytes", stats)
+			if total <= 0 || available < 0 {
+				continue
+			}
+			usage[parseString("name", stats)] = (total - available) / total * 100.00
+		}
+	}
+	return usage, nil
+}
+
+// GetNodesSetting returns the value of a node setting keyed by node name. Nodes the setting
+// is not configured on are included with an empty value
+func (ec *esClient) GetNodesSetting(setting string) (map[string]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_nodes/settings?flat_settings=true",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get nodes settings",
+			"setting", setting,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	values := map[string]string{}
+	if nodes, ok := payload.ResponseBody["nodes"].(map[string]interface{}); ok {
+		for _, node := range nodes {
+			node, ok := node.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			settings, _ := node["settings"].(map[string]interface{})
+
+			var value string
+			switch v := settings[setting].(type) {
+			case string:
+				value = v
+			case []interface{}:
+				items := make([]string, 0, len(v))
+				for _, item := range v {
+					items = append(items, fmt.Sprint(item))
+				}
+				value = strings.Join(items, ",")
+			}
+			values[parseString("name", node)] = value
+		}
+	}
+	return values, nil
+}
+
+// GetNodeTimestamps returns the time at which each node collected its stats keyed by node name
+func (ec *esClient) GetNodeTimestamps() (map[string]time.Time, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_nodes/stats/os?filter_path=nodes.*.name,nodes.*.timestamp",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get nodes stats",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	timestamps := map[string]time.Time{}
+	if nodes, ok := payload.ResponseBody["nodes"].(map[string]interface{}); ok {
+		for _, node := range nodes {
+			node, ok := node.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			millis := parseFloat64("timestamp", node)
+			if millis < 0 {
+				continue
+			}
+			timestamps[parseString("name", node)] = time.Unix(0, int64(millis)*int64(time.Millisecond))
+		}
+	}
+	return timestamps, nil
+}
+
+// ReloadSecureSettings reloads the reloadable settings of the keystores of the nodes. The names
+// of the nodes failing to reload their settings are returned with the error
+func (ec *esClient) ReloadSecureSettings() error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    "_nodes/reload_secure_settings",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to reload secure settings",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	failed := []string{}
+	if nodes, ok := payload.ResponseBody["nodes"].(map[string]interface{}); ok {
+		for _, node := range nodes {
+			node, ok := node.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := node["reload_exception"]; ok {
+				failed = append(failed, parseString("name", node))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return ec.errorCtx().New("failed to reload secure settings of nodes",
+			"nodes", failed,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
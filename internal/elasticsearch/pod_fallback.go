@@ -0,0 +1,162 @@
+package elasticsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isServiceUnreachable returns true if the request failed before the service answered, e.g.
+// because the service has no endpoints or its address does not resolve
+func isServiceUnreachable(payload *EsRequest) bool {
+	return payload.Error != nil && payload.StatusCode == 0
+}
+
+// isReadRequest returns true if the request does not change the cluster and can be sent again
+func isReadRequest(payload *EsRequest) bool {
+	return payload.Method == http.MethodGet || payload.Method == http.MethodHead
+}
+
+// isRequestNotSent returns true if the request failed before it reached Elasticsearch, i.e. while
+// dialing the address or during the TLS handshake. Other errors, e.g. a timeout or a connection
+// reset while waiting for the response, may occur after Elasticsearch acted on the request
+func isRequestNotSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var recordHeaderErr tls.RecordHeaderError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateInvalidErr)
+}
+
+// podAddresses returns the IPs of the ready Elasticsearch pods of the cluster sorted to try
+// them in a stable order
+func podAddresses(cluster, namespace string, c client.Client) ([]string, error) {
+	pods := &v1.PodList{}
+	labels := client.MatchingLabels{
+		"cluster-name": cluster,
+		"component":    "elasticsearch",
+	}
+	if err := c.List(context.TODO(), pods, client.InNamespace(namespace), labels); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list elasticsearch pods",
+			"cluster", cluster,
+			"namespace", namespace)
+	}
+
+	addresses := []string{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				addresses = append(addresses, pod.Status.PodIP)
+				break
+			}
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// sendRequestToPods sends the read request to the pods of the cluster directly, bypassing the
+// service. The first pod answering fills the payload. The next pod is only tried if the request
+// did not reach the previous one
+func sendRequestToPods(cluster, namespace string, payload *EsRequest, c client.Client) {
+	addresses, err := podAddresses(cluster, namespace, c)
+	if err != nil {
+		log.Error(err, "failed to find elasticsearch pods to send the request to")
+		return
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	// the pods present the certificate of the service. Without TLS the admin certificate cannot
+	// be presented, so the SA token is authenticated by the proxy of the pods
+	scheme, port := httpScheme(cluster, namespace), "9200"
+	if scheme == schemeHTTP {
+		port = proxyPort
+	}
+
+	httpClient := getPodClient(cluster, namespace, c)
+	for _, address := range addresses {
+		u := fmt.Sprintf("%s://%s/%s", scheme, net.JoinHostPort(address, port), payload.URI)
+		urlURL, err := url.Parse(u)
+		if err != nil {
+			log.Error(err, "failed to parse URL", "url", u)
+			return
+		}
+
+		request := &http.Request{
+			Method: payload.Method,
+			URL:    urlURL,
+		}
+		if scheme == schemeHTTP || hasCredentials(cluster, namespace) {
+			request.Header = ensureAuthHeader(cluster, namespace, request.Header)
+		}
+
+		resp, err := httpClient.Do(request)
+		if err != nil {
+			log.Info("failed sending payload to elasticsearch pod", "method", payload.Method, "url", payload.URI, "pod_ip", address, "error", err)
+			if isRequestNotSent(err) {
+				continue
+			}
+			payload.Error = err
+			return
+		}
+
+		log.Info("Elasticsearch service unreachable, sent payload to pod directly", "method", payload.Method, "url", payload.URI, "pod_ip", address)
+		payload.StatusCode = resp.StatusCode
+		payload.Error = nil
+		if payload.RawResponseBody, err = getRawBody(resp.Body); err != nil {
+			log.Error(err, "failed to get raw response body")
+		}
+		if payload.ResponseBody, err = getMapFromBody(payload.RawResponseBody); err != nil {
+			log.Error(err, "getMapFromBody failed")
+		}
+		return
+	}
+}
+
+// this client presents the admin certs to the pods and validates them against the name of the
+// service since the pod IPs are not part of their certificate
+func getPodClient(clusterName, namespace string, client client.Client) *http.Client {
+	// get the contents of the secret
+	extractSecret(clusterName, namespace, client)
+
+	tlsConfig := newTLSConfig(clusterName, namespace)
+	tlsConfig.ServerName = fmt.Sprintf("%s.%s.svc", clusterName, namespace)
+	tlsConfig.Certificates = getClientCertificates(clusterName, namespace)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:     tlsConfig,
+		},
+		Timeout: 30 * time.Second,
+	}
+}
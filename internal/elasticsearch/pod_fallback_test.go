@@ -0,0 +1,85 @@
+package elasticsearch
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFallbackPod(name, ip string, phase v1.PodPhase, ready v1.ConditionStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-logging",
+			Labels: map[string]string{
+				"cluster-name": "elasticsearch",
+				"component":    "elasticsearch",
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+			PodIP: ip,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: ready},
+			},
+		},
+	}
+}
+
+func TestPodAddressesOnlyReturnsReadyPods(t *testing.T) {
+	client := fake.NewFakeClient(
+		newFallbackPod("elasticsearch-cdm-1", "10.0.0.2", v1.PodRunning, v1.ConditionTrue),
+		newFallbackPod("elasticsearch-cdm-2", "10.0.0.1", v1.PodRunning, v1.ConditionTrue),
+		newFallbackPod("elasticsearch-cdm-3", "10.0.0.3", v1.PodRunning, v1.ConditionFalse),
+		newFallbackPod("elasticsearch-cdm-4", "", v1.PodPending, v1.ConditionFalse),
+	)
+
+	addresses, err := podAddresses("elasticsearch", "openshift-logging", client)
+	if err != nil {
+		t.Fatalf("Exp. no error but got %v", err)
+	}
+	if exp := []string{"10.0.0.1", "10.0.0.2"}; !reflect.DeepEqual(addresses, exp) {
+		t.Errorf("Exp. the addresses %v but got %v", exp, addresses)
+	}
+}
+
+func TestIsServiceUnreachable(t *testing.T) {
+	if !isServiceUnreachable(&EsRequest{Error: errors.New("no such host")}) {
+		t.Errorf("Exp. a request failing without a response to fall back to the pods")
+	}
+	if isServiceUnreachable(&EsRequest{StatusCode: 503, Error: errors.New("unavailable")}) {
+		t.Errorf("Exp. a request answered by the service not to fall back to the pods")
+	}
+}
+
+func TestIsReadRequest(t *testing.T) {
+	if !isReadRequest(&EsRequest{Method: "GET"}) {
+		t.Errorf("Exp. a GET request to be sent to the pods")
+	}
+	if isReadRequest(&EsRequest{Method: "PUT"}) {
+		t.Errorf("Exp. a PUT request not to be sent to the pods")
+	}
+}
+
+func TestIsRequestNotSent(t *testing.T) {
+	dialErr := &url.Error{Op: "Put", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	if !isRequestNotSent(dialErr) {
+		t.Errorf("Exp. a failed dial not to reach Elasticsearch")
+	}
+	handshakeErr := &url.Error{Op: "Put", Err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}}
+	if !isRequestNotSent(handshakeErr) {
+		t.Errorf("Exp. a failed TLS handshake not to reach Elasticsearch")
+	}
+	readErr := &url.Error{Op: "Put", Err: &net.OpError{Op: "read", Net: "tcp", Err: io.EOF}}
+	if isRequestNotSent(readErr) {
+		t.Errorf("Exp. a failed read of the response to possibly reach Elasticsearch")
+	}
+}
@@ -0,0 +1,84 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// StartReIndex starts copying an index without waiting for its completion and returns the ID of the task
+func (ec *esClient) StartReIndex(reindex *estypes.ReIndexRequest) (string, error) {
+	body, err := utils.ToJSON(reindex)
+	if err != nil {
+		return "", err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         "_reindex?wait_for_completion=false",
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return "", ec.errorCtx().New("failed to start reindex",
+			"source_index", reindex.Source.Index,
+			"dest_index", reindex.Dest.Index,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	return parseString("task", payload.ResponseBody), nil
+}
+
+// GetReIndexTask returns the progress of a reindex task or nil if the task is not known to the cluster
+func (ec *esClient) GetReIndexTask(taskID string) (*estypes.ReIndexTask, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_tasks/%s", taskID),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get reindex task",
+			"task", taskID,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	task := &estypes.ReIndexTask{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), task); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.ReIndexTask`",
+			"task", taskID)
+	}
+	return task, nil
+}
+
+// CancelTask cancels a running task. Tasks not known to the cluster are ignored
+func (ec *esClient) CancelTask(taskID string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("_tasks/%s/_cancel", taskID),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusNotFound {
+		return ec.errorCtx().New("failed to cancel task",
+			"task", taskID,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
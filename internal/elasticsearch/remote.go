@@ -0,0 +1,137 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// UpdateRemoteClusterSettings connects the cluster to the seeds of the remote cluster or
+// removes the remote cluster when no seeds are given
+func (ec *esClient) UpdateRemoteClusterSettings(name string, seeds []string, skipUnavailable *bool) error {
+	var seedsValue, skipUnavailableValue interface{}
+	if len(seeds) > 0 {
+		seedsValue = seeds
+		if skipUnavailable != nil {
+			skipUnavailableValue = *skipUnavailable
+		}
+	}
+	body, err := utils.ToJSON(map[string]interface{}{
+		"persistent": map[string]interface{}{
+			fmt.Sprintf("cluster.remote.%s.seeds", name):            seedsValue,
+			fmt.Sprintf("cluster.remote.%s.skip_unavailable", name): skipUnavailableValue,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update remote cluster settings",
+			"remote_cluster", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetRemoteClusterInfo returns the connection state of the remote clusters by name
+func (ec *esClient) GetRemoteClusterInfo() (map[string]estypes.RemoteClusterInfo, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_remote/info",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get remote cluster info",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	info := map[string]estypes.RemoteClusterInfo{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &info); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.RemoteClusterInfo`")
+	}
+	return info, nil
+}
+
+// IsCrossClusterReplicationAvailable returns true if the cluster serves the cross-cluster replication API
+func (ec *esClient) IsCrossClusterReplicationAvailable() (bool, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_ccr/stats",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return false, payload.Error
+	}
+	switch payload.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusBadRequest, http.StatusNotFound:
+		return false, nil
+	}
+	return false, ec.errorCtx().New("failed to get cross-cluster replication stats",
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody)
+}
+
+// ListFollowerIndices returns the follower indices of the cluster
+func (ec *esClient) ListFollowerIndices() ([]estypes.FollowerIndexInfo, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_all/_ccr/info",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list follower indices",
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	info := &estypes.FollowerIndicesInfo{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), info); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.FollowerIndicesInfo`")
+	}
+	return info.FollowerIndices, nil
+}
+
+// FollowIndex creates the follower index replicating the leader index of the remote cluster
+func (ec *esClient) FollowIndex(name string, follow *estypes.FollowIndex) error {
+	body, err := utils.ToJSON(follow)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_ccr/follow", name),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to follow index",
+			"index", name,
+			"remote_cluster", follow.RemoteCluster,
+			"leader_index", follow.LeaderIndex,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
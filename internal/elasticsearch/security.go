@@ -0,0 +1,99 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+const securityAPI = "_opendistro/_security/api"
+
+// CreateOrUpdateInternalUser stores the user in the internal user database of the security plugin.
+// The request carries the password, so it is left out of the returned error
+func (ec *esClient) CreateOrUpdateInternalUser(name string, user *estypes.InternalUser) error {
+	body, err := utils.ToJSON(user)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/internalusers/%s", securityAPI, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to create or update internal user",
+			"user", name,
+			ErrorReasonKey, parseSecurityErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+func (ec *esClient) DeleteInternalUser(name string) error {
+	return ec.deleteSecurityResource("internalusers", name)
+}
+
+// CreateOrUpdateSecurityRole stores the role in the security plugin
+func (ec *esClient) CreateOrUpdateSecurityRole(name string, role *estypes.SecurityRole) error {
+	body, err := utils.ToJSON(role)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/roles/%s", securityAPI, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to create or update security role",
+			"role", name,
+			ErrorReasonKey, parseSecurityErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+func (ec *esClient) DeleteSecurityRole(name string) error {
+	return ec.deleteSecurityResource("roles", name)
+}
+
+func (ec *esClient) deleteSecurityResource(kind, name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("%s/%s/%s", securityAPI, kind, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && (payload.StatusCode == http.StatusNotFound || payload.StatusCode < 300) {
+		return nil
+	}
+
+	return ec.errorCtx().New("failed to delete security resource",
+		"kind", kind,
+		"name", name,
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody,
+		"response_error", payload.Error)
+}
+
+// parseSecurityErrorReason returns the reason the security plugin rejected a request. Its API
+// returns the reason as message instead of the error of Elasticsearch
+func parseSecurityErrorReason(body map[string]interface{}) string {
+	if reason := parseErrorReason(body); reason != "" {
+		return reason
+	}
+	message, _ := body["message"].(string)
+	return message
+}
@@ -0,0 +1,67 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestCreateOrUpdateInternalUserWhenRejected(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/internalusers/admin": {
+				{
+					StatusCode: http.StatusForbidden,
+					Body:       `{"status": "FORBIDDEN", "message": "Resource 'admin' is reserved."}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	err := esClient.CreateOrUpdateInternalUser("admin", &estypes.InternalUser{Password: "secret"})
+	if err == nil {
+		t.Fatal("Exp. to return an error but did not")
+	}
+	if reason := kverrors.KVs(err)[elasticsearch.ErrorReasonKey]; reason != "Resource 'admin' is reserved." {
+		t.Errorf("Exp. the security plugin message to be returned as reason, got %q", reason)
+	}
+}
+
+func TestCreateOrUpdateSecurityRoleWhenResponse201(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/roles/log-reader": {
+				{
+					StatusCode: http.StatusCreated,
+					Body:       `{"status": "CREATED", "message": "'log-reader' created."}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	role := &estypes.SecurityRole{ClusterPermissions: []string{"cluster_monitor"}}
+	if err := esClient.CreateOrUpdateSecurityRole("log-reader", role); err != nil {
+		t.Errorf("Exp. to not return an error %v", err)
+	}
+}
+
+func TestDeleteInternalUserWhenNotFound(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/internalusers/fluentd": {
+				{
+					StatusCode: http.StatusNotFound,
+					Body:       `{"status": "NOT_FOUND", "message": "'fluentd' not found."}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	if err := esClient.DeleteInternalUser("fluentd"); err != nil {
+		t.Errorf("Exp. a missing user to be ignored, got %v", err)
+	}
+}
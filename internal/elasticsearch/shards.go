@@ -1,10 +1,16 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/ViaQ/logerr/kverrors"
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
 )
 
 func (ec *esClient) ClearTransientShardAllocation() (bool, error) {
@@ -75,3 +81,116 @@ func (ec *esClient) GetShardAllocation() (string, error) {
 
 	return allocationString, payload.Error
 }
+
+// GetAllocationExcludedNodes returns the names of the nodes shards are moved off by the persistent
+// allocation exclusion of the cluster
+func (ec *esClient) GetAllocationExcludedNodes() ([]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cluster/settings?flat_settings=true&filter_path=persistent",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get allocation excluded nodes",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	persistent, _ := payload.ResponseBody["persistent"].(map[string]interface{})
+	value, _ := persistent["cluster.routing.allocation.exclude._name"].(string)
+
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// SetAllocationExcludedNodes moves the shards off the named nodes or allows allocating
+// shards to all nodes again when no names are given
+func (ec *esClient) SetAllocationExcludedNodes(names []string) error {
+	var value interface{}
+	if len(names) > 0 {
+		value = strings.Join(names, ",")
+	}
+	body, err := utils.ToJSON(map[string]interface{}{
+		"persistent": map[string]interface{}{
+			"cluster.routing.allocation.exclude._name": value,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update allocation excluded nodes",
+			"nodes", names,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetNodeShardCounts returns the number of shards allocated to the nodes by name
+func (ec *esClient) GetNodeShardCounts() (map[string]int32, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/allocation?h=node,shards&format=json",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shard allocation",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	allocations := []struct {
+		Node   string `json:"node"`
+		Shards string `json:"shards"`
+	}{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &allocations); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/allocation response body")
+	}
+
+	counts := map[string]int32{}
+	for _, allocation := range allocations {
+		shards, err := strconv.ParseInt(allocation.Shards, 10, 32)
+		if err != nil {
+			continue
+		}
+		counts[allocation.Node] += int32(shards)
+	}
+	return counts, nil
+}
+
+// GetShardStats returns the allocation and the indexing and search counters of all shard copies
+func (ec *esClient) GetShardStats() (estypes.CatShardsResponses, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/shards?h=index,shard,prirep,state,node,indexing.index_total,search.query_total&format=json",
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shard stats",
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	shards := estypes.CatShardsResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &shards); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/shards response body")
+	}
+	return shards, nil
+}
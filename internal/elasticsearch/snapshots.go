@@ -0,0 +1,142 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/ViaQ/logerr/kverrors"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// ListSnapshotRepositories returns the names of the snapshot repositories registered in the cluster
+func (ec *esClient) ListSnapshotRepositories() ([]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_snapshot/_all",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list snapshot repositories",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	repositories := []string{}
+	for name := range payload.ResponseBody {
+		repositories = append(repositories, name)
+	}
+	sort.Strings(repositories)
+	return repositories, nil
+}
+
+// VerifySnapshotRepository checks that all nodes are able to access the snapshot repository
+func (ec *esClient) VerifySnapshotRepository(repository string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("_snapshot/%s/_verify", repository),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to verify snapshot repository",
+			"repository", repository,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+	return nil
+}
+
+// ListSnapshots returns the names and states of the snapshots stored in the repository. The
+// listing is not verbose, so Elasticsearch only reads the index of the repository instead of the
+// metadata of every snapshot and the snapshots are sorted by name without their times
+func (ec *esClient) ListSnapshots(repository string) ([]estypes.Snapshot, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s/_all?verbose=false", repository),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to list snapshots",
+			"repository", repository,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+
+	response := &estypes.SnapshotsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.SnapshotsResponse`",
+			"repository", repository)
+	}
+	return response.Snapshots, nil
+}
+
+// CreateSnapshot starts a snapshot in the repository without waiting for its completion
+func (ec *esClient) CreateSnapshot(repository, name string, snapshot *estypes.CreateSnapshot) error {
+	body, err := utils.ToJSON(snapshot)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_snapshot/%s/%s", repository, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to create snapshot",
+			"repository", repository,
+			"snapshot", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error)
+	}
+	return nil
+}
+
+// GetSnapshot returns the snapshot of the repository or nil if it does not exist
+func (ec *esClient) GetSnapshot(repository, name string) (*estypes.Snapshot, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s/%s", repository, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return nil, payload.Error
+	}
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshot",
+			"repository", repository,
+			"snapshot", name,
+			ErrorReasonKey, parseErrorReason(payload.ResponseBody),
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	response := &estypes.SnapshotsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed decoding raw response body into `estypes.SnapshotsResponse`",
+			"repository", repository)
+	}
+	for i := range response.Snapshots {
+		if response.Snapshots[i].Snapshot == name {
+			return &response.Snapshots[i], nil
+		}
+	}
+	return nil, nil
+}
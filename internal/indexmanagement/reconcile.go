@@ -176,7 +176,7 @@ func ReconcileIndexManagementCronjob(apiclient client.Client, cluster *apis.Elas
 
 	name := fmt.Sprintf("%s-im-%s", cluster.Name, mapping.Name)
 	script := formatCmd(policy)
-	desired := newCronJob(cluster.Name, cluster.Namespace, name, schedule, script, cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, envvars)
+	desired := newCronJob(cluster.Name, cluster.Namespace, name, schedule, script, esService(cluster), cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, envvars)
 
 	cluster.AddOwnerRefTo(desired)
 	return reconcileCronJob(apiclient, cluster, desired, areCronJobsSame)
@@ -280,8 +280,19 @@ func areContainersSame(container, other corev1.Container) bool {
 	return true
 }
 
-func newContainer(clusterName, name, image, scriptPath string, envvars []corev1.EnvVar) corev1.Container {
-	envvars = append(envvars, corev1.EnvVar{Name: "ES_SERVICE", Value: fmt.Sprintf("https://%s:9200", clusterName)})
+// esService returns the URL of the REST API of the cluster, served without TLS if it is disabled
+func esService(cluster *apis.Elasticsearch) string {
+	scheme := "https"
+	if security := cluster.Spec.Security; security != nil && security.TLS != nil && security.TLS.HTTP != nil {
+		if enabled := security.TLS.HTTP.Enabled; enabled != nil && !*enabled {
+			scheme = "http"
+		}
+	}
+	return fmt.Sprintf("%s://%s:9200", scheme, cluster.Name)
+}
+
+func newContainer(name, image, scriptPath, esService string, envvars []corev1.EnvVar) corev1.Container {
+	envvars = append(envvars, corev1.EnvVar{Name: "ES_SERVICE", Value: esService})
 	container := corev1.Container{
 		Name:            name,
 		Image:           image,
@@ -308,11 +319,11 @@ func newContainer(clusterName, name, image, scriptPath string, envvars []corev1.
 	return container
 }
 
-func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelector map[string]string, tolerations []corev1.Toleration, envvars []corev1.EnvVar) *batch.CronJob {
+func newCronJob(clusterName, namespace, name, schedule, script, esService string, nodeSelector map[string]string, tolerations []corev1.Toleration, envvars []corev1.EnvVar) *batch.CronJob {
 	containerName := "indexmanagement"
 	podSpec := corev1.PodSpec{
 		ServiceAccountName: clusterName,
-		Containers:         []corev1.Container{newContainer(clusterName, containerName, constants.PackagedElasticsearchImage(), script, envvars)},
+		Containers:         []corev1.Container{newContainer(containerName, constants.PackagedElasticsearchImage(), script, esService, envvars)},
 		Volumes: []corev1.Volume{
 			{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: clusterName}}},
 			{Name: "scripts", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: indexManagementConfigmap}, DefaultMode: &fullExecMode}}},
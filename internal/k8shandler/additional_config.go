@@ -0,0 +1,120 @@
+package k8shandler
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// operatorSettings returns the settings of elasticsearch.yml set by the operator. The configuration
+// is rendered with every optional setting enabled
+func operatorSettings() ([]string, error) {
+	settings := []string{}
+	// FIPS mode replaces the keystore settings by the PEM files
+	for _, fipsMode := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		esy := esYmlStruct{
+			KibanaIndexMode:      "shared_ops",
+			EsUnicastHost:        "elasticsearch-cluster",
+			NodeQuorum:           "2",
+			RecoverExpectedNodes: "3",
+			SystemCallFilter:     "true",
+			ReindexWhitelist:     "remote:9200",
+			TransportTruststore:  remoteTruststorePath,
+			DataTiers:            true,
+			MemoryLock:           true,
+			ZoneAwareness:        true,
+			IngestRoles:          true,
+			MachineLearning:      true,
+			VotingOnly:           true,
+			CacheLimits:          true,
+			NodesDN:              true,
+			HTTPTLS:              true,
+			FIPSMode:             fipsMode,
+			ThreadPools:          true,
+			WriteThreadPool:      true,
+		}
+		if err := renderEsYml(buf, esy); err != nil {
+			return nil, err
+		}
+
+		values, err := flattenConfig(buf.String())
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range values {
+			if !utils.ContainsString(settings, name) {
+				settings = append(settings, name)
+			}
+		}
+	}
+	return settings, nil
+}
+
+// additionalConfigViolation returns the reason the additional configuration of the cluster is
+// invalid or an empty string. It must be valid YAML and must not set or nest the settings of the
+// operator
+func additionalConfigViolation(cluster *api.Elasticsearch) string {
+	config := cluster.Spec.AdditionalConfig
+	if config == "" {
+		return ""
+	}
+
+	values, err := flattenConfig(config)
+	if err != nil {
+		return fmt.Sprintf("The additional configuration is not valid YAML: %s", err)
+	}
+
+	owned, err := operatorSettings()
+	if err != nil {
+		return fmt.Sprintf("Unable to render the configuration of the operator: %s", err)
+	}
+
+	conflicts := []string{}
+	for name := range values {
+		for _, setting := range owned {
+			if matchesSetting(name, []string{setting}) || matchesSetting(setting, []string{name}) {
+				conflicts = append(conflicts, name)
+				break
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Sprintf("The additional configuration must not set the settings of the operator: %s", strings.Join(conflicts, ", "))
+	}
+	return ""
+}
+
+// renderAdditionalConfig returns the additional configuration of the cluster to append to
+// elasticsearch.yml. The settings are rendered by their dotted names which Elasticsearch merges
+// with the nested settings of the operator
+func renderAdditionalConfig(cluster *api.Elasticsearch) (string, error) {
+	values, err := flattenConfig(cluster.Spec.AdditionalConfig)
+	if err != nil {
+		return "", err
+	}
+
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	settings := yaml.MapSlice{}
+	for _, name := range names {
+		settings = append(settings, yaml.MapItem{Key: name, Value: values[name]})
+	}
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return "\n# additional configuration of the cluster\n" + string(out), nil
+}
@@ -0,0 +1,74 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Additional config", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+					},
+				},
+				AdditionalConfig: `
+thread_pool:
+  write:
+    queue_size: 500
+indices.breaker.total.limit: 70%
+node.attr.rack: r1
+`,
+			},
+		}
+	})
+
+	It("should append the additional settings to the generated elasticsearch.yml", func() {
+		Expect(additionalConfigViolation(cluster)).To(BeEmpty())
+
+		configmap := newClusterConfigMap(cluster, nil)
+		Expect(configmap).ToNot(BeNil())
+		Expect(configmap.Data[esConfig]).To(HaveSuffix(`
+# additional configuration of the cluster
+indices.breaker.total.limit: 70%
+node.attr.rack: r1
+thread_pool.write.queue_size: 500
+`))
+
+		settings := flattenSettings(configmap.Data[esConfig])
+		Expect(settings).To(HaveKeyWithValue("thread_pool.write.queue_size", "500"))
+		Expect(settings).To(HaveKeyWithValue("node.name", "${DC_NAME}"))
+	})
+
+	It("should reject the settings of the operator and the settings nesting them", func() {
+		cluster.Spec.AdditionalConfig = `
+node:
+  master: false
+discovery.zen: {ping.unicast.hosts: other}
+opendistro_security: disabled
+`
+		Expect(additionalConfigViolation(cluster)).To(Equal("The additional configuration must not set the settings of the operator: discovery.zen.ping.unicast.hosts, node.master, opendistro_security"))
+
+		configmap := newClusterConfigMap(cluster, nil)
+		Expect(configmap.Data[esConfig]).ToNot(ContainSubstring("additional configuration"))
+	})
+
+	It("should reject invalid YAML", func() {
+		cluster.Spec.AdditionalConfig = "thread_pool: [write"
+		Expect(additionalConfigViolation(cluster)).To(HavePrefix("The additional configuration is not valid YAML"))
+
+		cluster.Spec.AdditionalConfig = "- thread_pool"
+		Expect(additionalConfigViolation(cluster)).ToNot(BeEmpty())
+	})
+})
@@ -0,0 +1,67 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// nodeAffinity returns the affinity of the node or the generated one preferring to run the pods
+// of nodes with the same roles on different hosts
+func nodeAffinity(node api.ElasticsearchNode, roleMap map[api.ElasticsearchNodeRole]bool) *v1.Affinity {
+	if node.Affinity != nil {
+		return node.Affinity
+	}
+	return newAffinity(roleMap)
+}
+
+// affinityViolation returns the reason the affinity of a node is invalid or an empty string. The
+// affinity of a node must keep its pods off the hosts running pods of nodes with the same roles
+// unless the node allows colocation
+func affinityViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		if node.Affinity == nil || node.AllowColocation {
+			continue
+		}
+
+		// the pods of a node group differ by their node name only
+		podLabels := newLabels(cluster.Name, "", getNodeRoleMap(node))
+		delete(podLabels, "node-name")
+
+		if !separatesPods(node.Affinity, podLabels) {
+			return fmt.Sprintf("The affinity of the nodes with the roles %v must keep their pods on different hosts with a pod anti-affinity on %s or allow colocation",
+				node.Roles, hostnameLabel)
+		}
+	}
+	return ""
+}
+
+// separatesPods returns true if the affinity has a required or preferred pod anti-affinity on the
+// host name selecting the pods with the labels
+func separatesPods(affinity *v1.Affinity, podLabels map[string]string) bool {
+	if affinity.PodAntiAffinity == nil {
+		return false
+	}
+
+	terms := append([]v1.PodAffinityTerm{}, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+	for _, weighted := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		terms = append(terms, weighted.PodAffinityTerm)
+	}
+
+	for _, term := range terms {
+		if term.TopologyKey != hostnameLabel || term.LabelSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Custom affinity of nodes", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster  *api.Elasticsearch
+		affinity *v1.Affinity
+	)
+
+	BeforeEach(func() {
+		affinity = &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{MatchExpressions: []v1.NodeSelectorRequirement{
+							{Key: "node-role.kubernetes.io/infra", Operator: v1.NodeSelectorOpExists},
+						}},
+					},
+				},
+			},
+			PodAntiAffinity: &v1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+					{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"cluster-name": "elasticsearch", "es-node-master": "true"},
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		}
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3, Affinity: affinity},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	It("should replace the generated affinity", func() {
+		roleMap := getNodeRoleMap(cluster.Spec.Nodes[0])
+		Expect(nodeAffinity(cluster.Spec.Nodes[0], roleMap)).To(Equal(affinity))
+		Expect(nodeAffinity(cluster.Spec.Nodes[1], getNodeRoleMap(cluster.Spec.Nodes[1]))).To(Equal(newAffinity(getNodeRoleMap(cluster.Spec.Nodes[1]))))
+	})
+
+	It("should accept an affinity keeping the pods with the same roles apart", func() {
+		Expect(affinityViolation(cluster)).To(BeEmpty())
+	})
+
+	It("should reject an affinity allowing the pods with the same roles on a host", func() {
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey = "topology.kubernetes.io/zone"
+		Expect(affinityViolation(cluster)).To(ContainSubstring("must keep their pods on different hosts"))
+
+		affinity.PodAntiAffinity = nil
+		Expect(affinityViolation(cluster)).ToNot(BeEmpty())
+	})
+
+	It("should accept any affinity of nodes allowing colocation", func() {
+		affinity.PodAntiAffinity = nil
+		cluster.Spec.Nodes[0].AllowColocation = true
+		Expect(affinityViolation(cluster)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,350 @@
+package k8shandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultDualWriteWindow = 10 * time.Minute
+	defaultCutoverSample   = int32(100)
+)
+
+// ReconcileElasticsearchAliasCutover moves the alias of the cutover from its write index to the target
+// index one phase at a time. A failed verification moves the writes of the alias back to the source index
+func ReconcileElasticsearchAliasCutover(cutover *api.ElasticsearchAliasCutover, requestClient client.Client) error {
+	status := cutover.Status.DeepCopy()
+	if status.Phase == "" {
+		status.Phase = api.ElasticsearchAliasCutoverPhasePending
+	}
+
+	if isAliasCutoverFinished(status.Phase) {
+		return nil
+	}
+
+	er, err := newElasticsearchRequestFor(cutover.Spec.ElasticsearchName, cutover.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	if er == nil {
+		status.Message = "Elasticsearch cluster not found"
+		return updateElasticsearchAliasCutoverStatus(cutover, status, requestClient)
+	}
+
+	if !er.AnyNodeReady() {
+		status.Message = "Waiting for an Elasticsearch node to be ready"
+		return updateElasticsearchAliasCutoverStatus(cutover, status, requestClient)
+	}
+
+	if err := er.reconcileAliasCutover(cutover, status, time.Now()); err != nil {
+		return err
+	}
+	return updateElasticsearchAliasCutoverStatus(cutover, status, requestClient)
+}
+
+func (er *ElasticsearchRequest) reconcileAliasCutover(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus, now time.Time) error {
+	spec := cutover.Spec
+
+	switch status.Phase {
+	case api.ElasticsearchAliasCutoverPhasePending:
+		return er.startAliasCutover(cutover, status)
+
+	case api.ElasticsearchAliasCutoverPhaseBackfilling:
+		done, err := er.refreshAliasCutoverTask(status)
+		if err != nil || !done {
+			return err
+		}
+		if err := er.esClient.UpdateAlias(dualWriteAliasActions(spec.Alias, status.SourceIndex, spec.TargetIndex)); err != nil {
+			return err
+		}
+		start := metav1.NewTime(now)
+		status.Phase = api.ElasticsearchAliasCutoverPhaseDualWrite
+		status.DualWriteStartTime = &start
+		er.L().Info("Started dual write window of alias", "alias", spec.Alias, "target", spec.TargetIndex)
+
+	case api.ElasticsearchAliasCutoverPhaseDualWrite:
+		window := defaultDualWriteWindow
+		if spec.DualWriteWindow != nil {
+			window = spec.DualWriteWindow.Duration
+		}
+		if status.DualWriteStartTime != nil && now.Sub(status.DualWriteStartTime.Time) < window {
+			return nil
+		}
+		// only copy the documents written to the source index since the backfill started
+		return er.startAliasCutoverTask(cutover, status, api.ElasticsearchAliasCutoverPhaseCatchingUp, "create")
+
+	case api.ElasticsearchAliasCutoverPhaseCatchingUp:
+		done, err := er.refreshAliasCutoverTask(status)
+		if err != nil {
+			return err
+		}
+		if !done {
+			if status.Phase == api.ElasticsearchAliasCutoverPhaseFailed {
+				return er.rollbackAliasCutover(cutover, status)
+			}
+			return nil
+		}
+		if err := er.verifyAliasCutover(cutover, status); err != nil {
+			return err
+		}
+		if status.Phase == api.ElasticsearchAliasCutoverPhaseFailed {
+			return er.rollbackAliasCutover(cutover, status)
+		}
+		if err := er.esClient.UpdateAlias(estypes.AliasActions{
+			Actions: []estypes.AliasAction{
+				{Remove: &estypes.AliasRef{Index: status.SourceIndex, Alias: spec.Alias}},
+			},
+		}); err != nil {
+			return err
+		}
+		completed := metav1.NewTime(now)
+		status.Phase = api.ElasticsearchAliasCutoverPhaseCompleted
+		status.CompletionTime = &completed
+		status.Message = ""
+		er.L().Info("Completed cutover of alias", "alias", spec.Alias, "source", status.SourceIndex, "target", spec.TargetIndex)
+	}
+	return nil
+}
+
+// startAliasCutover copies the documents of the write index of the alias once the target index exists
+func (er *ElasticsearchRequest) startAliasCutover(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus) error {
+	spec := cutover.Spec
+
+	source, err := er.esClient.GetWriteIndex(spec.Alias)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = fmt.Sprintf("Alias %s has no write index", spec.Alias)
+		return nil
+	}
+	if source == spec.TargetIndex {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = fmt.Sprintf("Index %s already is the write index of alias %s", source, spec.Alias)
+		return nil
+	}
+
+	target, err := er.esClient.GetIndex(spec.TargetIndex)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		status.Message = fmt.Sprintf("Waiting for index %s to be created", spec.TargetIndex)
+		return nil
+	}
+
+	status.SourceIndex = source
+	return er.startAliasCutoverTask(cutover, status, api.ElasticsearchAliasCutoverPhaseBackfilling, "")
+}
+
+func (er *ElasticsearchRequest) startAliasCutoverTask(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus, phase api.ElasticsearchAliasCutoverPhase, opType string) error {
+	request := &estypes.ReIndexRequest{
+		Source: estypes.ReIndexSource{
+			Index: status.SourceIndex,
+			Size:  defaultRemoteReindexBatchSize,
+		},
+		Dest: estypes.ReIndexDest{
+			Index:  cutover.Spec.TargetIndex,
+			OpType: opType,
+		},
+	}
+	if opType == "create" {
+		request.Conflicts = "proceed"
+	}
+
+	taskID, err := er.esClient.StartReIndex(request)
+	if err != nil {
+		reason, _ := kverrors.KVs(err)[elasticsearch.ErrorReasonKey].(string)
+		if reason == "" {
+			return err
+		}
+		er.L().Error(err, "alias cutover reindex rejected", "cutover", cutover.Name)
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = reason
+		if phase == api.ElasticsearchAliasCutoverPhaseCatchingUp {
+			return er.rollbackAliasCutover(cutover, status)
+		}
+		return nil
+	}
+
+	status.Phase = phase
+	status.TaskID = taskID
+	status.Message = ""
+	return nil
+}
+
+// refreshAliasCutoverTask returns true once the reindex task of the cutover completed successfully
+func (er *ElasticsearchRequest) refreshAliasCutoverTask(status *api.ElasticsearchAliasCutoverStatus) (bool, error) {
+	task, err := er.esClient.GetReIndexTask(status.TaskID)
+	if err != nil {
+		return false, err
+	}
+
+	if task == nil {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = fmt.Sprintf("Reindex task %s no longer exists", status.TaskID)
+		return false, nil
+	}
+
+	if !task.Completed {
+		return false, nil
+	}
+
+	progress := task.Task.Status
+	if task.Response != nil {
+		progress = *task.Response
+	}
+	if failed, message := reindexTaskFailure(task, progress); failed {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = message
+		return false, nil
+	}
+
+	status.TaskID = ""
+	return true, nil
+}
+
+// verifyAliasCutover compares the document counts and a sample of the documents of both indices
+func (er *ElasticsearchRequest) verifyAliasCutover(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus) error {
+	var err error
+	if status.SourceCount, err = er.esClient.CountDocuments(status.SourceIndex); err != nil {
+		return err
+	}
+	if status.TargetCount, err = er.esClient.CountDocuments(cutover.Spec.TargetIndex); err != nil {
+		return err
+	}
+	// the target index also holds the documents written during the dual write window
+	if status.TargetCount < status.SourceCount {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = fmt.Sprintf("Index %s has %d documents but index %s has %d",
+			cutover.Spec.TargetIndex, status.TargetCount, status.SourceIndex, status.SourceCount)
+		return nil
+	}
+
+	size := defaultCutoverSample
+	if cutover.Spec.SampleSize != nil {
+		size = *cutover.Spec.SampleSize
+	}
+	if size == 0 {
+		return nil
+	}
+
+	samples, err := er.esClient.SampleDocuments(status.SourceIndex, size)
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		ids = append(ids, sample.ID)
+	}
+	targets, err := er.esClient.GetDocuments(cutover.Spec.TargetIndex, ids)
+	if err != nil {
+		return err
+	}
+
+	status.SampledDocuments = int32(len(samples))
+	status.MismatchedDocuments = countMismatchedDocuments(samples, targets)
+	if status.MismatchedDocuments > 0 {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseFailed
+		status.Message = fmt.Sprintf("%d of %d sampled documents of index %s are missing or differ in index %s",
+			status.MismatchedDocuments, status.SampledDocuments, status.SourceIndex, cutover.Spec.TargetIndex)
+	}
+	return nil
+}
+
+// rollbackAliasCutover writes to the source index again and removes the target index from the alias
+func (er *ElasticsearchRequest) rollbackAliasCutover(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus) error {
+	er.L().Info("Rolling back cutover of alias", "alias", cutover.Spec.Alias, "reason", status.Message)
+	return er.esClient.UpdateAlias(estypes.AliasActions{
+		Actions: []estypes.AliasAction{
+			{Remove: &estypes.AliasRef{Index: cutover.Spec.TargetIndex, Alias: cutover.Spec.Alias}},
+			{Add: &estypes.AddAliasAction{Index: status.SourceIndex, Alias: cutover.Spec.Alias, IsWriteIndex: utils.GetBool(true)}},
+		},
+	})
+}
+
+// dualWriteAliasActions makes the target the write index of the alias while the alias keeps reading
+// from the source index
+func dualWriteAliasActions(alias, source, target string) estypes.AliasActions {
+	return estypes.AliasActions{
+		Actions: []estypes.AliasAction{
+			{Add: &estypes.AddAliasAction{Index: source, Alias: alias, IsWriteIndex: utils.GetBool(false)}},
+			{Add: &estypes.AddAliasAction{Index: target, Alias: alias, IsWriteIndex: utils.GetBool(true)}},
+		},
+	}
+}
+
+// countMismatchedDocuments returns the number of documents missing from targets or whose source differs
+func countMismatchedDocuments(samples, targets []estypes.Document) int32 {
+	found := map[string]estypes.Document{}
+	for _, target := range targets {
+		if target.Found == nil || *target.Found {
+			found[target.ID] = target
+		}
+	}
+
+	mismatched := int32(0)
+	for _, sample := range samples {
+		target, ok := found[sample.ID]
+		if !ok || !bytes.Equal(normalizeDocumentSource(sample.Source), normalizeDocumentSource(target.Source)) {
+			mismatched++
+		}
+	}
+	return mismatched
+}
+
+// normalizeDocumentSource encodes the source of a document with sorted keys and no whitespace
+func normalizeDocumentSource(source json.RawMessage) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(source, &doc); err != nil {
+		return source
+	}
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return source
+	}
+	return normalized
+}
+
+func isAliasCutoverFinished(phase api.ElasticsearchAliasCutoverPhase) bool {
+	return phase == api.ElasticsearchAliasCutoverPhaseCompleted || phase == api.ElasticsearchAliasCutoverPhaseFailed
+}
+
+func updateElasticsearchAliasCutoverStatus(cutover *api.ElasticsearchAliasCutover, status *api.ElasticsearchAliasCutoverStatus, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchAliasCutover{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: cutover.Name, Namespace: cutover.Namespace}, current); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(current.Status, *status) {
+			return nil
+		}
+
+		current.Status = *status
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch alias cutover status",
+		"cutover", cutover.Name,
+		"namespace", cutover.Namespace)
+}
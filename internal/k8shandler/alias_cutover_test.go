@@ -0,0 +1,160 @@
+package k8shandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ElasticsearchAliasCutover", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cutover *api.ElasticsearchAliasCutover
+		status  *api.ElasticsearchAliasCutoverStatus
+		now     = time.Now()
+	)
+
+	withResponses := func(responses map[string]helpers.FakeElasticsearchResponses) {
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	}
+
+	BeforeEach(func() {
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			},
+		}
+		cutover = &api.ElasticsearchAliasCutover{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchAliasCutoverSpec{
+				ElasticsearchName: "elasticsearch",
+				Alias:             "app",
+				TargetIndex:       "app-v2",
+			},
+		}
+		status = &api.ElasticsearchAliasCutoverStatus{Phase: api.ElasticsearchAliasCutoverPhasePending}
+	})
+
+	It("should wait for the target index to be created", func() {
+		withResponses(map[string]helpers.FakeElasticsearchResponses{
+			"_alias/app": {{StatusCode: http.StatusOK, Body: `{"app-v1": {"aliases": {"app": {}}}}`}},
+			"app-v2":     {{StatusCode: http.StatusNotFound, Body: `{}`}},
+		})
+
+		Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+		Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhasePending))
+		Expect(status.Message).To(Equal("Waiting for index app-v2 to be created"))
+	})
+
+	It("should copy the documents of the write index of the alias", func() {
+		withResponses(map[string]helpers.FakeElasticsearchResponses{
+			"_alias/app":                         {{StatusCode: http.StatusOK, Body: `{"app-v1": {"aliases": {"app": {}}}}`}},
+			"app-v2":                             {{StatusCode: http.StatusOK, Body: `{"app-v2": {}}`}},
+			"_reindex?wait_for_completion=false": {{StatusCode: http.StatusOK, Body: `{"task": "node-a:1"}`}},
+		})
+
+		Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+		Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhaseBackfilling))
+		Expect(status.SourceIndex).To(Equal("app-v1"))
+		Expect(status.TaskID).To(Equal("node-a:1"))
+	})
+
+	It("should write to the target index once the documents are copied", func() {
+		status.Phase = api.ElasticsearchAliasCutoverPhaseBackfilling
+		status.SourceIndex = "app-v1"
+		status.TaskID = "node-a:1"
+		withResponses(map[string]helpers.FakeElasticsearchResponses{
+			"_tasks/node-a:1": {{StatusCode: http.StatusOK, Body: `{"completed": true, "response": {"total": 2, "created": 2}}`}},
+			"_aliases":        {{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}},
+		})
+
+		Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+		Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhaseDualWrite))
+		Expect(status.DualWriteStartTime).ToNot(BeNil())
+		req, found := chatter.GetRequest("_aliases")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"actions": [
+			{"add": {"index": "app-v1", "alias": "app", "is_write_index": false}},
+			{"add": {"index": "app-v2", "alias": "app", "is_write_index": true}}
+		]}`)
+	})
+
+	It("should copy the missing documents once the dual write window elapsed", func() {
+		start := metav1.NewTime(now.Add(-time.Hour))
+		status.Phase = api.ElasticsearchAliasCutoverPhaseDualWrite
+		status.SourceIndex = "app-v1"
+		status.DualWriteStartTime = &start
+		withResponses(map[string]helpers.FakeElasticsearchResponses{
+			"_reindex?wait_for_completion=false": {{StatusCode: http.StatusOK, Body: `{"task": "node-a:2"}`}},
+		})
+
+		Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+		Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhaseCatchingUp))
+		req, _ := chatter.GetRequest("_reindex?wait_for_completion=false")
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"conflicts": "proceed",
+			"source": {"index": "app-v1", "size": 1000},
+			"dest": {"index": "app-v2", "op_type": "create"}
+		}`)
+	})
+
+	Context("when the missing documents are copied", func() {
+		BeforeEach(func() {
+			status.Phase = api.ElasticsearchAliasCutoverPhaseCatchingUp
+			status.SourceIndex = "app-v1"
+			status.TaskID = "node-a:2"
+		})
+
+		responses := func(target string) map[string]helpers.FakeElasticsearchResponses {
+			return map[string]helpers.FakeElasticsearchResponses{
+				"_tasks/node-a:2": {{StatusCode: http.StatusOK, Body: `{"completed": true, "response": {"total": 1, "created": 1}}`}},
+				"app-v1/_refresh": {{StatusCode: http.StatusOK, Body: `{}`}},
+				"app-v2/_refresh": {{StatusCode: http.StatusOK, Body: `{}`}},
+				"app-v1/_count":   {{StatusCode: http.StatusOK, Body: `{"count": 2}`}},
+				"app-v2/_count":   {{StatusCode: http.StatusOK, Body: `{"count": 3}`}},
+				"app-v1/_search":  {{StatusCode: http.StatusOK, Body: `{"hits": {"hits": [{"_id": "1", "_source": {"a": 1, "b": 2}}]}}`}},
+				"app-v2/_mget":    {{StatusCode: http.StatusOK, Body: target}},
+				"_aliases":        {{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}},
+			}
+		}
+
+		It("should remove the source index from the alias when the documents match", func() {
+			withResponses(responses(`{"docs": [{"_id": "1", "found": true, "_source": {"b": 2, "a": 1}}]}`))
+
+			Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+			Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhaseCompleted))
+			Expect(status.SourceCount).To(Equal(int64(2)))
+			Expect(status.TargetCount).To(Equal(int64(3)))
+			Expect(status.SampledDocuments).To(Equal(int32(1)))
+			Expect(status.MismatchedDocuments).To(BeZero())
+			req, _ := chatter.GetRequest("_aliases")
+			helpers.ExpectJSON(req.Body).ToEqual(`{"actions": [{"remove": {"index": "app-v1", "alias": "app"}}]}`)
+		})
+
+		It("should write to the source index again when a document differs", func() {
+			withResponses(responses(`{"docs": [{"_id": "1", "found": true, "_source": {"a": 1, "b": 3}}]}`))
+
+			Expect(request.reconcileAliasCutover(cutover, status, now)).To(Succeed())
+			Expect(status.Phase).To(Equal(api.ElasticsearchAliasCutoverPhaseFailed))
+			Expect(status.MismatchedDocuments).To(Equal(int32(1)))
+			Expect(status.Message).To(Equal("1 of 1 sampled documents of index app-v1 are missing or differ in index app-v2"))
+			req, _ := chatter.GetRequest("_aliases")
+			helpers.ExpectJSON(req.Body).ToEqual(`{"actions": [
+				{"remove": {"index": "app-v2", "alias": "app"}},
+				{"add": {"index": "app-v1", "alias": "app", "is_write_index": true}}
+			]}`)
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package k8shandler
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// updateAllocationExclusion adds and removes names of the nodes shards are moved off. The backlog
+// scaling and the workload migrations share the persistent exclusion of the cluster with the names
+// excluded by administrators, so it is read and only the names of the caller are changed
+func (er *ElasticsearchRequest) updateAllocationExclusion(add, remove []string) error {
+	current, err := er.esClient.GetAllocationExcludedNodes()
+	if err != nil {
+		return err
+	}
+
+	excluded := sets.NewString(current...)
+	removed := sets.NewString(remove...)
+
+	// the names already excluded keep their order
+	names := []string{}
+	for _, name := range current {
+		if !removed.Has(name) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range sets.NewString(add...).Difference(excluded).List() {
+		names = append(names, name)
+	}
+
+	if sets.NewString(names...).Equal(excluded) {
+		return nil
+	}
+	return er.esClient.SetAllocationExcludedNodes(names)
+}
@@ -0,0 +1,217 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	analysisFilesVolumeName = "elasticsearch-analysis"
+	analysisFilesPath       = elasticsearchConfigPath + "/analysis"
+	// analysisFilesPropagation is the time the kubelets take to update the ConfigMaps mounted by
+	// the pods
+	analysisFilesPropagation = 2 * time.Minute
+)
+
+// analysisFiles are the ConfigMaps mounted into the analysis directory of the nodes
+type analysisFiles struct {
+	configMapNames []string
+	// staticHash is the hash of the files of the ConfigMaps with the Restart policy. Changing them
+	// changes the pod template and rolls out the nodes
+	staticHash string
+}
+
+// newAnalysisFiles returns the analysis files of the cluster or nil if it has none
+func newAnalysisFiles(cluster *api.Elasticsearch, client client.Client) *analysisFiles {
+	if len(cluster.Spec.AnalysisFiles) == 0 {
+		return nil
+	}
+
+	files := &analysisFiles{}
+	for _, spec := range cluster.Spec.AnalysisFiles {
+		files.configMapNames = append(files.configMapNames, spec.ConfigMapName)
+	}
+
+	configMaps, err := getAnalysisConfigMaps(cluster, client, api.AnalysisFilesRestart)
+	if err != nil {
+		log.Error(err, "Failed to get the analysis files of the cluster", "cluster", cluster.Name)
+	}
+	files.staticHash = analysisFilesHash(configMaps)
+	return files
+}
+
+func analysisFilesUpdatePolicy(spec api.AnalysisFilesSpec) api.AnalysisFilesUpdatePolicy {
+	if spec.UpdatePolicy == "" {
+		return api.AnalysisFilesReload
+	}
+	return spec.UpdatePolicy
+}
+
+// getAnalysisConfigMaps returns the ConfigMaps of the analysis files of the cluster with the update
+// policy. Missing ConfigMaps are skipped as they block the cluster
+func getAnalysisConfigMaps(cluster *api.Elasticsearch, c client.Client, policy api.AnalysisFilesUpdatePolicy) ([]v1.ConfigMap, error) {
+	configMaps := []v1.ConfigMap{}
+	for _, spec := range cluster.Spec.AnalysisFiles {
+		if analysisFilesUpdatePolicy(spec) != policy {
+			continue
+		}
+
+		configMap := v1.ConfigMap{}
+		key := types.NamespacedName{Name: spec.ConfigMapName, Namespace: cluster.Namespace}
+		if err := c.Get(context.TODO(), key, &configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, kverrors.Wrap(err, "failed to get analysis files configmap",
+				"configmap", spec.ConfigMapName)
+		}
+		configMaps = append(configMaps, configMap)
+	}
+	return configMaps, nil
+}
+
+// analysisFilesHash returns the hash of the files of the ConfigMaps
+func analysisFilesHash(configMaps []v1.ConfigMap) string {
+	hash := sha256.New()
+	for _, configMap := range configMaps {
+		files := map[string][]byte{}
+		for key, value := range configMap.Data {
+			files[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			files[key] = value
+		}
+
+		keys := []string{}
+		for key := range files {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(hash, "%s/%s=%d:", configMap.Name, key, len(files[key]))
+			hash.Write(files[key])
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// useAnalysisFiles mounts the analysis files into the Elasticsearch container
+func useAnalysisFiles(container *v1.Container, files *analysisFiles) {
+	container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+		Name:      analysisFilesVolumeName,
+		MountPath: analysisFilesPath,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env, v1.EnvVar{
+		Name:  "ANALYSIS_FILES_HASH",
+		Value: files.staticHash,
+	})
+}
+
+// newAnalysisFilesVolume returns the volume projecting the keys of the ConfigMaps into a single
+// directory. The volume is not mounted with a subPath so the kubelets update the files
+func newAnalysisFilesVolume(files *analysisFiles) v1.Volume {
+	sources := []v1.VolumeProjection{}
+	for _, name := range files.configMapNames {
+		sources = append(sources, v1.VolumeProjection{
+			ConfigMap: &v1.ConfigMapProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: name},
+			},
+		})
+	}
+	return v1.Volume{
+		Name: analysisFilesVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{Sources: sources},
+		},
+	}
+}
+
+// ReconcileAnalysisFiles reloads the search analyzers of the indices once the kubelets updated the
+// changed files of the ConfigMaps with the Reload policy. Changes of the other ConfigMaps roll out
+// the nodes instead
+func (er *ElasticsearchRequest) ReconcileAnalysisFiles() error {
+	return er.reconcileAnalysisFiles(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileAnalysisFiles(now time.Time) error {
+	cluster := er.cluster
+
+	if len(cluster.Spec.AnalysisFiles) == 0 {
+		return er.updateAnalysisFilesStatus(nil)
+	}
+
+	configMaps, err := getAnalysisConfigMaps(cluster, er.client, api.AnalysisFilesReload)
+	if err != nil {
+		return err
+	}
+	hash := analysisFilesHash(configMaps)
+
+	// the nodes load the current files on startup
+	if cluster.Status.AnalysisFiles == nil {
+		return er.updateAnalysisFilesStatus(&api.AnalysisFilesStatus{Hash: hash})
+	}
+
+	status := cluster.Status.AnalysisFiles.DeepCopy()
+	if status.Hash != hash {
+		er.L().Info("Analysis files changed, waiting for the files of the nodes to be updated")
+		status.Hash = hash
+		status.ChangedSince = &metav1.Time{Time: now}
+	}
+	if err := er.updateAnalysisFilesStatus(status); err != nil {
+		return err
+	}
+
+	if status.ChangedSince == nil || now.Before(status.ChangedSince.Add(analysisFilesPropagation)) {
+		return nil
+	}
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	if err := er.esClient.ReloadSearchAnalyzers("*"); err != nil {
+		return err
+	}
+
+	er.L().Info("Reloaded the search analyzers of the indices")
+	status = status.DeepCopy()
+	status.ChangedSince = nil
+	return er.updateAnalysisFilesStatus(status)
+}
+
+func (er *ElasticsearchRequest) updateAnalysisFilesStatus(status *api.AnalysisFilesStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.AnalysisFiles, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.AnalysisFiles, status) {
+			return nil
+		}
+
+		cluster.Status.AnalysisFiles = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update analysis files status")
+}
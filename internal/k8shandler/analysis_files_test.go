@@ -0,0 +1,151 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Analysis files", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter   *helpers.FakeElasticsearchChatter
+		request   *ElasticsearchRequest
+		cluster   *api.Elasticsearch
+		synonyms  *v1.ConfigMap
+		stopwords *v1.ConfigMap
+		now       time.Time
+	)
+
+	updateConfigMap := func(configMap *v1.ConfigMap, key, value string) {
+		configMap.Data[key] = value
+		Expect(request.client.Update(context.TODO(), configMap)).To(Succeed())
+	}
+
+	newTemplate := func() v1.PodTemplateSpec {
+		node := cluster.Spec.Nodes[0]
+		return newPodTemplateSpec("elasticsearch-cdm-abc-1", node, map[string]string{}, getNodeRoleMap(node), request.client, podTemplateOptions{
+			clusterName:   cluster.Name,
+			namespace:     cluster.Namespace,
+			commonSpec:    cluster.Spec.Spec,
+			analysisFiles: newAnalysisFiles(cluster, request.client),
+		})
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		now = time.Now()
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+					},
+				},
+				AnalysisFiles: []api.AnalysisFilesSpec{
+					{ConfigMapName: "synonyms"},
+					{ConfigMapName: "stopwords", UpdatePolicy: api.AnalysisFilesRestart},
+				},
+			},
+		}
+		synonyms = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "synonyms", Namespace: "openshift-logging"},
+			Data:       map[string]string{"synonyms.txt": "error, failure"},
+		}
+		stopwords = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "stopwords", Namespace: "openshift-logging"},
+			Data:       map[string]string{"stopwords.txt": "the"},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, synonyms, stopwords, pod),
+			cluster: cluster,
+		}
+
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"*/_reload_search_analyzers": {
+				{StatusCode: http.StatusOK, Body: `{"_shards": {"total": 2, "successful": 2, "failed": 0}, "reload_details": []}`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	})
+
+	It("should mount the keys of the configmaps into the analysis directory", func() {
+		spec := newTemplate().Spec
+
+		Expect(spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      analysisFilesVolumeName,
+			MountPath: "/usr/share/java/elasticsearch/config/analysis",
+			ReadOnly:  true,
+		}))
+		Expect(spec.Volumes).To(ContainElement(v1.Volume{
+			Name: analysisFilesVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Projected: &v1.ProjectedVolumeSource{
+					Sources: []v1.VolumeProjection{
+						{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "synonyms"}}},
+						{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "stopwords"}}},
+					},
+				},
+			},
+		}))
+	})
+
+	It("should roll out the nodes for the changes of the configmaps with the restart policy only", func() {
+		template := newTemplate()
+
+		updateConfigMap(synonyms, "synonyms.txt", "error, failure, fault")
+		Expect(podSpecChanges(template.Spec, newTemplate().Spec, true)).To(BeEmpty())
+
+		updateConfigMap(stopwords, "stopwords.txt", "the, a")
+		Expect(podSpecChanges(template.Spec, newTemplate().Spec, true)).To(ConsistOf("containers[elasticsearch].env: ANALYSIS_FILES_HASH"))
+	})
+
+	It("should reload the search analyzers once the changed files are updated", func() {
+		Expect(request.reconcileAnalysisFiles(now)).To(Succeed())
+		Expect(cluster.Status.AnalysisFiles.ChangedSince).To(BeNil())
+		hash := cluster.Status.AnalysisFiles.Hash
+
+		updateConfigMap(synonyms, "synonyms.txt", "error, failure, fault")
+		Expect(request.reconcileAnalysisFiles(now)).To(Succeed())
+		Expect(cluster.Status.AnalysisFiles.Hash).ToNot(Equal(hash))
+		Expect(cluster.Status.AnalysisFiles.ChangedSince).ToNot(BeNil())
+
+		Expect(request.reconcileAnalysisFiles(now.Add(time.Minute))).To(Succeed())
+		_, found := chatter.GetRequest("*/_reload_search_analyzers")
+		Expect(found).To(BeFalse())
+
+		Expect(request.reconcileAnalysisFiles(now.Add(3 * time.Minute))).To(Succeed())
+		_, found = chatter.GetRequest("*/_reload_search_analyzers")
+		Expect(found).To(BeTrue())
+		Expect(cluster.Status.AnalysisFiles.ChangedSince).To(BeNil())
+	})
+})
@@ -0,0 +1,184 @@
+package k8shandler
+
+import (
+	"fmt"
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	auditLoggerName      = "security_audit"
+	auditLogVolumeName   = "elasticsearch-audit"
+	auditLogPath         = "/elasticsearch/audit"
+	auditRollingAppender = "audit_rolling"
+	// auditRollingMaxFiles is the number of rolled audit log files kept in the audit log volume
+	auditRollingMaxFiles = 8
+)
+
+var (
+	defaultAuditLogSizeLimit = resource.MustParse("1Gi")
+
+	// auditEventTypes are the categories of the audit events of the security plugin
+	auditEventTypes = []api.AuditEventType{
+		api.AuditEventFailedLogin,
+		api.AuditEventAuthenticated,
+		api.AuditEventMissingPrivileges,
+		api.AuditEventGrantedPrivileges,
+		api.AuditEventSSLException,
+		api.AuditEventBadHeaders,
+	}
+)
+
+// auditLogging returns the enabled audit logging of the cluster or nil
+func auditLogging(cluster *api.Elasticsearch) *api.AuditLoggingSpec {
+	if cluster.Spec.Security == nil || cluster.Spec.Security.Audit == nil || !cluster.Spec.Security.Audit.Enabled {
+		return nil
+	}
+	return cluster.Spec.Security.Audit
+}
+
+func auditOutput(spec *api.AuditLoggingSpec) api.AuditOutputType {
+	if spec.Output == "" {
+		return api.AuditOutputInternal
+	}
+	return spec.Output
+}
+
+// auditLoggingViolation returns the reason the audit logging of the cluster is invalid or an empty
+// string. The event types must be distinct and the audit log volume is only written by the Log4j
+// output
+func auditLoggingViolation(cluster *api.Elasticsearch) string {
+	spec := auditLogging(cluster)
+	if spec == nil {
+		return ""
+	}
+
+	eventTypes := map[api.AuditEventType]bool{}
+	for _, eventType := range spec.EventTypes {
+		if eventTypes[eventType] {
+			return fmt.Sprintf("The audit event type %q is listed more than once", eventType)
+		}
+		eventTypes[eventType] = true
+	}
+
+	if spec.Volume != nil && auditOutput(spec) != api.AuditOutputLog4j {
+		return fmt.Sprintf("The audit log volume requires the %s output", api.AuditOutputLog4j)
+	}
+	if volume := spec.Volume; volume != nil && volume.SizeLimit != nil && volume.SizeLimit.Value() < 10*1024*1024 {
+		return fmt.Sprintf("The size limit %s of the audit log volume is below 10Mi", volume.SizeLimit.String())
+	}
+	return ""
+}
+
+// renderAuditLogging returns the audit settings of the security plugin to append to
+// elasticsearch.yml. The audited event types are rendered as the categories disabled on the REST
+// and transport layers
+func renderAuditLogging(cluster *api.Elasticsearch) (string, error) {
+	spec := auditLogging(cluster)
+	if spec == nil {
+		return "", nil
+	}
+
+	settings := yaml.MapSlice{}
+	switch auditOutput(spec) {
+	case api.AuditOutputLog4j:
+		settings = append(settings,
+			yaml.MapItem{Key: "opendistro_security.audit.type", Value: "log4j"},
+			yaml.MapItem{Key: "opendistro_security.audit.config.log4j.logger_name", Value: auditLoggerName},
+			yaml.MapItem{Key: "opendistro_security.audit.config.log4j.level", Value: "INFO"},
+		)
+	default:
+		settings = append(settings, yaml.MapItem{Key: "opendistro_security.audit.type", Value: "internal_elasticsearch"})
+	}
+
+	if len(spec.EventTypes) > 0 {
+		var disabled interface{} = "NONE"
+		if categories := disabledAuditCategories(spec.EventTypes); len(categories) > 0 {
+			disabled = categories
+		}
+		settings = append(settings,
+			yaml.MapItem{Key: "opendistro_security.audit.config.disabled_rest_categories", Value: disabled},
+			yaml.MapItem{Key: "opendistro_security.audit.config.disabled_transport_categories", Value: disabled},
+		)
+	}
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return "\n# audit logging of the cluster\n" + string(out), nil
+}
+
+// disabledAuditCategories returns the categories of the audit events not listed by eventTypes
+func disabledAuditCategories(eventTypes []api.AuditEventType) []string {
+	enabled := map[api.AuditEventType]bool{}
+	for _, eventType := range eventTypes {
+		enabled[eventType] = true
+	}
+
+	disabled := []string{}
+	for _, eventType := range auditEventTypes {
+		if !enabled[eventType] {
+			disabled = append(disabled, string(eventType))
+		}
+	}
+	return disabled
+}
+
+// applyAuditLogging sets the audit logger of the log configuration of the cluster if its audit
+// events are written by log4j. It writes to the audit log volume if it is set or to the console
+func applyAuditLogging(cluster *api.Elasticsearch, config *LogConfig) {
+	spec := auditLogging(cluster)
+	if spec == nil || auditOutput(spec) != api.AuditOutputLog4j {
+		return
+	}
+
+	config.AuditLogger = auditLoggerName
+	if spec.Volume == nil {
+		config.AuditAppender = "console"
+		return
+	}
+	sizeLimit := auditLogSizeLimit(spec.Volume)
+	config.AuditAppender = auditRollingAppender
+	config.AuditMaxFileSize = strconv.FormatInt(sizeLimit.Value()/10, 10)
+	config.AuditMaxFiles = auditRollingMaxFiles
+}
+
+func auditLogSizeLimit(spec *api.AuditLogVolumeSpec) resource.Quantity {
+	if spec.SizeLimit != nil && !spec.SizeLimit.IsZero() {
+		return spec.SizeLimit.DeepCopy()
+	}
+	return defaultAuditLogSizeLimit.DeepCopy()
+}
+
+// applyAuditLogVolume mounts the audit log volume into the Elasticsearch container if the audit
+// logger of the cluster writes to it
+func applyAuditLogVolume(cluster *api.Elasticsearch, spec *v1.PodSpec) {
+	audit := auditLogging(cluster)
+	if audit == nil || audit.Volume == nil || auditOutput(audit) != api.AuditOutputLog4j {
+		return
+	}
+
+	sizeLimit := auditLogSizeLimit(audit.Volume)
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name: auditLogVolumeName,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{
+				SizeLimit: &sizeLimit,
+			},
+		},
+	})
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		if container.Name == "elasticsearch" {
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+				Name:      auditLogVolumeName,
+				MountPath: auditLogPath,
+			})
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package k8shandler
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Audit logging", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Security: &api.SecuritySpec{
+					Audit: &api.AuditLoggingSpec{Enabled: true},
+				},
+			},
+		}
+	})
+
+	It("should not render anything unless audit logging is enabled", func() {
+		cluster.Spec.Security.Audit.Enabled = false
+		audit, err := renderAuditLogging(cluster)
+		Expect(err).To(BeNil())
+		Expect(audit).To(BeEmpty())
+	})
+
+	It("should write the audit events to the internal indices by default", func() {
+		audit, err := renderAuditLogging(cluster)
+		Expect(err).To(BeNil())
+		Expect(audit).To(Equal(`
+# audit logging of the cluster
+opendistro_security.audit.type: internal_elasticsearch
+`))
+	})
+
+	It("should render the event types as the disabled categories", func() {
+		cluster.Spec.Security.Audit.Output = api.AuditOutputLog4j
+		cluster.Spec.Security.Audit.EventTypes = []api.AuditEventType{
+			api.AuditEventFailedLogin,
+			api.AuditEventMissingPrivileges,
+			api.AuditEventSSLException,
+			api.AuditEventBadHeaders,
+		}
+		audit, err := renderAuditLogging(cluster)
+		Expect(err).To(BeNil())
+		Expect(audit).To(Equal(`
+# audit logging of the cluster
+opendistro_security.audit.type: log4j
+opendistro_security.audit.config.log4j.logger_name: security_audit
+opendistro_security.audit.config.log4j.level: INFO
+opendistro_security.audit.config.disabled_rest_categories:
+- AUTHENTICATED
+- GRANTED_PRIVILEGES
+opendistro_security.audit.config.disabled_transport_categories:
+- AUTHENTICATED
+- GRANTED_PRIVILEGES
+`))
+
+		cluster.Spec.Security.Audit.EventTypes = append(cluster.Spec.Security.Audit.EventTypes, api.AuditEventAuthenticated, api.AuditEventGrantedPrivileges)
+		audit, err = renderAuditLogging(cluster)
+		Expect(err).To(BeNil())
+		Expect(audit).To(ContainSubstring("opendistro_security.audit.config.disabled_rest_categories: NONE\n"))
+	})
+
+	It("should reject a volume which is not written by the audit logger", func() {
+		cluster.Spec.Security.Audit.Volume = &api.AuditLogVolumeSpec{}
+		Expect(auditLoggingViolation(cluster)).To(Equal("The audit log volume requires the Log4j output"))
+
+		cluster.Spec.Security.Audit.Output = api.AuditOutputLog4j
+		Expect(auditLoggingViolation(cluster)).To(BeEmpty())
+
+		sizeLimit := resource.MustParse("1Mi")
+		cluster.Spec.Security.Audit.Volume.SizeLimit = &sizeLimit
+		Expect(auditLoggingViolation(cluster)).To(ContainSubstring("below 10Mi"))
+	})
+
+	It("should reject event types listed more than once", func() {
+		cluster.Spec.Security.Audit.EventTypes = []api.AuditEventType{api.AuditEventFailedLogin, api.AuditEventFailedLogin}
+		Expect(auditLoggingViolation(cluster)).To(Equal(`The audit event type "FAILED_LOGIN" is listed more than once`))
+	})
+
+	It("should log the audit events to the console without a volume", func() {
+		cluster.Spec.Security.Audit.Output = api.AuditOutputLog4j
+		config := newLogConfig(cluster)
+		applyAuditLogging(cluster, &config)
+
+		buf := &strings.Builder{}
+		Expect(renderLog4j2Properties(buf, config)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("logger.audit.name = security_audit\nlogger.audit.level = info\nlogger.audit.appenderRef.console.ref = console\n"))
+		Expect(buf.String()).ToNot(ContainSubstring("appender.audit_rolling"))
+	})
+
+	It("should roll the audit log in the audit log volume", func() {
+		sizeLimit := resource.MustParse("100Mi")
+		cluster.Spec.Security.Audit.Output = api.AuditOutputLog4j
+		cluster.Spec.Security.Audit.Volume = &api.AuditLogVolumeSpec{SizeLimit: &sizeLimit}
+		config := newLogConfig(cluster)
+		applyAuditLogging(cluster, &config)
+
+		buf := &strings.Builder{}
+		Expect(renderLog4j2Properties(buf, config)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("logger.audit.appenderRef.audit_rolling.ref = audit_rolling\n"))
+		Expect(buf.String()).To(ContainSubstring("appender.audit_rolling.fileName = /elasticsearch/audit/audit.log\n"))
+		Expect(buf.String()).To(ContainSubstring("appender.audit_rolling.policies.size.size = 10485760\n"))
+		Expect(buf.String()).To(ContainSubstring("appender.audit_rolling.strategy.max = 8"))
+
+		spec := &v1.PodSpec{Containers: []v1.Container{{Name: "elasticsearch"}, {Name: "proxy"}}}
+		applyAuditLogVolume(cluster, spec)
+		Expect(spec.Volumes).To(ConsistOf(v1.Volume{
+			Name: "elasticsearch-audit",
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{SizeLimit: &sizeLimit},
+			},
+		}))
+		Expect(spec.Containers[0].VolumeMounts).To(ConsistOf(v1.VolumeMount{Name: "elasticsearch-audit", MountPath: "/elasticsearch/audit"}))
+		Expect(spec.Containers[1].VolumeMounts).To(BeEmpty())
+	})
+})
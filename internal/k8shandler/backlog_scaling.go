@@ -0,0 +1,193 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	ingestBacklogAnnotation      = "elasticsearch.openshift.io/ingest-backlog"
+	defaultBacklogCooldownPeriod = 15 * time.Minute
+)
+
+// ReconcileBacklogScaling adds data nodes to the last hot data node while the ingestion is
+// signalled to be backlogged. The added nodes are drained and removed once the backlog is
+// cleared for the cooldown period
+func (er *ElasticsearchRequest) ReconcileBacklogScaling() error {
+	return er.reconcileBacklogScaling(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileBacklogScaling(now time.Time) error {
+	cluster := er.cluster
+	spec := cluster.Spec.BacklogScaling
+	current := cluster.Status.BacklogScaling
+
+	if spec != nil && cluster.GetAnnotations()[ingestBacklogAnnotation] == "true" {
+		// a lowered limit applies once the backlog is cleared to drain the nodes first
+		extraNodes := spec.MaxExtraNodes
+		if current != nil && current.ExtraNodes > extraNodes {
+			extraNodes = current.ExtraNodes
+		}
+
+		// the added nodes may be draining since the previous backlog was cleared
+		if current != nil && er.backlogNodesDraining(current, spec, now) && er.AnyNodeReady() {
+			if index := backlogScaledNode(cluster); index != NotFoundIndex {
+				if err := er.updateAllocationExclusion(nil, backlogNodeNames(cluster, index, current.ExtraNodes)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if current == nil || current.ExtraNodes < extraNodes {
+			er.L().Info("Adding data nodes for the ingestion backlog", "nodes", extraNodes)
+		}
+		return er.updateBacklogScalingStatus(&api.BacklogScalingStatus{ExtraNodes: extraNodes})
+	}
+
+	if current == nil || current.ExtraNodes == 0 {
+		return er.updateBacklogScalingStatus(nil)
+	}
+
+	status := current.DeepCopy()
+	if status.ClearedSince == nil {
+		status.ClearedSince = &metav1.Time{Time: now}
+	}
+	if now.Before(status.ClearedSince.Add(backlogCooldownPeriod(spec))) {
+		return er.updateBacklogScalingStatus(status)
+	}
+
+	index := backlogScaledNode(cluster)
+	if index == NotFoundIndex {
+		return er.updateBacklogScalingStatus(nil)
+	}
+
+	// the shards are moved off one set of nodes at a time
+	if len(cluster.Status.WorkloadMigrations) > 0 {
+		status.Message = "Waiting for the workload migrations to complete to remove the added nodes"
+		return er.updateBacklogScalingStatus(status)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	names := backlogNodeNames(cluster, index, status.ExtraNodes)
+	if err := er.updateAllocationExclusion(names, nil); err != nil {
+		return err
+	}
+
+	counts, err := er.esClient.GetNodeShardCounts()
+	if err != nil {
+		return err
+	}
+
+	status.RemainingShards = 0
+	for _, name := range names {
+		status.RemainingShards += counts[name]
+	}
+	if status.RemainingShards > 0 {
+		status.Message = fmt.Sprintf("Moving the shards off the added nodes %v", names)
+		return er.updateBacklogScalingStatus(status)
+	}
+
+	if err := er.updateAllocationExclusion(nil, names); err != nil {
+		return err
+	}
+
+	er.L().Info("Removing the data nodes added for the ingestion backlog", "nodes", names)
+	return er.updateBacklogScalingStatus(nil)
+}
+
+// backlogNodesDraining returns true if the shards are moved off the added nodes since the
+// backlog is cleared for the cooldown period
+func (er *ElasticsearchRequest) backlogNodesDraining(status *api.BacklogScalingStatus, spec *api.BacklogScalingSpec, now time.Time) bool {
+	if status.ClearedSince == nil || now.Before(status.ClearedSince.Add(backlogCooldownPeriod(spec))) {
+		return false
+	}
+	return len(er.cluster.Status.WorkloadMigrations) == 0
+}
+
+// backlogExtraNodes returns the number of data nodes added to the node at the index of the nodes
+// of the cluster for an ingestion backlog
+func backlogExtraNodes(cluster *api.Elasticsearch, index int) int32 {
+	status := cluster.Status.BacklogScaling
+	if status == nil || status.ExtraNodes == 0 || index != backlogScaledNode(cluster) {
+		return 0
+	}
+	return status.ExtraNodes
+}
+
+// backlogScaledNode returns the index of the node getting the data nodes added for an ingestion
+// backlog. The last hot data node without the master role is scaled to keep the quorum of the
+// masters, or else the last hot data node
+func backlogScaledNode(cluster *api.Elasticsearch) int {
+	nodes := cluster.Spec.Nodes
+
+	index := NotFoundIndex
+	for i, node := range nodes {
+		if !isDataNode(node) || node.GenUUID == nil {
+			continue
+		}
+		if tier := dataTier(cluster, node); tier != "" && tier != string(api.ElasticsearchDataTierHot) {
+			continue
+		}
+		if index == NotFoundIndex || !isMasterNode(node) || isMasterNode(nodes[index]) {
+			index = i
+		}
+	}
+	return index
+}
+
+// backlogNodeNames returns the names of the Elasticsearch nodes added to the node at the index
+// for an ingestion backlog
+func backlogNodeNames(cluster *api.Elasticsearch, index int, extraNodes int32) []string {
+	node := cluster.Spec.Nodes[index]
+	nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+
+	names := []string{}
+	for i := int32(0); i < extraNodes; i++ {
+		if isStatefulSetNode(node) {
+			names = append(names, statefulSetPodName(nodeName, node, node.NodeCount+i))
+		} else {
+			names = append(names, addDataNodeSuffix(nodeName, node.NodeCount+i+1))
+		}
+	}
+	return names
+}
+
+func backlogCooldownPeriod(spec *api.BacklogScalingSpec) time.Duration {
+	if spec != nil && spec.CooldownPeriod != nil && spec.CooldownPeriod.Duration > 0 {
+		return spec.CooldownPeriod.Duration
+	}
+	return defaultBacklogCooldownPeriod
+}
+
+func (er *ElasticsearchRequest) updateBacklogScalingStatus(status *api.BacklogScalingStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.BacklogScaling, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.BacklogScaling, status) {
+			return nil
+		}
+
+		cluster.Status.BacklogScaling = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update backlog scaling status")
+}
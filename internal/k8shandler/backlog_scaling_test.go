@@ -0,0 +1,183 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Backlog scaling", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     time.Time
+		uuid    = "abc"
+	)
+
+	newChatter := func(shards, excluded string) {
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_cluster/settings?flat_settings=true&filter_path=persistent": {
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster.routing.allocation.exclude._name": "` + excluded + `"}}`},
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster.routing.allocation.exclude._name": "` + excluded + `"}}`},
+				},
+				"_cluster/settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+				"_cat/allocation?h=node,shards&format=json": {
+					{StatusCode: http.StatusOK, Body: `[
+						{"node": "elasticsearch-cdm-abc-1", "shards": "4"},
+						{"node": "elasticsearch-cd-abc-1", "shards": "4"},
+						{"node": "elasticsearch-cd-abc-2", "shards": "` + shards + `"}
+					]`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		now = time.Now()
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "elasticsearch",
+				Namespace:   "openshift-logging",
+				Annotations: map[string]string{ingestBacklogAnnotation: "true"},
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+						GenUUID:   &uuid,
+					},
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+						NodeCount: 1,
+						GenUUID:   &uuid,
+					},
+				},
+				BacklogScaling: &api.BacklogScalingSpec{MaxExtraNodes: 1},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+	})
+
+	clearBacklog := func() {
+		cluster.Annotations = nil
+		Expect(request.client.Update(context.TODO(), cluster)).To(Succeed())
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		Expect(cluster.Status.BacklogScaling.ClearedSince).ToNot(BeNil())
+	}
+
+	It("should add nodes to the last hot data node without the master role while backlogged", func() {
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		Expect(cluster.Status.BacklogScaling).To(Equal(&api.BacklogScalingStatus{ExtraNodes: 1}))
+
+		Expect(backlogExtraNodes(cluster, 0)).To(BeZero())
+		Expect(backlogExtraNodes(cluster, 1)).To(Equal(int32(1)))
+		Expect(backlogNodeNames(cluster, 1, 1)).To(Equal([]string{"elasticsearch-cd-abc-2"}))
+	})
+
+	It("should keep the added nodes during the cooldown period", func() {
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		clearBacklog()
+
+		Expect(request.reconcileBacklogScaling(now.Add(10 * time.Minute))).To(Succeed())
+		Expect(cluster.Status.BacklogScaling.ExtraNodes).To(Equal(int32(1)))
+	})
+
+	It("should move the shards off the added nodes before removing them", func() {
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		clearBacklog()
+
+		newChatter("3", "")
+		Expect(request.reconcileBacklogScaling(now.Add(20 * time.Minute))).To(Succeed())
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "elasticsearch-cd-abc-2"}
+		}`)
+		Expect(cluster.Status.BacklogScaling.ExtraNodes).To(Equal(int32(1)))
+		Expect(cluster.Status.BacklogScaling.RemainingShards).To(Equal(int32(3)))
+
+		newChatter("0", "elasticsearch-cd-abc-2")
+		Expect(request.reconcileBacklogScaling(now.Add(25 * time.Minute))).To(Succeed())
+		Expect(cluster.Status.BacklogScaling).To(BeNil())
+		req, found = chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": null}
+		}`)
+	})
+
+	It("should keep the nodes excluded by others", func() {
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		clearBacklog()
+
+		newChatter("3", "elasticsearch-cd-abc-1,retired-node")
+		Expect(request.reconcileBacklogScaling(now.Add(20 * time.Minute))).To(Succeed())
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "elasticsearch-cd-abc-1,retired-node,elasticsearch-cd-abc-2"}
+		}`)
+
+		newChatter("0", "elasticsearch-cd-abc-1,retired-node,elasticsearch-cd-abc-2")
+		Expect(request.reconcileBacklogScaling(now.Add(25 * time.Minute))).To(Succeed())
+		req, found = chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "elasticsearch-cd-abc-1,retired-node"}
+		}`)
+	})
+
+	It("should only clear its own nodes when the backlog returns while draining", func() {
+		Expect(request.reconcileBacklogScaling(now)).To(Succeed())
+		clearBacklog()
+
+		cluster.Annotations = map[string]string{ingestBacklogAnnotation: "true"}
+		newChatter("3", "retired-node,elasticsearch-cd-abc-2")
+		Expect(request.reconcileBacklogScaling(now.Add(20 * time.Minute))).To(Succeed())
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "retired-node"}
+		}`)
+		Expect(cluster.Status.BacklogScaling).To(Equal(&api.BacklogScalingStatus{ExtraNodes: 1}))
+	})
+})
@@ -0,0 +1,213 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// blockingDependency is an external prerequisite the cluster is waiting on
+type blockingDependency struct {
+	reason  string
+	message string
+}
+
+// CheckBlockingDependencies flags the cluster with the Blocked condition when it waits on
+// external prerequisites which require an action of the user, and clears it once they are met
+func (er *ElasticsearchRequest) CheckBlockingDependencies() error {
+	cluster := er.cluster
+
+	dependencies, err := er.blockingDependencies()
+	if err != nil {
+		return err
+	}
+
+	value := v1.ConditionFalse
+	reason := ""
+	var messages []string
+	if len(dependencies) > 0 {
+		value = v1.ConditionTrue
+		reason = dependencies[0].reason
+		for _, dependency := range dependencies {
+			messages = append(messages, dependency.message)
+		}
+		er.L().Info("Blocked by external dependencies", "dependencies", messages)
+	}
+
+	err = updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.Blocked,
+				Status:  value,
+				Reason:  reason,
+				Message: strings.Join(messages, "; "),
+			})
+		},
+		er.client,
+	)
+	if err != nil {
+		return err
+	}
+
+	// the nodes cannot start without the secrets they mount, so the reconcile waits for the
+	// user to create them instead of retrying right away
+	var missingSecrets []string
+	for _, dependency := range dependencies {
+		if dependency.reason == api.BlockedReasonMissingSecret {
+			missingSecrets = append(missingSecrets, dependency.message)
+		}
+	}
+	if len(missingSecrets) > 0 {
+		return kverrors.Wrap(ErrInvalidConfiguration, "missing referenced secrets",
+			"secrets", strings.Join(missingSecrets, "; "))
+	}
+	return nil
+}
+
+// blockingDependencies returns the missing external prerequisites of the cluster
+func (er *ElasticsearchRequest) blockingDependencies() ([]blockingDependency, error) {
+	cluster := er.cluster
+	var dependencies []blockingDependency
+
+	secrets := []string{cluster.Name}
+	if cluster.Spec.Migration != nil && cluster.Spec.Migration.RemoteReindex != nil && cluster.Spec.Migration.RemoteReindex.Secret != "" {
+		secrets = append(secrets, cluster.Spec.Migration.RemoteReindex.Secret)
+	}
+	for _, remote := range cluster.Spec.RemoteClusters {
+		if remote.Elasticsearch == "" && remote.Secret != "" {
+			secrets = append(secrets, remote.Secret)
+		}
+	}
+	for _, settings := range cluster.Spec.SecureSettings {
+		secrets = append(secrets, settings.SecretName)
+	}
+	if cluster.Spec.ClientCertificates != nil {
+		secrets = append(secrets, cluster.Spec.ClientCertificates.CASecretName)
+	}
+	secrets = append(secrets, customCertificateSecrets(cluster)...)
+	if spec := operatorCredentials(cluster); spec != nil {
+		secrets = append(secrets, spec.SecretRef.Name)
+	}
+	for _, source := range realmSecureSettingsSources(cluster) {
+		secrets = append(secrets, source.secretName)
+	}
+	for _, service := range cluster.Spec.LegacyClientServices {
+		if service.CertSecretName != "" {
+			secrets = append(secrets, service.CertSecretName)
+		}
+	}
+	for _, name := range secrets {
+		found, err := er.exists(types.NamespacedName{Name: name, Namespace: cluster.Namespace}, &v1.Secret{})
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to get secret", "secret", name)
+		}
+		if !found {
+			dependencies = append(dependencies, blockingDependency{
+				reason:  api.BlockedReasonMissingSecret,
+				message: fmt.Sprintf("Waiting for secret %s/%s", cluster.Namespace, name),
+			})
+		}
+	}
+
+	missingKeys, err := er.missingRealmSecretKeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range missingKeys {
+		dependencies = append(dependencies, blockingDependency{
+			reason:  api.BlockedReasonMissingSecret,
+			message: fmt.Sprintf("Waiting for key %s/%s", cluster.Namespace, key),
+		})
+	}
+
+	for _, files := range cluster.Spec.AnalysisFiles {
+		name := files.ConfigMapName
+		found, err := er.exists(types.NamespacedName{Name: name, Namespace: cluster.Namespace}, &v1.ConfigMap{})
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to get configmap", "configmap", name)
+		}
+		if !found {
+			dependencies = append(dependencies, blockingDependency{
+				reason:  api.BlockedReasonMissingConfigMap,
+				message: fmt.Sprintf("Waiting for configmap %s/%s", cluster.Namespace, name),
+			})
+		}
+	}
+
+	storageClasses := map[string]bool{}
+	for _, node := range cluster.Spec.Nodes {
+		name := node.Storage.StorageClassName
+		if usesEphemeralStorage(node) || name == nil || *name == "" || storageClasses[*name] {
+			continue
+		}
+		storageClasses[*name] = true
+
+		found, err := er.exists(types.NamespacedName{Name: *name}, &storagev1.StorageClass{})
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to get storage class", "storage_class", *name)
+		}
+		if !found {
+			dependencies = append(dependencies, blockingDependency{
+				reason:  api.BlockedReasonMissingStorageClass,
+				message: fmt.Sprintf("Waiting for storage class %s", *name),
+			})
+		}
+	}
+
+	for _, node := range cluster.Status.Nodes {
+		nodeName := node.DeploymentName
+		if nodeName == "" {
+			nodeName = node.StatefulSetName
+		}
+		for _, condition := range node.Conditions {
+			if condition.Type != api.ESContainerWaiting && condition.Type != api.ProxyContainerWaiting {
+				continue
+			}
+			if condition.Status != v1.ConditionTrue || !imagePullFailureReasons[condition.Reason] {
+				continue
+			}
+			dependencies = append(dependencies, blockingDependency{
+				reason:  api.BlockedReasonImagePullFailure,
+				message: fmt.Sprintf("Waiting for the image of node %s: %s", nodeName, condition.Message),
+			})
+		}
+	}
+
+	for _, repository := range cluster.Status.SnapshotRepositories {
+		if repository.Healthy {
+			continue
+		}
+		dependencies = append(dependencies, blockingDependency{
+			reason:  api.BlockedReasonSnapshotRepositoryUnreachable,
+			message: fmt.Sprintf("Waiting for snapshot repository %s to be reachable: %s", repository.Name, repository.Message),
+		})
+	}
+
+	return dependencies, nil
+}
+
+func (er *ElasticsearchRequest) exists(key types.NamespacedName, obj runtime.Object) (bool, error) {
+	if err := er.client.Get(context.TODO(), key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Blocked condition", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster      *api.Elasticsearch
+		storageClass = "gp2"
+		storageSize  = resource.MustParse("10Gi")
+	)
+
+	newRequest := func(objs ...runtime.Object) *ElasticsearchRequest {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		return &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, append(objs, cluster)...),
+			cluster: cluster,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+						NodeCount: 1,
+						Storage:   api.ElasticsearchStorageSpec{StorageClassName: &storageClass, Size: &storageSize},
+					},
+				},
+			},
+		}
+	})
+
+	It("should not be blocked when all dependencies exist", func() {
+		request := newRequest(
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp2"}},
+		)
+
+		Expect(request.CheckBlockingDependencies()).To(Succeed())
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.Blocked)
+		Expect(condition).To(BeNil())
+	})
+
+	It("should name the missing secret and storage class", func() {
+		request := newRequest()
+
+		err := request.CheckBlockingDependencies()
+		Expect(IsUserError(err)).To(BeTrue())
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.Blocked)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(api.BlockedReasonMissingSecret))
+		Expect(condition.Message).To(Equal("Waiting for secret openshift-logging/elasticsearch; Waiting for storage class gp2"))
+	})
+
+	It("should report image pull failures of the nodes", func() {
+		cluster.Status.Nodes = []api.ElasticsearchNodeStatus{
+			{
+				DeploymentName: "elasticsearch-cdm-1",
+				Conditions: api.ClusterConditions{
+					{
+						Type:    api.ESContainerWaiting,
+						Status:  v1.ConditionTrue,
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "elasticsearch:missing"`,
+					},
+				},
+			},
+		}
+		request := newRequest(
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp2"}},
+		)
+
+		Expect(request.CheckBlockingDependencies()).To(Succeed())
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.Blocked)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(api.BlockedReasonImagePullFailure))
+		Expect(condition.Message).To(Equal(`Waiting for the image of node elasticsearch-cdm-1: Back-off pulling image "elasticsearch:missing"`))
+	})
+})
@@ -0,0 +1,161 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// ReconcileCertificateExpiry reports the expiry of the certificates of the secret of the cluster
+// and restarts the nodes once the first certificate expires within the days of the cert rotation
+func (er *ElasticsearchRequest) ReconcileCertificateExpiry() error {
+	return er.reconcileCertificateExpiry(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileCertificateExpiry(now time.Time) error {
+	cluster := er.cluster
+
+	secret := &v1.Secret{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the missing secret is reported by the Blocked condition
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to get cluster secret")
+	}
+
+	status := &api.CertificatesStatus{}
+	if cluster.Status.Certificates != nil {
+		status.RedeployedFor = cluster.Status.Certificates.RedeployedFor
+	}
+
+	expiries := map[string]time.Time{}
+	invalid := []string{}
+	for _, key := range certificateKeys() {
+		if len(secret.Data[key]) == 0 {
+			continue
+		}
+		certificate := firstExpiringCertificate(secret.Data[key])
+		if certificate == nil {
+			invalid = append(invalid, key)
+			continue
+		}
+		expiries[key] = certificate.NotAfter
+		status.Certificates = append(status.Certificates, api.CertificateStatus{
+			Key:      key,
+			Subject:  certificate.Subject.String(),
+			NotAfter: metav1.NewTime(certificate.NotAfter.UTC()),
+		})
+	}
+	if len(invalid) > 0 {
+		status.Message = fmt.Sprintf("Unable to parse the certificates of the keys %s", strings.Join(invalid, ", "))
+	}
+	metrics.SetCertificateExpiries(cluster.Name, cluster.Namespace, expiries)
+
+	redeploy := false
+	if spec := certRotation(cluster); spec != nil && len(status.Certificates) > 0 {
+		expiry := status.Certificates[0].NotAfter
+		for _, certificate := range status.Certificates[1:] {
+			if certificate.NotAfter.Before(&expiry) {
+				expiry = certificate.NotAfter
+			}
+		}
+
+		window := time.Duration(spec.RedeployBeforeDays) * 24 * time.Hour
+		if now.Add(window).After(expiry.Time) && (status.RedeployedFor == nil || !status.RedeployedFor.Equal(&expiry)) {
+			message := fmt.Sprintf("Restarting the nodes to load the certificates of secret %s before they expire on %s",
+				cluster.Name, expiry.UTC().Format(time.RFC3339))
+			er.L().Info(message)
+			if er.recorder != nil {
+				er.recorder.Event(cluster, v1.EventTypeWarning, "CertificatesExpiring", message)
+			}
+			status.RedeployedFor = &expiry
+			redeploy = true
+		}
+	}
+
+	return er.updateCertificatesStatus(status, redeploy)
+}
+
+// certificateKeys returns the keys of the certificates of the secret of the cluster
+func certificateKeys() []string {
+	keys := []string{"admin-ca"}
+	for _, certificate := range clusterCertificates {
+		keys = append(keys, certificate.certKey)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func certRotation(cluster *api.Elasticsearch) *api.CertRotationSpec {
+	if cluster.Spec.Security == nil {
+		return nil
+	}
+	return cluster.Spec.Security.CertRotation
+}
+
+// firstExpiringCertificate returns the certificate of the PEM bundle expiring first or nil if the
+// bundle holds no certificate
+func firstExpiringCertificate(bundle []byte) *x509.Certificate {
+	var first *x509.Certificate
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return first
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if first == nil || certificate.NotAfter.Before(first.NotAfter) {
+			first = certificate
+		}
+	}
+}
+
+// updateCertificatesStatus updates the certificates of the status and schedules the cert redeploy
+// of every node if requested
+func (er *ElasticsearchRequest) updateCertificatesStatus(status *api.CertificatesStatus, redeploy bool) error {
+	cluster := er.cluster
+
+	if !redeploy && reflect.DeepEqual(cluster.Status.Certificates, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if !redeploy && reflect.DeepEqual(cluster.Status.Certificates, status) {
+			return nil
+		}
+
+		cluster.Status.Certificates = status
+		if redeploy {
+			for i := range cluster.Status.Nodes {
+				cluster.Status.Nodes[i].UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionTrue
+			}
+		}
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update certificates status")
+}
@@ -0,0 +1,114 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("certificate expiry", func() {
+	defer GinkgoRecover()
+
+	var (
+		request  *ElasticsearchRequest
+		cluster  *api.Elasticsearch
+		recorder *record.FakeRecorder
+		now      = time.Now().Truncate(time.Second)
+	)
+
+	selfSigned := func(commonName string, notAfter time.Time) []byte {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: commonName, OrganizationalUnit: []string{"OpenShift"}, Organization: []string{"Logging"}},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     notAfter,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Security: &api.SecuritySpec{
+					CertRotation: &api.CertRotationSpec{RedeployBeforeDays: 30},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{DeploymentName: "elasticsearch-cdm-abc-1"},
+					{StatefulSetName: "elasticsearch-cdm-def"},
+				},
+			},
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Data: map[string][]byte{
+				"admin-ca":          selfSigned("ca", now.Add(365*24*time.Hour)),
+				"elasticsearch.crt": selfSigned("elasticsearch", now.Add(10*24*time.Hour)),
+				"logging-es.crt":    []byte("not a certificate"),
+			},
+		}
+		recorder = record.NewFakeRecorder(5)
+		request = &ElasticsearchRequest{
+			client:   fake.NewFakeClientWithScheme(s, cluster, secret),
+			cluster:  cluster,
+			recorder: recorder,
+		}
+	})
+
+	It("should report the expiry of the certificates of the secret", func() {
+		cluster.Spec.Security = nil
+
+		Expect(request.reconcileCertificateExpiry(now)).To(Succeed())
+		Expect(cluster.Status.Certificates.Certificates).To(Equal([]api.CertificateStatus{
+			{Key: "admin-ca", Subject: "CN=ca,OU=OpenShift,O=Logging", NotAfter: metav1.NewTime(now.Add(365 * 24 * time.Hour).UTC())},
+			{Key: "elasticsearch.crt", Subject: "CN=elasticsearch,OU=OpenShift,O=Logging", NotAfter: metav1.NewTime(now.Add(10 * 24 * time.Hour).UTC())},
+		}))
+		Expect(cluster.Status.Certificates.Message).To(Equal("Unable to parse the certificates of the keys logging-es.crt"))
+		Expect(cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy).To(BeEmpty())
+	})
+
+	It("should restart the nodes once before the first certificate expires", func() {
+		Expect(request.reconcileCertificateExpiry(now)).To(Succeed())
+		Expect(cluster.Status.Certificates.RedeployedFor.Time).To(BeTemporally("==", now.Add(10*24*time.Hour)))
+		for _, node := range cluster.Status.Nodes {
+			Expect(node.UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionTrue))
+		}
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning CertificatesExpiring Restarting the nodes")))
+
+		// the restart of the nodes completed
+		for i := range cluster.Status.Nodes {
+			cluster.Status.Nodes[i].UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionFalse
+		}
+		Expect(request.client.Status().Update(context.TODO(), cluster)).To(Succeed())
+
+		Expect(request.reconcileCertificateExpiry(now.Add(time.Hour))).To(Succeed())
+		Expect(cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionFalse))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
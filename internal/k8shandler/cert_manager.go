@@ -0,0 +1,261 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	certManagerAPIVersion = "cert-manager.io/v1"
+	certManagerKind       = "Certificate"
+	certManagerGroup      = "cert-manager.io"
+	certManagerIssuerKind = "Issuer"
+)
+
+// clusterCertificate is a certificate requested from cert-manager and the keys of the secret of
+// the cluster its certificate and key are copied to
+type clusterCertificate struct {
+	suffix     string
+	commonName string
+	server     bool
+	client     bool
+	certKey    string
+	keyKey     string
+}
+
+// clusterCertificates are the certificates of the secret of the cluster. The subjects match the
+// admin and node DNs of the security configuration
+var clusterCertificates = []clusterCertificate{
+	{suffix: "transport", commonName: "elasticsearch", server: true, client: true, certKey: "elasticsearch.crt", keyKey: "elasticsearch.key"},
+	{suffix: "http", commonName: "elasticsearch", server: true, certKey: "logging-es.crt", keyKey: "logging-es.key"},
+	{suffix: "admin", commonName: "system.admin", client: true, certKey: "admin-cert", keyKey: "admin-key"},
+}
+
+func certificateName(cluster *api.Elasticsearch, certificate clusterCertificate) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, certificate.suffix)
+}
+
+func certificateSecretName(cluster *api.Elasticsearch, certificate clusterCertificate) string {
+	return fmt.Sprintf("%s-%s-tls", cluster.Name, certificate.suffix)
+}
+
+// ReconcileCertManagerCertificates requests the certificates of the cluster from cert-manager and
+// assembles the secret of the cluster from the issued ones. Renewed certificates update the secret
+// which schedules the cert redeploy of the nodes
+func (er *ElasticsearchRequest) ReconcileCertManagerCertificates() error {
+	cluster := er.cluster
+	spec := cluster.Spec.CertManager
+
+	if spec == nil {
+		return nil
+	}
+
+	for _, certificate := range clusterCertificates {
+		if err := er.createOrUpdateCertificate(spec, certificate); err != nil {
+			return err
+		}
+	}
+
+	data := map[string][]byte{}
+	for _, certificate := range clusterCertificates {
+		issued, err := er.getIssuedCertificate(certificate)
+		if err != nil {
+			return err
+		}
+		if issued == nil {
+			er.L().Info("Waiting for cert-manager to issue the certificate", "certificate", certificateName(cluster, certificate))
+			return nil
+		}
+
+		data[certificate.certKey] = issued.Data[v1.TLSCertKey]
+		data[certificate.keyKey] = issued.Data[v1.TLSPrivateKeyKey]
+		data["admin-ca"] = issued.Data["ca.crt"]
+	}
+
+	return er.updateClusterSecret(data)
+}
+
+// newCertificate returns the cert-manager Certificate of the cluster
+func newCertificate(cluster *api.Elasticsearch, spec *api.CertManagerSpec, certificate clusterCertificate) *unstructured.Unstructured {
+	issuerKind := spec.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = certManagerIssuerKind
+	}
+	issuerGroup := spec.IssuerRef.Group
+	if issuerGroup == "" {
+		issuerGroup = certManagerGroup
+	}
+
+	usages := []interface{}{"digital signature", "key encipherment"}
+	if certificate.server {
+		usages = append(usages, "server auth")
+	}
+	if certificate.client {
+		usages = append(usages, "client auth")
+	}
+
+	certSpec := map[string]interface{}{
+		"secretName": certificateSecretName(cluster, certificate),
+		"commonName": certificate.commonName,
+		"subject": map[string]interface{}{
+			"organizationalUnits": []interface{}{"OpenShift"},
+			"organizations":       []interface{}{"Logging"},
+		},
+		"usages": usages,
+		"privateKey": map[string]interface{}{
+			"algorithm": "RSA",
+			"encoding":  "PKCS8",
+			"size":      int64(4096),
+		},
+		"issuerRef": map[string]interface{}{
+			"name":  spec.IssuerRef.Name,
+			"kind":  issuerKind,
+			"group": issuerGroup,
+		},
+	}
+	if certificate.server {
+		certSpec["dnsNames"] = []interface{}{
+			"localhost",
+			cluster.Name,
+			fmt.Sprintf("%s.%s.svc", cluster.Name, cluster.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", cluster.Name, cluster.Namespace),
+			fmt.Sprintf("%s-cluster", cluster.Name),
+			fmt.Sprintf("%s-cluster.%s.svc", cluster.Name, cluster.Namespace),
+			fmt.Sprintf("%s-cluster.%s.svc.cluster.local", cluster.Name, cluster.Namespace),
+		}
+	}
+	if spec.Duration != nil && spec.Duration.Duration > 0 {
+		certSpec["duration"] = spec.Duration.Duration.String()
+	}
+	if spec.RenewBefore != nil && spec.RenewBefore.Duration > 0 {
+		certSpec["renewBefore"] = spec.RenewBefore.Duration.String()
+	}
+
+	object := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": certSpec,
+		},
+	}
+	object.SetAPIVersion(certManagerAPIVersion)
+	object.SetKind(certManagerKind)
+	object.SetName(certificateName(cluster, certificate))
+	object.SetNamespace(cluster.Namespace)
+	object.SetLabels(map[string]string{
+		"cluster-name": cluster.Name,
+	})
+	cluster.AddOwnerRefTo(object)
+	return object
+}
+
+func (er *ElasticsearchRequest) createOrUpdateCertificate(spec *api.CertManagerSpec, certificate clusterCertificate) error {
+	desired := newCertificate(er.cluster, spec, certificate)
+
+	current := &unstructured.Unstructured{}
+	current.SetAPIVersion(certManagerAPIVersion)
+	current.SetKind(certManagerKind)
+	err := er.client.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	if apierrors.IsNotFound(err) {
+		er.L().Info("Requesting certificate from cert-manager", "certificate", desired.GetName())
+		if err := er.client.Create(context.TODO(), desired); err != nil {
+			return kverrors.Wrap(err, "failed to create certificate",
+				"certificate", desired.GetName())
+		}
+		return nil
+	}
+	if err != nil {
+		return kverrors.Wrap(err, "failed to get certificate",
+			"certificate", desired.GetName())
+	}
+
+	if reflect.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+
+	current.Object["spec"] = desired.Object["spec"]
+	if err := er.client.Update(context.TODO(), current); err != nil {
+		return kverrors.Wrap(err, "failed to update certificate",
+			"certificate", desired.GetName())
+	}
+	return nil
+}
+
+// getIssuedCertificate returns the secret of the certificate or nil if it is not issued yet
+func (er *ElasticsearchRequest) getIssuedCertificate(certificate clusterCertificate) (*v1.Secret, error) {
+	name := certificateSecretName(er.cluster, certificate)
+
+	secret := &v1.Secret{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: er.cluster.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get certificate secret",
+			"secret", name)
+	}
+
+	for _, key := range []string{v1.TLSCertKey, v1.TLSPrivateKeyKey, "ca.crt"} {
+		if len(secret.Data[key]) == 0 {
+			return nil, nil
+		}
+	}
+	return secret, nil
+}
+
+// updateClusterSecret sets the keys of the secret of the cluster. The other keys of an existing
+// secret are kept
+func (er *ElasticsearchRequest) updateClusterSecret(data map[string][]byte) error {
+	cluster := er.cluster
+
+	secret := &v1.Secret{}
+	err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+			Type: v1.SecretTypeOpaque,
+			Data: data,
+		}
+		cluster.AddOwnerRefTo(secret)
+
+		er.L().Info("Creating the secret of the cluster from the certificates")
+		if err := er.client.Create(context.TODO(), secret); err != nil {
+			return kverrors.Wrap(err, "failed to create cluster secret")
+		}
+		return nil
+	}
+	if err != nil {
+		return kverrors.Wrap(err, "failed to get cluster secret")
+	}
+
+	changed := false
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for key, value := range data {
+		if !reflect.DeepEqual(secret.Data[key], value) {
+			secret.Data[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	er.L().Info("Updating the secret of the cluster from the changed certificates")
+	if err := er.client.Update(context.TODO(), secret); err != nil {
+		return kverrors.Wrap(err, "failed to update cluster secret")
+	}
+
+	// the nodes are restarted with the changed certificates
+	return SecretReconcile(cluster, er.client)
+}
@@ -0,0 +1,121 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("cert-manager certificates", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	issue := func(suffix, cert string) {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-" + suffix + "-tls", Namespace: "openshift-logging"},
+			Data: map[string][]byte{
+				"tls.crt": []byte(cert),
+				"tls.key": []byte(suffix + "-key"),
+				"ca.crt":  []byte("ca"),
+			},
+		}
+		current := &v1.Secret{}
+		if err := request.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, current); err == nil {
+			current.Data = secret.Data
+			Expect(request.client.Update(context.TODO(), current)).To(Succeed())
+			return
+		}
+		Expect(request.client.Create(context.TODO(), secret)).To(Succeed())
+	}
+
+	getCertificate := func(suffix string) *unstructured.Unstructured {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetAPIVersion(certManagerAPIVersion)
+		certificate.SetKind(certManagerKind)
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-" + suffix, Namespace: "openshift-logging"}, certificate)).To(Succeed())
+		return certificate
+	}
+
+	nestedString := func(certificate *unstructured.Unstructured, fields ...string) string {
+		value, _, _ := unstructured.NestedString(certificate.Object, fields...)
+		return value
+	}
+
+	getClusterSecret := func() *v1.Secret {
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, secret)).To(Succeed())
+		return secret
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				CertManager: &api.CertManagerSpec{
+					IssuerRef: api.CertManagerIssuerReference{Name: "logging-ca", Kind: "ClusterIssuer"},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+	})
+
+	It("should request the certificates of the cluster from the issuer", func() {
+		Expect(request.ReconcileCertManagerCertificates()).To(Succeed())
+
+		transport := getCertificate("transport")
+		Expect(nestedString(transport, "spec", "secretName")).To(Equal("elasticsearch-transport-tls"))
+		Expect(nestedString(transport, "spec", "commonName")).To(Equal("elasticsearch"))
+		Expect(nestedString(transport, "spec", "issuerRef", "kind")).To(Equal("ClusterIssuer"))
+		usages, _, _ := unstructured.NestedStringSlice(transport.Object, "spec", "usages")
+		Expect(usages).To(ConsistOf("digital signature", "key encipherment", "server auth", "client auth"))
+
+		admin := getCertificate("admin")
+		Expect(nestedString(admin, "spec", "commonName")).To(Equal("system.admin"))
+		_, found, _ := unstructured.NestedSlice(admin.Object, "spec", "dnsNames")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should assemble the secret of the cluster once every certificate is issued", func() {
+		issue("transport", "transport-cert")
+		issue("http", "http-cert")
+		Expect(request.ReconcileCertManagerCertificates()).To(Succeed())
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, &v1.Secret{})).ToNot(Succeed())
+
+		issue("admin", "admin-cert")
+		Expect(request.ReconcileCertManagerCertificates()).To(Succeed())
+		Expect(getClusterSecret().Data).To(Equal(map[string][]byte{
+			"elasticsearch.crt": []byte("transport-cert"),
+			"elasticsearch.key": []byte("transport-key"),
+			"logging-es.crt":    []byte("http-cert"),
+			"logging-es.key":    []byte("http-key"),
+			"admin-cert":        []byte("admin-cert"),
+			"admin-key":         []byte("admin-key"),
+			"admin-ca":          []byte("ca"),
+		}))
+
+		issue("transport", "renewed-cert")
+		Expect(request.ReconcileCertManagerCertificates()).To(Succeed())
+		Expect(getClusterSecret().Data["elasticsearch.crt"]).To(Equal([]byte("renewed-cert")))
+	})
+})
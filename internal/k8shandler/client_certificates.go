@@ -0,0 +1,333 @@
+package k8shandler
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clientCertificateFinalizer       = "logging.openshift.io/elasticsearch-client-certificates"
+	clientCertificateClusterLabel    = "elasticsearch.openshift.io/client-certificate-cluster"
+	clientCertificateNamespaceLabel  = "elasticsearch.openshift.io/client-certificate-namespace"
+	defaultClientCertificateValidity = 90 * 24 * time.Hour
+)
+
+// certificateAuthority is the CA signing the client certificates
+type certificateAuthority struct {
+	certPEM []byte
+	cert    *x509.Certificate
+	key     crypto.Signer
+}
+
+// RevokeClientCertificates deletes the Secrets of the client certificates of the cluster once it
+// is deleted and releases the deletion of the cluster
+func RevokeClientCertificates(cluster *api.Elasticsearch, requestClient client.Client) error {
+	er := &ElasticsearchRequest{
+		client:  requestClient,
+		cluster: cluster,
+		ll:      log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace),
+	}
+
+	if !utils.ContainsString(cluster.GetFinalizers(), clientCertificateFinalizer) {
+		return nil
+	}
+
+	if err := er.deleteClientCertificates(nil); err != nil {
+		return err
+	}
+	return er.updateFinalizer(clientCertificateFinalizer, false)
+}
+
+// ReconcileClientCertificates issues the client certificates of the cluster into their Secrets,
+// renews them once two thirds of their validity passed and reissues them for a changed CA
+func (er *ElasticsearchRequest) ReconcileClientCertificates() error {
+	return er.reconcileClientCertificates(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileClientCertificates(now time.Time) error {
+	cluster := er.cluster
+	spec := cluster.Spec.ClientCertificates
+
+	if spec == nil || len(spec.Certificates) == 0 {
+		if err := er.deleteClientCertificates(nil); err != nil {
+			return err
+		}
+		return er.updateFinalizer(clientCertificateFinalizer, false)
+	}
+
+	// the Secrets in other namespaces are deleted with the cluster by the finalizer
+	if err := er.updateFinalizer(clientCertificateFinalizer, true); err != nil {
+		return err
+	}
+
+	ca, err := er.getCertificateAuthority(spec.CASecretName)
+	if err != nil || ca == nil {
+		return err
+	}
+
+	validity := defaultClientCertificateValidity
+	if spec.Validity != nil && spec.Validity.Duration > 0 {
+		validity = spec.Validity.Duration
+	}
+
+	desired := map[types.NamespacedName]bool{}
+	for _, certificate := range spec.Certificates {
+		key := clientCertificateSecretKey(cluster, certificate)
+		if desired[key] {
+			er.L().Info("Skipping client certificate delivered to the same secret", "secret", key.String())
+			continue
+		}
+		desired[key] = true
+
+		if err := er.reconcileClientCertificate(certificate, key, ca, validity, now); err != nil {
+			return err
+		}
+	}
+
+	return er.deleteClientCertificates(desired)
+}
+
+func (er *ElasticsearchRequest) reconcileClientCertificate(spec api.ClientCertificateSpec, key types.NamespacedName, ca *certificateAuthority, validity time.Duration, now time.Time) error {
+	cluster := er.cluster
+
+	secret := &v1.Secret{}
+	err := er.client.Get(context.TODO(), key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to get client certificate secret",
+			"secret", key.String())
+	}
+	exists := err == nil
+
+	if exists {
+		labels := secret.GetLabels()
+		if labels[clientCertificateClusterLabel] != cluster.Name || labels[clientCertificateNamespaceLabel] != cluster.Namespace {
+			return kverrors.New("client certificate secret is not managed by the cluster",
+				"secret", key.String())
+		}
+		if clientCertificateCurrent(secret, spec, ca, validity, now) {
+			return nil
+		}
+	}
+
+	certPEM, keyPEM, err := issueClientCertificate(spec, ca, validity, now)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to issue client certificate",
+			"secret", key.String())
+	}
+
+	secret.Name = key.Name
+	secret.Namespace = key.Namespace
+	secret.Labels = map[string]string{
+		clientCertificateClusterLabel:   cluster.Name,
+		clientCertificateNamespaceLabel: cluster.Namespace,
+	}
+	secret.Type = v1.SecretTypeTLS
+	secret.Data = map[string][]byte{
+		v1.TLSCertKey:       certPEM,
+		v1.TLSPrivateKeyKey: keyPEM,
+		"ca.crt":            ca.certPEM,
+	}
+
+	er.L().Info("Issuing client certificate", "secret", key.String(), "subject", clientCertificateSubject(spec).String())
+	if exists {
+		err = er.client.Update(context.TODO(), secret)
+	} else {
+		err = er.client.Create(context.TODO(), secret)
+	}
+	return kverrors.Wrap(err, "failed to update client certificate secret",
+		"secret", key.String())
+}
+
+// deleteClientCertificates deletes the Secrets of the client certificates of the cluster which are
+// not desired
+func (er *ElasticsearchRequest) deleteClientCertificates(desired map[types.NamespacedName]bool) error {
+	cluster := er.cluster
+
+	secrets := &v1.SecretList{}
+	labels := client.MatchingLabels{
+		clientCertificateClusterLabel:   cluster.Name,
+		clientCertificateNamespaceLabel: cluster.Namespace,
+	}
+	if err := er.client.List(context.TODO(), secrets, labels); err != nil {
+		return kverrors.Wrap(err, "failed to list client certificate secrets")
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		key := types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}
+		if desired[key] {
+			continue
+		}
+		er.L().Info("Deleting client certificate", "secret", key.String())
+		if err := er.client.Delete(context.TODO(), secret); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete client certificate secret",
+				"secret", key.String())
+		}
+	}
+	return nil
+}
+
+// getCertificateAuthority returns the CA of the named Secret or nil if it does not exist, which
+// is reported as blocking dependency
+func (er *ElasticsearchRequest) getCertificateAuthority(name string) (*certificateAuthority, error) {
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Name: name, Namespace: er.cluster.Namespace}
+	if err := er.client.Get(context.TODO(), key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get client certificate CA secret",
+			"secret", name)
+	}
+
+	ca, err := parseCertificateAuthority(secret.Data["ca.crt"], secret.Data["ca.key"])
+	return ca, kverrors.Wrap(err, "invalid client certificate CA secret",
+		"secret", name)
+}
+
+func parseCertificateAuthority(certPEM, keyPEM []byte) (*certificateAuthority, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, kverrors.New("no PEM encoded certificate found in ca.crt")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		return nil, kverrors.New("no PEM encoded private key found in ca.key")
+	}
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificateAuthority{certPEM: certPEM, cert: cert, key: key}, nil
+}
+
+// parsePrivateKey parses a PKCS #8, PKCS #1 or SEC 1 encoded private key
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		}
+		return nil, kverrors.New("unsupported private key type in ca.key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, kverrors.New("unable to parse the private key in ca.key")
+}
+
+func clientCertificateSecretKey(cluster *api.Elasticsearch, spec api.ClientCertificateSpec) types.NamespacedName {
+	namespace := spec.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	return types.NamespacedName{Name: spec.SecretName, Namespace: namespace}
+}
+
+func clientCertificateSubject(spec api.ClientCertificateSpec) pkix.Name {
+	return pkix.Name{
+		CommonName:         spec.CommonName,
+		OrganizationalUnit: spec.OrganizationalUnits,
+		Organization:       spec.Organizations,
+	}
+}
+
+// clientCertificateCurrent returns true if the certificate of the Secret has the subject of the
+// spec, is signed by the CA and is within the first two thirds of its validity
+func clientCertificateCurrent(secret *v1.Secret, spec api.ClientCertificateSpec, ca *certificateAuthority, validity time.Duration, now time.Time) bool {
+	if !bytes.Equal(secret.Data["ca.crt"], ca.certPEM) || len(secret.Data[v1.TLSPrivateKeyKey]) == 0 {
+		return false
+	}
+
+	block, _ := pem.Decode(secret.Data[v1.TLSCertKey])
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	if cert.CheckSignatureFrom(ca.cert) != nil {
+		return false
+	}
+	if cert.Subject.String() != clientCertificateSubject(spec).String() {
+		return false
+	}
+
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	if lifetime > validity {
+		lifetime = validity
+	}
+	return now.Before(cert.NotAfter.Add(-lifetime / 3))
+}
+
+// issueClientCertificate returns a PEM encoded client certificate signed by the CA and its key
+func issueClientCertificate(spec api.ClientCertificateSpec, ca *certificateAuthority, validity time.Duration, now time.Time) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      clientCertificateSubject(spec),
+		// tolerate clocks of the nodes lagging behind
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
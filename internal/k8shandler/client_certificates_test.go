@@ -0,0 +1,157 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Client certificates", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     time.Time
+		key     = types.NamespacedName{Name: "app-es-client", Namespace: "app"}
+	)
+
+	newCASecret := func(commonName string) *v1.Secret {
+		caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(BeNil())
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: commonName},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(5 * 365 * 24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+		Expect(err).To(BeNil())
+		keyDER, err := x509.MarshalECPrivateKey(caKey)
+		Expect(err).To(BeNil())
+
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "logging-ca", Namespace: "openshift-logging"},
+			Data: map[string][]byte{
+				"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+				"ca.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			},
+		}
+	}
+
+	getSecret := func() *v1.Secret {
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), key, secret)).To(Succeed())
+		return secret
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		now = time.Now()
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				ClientCertificates: &api.ClientCertificatesSpec{
+					CASecretName: "logging-ca",
+					Certificates: []api.ClientCertificateSpec{
+						{
+							SecretName:          "app-es-client",
+							Namespace:           "app",
+							CommonName:          "app",
+							OrganizationalUnits: []string{"team-a"},
+							Organizations:       []string{"Logging"},
+						},
+					},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, newCASecret("logging-ca")),
+			cluster: cluster,
+		}
+	})
+
+	It("should deliver a certificate signed by the CA into the namespace of the application", func() {
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+		Expect(cluster.GetFinalizers()).To(ContainElement(clientCertificateFinalizer))
+
+		secret := getSecret()
+		Expect(secret.Type).To(Equal(v1.SecretTypeTLS))
+		Expect(secret.Data).To(HaveKey(v1.TLSPrivateKeyKey))
+
+		ca, err := request.getCertificateAuthority("logging-ca")
+		Expect(err).To(BeNil())
+		block, _ := pem.Decode(secret.Data[v1.TLSCertKey])
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).To(BeNil())
+		Expect(cert.CheckSignatureFrom(ca.cert)).To(Succeed())
+		Expect(cert.Subject.String()).To(Equal("CN=app,OU=team-a,O=Logging"))
+		Expect(cert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}))
+	})
+
+	It("should renew the certificate after two thirds of its validity", func() {
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+		issued := getSecret().Data[v1.TLSCertKey]
+
+		Expect(request.reconcileClientCertificates(now.Add(59 * 24 * time.Hour))).To(Succeed())
+		Expect(getSecret().Data[v1.TLSCertKey]).To(Equal(issued))
+
+		Expect(request.reconcileClientCertificates(now.Add(61 * 24 * time.Hour))).To(Succeed())
+		Expect(getSecret().Data[v1.TLSCertKey]).ToNot(Equal(issued))
+	})
+
+	It("should reissue the certificate for a rotated CA", func() {
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+
+		rotated := newCASecret("logging-ca-2")
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "logging-ca", Namespace: "openshift-logging"}, secret)).To(Succeed())
+		secret.Data = rotated.Data
+		Expect(request.client.Update(context.TODO(), secret)).To(Succeed())
+
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+		Expect(getSecret().Data["ca.crt"]).To(Equal(rotated.Data["ca.crt"]))
+	})
+
+	It("should not overwrite secrets it does not manage", func() {
+		Expect(request.client.Create(context.TODO(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		})).To(Succeed())
+
+		Expect(request.reconcileClientCertificates(now)).ToNot(Succeed())
+	})
+
+	It("should delete the secrets of removed certificates", func() {
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+
+		cluster.Spec.ClientCertificates.Certificates = nil
+		Expect(request.reconcileClientCertificates(now)).To(Succeed())
+		err := request.client.Get(context.TODO(), key, &v1.Secret{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(cluster.GetFinalizers()).ToNot(ContainElement(clientCertificateFinalizer))
+	})
+})
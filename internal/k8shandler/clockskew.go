@@ -0,0 +1,143 @@
+package k8shandler
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	defaultMaxClockSkew = 10 * time.Second
+	clockCheckName      = "clock-check"
+)
+
+// clockCheckScript compares the node clock with the Date header of the Kubernetes API server
+const clockCheckScript = `
+api_time=$(curl -s -k -I --max-time 10 https://kubernetes.default.svc/healthz | grep -i '^date:' | cut -d' ' -f2- | tr -d '\r')
+if [ -z "$api_time" ] ; then
+  echo "Unable to read the time of the Kubernetes API server, skipping the clock check"
+  exit 0
+fi
+skew=$(( $(date +%s) - $(date -d "$api_time" +%s) ))
+skew=${skew#-}
+if [ "$skew" -gt "$MAX_CLOCK_SKEW_SECONDS" ] ; then
+  echo "The clock of this node is ${skew}s off the Kubernetes API server which exceeds ${MAX_CLOCK_SKEW_SECONDS}s. Verify the NTP synchronization of the node"
+  exit 1
+fi
+echo "The clock of this node is ${skew}s off the Kubernetes API server"
+`
+
+func maxClockSkew(spec *api.ClockSkewSpec) time.Duration {
+	if spec != nil && spec.MaxSkew != nil && spec.MaxSkew.Duration > 0 {
+		return spec.MaxSkew.Duration
+	}
+	return defaultMaxClockSkew
+}
+
+// CheckClockSkew compares the clocks of the nodes and flags the cluster with the
+// ClockSkewDetected condition when a node deviates from the others by more than the maximum skew
+func (er *ElasticsearchRequest) CheckClockSkew() error {
+	cluster := er.cluster
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	timestamps, err := er.esClient.GetNodeTimestamps()
+	if err != nil {
+		return err
+	}
+
+	maxSkew := maxClockSkew(cluster.Spec.ClockSkew)
+	offsets := clockOffsets(timestamps)
+
+	var skewed []string
+	for _, node := range sortedNodeNames(offsets) {
+		offset := offsets[node]
+		metrics.SetNodeClockSkew(cluster.Name, cluster.Namespace, node, offset)
+		if offset > maxSkew || offset < -maxSkew {
+			skewed = append(skewed, fmt.Sprintf("%s (%s)", node, offset.Round(time.Second)))
+		}
+	}
+
+	value := v1.ConditionFalse
+	message := ""
+	if len(skewed) > 0 {
+		value = v1.ConditionTrue
+		message = fmt.Sprintf("The clock of nodes %s deviates by more than %s from the other nodes which breaks TLS and scheduled tasks. Verify the NTP synchronization of the Kubernetes nodes",
+			strings.Join(skewed, ", "), maxSkew)
+		er.L().Info("Clock skew detected", "nodes", skewed, "max_skew", maxSkew.String())
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.ClockSkewDetected,
+				Status:  value,
+				Reason:  "ThresholdExceeded",
+				Message: message,
+			})
+		},
+		er.client,
+	)
+}
+
+// clockOffsets returns the offset of each node clock from the median clock of all nodes
+func clockOffsets(timestamps map[string]time.Time) map[string]time.Duration {
+	offsets := map[string]time.Duration{}
+	if len(timestamps) == 0 {
+		return offsets
+	}
+
+	times := make([]time.Time, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		times = append(times, timestamp)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	median := times[len(times)/2]
+
+	for node, timestamp := range timestamps {
+		offsets[node] = timestamp.Sub(median)
+	}
+	return offsets
+}
+
+func sortedNodeNames(offsets map[string]time.Duration) []string {
+	names := make([]string, 0, len(offsets))
+	for name := range offsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newClockCheckContainer returns an init container failing when the node clock exceeds the maximum skew
+func newClockCheckContainer(imageName string, spec *api.ClockSkewSpec) v1.Container {
+	return v1.Container{
+		Name:            clockCheckName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", clockCheckScript},
+		Env: []v1.EnvVar{
+			{
+				Name:  "MAX_CLOCK_SKEW_SECONDS",
+				Value: strconv.Itoa(int(maxClockSkew(spec).Seconds())),
+			},
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+}
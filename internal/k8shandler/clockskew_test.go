@@ -0,0 +1,49 @@
+package k8shandler
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("Clock skew", func() {
+	defer GinkgoRecover()
+
+	Describe("#clockOffsets", func() {
+		It("should measure each node against the median clock", func() {
+			now := time.Now()
+			offsets := clockOffsets(map[string]time.Time{
+				"node-a": now,
+				"node-b": now.Add(500 * time.Millisecond),
+				"node-c": now.Add(-30 * time.Second),
+			})
+
+			Expect(offsets).To(Equal(map[string]time.Duration{
+				"node-a": 0,
+				"node-b": 500 * time.Millisecond,
+				"node-c": -30 * time.Second,
+			}))
+		})
+	})
+
+	Describe("#newPodTemplateSpec", func() {
+		It("should not verify the clock on startup by default", func() {
+			template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "cluster", namespace: "namespace"})
+			Expect(template.Spec.InitContainers).To(BeEmpty())
+		})
+
+		It("should add the clock check init container when requested", func() {
+			template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+				clusterName: "cluster",
+				namespace:   "namespace",
+				clockSkew:   &api.ClockSkewSpec{VerifyOnStartup: true},
+			})
+			Expect(template.Spec.InitContainers).To(HaveLen(1))
+			Expect(template.Spec.InitContainers[0].Name).To(Equal(clockCheckName))
+			Expect(template.Spec.InitContainers[0].Env[0].Value).To(Equal("10"))
+		})
+	})
+})
@@ -3,12 +3,16 @@ package k8shandler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ViaQ/logerr/log"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
 	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 
@@ -26,6 +30,14 @@ var aliasNeededMap map[string]bool
 
 func FlushNodes(clusterName, namespace string) {
 	nodes[nodeMapKey(clusterName, namespace)] = []NodeTypeInterface{}
+	elasticsearch.StopWatchingClusterHealth(clusterName, namespace)
+	elasticsearch.ForgetHTTPTLS(clusterName, namespace)
+	elasticsearch.ForgetFIPSMode(clusterName, namespace)
+	elasticsearch.ForgetCredentials(clusterName, namespace)
+	forgetShardSample(clusterName, namespace)
+	metrics.SetNodeReplicaGaps(clusterName, namespace, nil)
+	metrics.SetNodeUpgradePhases(clusterName, namespace, nil)
+	metrics.SetCertificateExpiries(clusterName, namespace, nil)
 }
 
 func nodeMapKey(clusterName, namespace string) string {
@@ -71,8 +83,17 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 	}
 
 	certRestartNodes := er.getScheduledCertRedeployNodes()
+
+	// disruptive operations wait for the other operators of the logging stack to complete theirs
+	pending := len(certRestartNodes) > 0 || er.getNodeUpgradeInProgress() != nil ||
+		len(er.getScheduledUpgradeNodes()) > 0 || len(er.getScheduledRedeployNodes()) > 0
+	disruptionAllowed, err := er.coordinateDisruption(pending, time.Now())
+	if err != nil {
+		return err
+	}
+
 	stillRecovering := containsClusterCondition(api.Recovering, v1.ConditionTrue, &er.cluster.Status)
-	if len(certRestartNodes) > 0 || stillRecovering {
+	if (len(certRestartNodes) > 0 && disruptionAllowed) || stillRecovering {
 		if err := er.PerformFullClusterCertRestart(certRestartNodes); err != nil {
 			ll.Error(err, "unable to complete full cluster restart")
 			return er.UpdateClusterStatus()
@@ -96,18 +117,24 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 
 			// update scheduled nodes since we were able to complete upgrade for inProgressNode
 			scheduledNodes = er.getScheduledUpgradeNodes()
+			if err := er.advanceDeploymentRollout(inProgressNode, scheduledNodes); err != nil {
+				ll.Error(err, "unable to advance deployment rollout", "node", inProgressNode.name())
+			}
 		} else {
 			if err := er.PerformNodeRestart(inProgressNode); err != nil {
 				ll.Error(err, "unable to restart node", "node", inProgressNode.name())
 				return er.UpdateClusterStatus()
 			}
+			if err := er.advanceDeploymentRollout(inProgressNode, er.getScheduledRedeployNodes()); err != nil {
+				ll.Error(err, "unable to advance deployment rollout", "node", inProgressNode.name())
+			}
 		}
 
 		_ = er.UpdateClusterStatus()
 	}
 
 	// We didn't have any in progress, but we have ones scheduled to be updated
-	if len(scheduledNodes) > 0 {
+	if len(scheduledNodes) > 0 && disruptionAllowed {
 
 		// get the current ES version
 		version, err := esClient.GetLowestClusterVersion()
@@ -136,6 +163,18 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 		_ = er.UpdateClusterStatus()
 	}
 
+	// restart the nodes for the changes of their configuration once their updates completed
+	if er.getNodeUpgradeInProgress() == nil && len(er.getScheduledUpgradeNodes()) == 0 && disruptionAllowed {
+		if redeployNodes := er.getScheduledRedeployNodes(); len(redeployNodes) > 0 {
+			if err := er.PerformConfigRestart(redeployNodes); err != nil {
+				ll.Error(err, "unable to restart nodes for the changed configuration")
+				return er.UpdateClusterStatus()
+			}
+
+			_ = er.UpdateClusterStatus()
+		}
+	}
+
 	if er.getNodeUpgradeInProgress() == nil {
 		// We have no updates or restarts in progress
 		// create any nodes we are missing and perform any required operations to ensure state
@@ -277,6 +316,8 @@ func (er *ElasticsearchRequest) setUUID(index int, uuid string) {
 	}
 }
 
+// populateNodes builds the nodes of the cluster from spec.nodes and deletes the nodes no longer in
+// the spec. Changes to the nodes of an upgrade in flight are queued as described in upgrade_queue.go
 func (er *ElasticsearchRequest) populateNodes() error {
 	if err := er.recoverOrphanedCluster(); err != nil {
 		return err
@@ -290,21 +331,68 @@ func (er *ElasticsearchRequest) populateNodes() error {
 	cluster := er.cluster
 	currentNodes := []NodeTypeInterface{}
 
+	// changes to the nodes are queued while an upgrade is in flight
+	inFlight := er.inFlightNodeNames()
+	queued := []string{}
+	addNode := func(nodeTypeInterface NodeTypeInterface) {
+		nodeIndex, ok := containsNodeTypeInterface(nodeTypeInterface, nodes[nodeMapKey(cluster.Name, cluster.Namespace)])
+		if !ok {
+			// nodes are only created once no upgrade is in flight
+			if index, _ := getNodeStatus(nodeTypeInterface.name(), &cluster.Status); inFlight.Len() > 0 && index == NotFoundIndex {
+				queued = append(queued, fmt.Sprintf("add %s", nodeTypeInterface.name()))
+			}
+			currentNodes = append(currentNodes, nodeTypeInterface)
+			return
+		}
+
+		existing := nodes[nodeMapKey(cluster.Name, cluster.Namespace)][nodeIndex]
+		if inFlight.Has(existing.name()) {
+			if desiredNodeChanged(existing, nodeTypeInterface) {
+				queued = append(queued, fmt.Sprintf("update %s", existing.name()))
+			}
+		} else {
+			existing.updateReference(nodeTypeInterface)
+		}
+		currentNodes = append(currentNodes, existing)
+	}
+
 	// get list of client only nodes, and collapse node info into the node (self field) if needed
-	for _, node := range cluster.Spec.Nodes {
+	for i, node := range cluster.Spec.Nodes {
+		node.NodeCount += backlogExtraNodes(cluster, i)
+
 		// build the NodeTypeInterface list
-		for _, nodeTypeInterface := range er.GetNodeTypeInterface(*node.GenUUID, node) {
-
-			nodeIndex, ok := containsNodeTypeInterface(nodeTypeInterface, nodes[nodeMapKey(cluster.Name, cluster.Namespace)])
-			if !ok {
-				currentNodes = append(currentNodes, nodeTypeInterface)
-			} else {
-				nodes[nodeMapKey(cluster.Name, cluster.Namespace)][nodeIndex].updateReference(nodeTypeInterface)
-				currentNodes = append(currentNodes, nodes[nodeMapKey(cluster.Name, cluster.Namespace)][nodeIndex])
+		nodeTypeInterfaces, err := er.GetNodeTypeInterface(*node.GenUUID, node)
+		if err != nil {
+			return err
+		}
+		for _, nodeTypeInterface := range nodeTypeInterfaces {
+			addNode(nodeTypeInterface)
+		}
+
+		// keep the deployments of data nodes migrating to a StatefulSet until their shards are moved
+		migratedNodes, err := er.getMigratedNodeTypeInterfaces(*node.GenUUID, node)
+		if err != nil {
+			return err
+		}
+		for _, nodeTypeInterface := range migratedNodes {
+			addNode(nodeTypeInterface)
+		}
+	}
+
+	// the removed nodes are kept until the upgrade completes
+	if inFlight.Len() > 0 {
+		for _, node := range nodes[nodeMapKey(cluster.Name, cluster.Namespace)] {
+			if _, ok := containsNodeTypeInterface(node, currentNodes); !ok {
+				queued = append(queued, fmt.Sprintf("remove %s", node.name()))
+				currentNodes = append(currentNodes, node)
 			}
 		}
 	}
 
+	if err := er.updateSpecChangeQueuedCondition(inFlight, queued); err != nil {
+		return err
+	}
+
 	minMasterUpdated := false
 
 	// we want to only keep nodes that were generated and purge/delete any other ones...
@@ -317,7 +405,7 @@ func (er *ElasticsearchRequest) populateNodes() error {
 					minMasterUpdated = true
 				}
 			}
-			if err := node.delete(); err != nil {
+			if err := node.delete(); err != nil && !apierrors.IsNotFound(err) {
 				log.Error(err, "unable to delete node")
 			}
 
@@ -380,6 +468,7 @@ func addNodeState(node NodeTypeInterface, nodeStatus *api.ElasticsearchNodeStatu
 
 	nodeStatus.UpgradeStatus.ScheduledForUpgrade = nodeState.UpgradeStatus.ScheduledForUpgrade
 	nodeStatus.UpgradeStatus.ScheduledForCertRedeploy = nodeState.UpgradeStatus.ScheduledForCertRedeploy
+	nodeStatus.UpgradeStatus.ScheduledChanges = nodeState.UpgradeStatus.ScheduledChanges
 	nodeStatus.DeploymentName = nodeState.DeploymentName
 	nodeStatus.StatefulSetName = nodeState.StatefulSetName
 }
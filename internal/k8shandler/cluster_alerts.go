@@ -0,0 +1,180 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultRedFor           = "7m"
+	defaultYellowFor        = "20m"
+	defaultDiskWatermarkFor = "5m"
+	defaultPendingTasks     = 20
+	defaultPendingTasksFor  = "10m"
+	defaultHeapUsagePercent = 75
+	defaultHeapUsageFor     = "10m"
+
+	alertsDocsURL = "https://github.com/openshift/elasticsearch-operator/blob/master/docs/alerts.md"
+)
+
+// generatedAlerts are the names of the alerts generated for a cluster. The shipped alerts with
+// these names are replaced by them
+var generatedAlerts = []string{
+	"ElasticsearchClusterNotHealthy",
+	"ElasticsearchNodeDiskWatermarkReached",
+	"ElasticsearchJVMHeapUseHigh",
+	"ElasticsearchPendingTasksHigh",
+}
+
+// diskWatermark is a disk watermark of the cluster alerted on. The threshold of the spec replaces
+// the watermark of the cluster settings if set
+type diskWatermark struct {
+	name      string
+	metric    string
+	threshold func(spec *api.ClusterAlertsSpec) *int32
+	severity  string
+	message   string
+	anchor    string
+}
+
+var diskWatermarks = []diskWatermark{
+	{
+		name:      "Low",
+		metric:    "es_cluster_routing_allocation_disk_watermark_low_pct",
+		threshold: func(spec *api.ClusterAlertsSpec) *int32 { return spec.DiskLowPercent },
+		severity:  "info",
+		message:   "Shards can not be allocated to this node anymore. You should consider adding more disk to the node.",
+		anchor:    "Elasticsearch-Node-Disk-Low-Watermark-Reached",
+	},
+	{
+		name:      "High",
+		metric:    "es_cluster_routing_allocation_disk_watermark_high_pct",
+		threshold: func(spec *api.ClusterAlertsSpec) *int32 { return spec.DiskHighPercent },
+		severity:  "warning",
+		message:   "Some shards will be re-allocated to different nodes if possible. Make sure more disk space is added to the node or drop old indices allocated to this node.",
+		anchor:    "Elasticsearch-Node-Disk-High-Watermark-Reached",
+	},
+	{
+		name:      "Flood Stage",
+		metric:    "es_cluster_routing_allocation_disk_watermark_flood_stage_pct",
+		threshold: func(spec *api.ClusterAlertsSpec) *int32 { return spec.DiskFloodStagePercent },
+		severity:  "critical",
+		message:   "Every index having a shard allocated on this node is enforced a read-only block. The index block must be released manually when the disk utilization falls below the high watermark.",
+		anchor:    "Elasticsearch-Node-Disk-Flood-Watermark-Reached",
+	},
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func int32OrDefault(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+// applyClusterAlerts replaces the shipped alerts of the rule spec by the ones generated for the
+// cluster if the cluster defines its alerts
+func applyClusterAlerts(cluster *api.Elasticsearch, spec *monitoringv1.PrometheusRuleSpec) {
+	if cluster.Spec.Alerts == nil {
+		return
+	}
+
+	for i := range spec.Groups {
+		group := &spec.Groups[i]
+		rules := []monitoringv1.Rule{}
+		for _, rule := range group.Rules {
+			if !isGeneratedAlert(rule.Alert) {
+				rules = append(rules, rule)
+			}
+		}
+		group.Rules = rules
+	}
+	spec.Groups = append(spec.Groups, newClusterAlertsGroup(cluster))
+}
+
+func isGeneratedAlert(name string) bool {
+	for _, alert := range generatedAlerts {
+		if name == alert {
+			return true
+		}
+	}
+	return false
+}
+
+// newClusterAlertsGroup returns the alerting rules of the cluster, selecting its metrics by the
+// name of the cluster and its namespace
+func newClusterAlertsGroup(cluster *api.Elasticsearch) monitoringv1.RuleGroup {
+	spec := cluster.Spec.Alerts
+	selector := fmt.Sprintf("cluster=%q,namespace=%q", cluster.Name, cluster.Namespace)
+
+	newAlert := func(name, severity, expr, forDuration, summary, message string) monitoringv1.Rule {
+		labels := map[string]string{}
+		for key, value := range spec.Labels {
+			labels[key] = value
+		}
+		labels["severity"] = severity
+		return monitoringv1.Rule{
+			Alert:       name,
+			Expr:        intstr.FromString(expr),
+			For:         forDuration,
+			Labels:      labels,
+			Annotations: map[string]string{"summary": summary, "message": message},
+		}
+	}
+
+	redFor := stringOrDefault(spec.RedFor, defaultRedFor)
+	yellowFor := stringOrDefault(spec.YellowFor, defaultYellowFor)
+	rules := []monitoringv1.Rule{
+		newAlert("ElasticsearchClusterNotHealthy", "critical",
+			fmt.Sprintf("sum by (cluster, namespace) (es_cluster_status{%s} == 2)\n", selector),
+			redFor,
+			"Cluster health status is RED",
+			fmt.Sprintf("Cluster {{ $labels.cluster }} health status has been RED for at least %s. Cluster does not accept writes, shards may be missing or master node hasn't been elected yet. For more information refer to %s#Elasticsearch-Cluster-Health-is-Red", redFor, alertsDocsURL)),
+		newAlert("ElasticsearchClusterNotHealthy", "warning",
+			fmt.Sprintf("sum by (cluster, namespace) (es_cluster_status{%s} == 1)\n", selector),
+			yellowFor,
+			"Cluster health status is YELLOW",
+			fmt.Sprintf("Cluster {{ $labels.cluster }} health status has been YELLOW for at least %s. Some shard replicas are not allocated. For more information refer to %s#Elasticsearch-Cluster-Healthy-is-Yellow", yellowFor, alertsDocsURL)),
+	}
+
+	diskWatermarkFor := stringOrDefault(spec.DiskWatermarkFor, defaultDiskWatermarkFor)
+	for _, watermark := range diskWatermarks {
+		threshold := fmt.Sprintf("on(instance, pod) %s{%s}", watermark.metric, selector)
+		if percent := watermark.threshold(spec); percent != nil {
+			threshold = fmt.Sprintf("%d", *percent)
+		}
+		rules = append(rules, newAlert("ElasticsearchNodeDiskWatermarkReached", watermark.severity,
+			fmt.Sprintf("sum by (cluster, namespace, instance, pod) (\n  round((1 - (es_fs_path_available_bytes{%[1]s} / es_fs_path_total_bytes{%[1]s})) * 100, 0.001)\n) > %[2]s\n", selector, threshold),
+			diskWatermarkFor,
+			fmt.Sprintf("Disk %s Watermark Reached - disk saturation is {{ $value }}%%", watermark.name),
+			fmt.Sprintf("Disk %s Watermark Reached at {{ $labels.pod }} pod of cluster {{ $labels.cluster }}. %s For more information refer to %s#%s", watermark.name, watermark.message, alertsDocsURL, watermark.anchor)))
+	}
+
+	pendingTasks := int32OrDefault(spec.PendingTasks, defaultPendingTasks)
+	rules = append(rules, newAlert("ElasticsearchPendingTasksHigh", "warning",
+		fmt.Sprintf("max by (cluster, namespace) (es_cluster_pending_tasks_number{%s}) > %d\n", selector, pendingTasks),
+		stringOrDefault(spec.PendingTasksFor, defaultPendingTasksFor),
+		"Cluster has many pending tasks",
+		fmt.Sprintf("The master of cluster {{ $labels.cluster }} has {{ $value }} pending tasks, more than %d. Changes of the cluster state such as index creations and mapping updates are delayed. For more information refer to %s#Elasticsearch-Pending-Tasks-are-High", pendingTasks, alertsDocsURL)))
+
+	heapUsage := int32OrDefault(spec.HeapUsagePercent, defaultHeapUsagePercent)
+	rules = append(rules, newAlert("ElasticsearchJVMHeapUseHigh", "alert",
+		fmt.Sprintf("sum by (cluster, namespace, instance, node) (es_jvm_mem_heap_used_percent{%s}) > %d\n", selector, heapUsage),
+		stringOrDefault(spec.HeapUsageFor, defaultHeapUsageFor),
+		"JVM Heap usage on the node is high",
+		fmt.Sprintf("JVM Heap usage on the node {{ $labels.node }} in {{ $labels.cluster }} cluster is {{ $value }}%%. For more information refer to %s#Elasticsearch-JVM-Heap-Use-is-High", alertsDocsURL)))
+
+	return monitoringv1.RuleGroup{
+		Name:  fmt.Sprintf("logging_elasticsearch.%s.%s.alerts", cluster.Namespace, cluster.Name),
+		Rules: rules,
+	}
+}
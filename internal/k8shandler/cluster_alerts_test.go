@@ -0,0 +1,105 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Cluster alerts", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		spec    *monitoringv1.PrometheusRuleSpec
+	)
+
+	alertRules := func(group monitoringv1.RuleGroup, name string) []monitoringv1.Rule {
+		rules := []monitoringv1.Rule{}
+		for _, rule := range group.Rules {
+			if rule.Alert == name {
+				rules = append(rules, rule)
+			}
+		}
+		return rules
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+
+		var err error
+		spec, err = ruleSpec(alertPath)
+		Expect(err).To(BeNil())
+	})
+
+	It("should keep the shipped alerts unless the cluster defines its alerts", func() {
+		shipped := spec.DeepCopy()
+		applyClusterAlerts(cluster, spec)
+		Expect(spec).To(Equal(shipped))
+	})
+
+	It("should replace the shipped alerts by the ones scoped to the cluster", func() {
+		cluster.Spec.Alerts = &api.ClusterAlertsSpec{}
+		applyClusterAlerts(cluster, spec)
+
+		Expect(spec.Groups).To(HaveLen(2))
+		Expect(alertRules(spec.Groups[0], "ElasticsearchClusterNotHealthy")).To(BeEmpty())
+		Expect(alertRules(spec.Groups[0], "ElasticsearchNodeDiskWatermarkReached")).To(BeEmpty())
+		Expect(alertRules(spec.Groups[0], "ElasticsearchJVMHeapUseHigh")).To(BeEmpty())
+		Expect(alertRules(spec.Groups[0], "ElasticsearchProcessCPUHigh")).To(HaveLen(1))
+
+		group := spec.Groups[1]
+		Expect(group.Name).To(Equal("logging_elasticsearch.openshift-logging.elasticsearch.alerts"))
+		Expect(alertRules(group, "ElasticsearchClusterNotHealthy")).To(HaveLen(2))
+		Expect(alertRules(group, "ElasticsearchNodeDiskWatermarkReached")).To(HaveLen(3))
+
+		red := alertRules(group, "ElasticsearchClusterNotHealthy")[0]
+		Expect(red.Expr.String()).To(Equal("sum by (cluster, namespace) (es_cluster_status{cluster=\"elasticsearch\",namespace=\"openshift-logging\"} == 2)\n"))
+		Expect(red.For).To(Equal("7m"))
+		Expect(red.Labels).To(Equal(map[string]string{"severity": "critical"}))
+
+		pending := alertRules(group, "ElasticsearchPendingTasksHigh")
+		Expect(pending).To(HaveLen(1))
+		Expect(pending[0].Expr.String()).To(HaveSuffix("> 20\n"))
+	})
+
+	It("should apply the thresholds and labels of the cluster", func() {
+		pendingTasks := int32(50)
+		heapUsage := int32(85)
+		diskHigh := int32(80)
+		cluster.Spec.Alerts = &api.ClusterAlertsSpec{
+			RedFor:           "2m",
+			DiskWatermarkFor: "15m",
+			DiskHighPercent:  &diskHigh,
+			PendingTasks:     &pendingTasks,
+			HeapUsagePercent: &heapUsage,
+			HeapUsageFor:     "30m",
+			Labels:           map[string]string{"team": "logging", "severity": "ignored"},
+		}
+		applyClusterAlerts(cluster, spec)
+		group := spec.Groups[1]
+
+		red := alertRules(group, "ElasticsearchClusterNotHealthy")[0]
+		Expect(red.For).To(Equal("2m"))
+		Expect(red.Annotations["message"]).To(ContainSubstring("RED for at least 2m"))
+		Expect(red.Labels).To(Equal(map[string]string{"severity": "critical", "team": "logging"}))
+
+		disk := alertRules(group, "ElasticsearchNodeDiskWatermarkReached")
+		for _, rule := range disk {
+			Expect(rule.For).To(Equal("15m"))
+		}
+		Expect(disk[0].Expr.String()).To(HaveSuffix("> on(instance, pod) es_cluster_routing_allocation_disk_watermark_low_pct{cluster=\"elasticsearch\",namespace=\"openshift-logging\"}\n"))
+		Expect(disk[1].Expr.String()).To(HaveSuffix("> 80\n"))
+		Expect(disk[1].Labels["severity"]).To(Equal("warning"))
+		Expect(alertRules(group, "ElasticsearchPendingTasksHigh")[0].Expr.String()).To(HaveSuffix("> 50\n"))
+
+		heap := alertRules(group, "ElasticsearchJVMHeapUseHigh")[0]
+		Expect(heap.Expr.String()).To(HaveSuffix("> 85\n"))
+		Expect(heap.For).To(Equal("30m"))
+	})
+})
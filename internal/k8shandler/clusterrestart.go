@@ -2,6 +2,8 @@ package k8shandler
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
@@ -19,6 +21,9 @@ type ClusterRestart struct {
 	clusterName      string
 	clusterNamespace string
 	scheduledNodes   []NodeTypeInterface
+
+	// electedMasterLast orders the nodes to restart the node running the elected master last
+	electedMasterLast func([]NodeTypeInterface) []NodeTypeInterface
 }
 
 type Restarter struct {
@@ -53,18 +58,23 @@ func (er *ElasticsearchRequest) PerformFullClusterUpdate(nodes []NodeTypeInterfa
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
 		scheduledNodes:   nodes,
+
+		electedMasterLast: er.electedMasterLast,
 	}
 
 	restarter := Restarter{
 		scheduledNodes:   nodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		precheck:         r.ensureClusterHealthValid,
+		precheck:         er.snapshotBeforeUpgrade(r.ensureClusterHealthValid, nodes),
 		prep:             r.requiredSetPrimariesShardsAndFlush,
 		main:             r.pushNodeUpdates,
 		post:             r.waitAllNodesRejoinAndSetAllShards,
 		recovery:         r.ensureClusterHealthValid,
 	}
+	if er.ephemeralNodes(nodes) {
+		restarter.relaxForEphemeralStorage(r)
+	}
 
 	updateStatus := func() {
 		for _, node := range r.scheduledNodes {
@@ -78,6 +88,16 @@ func (er *ElasticsearchRequest) PerformFullClusterUpdate(nodes []NodeTypeInterfa
 	}
 
 	restarter.setClusterConditions(updateStatus)
+
+	// record why the nodes are restarted once the update begins
+	beginUpdate := restarter.precheckSignaler
+	restarter.precheckSignaler = func() {
+		for _, node := range nodes {
+			er.recordRollout(node, er.getNodeState(node).UpgradeStatus.ScheduledChanges)
+		}
+		beginUpdate()
+	}
+
 	restarter.clusterStatus = &er.cluster.Status
 	return restarter.restartCluster()
 }
@@ -88,6 +108,8 @@ func (er *ElasticsearchRequest) PerformFullClusterCertRestart(nodes []NodeTypeIn
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
 		scheduledNodes:   nodes,
+
+		electedMasterLast: er.electedMasterLast,
 	}
 
 	restarter := Restarter{
@@ -100,6 +122,9 @@ func (er *ElasticsearchRequest) PerformFullClusterCertRestart(nodes []NodeTypeIn
 		post:             r.waitAllNodesRejoinAndSetAllShards,
 		recovery:         r.ensureClusterHealthValid,
 	}
+	if er.ephemeralNodes(nodes) {
+		restarter.relaxForEphemeralStorage(r)
+	}
 
 	updateStatus := func() {
 		for _, node := range r.scheduledNodes {
@@ -123,18 +148,23 @@ func (er *ElasticsearchRequest) PerformFullClusterRestart(nodes []NodeTypeInterf
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
 		scheduledNodes:   nodes,
+
+		electedMasterLast: er.electedMasterLast,
 	}
 
 	restarter := Restarter{
 		scheduledNodes:   nodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		precheck:         r.ensureClusterHealthValid,
+		precheck:         er.snapshotBeforeUpgrade(r.ensureClusterHealthValid, nodes),
 		prep:             r.optionalSetPrimariesShardsAndFlush,
 		main:             er.scaleDownThenUpFunc(r),
 		post:             r.waitAllNodesRejoinAndSetAllShards,
 		recovery:         r.ensureClusterHealthValid,
 	}
+	if er.ephemeralNodes(nodes) {
+		restarter.relaxForEphemeralStorage(r)
+	}
 
 	updateStatus := func() {
 		for _, node := range r.scheduledNodes {
@@ -160,17 +190,22 @@ func (er *ElasticsearchRequest) PerformNodeRestart(node NodeTypeInterface) error
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
 		scheduledNodes:   scheduledNode,
+
+		electedMasterLast: er.electedMasterLast,
 	}
 
 	restarter := Restarter{
 		scheduledNodes:   scheduledNode,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		precheck:         r.ensureClusterHealthValid,
+		precheck:         er.snapshotBeforeUpgrade(r.ensureClusterHealthValid, scheduledNode),
 		prep:             r.optionalSetPrimariesShardsAndFlush,
 		main:             r.scaleDownThenUpNodes,
 		post:             r.waitAllNodesRejoinAndSetAllShards,
-		recovery:         r.ensureClusterHealthValid,
+		recovery:         er.warmUpAfter(r.ensureClusterHealthValid, node),
+	}
+	if er.ephemeralNodes(scheduledNode) {
+		restarter.relaxForEphemeralStorage(r)
 	}
 
 	updateStatus := func() {
@@ -193,20 +228,28 @@ func (er *ElasticsearchRequest) PerformNodeUpdate(node NodeTypeInterface) error
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
 		scheduledNodes:   scheduledNode,
+
+		electedMasterLast: er.electedMasterLast,
 	}
 
 	restarter := Restarter{
 		scheduledNodes:   scheduledNode,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		precheck:         r.ensureClusterHealthValid,
+		precheck:         er.snapshotBeforeUpgrade(r.ensureClusterHealthValid, scheduledNode),
 		prep:             r.requiredSetPrimariesShardsAndFlush,
 		main:             r.pushNodeUpdates,
 		post:             r.waitAllNodesRejoinAndSetAllShards,
-		recovery:         r.ensureClusterHealthValid,
+		recovery:         er.warmUpAfter(r.ensureClusterHealthValid, node),
+	}
+	if er.ephemeralNodes(scheduledNode) {
+		restarter.relaxForEphemeralStorage(r)
 	}
 
 	updateStatus := func() {
+		// each phase has its own retry budget
+		restarter.nodeStatus.UpgradeStatus.PhaseAttempts = 0
+
 		if err := er.setNodeStatus(node, restarter.nodeStatus, &er.cluster.Status); err != nil {
 			log.Error(err, "unable to update node status", "namespace", er.cluster.Namespace, "name", er.cluster.Name)
 		}
@@ -214,13 +257,51 @@ func (er *ElasticsearchRequest) PerformNodeUpdate(node NodeTypeInterface) error
 
 	restarter.setNodeConditions(updateStatus)
 
+	// record why the node is restarted once its update begins
+	beginUpdate := restarter.precheckSignaler
+	restarter.precheckSignaler = func() {
+		er.recordRollout(node, restarter.nodeStatus.UpgradeStatus.ScheduledChanges)
+		beginUpdate()
+	}
+
+	if err := er.checkUpgradeRetryBudget(node, er.getNodeState(node)); err != nil {
+		return err
+	}
+
 	restarter.nodeStatus = er.getNodeState(node)
-	return restarter.restartCluster()
+	if err := restarter.restartCluster(); err != nil {
+		return er.recordFailedUpgradeAttempt(node, restarter.nodeStatus, err)
+	}
+	return nil
+}
+
+// recordRollout logs and records an event listing the changes of the pod template
+// which cause the rollout of the node
+func (er *ElasticsearchRequest) recordRollout(node NodeTypeInterface, changes []string) {
+	er.L().Info("Rolling out changes to node", "node", node.name(), "changes", changes)
+
+	if er.recorder == nil {
+		return
+	}
+
+	message := fmt.Sprintf("Rolling out node %s", node.name())
+	if len(changes) > 0 {
+		message = fmt.Sprintf("%s: %s", message, strings.Join(changes, "; "))
+	}
+	er.recorder.Event(er.cluster, v1.EventTypeNormal, "RolloutStarted", message)
 }
 
+// PerformRollingUpdate updates the nodes one after the other. The elected master is looked up again
+// after each node since the update of a node may have moved it. The deployments of a node of the
+// spec are updated in the order of their ordinals, tracked by the status to resume from
 func (er *ElasticsearchRequest) PerformRollingUpdate(nodes []NodeTypeInterface) error {
-	for _, node := range nodes {
-		if err := er.PerformNodeUpdate(node); err != nil {
+	nodes = sequenceRollout(nodes, er.cluster.Status.DeploymentRollouts)
+	for remaining := nodes; len(remaining) > 0; remaining = remaining[1:] {
+		remaining = er.electedMasterLast(remaining)
+		if err := er.PerformNodeUpdate(remaining[0]); err != nil {
+			return err
+		}
+		if err := er.advanceDeploymentRollout(remaining[0], remaining[1:]); err != nil {
 			return err
 		}
 	}
@@ -228,9 +309,17 @@ func (er *ElasticsearchRequest) PerformRollingUpdate(nodes []NodeTypeInterface)
 	return nil
 }
 
+// PerformRollingRestart restarts the nodes one after the other. The elected master is looked up
+// again after each node since the restart of a node may have moved it. The deployments of a node
+// of the spec are restarted in the order of their ordinals, tracked by the status to resume from
 func (er *ElasticsearchRequest) PerformRollingRestart(nodes []NodeTypeInterface) error {
-	for _, node := range nodes {
-		if err := er.PerformNodeRestart(node); err != nil {
+	nodes = sequenceRollout(nodes, er.cluster.Status.DeploymentRollouts)
+	for remaining := nodes; len(remaining) > 0; remaining = remaining[1:] {
+		remaining = er.electedMasterLast(remaining)
+		if err := er.PerformNodeRestart(remaining[0]); err != nil {
+			return err
+		}
+		if err := er.advanceDeploymentRollout(remaining[0], remaining[1:]); err != nil {
 			return err
 		}
 	}
@@ -361,7 +450,7 @@ func (cr ClusterRestart) scaleDownThenUpNodes() error {
 
 func (cr ClusterRestart) scaleDownNodes() error {
 	// scale down all nodes
-	for _, node := range cr.scheduledNodes {
+	for _, node := range cr.orderedNodes(cr.scheduledNodes) {
 		if err := node.scaleDown(); err != nil {
 			return err
 		}
@@ -384,8 +473,10 @@ func (cr ClusterRestart) scaleUpNodes() error {
 }
 
 func (cr ClusterRestart) pushNodeUpdates() error {
-	for _, node := range cr.scheduledNodes {
-		if err := node.progressNodeChanges(); err != nil {
+	// the elected master may move with each updated node
+	for remaining := cr.scheduledNodes; len(remaining) > 0; remaining = remaining[1:] {
+		remaining = cr.orderedNodes(remaining)
+		if err := remaining[0].progressNodeChanges(); err != nil {
 			return err
 		}
 	}
@@ -393,6 +484,14 @@ func (cr ClusterRestart) pushNodeUpdates() error {
 	return nil
 }
 
+// orderedNodes returns the nodes in the order to restart them with the elected master last
+func (cr ClusterRestart) orderedNodes(nodes []NodeTypeInterface) []NodeTypeInterface {
+	if cr.electedMasterLast == nil {
+		return nodes
+	}
+	return cr.electedMasterLast(nodes)
+}
+
 func (r *Restarter) setClusterConditions(updateStatus func()) {
 	// cluster conditions
 	r.precheckCondition = func() bool {
@@ -492,19 +591,19 @@ func (r *Restarter) setNodeConditions(updateStatus func()) {
 	}
 
 	r.prepSignaler = func() {
-		r.nodeStatus.UpgradeStatus.UpgradePhase = api.PreparationComplete
+		setUpgradePhase(r.nodeStatus, api.PreparationComplete)
 
 		updateStatus()
 	}
 
 	r.mainSignaler = func() {
-		r.nodeStatus.UpgradeStatus.UpgradePhase = api.NodeRestarting
+		setUpgradePhase(r.nodeStatus, api.NodeRestarting)
 
 		updateStatus()
 	}
 
 	r.postSignaler = func() {
-		r.nodeStatus.UpgradeStatus.UpgradePhase = api.RecoveringData
+		setUpgradePhase(r.nodeStatus, api.RecoveringData)
 
 		updateStatus()
 	}
@@ -516,10 +615,12 @@ func (r *Restarter) setNodeConditions(updateStatus func()) {
 			"cluster", r.clusterName,
 			"namespace", r.clusterNamespace)
 
-		r.nodeStatus.UpgradeStatus.UpgradePhase = api.ControllerUpdated
+		setUpgradePhase(r.nodeStatus, api.ControllerUpdated)
 		r.nodeStatus.UpgradeStatus.UnderUpgrade = ""
 
 		r.nodeStatus.UpgradeStatus.ScheduledForUpgrade = ""
+		r.nodeStatus.UpgradeStatus.ScheduledForRedeploy = ""
+		r.nodeStatus.UpgradeStatus.ScheduledChanges = nil
 
 		updateStatus()
 	}
@@ -6,7 +6,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
@@ -424,6 +427,25 @@ var _ = Describe("clusterrestart", func() {
 			Expect(restarter.clusterStatus).To(BeEquivalentTo(expectedStatus))
 		})
 	})
+
+	Context("rollouts", func() {
+		It("should record the changes causing the rollout of a node", func() {
+			recorder := record.NewFakeRecorder(1)
+			request := &ElasticsearchRequest{
+				cluster: &api.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+				},
+				recorder: recorder,
+			}
+			node := &deploymentNode{
+				self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1"}},
+			}
+
+			request.recordRollout(node, []string{"containers[elasticsearch].image: old -> new", "containers[elasticsearch].env: HEAP_DUMP"})
+			Expect(recorder.Events).To(Receive(Equal(
+				"Normal RolloutStarted Rolling out node elasticsearch-cdm-abc-1: containers[elasticsearch].image: old -> new; containers[elasticsearch].env: HEAP_DUMP")))
+		})
+	})
 })
 
 func (cr ClusterRestart) restartFail() error {
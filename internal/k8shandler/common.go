@@ -1,15 +1,12 @@
 package k8shandler
 
 import (
-	"context"
 	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
 
-	"github.com/ViaQ/logerr/kverrors"
 	v1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -19,9 +16,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
-
-	networking "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 var defaultResources = map[string]v1.ResourceRequirements{
@@ -57,6 +51,9 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 	isClient := false
 	isData := false
 	isMaster := false
+	isIngest := false
+	isML := false
+	isVotingOnly := false
 
 	for _, role := range node.Roles {
 		if role == api.ElasticsearchRoleClient {
@@ -70,11 +67,26 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 		if role == api.ElasticsearchRoleMaster {
 			isMaster = true
 		}
+
+		if role == api.ElasticsearchRoleIngest {
+			isIngest = true
+		}
+
+		if role == api.ElasticsearchRoleML {
+			isML = true
+		}
+
+		if role == api.ElasticsearchRoleVotingOnly {
+			isVotingOnly = true
+		}
 	}
 	return map[api.ElasticsearchNodeRole]bool{
-		api.ElasticsearchRoleClient: isClient,
-		api.ElasticsearchRoleData:   isData,
-		api.ElasticsearchRoleMaster: isMaster,
+		api.ElasticsearchRoleClient:     isClient,
+		api.ElasticsearchRoleData:       isData,
+		api.ElasticsearchRoleMaster:     isMaster,
+		api.ElasticsearchRoleIngest:     isIngest,
+		api.ElasticsearchRoleML:         isML,
+		api.ElasticsearchRoleVotingOnly: isVotingOnly,
 	}
 }
 
@@ -88,6 +100,44 @@ func isMasterNode(node api.ElasticsearchNode) bool {
 	return false
 }
 
+// isCoordinatingNode returns true if the nodes only route requests to the other nodes
+func isCoordinatingNode(node api.ElasticsearchNode) bool {
+	roleMap := getNodeRoleMap(node)
+	return roleMap[api.ElasticsearchRoleClient] && !roleMap[api.ElasticsearchRoleData] &&
+		!roleMap[api.ElasticsearchRoleMaster] && !roleMap[api.ElasticsearchRoleIngest] &&
+		!roleMap[api.ElasticsearchRoleML]
+}
+
+func isIngestNode(node api.ElasticsearchNode) bool {
+	for _, role := range node.Roles {
+		if role == api.ElasticsearchRoleIngest {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMLNode(node api.ElasticsearchNode) bool {
+	for _, role := range node.Roles {
+		if role == api.ElasticsearchRoleML {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isVotingOnlyNode(node api.ElasticsearchNode) bool {
+	for _, role := range node.Roles {
+		if role == api.ElasticsearchRoleVotingOnly {
+			return true
+		}
+	}
+
+	return false
+}
+
 func isDataNode(node api.ElasticsearchNode) bool {
 	for _, role := range node.Roles {
 		if role == api.ElasticsearchRoleData {
@@ -121,6 +171,27 @@ func newAffinity(roleMap map[api.ElasticsearchNodeRole]bool) *v1.Affinity {
 			Values:   []string{"true"},
 		})
 	}
+	if roleMap[api.ElasticsearchRoleIngest] {
+		labelSelectorReqs = append(labelSelectorReqs, metav1.LabelSelectorRequirement{
+			Key:      "es-node-ingest",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"true"},
+		})
+	}
+	if roleMap[api.ElasticsearchRoleML] {
+		labelSelectorReqs = append(labelSelectorReqs, metav1.LabelSelectorRequirement{
+			Key:      "es-node-ml",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"true"},
+		})
+	}
+	if roleMap[api.ElasticsearchRoleVotingOnly] {
+		labelSelectorReqs = append(labelSelectorReqs, metav1.LabelSelectorRequirement{
+			Key:      "es-node-voting-only",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"true"},
+		})
+	}
 
 	return &v1.Affinity{
 		PodAntiAffinity: &v1.PodAntiAffinity{
@@ -246,8 +317,8 @@ func newProxyContainer(imageName, clusterName, namespace string, logConfig LogCo
 	return container
 }
 
-func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.ElasticsearchNodeRole]bool) []v1.EnvVar {
-	return []v1.EnvVar{
+func newEnvVars(nodeName, instanceRAM string, roleMap map[api.ElasticsearchNodeRole]bool, options podTemplateOptions) []v1.EnvVar {
+	envVars := []v1.EnvVar{
 		{
 			Name:  "DC_NAME",
 			Value: nodeName,
@@ -278,11 +349,11 @@ func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.Elast
 		},
 		{
 			Name:  "SERVICE_DNS",
-			Value: fmt.Sprintf("%s-cluster", clusterName),
+			Value: fmt.Sprintf("%s-cluster", options.clusterName),
 		},
 		{
 			Name:  "CLUSTER_NAME",
-			Value: clusterName,
+			Value: options.clusterName,
 		},
 		{
 			Name:  "INSTANCE_RAM",
@@ -302,7 +373,7 @@ func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.Elast
 		},
 		{
 			Name:  "POD_LABEL",
-			Value: fmt.Sprintf("cluster=%s", clusterName),
+			Value: fmt.Sprintf("cluster=%s", options.clusterName),
 		},
 		{
 			Name:  "IS_MASTER",
@@ -313,11 +384,45 @@ func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.Elast
 			Value: strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
 		},
 	}
+
+	if options.dataTier != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "DATA_TIER",
+			Value: options.dataTier,
+		})
+	}
+
+	if options.ingest != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "IS_INGEST",
+			Value: options.ingest,
+		})
+	}
+
+	if options.machineLearning != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "IS_ML",
+			Value: options.machineLearning,
+		})
+	}
+
+	if options.votingOnly != "" {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "IS_VOTING_ONLY",
+			Value: options.votingOnly,
+		})
+	}
+
+	if options.caches != nil {
+		envVars = append(envVars, newCacheLimitEnvVars(options.caches)...)
+	}
+
+	return envVars
 }
 
 // TODO: add isChanged check for labels and label selector
 func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
-	return map[string]string{
+	labels := map[string]string{
 		"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
 		"es-node-data":   strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
 		"es-node-master": strconv.FormatBool(roleMap[api.ElasticsearchRoleMaster]),
@@ -325,6 +430,17 @@ func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRo
 		"component":      "elasticsearch",
 		"node-name":      nodeName,
 	}
+	// only ingest, ml and voting only nodes are labeled to keep the pods of the other nodes unchanged
+	if roleMap[api.ElasticsearchRoleIngest] {
+		labels["es-node-ingest"] = "true"
+	}
+	if roleMap[api.ElasticsearchRoleML] {
+		labels["es-node-ml"] = "true"
+	}
+	if roleMap[api.ElasticsearchRoleVotingOnly] {
+		labels["es-node-voting-only"] = "true"
+	}
+	return labels
 }
 
 func newLabelSelector(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
@@ -337,16 +453,72 @@ func newLabelSelector(clusterName, nodeName string, roleMap map[api.Elasticsearc
 	}
 }
 
-func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.ElasticsearchNode, commonSpec api.ElasticsearchNodeSpec, labels map[string]string, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, logConfig LogConfig) v1.PodTemplateSpec {
-	resourceRequirements := newESResourceRequirements(node.Resources, commonSpec.Resources)
-	proxyResourceRequirements := newESProxyResourceRequirements(node.ProxyResources, commonSpec.ProxyResources)
+// podTemplateOptions are the settings of the pods of a node which derive from the spec of the cluster
+type podTemplateOptions struct {
+	clusterName     string
+	namespace       string
+	commonSpec      api.ElasticsearchNodeSpec
+	logConfig       LogConfig
+	clockSkew       *api.ClockSkewSpec
+	remoteTrust     bool
+	dataTier        string
+	ingest          string
+	machineLearning string
+	votingOnly      string
+	memoryLock      bool
+	hostTuning      bool
+	zoneAwareness   *api.ZoneAwarenessSpec
+	caches          *api.ElasticsearchCacheSpec
+	secureSettings  *secureSettings
+	analysisFiles   *analysisFiles
+}
+
+// newPodTemplateOptions returns the pod settings of the node of the cluster. The secure settings
+// and analysis files are read with the client and left out without one, e.g. when rendering
+func newPodTemplateOptions(cluster *api.Elasticsearch, node api.ElasticsearchNode, client client.Client) (podTemplateOptions, error) {
+	options := podTemplateOptions{
+		clusterName:     cluster.Name,
+		namespace:       cluster.Namespace,
+		commonSpec:      cluster.Spec.Spec,
+		logConfig:       newLogConfig(cluster),
+		clockSkew:       cluster.Spec.ClockSkew,
+		remoteTrust:     len(cluster.Spec.RemoteClusters) > 0,
+		dataTier:        dataTier(cluster, node),
+		ingest:          nodeIngest(cluster, node),
+		machineLearning: nodeMachineLearning(cluster, node),
+		votingOnly:      nodeVotingOnly(cluster, node),
+		memoryLock:      cluster.Spec.MemoryLock,
+		hostTuning:      cluster.Spec.HostTuning,
+		zoneAwareness:   cluster.Spec.ZoneAwareness,
+		caches:          nodeCacheLimits(cluster, node),
+	}
+	if client != nil {
+		settings, err := newSecureSettings(cluster, client)
+		if err != nil {
+			return options, err
+		}
+		options.secureSettings = settings
+		options.analysisFiles = newAnalysisFiles(cluster, client)
+	}
+	return options, nil
+}
+
+func newPodTemplateSpec(nodeName string, node api.ElasticsearchNode, labels map[string]string, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, options podTemplateOptions) v1.PodTemplateSpec {
+	resourceRequirements := newESResourceRequirements(node.Resources, options.commonSpec.Resources)
+	proxyResourceRequirements := newESProxyResourceRequirements(node.ProxyResources, options.commonSpec.ProxyResources)
 
-	selectors := mergeSelectors(node.NodeSelector, commonSpec.NodeSelector)
+	// the placement profile was validated with the configuration of the cluster
+	profile, err := nodePlacementProfile(options.commonSpec, node)
+	if err != nil {
+		log.Error(err, "Failed to get the placement profile of the node", "node", nodeName)
+	}
+
+	selectors := mergeSelectors(node.NodeSelector, mergeSelectors(options.commonSpec.NodeSelector, profile.NodeSelector))
 	// We want to make sure the pod ends up allocated on linux node. Thus we make sure the
 	// linux node selectors is always present. See LOG-411
 	selectors = utils.EnsureLinuxNodeSelector(selectors)
 
-	tolerations := appendTolerations(node.Tolerations, commonSpec.Tolerations)
+	tolerations := appendTolerations(node.Tolerations, appendTolerations(options.commonSpec.Tolerations, profile.Tolerations))
 	tolerations = appendTolerations(tolerations, []v1.Toleration{
 		{
 			Key:      "node.kubernetes.io/disk-pressure",
@@ -355,29 +527,114 @@ func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.Elasti
 		},
 	})
 
+	scratchSpace := nodeScratchSpace(options.commonSpec, node)
+	envVars := newEnvVars(nodeName, newInstanceRAM(nodeHeapSize(node, resourceRequirements)), roleMap, options)
+	if scratchSpace != nil {
+		envVars = append(envVars, newScratchSpaceEnvVar())
+	}
+
+	esContainer := newElasticsearchContainer(
+		getESImage(),
+		appendEnvVars(envVars, mergeEnvVars(node.Env, options.commonSpec.Env)),
+		resourceRequirements,
+	)
+	if options.memoryLock {
+		esContainer.SecurityContext = newMemoryLockSecurityContext()
+	}
+	volumes := newVolumes(options.clusterName, nodeName, options.namespace, node, client)
+	if scratchSpace != nil {
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, newScratchSpaceVolumeMount())
+		volumes = append(volumes, newScratchSpaceVolume(scratchSpace))
+	}
+	if jvmOptions := nodeJVMOptions(node); len(jvmOptions) > 0 {
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, v1.VolumeMount{
+			Name:      jvmOptionsVolumeName,
+			MountPath: jvmOptionsPath,
+		})
+		volumes = append(volumes, newJVMOptionsVolume(options.clusterName, jvmOptions))
+	}
+
+	var initContainers []v1.Container
+	if options.hostTuning {
+		initContainers = append(initContainers, newHostTuningContainer(getESImage()))
+	}
+	if options.clockSkew != nil && options.clockSkew.VerifyOnStartup {
+		initContainers = append(initContainers, newClockCheckContainer(getESImage(), options.clockSkew))
+	}
+	if options.remoteTrust {
+		initContainers = append(initContainers, newRemoteCAImportContainer(getESImage()))
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, v1.VolumeMount{
+			Name:      remoteTruststoreVolumeName,
+			MountPath: remoteTruststorePath,
+		})
+		volumes = append(volumes, newRemoteTrustVolumes(options.clusterName)...)
+	}
+	if options.zoneAwareness != nil {
+		initContainers = append(initContainers, newZoneDiscoveryContainer(getESImage(), options.zoneAwareness))
+		useZoneConfig(&esContainer)
+		volumes = append(volumes, newZoneConfigVolume())
+	}
+	if options.secureSettings != nil {
+		initContainers = append(initContainers, newKeystoreBuildContainer(getESImage(), options.secureSettings))
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, newKeystoreVolumeMount())
+		volumes = append(volumes, newSecureSettingsVolumes(options.secureSettings)...)
+	}
+	if options.analysisFiles != nil {
+		useAnalysisFiles(&esContainer, options.analysisFiles)
+		volumes = append(volumes, newAnalysisFilesVolume(options.analysisFiles))
+	}
+	initContainers = append(initContainers, node.InitContainers...)
+
+	schedulerName := node.SchedulerName
+	if schedulerName == "" {
+		schedulerName = options.commonSpec.SchedulerName
+	}
+
+	var runtimeClassName *string
+	if node.RuntimeClassName != "" {
+		runtimeClassName = &node.RuntimeClassName
+	} else if options.commonSpec.RuntimeClassName != "" {
+		runtimeClassName = &options.commonSpec.RuntimeClassName
+	}
+
+	priorityClassName := node.PriorityClassName
+	if priorityClassName == "" {
+		priorityClassName = options.commonSpec.PriorityClassName
+	}
+
+	containers := []v1.Container{
+		esContainer,
+		newProxyContainer(
+			getESProxyImage(),
+			options.clusterName,
+			options.namespace,
+			options.logConfig,
+			proxyResourceRequirements),
+	}
+	if options.secureSettings != nil {
+		containers = append(containers, newKeystoreContainer(getESImage(), options.secureSettings))
+	}
+
+	podLabels, annotations := podTemplateMetadata(options.commonSpec, node, labels)
+
 	return v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels: labels,
+			Labels:      podLabels,
+			Annotations: annotations,
 		},
 		Spec: v1.PodSpec{
-			Affinity: newAffinity(roleMap),
-			Containers: []v1.Container{
-				newElasticsearchContainer(
-					getESImage(),
-					newEnvVars(nodeName, clusterName, resourceRequirements.Limits.Memory().String(), roleMap),
-					resourceRequirements,
-				),
-				newProxyContainer(
-					getESProxyImage(),
-					clusterName,
-					namespace,
-					logConfig,
-					proxyResourceRequirements),
-			},
-			NodeSelector:       selectors,
-			ServiceAccountName: clusterName,
-			Volumes:            newVolumes(clusterName, nodeName, namespace, node, client),
-			Tolerations:        tolerations,
+			Affinity:                  nodeAffinity(node, roleMap),
+			InitContainers:            initContainers,
+			Containers:                append(containers, node.Sidecars...),
+			NodeSelector:              selectors,
+			NodeName:                  node.NodeName,
+			ServiceAccountName:        options.clusterName,
+			Volumes:                   volumes,
+			Tolerations:               tolerations,
+			SchedulerName:             schedulerName,
+			RuntimeClassName:          runtimeClassName,
+			PriorityClassName:         priorityClassName,
+			TopologySpreadConstraints: newTopologySpreadConstraints(options.clusterName, node, roleMap, options.zoneAwareness),
 		},
 	}
 }
@@ -578,6 +835,10 @@ func newVolumeSource(clusterName, nodeName, namespace string, node api.Elasticse
 	volSource := v1.VolumeSource{}
 
 	// Ephemeral storage
+	if specVol.EmptyDir != nil {
+		volSource.EmptyDir = specVol.EmptyDir.DeepCopy()
+		return volSource
+	}
 	emptySpecVol := api.ElasticsearchStorageSpec{}
 	if reflect.DeepEqual(specVol, emptySpecVol) {
 		volSource.EmptyDir = &v1.EmptyDirVolumeSource{}
@@ -627,127 +888,3 @@ func sortDataHashKeys(dataHash map[string][32]byte) []string {
 
 	return keys
 }
-
-/*
-kind: NetworkPolicy
-apiVersion: extensions/v1beta1
-metadata:
-  name: restricted-es-access
-spec:
-  podSelector:
-    matchLabels:
-      component: elasticsearch
-  ingress:
-  - from:
-    - podSelector:
-        matchLabels:
-          name: elasticsearch-operator
-    ports:
-    - protocol: TCP
-      port: 9200
-*/
-func newNetworkPolicy(namespace string) networking.NetworkPolicy {
-	protocol := v1.ProtocolTCP
-	port := intstr.FromInt(9200)
-	internalPort := intstr.FromInt(9300)
-
-	return networking.NetworkPolicy{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "NetworkPolicy",
-			APIVersion: networking.SchemeGroupVersion.String(),
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "restricted-es-policy",
-			Namespace: namespace,
-		},
-		Spec: networking.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"component": "elasticsearch",
-				},
-			},
-			Ingress: []networking.NetworkPolicyIngressRule{
-				{
-					From: []networking.NetworkPolicyPeer{
-						{
-							PodSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{
-									"name": "elasticsearch-operator",
-								},
-							},
-							// This needs to be present but empty so it will select all namespaces
-							// since we do not have a label for our operator namespace
-							NamespaceSelector: &metav1.LabelSelector{},
-						},
-					},
-					Ports: []networking.NetworkPolicyPort{
-						{
-							Protocol: &protocol,
-							Port:     &port,
-						},
-					},
-				},
-				{
-					From: []networking.NetworkPolicyPeer{
-						{
-							PodSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{
-									"component": "elasticsearch",
-								},
-							},
-						},
-					},
-					Ports: []networking.NetworkPolicyPort{
-						{
-							Protocol: &protocol,
-							Port:     &port,
-						},
-					},
-				},
-				{
-					From: []networking.NetworkPolicyPeer{
-						{
-							PodSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{
-									"component": "elasticsearch",
-								},
-							},
-						},
-					},
-					Ports: []networking.NetworkPolicyPort{
-						{
-							Protocol: &protocol,
-							Port:     &internalPort,
-						},
-					},
-				},
-			},
-		},
-	}
-}
-
-func EnforceNetworkPolicy(namespace string, client client.Client, ownerRef []metav1.OwnerReference) error {
-	policy := newNetworkPolicy(namespace)
-	policy.ObjectMeta.OwnerReferences = ownerRef
-
-	err := client.Create(context.TODO(), &policy)
-	if err != nil {
-		if !apierrors.IsAlreadyExists(kverrors.Root(err)) {
-			return kverrors.Wrap(err, "failed to create network policy")
-		}
-	}
-
-	return nil
-}
-
-func RelaxNetworkPolicy(namespace string, client client.Client) error {
-	policy := newNetworkPolicy(namespace)
-	err := client.Delete(context.TODO(), &policy)
-	if err != nil {
-		if !apierrors.IsNotFound(kverrors.Root(err)) {
-			return kverrors.Wrap(err, "failed to delete network policy")
-		}
-	}
-
-	return nil
-}
@@ -555,7 +555,7 @@ func TestPodSpecHasTaintTolerations(t *testing.T) {
 		},
 	}
 
-	podTemplateSpec := newPodTemplateSpec("test-node-name", "test-cluster-name", "test-namespace-name", api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, LogConfig{})
+	podTemplateSpec := newPodTemplateSpec("test-node-name", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"})
 
 	if !reflect.DeepEqual(podTemplateSpec.Spec.Tolerations, expectedTolerations) {
 		t.Errorf("Exp. the tolerations to be %v but was %v", expectedTolerations, podTemplateSpec.Spec.Tolerations)
@@ -634,6 +634,274 @@ func TestPodDiskToleration(t *testing.T) {
 	}
 }
 
+func TestPodSchedulerAndRuntimeClass(t *testing.T) {
+	commonSpec := api.ElasticsearchNodeSpec{SchedulerName: "kube-batch", RuntimeClassName: "gvisor"}
+
+	podSpec := newPodTemplateSpec("test-node-name", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	}).Spec
+
+	if podSpec.SchedulerName != "kube-batch" {
+		t.Errorf("Exp. the schedulerName of the common spec but was %q", podSpec.SchedulerName)
+	}
+	if podSpec.RuntimeClassName == nil || *podSpec.RuntimeClassName != "gvisor" {
+		t.Errorf("Exp. the runtimeClassName of the common spec but was %v", podSpec.RuntimeClassName)
+	}
+
+	node := api.ElasticsearchNode{SchedulerName: "volcano", RuntimeClassName: "kata"}
+	podSpec = newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	}).Spec
+
+	if podSpec.SchedulerName != "volcano" {
+		t.Errorf("Exp. the schedulerName of the node but was %q", podSpec.SchedulerName)
+	}
+	if podSpec.RuntimeClassName == nil || *podSpec.RuntimeClassName != "kata" {
+		t.Errorf("Exp. the runtimeClassName of the node but was %v", podSpec.RuntimeClassName)
+	}
+
+	podSpec = newPodTemplateSpec("test-node-name", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"}).Spec
+
+	if podSpec.SchedulerName != "" || podSpec.RuntimeClassName != nil {
+		t.Errorf("Exp. no schedulerName and runtimeClassName but were %q and %v", podSpec.SchedulerName, podSpec.RuntimeClassName)
+	}
+}
+
+func TestPodPriorityClassName(t *testing.T) {
+	commonSpec := api.ElasticsearchNodeSpec{PriorityClassName: "logging-critical"}
+
+	podSpec := newPodTemplateSpec("test-node-name", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	}).Spec
+
+	if podSpec.PriorityClassName != "logging-critical" {
+		t.Errorf("Exp. the priorityClassName of the common spec but was %q", podSpec.PriorityClassName)
+	}
+
+	node := api.ElasticsearchNode{PriorityClassName: "system-cluster-critical"}
+	podSpec = newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	}).Spec
+
+	if podSpec.PriorityClassName != "system-cluster-critical" {
+		t.Errorf("Exp. the priorityClassName of the node but was %q", podSpec.PriorityClassName)
+	}
+
+	admitted := *podSpec.DeepCopy()
+	podSpec.PriorityClassName = ""
+	if ArePodSpecDifferent(admitted, podSpec, false) {
+		t.Errorf("Exp. the priority class set by the admission not to differ from a pod spec without one")
+	}
+	if !ArePodSpecDifferent(admitted, podSpec, true) {
+		t.Errorf("Exp. the removed priority class to differ")
+	}
+}
+
+func TestPodTemplateMetadata(t *testing.T) {
+	commonSpec := api.ElasticsearchNodeSpec{
+		PodTemplate: &api.ElasticsearchPodTemplateSpec{
+			Metadata: api.ElasticsearchPodTemplateMetadata{
+				Labels:      map[string]string{"cost-center": "logging", "team": "infra"},
+				Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+			},
+		},
+	}
+	node := api.ElasticsearchNode{
+		PodTemplate: &api.ElasticsearchPodTemplateSpec{
+			Metadata: api.ElasticsearchPodTemplateMetadata{
+				Labels: map[string]string{"team": "observability", "component": "custom"},
+			},
+		},
+	}
+	labels := map[string]string{"component": "elasticsearch", "node-name": "test-node-name"}
+
+	template := newPodTemplateSpec("test-node-name", node, labels, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	})
+
+	expLabels := map[string]string{
+		"component":   "elasticsearch",
+		"node-name":   "test-node-name",
+		"cost-center": "logging",
+		"team":        "observability",
+	}
+	if !reflect.DeepEqual(template.Labels, expLabels) {
+		t.Errorf("Exp. the labels of the operator, the node and the node spec %v but were %v", expLabels, template.Labels)
+	}
+	if template.Annotations["sidecar.istio.io/inject"] != "false" {
+		t.Errorf("Exp. the annotations of the node spec but were %v", template.Annotations)
+	}
+	if len(labels) != 2 {
+		t.Errorf("Exp. the labels of the operator not to be modified but were %v", labels)
+	}
+
+	current := *template.DeepCopy()
+	current.Annotations = nil
+	if changes := podTemplateSpecChanges(current, template); !reflect.DeepEqual(changes, []string{"metadata.annotations"}) {
+		t.Errorf("Exp. the removed annotations to differ but the changes were %v", changes)
+	}
+}
+
+func TestPodEnvVars(t *testing.T) {
+	secretRef := &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "s3-credentials"},
+		Key:                  "access-key",
+	}}
+	commonSpec := api.ElasticsearchNodeSpec{
+		Env: []v1.EnvVar{
+			{Name: "S3_ACCESS_KEY", ValueFrom: secretRef},
+			{Name: "TZ", Value: "UTC"},
+		},
+	}
+	node := api.ElasticsearchNode{
+		Env: []v1.EnvVar{
+			{Name: "TZ", Value: "Europe/Berlin"},
+			{Name: "DC_NAME", Value: "overridden"},
+		},
+	}
+
+	template := newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	})
+
+	env := map[string]v1.EnvVar{}
+	for _, envVar := range template.Spec.Containers[0].Env {
+		if _, ok := env[envVar.Name]; ok {
+			t.Errorf("Exp. the environment variable %q once", envVar.Name)
+		}
+		env[envVar.Name] = envVar
+	}
+	if env["DC_NAME"].Value != "test-node-name" {
+		t.Errorf("Exp. the environment variables of the operator not to be overridden but DC_NAME was %v", env["DC_NAME"])
+	}
+	if env["TZ"].Value != "Europe/Berlin" {
+		t.Errorf("Exp. the environment variables of the node to override the node spec but TZ was %v", env["TZ"])
+	}
+	if !reflect.DeepEqual(env["S3_ACCESS_KEY"].ValueFrom, secretRef) {
+		t.Errorf("Exp. the environment variables of the node spec but S3_ACCESS_KEY was %v", env["S3_ACCESS_KEY"])
+	}
+
+	current := *template.DeepCopy()
+	envVars := current.Spec.Containers[0].Env
+	for i, j := 0, len(envVars)-1; i < j; i, j = i+1, j-1 {
+		envVars[i], envVars[j] = envVars[j], envVars[i]
+	}
+	if changes := podTemplateSpecChanges(current, template); len(changes) != 0 {
+		t.Errorf("Exp. the reordered environment variables not to differ but the changes were %v", changes)
+	}
+
+	node.Env[0].Value = "UTC"
+	template = newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+		clusterName: "test-cluster-name",
+		namespace:   "test-namespace-name",
+		commonSpec:  commonSpec,
+	})
+	if changes := podTemplateSpecChanges(current, template); !reflect.DeepEqual(changes, []string{"containers[elasticsearch].env: TZ"}) {
+		t.Errorf("Exp. the changed environment variable to differ but the changes were %v", changes)
+	}
+}
+
+func TestPodNodeName(t *testing.T) {
+	node := api.ElasticsearchNode{NodeName: "infra-0"}
+
+	podSpec := newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"}).Spec
+
+	if podSpec.NodeName != "infra-0" {
+		t.Errorf("Exp. the nodeName of the node but was %q", podSpec.NodeName)
+	}
+
+	scheduled := *podSpec.DeepCopy()
+	podSpec.NodeName = ""
+	if ArePodSpecDifferent(scheduled, podSpec, false) {
+		t.Errorf("Exp. the node name set by the scheduler not to differ from a pod spec without one")
+	}
+	if !ArePodSpecDifferent(scheduled, podSpec, true) {
+		t.Errorf("Exp. the removed node name to differ")
+	}
+}
+
+func TestPodTopologySpreadConstraints(t *testing.T) {
+	roleMap := map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}
+
+	podSpec := newPodTemplateSpec("test-node-name", api.ElasticsearchNode{}, map[string]string{}, roleMap, nil, podTemplateOptions{
+		clusterName:   "test-cluster-name",
+		namespace:     "test-namespace-name",
+		zoneAwareness: &api.ZoneAwarenessSpec{TopologyKey: "failure-domain.beta.kubernetes.io/zone"},
+	}).Spec
+
+	if len(podSpec.TopologySpreadConstraints) != 2 {
+		t.Fatalf("Exp. constraints spreading across zones and hosts but got %v", podSpec.TopologySpreadConstraints)
+	}
+	for i, key := range []string{"failure-domain.beta.kubernetes.io/zone", "kubernetes.io/hostname"} {
+		constraint := podSpec.TopologySpreadConstraints[i]
+		if constraint.TopologyKey != key || constraint.WhenUnsatisfiable != v1.ScheduleAnyway {
+			t.Errorf("Exp. a preferred constraint with the topology key %q but got %v", key, constraint)
+		}
+		if constraint.LabelSelector.MatchLabels["es-node-data"] != "true" || constraint.LabelSelector.MatchLabels["cluster-name"] != "test-cluster-name" {
+			t.Errorf("Exp. the constraint to select the data nodes of the cluster but got %v", constraint.LabelSelector)
+		}
+	}
+
+	constraints := []v1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.DoNotSchedule},
+	}
+	node := api.ElasticsearchNode{TopologySpreadConstraints: constraints}
+	podSpec = newPodTemplateSpec("test-node-name", node, map[string]string{}, roleMap, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"}).Spec
+
+	if !reflect.DeepEqual(podSpec.TopologySpreadConstraints, constraints) {
+		t.Errorf("Exp. the constraints of the node but got %v", podSpec.TopologySpreadConstraints)
+	}
+}
+
+func TestNewTopologySpreadConstraints(t *testing.T) {
+	masters := map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleMaster: true}
+	constraints := newTopologySpreadConstraints("test-cluster-name", api.ElasticsearchNode{}, masters, nil)
+
+	if len(constraints) != 2 {
+		t.Fatalf("Exp. constraints spreading across zones and hosts but got %v", constraints)
+	}
+	if constraints[0].TopologyKey != "topology.kubernetes.io/zone" || constraints[1].TopologyKey != "kubernetes.io/hostname" {
+		t.Errorf("Exp. the default zone and the hostname topology keys but got %q and %q", constraints[0].TopologyKey, constraints[1].TopologyKey)
+	}
+
+	expSelector := map[string]string{
+		"cluster-name":   "test-cluster-name",
+		"component":      "elasticsearch",
+		"es-node-client": "false",
+		"es-node-data":   "false",
+		"es-node-master": "true",
+	}
+	for _, constraint := range constraints {
+		if constraint.MaxSkew != 1 || constraint.WhenUnsatisfiable != v1.ScheduleAnyway {
+			t.Errorf("Exp. a preferred constraint with a max skew of 1 but got %v", constraint)
+		}
+		if !reflect.DeepEqual(constraint.LabelSelector.MatchLabels, expSelector) {
+			t.Errorf("Exp. the constraint to select the nodes with the same roles %v but got %v", expSelector, constraint.LabelSelector.MatchLabels)
+		}
+	}
+	if constraints[0].LabelSelector == constraints[1].LabelSelector {
+		t.Error("Exp. the constraints not to share their label selector")
+	}
+
+	zoneAwareness := &api.ZoneAwarenessSpec{TopologyKey: "failure-domain.beta.kubernetes.io/zone"}
+	constraints = newTopologySpreadConstraints("test-cluster-name", api.ElasticsearchNode{}, masters, zoneAwareness)
+	if constraints[0].TopologyKey != "failure-domain.beta.kubernetes.io/zone" {
+		t.Errorf("Exp. the topology key of the zone awareness but got %q", constraints[0].TopologyKey)
+	}
+}
+
 func TestNewVolumeSource(t *testing.T) {
 	const (
 		clusterName = "elastisearch"
@@ -776,16 +1044,7 @@ func TestNewVolumeSource(t *testing.T) {
 // This function wraps the call to newPodTempalteSpec in case its signature changes in the future
 // so that keeping unit tests up to date will be easier.
 func preparePodTemplateSpecProvidingNodeSelectors(selectors map[string]string) v1.PodTemplateSpec {
-	return newPodTemplateSpec(
-		"test-node-name",
-		"test-cluster-name",
-		"test-namespace-name",
-		api.ElasticsearchNode{NodeSelector: selectors},
-		api.ElasticsearchNodeSpec{},
-		map[string]string{},
-		map[api.ElasticsearchNodeRole]bool{},
-		nil,
-		LogConfig{})
+	return newPodTemplateSpec("test-node-name", api.ElasticsearchNode{NodeSelector: selectors}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"})
 }
 
 func buildResource(cpuLimit, cpuRequest, memLimit, memRequest resource.Quantity) v1.ResourceRequirements {
@@ -849,7 +1108,7 @@ var _ = Describe("common.go", func() {
 	Describe("#newEnvVars", func() {
 		var envVars []v1.EnvVar
 		BeforeEach(func() {
-			envVars = newEnvVars("theNodeName", "theClusterName", "theInstanceRam", map[api.ElasticsearchNodeRole]bool{})
+			envVars = newEnvVars("theNodeName", "theInstanceRam", map[api.ElasticsearchNodeRole]bool{}, podTemplateOptions{clusterName: "theClusterName"})
 		})
 
 		It("should define POD_IP so IPV4 or IPV6 deployments are possible", func() {
@@ -0,0 +1,330 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// composableTemplatesMinVersion is the first version of Elasticsearch with component templates
+// and composable index templates
+const composableTemplatesMinVersion = "7.8"
+
+func declaredComposableTemplates(cluster *api.Elasticsearch) ([]api.ComponentTemplateSpec, []api.ComposableIndexTemplateSpec) {
+	if cluster.Spec.IndexManagement == nil {
+		return nil, nil
+	}
+	return cluster.Spec.IndexManagement.ComponentTemplates, cluster.Spec.IndexManagement.IndexTemplates
+}
+
+// ReconcileComposableTemplates creates the declared component templates before the index templates
+// composed of them and deletes the removed templates in the reverse order. Index templates with
+// unresolved components or overlapping another template of the same priority are not applied
+func (er *ElasticsearchRequest) ReconcileComposableTemplates() error {
+	cluster := er.cluster
+	components, templates := declaredComposableTemplates(cluster)
+
+	if len(components) == 0 && len(templates) == 0 && len(cluster.Status.ComposableTemplates) == 0 {
+		return nil
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	version, err := er.esClient.GetLowestClusterVersion()
+	if err != nil {
+		return err
+	}
+	if comparators.CompareVersions(version, composableTemplatesMinVersion) > 0 {
+		message := fmt.Sprintf("Composable templates require Elasticsearch %s or later, the cluster runs %s", composableTemplatesMinVersion, version)
+		return er.updateComposableTemplatesStatus(unsupportedComposableTemplates(components, templates, message))
+	}
+
+	er.removeComposableTemplates(components, templates)
+
+	statuses := []api.ComposableTemplateStatus{}
+	declared := map[string]bool{}
+	for _, spec := range components {
+		status := api.ComposableTemplateStatus{Name: spec.Name, Kind: api.ComposableTemplateKindComponent}
+		if declared[spec.Name] {
+			status.State = api.ComposableTemplateStateFailed
+			status.Message = "The component template is declared more than once"
+			statuses = append(statuses, status)
+			continue
+		}
+		declared[spec.Name] = true
+
+		template, err := newComponentTemplate(spec)
+		if err == nil {
+			err = er.esClient.CreateOrUpdateComponentTemplate(spec.Name, template)
+		}
+		if err != nil {
+			er.L().Error(err, "failed to apply component template", "template", spec.Name)
+			status.State = api.ComposableTemplateStateFailed
+			status.Message = elasticsearchErrorReason(err)
+		} else {
+			status.State = api.ComposableTemplateStateApplied
+		}
+		statuses = append(statuses, status)
+	}
+
+	available := map[string]bool{}
+	if len(templates) > 0 {
+		existing, err := er.esClient.ListComponentTemplates()
+		if err != nil {
+			return err
+		}
+		for _, name := range existing {
+			available[name] = true
+		}
+	}
+
+	conflicts, shadows := composableTemplateOverlaps(templates)
+	declared = map[string]bool{}
+	for _, spec := range templates {
+		status := api.ComposableTemplateStatus{Name: spec.Name, Kind: api.ComposableTemplateKindIndex}
+		if declared[spec.Name] {
+			status.State = api.ComposableTemplateStateFailed
+			status.Message = "The index template is declared more than once"
+			statuses = append(statuses, status)
+			continue
+		}
+		declared[spec.Name] = true
+
+		unresolved := []string{}
+		for _, name := range spec.ComposedOf {
+			if !available[name] {
+				unresolved = append(unresolved, name)
+			}
+		}
+
+		switch {
+		case len(unresolved) > 0:
+			status.State = api.ComposableTemplateStateUnresolved
+			status.Message = fmt.Sprintf("Composed of missing component templates: %s", strings.Join(unresolved, ", "))
+		case conflicts[spec.Name] != "":
+			status.State = api.ComposableTemplateStateConflict
+			status.Message = conflicts[spec.Name]
+		default:
+			template, err := newComposableIndexTemplate(spec)
+			if err == nil {
+				err = er.esClient.CreateOrUpdateComposableIndexTemplate(spec.Name, template)
+			}
+			if err != nil {
+				er.L().Error(err, "failed to apply composable index template", "template", spec.Name)
+				status.State = api.ComposableTemplateStateFailed
+				status.Message = elasticsearchErrorReason(err)
+			} else {
+				status.State = api.ComposableTemplateStateApplied
+				status.Message = shadows[spec.Name]
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return er.updateComposableTemplatesStatus(statuses)
+}
+
+// removeComposableTemplates deletes the templates of the status which are no longer declared. The
+// index templates are deleted first as the cluster refuses to delete components in use
+func (er *ElasticsearchRequest) removeComposableTemplates(components []api.ComponentTemplateSpec, templates []api.ComposableIndexTemplateSpec) {
+	declared := map[api.ComposableTemplateKind]map[string]bool{
+		api.ComposableTemplateKindComponent: {},
+		api.ComposableTemplateKindIndex:     {},
+	}
+	for _, spec := range components {
+		declared[api.ComposableTemplateKindComponent][spec.Name] = true
+	}
+	for _, spec := range templates {
+		declared[api.ComposableTemplateKindIndex][spec.Name] = true
+	}
+
+	for _, kind := range []api.ComposableTemplateKind{api.ComposableTemplateKindIndex, api.ComposableTemplateKindComponent} {
+		for _, status := range er.cluster.Status.ComposableTemplates {
+			if status.Kind != kind || declared[kind][status.Name] {
+				continue
+			}
+
+			var err error
+			if kind == api.ComposableTemplateKindIndex {
+				err = er.esClient.DeleteComposableIndexTemplate(status.Name)
+			} else {
+				err = er.esClient.DeleteComponentTemplate(status.Name)
+			}
+			if err != nil {
+				er.L().Error(err, "failed to delete removed composable template", "template", status.Name, "kind", kind)
+			}
+		}
+	}
+}
+
+func unsupportedComposableTemplates(components []api.ComponentTemplateSpec, templates []api.ComposableIndexTemplateSpec, message string) []api.ComposableTemplateStatus {
+	statuses := []api.ComposableTemplateStatus{}
+	for _, spec := range components {
+		statuses = append(statuses, api.ComposableTemplateStatus{
+			Name:    spec.Name,
+			Kind:    api.ComposableTemplateKindComponent,
+			State:   api.ComposableTemplateStateUnsupported,
+			Message: message,
+		})
+	}
+	for _, spec := range templates {
+		statuses = append(statuses, api.ComposableTemplateStatus{
+			Name:    spec.Name,
+			Kind:    api.ComposableTemplateKindIndex,
+			State:   api.ComposableTemplateStateUnsupported,
+			Message: message,
+		})
+	}
+	return statuses
+}
+
+// composableTemplateOverlaps returns the index templates overlapping another template of the same
+// priority, which the cluster rejects, and the templates overlapping a template of a higher
+// priority, which never apply to the indices matched by both
+func composableTemplateOverlaps(templates []api.ComposableIndexTemplateSpec) (map[string]string, map[string]string) {
+	conflicts := map[string][]string{}
+	shadows := map[string][]string{}
+	for i, a := range templates {
+		for _, b := range templates[i+1:] {
+			if a.Name == b.Name || !templatesOverlap(a, b) {
+				continue
+			}
+
+			switch {
+			case a.Priority == b.Priority:
+				conflicts[a.Name] = append(conflicts[a.Name], b.Name)
+				conflicts[b.Name] = append(conflicts[b.Name], a.Name)
+			case a.Priority < b.Priority:
+				shadows[a.Name] = append(shadows[a.Name], b.Name)
+			default:
+				shadows[b.Name] = append(shadows[b.Name], a.Name)
+			}
+		}
+	}
+
+	conflictMessages := map[string]string{}
+	for name, others := range conflicts {
+		sort.Strings(others)
+		conflictMessages[name] = fmt.Sprintf("Overlaps the index templates of the same priority: %s", strings.Join(others, ", "))
+	}
+	shadowMessages := map[string]string{}
+	for name, others := range shadows {
+		sort.Strings(others)
+		shadowMessages[name] = fmt.Sprintf("Overridden for the overlapping indices by the index templates of a higher priority: %s", strings.Join(others, ", "))
+	}
+	return conflictMessages, shadowMessages
+}
+
+func templatesOverlap(a, b api.ComposableIndexTemplateSpec) bool {
+	for _, patternA := range a.IndexPatterns {
+		for _, patternB := range b.IndexPatterns {
+			if indexPatternsOverlap(patternA, patternB) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// indexPatternsOverlap returns true if an index name may match both patterns. Two wildcard
+// patterns overlap if their literal prefixes and suffixes are compatible
+func indexPatternsOverlap(a, b string) bool {
+	if !strings.Contains(a, "*") {
+		return indexPatternMatches(b, a)
+	}
+	if !strings.Contains(b, "*") {
+		return indexPatternMatches(a, b)
+	}
+
+	prefixA, prefixB := a[:strings.Index(a, "*")], b[:strings.Index(b, "*")]
+	suffixA, suffixB := a[strings.LastIndex(a, "*")+1:], b[strings.LastIndex(b, "*")+1:]
+	return (strings.HasPrefix(prefixA, prefixB) || strings.HasPrefix(prefixB, prefixA)) &&
+		(strings.HasSuffix(suffixA, suffixB) || strings.HasSuffix(suffixB, suffixA))
+}
+
+func indexPatternMatches(pattern, name string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	return regexp.MustCompile(expr).MatchString(name)
+}
+
+func newComponentTemplate(spec api.ComponentTemplateSpec) (*estypes.ComponentTemplate, error) {
+	definition, err := newTemplateDefinition(spec.Settings, spec.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Aliases) > 0 {
+		definition.Aliases = map[string]estypes.IndexAlias{}
+		for _, alias := range spec.Aliases {
+			definition.Aliases[alias] = estypes.IndexAlias{}
+		}
+	}
+	return &estypes.ComponentTemplate{Template: definition}, nil
+}
+
+func newComposableIndexTemplate(spec api.ComposableIndexTemplateSpec) (*estypes.ComposableIndexTemplate, error) {
+	definition, err := newTemplateDefinition(spec.Settings, spec.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	return &estypes.ComposableIndexTemplate{
+		IndexPatterns: spec.IndexPatterns,
+		ComposedOf:    spec.ComposedOf,
+		Priority:      spec.Priority,
+		Template:      definition,
+	}, nil
+}
+
+func newTemplateDefinition(settings map[string]string, mappings *runtime.RawExtension) (estypes.IndexDefinition, error) {
+	definition := estypes.IndexDefinition{}
+	if len(settings) > 0 {
+		definition.Settings = map[string]interface{}{}
+		for name, value := range settings {
+			definition.Settings[name] = value
+		}
+	}
+	if mappings != nil && len(mappings.Raw) > 0 {
+		if err := json.Unmarshal(mappings.Raw, &definition.Mappings); err != nil {
+			return definition, kverrors.Wrap(err, "failed to decode the mappings of the template")
+		}
+	}
+	return definition, nil
+}
+
+func (er *ElasticsearchRequest) updateComposableTemplatesStatus(statuses []api.ComposableTemplateStatus) error {
+	cluster := er.cluster
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+
+	if reflect.DeepEqual(cluster.Status.ComposableTemplates, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.ComposableTemplates, statuses) {
+			return nil
+		}
+
+		cluster.Status.ComposableTemplates = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update composable templates status")
+}
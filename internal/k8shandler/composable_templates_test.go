@@ -0,0 +1,132 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Composable templates", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	newRequest := func(version string, responses map[string]helpers.FakeElasticsearchResponses) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+
+		responses["_cluster/stats/nodes/_all"] = helpers.FakeElasticsearchResponses{
+			{StatusCode: http.StatusOK, Body: `{"nodes": {"versions": ["` + version + `"]}}`},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(responses)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				IndexManagement: &api.IndexManagementSpec{
+					ComponentTemplates: []api.ComponentTemplateSpec{
+						{Name: "logs-settings", Settings: map[string]string{"index.refresh_interval": "30s"}},
+					},
+					IndexTemplates: []api.ComposableIndexTemplateSpec{
+						{Name: "app", IndexPatterns: []string{"app-*"}, ComposedOf: []string{"logs-settings"}, Priority: 10},
+						{Name: "app-audit", IndexPatterns: []string{"app-audit*"}, Priority: 20},
+						{Name: "infra", IndexPatterns: []string{"infra-*"}, ComposedOf: []string{"logs-settings", "logs-mappings"}},
+					},
+				},
+			},
+		}
+	})
+
+	It("should apply the components before the index templates composed of them", func() {
+		newRequest("7.10.2", map[string]helpers.FakeElasticsearchResponses{
+			"_component_template/logs-settings": {{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}},
+			"_component_template":               {{StatusCode: http.StatusOK, Body: `{"component_templates": [{"name": "logs-settings"}]}`}},
+			"_index_template/app":               {{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}},
+			"_index_template/app-audit":         {{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}},
+		})
+
+		Expect(request.ReconcileComposableTemplates()).To(Succeed())
+
+		component, found := chatter.GetRequest("_component_template/logs-settings")
+		Expect(found).To(BeTrue())
+		Expect(component.Body).To(MatchJSON(`{"template": {"settings": {"index.refresh_interval": "30s"}}}`))
+		template, found := chatter.GetRequest("_index_template/app")
+		Expect(found).To(BeTrue())
+		Expect(template.Body).To(MatchJSON(`{"index_patterns": ["app-*"], "composed_of": ["logs-settings"], "priority": 10, "template": {}}`))
+		Expect(component.SeqNo).To(BeNumerically("<", template.SeqNo))
+		Expect(chatter.Requests).ToNot(HaveKey("_index_template/infra"))
+
+		Expect(cluster.Status.ComposableTemplates).To(Equal([]api.ComposableTemplateStatus{
+			{Name: "logs-settings", Kind: api.ComposableTemplateKindComponent, State: api.ComposableTemplateStateApplied},
+			{
+				Name:    "app",
+				Kind:    api.ComposableTemplateKindIndex,
+				State:   api.ComposableTemplateStateApplied,
+				Message: "Overridden for the overlapping indices by the index templates of a higher priority: app-audit",
+			},
+			{Name: "app-audit", Kind: api.ComposableTemplateKindIndex, State: api.ComposableTemplateStateApplied},
+			{
+				Name:    "infra",
+				Kind:    api.ComposableTemplateKindIndex,
+				State:   api.ComposableTemplateStateUnresolved,
+				Message: "Composed of missing component templates: logs-mappings",
+			},
+		}))
+	})
+
+	It("should not apply overlapping index templates of the same priority", func() {
+		conflicts, _ := composableTemplateOverlaps([]api.ComposableIndexTemplateSpec{
+			{Name: "app", IndexPatterns: []string{"app-*"}},
+			{Name: "app-write", IndexPatterns: []string{"app-write"}},
+			{Name: "infra", IndexPatterns: []string{"infra-*"}},
+		})
+
+		Expect(conflicts).To(Equal(map[string]string{
+			"app":       "Overlaps the index templates of the same priority: app-write",
+			"app-write": "Overlaps the index templates of the same priority: app",
+		}))
+	})
+
+	It("should report the templates as unsupported before Elasticsearch 7.8", func() {
+		newRequest("6.8.1", map[string]helpers.FakeElasticsearchResponses{})
+
+		Expect(request.ReconcileComposableTemplates()).To(Succeed())
+		Expect(cluster.Status.ComposableTemplates).To(HaveLen(4))
+		Expect(cluster.Status.ComposableTemplates[0].State).To(Equal(api.ComposableTemplateStateUnsupported))
+		Expect(chatter.Requests).ToNot(HaveKey("_component_template/logs-settings"))
+	})
+})
@@ -0,0 +1,227 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// configRestart is the restart of the nodes required by a change of their configuration
+type configRestart int
+
+const (
+	configRestartNone configRestart = iota
+	configRestartRolling
+	configRestartFullCluster
+)
+
+var (
+	// fullRestartSettings are the static settings of elasticsearch.yml which all nodes of the
+	// cluster must agree on, so the nodes cannot be restarted one after the other
+	fullRestartSettings = []string{
+		"cluster.name",
+		"opendistro_security.authcz",
+		"opendistro_security.config_index_name",
+		"opendistro_security.ssl.transport.enabled",
+		"opendistro_security.ssl.http.enabled",
+		"opendistro_security.ssl.transport.enforce_hostname_verification",
+	}
+
+	// ignoredRestartSettings are the settings of elasticsearch.yml which the operator updates
+	// through the API or which are only read when the whole cluster recovers
+	ignoredRestartSettings = []string{
+		"discovery.zen.minimum_master_nodes",
+		"gateway",
+	}
+)
+
+// configChangeRestart returns the restart required by the changes of the configuration files
+// from current to desired and the changed settings requiring a full cluster restart. The index
+// settings are applied through the index templates, the JVM options through the pod template and
+// the levels of the loggers through the cluster settings
+func configChangeRestart(current, desired map[string]string) (configRestart, []string) {
+	restart := configRestartNone
+	if log4jChangeRequiresRestart(current[log4jConfig], desired[log4jConfig]) {
+		restart = configRestartRolling
+	}
+
+	fullRestart := []string{}
+	for _, setting := range changedSettings(current[esConfig], desired[esConfig]) {
+		switch {
+		case matchesSetting(setting, ignoredRestartSettings):
+			continue
+		case matchesSetting(setting, fullRestartSettings):
+			fullRestart = append(fullRestart, setting)
+		default:
+			restart = configRestartRolling
+		}
+	}
+
+	if len(fullRestart) > 0 {
+		return configRestartFullCluster, fullRestart
+	}
+	return restart, nil
+}
+
+// changedSettings returns the sorted names of the settings of elasticsearch.yml added, removed or
+// changed. A configuration which cannot be parsed changes all of its settings
+func changedSettings(current, desired string) []string {
+	currentSettings := flattenSettings(current)
+	desiredSettings := flattenSettings(desired)
+
+	names := []string{}
+	for name, value := range desiredSettings {
+		if currentValue, ok := currentSettings[name]; !ok || currentValue != value {
+			names = append(names, name)
+		}
+	}
+	for name := range currentSettings {
+		if _, ok := desiredSettings[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flattenSettings returns the settings of elasticsearch.yml by their dotted names
+func flattenSettings(config string) map[string]string {
+	settings := map[string]string{}
+	if config == "" {
+		return settings
+	}
+
+	values, err := flattenConfig(config)
+	if err != nil {
+		settings[""] = config
+		return settings
+	}
+	for name, value := range values {
+		settings[name] = fmt.Sprint(value)
+	}
+	return settings
+}
+
+// flattenConfig returns the values of the settings of a YAML configuration by their dotted names
+func flattenConfig(config string) (map[string]interface{}, error) {
+	values := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal([]byte(config), &values); err != nil {
+		return nil, err
+	}
+
+	settings := map[string]interface{}{}
+	flattenSetting("", values, settings)
+	return settings, nil
+}
+
+func flattenSetting(prefix string, value interface{}, settings map[string]interface{}) {
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		settings[prefix] = value
+		return
+	}
+
+	for key, value := range values {
+		name := fmt.Sprint(key)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flattenSetting(name, value, settings)
+	}
+}
+
+// matchesSetting returns true if the setting is one of the settings or nested below one of them
+func matchesSetting(setting string, settings []string) bool {
+	for _, s := range settings {
+		if setting == s || strings.HasPrefix(setting, s+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleConfigRestart schedules the restart of all nodes for the changes of their configuration
+// files. A scheduled full cluster restart is announced with the FullRestartScheduled condition and
+// is not replaced by a later rolling restart
+func (er *ElasticsearchRequest) scheduleConfigRestart(current, desired map[string]string) error {
+	restart, settings := configChangeRestart(current, desired)
+	if restart == configRestartNone {
+		return nil
+	}
+
+	cluster := er.cluster
+	if restart == configRestartFullCluster {
+		er.L().Info("Scheduling full cluster restart for the changed configuration", "settings", settings)
+	} else {
+		er.L().Info("Scheduling rolling restart for the changed configuration")
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		for i := range cluster.Status.Nodes {
+			cluster.Status.Nodes[i].UpgradeStatus.ScheduledForRedeploy = v1.ConditionTrue
+		}
+		if restart == configRestartFullCluster {
+			updateESNodeCondition(&cluster.Status, &api.ClusterCondition{
+				Type:    api.FullRestartScheduled,
+				Status:  v1.ConditionTrue,
+				Reason:  "StaticSettingsChanged",
+				Message: fmt.Sprintf("The nodes are restarted all at once for the changed settings %s", strings.Join(settings, ", ")),
+			})
+		}
+
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to schedule restart for the changed configuration")
+}
+
+func (er *ElasticsearchRequest) getScheduledRedeployNodes() []NodeTypeInterface {
+	cluster := er.cluster
+	redeployNodes := []NodeTypeInterface{}
+
+	for _, node := range cluster.Status.Nodes {
+		if node.UpgradeStatus.ScheduledForRedeploy == v1.ConditionTrue {
+			for _, nodeTypeInterface := range nodes[nodeMapKey(cluster.Name, cluster.Namespace)] {
+				if node.DeploymentName == nodeTypeInterface.name() ||
+					node.StatefulSetName == nodeTypeInterface.name() {
+					redeployNodes = append(redeployNodes, nodeTypeInterface)
+				}
+			}
+		}
+	}
+
+	return redeployNodes
+}
+
+// PerformConfigRestart restarts the nodes scheduled for the changes of their configuration, all at
+// once if a full cluster restart is scheduled or else one after the other
+func (er *ElasticsearchRequest) PerformConfigRestart(nodes []NodeTypeInterface) error {
+	if !containsClusterCondition(api.FullRestartScheduled, v1.ConditionTrue, &er.cluster.Status) {
+		return er.PerformRollingRestart(nodes)
+	}
+
+	if err := er.PerformFullClusterRestart(nodes); err != nil {
+		return err
+	}
+
+	clusterStatus := er.cluster.Status.DeepCopy()
+	for i := range clusterStatus.Nodes {
+		clusterStatus.Nodes[i].UpgradeStatus.ScheduledForRedeploy = ""
+	}
+	updateESNodeCondition(clusterStatus, &api.ClusterCondition{
+		Type:   api.FullRestartScheduled,
+		Status: v1.ConditionFalse,
+	})
+	return er.updateNodeStatus(*clusterStatus)
+}
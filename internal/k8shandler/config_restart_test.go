@@ -0,0 +1,86 @@
+package k8shandler
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Configuration restarts", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{DeploymentName: "elasticsearch-cdm-abc-1"},
+					{DeploymentName: "elasticsearch-cdm-abc-2"},
+				},
+			},
+		}
+	})
+
+	It("should not restart the nodes for the settings updated through the API", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		cluster.Spec.Nodes[0].NodeCount = 5
+
+		restart, _ := configChangeRestart(current, newClusterConfigMap(cluster, nil).Data)
+		Expect(restart).To(Equal(configRestartNone))
+	})
+
+	It("should restart the nodes one after the other for node settings", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		cluster.Spec.ZoneAwareness = &api.ZoneAwarenessSpec{}
+
+		restart, _ := configChangeRestart(current, newClusterConfigMap(cluster, nil).Data)
+		Expect(restart).To(Equal(configRestartRolling))
+	})
+
+	It("should restart the whole cluster for static cluster-wide settings", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		desired := newClusterConfigMap(cluster, nil).Data
+		desired[esConfig] = strings.Replace(desired[esConfig], "CN=system.admin", "CN=admin", 1)
+
+		restart, settings := configChangeRestart(current, desired)
+		Expect(restart).To(Equal(configRestartFullCluster))
+		Expect(settings).To(Equal([]string{"opendistro_security.authcz.admin_dn"}))
+	})
+
+	It("should schedule the nodes and announce a full cluster restart", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		request := &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+
+		current := newClusterConfigMap(cluster, nil).Data
+		desired := newClusterConfigMap(cluster, nil).Data
+		desired[esConfig] = strings.Replace(desired[esConfig], "name: ${CLUSTER_NAME}", "name: logging", 1)
+		Expect(request.scheduleConfigRestart(current, desired)).To(Succeed())
+
+		for _, node := range cluster.Status.Nodes {
+			Expect(node.UpgradeStatus.ScheduledForRedeploy).To(Equal(v1.ConditionTrue))
+		}
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.FullRestartScheduled)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(Equal("The nodes are restarted all at once for the changed settings cluster.name"))
+	})
+})
@@ -0,0 +1,263 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultConfigValidationTimeout = 10 * time.Minute
+	// configHashAnnotation records the hash of the configuration a validation pod boots from
+	configHashAnnotation = "elasticsearch.openshift.io/config-hash"
+)
+
+// configValidationName returns the name of the validation pod and of its candidate configmap
+func configValidationName(clusterName string) string {
+	return fmt.Sprintf("%s-config-validation", clusterName)
+}
+
+// validateConfigMap returns true once the node of the validation pod started with the data of the
+// configmap. The validation pod is recreated whenever the data changes and kept on failure for its
+// logs until the configuration changes again
+func (er *ElasticsearchRequest) validateConfigMap(configmap *v1.ConfigMap, now time.Time) (bool, error) {
+	cluster := er.cluster
+	name := configValidationName(cluster.Name)
+	hash := configDataHash(configmap.Data)
+
+	pod := &v1.Pod{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, kverrors.Wrap(err, "failed to get config validation pod",
+				"pod", name)
+		}
+		er.L().Info("Validating new configuration", "pod", name)
+		return false, er.createConfigValidation(configmap, hash)
+	}
+
+	// wait for the validation of a former configuration to go away
+	if pod.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+
+	if pod.Annotations[configHashAnnotation] != hash {
+		er.L().Info("Configuration changed during its validation, restarting the validation", "pod", name)
+		return false, er.deleteConfigValidationObject(pod, name)
+	}
+
+	if isPodConditionTrue(pod, v1.PodReady) {
+		er.L().Info("Validated new configuration", "pod", name)
+		if err := er.removeConfigValidation(); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	timeout := defaultConfigValidationTimeout
+	if spec := cluster.Spec.ConfigValidation; spec != nil && spec.Timeout != nil && spec.Timeout.Duration > 0 {
+		timeout = spec.Timeout.Duration
+	}
+	if message := configValidationFailure(pod, timeout, now); message != "" {
+		return false, updateConfigValidationFailedCondition(cluster, v1.ConditionTrue, message, er.client)
+	}
+	return false, nil
+}
+
+// createConfigValidation stores the data of the configmap in the candidate configmap and creates
+// the validation pod booting from it
+func (er *ElasticsearchRequest) createConfigValidation(configmap *v1.ConfigMap, hash string) error {
+	cluster := er.cluster
+
+	candidate := configmap.DeepCopy()
+	candidate.Name = configValidationName(cluster.Name)
+	candidate.ResourceVersion = ""
+	candidate.OwnerReferences = nil
+	cluster.AddOwnerRefTo(candidate)
+
+	err := er.client.Create(context.TODO(), candidate)
+	if apierrors.IsAlreadyExists(kverrors.Root(err)) {
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current := &v1.ConfigMap{}
+			if err := er.client.Get(context.TODO(), types.NamespacedName{Name: candidate.Name, Namespace: candidate.Namespace}, current); err != nil {
+				return err
+			}
+			current.Data = candidate.Data
+			return er.client.Update(context.TODO(), current)
+		})
+	}
+	if err != nil {
+		return kverrors.Wrap(err, "failed to reconcile candidate configmap",
+			"configmap", candidate.Name)
+	}
+
+	pod, err := newConfigValidationPod(cluster, hash, er.client)
+	if err != nil {
+		return err
+	}
+	cluster.AddOwnerRefTo(pod)
+
+	if err := er.client.Create(context.TODO(), pod); err != nil && !apierrors.IsAlreadyExists(kverrors.Root(err)) {
+		return kverrors.Wrap(err, "failed to create config validation pod",
+			"pod", pod.Name)
+	}
+	return nil
+}
+
+// removeConfigValidation deletes the validation pod and the candidate configmap and clears the
+// ConfigValidationFailed condition
+func (er *ElasticsearchRequest) removeConfigValidation() error {
+	cluster := er.cluster
+	key := types.NamespacedName{Name: configValidationName(cluster.Name), Namespace: cluster.Namespace}
+
+	for _, obj := range []runtime.Object{&v1.Pod{}, &v1.ConfigMap{}} {
+		if err := er.client.Get(context.TODO(), key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return kverrors.Wrap(err, "failed to get config validation object",
+				"name", key.Name)
+		}
+		if err := er.deleteConfigValidationObject(obj, key.Name); err != nil {
+			return err
+		}
+	}
+
+	return updateConfigValidationFailedCondition(cluster, v1.ConditionFalse, "", er.client)
+}
+
+func (er *ElasticsearchRequest) deleteConfigValidationObject(obj runtime.Object, name string) error {
+	if err := er.client.Delete(context.TODO(), obj); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete config validation object",
+			"name", name)
+	}
+	return nil
+}
+
+// newConfigValidationPod returns the pod booting the first node of the cluster from the candidate
+// configmap. The node uses no data and a cluster name of its own to keep it from joining the cluster.
+// It is ready once it accepts HTTP connections which it only does after passing its bootstrap checks
+func newConfigValidationPod(cluster *api.Elasticsearch, hash string, client client.Client) (*v1.Pod, error) {
+	name := configValidationName(cluster.Name)
+
+	node := *cluster.Spec.Nodes[0].DeepCopy()
+	node.Storage = api.ElasticsearchStorageSpec{}
+	node.NodeName = ""
+	roleMap := getNodeRoleMap(node)
+
+	labels := map[string]string{
+		"cluster-name": cluster.Name,
+		"component":    "elasticsearch-config-validation",
+	}
+
+	options, err := newPodTemplateOptions(cluster, node, client)
+	if err != nil {
+		return nil, err
+	}
+	template := newPodTemplateSpec(name, node, labels, roleMap, client, options)
+
+	applyFIPSMode(cluster, &template.Spec)
+	applyThreadPools(cluster, node, &template.Spec)
+	applyAuditLogVolume(cluster, &template.Spec)
+	applyPodSecurity(cluster, &template)
+
+	spec := template.Spec
+	spec.RestartPolicy = v1.RestartPolicyNever
+
+	esContainer := spec.Containers[0]
+	esContainer.ReadinessProbe = &v1.Probe{
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       5,
+		Handler: v1.Handler{
+			TCPSocket: &v1.TCPSocketAction{
+				Port: intstr.FromInt(9200),
+			},
+		},
+	}
+	esContainer.Env = append([]v1.EnvVar{}, esContainer.Env...)
+	for i := range esContainer.Env {
+		if esContainer.Env[i].Name == "CLUSTER_NAME" {
+			esContainer.Env[i].Value = name
+		}
+	}
+	spec.Containers = []v1.Container{esContainer}
+
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == "elasticsearch-config" {
+			spec.Volumes[i].ConfigMap.Name = name
+		}
+	}
+
+	return &v1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: v1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cluster.Namespace,
+			Labels:      labels,
+			Annotations: mergeSelectors(map[string]string{configHashAnnotation: hash}, template.Annotations),
+		},
+		Spec: spec,
+	}, nil
+}
+
+// configValidationFailure returns why the node of the validation pod failed to start or an empty
+// string if it may still start
+func configValidationFailure(pod *v1.Pod, timeout time.Duration, now time.Time) string {
+	if pod.Status.Phase == v1.PodFailed {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != "elasticsearch" || status.State.Terminated == nil {
+				continue
+			}
+			return fmt.Sprintf("Elasticsearch failed to start with the new configuration in pod %s: exit code %d (%s). The configuration is not rolled out",
+				pod.Name, status.State.Terminated.ExitCode, status.State.Terminated.Reason)
+		}
+		return fmt.Sprintf("Elasticsearch failed to start with the new configuration in pod %s: %s. The configuration is not rolled out",
+			pod.Name, pod.Status.Reason)
+	}
+
+	if now.Sub(pod.CreationTimestamp.Time) > timeout {
+		return fmt.Sprintf("Elasticsearch did not start with the new configuration in pod %s within %s. The configuration is not rolled out",
+			pod.Name, timeout)
+	}
+	return ""
+}
+
+func isPodConditionTrue(pod *v1.Pod, conditionType v1.PodConditionType) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// configDataHash returns a hash of the data of a configmap
+func configDataHash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s\x00%s\x00", key, data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
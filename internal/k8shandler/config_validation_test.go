@@ -0,0 +1,142 @@
+package k8shandler
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Config validation", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		key     types.NamespacedName
+	)
+
+	getConfigMap := func(name string) (*v1.ConfigMap, error) {
+		configmap := &v1.ConfigMap{}
+		err := request.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: key.Namespace}, configmap)
+		return configmap, err
+	}
+
+	getPod := func() (*v1.Pod, error) {
+		pod := &v1.Pod{}
+		err := request.client.Get(context.TODO(), key, pod)
+		return pod, err
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		size := resource.MustParse("10Gi")
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData},
+						NodeCount: 1,
+						Storage:   api.ElasticsearchStorageSpec{Size: &size},
+					},
+				},
+				ConfigValidation: &api.ConfigValidationSpec{},
+			},
+		}
+		live := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Data:       map[string]string{esConfig: "cluster.name: old"},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, live),
+			cluster: cluster,
+		}
+		key = types.NamespacedName{Name: "elasticsearch-config-validation", Namespace: "openshift-logging"}
+	})
+
+	It("should boot the new configuration in a validation pod before rolling it out", func() {
+		Expect(request.CreateOrUpdateConfigMaps()).To(Succeed())
+
+		live, err := getConfigMap("elasticsearch")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(live.Data[esConfig]).To(Equal("cluster.name: old"))
+
+		candidate, err := getConfigMap(key.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(candidate.Data[esConfig]).To(ContainSubstring("discovery.zen"))
+
+		pod, err := getPod()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(v1.EnvVar{Name: "CLUSTER_NAME", Value: key.Name}))
+		Expect(pod.Spec.Volumes).To(ContainElement(v1.Volume{
+			Name:         "elasticsearch-storage",
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		}))
+		Expect(pod.Labels["component"]).ToNot(Equal("elasticsearch"))
+
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+		Expect(request.client.Update(context.TODO(), pod)).To(Succeed())
+
+		Expect(request.CreateOrUpdateConfigMaps()).To(Succeed())
+
+		live, err = getConfigMap("elasticsearch")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(live.Data).To(Equal(candidate.Data))
+
+		_, err = getPod()
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		_, err = getConfigMap(key.Name)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep the configuration back if Elasticsearch fails to start with it", func() {
+		Expect(request.CreateOrUpdateConfigMaps()).To(Succeed())
+
+		pod, err := getPod()
+		Expect(err).ToNot(HaveOccurred())
+		pod.Status.Phase = v1.PodFailed
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{
+			{
+				Name:  "elasticsearch",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 78, Reason: "Error"}},
+			},
+		}
+		Expect(request.client.Update(context.TODO(), pod)).To(Succeed())
+
+		Expect(request.CreateOrUpdateConfigMaps()).To(Succeed())
+
+		live, err := getConfigMap("elasticsearch")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(live.Data[esConfig]).To(Equal("cluster.name: old"))
+
+		_, condition := getESNodeCondition(request.cluster.Status.Conditions, api.ConfigValidationFailed)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(ContainSubstring("exit code 78"))
+
+		_, err = getPod()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail the validation once the timeout passed", func() {
+		created := time.Now()
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: key.Name, CreationTimestamp: metav1.NewTime(created)}}
+
+		Expect(configValidationFailure(pod, defaultConfigValidationTimeout, created.Add(time.Minute))).To(BeEmpty())
+		Expect(configValidationFailure(pod, defaultConfigValidationTimeout, created.Add(11*time.Minute))).To(ContainSubstring("within 10m0s"))
+	})
+})
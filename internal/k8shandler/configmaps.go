@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"reflect"
 	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,12 +37,45 @@ type esYmlStruct struct {
 	NodeQuorum           string
 	RecoverExpectedNodes string
 	SystemCallFilter     string
+	ReindexWhitelist     string
+	TransportTruststore  string
+	DataTiers            bool
+	MemoryLock           bool
+	ZoneAwareness        bool
+	IngestRoles          bool
+	MachineLearning      bool
+	VotingOnly           bool
+	CacheLimits          bool
+	NodesDN              bool
+	HTTPTLS              bool
+	FIPSMode             bool
+	ThreadPools          bool
+	WriteThreadPool      bool
+	TransportTrustedCAs  string
+	FIPSProtocols        string
+	FIPSCiphers          string
 }
 
 type log4j2PropertiesStruct struct {
-	RootLogger       string
-	LogLevel         string
-	SecurityLogLevel string
+	RootLogger              string
+	LogLevel                string
+	SecurityLogLevel        string
+	DeprecationLogLevel     string
+	SearchSlowlogLogLevel   string
+	IndexingSlowlogLogLevel string
+	Loggers                 []log4j2Logger
+	RollingMaxFileSize      string
+	RollingMaxFiles         int32
+	AuditLogger             string
+	AuditAppender           string
+	AuditMaxFileSize        string
+	AuditMaxFiles           int32
+}
+
+type log4j2Logger struct {
+	ID    string
+	Name  string
+	Level string
 }
 
 type indexSettingsStruct struct {
@@ -103,28 +139,17 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMap(cm *v1.ConfigMap) error
 func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() (err error) {
 	dpl := er.cluster
 
-	kibanaIndexMode, err := kibanaIndexMode("")
+	reindexes, err := er.listElasticsearchReindexes()
 	if err != nil {
 		return err
 	}
-	dataNodeCount := int(getDataCount(dpl))
-	masterNodeCount := int(getMasterCount(dpl))
 
-	logConfig := getLogConfig(dpl.GetAnnotations())
-
-	configmap := newConfigMap(
-		dpl.Name,
-		dpl.Namespace,
-		dpl.Labels,
-		kibanaIndexMode,
-		esUnicastHost(dpl.Name, dpl.Namespace),
-		strconv.Itoa(masterNodeCount/2+1),
-		strconv.Itoa(dataNodeCount),
-		strconv.Itoa(calculatePrimaryCount(dpl)),
-		strconv.Itoa(calculateReplicaCount(dpl)),
-		strconv.FormatBool(runtime.GOARCH == "amd64"),
-		logConfig,
-	)
+	configmap := newClusterConfigMap(dpl, reindexes)
+	if configmap == nil {
+		return kverrors.New("failed to render elasticsearch configmap",
+			"cluster", dpl.Name,
+			"namespace", dpl.Namespace)
+	}
 
 	dpl.AddOwnerRefTo(configmap)
 
@@ -149,12 +174,33 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() (err error) {
 			"cluster", current.ClusterName)
 	}
 
-	if configMapContentChanged(current, configmap) {
+	changed := configMapContentChanged(current, configmap)
+
+	// roll out a new configuration once the validation pod started with it
+	if changed && dpl.Spec.ConfigValidation != nil {
+		validated, err := er.validateConfigMap(configmap, time.Now())
+		if err != nil || !validated {
+			return err
+		}
+	} else if err := er.removeConfigValidation(); err != nil {
+		return err
+	}
+
+	if changed {
 		// Cluster settings has changed, make sure it doesnt go unnoticed
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateUpdatingSettingsCondition, er.client); err != nil {
 			return err
 		}
 
+		// schedule the restart before the update so it is not lost if the update fails
+		if err := er.scheduleConfigRestart(current.Data, configmap.Data); err != nil {
+			return err
+		}
+
+		if err := er.updateLoggerLevels(current.Data[log4jConfig], configmap.Data[log4jConfig]); err != nil {
+			return err
+		}
+
 		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			if err := er.client.Get(context.TODO(), types.NamespacedName{Name: current.Name, Namespace: current.Namespace}, current); err != nil {
 				log.Error(err, "Could not get Elasticsearch configmap", configmap.Name)
@@ -181,22 +227,111 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() (err error) {
 	return nil
 }
 
-func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) (map[string]string, error) {
+// newClusterConfigMap returns the configmap holding the configuration files of the nodes of the cluster
+func newClusterConfigMap(dpl *api.Elasticsearch, reindexes []api.ElasticsearchReindex) *v1.ConfigMap {
+	configmap := newConfigMap(dpl.Name, dpl.Namespace, dpl.Labels, newConfigMapOptions(dpl, reindexes))
+	if configmap == nil {
+		return nil
+	}
+
+	for key, options := range newJVMOptionsData(dpl) {
+		configmap.Data[key] = options
+	}
+
+	// an invalid additional configuration is reported by the validation of the cluster
+	if dpl.Spec.AdditionalConfig != "" && additionalConfigViolation(dpl) == "" {
+		additional, err := renderAdditionalConfig(dpl)
+		if err != nil {
+			log.Error(err, "Failed to render the additional configuration", "cluster", dpl.Name)
+			return nil
+		}
+		configmap.Data[esConfig] += additional
+	}
+
+	// invalid realms are reported by the validation of the cluster
+	if realmsViolation(dpl) == "" {
+		realms, err := renderRealms(dpl)
+		if err != nil {
+			log.Error(err, "Failed to render the authentication realms", "cluster", dpl.Name)
+			return nil
+		}
+		configmap.Data[esConfig] += realms
+	}
+
+	// invalid audit logging is reported by the validation of the cluster
+	if auditLoggingViolation(dpl) == "" {
+		audit, err := renderAuditLogging(dpl)
+		if err != nil {
+			log.Error(err, "Failed to render the audit logging", "cluster", dpl.Name)
+			return nil
+		}
+		configmap.Data[esConfig] += audit
+	}
+	return configmap
+}
+
+// configMapOptions are the values of the configuration files of the nodes which derive from the
+// spec of the cluster
+type configMapOptions struct {
+	esYml              esYmlStruct
+	primaryShardsCount string
+	replicaShardsCount string
+	logConfig          LogConfig
+}
+
+// newConfigMapOptions returns the values of the configuration files of the nodes of the cluster
+func newConfigMapOptions(dpl *api.Elasticsearch, reindexes []api.ElasticsearchReindex) configMapOptions {
+	kibanaIndexMode, _ := kibanaIndexMode("")
+	dataNodeCount := int(getDataCount(dpl))
+	masterNodeCount := int(getMasterCount(dpl))
+
+	logConfig := newLogConfig(dpl)
+	applyAuditLogging(dpl, &logConfig)
+
+	return configMapOptions{
+		esYml: esYmlStruct{
+			KibanaIndexMode:      kibanaIndexMode,
+			EsUnicastHost:        esUnicastHost(dpl.Name, dpl.Namespace),
+			NodeQuorum:           strconv.Itoa(masterNodeCount/2 + 1),
+			RecoverExpectedNodes: strconv.Itoa(dataNodeCount),
+			SystemCallFilter:     strconv.FormatBool(runtime.GOARCH == "amd64"),
+			ReindexWhitelist:     remoteReindexWhitelist(dpl, reindexes),
+			TransportTruststore:  transportTruststore(dpl),
+			DataTiers:            usesDataTiers(dpl),
+			MemoryLock:           dpl.Spec.MemoryLock,
+			ZoneAwareness:        dpl.Spec.ZoneAwareness != nil,
+			IngestRoles:          usesIngestRoles(dpl),
+			MachineLearning:      usesMachineLearningNodes(dpl),
+			VotingOnly:           usesVotingOnlyNodes(dpl),
+			CacheLimits:          usesCacheLimits(dpl),
+			NodesDN:              identifiesNodesByDN(dpl),
+			HTTPTLS:              httpTLSEnabled(dpl),
+			FIPSMode:             fipsModeEnabled(dpl),
+			ThreadPools:          usesThreadPoolSizing(dpl),
+			WriteThreadPool:      usesWriteThreadPoolSize(dpl),
+		},
+		primaryShardsCount: strconv.Itoa(calculatePrimaryCount(dpl)),
+		replicaShardsCount: strconv.Itoa(calculateReplicaCount(dpl)),
+		logConfig:          logConfig,
+	}
+}
+
+func renderData(options configMapOptions) (map[string]string, error) {
 	data := map[string]string{}
 	buf := &bytes.Buffer{}
-	if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter); err != nil {
+	if err := renderEsYml(buf, options.esYml); err != nil {
 		return data, err
 	}
 	data[esConfig] = buf.String()
 
 	buf = &bytes.Buffer{}
-	if err := renderLog4j2Properties(buf, logConfig); err != nil {
+	if err := renderLog4j2Properties(buf, options.logConfig); err != nil {
 		return data, err
 	}
 	data[log4jConfig] = buf.String()
 
 	buf = &bytes.Buffer{}
-	if err := renderIndexSettings(buf, primaryShardsCount, replicaShardsCount); err != nil {
+	if err := renderIndexSettings(buf, options.primaryShardsCount, options.replicaShardsCount); err != nil {
 		return data, err
 	}
 	data[indexSettingsConfig] = buf.String()
@@ -205,9 +340,8 @@ func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes
 }
 
 // newConfigMap returns a v1.ConfigMap object
-func newConfigMap(configMapName, namespace string, labels map[string]string,
-	kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) *v1.ConfigMap {
-	data, err := renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter, logConfig)
+func newConfigMap(configMapName, namespace string, labels map[string]string, options configMapOptions) *v1.ConfigMap {
+	data, err := renderData(options)
 	if err != nil {
 		return nil
 	}
@@ -248,23 +382,25 @@ func configMapContentChanged(old, new *v1.ConfigMap) bool {
 		return true
 	}
 
+	if !reflect.DeepEqual(jvmOptionsData(old.Data), jvmOptionsData(new.Data)) {
+		return true
+	}
+
 	return false
 }
 
-func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter string) error {
+// renderEsYml renders elasticsearch.yml. The trusted CAs of the transport layer and the FIPS
+// protocols and ciphers derive from the other values
+func renderEsYml(w io.Writer, esy esYmlStruct) error {
 	t := template.New("elasticsearch.yml")
 	config := esYmlTmpl
 	t, err := t.Parse(config)
 	if err != nil {
 		return err
 	}
-	esy := esYmlStruct{
-		KibanaIndexMode:      kibanaIndexMode,
-		EsUnicastHost:        esUnicastHost,
-		NodeQuorum:           nodeQuorum,
-		RecoverExpectedNodes: recoverExpectedNodes,
-		SystemCallFilter:     systemCallFilter,
-	}
+	esy.TransportTrustedCAs = transportTrustedCAs(esy.TransportTruststore)
+	esy.FIPSProtocols = fipsProtocols
+	esy.FIPSCiphers = fipsCiphers
 
 	return t.Execute(w, esy)
 }
@@ -277,9 +413,25 @@ func renderLog4j2Properties(w io.Writer, logConfig LogConfig) error {
 	}
 
 	log4jProp := log4j2PropertiesStruct{
-		RootLogger:       logConfig.ServerAppender,
-		LogLevel:         logConfig.ServerLoglevel,
-		SecurityLogLevel: logConfig.LogLevel,
+		RootLogger:              logConfig.ServerAppender,
+		LogLevel:                logConfig.ServerLoglevel,
+		SecurityLogLevel:        logConfig.LogLevel,
+		DeprecationLogLevel:     logConfig.DeprecationLoglevel,
+		SearchSlowlogLogLevel:   logConfig.SearchSlowlogLoglevel,
+		IndexingSlowlogLogLevel: logConfig.IndexingSlowlogLoglevel,
+		RollingMaxFileSize:      logConfig.RollingMaxFileSize,
+		RollingMaxFiles:         logConfig.RollingMaxFiles,
+		AuditLogger:             logConfig.AuditLogger,
+		AuditAppender:           logConfig.AuditAppender,
+		AuditMaxFileSize:        logConfig.AuditMaxFileSize,
+		AuditMaxFiles:           logConfig.AuditMaxFiles,
+	}
+	for _, logger := range logConfig.Loggers {
+		log4jProp.Loggers = append(log4jProp.Loggers, log4j2Logger{
+			ID:    loggerID(logger.Name),
+			Name:  logger.Name,
+			Level: string(logger.Level),
+		})
 	}
 
 	return t.Execute(w, log4jProp)
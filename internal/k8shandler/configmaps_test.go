@@ -16,7 +16,11 @@ var _ = Describe("configmaps.go", func() {
 	Describe("#renderLog4j2Properties", func() {
 		It("should create a well-formed file without error", func() {
 			out := bytes.NewBufferString("")
-			logConfig := LogConfig{"debug", "trace", "mylogger"}
+			logConfig := getLogConfig(map[string]string{
+				loglevelAnnotation:          "debug",
+				serverLoglevelAnnotation:    "trace",
+				serverLogAppenderAnnotation: "mylogger",
+			})
 			if err := renderLog4j2Properties(out, logConfig); err != nil {
 				Fail(fmt.Sprintf("unable to render Log4J properties. %s\r\n", err.Error()))
 			}
@@ -111,7 +115,14 @@ var _ = Describe("configmaps", func() {
 	Describe("#renderEsYml", func() {
 		It("should produce an elasticsearch.yml for our managed elasticsearch instance", func() {
 			result := &bytes.Buffer{}
-			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false")).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
 			helpers.ExpectYaml(result.String()).ToEqual(`
 cluster:
   name: ${CLUSTER_NAME}
@@ -176,5 +187,151 @@ opendistro_security:
       truststore_filepath: /etc/elasticsearch/secret/truststore
       truststore_password: tspass`)
 		})
+
+		It("should allow reindex from the external cluster of a migration", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				ReindexWhitelist:     "old-es.example.com:9200",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\nreindex.remote.whitelist: old-es.example.com:9200\n"))
+		})
+
+		It("should add the data tier attribute to the nodes of a tiered cluster", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				DataTiers:            true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n  max_local_storage_nodes: 1\n  attr.data: ${DATA_TIER}\n"))
+		})
+
+		It("should lock the memory of the nodes when requested", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				MemoryLock:           true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n  system_call_filter: false\n  memory_lock: true\n"))
+		})
+
+		It("should disable ingest on the coordinating nodes", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				IngestRoles:          true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n  max_local_storage_nodes: 1\n  ingest: ${IS_INGEST}\n"))
+		})
+
+		It("should make the shard allocation aware of the zones of the nodes", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				ZoneAwareness:        true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\ncluster.routing.allocation.awareness.attributes: zone\n"))
+		})
+
+		It("should enable machine learning on the ml nodes", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				MachineLearning:      true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n  max_local_storage_nodes: 1\n  ml: ${IS_ML}\n"))
+			Expect(result.String()).To(ContainSubstring("\nxpack.ml.enabled: true\n"))
+		})
+
+		It("should render the voting only setting of the nodes", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				VotingOnly:           true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n  max_local_storage_nodes: 1\n  voting_only: ${IS_VOTING_ONLY}\n"))
+		})
+
+		It("should limit the caches of the nodes", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+				CacheLimits:          true,
+				HTTPTLS:              true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\nindices:\n  fielddata.cache.size: ${FIELDDATA_CACHE_SIZE}\n  queries.cache.size: ${QUERY_CACHE_SIZE}\n  requests.cache.size: ${REQUEST_CACHE_SIZE}\n"))
+		})
+
+		It("should serve the REST API without TLS when disabled", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/secret/searchguard.truststore",
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring("\n    transport:\n      enabled: true\n"))
+			Expect(result.String()).To(ContainSubstring("\n    http:\n      enabled: false\n"))
+		})
+
+		It("should load the PEM certificates with FIPS-approved TLS in FIPS mode", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, esYmlStruct{
+				EsUnicastHost:        "my.unicast.host",
+				NodeQuorum:           "7",
+				RecoverExpectedNodes: "4",
+				SystemCallFilter:     "false",
+				TransportTruststore:  "/etc/elasticsearch/remote-truststore/searchguard.truststore",
+				HTTPTLS:              true,
+				FIPSMode:             true,
+			})).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).ToNot(ContainSubstring("keystore"))
+			Expect(result.String()).To(ContainSubstring("\n      pemtrustedcas_filepath: /etc/elasticsearch/remote-truststore/admin-ca\n"))
+			Expect(result.String()).To(ContainSubstring("\n      pemcert_filepath: /etc/elasticsearch/secret/logging-es.crt\n"))
+
+			settings := flattenSettings(result.String())
+			Expect(settings).To(HaveKeyWithValue("opendistro_security.ssl.http.enabled_protocols", "[TLSv1.2]"))
+			Expect(settings).To(HaveKey("opendistro_security.ssl.transport.enabled_ciphers"))
+		})
 	})
 })
@@ -6,14 +6,56 @@ cluster:
 
 bootstrap:
   system_call_filter: {{.SystemCallFilter}}
+{{- if .MemoryLock}}
+  memory_lock: true
+{{- end}}
 
 node:
   name: ${DC_NAME}
   master: ${IS_MASTER}
   data: ${HAS_DATA}
   max_local_storage_nodes: 1
+{{- if .IngestRoles}}
+  ingest: ${IS_INGEST}
+{{- end}}
+{{- if .MachineLearning}}
+  ml: ${IS_ML}
+{{- end}}
+{{- if .VotingOnly}}
+  voting_only: ${IS_VOTING_ONLY}
+{{- end}}
+{{- if .DataTiers}}
+  attr.data: ${DATA_TIER}
+{{- end}}
 
 action.auto_create_index: "-*-write,+*"
+{{- if .ZoneAwareness}}
+
+cluster.routing.allocation.awareness.attributes: zone
+{{- end}}
+{{- if .MachineLearning}}
+
+xpack.ml.enabled: true
+{{- end}}
+{{- if .ThreadPools}}
+
+processors: ${NODE_PROCESSORS}
+{{- end}}
+{{- if .WriteThreadPool}}
+
+thread_pool.write.size: ${WRITE_THREAD_POOL_SIZE}
+{{- end}}
+{{- if .CacheLimits}}
+
+indices:
+  fielddata.cache.size: ${FIELDDATA_CACHE_SIZE}
+  queries.cache.size: ${QUERY_CACHE_SIZE}
+  requests.cache.size: ${REQUEST_CACHE_SIZE}
+{{- end}}
+{{- if .ReindexWhitelist}}
+
+reindex.remote.whitelist: {{.ReindexWhitelist}}
+{{- end}}
 
 network:
   publish_host: ${POD_IP}
@@ -41,6 +83,10 @@ http.max_header_size: 128kb
 opendistro_security:
   authcz.admin_dn:
   - CN=system.admin,OU=OpenShift,O=Logging
+{{- if .NodesDN}}
+  nodes_dn:
+  - CN=elasticsearch,OU=OpenShift,O=Logging
+{{- end}}
   config_index_name: ".security"
   restapi:
     roles_enabled: ["kibana_server"]
@@ -48,21 +94,38 @@ opendistro_security:
     transport:
       enabled: true
       enforce_hostname_verification: false
+{{- if .FIPSMode}}
+      pemcert_filepath: /etc/elasticsearch/secret/elasticsearch.crt
+      pemkey_filepath: /etc/elasticsearch/secret/elasticsearch.key
+      pemtrustedcas_filepath: {{.TransportTrustedCAs}}
+      enabled_protocols: {{.FIPSProtocols}}
+      enabled_ciphers: {{.FIPSCiphers}}
+{{- else}}
       keystore_type: JKS
       keystore_filepath: /etc/elasticsearch/secret/searchguard.key
       keystore_password: kspass
       truststore_type: JKS
-      truststore_filepath: /etc/elasticsearch/secret/searchguard.truststore
+      truststore_filepath: {{.TransportTruststore}}
       truststore_password: tspass
+{{- end}}
     http:
-      enabled: true
+      enabled: {{.HTTPTLS}}
+{{- if .FIPSMode}}
+      pemcert_filepath: /etc/elasticsearch/secret/logging-es.crt
+      pemkey_filepath: /etc/elasticsearch/secret/logging-es.key
+      clientauth_mode: OPTIONAL
+      pemtrustedcas_filepath: /etc/elasticsearch/secret/admin-ca
+      enabled_protocols: {{.FIPSProtocols}}
+      enabled_ciphers: {{.FIPSCiphers}}
+{{- else}}
       keystore_type: JKS
       keystore_filepath: /etc/elasticsearch/secret/key
       keystore_password: kspass
       clientauth_mode: OPTIONAL
       truststore_type: JKS
       truststore_filepath: /etc/elasticsearch/secret/truststore
-      truststore_password: tspass`
+      truststore_password: tspass
+{{- end}}`
 
 const log4j2PropertiesTmpl = `
 status = error
@@ -73,7 +136,10 @@ logger.action.level = debug
 
 logger.security.name = com.amazon.opendistroforelasticsearch.security
 logger.security.level = {{.SecurityLogLevel}}  
-
+{{range .Loggers}}
+logger.{{.ID}}.name = {{.Name}}
+logger.{{.ID}}.level = {{.Level}}
+{{end}}
 appender.console.type = Console
 appender.console.name = console
 appender.console.layout.type = PatternLayout
@@ -90,9 +156,9 @@ appender.rolling.policies.time.type = TimeBasedTriggeringPolicy
 appender.rolling.policies.time.interval = 1
 appender.rolling.policies.time.modulate = true
 appender.rolling.policies.size.type=SizeBasedTriggeringPolicy
-appender.rolling.policies.size.size=100MB
+appender.rolling.policies.size.size={{.RollingMaxFileSize}}
 appender.rolling.strategy.type=DefaultRolloverStrategy
-appender.rolling.strategy.max=5
+appender.rolling.strategy.max={{.RollingMaxFiles}}
 
 rootLogger.level = {{.LogLevel}}
 rootLogger.appenderRef.{{.RootLogger}}.ref = {{.RootLogger}}
@@ -110,7 +176,7 @@ appender.deprecation_rolling.strategy.type = DefaultRolloverStrategy
 appender.deprecation_rolling.strategy.max = 4
 
 logger.deprecation.name = org.elasticsearch.deprecation
-logger.deprecation.level = warn
+logger.deprecation.level = {{.DeprecationLogLevel}}
 logger.deprecation.appenderRef.deprecation_rolling.ref = deprecation_rolling
 logger.deprecation.additivity = false
 
@@ -126,7 +192,7 @@ appender.index_search_slowlog_rolling.policies.time.interval = 1
 appender.index_search_slowlog_rolling.policies.time.modulate = true
 
 logger.index_search_slowlog_rolling.name = index.search.slowlog
-logger.index_search_slowlog_rolling.level = trace
+logger.index_search_slowlog_rolling.level = {{.SearchSlowlogLogLevel}}
 logger.index_search_slowlog_rolling.appenderRef.index_search_slowlog_rolling.ref = index_search_slowlog_rolling
 logger.index_search_slowlog_rolling.additivity = false
 
@@ -142,9 +208,30 @@ appender.index_indexing_slowlog_rolling.policies.time.interval = 1
 appender.index_indexing_slowlog_rolling.policies.time.modulate = true
 
 logger.index_indexing_slowlog.name = index.indexing.slowlog.index
-logger.index_indexing_slowlog.level = trace
+logger.index_indexing_slowlog.level = {{.IndexingSlowlogLogLevel}}
 logger.index_indexing_slowlog.appenderRef.index_indexing_slowlog_rolling.ref = index_indexing_slowlog_rolling
-logger.index_indexing_slowlog.additivity = false`
+logger.index_indexing_slowlog.additivity = false
+{{- if .AuditAppender}}
+
+logger.audit.name = {{.AuditLogger}}
+logger.audit.level = info
+logger.audit.appenderRef.{{.AuditAppender}}.ref = {{.AuditAppender}}
+logger.audit.additivity = false
+{{- end}}
+{{- if .AuditMaxFileSize}}
+
+appender.audit_rolling.type = RollingFile
+appender.audit_rolling.name = audit_rolling
+appender.audit_rolling.fileName = /elasticsearch/audit/audit.log
+appender.audit_rolling.layout.type = PatternLayout
+appender.audit_rolling.layout.pattern = [%d{ISO8601}] %m%n
+appender.audit_rolling.filePattern = /elasticsearch/audit/audit-%i.log.gz
+appender.audit_rolling.policies.type = Policies
+appender.audit_rolling.policies.size.type = SizeBasedTriggeringPolicy
+appender.audit_rolling.policies.size.size = {{.AuditMaxFileSize}}
+appender.audit_rolling.strategy.type = DefaultRolloverStrategy
+appender.audit_rolling.strategy.max = {{.AuditMaxFiles}}
+{{- end}}`
 
 const indexSettingsTmpl = `
 PRIMARY_SHARDS={{.PrimaryShards}}
@@ -0,0 +1,63 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// usesCoordinatingNodes returns true if the cluster has nodes which only route requests
+func usesCoordinatingNodes(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if isCoordinatingNode(node) {
+			return true
+		}
+	}
+	return false
+}
+
+func coordinatingServiceName(cluster *api.Elasticsearch) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, "coordinating")
+}
+
+// createOrUpdateCoordinatingService ensures the query traffic can be sent to the coordinating
+// nodes only and removes the service once the cluster has none
+func (er *ElasticsearchRequest) createOrUpdateCoordinatingService() error {
+	cluster := er.cluster
+	serviceName := coordinatingServiceName(cluster)
+
+	if !usesCoordinatingNodes(cluster) {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      serviceName,
+				Namespace: cluster.Namespace,
+			},
+		}
+		if err := er.client.Delete(context.TODO(), service); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete service",
+				"service_name", serviceName)
+		}
+		return nil
+	}
+
+	selector := selectorForES("es-node-client", cluster.Name)
+	selector["es-node-data"] = "false"
+	selector["es-node-master"] = "false"
+
+	return er.createOrUpdateService(
+		serviceName,
+		cluster.Namespace,
+		cluster.Name,
+		"restapi",
+		9200,
+		selector,
+		map[string]string{},
+		false,
+		map[string]string{},
+	)
+}
@@ -0,0 +1,248 @@
+package k8shandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	certificatesOrganizationalUnit = "OpenShift"
+	certificatesOrganization       = "Logging"
+)
+
+// ReconcileCustomCertificates validates the certificates of the cluster supplied by the user and
+// assembles the secret of the cluster from them. Invalid certificates are reported by the
+// InvalidCertificates condition and keep the nodes from being rolled out with them
+func (er *ElasticsearchRequest) ReconcileCustomCertificates() error {
+	return er.reconcileCustomCertificates(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileCustomCertificates(now time.Time) error {
+	cluster := er.cluster
+	spec := customCertificates(cluster)
+
+	if spec == nil {
+		return updateInvalidCertificatesCondition(cluster, v1.ConditionFalse, "", er.client)
+	}
+
+	if cluster.Spec.CertManager != nil {
+		return er.invalidCertificates("The certificates of the cluster cannot be both issued by cert-manager and supplied by security.certs")
+	}
+
+	secrets := map[string]*v1.Secret{}
+	for _, certificate := range clusterCertificates {
+		source := customCertificateSource(spec, certificate)
+		if source == nil {
+			continue
+		}
+
+		secret := &v1.Secret{}
+		name := source.SecretRef.Name
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				// the missing secret is reported by the Blocked condition
+				return nil
+			}
+			return kverrors.Wrap(err, "failed to get certificate secret",
+				"secret", name)
+		}
+
+		if violation := customCertificateViolation(cluster, certificate, secret, now); violation != "" {
+			return er.invalidCertificates(violation)
+		}
+		secrets[certificate.suffix] = secret
+	}
+
+	if err := updateInvalidCertificatesCondition(cluster, v1.ConditionFalse, "", er.client); err != nil {
+		return kverrors.Wrap(err, "failed to set certificates status")
+	}
+
+	data := map[string][]byte{}
+	cas := [][]byte{}
+	for _, certificate := range clusterCertificates {
+		secret, ok := secrets[certificate.suffix]
+		if !ok {
+			continue
+		}
+		data[certificate.certKey] = secret.Data[v1.TLSCertKey]
+		data[certificate.keyKey] = secret.Data[v1.TLSPrivateKeyKey]
+		cas = append(cas, secret.Data["ca.crt"])
+	}
+
+	// the certificates kept from the secret of the cluster must still be trusted
+	if len(secrets) < len(clusterCertificates) {
+		current := &v1.Secret{}
+		err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, current)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to get cluster secret")
+		}
+		cas = append([][]byte{current.Data["admin-ca"]}, cas...)
+	}
+	data["admin-ca"] = mergeCABundles(cas...)
+
+	return er.updateClusterSecret(data)
+}
+
+func (er *ElasticsearchRequest) invalidCertificates(violation string) error {
+	if err := updateInvalidCertificatesCondition(er.cluster, v1.ConditionTrue, violation, er.client); err != nil {
+		return kverrors.Wrap(err, "failed to set certificates status")
+	}
+	return kverrors.Wrap(ErrInvalidConfiguration, "invalid certificates of the cluster",
+		"reason", violation)
+}
+
+func customCertificates(cluster *api.Elasticsearch) *api.CertsSpec {
+	if cluster.Spec.Security == nil {
+		return nil
+	}
+	return cluster.Spec.Security.Certs
+}
+
+func customCertificateSource(spec *api.CertsSpec, certificate clusterCertificate) *api.CertSource {
+	switch certificate.suffix {
+	case "transport":
+		return spec.Transport
+	case "http":
+		return spec.HTTP
+	case "admin":
+		return spec.Admin
+	}
+	return nil
+}
+
+// customCertificateSecrets returns the names of the secrets of the certificates supplied by the user
+func customCertificateSecrets(cluster *api.Elasticsearch) []string {
+	spec := customCertificates(cluster)
+	if spec == nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, certificate := range clusterCertificates {
+		if source := customCertificateSource(spec, certificate); source != nil {
+			names = append(names, source.SecretRef.Name)
+		}
+	}
+	return names
+}
+
+// identifiesNodesByDN returns true if the transport certificates of the cluster do not carry the
+// node OID and the nodes identify each other by their subject instead
+func identifiesNodesByDN(cluster *api.Elasticsearch) bool {
+	if cluster.Spec.CertManager != nil {
+		return true
+	}
+	spec := customCertificates(cluster)
+	return spec != nil && spec.Transport != nil
+}
+
+// customCertificateServiceNames returns the names of the services of the cluster the server
+// certificate must be valid for
+func customCertificateServiceNames(cluster *api.Elasticsearch, certificate clusterCertificate) []string {
+	service := cluster.Name
+	if certificate.suffix == "transport" {
+		service = fmt.Sprintf("%s-cluster", cluster.Name)
+	}
+	return []string{service, fmt.Sprintf("%s.%s.svc", service, cluster.Namespace)}
+}
+
+// customCertificateViolation returns the reason the certificate supplied by the user in the secret
+// cannot be used by the cluster or an empty string
+func customCertificateViolation(cluster *api.Elasticsearch, certificate clusterCertificate, secret *v1.Secret, now time.Time) string {
+	for _, key := range []string{v1.TLSCertKey, v1.TLSPrivateKeyKey, "ca.crt"} {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Sprintf("The %s certificate secret %s is missing the key %s", certificate.suffix, secret.Name, key)
+		}
+	}
+
+	pair, err := tls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Sprintf("The %s certificate secret %s does not hold a valid certificate and key: %s", certificate.suffix, secret.Name, err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Sprintf("The %s certificate of secret %s cannot be parsed: %s", certificate.suffix, secret.Name, err)
+	}
+
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Sprintf("The %s certificate of secret %s is only valid from %s to %s", certificate.suffix, secret.Name,
+			leaf.NotBefore.UTC().Format(time.RFC3339), leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data["ca.crt"]) {
+		return fmt.Sprintf("The %s certificate secret %s does not hold a valid CA in ca.crt", certificate.suffix, secret.Name)
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range pair.Certificate[1:] {
+		if cert, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(cert)
+		}
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Sprintf("The %s certificate of secret %s is not signed by its CA: %s", certificate.suffix, secret.Name, err)
+	}
+
+	// the nodes and the operator are identified by the subject of their client certificates
+	if certificate.client {
+		subject := leaf.Subject
+		if subject.CommonName != certificate.commonName ||
+			len(subject.OrganizationalUnit) != 1 || subject.OrganizationalUnit[0] != certificatesOrganizationalUnit ||
+			len(subject.Organization) != 1 || subject.Organization[0] != certificatesOrganization {
+			return fmt.Sprintf("The subject of the %s certificate of secret %s must be CN=%s,OU=%s,O=%s", certificate.suffix, secret.Name,
+				certificate.commonName, certificatesOrganizationalUnit, certificatesOrganization)
+		}
+	}
+
+	if certificate.server {
+		missing := []string{}
+		for _, name := range customCertificateServiceNames(cluster, certificate) {
+			if err := leaf.VerifyHostname(name); err != nil {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("The %s certificate of secret %s is not valid for the services %s", certificate.suffix, secret.Name, strings.Join(missing, ", "))
+		}
+	}
+	return ""
+}
+
+// mergeCABundles returns the distinct certificates of the PEM bundles in their order
+func mergeCABundles(bundles ...[]byte) []byte {
+	merged := &bytes.Buffer{}
+	seen := map[string]bool{}
+	for _, bundle := range bundles {
+		for {
+			var block *pem.Block
+			block, bundle = pem.Decode(bundle)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" || seen[string(block.Bytes)] {
+				continue
+			}
+			seen[string(block.Bytes)] = true
+			_ = pem.Encode(merged, block)
+		}
+	}
+	return merged.Bytes()
+}
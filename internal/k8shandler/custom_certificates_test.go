@@ -0,0 +1,164 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("custom certificates", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     time.Time
+		ca      *x509.Certificate
+		caKey   *rsa.PrivateKey
+		caPEM   []byte
+	)
+
+	newKey := func() *rsa.PrivateKey {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	sign := func(template *x509.Certificate, key *rsa.PrivateKey) []byte {
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	createSecret := func(name, commonName string, dnsNames ...string) {
+		key := newKey()
+		cert := sign(&x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: commonName, OrganizationalUnit: []string{"OpenShift"}, Organization: []string{"Logging"}},
+			DNSNames:     dnsNames,
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(24 * time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}, key)
+
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"},
+			Type:       v1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": cert,
+				"tls.key": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+				"ca.crt":  caPEM,
+			},
+		}
+		Expect(request.client.Create(context.TODO(), secret)).To(Succeed())
+	}
+
+	getCluster := func() *api.Elasticsearch {
+		current := &api.Elasticsearch{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, current)).To(Succeed())
+		return current
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		caKey = newKey()
+		ca = &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "enterprise-ca"},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(48 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+		Expect(err).ToNot(HaveOccurred())
+		ca, err = x509.ParseCertificate(der)
+		Expect(err).ToNot(HaveOccurred())
+		caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Security: &api.SecuritySpec{
+					Certs: &api.CertsSpec{
+						Transport: &api.CertSource{SecretRef: api.CertSecretReference{Name: "es-transport"}},
+						HTTP:      &api.CertSource{SecretRef: api.CertSecretReference{Name: "es-http"}},
+					},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+	})
+
+	It("should assemble the secret of the cluster from valid certificates", func() {
+		createSecret("es-transport", "elasticsearch", "elasticsearch-cluster", "elasticsearch-cluster.openshift-logging.svc")
+		createSecret("es-http", "logging-es", "*.openshift-logging.svc", "elasticsearch")
+
+		Expect(request.reconcileCustomCertificates(now)).To(Succeed())
+
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKey("elasticsearch.crt"))
+		Expect(secret.Data).To(HaveKey("logging-es.key"))
+		Expect(secret.Data).ToNot(HaveKey("admin-cert"))
+		Expect(secret.Data["admin-ca"]).To(Equal(caPEM))
+		Expect(identifiesNodesByDN(cluster)).To(BeTrue())
+	})
+
+	It("should report a certificate missing the names of the services", func() {
+		createSecret("es-transport", "elasticsearch", "elasticsearch-cluster", "elasticsearch-cluster.openshift-logging.svc")
+		createSecret("es-http", "elasticsearch", "elasticsearch")
+
+		err := request.reconcileCustomCertificates(now)
+		Expect(IsUserError(err)).To(BeTrue())
+
+		_, condition := getESNodeCondition(getCluster().Status.Conditions, api.InvalidCertificates)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(Equal("The http certificate of secret es-http is not valid for the services elasticsearch.openshift-logging.svc"))
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, &v1.Secret{})).ToNot(Succeed())
+	})
+
+	It("should report a transport certificate with another subject", func() {
+		createSecret("es-transport", "node", "elasticsearch-cluster", "elasticsearch-cluster.openshift-logging.svc")
+
+		Expect(request.reconcileCustomCertificates(now)).ToNot(Succeed())
+
+		_, condition := getESNodeCondition(getCluster().Status.Conditions, api.InvalidCertificates)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(Equal("The subject of the transport certificate of secret es-transport must be CN=elasticsearch,OU=OpenShift,O=Logging"))
+	})
+
+	It("should report an expired certificate", func() {
+		createSecret("es-transport", "elasticsearch", "elasticsearch-cluster", "elasticsearch-cluster.openshift-logging.svc")
+
+		Expect(request.reconcileCustomCertificates(now.Add(25 * time.Hour))).ToNot(Succeed())
+
+		_, condition := getESNodeCondition(getCluster().Status.Conditions, api.InvalidCertificates)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(HavePrefix("The transport certificate of secret es-transport is only valid from"))
+	})
+})
@@ -0,0 +1,77 @@
+package k8shandler
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// ApplyDataNodeCount adjusts the node count of a data node for the data nodes of the cluster to add
+// up to the count set through the scale subresource
+func (er *ElasticsearchRequest) ApplyDataNodeCount() error {
+	cluster := er.cluster
+
+	if cluster.Spec.DataNodeCount == nil {
+		return nil
+	}
+	if index, _ := scaledDataNode(cluster.Spec.Nodes, *cluster.Spec.DataNodeCount); index == NotFoundIndex {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+		if cluster.Spec.DataNodeCount == nil {
+			return nil
+		}
+
+		index, count := scaledDataNode(cluster.Spec.Nodes, *cluster.Spec.DataNodeCount)
+		if index == NotFoundIndex {
+			return nil
+		}
+
+		er.L().Info("Scaling data nodes", "roles", cluster.Spec.Nodes[index].Roles, "from", cluster.Spec.Nodes[index].NodeCount, "to", count)
+		cluster.Spec.Nodes[index].NodeCount = count
+		return er.client.Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to apply data node count")
+}
+
+// scaledDataNode returns the index of the node to scale for the data nodes to add up to the total
+// and its new node count. The last node with the data role and without the master role is scaled to
+// keep the quorum of the masters, or else the last node with the data role. The node keeps one node
+// at least. The index is NotFoundIndex if no node needs to be scaled
+func scaledDataNode(nodes []api.ElasticsearchNode, total int32) (int, int32) {
+	index := NotFoundIndex
+	for i, node := range nodes {
+		if !isDataNode(node) {
+			continue
+		}
+		if index == NotFoundIndex || !isMasterNode(node) || isMasterNode(nodes[index]) {
+			index = i
+		}
+	}
+	if index == NotFoundIndex {
+		return NotFoundIndex, 0
+	}
+
+	others := int32(0)
+	for i, node := range nodes {
+		if i != index && isDataNode(node) {
+			others += node.NodeCount
+		}
+	}
+
+	count := total - others
+	if count < 1 {
+		count = 1
+	}
+	if count == nodes[index].NodeCount {
+		return NotFoundIndex, 0
+	}
+	return index, count
+}
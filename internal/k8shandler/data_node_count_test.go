@@ -0,0 +1,68 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Data node count", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 2},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient}, NodeCount: 1},
+				},
+			},
+		}
+	})
+
+	It("should scale the last data node without the master role", func() {
+		index, count := scaledDataNode(cluster.Spec.Nodes, 7)
+		Expect(index).To(Equal(1))
+		Expect(count).To(BeEquivalentTo(4))
+	})
+
+	It("should keep one node of the scaled node", func() {
+		index, count := scaledDataNode(cluster.Spec.Nodes, 2)
+		Expect(index).To(Equal(1))
+		Expect(count).To(BeEquivalentTo(1))
+	})
+
+	It("should scale the last data node if all have the master role", func() {
+		cluster.Spec.Nodes = cluster.Spec.Nodes[:1]
+		index, count := scaledDataNode(cluster.Spec.Nodes, 5)
+		Expect(index).To(Equal(0))
+		Expect(count).To(BeEquivalentTo(5))
+	})
+
+	It("should not scale when the data nodes add up to the count", func() {
+		index, _ := scaledDataNode(cluster.Spec.Nodes, 5)
+		Expect(index).To(Equal(NotFoundIndex))
+	})
+
+	It("should update the node count in the spec", func() {
+		total := int32(6)
+		cluster.Spec.DataNodeCount = &total
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		er := &ElasticsearchRequest{client: fake.NewFakeClientWithScheme(s, cluster), cluster: cluster}
+
+		Expect(er.ApplyDataNodeCount()).To(Succeed())
+		Expect(cluster.Spec.Nodes[1].NodeCount).To(BeEquivalentTo(3))
+	})
+})
@@ -0,0 +1,91 @@
+package k8shandler
+
+import (
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// dataTierAttribute is the node attribute holding the data tier of a node
+	dataTierAttribute = "data"
+	// dataTierTemplateName is the index template allocating new indices to the hot nodes. It is
+	// not prefixed with constants.OcpTemplatePrefix to keep its shards and replicas unmanaged
+	dataTierTemplateName = "ocp-data-tier"
+)
+
+// ReconcileDataTierAllocation allocates new indices to the hot nodes while the cluster has
+// tiered data nodes. Indices move to the other tiers through the allocate action of their lifecycle policy
+func (er *ElasticsearchRequest) ReconcileDataTierAllocation() error {
+	cluster := er.cluster
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	templates, err := er.esClient.ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	if !hasHotDataNodes(cluster) {
+		if templates.Has(dataTierTemplateName) {
+			return er.esClient.DeleteIndexTemplate(dataTierTemplateName)
+		}
+		return nil
+	}
+
+	if templates.Has(dataTierTemplateName) {
+		return nil
+	}
+
+	er.L().Info("Allocating new indices to the hot data tier")
+	return er.esClient.CreateIndexTemplate(dataTierTemplateName, newDataTierTemplate())
+}
+
+func newDataTierTemplate() *estypes.IndexTemplate {
+	return &estypes.IndexTemplate{
+		Template: "*",
+		Settings: estypes.IndexSettings{
+			Index: &estypes.IndexingSettings{
+				Routing: &estypes.IndexRoutingSettings{
+					Allocation: estypes.IndexAllocationSettings{
+						Require: map[string]string{
+							dataTierAttribute: string(api.ElasticsearchDataTierHot),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// usesDataTiers returns true if a node of the cluster has a data tier
+func usesDataTiers(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if node.Tier != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// dataTier returns the data tier of the node or an empty string if the cluster has no tiers
+func dataTier(cluster *api.Elasticsearch, node api.ElasticsearchNode) string {
+	if !usesDataTiers(cluster) {
+		return ""
+	}
+	if node.Tier == "" {
+		return string(api.ElasticsearchDataTierHot)
+	}
+	return string(node.Tier)
+}
+
+// hasHotDataNodes returns true if the cluster has tiers and new indices can be allocated to its hot nodes
+func hasHotDataNodes(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if node.NodeCount > 0 && isDataNode(node) && dataTier(cluster, node) == string(api.ElasticsearchDataTierHot) {
+			return true
+		}
+	}
+	return false
+}
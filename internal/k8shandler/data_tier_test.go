@@ -0,0 +1,110 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Data tiers", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 2, Tier: api.ElasticsearchDataTierWarm},
+				},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClient(pod),
+			cluster: cluster,
+		}
+	})
+
+	It("should default the nodes without a tier to hot once a node has a tier", func() {
+		Expect(dataTier(cluster, cluster.Spec.Nodes[0])).To(Equal("hot"))
+		Expect(dataTier(cluster, cluster.Spec.Nodes[1])).To(Equal("warm"))
+
+		cluster.Spec.Nodes[1].Tier = ""
+		Expect(dataTier(cluster, cluster.Spec.Nodes[0])).To(BeEmpty())
+	})
+
+	It("should set the tier of the node", func() {
+		envVars := newEnvVars("node", "", map[api.ElasticsearchNodeRole]bool{}, podTemplateOptions{
+			clusterName: "elasticsearch",
+			dataTier:    dataTier(cluster, cluster.Spec.Nodes[1]),
+		})
+		helpers.ExpectEnvVars(envVars).ToIncludeName("DATA_TIER").WithValue("warm")
+	})
+
+	It("should allocate new indices to the hot nodes", func() {
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_template": {
+					{StatusCode: http.StatusOK, Body: `{"ocp-gen-app": {}}`},
+				},
+				"_template/ocp-data-tier": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.ReconcileDataTierAllocation()).To(Succeed())
+		req, found := chatter.GetRequest("_template/ocp-data-tier")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"template": "*",
+			"settings": {"index": {"routing": {"allocation": {"require": {"data": "hot"}}}}}
+		}`)
+	})
+
+	It("should remove the allocation once no hot nodes are left", func() {
+		cluster.Spec.Nodes[0].Tier = api.ElasticsearchDataTierCold
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_template": {
+					{StatusCode: http.StatusOK, Body: `{"ocp-data-tier": {}}`},
+				},
+				"_template/ocp-data-tier": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.ReconcileDataTierAllocation()).To(Succeed())
+		req, found := chatter.GetRequest("_template/ocp-data-tier")
+		Expect(found).To(BeTrue())
+		Expect(req.Method).To(Equal(http.MethodDelete))
+	})
+})
@@ -36,8 +36,14 @@ type deploymentNode struct {
 	esClient elasticsearch.Client
 }
 
-func (node *deploymentNode) populateReference(nodeName string, n api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client) {
+func (node *deploymentNode) populateReference(nodeName string, n api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client) error {
+	options, err := newPodTemplateOptions(cluster, n, client)
+	if err != nil {
+		return err
+	}
+
 	labels := newLabels(cluster.Name, nodeName, roleMap)
+	workloadLabels, annotations := podTemplateMetadata(cluster.Spec.Spec, n, labels)
 
 	deployment := apps.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -45,16 +51,16 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 			APIVersion: apps.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      nodeName,
-			Namespace: cluster.Namespace,
-			Labels:    labels,
+			Name:        nodeName,
+			Namespace:   cluster.Namespace,
+			Labels:      workloadLabels,
+			Annotations: annotations,
 		},
 	}
 
 	node.replicas = replicas
 
 	progressDeadlineSeconds := int32(1800)
-	logConfig := getLogConfig(cluster.GetAnnotations())
 	deployment.Spec = apps.DeploymentSpec{
 		Replicas: &replicas,
 		Selector: &metav1.LabelSelector{
@@ -65,8 +71,14 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 		},
 		ProgressDeadlineSeconds: &progressDeadlineSeconds,
 		Paused:                  false,
-		Template:                newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, n, cluster.Spec.Spec, labels, roleMap, client, logConfig),
+		Template:                newPodTemplateSpec(nodeName, n, labels, roleMap, client, options),
 	}
+	applyHTTPTLS(cluster, &deployment.Spec.Template.Spec)
+	applyFIPSMode(cluster, &deployment.Spec.Template.Spec)
+	applyThreadPools(cluster, n, &deployment.Spec.Template.Spec)
+	applyAuditLogVolume(cluster, &deployment.Spec.Template.Spec)
+	applyLegacyClientProxies(cluster, &deployment.Spec.Template)
+	applyPodSecurity(cluster, &deployment.Spec.Template)
 
 	cluster.AddOwnerRefTo(&deployment)
 
@@ -75,6 +87,7 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 
 	node.client = client
 	node.esClient = esClient
+	return nil
 }
 
 func (node *deploymentNode) updateReference(n NodeTypeInterface) {
@@ -103,7 +116,8 @@ func (node *deploymentNode) state() api.ElasticsearchNodeStatus {
 	var rolloutForCertReload v1.ConditionStatus
 
 	// see if we need to update the deployment object
-	if node.isChanged() {
+	changes := node.pendingChanges()
+	if len(changes) > 0 {
 		rolloutForUpdate = v1.ConditionTrue
 	}
 
@@ -126,6 +140,7 @@ func (node *deploymentNode) state() api.ElasticsearchNodeStatus {
 		UpgradeStatus: api.ElasticsearchNodeUpgradeStatus{
 			ScheduledForUpgrade:      rolloutForUpdate,
 			ScheduledForCertRedeploy: rolloutForCertReload,
+			ScheduledChanges:         changes,
 		},
 	}
 }
@@ -358,6 +373,7 @@ func (node *deploymentNode) executeUpdate() error {
 
 		if ArePodTemplateSpecDifferent(currentDeployment.Spec.Template, node.self.Spec.Template) {
 			currentDeployment.Spec.Template = node.self.Spec.Template
+			mergeMetadata(&currentDeployment.ObjectMeta, node.self.ObjectMeta)
 			if err := node.client.Update(context.TODO(), &currentDeployment); err != nil {
 				log.Info("Failed to update node resource", "error", err)
 				return err
@@ -411,15 +427,20 @@ func (node *deploymentNode) refreshHashes() {
 }
 
 func (node *deploymentNode) isChanged() bool {
+	return len(node.pendingChanges()) > 0
+}
+
+// pendingChanges returns the fields of the pod template which differ from the deployment
+func (node *deploymentNode) pendingChanges() []string {
 	desiredTemplate := node.self.Spec.Template
 	currentDeployment := apps.Deployment{}
 
 	err := node.client.Get(context.TODO(), types.NamespacedName{Name: node.self.Name, Namespace: node.self.Namespace}, &currentDeployment)
 	// error check that it exists, etc
 	if err != nil {
-		// if it doesn't exist, return true
-		return false
+		// if it doesn't exist, there is nothing to change
+		return nil
 	}
 
-	return ArePodTemplateSpecDifferent(currentDeployment.Spec.Template, desiredTemplate)
+	return podTemplateSpecChanges(currentDeployment.Spec.Template, desiredTemplate)
 }
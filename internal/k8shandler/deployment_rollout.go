@@ -0,0 +1,157 @@
+package k8shandler
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// deploymentOrdinal returns the group and the ordinal of a deployment node. The deployments of a
+// node of the spec are named after the node with their ordinal appended. StatefulSet nodes have
+// no ordinal
+func deploymentOrdinal(node NodeTypeInterface) (string, int32, bool) {
+	if _, ok := node.(*deploymentNode); !ok {
+		return "", 0, false
+	}
+
+	name := node.name()
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return "", 0, false
+	}
+	ordinal, err := strconv.ParseInt(name[i+1:], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], int32(ordinal), true
+}
+
+// sequenceRollout orders the nodes to roll out so the deployments of a group follow each other
+// from the highest ordinal down, like the pods of a StatefulSet. Groups with an interrupted
+// rollout come first and resume below their partition. The groups otherwise keep their order
+func sequenceRollout(nodes []NodeTypeInterface, rollouts []api.DeploymentRolloutStatus) []NodeTypeInterface {
+	partitions := map[string]int32{}
+	for _, rollout := range rollouts {
+		partitions[rollout.Group] = rollout.Partition
+	}
+
+	type position struct {
+		resumed  bool
+		group    int
+		rolled   bool
+		ordinal  int32
+		original int
+	}
+
+	groups := map[string]int{}
+	positions := map[string]position{}
+	for i, node := range nodes {
+		group, ordinal, ok := deploymentOrdinal(node)
+		if !ok {
+			group = node.name()
+		}
+		if _, found := groups[group]; !found {
+			groups[group] = len(groups)
+		}
+
+		partition, resumed := partitions[group]
+		positions[node.name()] = position{
+			resumed:  resumed,
+			group:    groups[group],
+			rolled:   resumed && ordinal >= partition,
+			ordinal:  ordinal,
+			original: i,
+		}
+	}
+
+	ordered := append([]NodeTypeInterface{}, nodes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := positions[ordered[i].name()], positions[ordered[j].name()]
+		switch {
+		case a.resumed != b.resumed:
+			return a.resumed
+		case a.group != b.group:
+			return a.group < b.group
+		case a.rolled != b.rolled:
+			return !a.rolled
+		case a.ordinal != b.ordinal:
+			return a.ordinal > b.ordinal
+		}
+		return a.original < b.original
+	})
+	return ordered
+}
+
+// advanceDeploymentRollout moves the cursor of the group of the deployment node rolled out to its
+// ordinal, or removes it once none of the remaining nodes belongs to the group
+func (er *ElasticsearchRequest) advanceDeploymentRollout(node NodeTypeInterface, remaining []NodeTypeInterface) error {
+	group, ordinal, ok := deploymentOrdinal(node)
+	if !ok {
+		return nil
+	}
+
+	pending := false
+	for _, other := range remaining {
+		if otherGroup, _, ok := deploymentOrdinal(other); ok && otherGroup == group {
+			pending = true
+			break
+		}
+	}
+
+	rollouts := []api.DeploymentRolloutStatus{}
+	for _, rollout := range er.cluster.Status.DeploymentRollouts {
+		if rollout.Group != group && er.hasDeploymentGroup(rollout.Group) {
+			rollouts = append(rollouts, rollout)
+		}
+	}
+	if pending {
+		rollouts = append(rollouts, api.DeploymentRolloutStatus{Group: group, Partition: ordinal})
+	}
+	sort.Slice(rollouts, func(i, j int) bool {
+		return rollouts[i].Group < rollouts[j].Group
+	})
+
+	return er.updateDeploymentRolloutsStatus(rollouts)
+}
+
+// hasDeploymentGroup returns true if the cluster still has deployments of the group
+func (er *ElasticsearchRequest) hasDeploymentGroup(group string) bool {
+	for _, node := range nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)] {
+		if nodeGroup, _, ok := deploymentOrdinal(node); ok && nodeGroup == group {
+			return true
+		}
+	}
+	return false
+}
+
+func (er *ElasticsearchRequest) updateDeploymentRolloutsStatus(rollouts []api.DeploymentRolloutStatus) error {
+	cluster := er.cluster
+	if len(rollouts) == 0 {
+		rollouts = nil
+	}
+
+	if reflect.DeepEqual(cluster.Status.DeploymentRollouts, rollouts) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.DeploymentRollouts, rollouts) {
+			return nil
+		}
+
+		cluster.Status.DeploymentRollouts = rollouts
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update deployment rollouts status")
+}
@@ -0,0 +1,114 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Deployment rollout", func() {
+	defer GinkgoRecover()
+
+	const key = "elasticsearch-openshift-logging"
+
+	newNode := func(name string) NodeTypeInterface {
+		return &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+
+	names := func(ordered []NodeTypeInterface) []string {
+		result := []string{}
+		for _, node := range ordered {
+			result = append(result, node.name())
+		}
+		return result
+	}
+
+	It("should roll out the deployments of a group from the highest ordinal down", func() {
+		ordered := sequenceRollout([]NodeTypeInterface{
+			newNode("elasticsearch-cdm-abc-1"),
+			newNode("elasticsearch-cdm-abc-3"),
+			&statefulSetNode{self: apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-m-def"}}},
+			newNode("elasticsearch-cdm-abc-2"),
+		}, nil)
+
+		Expect(names(ordered)).To(Equal([]string{
+			"elasticsearch-cdm-abc-3",
+			"elasticsearch-cdm-abc-2",
+			"elasticsearch-cdm-abc-1",
+			"elasticsearch-m-def",
+		}))
+	})
+
+	It("should resume an interrupted rollout below its partition first", func() {
+		ordered := sequenceRollout([]NodeTypeInterface{
+			newNode("elasticsearch-cd-ghi-1"),
+			newNode("elasticsearch-cdm-abc-1"),
+			newNode("elasticsearch-cdm-abc-2"),
+			newNode("elasticsearch-cdm-abc-4"),
+		}, []api.DeploymentRolloutStatus{{Group: "elasticsearch-cdm-abc", Partition: 3}})
+
+		Expect(names(ordered)).To(Equal([]string{
+			"elasticsearch-cdm-abc-2",
+			"elasticsearch-cdm-abc-1",
+			"elasticsearch-cdm-abc-4",
+			"elasticsearch-cd-ghi-1",
+		}))
+	})
+
+	Describe("cursor", func() {
+		var request *ElasticsearchRequest
+
+		BeforeEach(func() {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+
+			cluster := &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+				Status: api.ElasticsearchStatus{
+					DeploymentRollouts: []api.DeploymentRolloutStatus{{Group: "elasticsearch-cdm-removed", Partition: 2}},
+				},
+			}
+			request = &ElasticsearchRequest{
+				client:  fake.NewFakeClientWithScheme(s, cluster),
+				cluster: cluster,
+			}
+
+			if nodes == nil {
+				nodes = map[string][]NodeTypeInterface{}
+			}
+			nodes[key] = []NodeTypeInterface{
+				newNode("elasticsearch-cdm-abc-1"),
+				newNode("elasticsearch-cdm-abc-2"),
+				newNode("elasticsearch-cdm-abc-3"),
+			}
+		})
+
+		AfterEach(func() {
+			delete(nodes, key)
+		})
+
+		It("should store the ordinal rolled out while the group has pending deployments", func() {
+			node := newNode("elasticsearch-cdm-abc-3")
+			remaining := []NodeTypeInterface{newNode("elasticsearch-cdm-abc-2"), newNode("elasticsearch-cdm-abc-1")}
+
+			Expect(request.advanceDeploymentRollout(node, remaining)).To(Succeed())
+			Expect(request.cluster.Status.DeploymentRollouts).To(Equal([]api.DeploymentRolloutStatus{
+				{Group: "elasticsearch-cdm-abc", Partition: 3},
+			}))
+		})
+
+		It("should remove the cursor once the group is rolled out", func() {
+			request.cluster.Status.DeploymentRollouts = []api.DeploymentRolloutStatus{{Group: "elasticsearch-cdm-abc", Partition: 2}}
+
+			Expect(request.advanceDeploymentRollout(newNode("elasticsearch-cdm-abc-1"), nil)).To(Succeed())
+			Expect(request.cluster.Status.DeploymentRollouts).To(BeEmpty())
+		})
+	})
+})
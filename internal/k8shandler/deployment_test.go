@@ -37,7 +37,7 @@ var _ = Describe("deployment", func() {
 		client = fake.NewFakeClient(&current.self)
 
 		elasticsearch = newElasticsearchContainer("someImage",
-			newEnvVars("mynodename", "clustername", "", map[loggingv1.ElasticsearchNodeRole]bool{}),
+			newEnvVars("mynodename", "", map[loggingv1.ElasticsearchNodeRole]bool{}, podTemplateOptions{clusterName: "clustername"}),
 			v1.ResourceRequirements{
 				Limits: v1.ResourceList{},
 			})
@@ -0,0 +1,172 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultDisasterRecoveryExportInterval = 24 * time.Hour
+	// disasterRecoveryExportAnnotation requests an immediate export whenever its value changes
+	disasterRecoveryExportAnnotation = "elasticsearch.openshift.io/export-disaster-recovery"
+	disasterRecoverySpecKey          = "elasticsearch.json"
+	disasterRecoveryMetadataKey      = "metadata.json"
+)
+
+// ExportDisasterRecoveryManifest writes the cluster spec and the metadata of the cluster to a secret
+// when the export is due or requested and records the result in the cluster status
+func (er *ElasticsearchRequest) ExportDisasterRecoveryManifest() error {
+	cluster := er.cluster
+	spec := cluster.Spec.DisasterRecoveryExport
+
+	if spec == nil {
+		return er.updateDisasterRecoveryExportStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	interval := defaultDisasterRecoveryExportInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+
+	secretName := disasterRecoverySecretName(cluster)
+	request := cluster.Annotations[disasterRecoveryExportAnnotation]
+	now := time.Now()
+
+	previous := cluster.Status.DisasterRecoveryExport
+	if previous != nil && previous.SecretName == secretName && previous.Request == request &&
+		now.Sub(previous.LastAttempt.Time) < interval {
+		return nil
+	}
+
+	status := &api.DisasterRecoveryExportStatus{
+		SecretName:  secretName,
+		LastAttempt: metav1.NewTime(now),
+		Request:     request,
+	}
+	if previous != nil && previous.SecretName == secretName {
+		status.LastExported = previous.LastExported
+	}
+
+	if err := er.writeDisasterRecoveryManifest(secretName); err != nil {
+		er.L().Error(err, "failed to export disaster recovery manifest", "secret", secretName)
+		status.Message = elasticsearchErrorReason(err)
+	} else {
+		exported := status.LastAttempt
+		status.LastExported = &exported
+	}
+
+	return er.updateDisasterRecoveryExportStatus(status)
+}
+
+func (er *ElasticsearchRequest) writeDisasterRecoveryManifest(secretName string) error {
+	cluster := er.cluster
+
+	spec, err := json.MarshalIndent(newDisasterRecoverySpec(cluster), "", "  ")
+	if err != nil {
+		return kverrors.Wrap(err, "failed to encode cluster spec")
+	}
+
+	metadata, err := er.esClient.GetClusterMetadata()
+	if err != nil {
+		return err
+	}
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return kverrors.Wrap(err, "failed to encode cluster metadata")
+	}
+
+	// the secret is deliberately not owned by the cluster to outlive it
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: cluster.Namespace,
+			Labels:    cluster.Labels,
+		},
+		Data: map[string][]byte{
+			disasterRecoverySpecKey:     spec,
+			disasterRecoveryMetadataKey: metadataJSON,
+		},
+	}
+
+	err = er.client.Create(context.TODO(), secret)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return kverrors.Wrap(err, "failed to create disaster recovery secret",
+			"secret", secret.Name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.Secret{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Data, secret.Data) {
+			return nil
+		}
+		current.Data = secret.Data
+		return er.client.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update disaster recovery secret",
+		"secret", secret.Name)
+}
+
+// newDisasterRecoverySpec returns the custom resource of the cluster without its status and
+// server populated metadata so it can be created in another Kubernetes cluster
+func newDisasterRecoverySpec(cluster *api.Elasticsearch) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": api.GroupVersion.String(),
+		"kind":       "Elasticsearch",
+		"metadata": map[string]interface{}{
+			"name":      cluster.Name,
+			"namespace": cluster.Namespace,
+			"labels":    cluster.Labels,
+		},
+		"spec": cluster.Spec,
+	}
+}
+
+func disasterRecoverySecretName(cluster *api.Elasticsearch) string {
+	if name := cluster.Spec.DisasterRecoveryExport.SecretName; name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s-disaster-recovery", cluster.Name)
+}
+
+func (er *ElasticsearchRequest) updateDisasterRecoveryExportStatus(status *api.DisasterRecoveryExportStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.DisasterRecoveryExport, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.DisasterRecoveryExport, status) {
+			return nil
+		}
+
+		cluster.Status.DisasterRecoveryExport = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update disaster recovery export status")
+}
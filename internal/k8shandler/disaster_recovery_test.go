@@ -0,0 +1,102 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Disaster recovery export", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "elasticsearch",
+				Namespace:       "openshift-logging",
+				ResourceVersion: "42",
+			},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy:       api.SingleRedundancy,
+				DisasterRecoveryExport: &api.DisasterRecoveryExportSpec{},
+			},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_template": {
+					{StatusCode: http.StatusOK, Body: `{"ocp-gen-app": {"index_patterns": ["app*"]}}`},
+				},
+				"_alias": {
+					{StatusCode: http.StatusOK, Body: `{"app-000001": {"aliases": {"app-write": {"is_write_index": true}}}}`},
+				},
+				"_cluster/settings?filter_path=persistent": {
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"enable": "all"}}}}}`},
+				},
+				"_ilm/status": {
+					{StatusCode: http.StatusBadRequest, Body: `{}`},
+				},
+			},
+		)
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClient(),
+			cluster: cluster,
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	})
+
+	It("should write the spec and metadata of the cluster to the secret", func() {
+		Expect(request.writeDisasterRecoveryManifest("elasticsearch-disaster-recovery")).To(Succeed())
+
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-disaster-recovery", Namespace: "openshift-logging"}, secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+
+		manifest := &api.Elasticsearch{}
+		Expect(json.Unmarshal(secret.Data[disasterRecoverySpecKey], manifest)).To(Succeed())
+		Expect(manifest.Kind).To(Equal("Elasticsearch"))
+		Expect(manifest.Name).To(Equal("elasticsearch"))
+		Expect(manifest.ResourceVersion).To(BeEmpty())
+		Expect(manifest.Spec.RedundancyPolicy).To(Equal(api.SingleRedundancy))
+
+		helpers.ExpectJSON(string(secret.Data[disasterRecoveryMetadataKey])).ToEqual(`{
+			"index_templates": {"ocp-gen-app": {"index_patterns": ["app*"]}},
+			"aliases": {"app-000001": {"aliases": {"app-write": {"is_write_index": true}}}},
+			"settings": {"persistent": {"cluster": {"routing": {"allocation": {"enable": "all"}}}}}
+		}`)
+	})
+
+	It("should update the secret of a previous export", func() {
+		Expect(request.client.Create(context.TODO(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-disaster-recovery", Namespace: "openshift-logging"},
+			Data:       map[string][]byte{disasterRecoverySpecKey: []byte("{}")},
+		})).To(Succeed())
+
+		Expect(request.writeDisasterRecoveryManifest("elasticsearch-disaster-recovery")).To(Succeed())
+
+		secret := &v1.Secret{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-disaster-recovery", Namespace: "openshift-logging"}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKey(disasterRecoveryMetadataKey))
+	})
+
+	It("should default the name of the secret", func() {
+		Expect(disasterRecoverySecretName(request.cluster)).To(Equal("elasticsearch-disaster-recovery"))
+
+		request.cluster.Spec.DisasterRecoveryExport.SecretName = "dr"
+		Expect(disasterRecoverySecretName(request.cluster)).To(Equal("dr"))
+	})
+})
@@ -0,0 +1,279 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultDiskPressureBlockThreshold   = 92
+	defaultDiskPressureReleaseThreshold = 85
+	writeBlockSetting                   = "index.blocks.write"
+)
+
+// ReconcileDiskPressure blocks the writes of one more write alias at every reconciliation while a
+// node uses more of its disk than the block threshold. Blocking the least critical aliases before
+// the flood-stage watermark keeps Elasticsearch from making every index on the node read-only
+func (er *ElasticsearchRequest) ReconcileDiskPressure() error {
+	return er.reconcileDiskPressure(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileDiskPressure(now time.Time) error {
+	cluster := er.cluster
+	spec := cluster.Spec.DiskPressure
+	current := cluster.Status.DiskPressure
+
+	if spec == nil && current == nil {
+		return nil
+	}
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	status := &api.DiskPressureStatus{}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+
+	if spec == nil {
+		if err := er.releaseWriteBlocks(status); err != nil {
+			return err
+		}
+		return er.updateDiskPressureStatus(nil)
+	}
+
+	usage, err := er.esClient.GetNodesDiskUsedPercent()
+	if err != nil {
+		return err
+	}
+	status.UsedPercent = highestDiskUsage(usage)
+
+	blockThreshold, releaseThreshold := diskPressureThresholds(spec)
+	if status.UsedPercent < releaseThreshold {
+		if err := er.releaseWriteBlocks(status); err != nil {
+			return err
+		}
+		status.Message = ""
+		return er.updateDiskPressureStatus(status)
+	}
+
+	writeAliases, err := er.esClient.ListWriteAliases()
+	if err != nil {
+		return err
+	}
+
+	// the blocked aliases may have been rolled over since
+	for i, blocked := range status.BlockedAliases {
+		index, found := writeAliases[blocked.Alias]
+		if !found || index == blocked.Index {
+			continue
+		}
+		if err := er.esClient.PutIndexSettings(index, map[string]string{writeBlockSetting: "true"}); err != nil {
+			return err
+		}
+		status.BlockedAliases[i].Index = index
+	}
+
+	if status.UsedPercent < blockThreshold {
+		return er.updateDiskPressureStatus(status)
+	}
+
+	candidates, err := er.writeBlockCandidates(spec, status, writeAliases)
+	if err != nil {
+		return err
+	}
+
+	failures := []string{}
+	for _, alias := range candidates {
+		index, err := er.blockWriteAlias(alias)
+		if err != nil {
+			er.L().Error(err, "Failed to block the writes of alias under disk pressure", "alias", alias)
+			failures = append(failures, alias)
+			continue
+		}
+
+		er.L().Info("Blocked the writes of alias under disk pressure", "alias", alias, "index", index, "used_percent", status.UsedPercent)
+		status.BlockedAliases = append(status.BlockedAliases, api.BlockedAliasStatus{
+			Alias:     alias,
+			Index:     index,
+			BlockedAt: metav1.NewTime(now),
+		})
+		break
+	}
+
+	switch {
+	case len(failures) > 0:
+		status.Message = fmt.Sprintf("Unable to block the writes of aliases %s", strings.Join(failures, ", "))
+	case len(candidates) == 0:
+		status.Message = "Every unprotected write alias is blocked"
+	default:
+		status.Message = ""
+	}
+	return er.updateDiskPressureStatus(status)
+}
+
+// diskPressureThresholds returns the block and release thresholds of the spec. The release
+// threshold is kept below the block threshold
+func diskPressureThresholds(spec *api.DiskPressureSpec) (int32, int32) {
+	block := int32(defaultDiskPressureBlockThreshold)
+	if spec.BlockThreshold != nil {
+		block = *spec.BlockThreshold
+	}
+	release := int32(defaultDiskPressureReleaseThreshold)
+	if spec.ReleaseThreshold != nil {
+		release = *spec.ReleaseThreshold
+	}
+	if release >= block {
+		release = block - 1
+	}
+	return block, release
+}
+
+func highestDiskUsage(usage map[string]float64) int32 {
+	highest := float64(0)
+	for _, percent := range usage {
+		highest = math.Max(highest, percent)
+	}
+	return int32(highest)
+}
+
+// writeBlockCandidates returns the write aliases to block in the order of the spec. System aliases,
+// protected aliases and the blocked ones are skipped
+func (er *ElasticsearchRequest) writeBlockCandidates(spec *api.DiskPressureSpec, status *api.DiskPressureStatus, writeAliases map[string]string) ([]string, error) {
+	blocked := map[string]bool{}
+	for _, alias := range status.BlockedAliases {
+		blocked[alias.Alias] = true
+	}
+
+	candidates := []string{}
+	for alias := range writeAliases {
+		if strings.HasPrefix(alias, ".") || blocked[alias] || isProtectedAlias(spec, alias) {
+			continue
+		}
+		candidates = append(candidates, alias)
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	var rank map[string]int64
+	if spec.Order == api.DiskPressureLargestFirst {
+		sizes, err := er.esClient.GetIndicesStoreSize("*")
+		if err != nil {
+			return nil, err
+		}
+		rank = map[string]int64{}
+		for alias, index := range writeAliases {
+			rank[alias] = -sizes[index]
+		}
+	} else {
+		indices, err := er.esClient.ListIndicesCreationDate("*")
+		if err != nil {
+			return nil, err
+		}
+		rank = writeIndexCreationDates(indices, writeAliases)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if rank[candidates[i]] != rank[candidates[j]] {
+			return rank[candidates[i]] < rank[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates, nil
+}
+
+// writeIndexCreationDates returns the creation date in milliseconds of the write index of every alias
+func writeIndexCreationDates(indices estypes.CatIndicesResponses, writeAliases map[string]string) map[string]int64 {
+	created := map[string]int64{}
+	for _, index := range indices {
+		millis, err := strconv.ParseInt(index.CreationDate, 10, 64)
+		if err != nil {
+			continue
+		}
+		created[index.Index] = millis
+	}
+
+	dates := map[string]int64{}
+	for alias, index := range writeAliases {
+		dates[alias] = created[index]
+	}
+	return dates
+}
+
+func isProtectedAlias(spec *api.DiskPressureSpec, alias string) bool {
+	for _, pattern := range spec.ProtectedAliases {
+		if matched, _ := path.Match(pattern, alias); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// blockWriteAlias rolls over the alias and blocks the writes of the new write index. The rolled
+// over index can be deleted by the retention rules while the new one stays empty
+func (er *ElasticsearchRequest) blockWriteAlias(alias string) (string, error) {
+	response, err := er.esClient.RolloverAlias(alias, estypes.RolloverConditions{})
+	if err != nil {
+		return "", err
+	}
+	if err := er.esClient.PutIndexSettings(response.NewIndex, map[string]string{writeBlockSetting: "true"}); err != nil {
+		return "", err
+	}
+	return response.NewIndex, nil
+}
+
+// releaseWriteBlocks removes the write blocks of the blocked aliases. Indices deleted since are
+// released as well
+func (er *ElasticsearchRequest) releaseWriteBlocks(status *api.DiskPressureStatus) error {
+	for len(status.BlockedAliases) > 0 {
+		blocked := status.BlockedAliases[0]
+		if err := er.esClient.PutIndexSettings(blocked.Index, map[string]string{writeBlockSetting: "false"}); err != nil {
+			indices, listErr := er.esClient.ListIndicesCreationDate(blocked.Index)
+			if listErr != nil || len(indices) > 0 {
+				return err
+			}
+		}
+
+		er.L().Info("Released the writes of alias blocked under disk pressure", "alias", blocked.Alias, "index", blocked.Index)
+		status.BlockedAliases = status.BlockedAliases[1:]
+	}
+	status.BlockedAliases = nil
+	return nil
+}
+
+func (er *ElasticsearchRequest) updateDiskPressureStatus(status *api.DiskPressureStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.DiskPressure, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.DiskPressure, status) {
+			return nil
+		}
+
+		cluster.Status.DiskPressure = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update disk pressure status")
+}
@@ -0,0 +1,126 @@
+package k8shandler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Disk pressure", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     = time.Now()
+	)
+
+	nodesStats := func(percent int) helpers.FakeElasticsearchResponse {
+		return helpers.FakeElasticsearchResponse{StatusCode: http.StatusOK, Body: fmt.Sprintf(`{"nodes": {
+			"abc": {"name": "elasticsearch-cdm-abc-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": %d}}},
+			"def": {"name": "elasticsearch-cdm-abc-2", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 50}}}
+		}}`, 100-percent)}
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				DiskPressure: &api.DiskPressureSpec{
+					ProtectedAliases: []string{"audit*"},
+				},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {nodesStats(94), nodesStats(90), nodesStats(80)},
+			"_alias": {
+				{StatusCode: http.StatusOK, Body: `{
+					"audit-000001": {"aliases": {"audit-write": {"is_write_index": true}}},
+					"app-000001": {"aliases": {"app-write": {"is_write_index": false}}},
+					"app-000002": {"aliases": {"app-write": {"is_write_index": true}}},
+					"infra-000001": {"aliases": {"infra-write": {}}},
+					".kibana_1": {"aliases": {".kibana": {}}}
+				}`},
+				{StatusCode: http.StatusOK, Body: `{
+					"audit-000001": {"aliases": {"audit-write": {"is_write_index": true}}},
+					"app-000002": {"aliases": {"app-write": {"is_write_index": false}}},
+					"app-000003": {"aliases": {"app-write": {"is_write_index": true}}},
+					"infra-000001": {"aliases": {"infra-write": {}}},
+					".kibana_1": {"aliases": {".kibana": {}}}
+				}`},
+			},
+			"_cat/indices/*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[
+					{"index": "audit-000001", "status": "open", "creation.date": "1000"},
+					{"index": "app-000002", "status": "open", "creation.date": "2000"},
+					{"index": "infra-000001", "status": "open", "creation.date": "3000"},
+					{"index": ".kibana_1", "status": "open", "creation.date": "500"}
+				]`},
+			},
+			"app-write/_rollover": {
+				{StatusCode: http.StatusOK, Body: `{"old_index": "app-000002", "new_index": "app-000003", "rolled_over": true}`},
+			},
+			"app-000003/_settings": {
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	})
+
+	It("should block the oldest unprotected write alias until the disk usage falls below the release threshold", func() {
+		Expect(request.reconcileDiskPressure(now)).To(Succeed())
+		Expect(cluster.Status.DiskPressure.UsedPercent).To(Equal(int32(94)))
+		Expect(cluster.Status.DiskPressure.BlockedAliases).To(Equal([]api.BlockedAliasStatus{
+			{Alias: "app-write", Index: "app-000003", BlockedAt: metav1.NewTime(now)},
+		}))
+		block, found := chatter.GetRequest("app-000003/_settings")
+		Expect(found).To(BeTrue())
+		Expect(block.Body).To(MatchJSON(`{"index.blocks.write": "true"}`))
+
+		Expect(request.reconcileDiskPressure(now)).To(Succeed())
+		Expect(cluster.Status.DiskPressure.UsedPercent).To(Equal(int32(90)))
+		Expect(cluster.Status.DiskPressure.BlockedAliases).To(HaveLen(1))
+		Expect(chatter.Requests["app-000003/_settings"]).To(BeEmpty())
+
+		Expect(request.reconcileDiskPressure(now)).To(Succeed())
+		Expect(cluster.Status.DiskPressure.UsedPercent).To(Equal(int32(80)))
+		Expect(cluster.Status.DiskPressure.BlockedAliases).To(BeEmpty())
+		release, found := chatter.GetRequest("app-000003/_settings")
+		Expect(found).To(BeTrue())
+		Expect(release.Body).To(MatchJSON(`{"index.blocks.write": "false"}`))
+	})
+})
@@ -0,0 +1,138 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// The operators of the logging stack take turns on their disruptive operations through a lock held
+// in the annotations of the Elasticsearch resource. The holder renews the lock during its operation
+// and releases it once done. A lock not renewed for the lease duration is taken over
+const (
+	disruptionHolderAnnotation  = "logging.openshift.io/disruption-holder"
+	disruptionRenewedAnnotation = "logging.openshift.io/disruption-renewed"
+	disruptionHolder            = "elasticsearch-operator"
+	disruptionLeaseDuration     = 30 * time.Minute
+	disruptionRenewInterval     = 5 * time.Minute
+)
+
+// coordinateDisruption returns true if the nodes may be restarted or upgraded. The lock is acquired
+// or renewed for the pending operations and released once none is left
+func (er *ElasticsearchRequest) coordinateDisruption(pending bool, now time.Time) (bool, error) {
+	cluster := er.cluster
+
+	if !pending {
+		if holder, _ := disruptionLock(cluster); holder == disruptionHolder {
+			er.L().Info("Releasing the disruption lock of the logging stack")
+			if err := er.updateDisruptionLock(now, false); err != nil {
+				return false, err
+			}
+		}
+		return true, er.updateDisruptionDeferredCondition(v1.ConditionFalse, "")
+	}
+
+	holder, renewed := disruptionLock(cluster)
+	if holder != "" && holder != disruptionHolder && now.Sub(renewed) < disruptionLeaseDuration {
+		return false, er.deferDisruption(holder)
+	}
+
+	if holder != disruptionHolder || now.Sub(renewed) >= disruptionRenewInterval {
+		if holder != disruptionHolder {
+			er.L().Info("Acquiring the disruption lock of the logging stack", "previous_holder", holder)
+		}
+		if err := er.updateDisruptionLock(now, true); err != nil {
+			return false, err
+		}
+
+		// another operator may have acquired the lock in the meantime
+		if holder, _ := disruptionLock(cluster); holder != disruptionHolder {
+			return false, er.deferDisruption(holder)
+		}
+	}
+	return true, er.updateDisruptionDeferredCondition(v1.ConditionFalse, "")
+}
+
+func (er *ElasticsearchRequest) deferDisruption(holder string) error {
+	message := fmt.Sprintf("Waiting for %s to complete its disruptive operation before restarting the nodes", holder)
+	er.L().Info(message)
+	return er.updateDisruptionDeferredCondition(v1.ConditionTrue, message)
+}
+
+// disruptionLock returns the holder of the lock and the time it was last renewed
+func disruptionLock(cluster *api.Elasticsearch) (string, time.Time) {
+	annotations := cluster.GetAnnotations()
+	holder := annotations[disruptionHolderAnnotation]
+	if holder == "" {
+		return "", time.Time{}
+	}
+
+	// a lock without a valid renewal time is stale
+	renewed, err := time.Parse(time.RFC3339, annotations[disruptionRenewedAnnotation])
+	if err != nil {
+		return holder, time.Time{}
+	}
+	return holder, renewed
+}
+
+// updateDisruptionLock acquires or renews the lock for the operator or releases it. The lock is
+// left to another operator which acquired it in the meantime
+func (er *ElasticsearchRequest) updateDisruptionLock(now time.Time, hold bool) error {
+	cluster := er.cluster
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		holder, renewed := disruptionLock(cluster)
+		if holder != "" && holder != disruptionHolder && now.Sub(renewed) < disruptionLeaseDuration {
+			return nil
+		}
+
+		annotations := cluster.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if hold {
+			annotations[disruptionHolderAnnotation] = disruptionHolder
+			annotations[disruptionRenewedAnnotation] = now.UTC().Format(time.RFC3339)
+		} else {
+			if holder != disruptionHolder {
+				return nil
+			}
+			delete(annotations, disruptionHolderAnnotation)
+			delete(annotations, disruptionRenewedAnnotation)
+		}
+		cluster.SetAnnotations(annotations)
+		return er.client.Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update disruption lock")
+}
+
+func (er *ElasticsearchRequest) updateDisruptionDeferredCondition(value v1.ConditionStatus, message string) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Waiting For Lock"
+	}
+
+	return updateConditionWithRetry(
+		er.cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.DisruptionDeferred,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		er.client,
+	)
+}
@@ -0,0 +1,84 @@
+package k8shandler
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("disruption coordination", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     = time.Now().Truncate(time.Second)
+	)
+
+	newRequest := func(annotations map[string]string) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging", Annotations: annotations},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+	}
+
+	It("should acquire, renew and release the lock for the disruptive operations", func() {
+		newRequest(nil)
+
+		Expect(request.coordinateDisruption(true, now)).To(BeTrue())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionHolderAnnotation, "elasticsearch-operator"))
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionRenewedAnnotation, now.UTC().Format(time.RFC3339)))
+
+		Expect(request.coordinateDisruption(true, now.Add(time.Minute))).To(BeTrue())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionRenewedAnnotation, now.UTC().Format(time.RFC3339)))
+
+		renewal := now.Add(6 * time.Minute)
+		Expect(request.coordinateDisruption(true, renewal)).To(BeTrue())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionRenewedAnnotation, renewal.UTC().Format(time.RFC3339)))
+
+		Expect(request.coordinateDisruption(false, renewal)).To(BeTrue())
+		Expect(cluster.Annotations).ToNot(HaveKey(disruptionHolderAnnotation))
+		Expect(cluster.Annotations).ToNot(HaveKey(disruptionRenewedAnnotation))
+	})
+
+	It("should defer the disruptive operations while another operator holds the lock", func() {
+		newRequest(map[string]string{
+			disruptionHolderAnnotation:  "cluster-logging-operator",
+			disruptionRenewedAnnotation: now.Add(-10 * time.Minute).UTC().Format(time.RFC3339),
+		})
+
+		Expect(request.coordinateDisruption(true, now)).To(BeFalse())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionHolderAnnotation, "cluster-logging-operator"))
+		Expect(containsClusterCondition(api.DisruptionDeferred, v1.ConditionTrue, &cluster.Status)).To(BeTrue())
+
+		// the lock of the other operator is left alone without pending operations
+		Expect(request.coordinateDisruption(false, now)).To(BeTrue())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionHolderAnnotation, "cluster-logging-operator"))
+		Expect(containsClusterCondition(api.DisruptionDeferred, v1.ConditionTrue, &cluster.Status)).To(BeFalse())
+	})
+
+	It("should take over a stale lock", func() {
+		newRequest(map[string]string{
+			disruptionHolderAnnotation:  "cluster-logging-operator",
+			disruptionRenewedAnnotation: now.Add(-31 * time.Minute).UTC().Format(time.RFC3339),
+		})
+
+		Expect(request.coordinateDisruption(true, now)).To(BeTrue())
+		Expect(cluster.Annotations).To(HaveKeyWithValue(disruptionHolderAnnotation, "elasticsearch-operator"))
+	})
+})
@@ -0,0 +1,52 @@
+package k8shandler
+
+import (
+	"fmt"
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// newEffectiveConfig returns the summary of the configuration rendered for the cluster. The
+// watermarks are the ones last read from the cluster
+func newEffectiveConfig(cluster *api.Elasticsearch, version string) *api.EffectiveConfig {
+	config := &api.EffectiveConfig{
+		Version:           version,
+		RedundancyPolicy:  cluster.Spec.RedundancyPolicy,
+		IndexReplicas:     int32(calculateReplicaCount(cluster)),
+		DiskWatermarkLow:  formatWatermark(DiskWatermarkLowPct, DiskWatermarkLowAbs),
+		DiskWatermarkHigh: formatWatermark(DiskWatermarkHighPct, DiskWatermarkHighAbs),
+	}
+
+	for i, node := range cluster.Spec.Nodes {
+		resources := newESResourceRequirements(node.Resources, cluster.Spec.Spec.Resources)
+
+		nodeConfig := api.EffectiveNodeConfig{
+			Roles:     node.Roles,
+			NodeCount: node.NodeCount + backlogExtraNodes(cluster, i),
+			// the image sizes the heap from INSTANCE_RAM in mebibytes
+			Heap:             fmt.Sprintf("%dMi", nodeHeapSize(node, resources)/mebibyte),
+			Storage:          "ephemeral",
+			StorageClassName: stringValue(node.Storage.StorageClassName),
+		}
+		if limit := resources.Limits.Memory(); !limit.IsZero() {
+			nodeConfig.MemoryLimit = limit.String()
+		}
+		if node.Storage.Size != nil {
+			nodeConfig.Storage = node.Storage.Size.String()
+		}
+		config.Nodes = append(config.Nodes, nodeConfig)
+	}
+	return config
+}
+
+func formatWatermark(percent *float64, free *resource.Quantity) string {
+	switch {
+	case percent != nil:
+		return strconv.FormatFloat(*percent, 'f', -1, 64) + "%"
+	case free != nil:
+		return free.String()
+	}
+	return ""
+}
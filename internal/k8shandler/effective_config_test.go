@@ -0,0 +1,83 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Effective config", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		size    = resource.MustParse("200Gi")
+		class   = "gp2"
+	)
+
+	BeforeEach(func() {
+		low, high := float64(85), float64(90)
+		DiskWatermarkLowPct, DiskWatermarkHighPct = &low, &high
+		DiskWatermarkLowAbs, DiskWatermarkHighAbs = nil, nil
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy: api.SingleRedundancy,
+				Spec: api.ElasticsearchNodeSpec{
+					Resources: v1.ResourceRequirements{
+						Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("16Gi")},
+						Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("16Gi")},
+					},
+				},
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster},
+						NodeCount: 3,
+					},
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+						NodeCount: 2,
+						Storage:   api.ElasticsearchStorageSpec{Size: &size, StorageClassName: &class},
+						JVM:       &api.ElasticsearchJVMSpec{Heap: resource.NewQuantity(6*1024*1024*1024, resource.BinarySI)},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		DiskWatermarkLowPct, DiskWatermarkHighPct = nil, nil
+	})
+
+	It("should summarize the rendered configuration of the cluster and its nodes", func() {
+		Expect(newEffectiveConfig(cluster, "6.8.1")).To(Equal(&api.EffectiveConfig{
+			Version:           "6.8.1",
+			RedundancyPolicy:  api.SingleRedundancy,
+			IndexReplicas:     1,
+			DiskWatermarkLow:  "85%",
+			DiskWatermarkHigh: "90%",
+			Nodes: []api.EffectiveNodeConfig{
+				{
+					Roles:       []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster},
+					NodeCount:   3,
+					Heap:        "8192Mi",
+					MemoryLimit: "16Gi",
+					Storage:     "ephemeral",
+				},
+				{
+					Roles:            []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+					NodeCount:        2,
+					Heap:             "6144Mi",
+					MemoryLimit:      "16Gi",
+					Storage:          "200Gi",
+					StorageClassName: "gp2",
+				},
+			},
+		}))
+	})
+})
@@ -84,6 +84,10 @@ func (er *ElasticsearchRequest) tryEnsureNoTransitiveShardAllocations() {
 }
 
 func (er *ElasticsearchRequest) updateReplicas() {
+	// the replicas are restored once the unavailable zone is back
+	if hasRaisedReplicas(er.cluster) {
+		return
+	}
 	if er.ClusterReady() {
 		replicaCount := int32(calculateReplicaCount(er.cluster))
 		if err := er.esClient.UpdateReplicaCount(replicaCount); err != nil {
@@ -0,0 +1,339 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	indexSettingNumberOfShards   = "index.number_of_shards"
+	indexSettingNumberOfReplicas = "index.number_of_replicas"
+
+	indexReasonApplied                 = "Applied"
+	indexReasonClusterNotFound         = "ClusterNotFound"
+	indexReasonClusterUnavailable      = "ClusterUnavailable"
+	indexReasonInvalidMappings         = "InvalidMappings"
+	indexReasonRejected                = "RejectedByElasticsearch"
+	indexReasonImmutableChangeRejected = "ImmutableChangeRejected"
+)
+
+// ReconcileElasticsearchIndex creates the declared index in the referenced Elasticsearch cluster
+// or applies the allowed changes to an existing one. The index is retained when the resource is deleted
+func ReconcileElasticsearchIndex(index *api.ElasticsearchIndex, requestClient client.Client) error {
+	er, err := newElasticsearchRequestFor(index.Spec.ElasticsearchName, index.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	conditions := index.Status.Conditions.DeepCopy()
+
+	if er == nil {
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonClusterNotFound, "Elasticsearch cluster not found")
+		return updateElasticsearchIndexStatus(index, conditions, requestClient)
+	}
+
+	if !er.AnyNodeReady() {
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonClusterUnavailable, "Waiting for an Elasticsearch node to be ready")
+		return updateElasticsearchIndexStatus(index, conditions, requestClient)
+	}
+
+	mappings, err := getIndexMappings(index)
+	if err != nil {
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonInvalidMappings, kverrors.Root(err).Error())
+		return updateElasticsearchIndexStatus(index, conditions, requestClient)
+	}
+
+	rejected, err := er.createOrUpdateIndex(index, mappings)
+
+	if len(rejected) > 0 {
+		conditions = setResourceCondition(conditions, api.ImmutableChangeRejected, corev1.ConditionTrue, indexReasonImmutableChangeRejected, strings.Join(rejected, "; "))
+	} else {
+		conditions = setResourceCondition(conditions, api.ImmutableChangeRejected, corev1.ConditionFalse, "", "")
+	}
+
+	switch {
+	case err != nil:
+		er.L().Error(err, "failed to apply index", "index", index.GetIndexName())
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonRejected, elasticsearchErrorReason(err))
+	case len(rejected) > 0:
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonImmutableChangeRejected, "The index does not match the spec")
+	default:
+		conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionTrue, indexReasonApplied, "")
+	}
+
+	return updateElasticsearchIndexStatus(index, conditions, requestClient)
+}
+
+// createOrUpdateIndex creates the index when missing, otherwise updates its dynamic settings, adds
+// new mapping fields and missing aliases and removes the aliases dropped from the spec. Changes
+// Elasticsearch can not apply to an existing index are returned as rejected. The applied aliases
+// are recorded in the status of the index
+func (er *ElasticsearchRequest) createOrUpdateIndex(index *api.ElasticsearchIndex, mappings map[string]interface{}) ([]string, error) {
+	name := index.GetIndexName()
+
+	current, err := er.esClient.GetIndexDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == nil {
+		settings := map[string]interface{}{
+			indexSettingNumberOfShards:   strconv.Itoa(calculatePrimaryCount(er.cluster)),
+			indexSettingNumberOfReplicas: strconv.Itoa(calculateReplicaCount(er.cluster)),
+		}
+		for key, value := range getIndexSettings(index) {
+			settings[key] = value
+		}
+
+		aliases := map[string]estypes.IndexAlias{}
+		for _, alias := range index.Spec.Aliases {
+			aliases[alias.Name] = estypes.IndexAlias{IsWriteIndex: alias.IsWriteIndex}
+		}
+
+		definition := &estypes.IndexDefinition{
+			Settings: settings,
+			Mappings: mappings,
+			Aliases:  aliases,
+		}
+		if err := er.esClient.CreateIndexDefinition(name, definition); err != nil {
+			return nil, err
+		}
+		index.Status.Aliases = indexAliasNames(index.Spec.Aliases)
+		return nil, nil
+	}
+
+	var rejected []string
+
+	settings := getIndexSettings(index)
+	if shards, ok := settings[indexSettingNumberOfShards]; ok {
+		if currentShards := fmt.Sprint(current.Settings[indexSettingNumberOfShards]); currentShards != shards {
+			rejected = append(rejected, fmt.Sprintf("number of shards can not be changed from %s to %s", currentShards, shards))
+		}
+		delete(settings, indexSettingNumberOfShards)
+	}
+
+	changed := map[string]string{}
+	for key, value := range settings {
+		if fmt.Sprint(current.Settings[key]) != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) > 0 {
+		if err := er.esClient.PutIndexSettings(name, changed); err != nil {
+			return rejected, err
+		}
+	}
+
+	conflicts := findMappingConflicts(current.Mappings, mappings, "")
+	rejected = append(rejected, conflicts...)
+	if len(conflicts) == 0 && hasNewMappingFields(current.Mappings, mappings) {
+		if err := er.esClient.PutIndexMappings(name, mappings); err != nil {
+			return rejected, err
+		}
+	}
+
+	if actions := changedAliasActions(name, current.Aliases, index.Spec.Aliases, index.Status.Aliases); len(actions.Actions) > 0 {
+		if err := er.esClient.UpdateAlias(actions); err != nil {
+			return rejected, err
+		}
+	}
+	index.Status.Aliases = indexAliasNames(index.Spec.Aliases)
+
+	return rejected, nil
+}
+
+// changedAliasActions returns the actions adding the aliases of the spec which are missing on the
+// index or whose write index flag differs, and removing the applied aliases no longer in the spec.
+// An unset flag of the spec accepts any live flag
+func changedAliasActions(name string, current map[string]estypes.IndexAlias, aliases []api.ElasticsearchIndexAliasSpec, applied []string) estypes.AliasActions {
+	actions := estypes.AliasActions{}
+
+	declared := sets.NewString(indexAliasNames(aliases)...)
+	for _, alias := range applied {
+		if _, ok := current[alias]; !ok || declared.Has(alias) {
+			continue
+		}
+		actions.Actions = append(actions.Actions, estypes.AliasAction{
+			Remove: &estypes.AliasRef{
+				Index: name,
+				Alias: alias,
+			},
+		})
+	}
+
+	for _, alias := range aliases {
+		if currentAlias, ok := current[alias.Name]; ok {
+			if alias.IsWriteIndex == nil {
+				continue
+			}
+			if currentAlias.IsWriteIndex != nil && *currentAlias.IsWriteIndex == *alias.IsWriteIndex {
+				continue
+			}
+		}
+		actions.Actions = append(actions.Actions, estypes.AliasAction{
+			Add: &estypes.AddAliasAction{
+				Index:        name,
+				Alias:        alias.Name,
+				IsWriteIndex: alias.IsWriteIndex,
+			},
+		})
+	}
+	return actions
+}
+
+// indexAliasNames returns the sorted names of the aliases or nil if there are none
+func indexAliasNames(aliases []api.ElasticsearchIndexAliasSpec) []string {
+	if len(aliases) == 0 {
+		return nil
+	}
+	names := sets.NewString()
+	for _, alias := range aliases {
+		names.Insert(alias.Name)
+	}
+	return names.List()
+}
+
+// getIndexSettings returns the explicitly declared settings of the index in their flat form
+func getIndexSettings(index *api.ElasticsearchIndex) map[string]string {
+	settings := map[string]string{}
+	for key, value := range index.Spec.Settings {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		settings[key] = value
+	}
+	if index.Spec.NumberOfShards > 0 {
+		settings[indexSettingNumberOfShards] = strconv.Itoa(int(index.Spec.NumberOfShards))
+	}
+	if index.Spec.NumberOfReplicas != nil {
+		settings[indexSettingNumberOfReplicas] = strconv.Itoa(int(*index.Spec.NumberOfReplicas))
+	}
+	return settings
+}
+
+func getIndexMappings(index *api.ElasticsearchIndex) (map[string]interface{}, error) {
+	if index.Spec.Mappings == nil || len(index.Spec.Mappings.Raw) == 0 {
+		return nil, nil
+	}
+	mappings := map[string]interface{}{}
+	if err := json.Unmarshal(index.Spec.Mappings.Raw, &mappings); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode index mappings",
+			"index", index.GetIndexName())
+	}
+	return mappings, nil
+}
+
+// findMappingConflicts lists the fields whose type differs between the current and desired mappings
+func findMappingConflicts(current, desired map[string]interface{}, path string) []string {
+	var conflicts []string
+
+	currentProperties, _ := current["properties"].(map[string]interface{})
+	desiredProperties, _ := desired["properties"].(map[string]interface{})
+
+	for _, field := range sets.StringKeySet(desiredProperties).List() {
+		currentField, ok := currentProperties[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		desiredField, _ := desiredProperties[field].(map[string]interface{})
+
+		name := field
+		if path != "" {
+			name = path + "." + field
+		}
+
+		if currentType, desiredType := mappingFieldType(currentField), mappingFieldType(desiredField); currentType != desiredType {
+			conflicts = append(conflicts, fmt.Sprintf("type of field %s can not be changed from %s to %s", name, currentType, desiredType))
+			continue
+		}
+		conflicts = append(conflicts, findMappingConflicts(currentField, desiredField, name)...)
+	}
+
+	return conflicts
+}
+
+// hasNewMappingFields returns true if the desired mappings declare fields missing from the current ones
+func hasNewMappingFields(current, desired map[string]interface{}) bool {
+	currentProperties, _ := current["properties"].(map[string]interface{})
+	desiredProperties, _ := desired["properties"].(map[string]interface{})
+
+	for field, value := range desiredProperties {
+		currentField, ok := currentProperties[field].(map[string]interface{})
+		if !ok {
+			return true
+		}
+		desiredField, _ := value.(map[string]interface{})
+		if hasNewMappingFields(currentField, desiredField) {
+			return true
+		}
+	}
+	return false
+}
+
+func mappingFieldType(field map[string]interface{}) string {
+	if fieldType, ok := field["type"].(string); ok {
+		return fieldType
+	}
+	return "object"
+}
+
+// setResourceCondition sets the condition of the given type keeping its transition time when the status is unchanged
+func setResourceCondition(conditions api.ResourceConditions, conditionType api.ResourceConditionType, status corev1.ConditionStatus, reason, message string) api.ResourceConditions {
+	condition := api.ResourceCondition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	for i, existing := range conditions {
+		if existing.Type != conditionType {
+			continue
+		}
+		if existing.Status == status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		conditions[i] = condition
+		return conditions
+	}
+
+	return append(conditions, condition)
+}
+
+func updateElasticsearchIndexStatus(index *api.ElasticsearchIndex, conditions api.ResourceConditions, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchIndex{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: index.Name, Namespace: index.Namespace}, current); err != nil {
+			return err
+		}
+
+		if current.Status.ObservedGeneration == index.Generation &&
+			reflect.DeepEqual(current.Status.Conditions, conditions) &&
+			reflect.DeepEqual(current.Status.Aliases, index.Status.Aliases) {
+			return nil
+		}
+
+		current.Status.ObservedGeneration = index.Generation
+		current.Status.Conditions = conditions
+		current.Status.Aliases = index.Status.Aliases
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch index status",
+		"index", index.Name,
+		"namespace", index.Namespace)
+}
@@ -0,0 +1,160 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Elasticsearch Index", func() {
+	defer GinkgoRecover()
+
+	var (
+		current = map[string]interface{}{
+			"properties": map[string]interface{}{
+				"message": map[string]interface{}{"type": "text"},
+				"kubernetes": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"namespace": map[string]interface{}{"type": "keyword"},
+					},
+				},
+			},
+		}
+	)
+
+	Describe("#findMappingConflicts", func() {
+		It("should not report unchanged or new fields", func() {
+			desired := map[string]interface{}{
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{"type": "text"},
+					"level":   map[string]interface{}{"type": "keyword"},
+				},
+			}
+			Expect(findMappingConflicts(current, desired, "")).To(BeEmpty())
+		})
+
+		It("should report fields changing their type including nested ones", func() {
+			desired := map[string]interface{}{
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{"type": "keyword"},
+					"kubernetes": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"namespace": map[string]interface{}{"type": "text"},
+						},
+					},
+				},
+			}
+			Expect(findMappingConflicts(current, desired, "")).To(Equal([]string{
+				"type of field kubernetes.namespace can not be changed from keyword to text",
+				"type of field message can not be changed from text to keyword",
+			}))
+		})
+	})
+
+	Describe("#hasNewMappingFields", func() {
+		It("should be false when all fields exist", func() {
+			desired := map[string]interface{}{
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{"type": "text"},
+				},
+			}
+			Expect(hasNewMappingFields(current, desired)).To(BeFalse())
+		})
+
+		It("should be true for a new nested field", func() {
+			desired := map[string]interface{}{
+				"properties": map[string]interface{}{
+					"kubernetes": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"pod_name": map[string]interface{}{"type": "keyword"},
+						},
+					},
+				},
+			}
+			Expect(hasNewMappingFields(current, desired)).To(BeTrue())
+		})
+	})
+
+	Describe("#getIndexSettings", func() {
+		It("should flatten the declared settings", func() {
+			replicas := int32(0)
+			index := &api.ElasticsearchIndex{
+				Spec: api.ElasticsearchIndexSpec{
+					NumberOfShards:   3,
+					NumberOfReplicas: &replicas,
+					Settings: map[string]string{
+						"refresh_interval":        "30s",
+						"index.max_result_window": "5000",
+					},
+				},
+			}
+			Expect(getIndexSettings(index)).To(Equal(map[string]string{
+				"index.number_of_shards":   "3",
+				"index.number_of_replicas": "0",
+				"index.refresh_interval":   "30s",
+				"index.max_result_window":  "5000",
+			}))
+		})
+	})
+
+	Describe("#changedAliasActions", func() {
+		live := map[string]estypes.IndexAlias{
+			"app":       {},
+			"app-write": {IsWriteIndex: utils.GetBool(true)},
+		}
+
+		It("should not send aliases matching the live aliases", func() {
+			aliases := []api.ElasticsearchIndexAliasSpec{
+				{Name: "app"},
+				{Name: "app-write", IsWriteIndex: utils.GetBool(true)},
+			}
+			Expect(changedAliasActions("app-000001", live, aliases, nil).Actions).To(BeEmpty())
+		})
+
+		It("should send missing aliases and changed write index flags including false", func() {
+			aliases := []api.ElasticsearchIndexAliasSpec{
+				{Name: "app", IsWriteIndex: utils.GetBool(false)},
+				{Name: "app-write", IsWriteIndex: utils.GetBool(false)},
+				{Name: "app-read"},
+			}
+			actions := changedAliasActions("app-000001", live, aliases, nil)
+			body, err := utils.ToJSON(actions)
+			Expect(err).To(BeNil())
+			Expect(body).To(MatchJSON(`{"actions": [
+				{"add": {"index": "app-000001", "alias": "app", "is_write_index": false}},
+				{"add": {"index": "app-000001", "alias": "app-write", "is_write_index": false}},
+				{"add": {"index": "app-000001", "alias": "app-read"}}
+			]}`))
+		})
+
+		It("should remove the applied aliases dropped from the spec only", func() {
+			aliases := []api.ElasticsearchIndexAliasSpec{
+				{Name: "app-write", IsWriteIndex: utils.GetBool(true)},
+			}
+			actions := changedAliasActions("app-000001", live, aliases, []string{"app", "app-read", "app-write"})
+			body, err := utils.ToJSON(actions)
+			Expect(err).To(BeNil())
+			Expect(body).To(MatchJSON(`{"actions": [
+				{"remove": {"index": "app-000001", "alias": "app"}}
+			]}`))
+
+			Expect(changedAliasActions("app-000001", live, aliases, nil).Actions).To(BeEmpty())
+		})
+	})
+
+	Describe("#setResourceCondition", func() {
+		It("should keep the transition time when the status does not change", func() {
+			conditions := setResourceCondition(nil, api.ResourceReady, corev1.ConditionFalse, indexReasonClusterNotFound, "")
+			transition := conditions[0].LastTransitionTime
+
+			conditions = setResourceCondition(conditions, api.ResourceReady, corev1.ConditionFalse, indexReasonClusterUnavailable, "")
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Reason).To(Equal(indexReasonClusterUnavailable))
+			Expect(conditions[0].LastTransitionTime).To(Equal(transition))
+		})
+	})
+})
@@ -0,0 +1,239 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reindexCancelFinalizer = "logging.openshift.io/reindex-cancel"
+
+// ReconcileElasticsearchReindex starts the reindex task in the referenced Elasticsearch cluster and
+// tracks its progress until it finished. A running task is cancelled when the resource is deleted
+func ReconcileElasticsearchReindex(reindex *api.ElasticsearchReindex, requestClient client.Client) error {
+	status := reindex.Status.DeepCopy()
+	if status.Phase == "" {
+		status.Phase = api.ElasticsearchReindexPhasePending
+	}
+	deleted := reindex.GetDeletionTimestamp() != nil
+
+	if isReindexFinished(status.Phase) || (deleted && status.Phase == api.ElasticsearchReindexPhasePending) {
+		return updateReindexFinalizer(reindex, false, requestClient)
+	}
+
+	er, err := newElasticsearchRequestFor(reindex.Spec.ElasticsearchName, reindex.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	if deleted {
+		return cancelElasticsearchReindex(reindex, er, requestClient)
+	}
+
+	if er == nil {
+		status.Message = "Elasticsearch cluster not found"
+		return updateElasticsearchReindexStatus(reindex, status, requestClient)
+	}
+
+	if !er.AnyNodeReady() {
+		status.Message = "Waiting for an Elasticsearch node to be ready"
+		return updateElasticsearchReindexStatus(reindex, status, requestClient)
+	}
+
+	switch status.Phase {
+	case api.ElasticsearchReindexPhasePending:
+		// the finalizer is added before the task starts to never leave a task of a deleted resource running
+		if err := updateReindexFinalizer(reindex, true, requestClient); err != nil {
+			return err
+		}
+		if err := er.startReindex(reindex, status); err != nil {
+			return err
+		}
+	case api.ElasticsearchReindexPhaseRunning:
+		if err := er.refreshReindex(status); err != nil {
+			return err
+		}
+	}
+
+	if err := updateElasticsearchReindexStatus(reindex, status, requestClient); err != nil {
+		return err
+	}
+	if isReindexFinished(status.Phase) {
+		return updateReindexFinalizer(reindex, false, requestClient)
+	}
+	return nil
+}
+
+// cancelElasticsearchReindex cancels the running task of a deleted reindex. The task of a cluster
+// without ready nodes ended with the nodes running it
+func cancelElasticsearchReindex(reindex *api.ElasticsearchReindex, er *ElasticsearchRequest, requestClient client.Client) error {
+	if er != nil && er.AnyNodeReady() {
+		if err := er.esClient.CancelTask(reindex.Status.TaskID); err != nil {
+			return err
+		}
+		er.L().Info("Cancelled reindex task", "reindex", reindex.Name, "task", reindex.Status.TaskID)
+	}
+	return updateReindexFinalizer(reindex, false, requestClient)
+}
+
+// startReindex starts the reindex task once all nodes allow reindexing from the external cluster
+func (er *ElasticsearchRequest) startReindex(reindex *api.ElasticsearchReindex, status *api.ElasticsearchReindexStatus) error {
+	spec := reindex.Spec
+
+	source := estypes.ReIndexSource{
+		Index: spec.Source.Index,
+		Size:  spec.Source.BatchSize,
+	}
+	if source.Size == 0 {
+		source.Size = defaultRemoteReindexBatchSize
+	}
+
+	if remote := spec.Source.Remote; remote != nil {
+		message, err := er.remoteReindexWhitelistMessage(remoteReindexHost(remote.Host))
+		if err != nil || message != "" {
+			status.Message = message
+			return err
+		}
+
+		host, message, err := er.newRemoteHost(remote.Host, remote.Secret)
+		if err != nil || message != "" {
+			status.Message = message
+			return err
+		}
+		source.Remote = host
+	}
+
+	taskID, err := er.esClient.StartReIndex(&estypes.ReIndexRequest{
+		Conflicts: spec.Conflicts,
+		Source:    source,
+		Dest: estypes.ReIndexDest{
+			Index:  spec.Dest.Index,
+			OpType: spec.Dest.OpType,
+		},
+	})
+	if err != nil {
+		reason, _ := kverrors.KVs(err)[elasticsearch.ErrorReasonKey].(string)
+		if reason == "" {
+			return err
+		}
+		er.L().Error(err, "reindex rejected", "reindex", reindex.Name)
+		status.Phase = api.ElasticsearchReindexPhaseFailed
+		status.Message = reason
+		return nil
+	}
+
+	now := metav1.Now()
+	status.Phase = api.ElasticsearchReindexPhaseRunning
+	status.TaskID = taskID
+	status.StartTime = &now
+	status.Message = ""
+	return nil
+}
+
+// refreshReindex updates the progress of the running reindex task
+func (er *ElasticsearchRequest) refreshReindex(status *api.ElasticsearchReindexStatus) error {
+	task, err := er.esClient.GetReIndexTask(status.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if task == nil {
+		status.Phase = api.ElasticsearchReindexPhaseFailed
+		status.Message = fmt.Sprintf("Reindex task %s no longer exists", status.TaskID)
+		return nil
+	}
+
+	progress := task.Task.Status
+	if task.Response != nil {
+		progress = *task.Response
+	}
+	status.Total = progress.Total
+	status.Created = progress.Created
+	status.Updated = progress.Updated
+	status.VersionConflicts = progress.VersionConflicts
+	status.Failures = int64(len(progress.Failures))
+
+	if !task.Completed {
+		return nil
+	}
+
+	now := metav1.Now()
+	status.CompletionTime = &now
+
+	if failed, message := reindexTaskFailure(task, progress); failed {
+		status.Phase = api.ElasticsearchReindexPhaseFailed
+		status.Message = message
+	} else {
+		status.Phase = api.ElasticsearchReindexPhaseCompleted
+	}
+	return nil
+}
+
+// listElasticsearchReindexes returns the reindex resources in the namespace of the cluster
+func (er *ElasticsearchRequest) listElasticsearchReindexes() ([]api.ElasticsearchReindex, error) {
+	list := &api.ElasticsearchReindexList{}
+	if err := er.client.List(context.TODO(), list, client.InNamespace(er.cluster.Namespace)); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list elasticsearch reindexes",
+			"namespace", er.cluster.Namespace)
+	}
+	return list.Items, nil
+}
+
+func isReindexFinished(phase api.ElasticsearchReindexPhase) bool {
+	return phase == api.ElasticsearchReindexPhaseCompleted || phase == api.ElasticsearchReindexPhaseFailed
+}
+
+func updateReindexFinalizer(reindex *api.ElasticsearchReindex, present bool, requestClient client.Client) error {
+	if utils.ContainsString(reindex.GetFinalizers(), reindexCancelFinalizer) == present {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: reindex.Name, Namespace: reindex.Namespace}, reindex); err != nil {
+			return err
+		}
+
+		if utils.ContainsString(reindex.GetFinalizers(), reindexCancelFinalizer) == present {
+			return nil
+		}
+
+		if present {
+			reindex.SetFinalizers(append(reindex.GetFinalizers(), reindexCancelFinalizer))
+		} else {
+			reindex.SetFinalizers(utils.RemoveString(reindex.GetFinalizers(), reindexCancelFinalizer))
+		}
+		return requestClient.Update(context.TODO(), reindex)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch reindex finalizers",
+		"reindex", reindex.Name,
+		"namespace", reindex.Namespace)
+}
+
+func updateElasticsearchReindexStatus(reindex *api.ElasticsearchReindex, status *api.ElasticsearchReindexStatus, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchReindex{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: reindex.Name, Namespace: reindex.Namespace}, current); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(current.Status, *status) {
+			return nil
+		}
+
+		current.Status = *status
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch reindex status",
+		"reindex", reindex.Name,
+		"namespace", reindex.Namespace)
+}
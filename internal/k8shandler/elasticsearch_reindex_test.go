@@ -0,0 +1,159 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ElasticsearchReindex", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		reindex *api.ElasticsearchReindex
+	)
+
+	newRequest := func(objs ...runtime.Object) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, append(objs, cluster, pod)...),
+			cluster: cluster,
+		}
+	}
+
+	getReindex := func() *api.ElasticsearchReindex {
+		current := &api.ElasticsearchReindex{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: reindex.Name, Namespace: reindex.Namespace}, current)).To(Succeed())
+		return current
+	}
+
+	BeforeEach(func() {
+		reindex = &api.ElasticsearchReindex{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-reindex", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchReindexSpec{
+				ElasticsearchName: "elasticsearch",
+				Source:            api.ElasticsearchReindexSource{Index: "app-000001"},
+				Dest:              api.ElasticsearchReindexDest{Index: "app-v2-000001", OpType: "create"},
+				Conflicts:         "proceed",
+			},
+		}
+	})
+
+	It("should whitelist the hosts of the remote reindexes of the cluster", func() {
+		newRequest()
+		remote := reindex.DeepCopy()
+		remote.Spec.Source.Remote = &api.ElasticsearchReindexRemote{Host: "https://old-es.example.com:9200"}
+		other := remote.DeepCopy()
+		other.Spec.ElasticsearchName = "other"
+		other.Spec.Source.Remote.Host = "https://other-es.example.com:9200"
+
+		Expect(remoteReindexWhitelist(request.cluster, []api.ElasticsearchReindex{*reindex, *remote, *other})).To(Equal("old-es.example.com:9200"))
+	})
+
+	It("should start the reindex task", func() {
+		newRequest(reindex)
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_reindex?wait_for_completion=false": {
+					{StatusCode: http.StatusOK, Body: `{"task": "node-a:42"}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.startReindex(reindex, &reindex.Status)).To(Succeed())
+		Expect(reindex.Status.Phase).To(Equal(api.ElasticsearchReindexPhaseRunning))
+		Expect(reindex.Status.TaskID).To(Equal("node-a:42"))
+
+		req, _ := chatter.GetRequest("_reindex?wait_for_completion=false")
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"conflicts": "proceed",
+			"source": {"index": "app-000001", "size": 1000},
+			"dest": {"index": "app-v2-000001", "op_type": "create"}
+		}`)
+	})
+
+	It("should record the document counts of a completed task", func() {
+		newRequest()
+		status := &api.ElasticsearchReindexStatus{Phase: api.ElasticsearchReindexPhaseRunning, TaskID: "node-a:42"}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_tasks/node-a:42": {
+					{StatusCode: http.StatusOK, Body: `{"completed": true, "task": {"status": {"total": 100}},
+						"response": {"total": 100, "created": 97, "updated": 1, "version_conflicts": 1, "failures": [{"id": "1"}]}}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.refreshReindex(status)).To(Succeed())
+		Expect(status.Phase).To(Equal(api.ElasticsearchReindexPhaseFailed))
+		Expect(status.Created).To(Equal(int64(97)))
+		Expect(status.Updated).To(Equal(int64(1)))
+		Expect(status.Failures).To(Equal(int64(1)))
+		Expect(status.Message).To(Equal("1 documents failed to be copied"))
+	})
+
+	It("should report a missing cluster", func() {
+		reindex.Finalizers = []string{reindexCancelFinalizer}
+		reindex.Status = api.ElasticsearchReindexStatus{Phase: api.ElasticsearchReindexPhaseRunning, TaskID: "node-a:42"}
+		newRequest(reindex)
+		reindex.Spec.ElasticsearchName = "missing"
+
+		Expect(ReconcileElasticsearchReindex(reindex, request.client)).To(Succeed())
+		Expect(getReindex().Status.Phase).To(Equal(api.ElasticsearchReindexPhaseRunning))
+		Expect(getReindex().Status.Message).To(Equal("Elasticsearch cluster not found"))
+	})
+
+	It("should cancel the running task when the resource is deleted", func() {
+		now := metav1.Now()
+		reindex.Finalizers = []string{reindexCancelFinalizer}
+		reindex.DeletionTimestamp = &now
+		reindex.Status = api.ElasticsearchReindexStatus{Phase: api.ElasticsearchReindexPhaseRunning, TaskID: "node-a:42"}
+		newRequest(reindex)
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_tasks/node-a:42/_cancel": {
+					{StatusCode: http.StatusOK, Body: `{"nodes": {}}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(cancelElasticsearchReindex(reindex, request, request.client)).To(Succeed())
+		_, found := chatter.GetRequest("_tasks/node-a:42/_cancel")
+		Expect(found).To(BeTrue())
+		Expect(getReindex().Finalizers).To(BeEmpty())
+	})
+})
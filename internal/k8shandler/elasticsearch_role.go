@@ -0,0 +1,140 @@
+package k8shandler
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const elasticsearchRoleFinalizer = "logging.openshift.io/elasticsearch-role"
+
+// ReconcileElasticsearchRole creates or updates the role in the security plugin of the referenced
+// Elasticsearch cluster. The role is deleted from the cluster with the resource
+func ReconcileElasticsearchRole(role *api.ElasticsearchRole, requestClient client.Client) error {
+	er, err := newElasticsearchRequestFor(role.Spec.ElasticsearchName, role.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	if role.GetDeletionTimestamp() != nil {
+		if er == nil {
+			return updateElasticsearchRoleFinalizer(role, requestClient, false)
+		}
+		return er.deleteElasticsearchRole(role)
+	}
+
+	if err := updateElasticsearchRoleFinalizer(role, requestClient, true); err != nil {
+		return err
+	}
+
+	status := api.ElasticsearchRoleStatus{
+		ObservedGeneration: role.Generation,
+	}
+
+	if er == nil {
+		status.State = api.SecurityResourceStatePending
+		status.Reason = api.SecurityResourceReasonClusterNotFound
+		status.Message = "Elasticsearch cluster not found"
+		return updateElasticsearchRoleStatus(role, status, requestClient)
+	}
+
+	return er.applyElasticsearchRole(role, status)
+}
+
+// applyElasticsearchRole pushes the role to the security plugin of the cluster
+func (er *ElasticsearchRequest) applyElasticsearchRole(role *api.ElasticsearchRole, status api.ElasticsearchRoleStatus) error {
+	requestClient := er.client
+
+	if !er.AnyNodeReady() {
+		status.State = api.SecurityResourceStatePending
+		status.Reason = api.SecurityResourceReasonClusterUnavailable
+		status.Message = "Waiting for an Elasticsearch node to be ready"
+		return updateElasticsearchRoleStatus(role, status, requestClient)
+	}
+
+	if err := er.esClient.CreateOrUpdateSecurityRole(role.GetRoleName(), newSecurityRole(role.Spec)); err != nil {
+		er.L().Error(err, "failed to apply security role", "role", role.Name)
+		status.State = api.SecurityResourceStateFailed
+		status.Reason = api.SecurityResourceReasonRejected
+		status.Message = elasticsearchErrorReason(err)
+		return updateElasticsearchRoleStatus(role, status, requestClient)
+	}
+
+	status.State = api.SecurityResourceStateApplied
+	status.Reason = api.SecurityResourceReasonApplied
+	return updateElasticsearchRoleStatus(role, status, requestClient)
+}
+
+// deleteElasticsearchRole deletes the role from the security plugin of the cluster before releasing
+// the resource
+func (er *ElasticsearchRequest) deleteElasticsearchRole(role *api.ElasticsearchRole) error {
+	if err := er.esClient.DeleteSecurityRole(role.GetRoleName()); err != nil {
+		return err
+	}
+	return updateElasticsearchRoleFinalizer(role, er.client, false)
+}
+
+func newSecurityRole(spec api.ElasticsearchRoleSpec) *estypes.SecurityRole {
+	role := &estypes.SecurityRole{
+		ClusterPermissions: spec.ClusterPermissions,
+	}
+	for _, permission := range spec.IndexPermissions {
+		role.IndexPermissions = append(role.IndexPermissions, estypes.SecurityIndexPermission{
+			IndexPatterns:  permission.IndexPatterns,
+			DLS:            permission.DLS,
+			FLS:            permission.FLS,
+			MaskedFields:   permission.MaskedFields,
+			AllowedActions: permission.AllowedActions,
+		})
+	}
+	return role
+}
+
+func updateElasticsearchRoleFinalizer(role *api.ElasticsearchRole, requestClient client.Client, present bool) error {
+	if utils.ContainsString(role.GetFinalizers(), elasticsearchRoleFinalizer) == present {
+		return nil
+	}
+
+	if present {
+		role.SetFinalizers(append(role.GetFinalizers(), elasticsearchRoleFinalizer))
+	} else {
+		role.SetFinalizers(utils.RemoveString(role.GetFinalizers(), elasticsearchRoleFinalizer))
+	}
+
+	if err := requestClient.Update(context.TODO(), role); err != nil {
+		return kverrors.Wrap(err, "failed to update elasticsearch role finalizers",
+			"role", role.Name,
+			"namespace", role.Namespace)
+	}
+	return nil
+}
+
+func updateElasticsearchRoleStatus(role *api.ElasticsearchRole, status api.ElasticsearchRoleStatus, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchRole{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: role.Namespace}, current); err != nil {
+			return err
+		}
+
+		if current.Status.State == status.State &&
+			current.Status.Reason == status.Reason &&
+			current.Status.Message == status.Message &&
+			current.Status.ObservedGeneration == status.ObservedGeneration {
+			return nil
+		}
+
+		status.LastUpdated = metav1.Now()
+		current.Status = status
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch role status",
+		"role", role.Name,
+		"namespace", role.Namespace)
+}
@@ -0,0 +1,207 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ElasticsearchRole", func() {
+	defer GinkgoRecover()
+
+	It("should translate the index permissions of the role", func() {
+		role := newSecurityRole(api.ElasticsearchRoleSpec{
+			ClusterPermissions: []string{"cluster_monitor"},
+			IndexPermissions: []api.RoleIndexPermissionSpec{
+				{
+					IndexPatterns:  []string{"app-*"},
+					AllowedActions: []string{"read"},
+					DLS:            `{"term": {"kubernetes.namespace_name": "${attr.internal.namespace}"}}`,
+					FLS:            []string{"~message"},
+					MaskedFields:   []string{"kubernetes.pod_ip"},
+				},
+			},
+		})
+
+		body, err := json.Marshal(role)
+		Expect(err).To(BeNil())
+		helpers.ExpectJSON(string(body)).ToEqual(`{
+			"cluster_permissions": ["cluster_monitor"],
+			"index_permissions": [{
+				"index_patterns": ["app-*"],
+				"dls": "{\"term\": {\"kubernetes.namespace_name\": \"${attr.internal.namespace}\"}}",
+				"fls": ["~message"],
+				"masked_fields": ["kubernetes.pod_ip"],
+				"allowed_actions": ["read"]
+			}]
+		}`)
+	})
+
+	Describe("#applyElasticsearchRole", func() {
+		const roleURI = "_opendistro/_security/api/roles/log-reader"
+
+		var (
+			chatter *helpers.FakeElasticsearchChatter
+			request *ElasticsearchRequest
+			role    *api.ElasticsearchRole
+		)
+
+		newRequest := func(responses helpers.FakeElasticsearchResponses, podPhase corev1.PodPhase) {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+
+			cluster := &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch-cdm-1",
+					Namespace: "openshift-logging",
+					Labels: map[string]string{
+						"component":    "elasticsearch",
+						"cluster-name": "elasticsearch",
+						"es-node-data": "true",
+					},
+				},
+				Status: corev1.PodStatus{Phase: podPhase},
+			}
+			request = &ElasticsearchRequest{
+				client:  fake.NewFakeClientWithScheme(s, cluster, pod, role),
+				cluster: cluster,
+			}
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{roleURI: responses},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+		}
+
+		getRole := func() *api.ElasticsearchRole {
+			current := &api.ElasticsearchRole{}
+			Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: role.Namespace}, current)).To(Succeed())
+			return current
+		}
+
+		apply := func() error {
+			current := getRole()
+			return request.applyElasticsearchRole(current, api.ElasticsearchRoleStatus{ObservedGeneration: current.Generation})
+		}
+
+		BeforeEach(func() {
+			role = &api.ElasticsearchRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "reader",
+					Namespace:  "openshift-logging",
+					Finalizers: []string{elasticsearchRoleFinalizer},
+				},
+				Spec: api.ElasticsearchRoleSpec{
+					ElasticsearchName:  "elasticsearch",
+					RoleName:           "log-reader",
+					ClusterPermissions: []string{"cluster_monitor"},
+					IndexPermissions: []api.RoleIndexPermissionSpec{
+						{IndexPatterns: []string{"app-*"}, AllowedActions: []string{"read"}},
+					},
+				},
+			}
+		})
+
+		It("should push the role under the name of the spec", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusCreated, Body: `{"status": "CREATED"}`}}, corev1.PodRunning)
+
+			Expect(apply()).To(Succeed())
+
+			req, found := chatter.GetRequest(roleURI)
+			Expect(found).To(BeTrue())
+			Expect(req.Method).To(Equal(http.MethodPut))
+			helpers.ExpectJSON(req.Body).ToEqual(`{
+				"cluster_permissions": ["cluster_monitor"],
+				"index_permissions": [{
+					"index_patterns": ["app-*"],
+					"allowed_actions": ["read"]
+				}]
+			}`)
+
+			current := getRole()
+			Expect(current.Status.State).To(Equal(api.SecurityResourceStateApplied))
+			Expect(current.Status.Reason).To(Equal(api.SecurityResourceReasonApplied))
+		})
+
+		It("should update the role once the spec changed", func() {
+			newRequest(helpers.FakeElasticsearchResponses{
+				{StatusCode: http.StatusCreated, Body: `{"status": "CREATED"}`},
+				{StatusCode: http.StatusOK, Body: `{"status": "OK"}`},
+			}, corev1.PodRunning)
+
+			Expect(apply()).To(Succeed())
+
+			current := getRole()
+			current.Spec.ClusterPermissions = []string{"cluster_composite_ops_ro"}
+			Expect(request.client.Update(context.TODO(), current)).To(Succeed())
+			Expect(apply()).To(Succeed())
+
+			Expect(chatter.Requests[roleURI]).To(HaveLen(2))
+			helpers.ExpectJSON(chatter.Requests[roleURI][1].Body).ToEqual(`{
+				"cluster_permissions": ["cluster_composite_ops_ro"],
+				"index_permissions": [{
+					"index_patterns": ["app-*"],
+					"allowed_actions": ["read"]
+				}]
+			}`)
+			Expect(getRole().Status.State).To(Equal(api.SecurityResourceStateApplied))
+		})
+
+		It("should wait for a node of the cluster to be ready", func() {
+			newRequest(nil, corev1.PodPending)
+
+			Expect(apply()).To(Succeed())
+			Expect(chatter.Requests).ToNot(HaveKey(roleURI))
+
+			current := getRole()
+			Expect(current.Status.State).To(Equal(api.SecurityResourceStatePending))
+			Expect(current.Status.Reason).To(Equal(api.SecurityResourceReasonClusterUnavailable))
+		})
+
+		It("should report the reason Elasticsearch rejected the role", func() {
+			newRequest(helpers.FakeElasticsearchResponses{
+				{StatusCode: http.StatusBadRequest, Body: `{"status": "error", "message": "Resource 'log-reader' is reserved."}`},
+			}, corev1.PodRunning)
+
+			Expect(apply()).To(Succeed())
+
+			current := getRole()
+			Expect(current.Status.State).To(Equal(api.SecurityResourceStateFailed))
+			Expect(current.Status.Reason).To(Equal(api.SecurityResourceReasonRejected))
+			Expect(current.Status.Message).To(Equal("Resource 'log-reader' is reserved."))
+		})
+
+		It("should delete the role from the cluster before releasing the resource", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusOK, Body: `{"status": "OK"}`}}, corev1.PodRunning)
+
+			Expect(request.deleteElasticsearchRole(getRole())).To(Succeed())
+
+			req, found := chatter.GetRequest(roleURI)
+			Expect(found).To(BeTrue())
+			Expect(req.Method).To(Equal(http.MethodDelete))
+			Expect(getRole().Finalizers).To(BeEmpty())
+		})
+
+		It("should keep the resource if the role could not be deleted", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusInternalServerError, Body: `{"status": "error"}`}}, corev1.PodRunning)
+
+			Expect(request.deleteElasticsearchRole(getRole())).ToNot(Succeed())
+			Expect(getRole().Finalizers).To(ConsistOf(elasticsearchRoleFinalizer))
+		})
+	})
+})
@@ -0,0 +1,190 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	elasticsearchUserFinalizer = "logging.openshift.io/elasticsearch-user"
+	defaultUserPasswordKey     = "password"
+)
+
+// ReconcileElasticsearchUser creates or updates the user in the internal user database of the
+// referenced Elasticsearch cluster. The password is only pushed when the spec or the password in
+// the secret changed, detected by the salted hash of the status. The user is deleted from the
+// cluster with the resource
+func ReconcileElasticsearchUser(user *api.ElasticsearchUser, requestClient client.Client) error {
+	er, err := newElasticsearchRequestFor(user.Spec.ElasticsearchName, user.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	if user.GetDeletionTimestamp() != nil {
+		if er != nil {
+			if err := er.esClient.DeleteInternalUser(user.GetUsername()); err != nil {
+				return err
+			}
+		}
+		return updateElasticsearchUserFinalizer(user, requestClient, false)
+	}
+
+	if err := updateElasticsearchUserFinalizer(user, requestClient, true); err != nil {
+		return err
+	}
+
+	status := api.ElasticsearchUserStatus{
+		ObservedGeneration: user.Generation,
+		PasswordHash:       user.Status.PasswordHash,
+	}
+
+	if er == nil {
+		status.State = api.SecurityResourceStatePending
+		status.Reason = api.SecurityResourceReasonClusterNotFound
+		status.Message = "Elasticsearch cluster not found"
+		return updateElasticsearchUserStatus(user, status, requestClient)
+	}
+
+	return er.applyElasticsearchUser(user, status)
+}
+
+// applyElasticsearchUser pushes the user to the cluster unless it is applied with the current spec
+// and password
+func (er *ElasticsearchRequest) applyElasticsearchUser(user *api.ElasticsearchUser, status api.ElasticsearchUserStatus) error {
+	requestClient := er.client
+
+	password, err := userPassword(user, requestClient)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		status.State = api.SecurityResourceStatePending
+		status.Reason = api.SecurityResourceReasonSecretNotFound
+		status.Message = fmt.Sprintf("Waiting for the password in key %q of secret %q", userPasswordKey(user), user.Spec.PasswordSecretRef.Name)
+		return updateElasticsearchUserStatus(user, status, requestClient)
+	}
+
+	hash := userPasswordHash(user, password)
+	if user.Status.State == api.SecurityResourceStateApplied &&
+		user.Status.ObservedGeneration == user.Generation &&
+		user.Status.PasswordHash == hash {
+		return nil
+	}
+
+	if !er.AnyNodeReady() {
+		status.State = api.SecurityResourceStatePending
+		status.Reason = api.SecurityResourceReasonClusterUnavailable
+		status.Message = "Waiting for an Elasticsearch node to be ready"
+		return updateElasticsearchUserStatus(user, status, requestClient)
+	}
+
+	ll := er.L().WithValues("user", user.Name)
+	if err := er.esClient.CreateOrUpdateInternalUser(user.GetUsername(), newInternalUser(user, password)); err != nil {
+		ll.Error(err, "failed to apply internal user")
+		status.State = api.SecurityResourceStateFailed
+		status.Reason = api.SecurityResourceReasonRejected
+		status.Message = elasticsearchErrorReason(err)
+		return updateElasticsearchUserStatus(user, status, requestClient)
+	}
+
+	if user.Status.PasswordHash != "" && user.Status.PasswordHash != hash {
+		ll.Info("Pushed the rotated password of the user")
+	}
+	status.State = api.SecurityResourceStateApplied
+	status.Reason = api.SecurityResourceReasonApplied
+	status.PasswordHash = hash
+	return updateElasticsearchUserStatus(user, status, requestClient)
+}
+
+func newInternalUser(user *api.ElasticsearchUser, password string) *estypes.InternalUser {
+	return &estypes.InternalUser{
+		Password:      password,
+		BackendRoles:  user.Spec.BackendRoles,
+		SecurityRoles: user.Spec.Roles,
+		Attributes:    user.Spec.Attributes,
+	}
+}
+
+func userPasswordKey(user *api.ElasticsearchUser) string {
+	if user.Spec.PasswordSecretRef.Key != "" {
+		return user.Spec.PasswordSecretRef.Key
+	}
+	return defaultUserPasswordKey
+}
+
+// userPassword returns the password of the user or an empty string if its secret or key is missing
+func userPassword(user *api.ElasticsearchUser, requestClient client.Client) (string, error) {
+	name := user.Spec.PasswordSecretRef.Name
+
+	secret := &corev1.Secret{}
+	if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: user.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", kverrors.Wrap(err, "failed to get password secret",
+			"secret", name,
+			"namespace", user.Namespace)
+	}
+	return string(secret.Data[userPasswordKey(user)]), nil
+}
+
+// userPasswordHash returns the hash of the password salted with the UID of the resource, so the
+// status does not reveal passwords shared between users
+func userPasswordHash(user *api.ElasticsearchUser, password string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(string(user.UID)+password)))
+}
+
+func updateElasticsearchUserFinalizer(user *api.ElasticsearchUser, requestClient client.Client, present bool) error {
+	if utils.ContainsString(user.GetFinalizers(), elasticsearchUserFinalizer) == present {
+		return nil
+	}
+
+	if present {
+		user.SetFinalizers(append(user.GetFinalizers(), elasticsearchUserFinalizer))
+	} else {
+		user.SetFinalizers(utils.RemoveString(user.GetFinalizers(), elasticsearchUserFinalizer))
+	}
+
+	if err := requestClient.Update(context.TODO(), user); err != nil {
+		return kverrors.Wrap(err, "failed to update elasticsearch user finalizers",
+			"user", user.Name,
+			"namespace", user.Namespace)
+	}
+	return nil
+}
+
+func updateElasticsearchUserStatus(user *api.ElasticsearchUser, status api.ElasticsearchUserStatus, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchUser{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: user.Name, Namespace: user.Namespace}, current); err != nil {
+			return err
+		}
+
+		if current.Status.State == status.State &&
+			current.Status.Reason == status.Reason &&
+			current.Status.Message == status.Message &&
+			current.Status.PasswordHash == status.PasswordHash &&
+			current.Status.ObservedGeneration == status.ObservedGeneration {
+			return nil
+		}
+
+		status.LastUpdated = metav1.Now()
+		current.Status = status
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch user status",
+		"user", user.Name,
+		"namespace", user.Namespace)
+}
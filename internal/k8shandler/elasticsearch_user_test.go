@@ -0,0 +1,157 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ElasticsearchUser", func() {
+	defer GinkgoRecover()
+
+	const userURI = "_opendistro/_security/api/internalusers/fluentd"
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		user    *api.ElasticsearchUser
+		secret  *corev1.Secret
+	)
+
+	newRequest := func(responses helpers.FakeElasticsearchResponses, objs ...runtime.Object) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, append(objs, cluster, pod)...),
+			cluster: cluster,
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{userURI: responses},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	}
+
+	getUser := func() *api.ElasticsearchUser {
+		current := &api.ElasticsearchUser{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: user.Name, Namespace: user.Namespace}, current)).To(Succeed())
+		return current
+	}
+
+	apply := func() error {
+		current := getUser()
+		return request.applyElasticsearchUser(current, api.ElasticsearchUserStatus{
+			ObservedGeneration: current.Generation,
+			PasswordHash:       current.Status.PasswordHash,
+		})
+	}
+
+	BeforeEach(func() {
+		user = &api.ElasticsearchUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "fluentd", Namespace: "openshift-logging", UID: "4a1c"},
+			Spec: api.ElasticsearchUserSpec{
+				ElasticsearchName: "elasticsearch",
+				PasswordSecretRef: api.UserPasswordSecretReference{Name: "fluentd-password"},
+				Roles:             []string{"log-writer"},
+				BackendRoles:      []string{"collectors"},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "fluentd-password", Namespace: "openshift-logging"},
+			Data:       map[string][]byte{"password": []byte("changeme")},
+		}
+	})
+
+	It("should push the user with the password of the secret", func() {
+		newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusCreated, Body: `{"status": "CREATED"}`}}, user, secret)
+
+		Expect(apply()).To(Succeed())
+
+		req, _ := chatter.GetRequest(userURI)
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"password": "changeme",
+			"backend_roles": ["collectors"],
+			"opendistro_security_roles": ["log-writer"]
+		}`)
+
+		current := getUser()
+		Expect(current.Status.State).To(Equal(api.SecurityResourceStateApplied))
+		Expect(current.Status.PasswordHash).To(Equal(userPasswordHash(user, "changeme")))
+		Expect(current.Status.PasswordHash).ToNot(ContainSubstring("changeme"))
+	})
+
+	It("should only push the password again once it is rotated", func() {
+		newRequest(helpers.FakeElasticsearchResponses{
+			{StatusCode: http.StatusCreated, Body: `{"status": "CREATED"}`},
+			{StatusCode: http.StatusOK, Body: `{"status": "OK"}`},
+		}, user, secret)
+
+		Expect(apply()).To(Succeed())
+		Expect(apply()).To(Succeed())
+		Expect(chatter.Requests[userURI]).To(HaveLen(1))
+
+		secret.Data["password"] = []byte("rotated")
+		Expect(request.client.Update(context.TODO(), secret)).To(Succeed())
+		Expect(apply()).To(Succeed())
+
+		Expect(chatter.Requests[userURI]).To(HaveLen(2))
+		helpers.ExpectJSON(chatter.Requests[userURI][1].Body).ToEqual(`{
+			"password": "rotated",
+			"backend_roles": ["collectors"],
+			"opendistro_security_roles": ["log-writer"]
+		}`)
+		Expect(getUser().Status.PasswordHash).To(Equal(userPasswordHash(user, "rotated")))
+	})
+
+	It("should wait for the password secret", func() {
+		newRequest(nil, user)
+
+		Expect(apply()).To(Succeed())
+		Expect(chatter.Requests).ToNot(HaveKey(userURI))
+
+		current := getUser()
+		Expect(current.Status.State).To(Equal(api.SecurityResourceStatePending))
+		Expect(current.Status.Reason).To(Equal(api.SecurityResourceReasonSecretNotFound))
+	})
+
+	It("should report the reason Elasticsearch rejected the user", func() {
+		newRequest(helpers.FakeElasticsearchResponses{
+			{StatusCode: http.StatusBadRequest, Body: `{"status": "error", "message": "Resource 'fluentd' is reserved."}`},
+		}, user, secret)
+
+		Expect(apply()).To(Succeed())
+
+		current := getUser()
+		Expect(current.Status.State).To(Equal(api.SecurityResourceStateFailed))
+		Expect(current.Status.Reason).To(Equal(api.SecurityResourceReasonRejected))
+		Expect(current.Status.Message).To(Equal("Resource 'fluentd' is reserved."))
+		Expect(current.Status.PasswordHash).To(BeEmpty())
+	})
+})
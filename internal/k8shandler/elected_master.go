@@ -0,0 +1,61 @@
+package k8shandler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// electedMaster returns the pod of the elected master node or nil if it is unknown
+func (er *ElasticsearchRequest) electedMaster() *v1.Pod {
+	cluster := er.cluster
+
+	ip, err := er.esClient.GetElectedMasterIP()
+	if err != nil {
+		er.L().Info("Unable to get the elected master", "error", err)
+		return nil
+	}
+	if ip == "" {
+		return nil
+	}
+
+	pods := &v1.PodList{}
+	labels := client.MatchingLabels{"cluster-name": cluster.Name, "component": "elasticsearch"}
+	if err := er.client.List(context.TODO(), pods, client.InNamespace(cluster.Namespace), labels); err != nil {
+		er.L().Info("Unable to list the pods of the cluster", "error", err)
+		return nil
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.PodIP == ip {
+			return &pods.Items[i]
+		}
+	}
+	return nil
+}
+
+// electedMasterLast returns the nodes with the node running the elected master moved last. The
+// order of the other nodes is kept. The guarantee is per node group only: the pods of a
+// statefulset restart in the order of their ordinals, so restarting a node group of several
+// master pods may elect a new master more than once
+func (er *ElasticsearchRequest) electedMasterLast(nodes []NodeTypeInterface) []NodeTypeInterface {
+	ordered := append([]NodeTypeInterface{}, nodes...)
+	if len(nodes) < 2 {
+		return ordered
+	}
+
+	pod := er.electedMaster()
+	if pod == nil {
+		return ordered
+	}
+
+	for i, node := range ordered {
+		if node.name() != pod.Labels["node-name"] {
+			continue
+		}
+		ordered = append(append(ordered[:i:i], ordered[i+1:]...), node)
+		break
+	}
+	return ordered
+}
@@ -0,0 +1,83 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Elected master", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		nodes   []NodeTypeInterface
+	)
+
+	newNode := func(name string) NodeTypeInterface {
+		return &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+
+	newPod := func(name, nodeName, ip string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"cluster-name": "elasticsearch",
+					"component":    "elasticsearch",
+					"node-name":    nodeName,
+				},
+			},
+			Status: v1.PodStatus{PodIP: ip},
+		}
+	}
+
+	BeforeEach(func() {
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(
+				newPod("elasticsearch-cdm-abc-1-7c9f", "elasticsearch-cdm-abc-1", "10.128.2.15"),
+				newPod("elasticsearch-cdm-abc-2-5d8b", "elasticsearch-cdm-abc-2", "10.128.2.16"),
+				newPod("elasticsearch-cdm-abc-3-6f7a", "elasticsearch-cdm-abc-3", "10.128.2.17"),
+			),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			},
+		}
+		chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_cat/master?h=ip&format=json": {
+				{StatusCode: 200, Body: `[{"ip": "10.128.2.16"}]`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		nodes = []NodeTypeInterface{
+			newNode("elasticsearch-cdm-abc-1"),
+			newNode("elasticsearch-cdm-abc-2"),
+			newNode("elasticsearch-cdm-abc-3"),
+		}
+	})
+
+	It("should find the pod of the elected master by its address", func() {
+		pod := request.electedMaster()
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("elasticsearch-cdm-abc-2-5d8b"))
+	})
+
+	It("should move the node of the elected master last and keep the order of the others", func() {
+		ordered := request.electedMasterLast(nodes)
+
+		names := []string{}
+		for _, node := range ordered {
+			names = append(names, node.name())
+		}
+		Expect(names).To(Equal([]string{"elasticsearch-cdm-abc-1", "elasticsearch-cdm-abc-3", "elasticsearch-cdm-abc-2"}))
+		Expect(nodes[1].name()).To(Equal("elasticsearch-cdm-abc-2"))
+	})
+})
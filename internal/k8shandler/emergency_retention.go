@@ -0,0 +1,217 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultEmergencyCriticalThreshold   = 94
+	defaultEmergencyMinDeletionInterval = 5 * time.Minute
+	maxEmergencyDeletions               = 10
+)
+
+// ReconcileEmergencyRetention deletes the oldest index matching the patterns of the spec with shards
+// on a node using more of its disk than the critical threshold, at most once per deletion interval,
+// to keep the cluster writable when the write blocks of the disk pressure are not enough
+func (er *ElasticsearchRequest) ReconcileEmergencyRetention() error {
+	return er.reconcileEmergencyRetention(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileEmergencyRetention(now time.Time) error {
+	cluster := er.cluster
+	spec := cluster.Spec.EmergencyRetention
+	current := cluster.Status.EmergencyRetention
+
+	if spec == nil {
+		return er.updateEmergencyRetentionStatus(nil)
+	}
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	status := &api.EmergencyRetentionStatus{}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+
+	usage, err := er.esClient.GetNodesDiskUsedPercent()
+	if err != nil {
+		return err
+	}
+	status.UsedPercent = highestDiskUsage(usage)
+
+	if status.UsedPercent < emergencyCriticalThreshold(spec) {
+		status.Message = ""
+		return er.updateEmergencyRetentionStatus(status)
+	}
+
+	// the disk usage reported by the nodes lags behind a deletion
+	if last := lastEmergencyDeletion(status); !spec.DryRun && last != nil &&
+		now.Sub(last.DeletedAt.Time) < emergencyMinDeletionInterval(spec) {
+		status.Message = fmt.Sprintf("Waiting for the disk freed by the deletion of index %s before deleting another index", last.Index)
+		return er.updateEmergencyRetentionStatus(status)
+	}
+
+	criticalNodes := sets.NewString()
+	for node, percent := range usage {
+		if int32(percent) >= emergencyCriticalThreshold(spec) {
+			criticalNodes.Insert(node)
+		}
+	}
+
+	index, err := er.oldestDeletableIndex(spec, criticalNodes)
+	if err != nil {
+		return err
+	}
+	if index == "" {
+		status.Message = "No index matching the patterns with shards on the nodes above the critical threshold can be deleted"
+		return er.updateEmergencyRetentionStatus(status)
+	}
+
+	if spec.DryRun {
+		// the selected index is recorded once until the oldest index changes
+		if last := len(status.DeletedIndices) - 1; last < 0 || !status.DeletedIndices[last].DryRun || status.DeletedIndices[last].Index != index {
+			er.L().Info("Selected index to delete under critical disk pressure in dry-run mode", "index", index, "used_percent", status.UsedPercent)
+			er.recordEmergencyDeletion("EmergencyIndexDeletionDryRun",
+				fmt.Sprintf("Would delete index %s as a node uses %d%% of its disk", index, status.UsedPercent))
+			appendEmergencyDeletion(status, api.EmergencyDeletionStatus{Index: index, DeletedAt: metav1.NewTime(now), DryRun: true})
+		}
+		status.Message = ""
+		return er.updateEmergencyRetentionStatus(status)
+	}
+
+	if err := er.esClient.DeleteIndex(index); err != nil {
+		er.L().Error(err, "Failed to delete index under critical disk pressure", "index", index)
+		status.Message = fmt.Sprintf("Unable to delete index %s: %s", index, elasticsearchErrorReason(err))
+		return er.updateEmergencyRetentionStatus(status)
+	}
+
+	er.L().Info("Deleted index under critical disk pressure", "index", index, "used_percent", status.UsedPercent)
+	er.recordEmergencyDeletion("EmergencyIndexDeleted",
+		fmt.Sprintf("Deleted index %s as a node uses %d%% of its disk", index, status.UsedPercent))
+	appendEmergencyDeletion(status, api.EmergencyDeletionStatus{Index: index, DeletedAt: metav1.NewTime(now)})
+	status.Message = ""
+	return er.updateEmergencyRetentionStatus(status)
+}
+
+func emergencyCriticalThreshold(spec *api.EmergencyRetentionSpec) int32 {
+	if spec.CriticalThreshold != nil {
+		return *spec.CriticalThreshold
+	}
+	return defaultEmergencyCriticalThreshold
+}
+
+func emergencyMinDeletionInterval(spec *api.EmergencyRetentionSpec) time.Duration {
+	if spec.MinDeletionInterval != nil {
+		return spec.MinDeletionInterval.Duration
+	}
+	return defaultEmergencyMinDeletionInterval
+}
+
+// lastEmergencyDeletion returns the last index deleted outside of the dry-run mode or nil
+func lastEmergencyDeletion(status *api.EmergencyRetentionStatus) *api.EmergencyDeletionStatus {
+	for i := len(status.DeletedIndices) - 1; i >= 0; i-- {
+		if !status.DeletedIndices[i].DryRun {
+			return &status.DeletedIndices[i]
+		}
+	}
+	return nil
+}
+
+// oldestDeletableIndex returns the oldest index matching the patterns of the spec with a shard on
+// one of the nodes which is not written to through an alias or an empty string. Deleting indices
+// without shards on the nodes would not free their disk
+func (er *ElasticsearchRequest) oldestDeletableIndex(spec *api.EmergencyRetentionSpec, nodes sets.String) (string, error) {
+	writeIndices, err := er.esClient.ListWriteIndices()
+	if err != nil {
+		return "", err
+	}
+
+	shards, err := er.esClient.GetShardStats()
+	if err != nil {
+		return "", err
+	}
+	onNodes := sets.NewString()
+	for _, shard := range shards {
+		if nodes.Has(shard.Node) {
+			onNodes.Insert(shard.Index)
+		}
+	}
+
+	oldest := ""
+	oldestCreated := int64(0)
+	for _, pattern := range spec.IndexPatterns {
+		indices, err := er.esClient.ListIndicesCreationDate(pattern)
+		if err != nil {
+			return "", err
+		}
+
+		for _, index := range indices {
+			if writeIndices.Has(index.Index) || !onNodes.Has(index.Index) {
+				continue
+			}
+			if strings.HasPrefix(index.Index, ".") && !strings.HasPrefix(pattern, ".") {
+				continue
+			}
+
+			created, err := strconv.ParseInt(index.CreationDate, 10, 64)
+			if err != nil {
+				continue
+			}
+			if oldest == "" || created < oldestCreated || (created == oldestCreated && index.Index < oldest) {
+				oldest = index.Index
+				oldestCreated = created
+			}
+		}
+	}
+	return oldest, nil
+}
+
+func (er *ElasticsearchRequest) recordEmergencyDeletion(reason, message string) {
+	if er.recorder != nil {
+		er.recorder.Event(er.cluster, v1.EventTypeWarning, reason, message)
+	}
+}
+
+// appendEmergencyDeletion records the deletion and keeps the last deletions only
+func appendEmergencyDeletion(status *api.EmergencyRetentionStatus, deletion api.EmergencyDeletionStatus) {
+	status.DeletedIndices = append(status.DeletedIndices, deletion)
+	if len(status.DeletedIndices) > maxEmergencyDeletions {
+		status.DeletedIndices = status.DeletedIndices[len(status.DeletedIndices)-maxEmergencyDeletions:]
+	}
+}
+
+func (er *ElasticsearchRequest) updateEmergencyRetentionStatus(status *api.EmergencyRetentionStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.EmergencyRetention, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.EmergencyRetention, status) {
+			return nil
+		}
+
+		cluster.Status.EmergencyRetention = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update emergency retention status")
+}
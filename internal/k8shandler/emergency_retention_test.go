@@ -0,0 +1,227 @@
+package k8shandler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Emergency retention", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter  *helpers.FakeElasticsearchChatter
+		request  *ElasticsearchRequest
+		cluster  *api.Elasticsearch
+		recorder *record.FakeRecorder
+		now      = time.Now()
+	)
+
+	nodesStats := func(percent int) helpers.FakeElasticsearchResponse {
+		return helpers.FakeElasticsearchResponse{StatusCode: http.StatusOK, Body: fmt.Sprintf(`{"nodes": {
+			"abc": {"name": "elasticsearch-cdm-abc-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": %d}}}
+		}}`, 100-percent)}
+	}
+
+	aliases := helpers.FakeElasticsearchResponse{StatusCode: http.StatusOK, Body: `{
+		"app-000003": {"aliases": {"app-write": {"is_write_index": true}}},
+		"app-000002": {"aliases": {"app-write": {"is_write_index": false}}}
+	}`}
+
+	shardsURI := "_cat/shards?h=index,shard,prirep,state,node,indexing.index_total,search.query_total&format=json"
+	shards := helpers.FakeElasticsearchResponse{StatusCode: http.StatusOK, Body: `[
+		{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"},
+		{"index": "app-000002", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"},
+		{"index": "app-000003", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"},
+		{"index": "infra-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"}
+	]`}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				EmergencyRetention: &api.EmergencyRetentionSpec{
+					IndexPatterns: []string{"app-*", "infra-*"},
+				},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		recorder = record.NewFakeRecorder(5)
+		request = &ElasticsearchRequest{
+			client:   fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster:  cluster,
+			recorder: recorder,
+		}
+	})
+
+	It("should delete the oldest index matching the patterns while the disk usage is critical", func() {
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {nodesStats(96), nodesStats(95), nodesStats(90)},
+			"_alias":          {aliases, aliases},
+			shardsURI:         {shards, shards},
+			"_cat/indices/app-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[
+					{"index": "app-000001", "status": "close", "creation.date": "1000"},
+					{"index": "app-000002", "status": "open", "creation.date": "2000"},
+					{"index": "app-000003", "status": "open", "creation.date": "500"}
+				]`},
+				{StatusCode: http.StatusOK, Body: `[
+					{"index": "app-000002", "status": "open", "creation.date": "2000"},
+					{"index": "app-000003", "status": "open", "creation.date": "500"}
+				]`},
+			},
+			"_cat/indices/infra-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[{"index": "infra-000001", "status": "open", "creation.date": "1500"}]`},
+				{StatusCode: http.StatusOK, Body: `[{"index": "infra-000001", "status": "open", "creation.date": "1500"}]`},
+			},
+			"app-000001": {
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+			},
+			"infra-000001": {
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.reconcileEmergencyRetention(now)).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.UsedPercent).To(Equal(int32(96)))
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(Equal([]api.EmergencyDeletionStatus{
+			{Index: "app-000001", DeletedAt: metav1.NewTime(now)},
+		}))
+		Expect(recorder.Events).To(Receive(Equal("Warning EmergencyIndexDeleted Deleted index app-000001 as a node uses 96% of its disk")))
+
+		later := now.Add(defaultEmergencyMinDeletionInterval)
+		Expect(request.reconcileEmergencyRetention(later)).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(HaveLen(2))
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices[1].Index).To(Equal("infra-000001"))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning EmergencyIndexDeleted Deleted index infra-000001")))
+
+		Expect(request.reconcileEmergencyRetention(later.Add(defaultEmergencyMinDeletionInterval))).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.UsedPercent).To(Equal(int32(90)))
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(HaveLen(2))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should only record the index to delete once in the dry-run mode", func() {
+		cluster.Spec.EmergencyRetention.DryRun = true
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {nodesStats(96), nodesStats(96)},
+			"_alias":          {aliases, aliases},
+			shardsURI:         {shards, shards},
+			"_cat/indices/app-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[{"index": "app-000001", "status": "open", "creation.date": "1000"}]`},
+				{StatusCode: http.StatusOK, Body: `[{"index": "app-000001", "status": "open", "creation.date": "1000"}]`},
+			},
+			"_cat/indices/infra-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[]`},
+				{StatusCode: http.StatusOK, Body: `[]`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.reconcileEmergencyRetention(now)).To(Succeed())
+		Expect(request.reconcileEmergencyRetention(now)).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(Equal([]api.EmergencyDeletionStatus{
+			{Index: "app-000001", DeletedAt: metav1.NewTime(now), DryRun: true},
+		}))
+		Expect(recorder.Events).To(Receive(Equal("Warning EmergencyIndexDeletionDryRun Would delete index app-000001 as a node uses 96% of its disk")))
+		Expect(recorder.Events).NotTo(Receive())
+		Expect(chatter.Requests["app-000001"]).To(BeEmpty())
+	})
+
+	It("should wait for the minimum interval between two deletions", func() {
+		cluster.Status.EmergencyRetention = &api.EmergencyRetentionStatus{
+			DeletedIndices: []api.EmergencyDeletionStatus{{Index: "app-000001", DeletedAt: metav1.NewTime(now)}},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {nodesStats(96)},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.reconcileEmergencyRetention(now.Add(time.Minute))).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(HaveLen(1))
+		Expect(cluster.Status.EmergencyRetention.Message).To(Equal(
+			"Waiting for the disk freed by the deletion of index app-000001 before deleting another index"))
+		Expect(chatter.Requests[shardsURI]).To(BeEmpty())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should only delete indices with shards on the nodes above the critical threshold", func() {
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {{StatusCode: http.StatusOK, Body: `{"nodes": {
+				"abc": {"name": "elasticsearch-cdm-abc-1", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 4}}},
+				"def": {"name": "elasticsearch-cdm-abc-2", "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 50}}}
+			}}`}},
+			"_alias": {aliases},
+			shardsURI: {{StatusCode: http.StatusOK, Body: `[
+				{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-2"},
+				{"index": "infra-000001", "shard": "0", "prirep": "r", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"}
+			]`}},
+			"_cat/indices/app-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[{"index": "app-000001", "status": "open", "creation.date": "1000"}]`},
+			},
+			"_cat/indices/infra-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[{"index": "infra-000001", "status": "open", "creation.date": "1500"}]`},
+			},
+			"infra-000001": {
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.reconcileEmergencyRetention(now)).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(Equal([]api.EmergencyDeletionStatus{
+			{Index: "infra-000001", DeletedAt: metav1.NewTime(now)},
+		}))
+		Expect(chatter.Requests["app-000001"]).To(BeEmpty())
+	})
+
+	It("should not delete any index without shards on the nodes above the critical threshold", func() {
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/stats/fs": {nodesStats(96)},
+			"_alias":          {aliases},
+			shardsURI: {{StatusCode: http.StatusOK, Body: `[
+				{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-2"}
+			]`}},
+			"_cat/indices/app-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[{"index": "app-000001", "status": "open", "creation.date": "1000"}]`},
+			},
+			"_cat/indices/infra-*?h=index,status,creation.date&format=json": {
+				{StatusCode: http.StatusOK, Body: `[]`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.reconcileEmergencyRetention(now)).To(Succeed())
+		Expect(cluster.Status.EmergencyRetention.DeletedIndices).To(BeEmpty())
+		Expect(cluster.Status.EmergencyRetention.Message).To(HavePrefix("No index matching the patterns"))
+		Expect(chatter.Requests["app-000001"]).To(BeEmpty())
+	})
+})
@@ -0,0 +1,54 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// usesEphemeralStorage returns true if the pods of the node keep their data in an emptyDir volume
+// instead of a claim
+func usesEphemeralStorage(node api.ElasticsearchNode) bool {
+	return node.Storage.EmptyDir != nil || node.Storage.Size == nil
+}
+
+// ephemeralNodes returns true if all nodes lose their data when they are restarted
+func (er *ElasticsearchRequest) ephemeralNodes(nodes []NodeTypeInterface) bool {
+	cluster := er.cluster
+	names := sets.NewString()
+	for _, node := range cluster.Spec.Nodes {
+		if node.GenUUID == nil || !usesEphemeralStorage(node) {
+			continue
+		}
+		nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+		if isStatefulSetNode(node) {
+			for _, shard := range statefulSetShards(nodeName, node) {
+				names.Insert(shard.name)
+			}
+			continue
+		}
+		for replicaIndex := int32(1); replicaIndex <= node.NodeCount; replicaIndex++ {
+			names.Insert(addDataNodeSuffix(nodeName, replicaIndex))
+		}
+	}
+
+	if len(nodes) == 0 {
+		return false
+	}
+	for _, node := range nodes {
+		if !names.Has(node.name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// relaxForEphemeralStorage neither waits for the health of the cluster nor requires flushing the
+// shards when the restarted nodes lose their data anyway, so a development cluster which lost its
+// data does not block its own restarts
+func (r *Restarter) relaxForEphemeralStorage(cr ClusterRestart) {
+	r.precheck = cr.restartNoop
+	r.prep = cr.optionalSetPrimariesShardsAndFlush
+	r.recovery = cr.restartNoop
+}
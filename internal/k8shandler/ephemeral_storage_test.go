@@ -0,0 +1,73 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Ephemeral storage", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		size    = resource.MustParse("10Gi")
+	)
+
+	newNode := func(name string) NodeTypeInterface {
+		return &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+
+	BeforeEach(func() {
+		master, data := "abc", "def"
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+						GenUUID:   &master,
+						Storage:   api.ElasticsearchStorageSpec{Size: &size},
+					},
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+						NodeCount: 2,
+						GenUUID:   &data,
+						Storage: api.ElasticsearchStorageSpec{
+							Size:     &size,
+							EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should use an emptyDir volume instead of a claim", func() {
+		node := cluster.Spec.Nodes[1]
+		Expect(usesEphemeralStorage(node)).To(BeTrue())
+		Expect(usesEphemeralStorage(cluster.Spec.Nodes[0])).To(BeFalse())
+		Expect(usesEphemeralStorage(api.ElasticsearchNode{})).To(BeTrue())
+
+		source := newVolumeSource(cluster.Name, "elasticsearch-d-def-1", cluster.Namespace, node, nil)
+		Expect(source.PersistentVolumeClaim).To(BeNil())
+		Expect(source.EmptyDir).To(Equal(&v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}))
+
+		node.Workload = api.ElasticsearchNodeWorkloadStatefulSet
+		Expect(newStorageClaimTemplates(cluster.Name, node)).To(BeEmpty())
+	})
+
+	It("should relax the restart checks only if all restarted nodes are ephemeral", func() {
+		er := &ElasticsearchRequest{cluster: cluster}
+
+		Expect(er.ephemeralNodes([]NodeTypeInterface{newNode("elasticsearch-d-def-1"), newNode("elasticsearch-d-def-2")})).To(BeTrue())
+		Expect(er.ephemeralNodes([]NodeTypeInterface{newNode("elasticsearch-d-def-1"), newNode("elasticsearch-m-abc-1")})).To(BeFalse())
+		Expect(er.ephemeralNodes(nil)).To(BeFalse())
+	})
+})
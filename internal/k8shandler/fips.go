@@ -0,0 +1,53 @@
+package k8shandler
+
+import (
+	"path"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// fipsProtocols are the TLS protocols of the nodes in FIPS mode
+	fipsProtocols = "[TLSv1.2]"
+	// fipsCiphers are the FIPS-approved cipher suites of the nodes in FIPS mode. The clients of
+	// the operator use the same suites
+	fipsCiphers = "[TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384]"
+)
+
+// remoteCABundleScript appends the CA certificates of the remote clusters to a copy of the CA of
+// the cluster. It replaces the import into the JKS truststore in FIPS mode, since the keystores
+// of a FIPS enabled JVM cannot be of type JKS
+const remoteCABundleScript = `
+cp /etc/openshift/elasticsearch/secret/admin-ca /etc/elasticsearch/remote-truststore/admin-ca
+for ca in /etc/elasticsearch/remote-ca/*.crt ; do
+  [ -e "$ca" ] || continue
+  { echo; cat "$ca"; } >> /etc/elasticsearch/remote-truststore/admin-ca
+done
+`
+
+// fipsModeEnabled returns true if the TLS of the cluster is restricted to FIPS-approved cryptography
+func fipsModeEnabled(cluster *api.Elasticsearch) bool {
+	return cluster.Spec.Security != nil && cluster.Spec.Security.FIPSMode
+}
+
+// transportTrustedCAs returns the PEM bundle of the CAs verifying the transport certificates of the
+// nodes in FIPS mode. It is next to the transport truststore used otherwise
+func transportTrustedCAs(transportTruststore string) string {
+	return path.Join(path.Dir(transportTruststore), clusterCASecretKey)
+}
+
+// applyFIPSMode bundles the CA certificates of the remote clusters in PEM format instead of
+// importing them into the JKS truststore if FIPS mode is enabled
+func applyFIPSMode(cluster *api.Elasticsearch, spec *v1.PodSpec) {
+	if !fipsModeEnabled(cluster) {
+		return
+	}
+
+	for i := range spec.InitContainers {
+		container := &spec.InitContainers[i]
+		if container.Name == remoteCAImportName {
+			container.Command = []string{"/bin/bash", "-c", remoteCABundleScript}
+		}
+	}
+}
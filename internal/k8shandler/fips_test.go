@@ -0,0 +1,65 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("FIPS mode", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+				RemoteClusters: []api.RemoteClusterSpec{
+					{Name: "other", Elasticsearch: "other"},
+				},
+			},
+		}
+	})
+
+	remoteCAImportCommand := func() []string {
+		spec := newPodTemplateSpec("node", cluster.Spec.Nodes[0], map[string]string{}, getNodeRoleMap(cluster.Spec.Nodes[0]), nil, podTemplateOptions{
+			clusterName: cluster.Name,
+			namespace:   cluster.Namespace,
+			commonSpec:  cluster.Spec.Spec,
+			remoteTrust: true,
+		}).Spec
+		applyFIPSMode(cluster, &spec)
+		for _, container := range spec.InitContainers {
+			if container.Name == remoteCAImportName {
+				return container.Command
+			}
+		}
+		return nil
+	}
+
+	It("should import the remote CAs into the JKS truststore by default", func() {
+		Expect(fipsModeEnabled(cluster)).To(BeFalse())
+		Expect(remoteCAImportCommand()).To(ContainElement(remoteCAImportScript))
+	})
+
+	It("should bundle the remote CAs in PEM format in FIPS mode", func() {
+		cluster.Spec.Security = &api.SecuritySpec{FIPSMode: true}
+
+		Expect(remoteCAImportCommand()).To(ContainElement(remoteCABundleScript))
+		Expect(transportTrustedCAs(transportTruststore(cluster))).To(Equal("/etc/elasticsearch/remote-truststore/admin-ca"))
+	})
+
+	It("should trust the CA of the cluster without remote clusters", func() {
+		cluster.Spec.RemoteClusters = nil
+		Expect(transportTrustedCAs(transportTruststore(cluster))).To(Equal("/etc/elasticsearch/secret/admin-ca"))
+	})
+})
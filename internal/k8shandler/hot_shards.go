@@ -0,0 +1,265 @@
+package k8shandler
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultHotShardThreshold = 50
+	defaultHotShardInterval  = 5 * time.Minute
+	// minHotShardNodeOperations keeps idle nodes from reporting hot shards
+	minHotShardNodeOperations = 1000
+	maxReportedHotShards      = 10
+)
+
+var (
+	// shardSamples holds the last sample of the shard operations per cluster. The first check after
+	// a restart of the operator only takes a sample
+	shardSamples      = map[string]*shardSample{}
+	shardSamplesMutex sync.Mutex
+)
+
+type shardSample struct {
+	taken      time.Time
+	operations map[shardCopy]shardOperations
+}
+
+type shardCopy struct {
+	index   string
+	shard   int32
+	primary bool
+	node    string
+}
+
+type shardOperations struct {
+	indexing int64
+	search   int64
+}
+
+// CheckHotShards samples the operations of the shards and reports the shards serving most of the
+// operations of their node since the previous sample
+func (er *ElasticsearchRequest) CheckHotShards() error {
+	cluster := er.cluster
+	spec := cluster.Spec.HotShardDetection
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+
+	if spec == nil {
+		forgetShardSample(cluster.Name, cluster.Namespace)
+		return er.updateHotShardDetectionStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	interval := defaultHotShardInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+	threshold := int32(defaultHotShardThreshold)
+	if spec.Threshold != nil {
+		threshold = *spec.Threshold
+	}
+
+	shardSamplesMutex.Lock()
+	previous := shardSamples[key]
+	shardSamplesMutex.Unlock()
+
+	now := time.Now()
+	if previous != nil && now.Sub(previous.taken) < interval {
+		return nil
+	}
+
+	stats, err := er.esClient.GetShardStats()
+	if err != nil {
+		return err
+	}
+	current := &shardSample{taken: now, operations: newShardOperations(stats)}
+
+	shardSamplesMutex.Lock()
+	shardSamples[key] = current
+	shardSamplesMutex.Unlock()
+
+	if previous == nil {
+		return nil
+	}
+
+	writeIndices, err := er.esClient.ListWriteIndices()
+	if err != nil {
+		return err
+	}
+
+	hotShards := findHotShards(previous.operations, current.operations, threshold, writeIndices, primaryShardCounts(stats), shardNodeCount(stats))
+	if len(hotShards) > 0 {
+		er.L().Info("Hot shards detected", "shards", hotShards)
+	}
+	metrics.SetHotShards(cluster.Name, cluster.Namespace, hotShards)
+
+	sampled := metav1.NewTime(now)
+	return er.updateHotShardDetectionStatus(&api.HotShardDetectionStatus{
+		LastSample: &sampled,
+		Shards:     hotShards,
+	})
+}
+
+// forgetShardSample drops the last sample and the hot shard metrics of a cluster
+func forgetShardSample(clusterName, namespace string) {
+	shardSamplesMutex.Lock()
+	delete(shardSamples, nodeMapKey(clusterName, namespace))
+	shardSamplesMutex.Unlock()
+	metrics.SetHotShards(clusterName, namespace, nil)
+}
+
+// newShardOperations returns the operation counters of the started shard copies
+func newShardOperations(stats estypes.CatShardsResponses) map[shardCopy]shardOperations {
+	operations := map[shardCopy]shardOperations{}
+	for _, stat := range stats {
+		if stat.State != "STARTED" || stat.Node == "" {
+			continue
+		}
+		shard, err := strconv.ParseInt(stat.Shard, 10, 32)
+		if err != nil {
+			continue
+		}
+		indexing, _ := strconv.ParseInt(stat.IndexingTotal, 10, 64)
+		search, _ := strconv.ParseInt(stat.SearchTotal, 10, 64)
+
+		operations[shardCopy{
+			index:   stat.Index,
+			shard:   int32(shard),
+			primary: stat.PriRep == "p",
+			node:    stat.Node,
+		}] = shardOperations{indexing: indexing, search: search}
+	}
+	return operations
+}
+
+func primaryShardCounts(stats estypes.CatShardsResponses) map[string]int32 {
+	counts := map[string]int32{}
+	for _, stat := range stats {
+		if stat.PriRep == "p" {
+			counts[stat.Index]++
+		}
+	}
+	return counts
+}
+
+func shardNodeCount(stats estypes.CatShardsResponses) int32 {
+	nodes := sets.NewString()
+	for _, stat := range stats {
+		if stat.Node != "" {
+			nodes.Insert(stat.Node)
+		}
+	}
+	return int32(nodes.Len())
+}
+
+// findHotShards returns the shard copies serving more than the threshold percentage of the operations
+// of their node between the samples, ordered by their load
+func findHotShards(previous, current map[shardCopy]shardOperations, threshold int32, writeIndices sets.String, primaries map[string]int32, dataNodes int32) []api.HotShard {
+	deltas := map[shardCopy]shardOperations{}
+	nodeTotals := map[string]int64{}
+	nodeShards := map[string]int{}
+	for key, ops := range current {
+		// the counters start over when a shard copy is relocated to the node
+		before, ok := previous[key]
+		if !ok || ops.indexing < before.indexing || ops.search < before.search {
+			continue
+		}
+		delta := shardOperations{
+			indexing: ops.indexing - before.indexing,
+			search:   ops.search - before.search,
+		}
+		deltas[key] = delta
+		nodeTotals[key.node] += delta.indexing + delta.search
+		nodeShards[key.node]++
+	}
+
+	hotShards := []api.HotShard{}
+	for key, delta := range deltas {
+		total := nodeTotals[key.node]
+		if total < minHotShardNodeOperations || nodeShards[key.node] < 2 {
+			continue
+		}
+		operations := delta.indexing + delta.search
+		if operations*100 <= int64(threshold)*total {
+			continue
+		}
+		hotShards = append(hotShards, api.HotShard{
+			Index:       key.index,
+			Shard:       key.shard,
+			Node:        key.node,
+			Primary:     key.primary,
+			LoadPercent: int32(operations * 100 / total),
+			Action:      hotShardAction(key.index, delta, writeIndices, primaries[key.index], dataNodes),
+		})
+	}
+
+	sort.Slice(hotShards, func(i, j int) bool {
+		if hotShards[i].LoadPercent != hotShards[j].LoadPercent {
+			return hotShards[i].LoadPercent > hotShards[j].LoadPercent
+		}
+		if hotShards[i].Index != hotShards[j].Index {
+			return hotShards[i].Index < hotShards[j].Index
+		}
+		if hotShards[i].Shard != hotShards[j].Shard {
+			return hotShards[i].Shard < hotShards[j].Shard
+		}
+		return hotShards[i].Node < hotShards[j].Node
+	})
+	if len(hotShards) > maxReportedHotShards {
+		hotShards = hotShards[:maxReportedHotShards]
+	}
+	if len(hotShards) == 0 {
+		return nil
+	}
+	return hotShards
+}
+
+// hotShardAction suggests spreading the writes of a write index over more primary shards or
+// rolling it over sooner and moving the shards serving mostly searches to another node
+func hotShardAction(index string, delta shardOperations, writeIndices sets.String, primaries, dataNodes int32) api.HotShardAction {
+	if delta.indexing < delta.search || !writeIndices.Has(index) {
+		return api.HotShardActionReroute
+	}
+	if primaries < dataNodes {
+		return api.HotShardActionIncreasePrimaryShards
+	}
+	return api.HotShardActionRolloverSooner
+}
+
+func (er *ElasticsearchRequest) updateHotShardDetectionStatus(status *api.HotShardDetectionStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.HotShardDetection, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.HotShardDetection, status) {
+			return nil
+		}
+
+		cluster.Status.HotShardDetection = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update hot shard detection status")
+}
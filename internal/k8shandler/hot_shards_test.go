@@ -0,0 +1,77 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var _ = Describe("Hot shards", func() {
+	defer GinkgoRecover()
+
+	var (
+		previous map[shardCopy]shardOperations
+		current  map[shardCopy]shardOperations
+	)
+
+	BeforeEach(func() {
+		previous = map[shardCopy]shardOperations{
+			{index: "app-000002", shard: 0, primary: true, node: "node-a"}:   {indexing: 1000, search: 0},
+			{index: "infra-000001", shard: 0, primary: true, node: "node-a"}: {indexing: 0, search: 100},
+			{index: "app-000001", shard: 0, primary: true, node: "node-b"}:   {indexing: 500, search: 500},
+			{index: "audit-000001", shard: 0, primary: true, node: "node-b"}: {indexing: 500, search: 500},
+		}
+		current = map[shardCopy]shardOperations{
+			{index: "app-000002", shard: 0, primary: true, node: "node-a"}:   {indexing: 10000, search: 0},
+			{index: "infra-000001", shard: 0, primary: true, node: "node-a"}: {indexing: 0, search: 1100},
+			{index: "app-000001", shard: 0, primary: true, node: "node-b"}:   {indexing: 1000, search: 1000},
+			{index: "audit-000001", shard: 0, primary: true, node: "node-b"}: {indexing: 1000, search: 1000},
+		}
+	})
+
+	It("should report the shards dominating the operations of their node", func() {
+		hotShards := findHotShards(previous, current, 50, sets.NewString("app-000002"), map[string]int32{"app-000002": 1}, 2)
+		Expect(hotShards).To(Equal([]api.HotShard{
+			{
+				Index:       "app-000002",
+				Shard:       0,
+				Node:        "node-a",
+				Primary:     true,
+				LoadPercent: 90,
+				Action:      api.HotShardActionIncreasePrimaryShards,
+			},
+		}))
+	})
+
+	It("should suggest rolling over a write index spread over all nodes sooner", func() {
+		hotShards := findHotShards(previous, current, 50, sets.NewString("app-000002"), map[string]int32{"app-000002": 2}, 2)
+		Expect(hotShards).To(HaveLen(1))
+		Expect(hotShards[0].Action).To(Equal(api.HotShardActionRolloverSooner))
+	})
+
+	It("should suggest moving a shard serving mostly searches", func() {
+		current[shardCopy{index: "infra-000001", shard: 0, primary: true, node: "node-a"}] = shardOperations{search: 100000}
+		hotShards := findHotShards(previous, current, 50, sets.NewString("app-000002"), map[string]int32{"app-000002": 1}, 2)
+		Expect(hotShards).To(HaveLen(1))
+		Expect(hotShards[0].Index).To(Equal("infra-000001"))
+		Expect(hotShards[0].Action).To(Equal(api.HotShardActionReroute))
+	})
+
+	It("should ignore relocated shards and idle nodes", func() {
+		delete(previous, shardCopy{index: "app-000002", shard: 0, primary: true, node: "node-a"})
+		Expect(findHotShards(previous, current, 50, sets.NewString(), nil, 2)).To(BeNil())
+	})
+
+	It("should only sample the started shard copies", func() {
+		operations := newShardOperations(estypes.CatShardsResponses{
+			{Index: "app-000001", Shard: "0", PriRep: "p", State: "STARTED", Node: "node-a", IndexingTotal: "10", SearchTotal: "5"},
+			{Index: "app-000001", Shard: "0", PriRep: "r", State: "UNASSIGNED"},
+		})
+		Expect(operations).To(Equal(map[shardCopy]shardOperations{
+			{index: "app-000001", shard: 0, primary: true, node: "node-a"}: {indexing: 10, search: 5},
+		}))
+	})
+})
@@ -0,0 +1,72 @@
+package k8shandler
+
+import (
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// the Elasticsearch defaults of the caches, -1 leaves the fielddata cache unbounded
+const (
+	defaultFielddataCacheSize = "-1"
+	defaultQueryCacheSize     = "10%"
+	defaultRequestCacheSize   = "1%"
+)
+
+// usesCacheLimits returns true if the node spec or a node limits the caches
+func usesCacheLimits(cluster *api.Elasticsearch) bool {
+	if cluster.Spec.Spec.Caches != nil {
+		return true
+	}
+	for _, node := range cluster.Spec.Nodes {
+		if node.Caches != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeCacheLimits returns the cache sizes of the node overriding the ones of the node spec, or nil
+// if the cluster keeps the defaults of all caches
+func nodeCacheLimits(cluster *api.Elasticsearch, node api.ElasticsearchNode) *api.ElasticsearchCacheSpec {
+	if !usesCacheLimits(cluster) {
+		return nil
+	}
+
+	limits := &api.ElasticsearchCacheSpec{
+		FielddataSize: defaultFielddataCacheSize,
+		QuerySize:     defaultQueryCacheSize,
+		RequestSize:   defaultRequestCacheSize,
+	}
+	for _, spec := range []*api.ElasticsearchCacheSpec{cluster.Spec.Spec.Caches, node.Caches} {
+		if spec == nil {
+			continue
+		}
+		if spec.FielddataSize != "" {
+			limits.FielddataSize = spec.FielddataSize
+		}
+		if spec.QuerySize != "" {
+			limits.QuerySize = spec.QuerySize
+		}
+		if spec.RequestSize != "" {
+			limits.RequestSize = spec.RequestSize
+		}
+	}
+	return limits
+}
+
+func newCacheLimitEnvVars(limits *api.ElasticsearchCacheSpec) []v1.EnvVar {
+	return []v1.EnvVar{
+		{
+			Name:  "FIELDDATA_CACHE_SIZE",
+			Value: limits.FielddataSize,
+		},
+		{
+			Name:  "QUERY_CACHE_SIZE",
+			Value: limits.QuerySize,
+		},
+		{
+			Name:  "REQUEST_CACHE_SIZE",
+			Value: limits.RequestSize,
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Cache limits", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	It("should keep the defaults when no limits are set", func() {
+		Expect(usesCacheLimits(cluster)).To(BeFalse())
+		Expect(nodeCacheLimits(cluster, cluster.Spec.Nodes[0])).To(BeNil())
+	})
+
+	It("should override the limits of the node spec with the ones of the node", func() {
+		cluster.Spec.Spec.Caches = &api.ElasticsearchCacheSpec{FielddataSize: "20%", QuerySize: "5%"}
+		cluster.Spec.Nodes[1].Caches = &api.ElasticsearchCacheSpec{FielddataSize: "2gb"}
+
+		Expect(nodeCacheLimits(cluster, cluster.Spec.Nodes[0])).To(Equal(&api.ElasticsearchCacheSpec{
+			FielddataSize: "20%",
+			QuerySize:     "5%",
+			RequestSize:   "1%",
+		}))
+		Expect(nodeCacheLimits(cluster, cluster.Spec.Nodes[1])).To(Equal(&api.ElasticsearchCacheSpec{
+			FielddataSize: "2gb",
+			QuerySize:     "5%",
+			RequestSize:   "1%",
+		}))
+	})
+
+	It("should render the defaults for the nodes without limits", func() {
+		cluster.Spec.Nodes[1].Caches = &api.ElasticsearchCacheSpec{FielddataSize: "30%"}
+
+		envVars := newEnvVars("node", "", map[api.ElasticsearchNodeRole]bool{}, podTemplateOptions{
+			clusterName: "elasticsearch",
+			caches:      nodeCacheLimits(cluster, cluster.Spec.Nodes[0]),
+		})
+		helpers.ExpectEnvVars(envVars).ToIncludeName("FIELDDATA_CACHE_SIZE").WithValue("-1")
+		helpers.ExpectEnvVars(envVars).ToIncludeName("QUERY_CACHE_SIZE").WithValue("10%")
+		helpers.ExpectEnvVars(envVars).ToIncludeName("REQUEST_CACHE_SIZE").WithValue("1%")
+	})
+})
@@ -0,0 +1,289 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	indexLifecyclePolicyFinalizer = "logging.openshift.io/index-lifecycle-policy"
+	// indexLifecyclePolicyDeletionTimeout bounds the time the deletion of a policy is retried
+	// before the resource is released and the policy left in the cluster
+	indexLifecyclePolicyDeletionTimeout = 10 * time.Minute
+)
+
+// ReconcileIndexLifecyclePolicy creates or updates the lifecycle policy in the referenced
+// Elasticsearch cluster and attaches it to the requested index templates
+func ReconcileIndexLifecyclePolicy(policy *api.ElasticsearchIndexLifecyclePolicy, requestClient client.Client, recorder record.EventRecorder) error {
+	er, err := newElasticsearchRequestFor(policy.Spec.ElasticsearchName, policy.Namespace, requestClient)
+	if err != nil {
+		return err
+	}
+
+	if policy.GetDeletionTimestamp() != nil {
+		// the policy goes away with a cluster which is gone or shutting down, which may never
+		// answer the delete request
+		if er == nil || er.cluster.GetDeletionTimestamp() != nil {
+			return updateIndexLifecyclePolicyFinalizer(policy, requestClient, false)
+		}
+		er.recorder = recorder
+		return er.deleteIndexLifecyclePolicy(policy, time.Now())
+	}
+
+	if err := updateIndexLifecyclePolicyFinalizer(policy, requestClient, true); err != nil {
+		return err
+	}
+
+	status := api.ElasticsearchIndexLifecyclePolicyStatus{
+		ObservedGeneration: policy.Generation,
+	}
+
+	if er == nil {
+		status.State = api.IndexLifecyclePolicyStatePending
+		status.Reason = api.IndexLifecyclePolicyReasonClusterNotFound
+		status.Message = "Elasticsearch cluster not found"
+		return updateIndexLifecyclePolicyStatus(policy, status, requestClient)
+	}
+
+	if !er.AnyNodeReady() {
+		status.State = api.IndexLifecyclePolicyStatePending
+		status.Reason = api.IndexLifecyclePolicyReasonClusterUnavailable
+		status.Message = "Waiting for an Elasticsearch node to be ready"
+		return updateIndexLifecyclePolicyStatus(policy, status, requestClient)
+	}
+
+	ll := er.L().WithValues("policy", policy.Name)
+	name := policy.GetPolicyName()
+	if err := er.esClient.CreateOrUpdateLifecyclePolicy(name, newLifecyclePolicy(policy.Spec.Phases)); err != nil {
+		ll.Error(err, "failed to apply lifecycle policy")
+		status.State = api.IndexLifecyclePolicyStateFailed
+		status.Reason = api.IndexLifecyclePolicyReasonRejected
+		status.Message = elasticsearchErrorReason(err)
+		return updateIndexLifecyclePolicyStatus(policy, status, requestClient)
+	}
+
+	status.State = api.IndexLifecyclePolicyStateApplied
+	status.Reason = api.IndexLifecyclePolicyReasonApplied
+
+	for _, mapping := range policy.Spec.Mappings {
+		template := formatTemplateName(mapping)
+		alias := formatWriteAlias(api.IndexManagementPolicyMappingSpec{Name: mapping})
+		if err := er.esClient.SetTemplateLifecyclePolicy(template, name, alias); err != nil {
+			ll.Error(err, "failed to attach lifecycle policy to index template", "template", template)
+			status.State = api.IndexLifecyclePolicyStateFailed
+			status.Reason = api.IndexLifecyclePolicyReasonTemplateFailed
+			status.Message = elasticsearchErrorReason(err)
+			continue
+		}
+		status.Templates = append(status.Templates, template)
+	}
+
+	return updateIndexLifecyclePolicyStatus(policy, status, requestClient)
+}
+
+// deleteIndexLifecyclePolicy deletes the policy from the cluster before releasing the resource. The
+// resource is released without deleting the policy if no node is ready or the deletion keeps
+// failing, so deleting the namespace is not blocked by a broken cluster. A warning event names the
+// policy left in the cluster
+func (er *ElasticsearchRequest) deleteIndexLifecyclePolicy(policy *api.ElasticsearchIndexLifecyclePolicy, now time.Time) error {
+	name := policy.GetPolicyName()
+
+	if !er.AnyNodeReady() {
+		er.recordOrphanedIndexLifecyclePolicy(policy, "no Elasticsearch node is ready")
+		return updateIndexLifecyclePolicyFinalizer(policy, er.client, false)
+	}
+
+	if err := er.esClient.DeleteLifecyclePolicy(name); err != nil {
+		if now.Before(policy.GetDeletionTimestamp().Add(indexLifecyclePolicyDeletionTimeout)) {
+			return err
+		}
+		er.L().Error(err, "failed to delete lifecycle policy, releasing it", "policy", policy.Name)
+		er.recordOrphanedIndexLifecyclePolicy(policy, elasticsearchErrorReason(err))
+	}
+	return updateIndexLifecyclePolicyFinalizer(policy, er.client, false)
+}
+
+func (er *ElasticsearchRequest) recordOrphanedIndexLifecyclePolicy(policy *api.ElasticsearchIndexLifecyclePolicy, reason string) {
+	if er.recorder == nil {
+		return
+	}
+	message := fmt.Sprintf("Lifecycle policy %q was left in Elasticsearch cluster %q: %s",
+		policy.GetPolicyName(), er.cluster.Name, reason)
+	er.recorder.Event(policy, v1.EventTypeWarning, "PolicyOrphaned", message)
+}
+
+func newLifecyclePolicy(spec api.IndexLifecyclePhasesSpec) *estypes.LifecyclePolicy {
+	phases := map[string]estypes.LifecyclePhase{}
+
+	for name, phase := range map[string]*api.IndexLifecyclePhaseSpec{
+		"hot":  spec.Hot,
+		"warm": spec.Warm,
+		"cold": spec.Cold,
+	} {
+		if phase == nil {
+			continue
+		}
+		phases[name] = estypes.LifecyclePhase{
+			MinAge:  string(phase.MinAge),
+			Actions: newLifecycleActions(phase.Actions),
+		}
+	}
+
+	if spec.Delete != nil {
+		phases["delete"] = estypes.LifecyclePhase{
+			MinAge: string(spec.Delete.MinAge),
+			Actions: map[string]interface{}{
+				"delete": map[string]interface{}{},
+			},
+		}
+	}
+
+	return &estypes.LifecyclePolicy{
+		Policy: estypes.LifecyclePolicyBody{
+			Phases: phases,
+		},
+	}
+}
+
+func newLifecycleActions(spec api.IndexLifecycleActionsSpec) map[string]interface{} {
+	actions := map[string]interface{}{}
+
+	if rollover := spec.Rollover; rollover != nil {
+		conditions := map[string]interface{}{}
+		if rollover.MaxAge != "" {
+			conditions["max_age"] = string(rollover.MaxAge)
+		}
+		if rollover.MaxSize != "" {
+			conditions["max_size"] = rollover.MaxSize
+		}
+		if rollover.MaxDocs > 0 {
+			conditions["max_docs"] = rollover.MaxDocs
+		}
+		actions["rollover"] = conditions
+	}
+
+	if spec.ForceMerge != nil {
+		actions["forcemerge"] = map[string]interface{}{
+			"max_num_segments": spec.ForceMerge.MaxNumSegments,
+		}
+	}
+
+	if spec.Shrink != nil {
+		actions["shrink"] = map[string]interface{}{
+			"number_of_shards": spec.Shrink.NumberOfShards,
+		}
+	}
+
+	if allocate := spec.Allocate; allocate != nil {
+		settings := map[string]interface{}{}
+		if allocate.NumberOfReplicas != nil {
+			settings["number_of_replicas"] = *allocate.NumberOfReplicas
+		}
+		require := map[string]string{}
+		for attribute, value := range allocate.Require {
+			require[attribute] = value
+		}
+		if allocate.Tier != "" {
+			require[dataTierAttribute] = string(allocate.Tier)
+		}
+		if len(require) > 0 {
+			settings["require"] = require
+		}
+		if len(allocate.Include) > 0 {
+			settings["include"] = allocate.Include
+		}
+		if len(allocate.Exclude) > 0 {
+			settings["exclude"] = allocate.Exclude
+		}
+		actions["allocate"] = settings
+	}
+
+	if spec.ReadOnly {
+		actions["readonly"] = map[string]interface{}{}
+	}
+
+	if spec.Priority != nil {
+		actions["set_priority"] = map[string]interface{}{
+			"priority": *spec.Priority,
+		}
+	}
+
+	return actions
+}
+
+// elasticsearchErrorReason returns the reason Elasticsearch gave for rejecting a request
+// falling back to the error message when none was provided
+func elasticsearchErrorReason(err error) string {
+	if reason, ok := kverrors.KVs(err)[elasticsearch.ErrorReasonKey].(string); ok && reason != "" {
+		return reason
+	}
+	return kverrors.Message(err)
+}
+
+func updateIndexLifecyclePolicyFinalizer(policy *api.ElasticsearchIndexLifecyclePolicy, requestClient client.Client, present bool) error {
+	if utils.ContainsString(policy.GetFinalizers(), indexLifecyclePolicyFinalizer) == present {
+		return nil
+	}
+
+	if present {
+		policy.SetFinalizers(append(policy.GetFinalizers(), indexLifecyclePolicyFinalizer))
+	} else {
+		policy.SetFinalizers(utils.RemoveString(policy.GetFinalizers(), indexLifecyclePolicyFinalizer))
+	}
+
+	if err := requestClient.Update(context.TODO(), policy); err != nil {
+		return kverrors.Wrap(err, "failed to update index lifecycle policy finalizers",
+			"policy", policy.Name,
+			"namespace", policy.Namespace)
+	}
+	return nil
+}
+
+func updateIndexLifecyclePolicyStatus(policy *api.ElasticsearchIndexLifecyclePolicy, status api.ElasticsearchIndexLifecyclePolicyStatus, requestClient client.Client) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &api.ElasticsearchIndexLifecyclePolicy{}
+		if err := requestClient.Get(context.TODO(), types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}, current); err != nil {
+			return err
+		}
+
+		if current.Status.State == status.State &&
+			current.Status.Reason == status.Reason &&
+			current.Status.Message == status.Message &&
+			current.Status.ObservedGeneration == status.ObservedGeneration &&
+			sliceEqualsString(current.Status.Templates, status.Templates) {
+			return nil
+		}
+
+		status.LastUpdated = metav1.Now()
+		current.Status = status
+		return requestClient.Status().Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(retryErr, "failed to update index lifecycle policy status",
+		"policy", policy.Name,
+		"namespace", policy.Namespace)
+}
+
+func sliceEqualsString(lhs, rhs []string) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if lhs[i] != rhs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Index Lifecycle Policy", func() {
+	defer GinkgoRecover()
+
+	Describe("#newLifecyclePolicy", func() {
+		It("should render every spec'd phase and its actions", func() {
+			replicas := int32(1)
+			policy := newLifecyclePolicy(api.IndexLifecyclePhasesSpec{
+				Hot: &api.IndexLifecyclePhaseSpec{
+					Actions: api.IndexLifecycleActionsSpec{
+						Rollover: &api.IndexLifecycleRolloverSpec{
+							MaxAge:  "1d",
+							MaxSize: "50gb",
+						},
+					},
+				},
+				Warm: &api.IndexLifecyclePhaseSpec{
+					MinAge: "7d",
+					Actions: api.IndexLifecycleActionsSpec{
+						ForceMerge: &api.IndexLifecycleForceMergeSpec{MaxNumSegments: 1},
+						Allocate: &api.IndexLifecycleAllocateSpec{
+							NumberOfReplicas: &replicas,
+							Require:          map[string]string{"data": "warm"},
+						},
+						ReadOnly: true,
+					},
+				},
+				Delete: &api.IndexLifecycleDeletePhaseSpec{
+					MinAge: "30d",
+				},
+			})
+
+			actual, _ := utils.ToJSON(policy)
+			helpers.ExpectJSON(actual).ToEqual(`{
+				"policy": {
+					"phases": {
+						"hot": {
+							"actions": {
+								"rollover": {"max_age": "1d", "max_size": "50gb"}
+							}
+						},
+						"warm": {
+							"min_age": "7d",
+							"actions": {
+								"forcemerge": {"max_num_segments": 1},
+								"allocate": {"number_of_replicas": 1, "require": {"data": "warm"}},
+								"readonly": {}
+							}
+						},
+						"delete": {
+							"min_age": "30d",
+							"actions": {"delete": {}}
+						}
+					}
+				}
+			}`)
+		})
+
+		It("should require the data tier of the allocate action", func() {
+			actions := newLifecycleActions(api.IndexLifecycleActionsSpec{
+				Allocate: &api.IndexLifecycleAllocateSpec{
+					Tier:    api.ElasticsearchDataTierCold,
+					Require: map[string]string{"box": "large"},
+				},
+			})
+
+			actual, _ := utils.ToJSON(actions)
+			helpers.ExpectJSON(actual).ToEqual(`{"allocate": {"require": {"box": "large", "data": "cold"}}}`)
+		})
+	})
+
+	Describe("#ReconcileIndexLifecyclePolicy", func() {
+		var (
+			policy *api.ElasticsearchIndexLifecyclePolicy
+			key    = types.NamespacedName{Name: "logs", Namespace: "openshift-logging"}
+		)
+
+		BeforeEach(func() {
+			now := metav1.Now()
+			policy = &api.ElasticsearchIndexLifecyclePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              key.Name,
+					Namespace:         key.Namespace,
+					DeletionTimestamp: &now,
+					Finalizers:        []string{indexLifecyclePolicyFinalizer},
+				},
+				Spec: api.ElasticsearchIndexLifecyclePolicySpec{
+					ElasticsearchName: "elasticsearch",
+				},
+			}
+		})
+
+		expectFinalizerRemoved := func(objs ...runtime.Object) {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+			client := fake.NewFakeClientWithScheme(s, append(objs, policy)...)
+
+			Expect(ReconcileIndexLifecyclePolicy(policy, client, nil)).To(Succeed())
+			current := &api.ElasticsearchIndexLifecyclePolicy{}
+			Expect(client.Get(context.TODO(), key, current)).To(Succeed())
+			Expect(current.GetFinalizers()).To(BeEmpty())
+		}
+
+		It("should release a deleted policy of a missing cluster", func() {
+			expectFinalizerRemoved()
+		})
+
+		It("should release a deleted policy of a cluster shutting down", func() {
+			now := metav1.Now()
+			expectFinalizerRemoved(&api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "elasticsearch",
+					Namespace:         key.Namespace,
+					DeletionTimestamp: &now,
+				},
+			})
+		})
+	})
+
+	Describe("#deleteIndexLifecyclePolicy", func() {
+		const policyURI = "_ilm/policy/logs"
+
+		var (
+			chatter  *helpers.FakeElasticsearchChatter
+			recorder *record.FakeRecorder
+			request  *ElasticsearchRequest
+			policy   *api.ElasticsearchIndexLifecyclePolicy
+			deleted  time.Time
+			key      = types.NamespacedName{Name: "logs", Namespace: "openshift-logging"}
+		)
+
+		newRequest := func(responses helpers.FakeElasticsearchResponses, podPhase corev1.PodPhase) {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+
+			deleted = time.Now()
+			policy = &api.ElasticsearchIndexLifecyclePolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              key.Name,
+					Namespace:         key.Namespace,
+					DeletionTimestamp: &metav1.Time{Time: deleted},
+					Finalizers:        []string{indexLifecyclePolicyFinalizer},
+				},
+				Spec: api.ElasticsearchIndexLifecyclePolicySpec{
+					ElasticsearchName: "elasticsearch",
+				},
+			}
+			cluster := &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: key.Namespace},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch-cdm-1",
+					Namespace: key.Namespace,
+					Labels: map[string]string{
+						"component":    "elasticsearch",
+						"cluster-name": "elasticsearch",
+						"es-node-data": "true",
+					},
+				},
+				Status: corev1.PodStatus{Phase: podPhase},
+			}
+			recorder = record.NewFakeRecorder(10)
+			request = &ElasticsearchRequest{
+				client:   fake.NewFakeClientWithScheme(s, cluster, pod, policy),
+				cluster:  cluster,
+				recorder: recorder,
+			}
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{policyURI: responses},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", key.Namespace, request.client, chatter)
+		}
+
+		getFinalizers := func() []string {
+			current := &api.ElasticsearchIndexLifecyclePolicy{}
+			Expect(request.client.Get(context.TODO(), key, current)).To(Succeed())
+			return current.GetFinalizers()
+		}
+
+		It("should delete the policy from the cluster before releasing the resource", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`}}, corev1.PodRunning)
+
+			Expect(request.deleteIndexLifecyclePolicy(policy, deleted)).To(Succeed())
+
+			req, found := chatter.GetRequest(policyURI)
+			Expect(found).To(BeTrue())
+			Expect(req.Method).To(Equal(http.MethodDelete))
+			Expect(getFinalizers()).To(BeEmpty())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should keep the resource while the deletion of the policy is retried", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusInternalServerError, Body: `{"error": "unavailable"}`}}, corev1.PodRunning)
+
+			Expect(request.deleteIndexLifecyclePolicy(policy, deleted.Add(time.Minute))).ToNot(Succeed())
+			Expect(getFinalizers()).To(ConsistOf(indexLifecyclePolicyFinalizer))
+		})
+
+		It("should release the resource once the deletion of the policy timed out", func() {
+			newRequest(helpers.FakeElasticsearchResponses{{StatusCode: http.StatusInternalServerError, Body: `{"error": "unavailable"}`}}, corev1.PodRunning)
+
+			Expect(request.deleteIndexLifecyclePolicy(policy, deleted.Add(indexLifecyclePolicyDeletionTimeout))).To(Succeed())
+			Expect(getFinalizers()).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("PolicyOrphaned")))
+		})
+
+		It("should release the resource without a ready node", func() {
+			newRequest(nil, corev1.PodPending)
+
+			Expect(request.deleteIndexLifecyclePolicy(policy, deleted)).To(Succeed())
+
+			_, found := chatter.GetRequest(policyURI)
+			Expect(found).To(BeFalse())
+			Expect(getFinalizers()).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("no Elasticsearch node is ready")))
+		})
+	})
+})
@@ -19,6 +19,10 @@ func (er *ElasticsearchRequest) CreateOrUpdateIndexManagement() error {
 		return nil
 	}
 	spec := indexmanagement.VerifyAndNormalize(cluster)
+	// only composable templates are declared
+	if spec == nil {
+		return nil
+	}
 	policies := spec.PolicyMap()
 	if er.AnyNodeReady() {
 		er.cullIndexManagement(spec.Mappings, policies)
@@ -0,0 +1,78 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// usesIngestNodes returns true if the cluster has nodes dedicated to the ingest pipelines
+func usesIngestNodes(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if isIngestNode(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesIngestRoles returns true if some nodes of the cluster no longer run the ingest pipelines
+func usesIngestRoles(cluster *api.Elasticsearch) bool {
+	return usesIngestNodes(cluster) || usesCoordinatingNodes(cluster)
+}
+
+// nodeIngest returns whether the node runs ingest pipelines or an empty string if all nodes
+// of the cluster keep the default. Once the cluster has ingest nodes only they run the pipelines,
+// otherwise every node but the coordinating ones does
+func nodeIngest(cluster *api.Elasticsearch, node api.ElasticsearchNode) string {
+	if !usesIngestRoles(cluster) {
+		return ""
+	}
+	if usesIngestNodes(cluster) {
+		return strconv.FormatBool(isIngestNode(node))
+	}
+	return strconv.FormatBool(!isCoordinatingNode(node))
+}
+
+func ingestServiceName(cluster *api.Elasticsearch) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, "ingest")
+}
+
+// createOrUpdateIngestService ensures the indexing traffic can be sent to the ingest nodes
+// only and removes the service once the cluster has none
+func (er *ElasticsearchRequest) createOrUpdateIngestService() error {
+	cluster := er.cluster
+	serviceName := ingestServiceName(cluster)
+
+	if !usesIngestNodes(cluster) {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      serviceName,
+				Namespace: cluster.Namespace,
+			},
+		}
+		if err := er.client.Delete(context.TODO(), service); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete service",
+				"service_name", serviceName)
+		}
+		return nil
+	}
+
+	return er.createOrUpdateService(
+		serviceName,
+		cluster.Namespace,
+		cluster.Name,
+		"restapi",
+		9200,
+		selectorForES("es-node-ingest", cluster.Name),
+		map[string]string{},
+		false,
+		map[string]string{},
+	)
+}
@@ -0,0 +1,82 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ingest nodes", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		uuid    = "abc"
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleIngest}, NodeCount: 2, GenUUID: &uuid},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+	})
+
+	It("should run the ingest pipelines on the ingest nodes only", func() {
+		Expect(nodeIngest(cluster, cluster.Spec.Nodes[0])).To(Equal("false"))
+		Expect(nodeIngest(cluster, cluster.Spec.Nodes[1])).To(Equal("true"))
+		Expect(isCoordinatingNode(cluster.Spec.Nodes[1])).To(BeFalse())
+	})
+
+	It("should label and name the ingest nodes after their role", func() {
+		roleMap := getNodeRoleMap(cluster.Spec.Nodes[1])
+		Expect(newLabels("elasticsearch", "node", roleMap)).To(HaveKeyWithValue("es-node-ingest", "true"))
+		Expect(newLabels("elasticsearch", "node", getNodeRoleMap(cluster.Spec.Nodes[0]))).NotTo(HaveKey("es-node-ingest"))
+
+		nodes, err := request.GetNodeTypeInterface(uuid, cluster.Spec.Nodes[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodes).To(HaveLen(2))
+		Expect(nodes[0]).To(BeAssignableToTypeOf(&deploymentNode{}))
+		Expect(nodes[0].name()).To(Equal("elasticsearch-ci-abc-1"))
+	})
+
+	It("should send the indexing traffic of the service to the ingest nodes", func() {
+		Expect(request.createOrUpdateIngestService()).To(Succeed())
+
+		service := &v1.Service{}
+		key := types.NamespacedName{Name: "elasticsearch-ingest", Namespace: "openshift-logging"}
+		Expect(request.client.Get(context.TODO(), key, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			"cluster-name":   "elasticsearch",
+			"es-node-ingest": "true",
+		}))
+
+		cluster.Spec.Nodes = cluster.Spec.Nodes[:1]
+		Expect(request.createOrUpdateIngestService()).To(Succeed())
+		err := request.client.Get(context.TODO(), key, service)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
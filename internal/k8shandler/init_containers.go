@@ -0,0 +1,116 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	hostTuningName = "host-tuning"
+	// minMaxMapCount is the vm.max_map_count required by the bootstrap checks of Elasticsearch
+	minMaxMapCount = 262144
+	// minFileMax is the number of file handles required by the bootstrap checks of Elasticsearch
+	minFileMax = 65536
+)
+
+// hostTuningScript raises the kernel settings of the node below the minimums of Elasticsearch and
+// never lowers settings tuned by the administrators of the node
+const hostTuningScript = `
+raise() {
+  current=$(cat "/proc/sys/$1")
+  if [ "$current" -lt "$2" ] ; then
+    echo "Raising $1 of this node from $current to $2"
+    echo "$2" > "/proc/sys/$1" || exit 1
+  fi
+}
+raise vm/max_map_count "$MIN_MAX_MAP_COUNT"
+raise fs/file-max "$MIN_FILE_MAX"
+`
+
+// managedInitContainerNames are the names of the init containers of the nodes managed by the operator
+var managedInitContainerNames = []string{clockCheckName, remoteCAImportName, zoneDiscoveryName, hostTuningName, keystoreBuildName}
+
+// initContainersViolation returns the reason the init containers of a node are invalid or an empty
+// string. The names of the init containers must differ from each other and from the init containers
+// of the operator
+func initContainersViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		names := map[string]bool{}
+		for _, name := range managedInitContainerNames {
+			names[name] = true
+		}
+
+		for _, container := range node.InitContainers {
+			if names[container.Name] {
+				return fmt.Sprintf("The init container %q of the nodes with the roles %v must be named differently from the other init containers of the pods",
+					container.Name, node.Roles)
+			}
+			names[container.Name] = true
+		}
+	}
+	return ""
+}
+
+// hostTuningViolation returns the reason the pod security admission level of the namespace
+// rejects the privileged init container tuning the nodes or an empty string if it is allowed
+func (er *ElasticsearchRequest) hostTuningViolation() (string, error) {
+	cluster := er.cluster
+
+	if !cluster.Spec.HostTuning {
+		return "", nil
+	}
+
+	namespace := &v1.Namespace{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Namespace}, namespace); err != nil {
+		return "", kverrors.Wrap(err, "failed to get namespace",
+			"namespace", cluster.Namespace)
+	}
+
+	// only the privileged level allows privileged containers
+	switch level := namespace.GetLabels()[podSecurityEnforceLabel]; level {
+	case "baseline", "restricted":
+		return fmt.Sprintf("Host tuning requires a privileged init container which the %s pod security level of namespace %s forbids",
+			level, cluster.Namespace), nil
+	}
+	return "", nil
+}
+
+// newHostTuningContainer returns a privileged init container raising the kernel settings of the
+// node to the minimums of Elasticsearch
+func newHostTuningContainer(imageName string) v1.Container {
+	privileged := true
+	root := int64(0)
+
+	return v1.Container{
+		Name:            hostTuningName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", hostTuningScript},
+		Env: []v1.EnvVar{
+			{
+				Name:  "MIN_MAX_MAP_COUNT",
+				Value: fmt.Sprint(minMaxMapCount),
+			},
+			{
+				Name:  "MIN_FILE_MAX",
+				Value: fmt.Sprint(minFileMax),
+			},
+		},
+		SecurityContext: &v1.SecurityContext{
+			Privileged: &privileged,
+			RunAsUser:  &root,
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+}
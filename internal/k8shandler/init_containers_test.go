@@ -0,0 +1,90 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Init containers", func() {
+	defer GinkgoRecover()
+
+	var node api.ElasticsearchNode
+
+	newTemplate := func(hostTuning bool) v1.PodTemplateSpec {
+		return newPodTemplateSpec("node", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			hostTuning:  hostTuning,
+		})
+	}
+
+	newRequest := func(level string) *ElasticsearchRequest {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec:       api.ElasticsearchSpec{HostTuning: true},
+		}
+		namespace := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "openshift-logging",
+				Labels: map[string]string{podSecurityEnforceLabel: level},
+			},
+		}
+		return &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, namespace),
+			cluster: cluster,
+		}
+	}
+
+	BeforeEach(func() {
+		node = api.ElasticsearchNode{
+			Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData},
+			NodeCount: 3,
+			InitContainers: []v1.Container{
+				{Name: "fetch-plugins", Image: "registry.example.com/plugins:1.0"},
+			},
+		}
+	})
+
+	It("should tune the host in a privileged init container before the init containers of the node", func() {
+		initContainers := newTemplate(true).Spec.InitContainers
+		Expect(initContainers).To(HaveLen(2))
+		Expect(initContainers[0].Name).To(Equal(hostTuningName))
+		Expect(*initContainers[0].SecurityContext.Privileged).To(BeTrue())
+		Expect(initContainers[1].Name).To(Equal("fetch-plugins"))
+
+		Expect(newTemplate(false).Spec.InitContainers).To(HaveLen(1))
+	})
+
+	It("should roll out changed init containers", func() {
+		current := newTemplate(false)
+
+		node.InitContainers[0].Image = "registry.example.com/plugins:1.1"
+		Expect(podTemplateSpecChanges(current, newTemplate(false))).To(ConsistOf(
+			"initContainers[fetch-plugins].image: registry.example.com/plugins:1.0 -> registry.example.com/plugins:1.1"))
+	})
+
+	It("should reject init containers named like the init containers of the operator", func() {
+		cluster := &api.Elasticsearch{Spec: api.ElasticsearchSpec{Nodes: []api.ElasticsearchNode{node}}}
+		Expect(initContainersViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.Nodes[0].InitContainers = append(cluster.Spec.Nodes[0].InitContainers, v1.Container{Name: clockCheckName})
+		Expect(initContainersViolation(cluster)).To(ContainSubstring(`"clock-check"`))
+	})
+
+	It("should reject tuning the host in a baseline namespace", func() {
+		Expect(newRequest("privileged").hostTuningViolation()).To(BeEmpty())
+		Expect(newRequest("baseline").hostTuningViolation()).To(Equal(
+			"Host tuning requires a privileged init container which the baseline pod security level of namespace openshift-logging forbids"))
+	})
+})
@@ -0,0 +1,201 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// InventoryConfigMapName is the name of the ConfigMap in the namespace of the operator listing
+	// the clusters it manages
+	InventoryConfigMapName = "elasticsearch-inventory"
+	inventoryKey           = "clusters.json"
+)
+
+// ClusterInventory lists the clusters managed by the operator across the watched namespaces
+type ClusterInventory struct {
+	Clusters []ClusterInventoryEntry `json:"clusters"`
+}
+
+// ClusterInventoryEntry summarizes a managed cluster
+type ClusterInventoryEntry struct {
+	Name              string                 `json:"name"`
+	Namespace         string                 `json:"namespace"`
+	Version           string                 `json:"version,omitempty"`
+	Health            string                 `json:"health,omitempty"`
+	Phase             api.ElasticsearchPhase `json:"phase,omitempty"`
+	ManagementState   api.ManagementState    `json:"managementState,omitempty"`
+	OperatorVersion   string                 `json:"operatorVersion,omitempty"`
+	Owner             string                 `json:"owner,omitempty"`
+	PendingOperations []string               `json:"pendingOperations,omitempty"`
+}
+
+// pendingConditions are the cluster conditions reporting an operation the operator did not
+// complete yet
+var pendingConditions = []api.ClusterConditionType{
+	api.FullRestartScheduled,
+	api.SpecChangeQueued,
+	api.DisruptionDeferred,
+}
+
+// ReconcileInventory lists the Elasticsearch clusters of all watched namespaces into the inventory
+// ConfigMap of the namespace and the managed cluster metrics
+func ReconcileInventory(namespace string, c client.Client) error {
+	clusters := &api.ElasticsearchList{}
+	if err := c.List(context.TODO(), clusters); err != nil {
+		return kverrors.Wrap(err, "failed to list clusters for the inventory")
+	}
+
+	inventory := newClusterInventory(clusters.Items)
+
+	managed := []metrics.ManagedCluster{}
+	for _, entry := range inventory.Clusters {
+		managed = append(managed, metrics.ManagedCluster{
+			Name:              entry.Name,
+			Namespace:         entry.Namespace,
+			Version:           entry.Version,
+			Health:            entry.Health,
+			Phase:             string(entry.Phase),
+			Owner:             entry.Owner,
+			OperatorVersion:   entry.OperatorVersion,
+			PendingOperations: len(entry.PendingOperations),
+		})
+	}
+	metrics.SetManagedClusters(managed)
+
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return kverrors.Wrap(err, "failed to marshal the inventory")
+	}
+
+	return createOrUpdateInventoryConfigMap(namespace, string(data), c)
+}
+
+func newClusterInventory(clusters []api.Elasticsearch) ClusterInventory {
+	inventory := ClusterInventory{Clusters: []ClusterInventoryEntry{}}
+	for i := range clusters {
+		inventory.Clusters = append(inventory.Clusters, newClusterInventoryEntry(&clusters[i]))
+	}
+
+	sort.Slice(inventory.Clusters, func(i, j int) bool {
+		a, b := inventory.Clusters[i], inventory.Clusters[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return inventory
+}
+
+func newClusterInventoryEntry(cluster *api.Elasticsearch) ClusterInventoryEntry {
+	entry := ClusterInventoryEntry{
+		Name:              cluster.Name,
+		Namespace:         cluster.Namespace,
+		Version:           cluster.Status.Version,
+		Health:            cluster.Status.Cluster.Status,
+		Phase:             cluster.Status.Phase,
+		ManagementState:   cluster.Spec.ManagementState,
+		PendingOperations: pendingOperations(cluster),
+	}
+
+	if cluster.Status.Operator != nil {
+		entry.OperatorVersion = cluster.Status.Operator.Version
+	}
+
+	if owner := metav1.GetControllerOf(cluster); owner != nil {
+		entry.Owner = fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+	}
+
+	return entry
+}
+
+// pendingOperations lists the node upgrades and the cluster operations the operator still has to
+// carry out on the cluster
+func pendingOperations(cluster *api.Elasticsearch) []string {
+	operations := []string{}
+
+	for _, node := range cluster.Status.Nodes {
+		name := node.DeploymentName
+		if name == "" {
+			name = node.StatefulSetName
+		}
+
+		upgrade := node.UpgradeStatus
+		switch {
+		case upgrade.UnderUpgrade == v1.ConditionTrue:
+			operations = append(operations, fmt.Sprintf("upgrading node %s", name))
+		case upgrade.ScheduledForCertRedeploy == v1.ConditionTrue:
+			operations = append(operations, fmt.Sprintf("certificate redeploy of node %s", name))
+		case upgrade.ScheduledForRedeploy == v1.ConditionTrue:
+			operations = append(operations, fmt.Sprintf("redeploy of node %s", name))
+		case upgrade.ScheduledForUpgrade == v1.ConditionTrue:
+			operations = append(operations, fmt.Sprintf("upgrade of node %s", name))
+		}
+	}
+
+	for _, condition := range cluster.Status.Conditions {
+		for _, pending := range pendingConditions {
+			if condition.Type == pending && condition.Status == v1.ConditionTrue {
+				operations = append(operations, string(condition.Type))
+			}
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+	return operations
+}
+
+func createOrUpdateInventoryConfigMap(namespace, data string, c client.Client) error {
+	cm := &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: v1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      InventoryConfigMapName,
+			Namespace: namespace,
+		},
+		Data: map[string]string{inventoryKey: data},
+	}
+
+	err := c.Create(context.TODO(), cm)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(kverrors.Root(err)) {
+		return kverrors.Wrap(err, "failed to create inventory configmap",
+			"name", cm.Name,
+			"namespace", cm.Namespace)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.ConfigMap{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, current); err != nil {
+			return err
+		}
+
+		if current.Data[inventoryKey] == data {
+			return nil
+		}
+
+		current.Data = cm.Data
+		return c.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update inventory configmap",
+		"name", cm.Name,
+		"namespace", cm.Namespace)
+}
@@ -0,0 +1,94 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster inventory", func() {
+	defer GinkgoRecover()
+
+	controller := true
+
+	newCluster := func(name, namespace string) *api.Elasticsearch {
+		return &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       api.ElasticsearchSpec{ManagementState: api.ManagementStateManaged},
+		}
+	}
+
+	It("should summarize the version, health, owner and pending operations of a cluster", func() {
+		cluster := newCluster("elasticsearch", "openshift-logging")
+		cluster.OwnerReferences = []metav1.OwnerReference{{Kind: "ClusterLogging", Name: "instance", Controller: &controller}}
+		cluster.Status = api.ElasticsearchStatus{
+			Version:  "6.8.1",
+			Phase:    api.ElasticsearchPhaseUpgrading,
+			Cluster:  api.ClusterHealth{Status: "green"},
+			Operator: &api.OperatorVersionStatus{Version: "5.1.0"},
+			Nodes: []api.ElasticsearchNodeStatus{
+				{DeploymentName: "elasticsearch-cdm-abc-1", UpgradeStatus: api.ElasticsearchNodeUpgradeStatus{UnderUpgrade: v1.ConditionTrue}},
+				{DeploymentName: "elasticsearch-cdm-abc-2", UpgradeStatus: api.ElasticsearchNodeUpgradeStatus{ScheduledForUpgrade: v1.ConditionTrue}},
+				{DeploymentName: "elasticsearch-cdm-abc-3"},
+			},
+			Conditions: api.ClusterConditions{
+				{Type: api.SpecChangeQueued, Status: v1.ConditionTrue},
+				{Type: api.FullRestartScheduled, Status: v1.ConditionFalse},
+			},
+		}
+
+		Expect(newClusterInventoryEntry(cluster)).To(Equal(ClusterInventoryEntry{
+			Name:            "elasticsearch",
+			Namespace:       "openshift-logging",
+			Version:         "6.8.1",
+			Health:          "green",
+			Phase:           api.ElasticsearchPhaseUpgrading,
+			ManagementState: api.ManagementStateManaged,
+			OperatorVersion: "5.1.0",
+			Owner:           "ClusterLogging/instance",
+			PendingOperations: []string{
+				"upgrading node elasticsearch-cdm-abc-1",
+				"upgrade of node elasticsearch-cdm-abc-2",
+				"SpecChangeQueued",
+			},
+		}))
+	})
+
+	It("should list the clusters of all namespaces into the inventory configmap", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		existing := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: InventoryConfigMapName, Namespace: "openshift-operators-redhat"},
+			Data:       map[string]string{inventoryKey: `{"clusters":[]}`},
+		}
+		c := fake.NewFakeClientWithScheme(s,
+			newCluster("elasticsearch", "team-b"),
+			newCluster("elasticsearch", "team-a"),
+			existing,
+		)
+
+		Expect(ReconcileInventory("openshift-operators-redhat", c)).To(Succeed())
+
+		cm := &v1.ConfigMap{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: InventoryConfigMapName, Namespace: "openshift-operators-redhat"}, cm)).To(Succeed())
+
+		inventory := ClusterInventory{}
+		Expect(json.Unmarshal([]byte(cm.Data[inventoryKey]), &inventory)).To(Succeed())
+		Expect(inventory.Clusters).To(HaveLen(2))
+		Expect(inventory.Clusters[0].Namespace).To(Equal("team-a"))
+		Expect(inventory.Clusters[1].Namespace).To(Equal("team-b"))
+		Expect(inventory.Clusters[1].PendingOperations).To(BeEmpty())
+	})
+})
@@ -0,0 +1,58 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	mebibyte = 1024 * 1024
+
+	// maxHeapSize keeps the heap below the limit of compressed object pointers of the JVM
+	maxHeapSize = 31 * 1024 * mebibyte
+)
+
+// nodeHeapSize returns the heap of the node in bytes, either the one of the node or the half of
+// the memory request of the Elasticsearch container capped at 31Gi
+func nodeHeapSize(node api.ElasticsearchNode, resources v1.ResourceRequirements) int64 {
+	if node.JVM != nil && node.JVM.Heap != nil && !node.JVM.Heap.IsZero() {
+		return node.JVM.Heap.Value()
+	}
+
+	heap := resources.Requests.Memory().Value() / 2
+	if heap > maxHeapSize {
+		heap = maxHeapSize
+	}
+	return heap
+}
+
+// newInstanceRAM returns the INSTANCE_RAM for the heap. The image sizes the heap -Xms and -Xmx to
+// the half of INSTANCE_RAM
+func newInstanceRAM(heap int64) string {
+	return fmt.Sprintf("%dMi", 2*(heap/mebibyte))
+}
+
+// jvmHeapViolation returns the reason the heap of a node is invalid or an empty string. The heap
+// of a node must be smaller than the memory limit of its Elasticsearch container
+func jvmHeapViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		if node.JVM == nil || node.JVM.Heap == nil {
+			continue
+		}
+
+		heap := node.JVM.Heap
+		if heap.Sign() < 0 || (!heap.IsZero() && heap.Value() < mebibyte) {
+			return fmt.Sprintf("The heap %s of the nodes with the roles %v must be at least 1Mi", heap.String(), node.Roles)
+		}
+
+		resources := newESResourceRequirements(node.Resources, cluster.Spec.Spec.Resources)
+		limit := resources.Limits.Memory()
+		if heap.Cmp(*limit) >= 0 {
+			return fmt.Sprintf("The heap %s of the nodes with the roles %v must be smaller than their memory limit %s",
+				heap.String(), node.Roles, limit.String())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("JVM heap", func() {
+	defer GinkgoRecover()
+
+	newResources := func(request, limit string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse(request)},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse(limit)},
+		}
+	}
+
+	newNode := func(heap string) api.ElasticsearchNode {
+		quantity := resource.MustParse(heap)
+		return api.ElasticsearchNode{
+			Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+			Resources: newResources("8Gi", "8Gi"),
+			JVM:       &api.ElasticsearchJVMSpec{Heap: &quantity},
+		}
+	}
+
+	It("should size the heap to the half of the memory request", func() {
+		heap := nodeHeapSize(api.ElasticsearchNode{}, newResources("8Gi", "16Gi"))
+		Expect(newInstanceRAM(heap)).To(Equal("8192Mi"))
+	})
+
+	It("should cap the heap for compressed object pointers", func() {
+		heap := nodeHeapSize(api.ElasticsearchNode{}, newResources("96Gi", "96Gi"))
+		Expect(heap).To(Equal(int64(maxHeapSize)))
+		Expect(newInstanceRAM(heap)).To(Equal("63488Mi"))
+	})
+
+	It("should use the heap of the node over the memory request", func() {
+		heap := nodeHeapSize(newNode("3Gi"), newResources("8Gi", "8Gi"))
+		Expect(newInstanceRAM(heap)).To(Equal("6144Mi"))
+	})
+
+	It("should reject a heap not smaller than the memory limit", func() {
+		cluster := &api.Elasticsearch{Spec: api.ElasticsearchSpec{Nodes: []api.ElasticsearchNode{newNode("8Gi")}}}
+		Expect(jvmHeapViolation(cluster)).To(Equal(
+			"The heap 8Gi of the nodes with the roles [data] must be smaller than their memory limit 8Gi"))
+
+		cluster.Spec.Nodes[0] = newNode("6Gi")
+		Expect(jvmHeapViolation(cluster)).To(BeEmpty())
+	})
+})
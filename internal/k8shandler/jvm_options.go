@@ -0,0 +1,98 @@
+package k8shandler
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	jvmOptionsVolumeName = "elasticsearch-jvm-options"
+	jvmOptionsKeyPrefix  = "jvm-options-"
+	jvmOptionsPath       = elasticsearchConfigPath + "/jvm.options.d"
+	jvmOptionsFile       = "custom.options"
+)
+
+// nodeJVMOptions returns the additional JVM options of the node
+func nodeJVMOptions(node api.ElasticsearchNode) []string {
+	if node.JVM == nil {
+		return nil
+	}
+	return node.JVM.Options
+}
+
+func renderJVMOptions(options []string) string {
+	return strings.Join(options, "\n") + "\n"
+}
+
+// jvmOptionsKey returns the key of the options in the configmap of the cluster. The key is derived
+// from the options so changing them changes the pod template and rolls out the nodes
+func jvmOptionsKey(options []string) string {
+	sum := sha256.Sum256([]byte(renderJVMOptions(options)))
+	return fmt.Sprintf("%s%x", jvmOptionsKeyPrefix, sum[:5])
+}
+
+// newJVMOptionsData returns the additional JVM options of the nodes of the cluster by their key
+func newJVMOptionsData(cluster *api.Elasticsearch) map[string]string {
+	data := map[string]string{}
+	for _, node := range cluster.Spec.Nodes {
+		if options := nodeJVMOptions(node); len(options) > 0 {
+			data[jvmOptionsKey(options)] = renderJVMOptions(options)
+		}
+	}
+	return data
+}
+
+// jvmOptionsData returns the JVM options of the data of a configmap
+func jvmOptionsData(data map[string]string) map[string]string {
+	options := map[string]string{}
+	for key, value := range data {
+		if strings.HasPrefix(key, jvmOptionsKeyPrefix) {
+			options[key] = value
+		}
+	}
+	return options
+}
+
+// newJVMOptionsVolume returns the volume of the options from the configmap of the cluster. The
+// volume is optional as the configmap drops the options of the previous spec before the pods of
+// the nodes are rolled out
+func newJVMOptionsVolume(clusterName string, options []string) v1.Volume {
+	optional := true
+	return v1.Volume{
+		Name: jvmOptionsVolumeName,
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: clusterName,
+				},
+				Items: []v1.KeyToPath{
+					{
+						Key:  jvmOptionsKey(options),
+						Path: jvmOptionsFile,
+					},
+				},
+				Optional: &optional,
+			},
+		},
+	}
+}
+
+// jvmOptionsViolation returns the reason the JVM options of a node are invalid or an empty string.
+// The options must be single lines and must not size the heap
+func jvmOptionsViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		for _, option := range nodeJVMOptions(node) {
+			if strings.TrimSpace(option) == "" || strings.ContainsAny(option, "\r\n") {
+				return fmt.Sprintf("The JVM options of the nodes with the roles %v must be single non-empty lines", node.Roles)
+			}
+			if strings.HasPrefix(option, "-Xms") || strings.HasPrefix(option, "-Xmx") {
+				return fmt.Sprintf("The JVM option %q of the nodes with the roles %v must be replaced by the heap of the nodes", option, node.Roles)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("JVM options", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3},
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+						NodeCount: 2,
+						JVM: &api.ElasticsearchJVMSpec{
+							Options: []string{"-XX:+UseG1GC", "-XX:MaxGCPauseMillis=200"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	newTemplate := func(node api.ElasticsearchNode) v1.PodTemplateSpec {
+		return newPodTemplateSpec("node", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "elasticsearch", namespace: "openshift-logging"})
+	}
+
+	It("should render the options of the nodes into the configmap of the cluster", func() {
+		configmap := newClusterConfigMap(cluster, nil)
+
+		key := jvmOptionsKey(cluster.Spec.Nodes[1].JVM.Options)
+		Expect(jvmOptionsData(configmap.Data)).To(Equal(map[string]string{
+			key: "-XX:+UseG1GC\n-XX:MaxGCPauseMillis=200\n",
+		}))
+	})
+
+	It("should mount the options of the node into the jvm.options.d directory", func() {
+		template := newTemplate(cluster.Spec.Nodes[1])
+
+		Expect(template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{Name: jvmOptionsVolumeName, MountPath: jvmOptionsPath}))
+		volume := template.Spec.Volumes[len(template.Spec.Volumes)-1]
+		Expect(volume.ConfigMap.Items).To(Equal([]v1.KeyToPath{
+			{Key: jvmOptionsKey(cluster.Spec.Nodes[1].JVM.Options), Path: jvmOptionsFile},
+		}))
+
+		for _, volume := range newTemplate(cluster.Spec.Nodes[0]).Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(jvmOptionsVolumeName))
+		}
+	})
+
+	It("should roll out the nodes when the options change", func() {
+		current := newTemplate(cluster.Spec.Nodes[1])
+		currentConfigMap := newClusterConfigMap(cluster, nil)
+
+		cluster.Spec.Nodes[1].JVM.Options = []string{"-XX:+UseG1GC"}
+		Expect(configMapContentChanged(currentConfigMap, newClusterConfigMap(cluster, nil))).To(BeTrue())
+		Expect(podTemplateSpecChanges(current, newTemplate(cluster.Spec.Nodes[1]))).To(ContainElement("volumes: elasticsearch-jvm-options"))
+	})
+
+	It("should reject options sizing the heap", func() {
+		Expect(jvmOptionsViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.Nodes[1].JVM.Options = []string{"-Xmx4g"}
+		Expect(jvmOptionsViolation(cluster)).To(Equal(
+			`The JVM option "-Xmx4g" of the nodes with the roles [data] must be replaced by the heap of the nodes`))
+	})
+})
@@ -0,0 +1,168 @@
+package kibana
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	apps "k8s.io/api/apps/v1"
+)
+
+const (
+	kibanaIndexPattern           = ".kibana*"
+	kibanaIndexAlias             = ".kibana"
+	defaultIndexMigrationTimeout = 15 * time.Minute
+	// upgradeSnapshotPrefix is followed by the hash of the Kibana image the snapshot was taken for
+	upgradeSnapshotPrefix = "kibana-upgrade-"
+	snapshotStateSuccess  = "SUCCESS"
+	snapshotStateRunning  = "IN_PROGRESS"
+)
+
+// migrationIndexName matches the indices the saved objects are migrated into. The .kibana alias is
+// moved to an index once the migration into it completed
+var migrationIndexName = regexp.MustCompile(`^\.kibana_(\d+)$`)
+
+// readyForKibanaUpgrade returns true once Kibana may be upgraded to the image and migrate its saved
+// objects. Kibana waits for the Elasticsearch nodes to run the same version, as a migration against a
+// cluster of mixed versions gets stuck, and for the snapshot of the .kibana indices if requested
+func (clusterRequest *KibanaRequest) readyForKibanaUpgrade(image string) (bool, error) {
+	indices, err := clusterRequest.esClient.GetAllIndices(kibanaIndexPattern)
+	if err != nil {
+		return false, kverrors.Wrap(err, "failed to get the kibana indices before upgrading kibana")
+	}
+	if len(indices) == 0 {
+		return true, nil
+	}
+
+	versions, err := clusterRequest.esClient.GetClusterNodeVersions()
+	if err != nil {
+		return false, kverrors.Wrap(err, "failed to get the node versions before upgrading kibana")
+	}
+	if len(versions) != 1 {
+		log.Info("Waiting for the upgrade of Elasticsearch to complete before upgrading Kibana", "versions", versions)
+		return false, nil
+	}
+
+	spec := clusterRequest.cluster.Spec.IndexMigration
+	if spec == nil || spec.SnapshotRepository == "" {
+		return true, nil
+	}
+	return clusterRequest.snapshotKibanaIndices(spec.SnapshotRepository, image)
+}
+
+// snapshotKibanaIndices snapshots the .kibana indices once for every image Kibana is upgraded to and
+// returns true once the snapshot completed
+func (clusterRequest *KibanaRequest) snapshotKibanaIndices(repository, image string) (bool, error) {
+	name := upgradeSnapshotName(image)
+
+	snapshot, err := clusterRequest.esClient.GetSnapshot(repository, name)
+	if err != nil {
+		return false, err
+	}
+
+	if snapshot == nil {
+		request := &estypes.CreateSnapshot{Indices: kibanaIndexPattern}
+		if err := clusterRequest.esClient.CreateSnapshot(repository, name, request); err != nil {
+			return false, err
+		}
+		log.Info("Snapshotting the kibana indices before upgrading Kibana", "repository", repository, "snapshot", name)
+		return false, nil
+	}
+
+	switch snapshot.State {
+	case snapshotStateSuccess:
+		return true, nil
+	case snapshotStateRunning:
+		return false, nil
+	default:
+		return false, kverrors.New("the snapshot of the kibana indices failed, delete it to retry the upgrade of kibana",
+			"repository", repository,
+			"snapshot", name,
+			"state", snapshot.State)
+	}
+}
+
+func upgradeSnapshotName(image string) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(image)))
+	return upgradeSnapshotPrefix + hash[:12]
+}
+
+// holdKibanaImages keeps the images of the current deployment in the desired one
+func holdKibanaImages(current, desired *apps.Deployment) {
+	for index, des := range desired.Spec.Template.Spec.Containers {
+		for _, curr := range current.Spec.Template.Spec.Containers {
+			if curr.Name == des.Name {
+				desired.Spec.Template.Spec.Containers[index].Image = curr.Image
+			}
+		}
+	}
+}
+
+// recoverStuckIndexMigration deletes the target index of a saved objects migration which did not
+// complete within the timeout. Kibana refuses to start as long as it finds the index, assuming
+// another instance migrates into it. The .kibana alias still points to the source of the migration
+func (clusterRequest *KibanaRequest) recoverStuckIndexMigration(now time.Time) error {
+	indices, err := clusterRequest.esClient.ListIndicesCreationDate(".kibana_*")
+	if err != nil {
+		return err
+	}
+
+	candidates := estypes.CatIndicesResponses{}
+	for _, index := range indices {
+		if migrationIndexName.MatchString(index.Index) {
+			candidates = append(candidates, index)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	aliased, err := clusterRequest.esClient.ListIndicesForAlias(kibanaIndexAlias)
+	if err != nil {
+		return err
+	}
+	current := 0
+	for _, name := range aliased {
+		if n := migrationIndexNumber(name); n > current {
+			current = n
+		}
+	}
+
+	timeout := defaultIndexMigrationTimeout
+	if spec := clusterRequest.cluster.Spec.IndexMigration; spec != nil && spec.Timeout != nil && spec.Timeout.Duration > 0 {
+		timeout = spec.Timeout.Duration
+	}
+
+	for _, index := range candidates {
+		if migrationIndexNumber(index.Index) <= current {
+			continue
+		}
+
+		millis, err := strconv.ParseInt(index.CreationDate, 10, 64)
+		if err != nil || now.Sub(time.Unix(0, millis*int64(time.Millisecond))) < timeout {
+			continue
+		}
+
+		log.Info("Deleting the target index of a stuck saved objects migration for Kibana to retry it",
+			"index", index.Index,
+			"alias", kibanaIndexAlias)
+		if err := clusterRequest.esClient.DeleteIndex(index.Index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrationIndexNumber(name string) int {
+	match := migrationIndexName.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
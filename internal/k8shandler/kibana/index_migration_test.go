@@ -0,0 +1,150 @@
+package kibana
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	kibana "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	kibanaIndicesURI  = "_cat/indices/.kibana*?format=json"
+	migrationIndexURI = "_cat/indices/.kibana_*?h=index,status,creation.date&format=json"
+)
+
+func newMigrationRequest(spec *kibana.KibanaIndexMigrationSpec, responses map[string]helpers.FakeElasticsearchResponses) (*KibanaRequest, *helpers.FakeElasticsearchChatter) {
+	client := fake.NewFakeClient()
+	chatter := helpers.NewFakeElasticsearchChatter(responses)
+	return &KibanaRequest{
+		client: client,
+		cluster: &kibana.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Name: "kibana", Namespace: "openshift-logging"},
+			Spec:       kibana.KibanaSpec{IndexMigration: spec},
+		},
+		esClient: helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", client, chatter),
+	}, chatter
+}
+
+func TestReadyForKibanaUpgradeWaitsForElasticsearchUpgrade(t *testing.T) {
+	request, _ := newMigrationRequest(nil, map[string]helpers.FakeElasticsearchResponses{
+		kibanaIndicesURI: {{StatusCode: http.StatusOK, Body: `[{"health":"green","index":".kibana_1"}]`}},
+		"_cluster/stats": {{StatusCode: http.StatusOK, Body: `{"nodes":{"versions":["6.8.1","7.10.2"]}}`}},
+	})
+
+	ready, err := request.readyForKibanaUpgrade("kibana:7.10.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ready {
+		t.Error("Exp. Kibana to wait for the nodes to run the same version")
+	}
+}
+
+func TestReadyForKibanaUpgradeWithoutKibanaIndices(t *testing.T) {
+	request, _ := newMigrationRequest(nil, map[string]helpers.FakeElasticsearchResponses{
+		kibanaIndicesURI: {{StatusCode: http.StatusOK, Body: `[]`}},
+	})
+
+	ready, err := request.readyForKibanaUpgrade("kibana:7.10.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ready {
+		t.Error("Exp. Kibana to be upgraded without saved objects to migrate")
+	}
+}
+
+func TestReadyForKibanaUpgradeSnapshotsKibanaIndices(t *testing.T) {
+	image := "kibana:7.10.2"
+	snapshotURI := "_snapshot/backups/" + upgradeSnapshotName(image)
+
+	responses := map[string]helpers.FakeElasticsearchResponses{
+		kibanaIndicesURI: {
+			{StatusCode: http.StatusOK, Body: `[{"health":"green","index":".kibana_1"}]`},
+			{StatusCode: http.StatusOK, Body: `[{"health":"green","index":".kibana_1"}]`},
+		},
+		"_cluster/stats": {
+			{StatusCode: http.StatusOK, Body: `{"nodes":{"versions":["7.10.2"]}}`},
+			{StatusCode: http.StatusOK, Body: `{"nodes":{"versions":["7.10.2"]}}`},
+		},
+		snapshotURI: {
+			{StatusCode: http.StatusNotFound, Body: `{}`},
+			{StatusCode: http.StatusOK, Body: `{"accepted":true}`},
+			{StatusCode: http.StatusOK, Body: fmt.Sprintf(`{"snapshots":[{"snapshot":%q,"state":"SUCCESS"}]}`, upgradeSnapshotName(image))},
+		},
+	}
+	request, chatter := newMigrationRequest(&kibana.KibanaIndexMigrationSpec{SnapshotRepository: "backups"}, responses)
+
+	ready, err := request.readyForKibanaUpgrade(image)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ready {
+		t.Error("Exp. Kibana to wait for the snapshot of the kibana indices")
+	}
+
+	requests := chatter.Requests[snapshotURI]
+	if len(requests) != 2 || requests[1].Method != http.MethodPut || requests[1].Body != `{"indices":".kibana*","include_global_state":false}` {
+		t.Errorf("Exp. the kibana indices to be snapshotted, got %v", requests)
+	}
+
+	ready, err = request.readyForKibanaUpgrade(image)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ready {
+		t.Error("Exp. Kibana to be upgraded once the snapshot completed")
+	}
+}
+
+func TestRecoverStuckIndexMigration(t *testing.T) {
+	now := time.Now()
+	created := func(age time.Duration) string {
+		return fmt.Sprintf("%d", now.Add(-age).UnixNano()/int64(time.Millisecond))
+	}
+
+	request, chatter := newMigrationRequest(nil, map[string]helpers.FakeElasticsearchResponses{
+		migrationIndexURI: {{
+			StatusCode: http.StatusOK,
+			Body: fmt.Sprintf(`[{"index":".kibana_1","creation.date":%q},{"index":".kibana_2","creation.date":%q},{"index":".kibana_-377444158_kubeadmin","creation.date":%q}]`,
+				created(48*time.Hour), created(time.Hour), created(time.Hour)),
+		}},
+		"_alias/.kibana": {{StatusCode: http.StatusOK, Body: `{".kibana_1":{"aliases":{".kibana":{}}}}`}},
+		".kibana_2":      {{StatusCode: http.StatusOK, Body: `{"acknowledged":true}`}},
+	})
+
+	if err := request.recoverStuckIndexMigration(now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deleted, found := chatter.GetRequest(".kibana_2")
+	if !found || deleted.Method != http.MethodDelete {
+		t.Error("Exp. the incomplete target index of the migration to be deleted")
+	}
+	if _, found := chatter.GetRequest(".kibana_-377444158_kubeadmin"); found {
+		t.Error("Exp. the indices of the tenants to be kept")
+	}
+}
+
+func TestRecoverStuckIndexMigrationWithinTimeout(t *testing.T) {
+	now := time.Now()
+	request, chatter := newMigrationRequest(&kibana.KibanaIndexMigrationSpec{Timeout: &metav1.Duration{Duration: 2 * time.Hour}}, map[string]helpers.FakeElasticsearchResponses{
+		migrationIndexURI: {{
+			StatusCode: http.StatusOK,
+			Body:       fmt.Sprintf(`[{"index":".kibana_1","creation.date":"%d"}]`, now.Add(-time.Hour).UnixNano()/int64(time.Millisecond)),
+		}},
+		"_alias/.kibana": {{StatusCode: http.StatusNotFound, Body: `{}`}},
+	})
+
+	if err := request.recoverStuckIndexMigration(now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := chatter.GetRequest(".kibana_1"); found {
+		t.Error("Exp. a migration within the timeout to be left running")
+	}
+}
@@ -127,6 +127,12 @@ var _ = Describe("Reconciling", func() {
 						Body:       `{"nodes": {"versions": ["6.8.1"]}}`,
 					},
 				},
+				"_cat/indices/.kibana_*?h=index,status,creation.date&format=json": {
+					{
+						StatusCode: 200,
+						Body:       `[]`,
+					},
+				},
 				"_alias/.kibana": {
 					// Set migration completed
 					{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
@@ -94,6 +95,10 @@ func Reconcile(requestCluster *kibana.Kibana, requestClient client.Client, esCli
 		return err
 	}
 
+	if err := clusterKibanaRequest.recoverStuckIndexMigration(time.Now()); err != nil {
+		log.Error(err, "Failed to recover a stuck saved objects migration of Kibana")
+	}
+
 	if err := clusterKibanaRequest.removeSharedConfigMapPre45x(); err != nil {
 		return err
 	}
@@ -268,6 +273,16 @@ func (clusterRequest *KibanaRequest) createOrUpdateKibanaDeployment(proxyConfig
 				return kverrors.Wrap(err, "failed to get Kibana deployment")
 			}
 
+			if isDeploymentImageDifference(current, kibanaDeployment) {
+				ready, err := clusterRequest.readyForKibanaUpgrade(getImage())
+				if err != nil {
+					return err
+				}
+				if !ready {
+					holdKibanaImages(current, kibanaDeployment)
+				}
+			}
+
 			current, different := isDeploymentDifferent(current, kibanaDeployment)
 
 			currentTrustedCAHash := current.Spec.Template.ObjectMeta.Annotations[constants.TrustedCABundleHashName]
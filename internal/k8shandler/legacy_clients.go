@@ -0,0 +1,232 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	legacyClientPrefix = "legacy-"
+	// legacyClientServiceLabel labels the Services of the legacy clients to find the stale ones
+	legacyClientServiceLabel = "elasticsearch.openshift.io/legacy-client-service"
+	legacyClientCertsPath    = "/etc/proxy/legacy"
+	servingCertAnnotation    = "service.beta.openshift.io/serving-cert-secret-name"
+)
+
+// reservedServiceNames are the names of the Services of the operator relative to the cluster
+var reservedServiceNames = []string{"cluster", "metrics", "coordinating", "ingest"}
+
+// reservedPorts are the ports of the containers of the nodes
+var reservedPorts = []int32{restPort, transportPort, proxyRestPort, metricsPort}
+
+// legacyClientsViolation returns the reason the Services of the legacy clients are invalid or an
+// empty string. Their names and ports must differ from each other and from the ones of the operator
+func legacyClientsViolation(cluster *api.Elasticsearch) string {
+	names := map[string]bool{}
+	for _, name := range reservedServiceNames {
+		names[name] = true
+	}
+	ports := map[int32]bool{}
+	for _, port := range reservedPorts {
+		ports[port] = true
+	}
+
+	for _, spec := range cluster.Spec.LegacyClientServices {
+		if names[spec.Name] {
+			return fmt.Sprintf("The name %q of the legacy client service must differ from the other services of the cluster", spec.Name)
+		}
+		names[spec.Name] = true
+
+		if ports[spec.Port] {
+			return fmt.Sprintf("The port %d of the legacy client service %q must differ from the other ports of the nodes", spec.Port, spec.Name)
+		}
+		ports[spec.Port] = true
+	}
+	return ""
+}
+
+// isLegacyClientContainer returns true if the container is the sidecar of a legacy client service
+func isLegacyClientContainer(cluster *api.Elasticsearch, name string) bool {
+	for _, service := range cluster.Spec.LegacyClientServices {
+		if legacyClientContainerName(service) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func legacyClientServiceName(cluster *api.Elasticsearch, spec api.LegacyClientServiceSpec) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, spec.Name)
+}
+
+// legacyClientCertSecretName returns the Secret with the serving certificate of the Service
+func legacyClientCertSecretName(cluster *api.Elasticsearch, spec api.LegacyClientServiceSpec) string {
+	if spec.CertSecretName != "" {
+		return spec.CertSecretName
+	}
+	return fmt.Sprintf("%s-tls", legacyClientServiceName(cluster, spec))
+}
+
+func legacyClientContainerName(spec api.LegacyClientServiceSpec) string {
+	return legacyClientPrefix + spec.Name
+}
+
+// legacyClientPortName returns the name of the port of the sidecar. Port names are limited to 15
+// characters so the port is named by its number
+func legacyClientPortName(spec api.LegacyClientServiceSpec) string {
+	return fmt.Sprintf("%s%d", legacyClientPrefix, spec.Port)
+}
+
+// CreateOrUpdateLegacyClientServices ensures the Services of the legacy clients exist and deletes
+// the ones removed from the spec
+func (er *ElasticsearchRequest) CreateOrUpdateLegacyClientServices() error {
+	cluster := er.cluster
+
+	desired := map[string]bool{}
+	for _, spec := range cluster.Spec.LegacyClientServices {
+		name := legacyClientServiceName(cluster, spec)
+		desired[name] = true
+
+		annotations := map[string]string{}
+		if spec.CertSecretName == "" {
+			annotations[servingCertAnnotation] = legacyClientCertSecretName(cluster, spec)
+		}
+
+		err := er.createOrUpdateService(
+			name,
+			cluster.Namespace,
+			cluster.Name,
+			legacyClientPortName(spec),
+			spec.Port,
+			selectorForES("es-node-client", cluster.Name),
+			annotations,
+			false,
+			map[string]string{legacyClientServiceLabel: "true"},
+		)
+		if err != nil {
+			return kverrors.Wrap(err, "failed to create legacy client service",
+				"service_name", name)
+		}
+	}
+
+	services := &v1.ServiceList{}
+	labels := client.MatchingLabels{
+		"cluster-name":           cluster.Name,
+		legacyClientServiceLabel: "true",
+	}
+	if err := er.client.List(context.TODO(), services, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return kverrors.Wrap(err, "failed to list legacy client services")
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		if desired[service.Name] {
+			continue
+		}
+		if err := er.client.Delete(context.TODO(), service); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete legacy client service",
+				"service_name", service.Name)
+		}
+		er.L().Info("Deleted legacy client service", "service", service.Name)
+	}
+	return nil
+}
+
+// applyLegacyClientProxies adds a sidecar of the proxy for each Service of the legacy clients to
+// the pods of the client nodes. The sidecars authenticate and forward like the proxy but serve the
+// certificate of their Service and do not request client certificates
+func applyLegacyClientProxies(cluster *api.Elasticsearch, template *v1.PodTemplateSpec) {
+	if len(cluster.Spec.LegacyClientServices) == 0 || template.Labels["es-node-client"] != "true" {
+		return
+	}
+
+	spec := &template.Spec
+	var proxy *v1.Container
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == "proxy" {
+			proxy = &spec.Containers[i]
+		}
+	}
+	if proxy == nil {
+		return
+	}
+
+	sidecars := []v1.Container{}
+	for _, service := range cluster.Spec.LegacyClientServices {
+		sidecars = append(sidecars, newLegacyClientContainer(cluster, service, proxy))
+		spec.Volumes = append(spec.Volumes, v1.Volume{
+			Name: legacyClientContainerName(service),
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: legacyClientCertSecretName(cluster, service),
+				},
+			},
+		})
+	}
+
+	// the sidecars follow the containers of the operator and precede the sidecars of the spec
+	containers := []v1.Container{}
+	for _, container := range spec.Containers {
+		containers = append(containers, container)
+		if container.Name == "proxy" {
+			containers = append(containers, sidecars...)
+		}
+	}
+	spec.Containers = containers
+}
+
+// newLegacyClientContainer returns the sidecar of the Service derived from the proxy so it keeps
+// the upstream and the authorization of the proxy
+func newLegacyClientContainer(cluster *api.Elasticsearch, service api.LegacyClientServiceSpec, proxy *v1.Container) v1.Container {
+	certsPath := fmt.Sprintf("%s/%s", legacyClientCertsPath, service.Name)
+
+	args := []string{
+		fmt.Sprintf("--listening-address=:%d", service.Port),
+		fmt.Sprintf("--tls-cert=%s/%s", certsPath, v1.TLSCertKey),
+		fmt.Sprintf("--tls-key=%s/%s", certsPath, v1.TLSPrivateKeyKey),
+	}
+	for _, arg := range proxy.Args {
+		switch {
+		case strings.HasPrefix(arg, "--auth-default-role=") && service.DefaultRole != "":
+			args = append(args, "--auth-default-role="+service.DefaultRole)
+		case strings.HasPrefix(arg, "--upstream"), strings.HasPrefix(arg, "--cache-expiry="), strings.HasPrefix(arg, "--auth-"):
+			args = append(args, arg)
+		}
+	}
+
+	mounts := []v1.VolumeMount{
+		{
+			Name:      legacyClientContainerName(service),
+			MountPath: certsPath,
+			ReadOnly:  true,
+		},
+	}
+	for _, mount := range proxy.VolumeMounts {
+		if mount.Name == "certificates" {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	return v1.Container{
+		Name:            legacyClientContainerName(service),
+		Image:           proxy.Image,
+		ImagePullPolicy: proxy.ImagePullPolicy,
+		Ports: []v1.ContainerPort{
+			{
+				Name:          legacyClientPortName(service),
+				ContainerPort: service.Port,
+				Protocol:      v1.ProtocolTCP,
+			},
+		},
+		Env:          proxy.Env,
+		VolumeMounts: mounts,
+		Args:         args,
+		Resources:    proxy.Resources,
+	}
+}
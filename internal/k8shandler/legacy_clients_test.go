@@ -0,0 +1,123 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Legacy client services", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		request *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+				LegacyClientServices: []api.LegacyClientServiceSpec{
+					{Name: "legacy", Port: 9443},
+					{Name: "fluentd", Port: 9444, CertSecretName: "fluentd-serving", DefaultRole: "fluentd"},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClient(),
+			cluster: cluster,
+		}
+	})
+
+	podTemplate := func(client bool) *v1.PodTemplateSpec {
+		roleMap := getNodeRoleMap(cluster.Spec.Nodes[0])
+		roleMap[api.ElasticsearchRoleClient] = client
+		template := newPodTemplateSpec("node", cluster.Spec.Nodes[0], newLabels(cluster.Name, "node", roleMap), roleMap, nil, podTemplateOptions{
+			clusterName: cluster.Name,
+			namespace:   cluster.Namespace,
+			commonSpec:  cluster.Spec.Spec,
+		})
+		applyLegacyClientProxies(cluster, &template)
+		return &template
+	}
+
+	It("should reject the names and ports of the operator and duplicates", func() {
+		Expect(legacyClientsViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.LegacyClientServices[1].Name = "metrics"
+		Expect(legacyClientsViolation(cluster)).To(ContainSubstring(`"metrics"`))
+
+		cluster.Spec.LegacyClientServices[1].Name = "fluentd"
+		cluster.Spec.LegacyClientServices[1].Port = 9443
+		Expect(legacyClientsViolation(cluster)).To(ContainSubstring("9443"))
+
+		cluster.Spec.LegacyClientServices[1].Port = 60000
+		Expect(legacyClientsViolation(cluster)).To(ContainSubstring("60000"))
+	})
+
+	It("should add a sidecar of the proxy per service to the client nodes", func() {
+		template := podTemplate(true)
+
+		names := []string{}
+		for _, container := range template.Spec.Containers {
+			names = append(names, container.Name)
+		}
+		Expect(names).To(Equal([]string{"elasticsearch", "proxy", "legacy-legacy", "legacy-fluentd"}))
+
+		sidecar := template.Spec.Containers[3]
+		Expect(sidecar.Image).To(Equal(template.Spec.Containers[1].Image))
+		Expect(sidecar.Ports).To(ConsistOf(v1.ContainerPort{Name: "legacy-9444", ContainerPort: 9444, Protocol: v1.ProtocolTCP}))
+		Expect(sidecar.Args).To(ContainElement("--listening-address=:9444"))
+		Expect(sidecar.Args).To(ContainElement("--tls-cert=/etc/proxy/legacy/fluentd/tls.crt"))
+		Expect(sidecar.Args).To(ContainElement("--upstream-ca=/etc/proxy/elasticsearch/admin-ca"))
+		Expect(sidecar.Args).To(ContainElement("--auth-default-role=fluentd"))
+		Expect(sidecar.Args).ToNot(ContainElement("--auth-default-role=project_user"))
+		Expect(sidecar.Args).ToNot(ContainElement(HavePrefix("--tls-client-ca")))
+		Expect(sidecar.Args).ToNot(ContainElement(HavePrefix("--metrics-")))
+
+		Expect(template.Spec.Volumes).To(ContainElement(v1.Volume{
+			Name: "legacy-legacy",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: "elasticsearch-legacy-tls"},
+			},
+		}))
+	})
+
+	It("should not add sidecars to the other nodes", func() {
+		template := podTemplate(false)
+		Expect(template.Spec.Containers).To(HaveLen(2))
+	})
+
+	It("should create the services and delete the removed ones", func() {
+		Expect(request.CreateOrUpdateLegacyClientServices()).To(Succeed())
+
+		service := &v1.Service{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-legacy", Namespace: "openshift-logging"}, service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue(servingCertAnnotation, "elasticsearch-legacy-tls"))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(9443)))
+		Expect(service.Spec.Ports[0].TargetPort).To(Equal(intstr.FromString("legacy-9443")))
+		Expect(service.Spec.Selector).To(HaveKeyWithValue("es-node-client", "true"))
+
+		service = &v1.Service{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-fluentd", Namespace: "openshift-logging"}, service)).To(Succeed())
+		Expect(service.Annotations).ToNot(HaveKey(servingCertAnnotation))
+
+		cluster.Spec.LegacyClientServices = cluster.Spec.LegacyClientServices[:1]
+		Expect(request.CreateOrUpdateLegacyClientServices()).To(Succeed())
+
+		err := request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-fluentd", Namespace: "openshift-logging"}, service)
+		Expect(err).To(HaveOccurred())
+	})
+})
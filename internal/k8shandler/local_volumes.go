@@ -0,0 +1,110 @@
+package k8shandler
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const hostnameLabel = "kubernetes.io/hostname"
+
+// ErrWaitingForLocalVolumeHost indicates a pod cannot start before the host of its local volume is
+// schedulable again. The upgrade of the node waits for the host instead of counting failed attempts
+var ErrWaitingForLocalVolumeHost = kverrors.New("waiting for the host of a local volume")
+
+// unavailableLocalVolumeHost returns the pending pod matching the labels and the host its local volume
+// pins it to if the host is missing, unschedulable or not ready. It returns empty names otherwise
+func unavailableLocalVolumeHost(c client.Client, namespace string, labels map[string]string) (string, string, error) {
+	pods := &v1.PodList{}
+	if err := c.List(context.TODO(), pods, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return "", "", kverrors.Wrap(err, "failed to list pods")
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodPending {
+			continue
+		}
+
+		for _, claimName := range volumeClaimNames(pod.Spec.Volumes) {
+			hosts, err := localVolumeHosts(c, namespace, claimName)
+			if err != nil {
+				return "", "", err
+			}
+
+			for _, host := range hosts {
+				available, err := isSchedulableHost(c, host)
+				if err != nil {
+					return "", "", err
+				}
+				if !available {
+					return pod.Name, host, nil
+				}
+			}
+		}
+	}
+
+	return "", "", nil
+}
+
+// localVolumeHosts returns the hosts the local volume bound to the claim is available on
+func localVolumeHosts(c client.Client, namespace, claimName string) ([]string, error) {
+	claim := &v1.PersistentVolumeClaim{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: namespace}, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get PVC",
+			"claim", claimName)
+	}
+	if claim.Spec.VolumeName == "" {
+		return nil, nil
+	}
+
+	volume := &v1.PersistentVolume{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: claim.Spec.VolumeName}, volume); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get persistent volume",
+			"volume", claim.Spec.VolumeName)
+	}
+	if volume.Spec.Local == nil || volume.Spec.NodeAffinity == nil || volume.Spec.NodeAffinity.Required == nil {
+		return nil, nil
+	}
+
+	hosts := []string{}
+	for _, term := range volume.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == hostnameLabel && expression.Operator == v1.NodeSelectorOpIn {
+				hosts = append(hosts, expression.Values...)
+			}
+		}
+	}
+	return hosts, nil
+}
+
+// isSchedulableHost returns true if the Kubernetes node labeled with the host name is ready and
+// schedulable
+func isSchedulableHost(c client.Client, host string) (bool, error) {
+	nodes := &v1.NodeList{}
+	if err := c.List(context.TODO(), nodes, client.MatchingLabels{hostnameLabel: host}); err != nil {
+		return false, kverrors.Wrap(err, "failed to list nodes",
+			"host", host)
+	}
+
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
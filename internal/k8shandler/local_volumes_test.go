@@ -0,0 +1,102 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Local volumes", func() {
+	defer GinkgoRecover()
+
+	var (
+		labels = map[string]string{"cluster-name": "elasticsearch", "node-name": "elasticsearch-cd-abc"}
+		pod    *v1.Pod
+		volume *v1.PersistentVolume
+		host   *v1.Node
+	)
+
+	BeforeEach(func() {
+		pod = &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc-0", Namespace: "openshift-logging", Labels: labels},
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{
+					{
+						Name: "elasticsearch-storage",
+						VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "elasticsearch-storage-elasticsearch-cd-abc-0"},
+						},
+					},
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodPending},
+		}
+		volume = &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "local-pv-1"},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeSource: v1.PersistentVolumeSource{Local: &v1.LocalVolumeSource{Path: "/mnt/disks/es"}},
+				NodeAffinity: &v1.VolumeNodeAffinity{
+					Required: &v1.NodeSelector{
+						NodeSelectorTerms: []v1.NodeSelectorTerm{
+							{
+								MatchExpressions: []v1.NodeSelectorRequirement{
+									{Key: hostnameLabel, Operator: v1.NodeSelectorOpIn, Values: []string{"worker-1"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		host = &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{hostnameLabel: "worker-1"}},
+			Spec:       v1.NodeSpec{Unschedulable: true},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			},
+		}
+	})
+
+	unavailableHost := func() (string, string) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		claim := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-storage-elasticsearch-cd-abc-0", Namespace: "openshift-logging"},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "local-pv-1"},
+		}
+		c := fake.NewFakeClientWithScheme(s, pod, claim, volume, host)
+
+		podName, hostName, err := unavailableLocalVolumeHost(c, "openshift-logging", labels)
+		Expect(err).To(BeNil())
+		return podName, hostName
+	}
+
+	It("should name the unschedulable host a pending pod is pinned to", func() {
+		podName, hostName := unavailableHost()
+		Expect(podName).To(Equal("elasticsearch-cd-abc-0"))
+		Expect(hostName).To(Equal("worker-1"))
+	})
+
+	It("should not wait once the host is schedulable again", func() {
+		host.Spec.Unschedulable = false
+		_, hostName := unavailableHost()
+		Expect(hostName).To(BeEmpty())
+	})
+
+	It("should ignore volumes which are not local", func() {
+		volume.Spec.Local = nil
+		_, hostName := unavailableHost()
+		Expect(hostName).To(BeEmpty())
+	})
+
+	It("should ignore running pods", func() {
+		pod.Status.Phase = v1.PodRunning
+		_, hostName := unavailableHost()
+		Expect(hostName).To(BeEmpty())
+	})
+})
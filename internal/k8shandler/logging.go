@@ -0,0 +1,192 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// reservedLoggers are the loggers configured by log4j2.properties of the operator
+var reservedLoggers = []string{
+	"_root",
+	"org.elasticsearch.action",
+	"com.amazon.opendistroforelasticsearch.security",
+	"org.elasticsearch.deprecation",
+	"index.search.slowlog",
+	"index.indexing.slowlog.index",
+	auditLoggerName,
+}
+
+var (
+	loggerNamePattern = regexp.MustCompile(`^[A-Za-z0-9_$.-]+$`)
+	loggerLevelKey    = regexp.MustCompile(`^logger\.([^.]+)\.level$`)
+	loggerIDChars     = regexp.MustCompile(`[^A-Za-z0-9]`)
+)
+
+// newLogConfig returns the log configuration of the annotations of the cluster overridden by its
+// logging spec
+func newLogConfig(cluster *api.Elasticsearch) LogConfig {
+	config := getLogConfig(cluster.GetAnnotations())
+
+	spec := cluster.Spec.Logging
+	if spec == nil {
+		return config
+	}
+
+	if spec.Level != "" {
+		config.ServerLoglevel = string(spec.Level)
+	}
+	if spec.Appender != "" {
+		config.ServerAppender = spec.Appender
+	}
+	if spec.DeprecationLevel != "" {
+		config.DeprecationLoglevel = string(spec.DeprecationLevel)
+	}
+	if spec.SearchSlowlogLevel != "" {
+		config.SearchSlowlogLoglevel = string(spec.SearchSlowlogLevel)
+	}
+	if spec.IndexingSlowlogLevel != "" {
+		config.IndexingSlowlogLoglevel = string(spec.IndexingSlowlogLevel)
+	}
+	config.Loggers = spec.Loggers
+	if spec.Rolling != nil {
+		if spec.Rolling.MaxFileSize != "" {
+			config.RollingMaxFileSize = spec.Rolling.MaxFileSize
+		}
+		if spec.Rolling.MaxFiles > 0 {
+			config.RollingMaxFiles = spec.Rolling.MaxFiles
+		}
+	}
+	return config
+}
+
+// loggerID returns the identifier of an additional logger in log4j2.properties
+func loggerID(name string) string {
+	return "custom_" + loggerIDChars.ReplaceAllString(name, "_")
+}
+
+// loggingViolation returns the reason the logging spec of the cluster is invalid or an empty
+// string. The additional loggers must have distinct names which are not configured by the operator
+func loggingViolation(cluster *api.Elasticsearch) string {
+	spec := cluster.Spec.Logging
+	if spec == nil {
+		return ""
+	}
+
+	ids := map[string]string{}
+	for _, logger := range spec.Loggers {
+		if !loggerNamePattern.MatchString(logger.Name) {
+			return fmt.Sprintf("Logger name %q is not a valid logger name", logger.Name)
+		}
+		for _, reserved := range reservedLoggers {
+			if logger.Name == reserved {
+				return fmt.Sprintf("Logger %q is configured by the operator and must be set by its own field", logger.Name)
+			}
+		}
+		id := loggerID(logger.Name)
+		if name, ok := ids[id]; ok {
+			return fmt.Sprintf("Loggers %q and %q are not distinct", name, logger.Name)
+		}
+		ids[id] = logger.Name
+	}
+	return ""
+}
+
+// parseProperties returns the properties of a log4j2.properties file by their keys
+func parseProperties(config string) map[string]string {
+	properties := map[string]string{}
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			properties[line] = ""
+			continue
+		}
+		properties[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return properties
+}
+
+// loggerLevels returns the levels of the loggers of a log4j2.properties file by their names. The
+// root logger is named _root as in the cluster settings
+func loggerLevels(config string) map[string]string {
+	properties := parseProperties(config)
+
+	levels := map[string]string{}
+	if level, ok := properties["rootLogger.level"]; ok {
+		levels["_root"] = level
+	}
+	for key, level := range properties {
+		match := loggerLevelKey.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		if name, ok := properties["logger."+match[1]+".name"]; ok {
+			levels[name] = level
+		}
+	}
+	return levels
+}
+
+// staticProperties returns the properties of a log4j2.properties file which the nodes only read on
+// startup. The levels of the loggers and the loggers only setting a level are updated through the
+// cluster settings
+func staticProperties(config string) map[string]string {
+	properties := parseProperties(config)
+
+	keys := map[string]int{}
+	for key := range properties {
+		if parts := strings.SplitN(key, ".", 3); len(parts) == 3 && parts[0] == "logger" {
+			keys[parts[1]]++
+		}
+	}
+
+	static := map[string]string{}
+	for key, value := range properties {
+		if key == "rootLogger.level" || loggerLevelKey.MatchString(key) {
+			continue
+		}
+		if parts := strings.SplitN(key, ".", 3); len(parts) == 3 && parts[0] == "logger" && parts[2] == "name" && keys[parts[1]] == 2 {
+			continue
+		}
+		static[key] = value
+	}
+	return static
+}
+
+// log4jChangeRequiresRestart returns true if the nodes must be restarted to read the changes of
+// log4j2.properties from current to desired
+func log4jChangeRequiresRestart(current, desired string) bool {
+	return !reflect.DeepEqual(staticProperties(current), staticProperties(desired))
+}
+
+// updateLoggerLevels updates the levels of the loggers of the running nodes for the changes of
+// log4j2.properties from current to desired. Nodes which are not ready read the levels on startup
+func (er *ElasticsearchRequest) updateLoggerLevels(current, desired string) error {
+	currentLevels := loggerLevels(current)
+	desiredLevels := loggerLevels(desired)
+
+	changes := map[string]interface{}{}
+	for name, level := range desiredLevels {
+		if currentLevels[name] != level {
+			changes[name] = level
+		}
+	}
+	for name := range currentLevels {
+		if _, ok := desiredLevels[name]; !ok {
+			changes[name] = nil
+		}
+	}
+	if len(changes) == 0 || !er.AnyNodeReady() {
+		return nil
+	}
+
+	er.L().Info("Updating the levels of the loggers of the nodes", "levels", changes)
+	return er.esClient.SetLoggerLevels(changes)
+}
@@ -0,0 +1,136 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Logging", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "elasticsearch",
+				Namespace:   "openshift-logging",
+				Annotations: map[string]string{serverLoglevelAnnotation: "debug"},
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	renderedLog4jConfig := func() string {
+		return newClusterConfigMap(cluster, nil).Data[log4jConfig]
+	}
+
+	It("should render the loggers of the spec over the annotations", func() {
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{
+			Level:              "warn",
+			SearchSlowlogLevel: "info",
+			Loggers:            []api.ElasticsearchLoggerSpec{{Name: "org.elasticsearch.transport", Level: "trace"}},
+			Rolling:            &api.ElasticsearchRollingAppenderSpec{MaxFiles: 10},
+		}
+
+		config := renderedLog4jConfig()
+		Expect(config).To(ContainSubstring("\nrootLogger.level = warn\n"))
+		Expect(config).To(ContainSubstring("\nlogger.index_search_slowlog_rolling.level = info\n"))
+		Expect(config).To(ContainSubstring("\nlogger.custom_org_elasticsearch_transport.name = org.elasticsearch.transport\nlogger.custom_org_elasticsearch_transport.level = trace\n"))
+		Expect(config).To(ContainSubstring("\nappender.rolling.strategy.max=10\n"))
+		Expect(config).To(ContainSubstring("\nappender.rolling.policies.size.size=100MB\n"))
+	})
+
+	It("should not restart the nodes for the levels of the loggers", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{
+			Level:            "warn",
+			DeprecationLevel: "error",
+			Loggers:          []api.ElasticsearchLoggerSpec{{Name: "org.elasticsearch.transport", Level: "trace"}},
+		}
+		desired := newClusterConfigMap(cluster, nil).Data
+
+		restart, _ := configChangeRestart(current, desired)
+		Expect(restart).To(Equal(configRestartNone))
+		Expect(loggerLevels(desired[log4jConfig])).To(HaveKeyWithValue("_root", "warn"))
+		Expect(loggerLevels(desired[log4jConfig])).To(HaveKeyWithValue("org.elasticsearch.deprecation", "error"))
+		Expect(loggerLevels(desired[log4jConfig])).To(HaveKeyWithValue("org.elasticsearch.transport", "trace"))
+	})
+
+	It("should restart the nodes one after the other for the appenders", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{
+			Appender: "rolling",
+			Rolling:  &api.ElasticsearchRollingAppenderSpec{MaxFileSize: "1GB"},
+		}
+
+		restart, _ := configChangeRestart(current, newClusterConfigMap(cluster, nil).Data)
+		Expect(restart).To(Equal(configRestartRolling))
+	})
+
+	It("should update the changed levels of the running nodes", func() {
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{
+			Loggers: []api.ElasticsearchLoggerSpec{{Name: "org.elasticsearch.transport", Level: "trace"}},
+		}
+		current := renderedLog4jConfig()
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{Level: "warn"}
+
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_cluster/settings": {
+				{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+			},
+		})
+		request := &ElasticsearchRequest{
+			client:  fake.NewFakeClient(pod),
+			cluster: cluster,
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.updateLoggerLevels(current, renderedLog4jConfig())).To(Succeed())
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"transient": {"logger._root": "warn", "logger.org.elasticsearch.transport": null}
+		}`)
+	})
+
+	It("should reject loggers configured by the operator or not distinct", func() {
+		cluster.Spec.Logging = &api.ElasticsearchLoggingSpec{
+			Loggers: []api.ElasticsearchLoggerSpec{{Name: "org.elasticsearch.deprecation", Level: "info"}},
+		}
+		Expect(loggingViolation(cluster)).ToNot(BeEmpty())
+
+		cluster.Spec.Logging.Loggers = []api.ElasticsearchLoggerSpec{
+			{Name: "org.elasticsearch.transport", Level: "info"},
+			{Name: "org_elasticsearch.transport", Level: "debug"},
+		}
+		Expect(loggingViolation(cluster)).ToNot(BeEmpty())
+
+		cluster.Spec.Logging.Loggers = cluster.Spec.Logging.Loggers[:1]
+		Expect(loggingViolation(cluster)).To(BeEmpty())
+	})
+})
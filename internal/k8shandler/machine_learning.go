@@ -0,0 +1,26 @@
+package k8shandler
+
+import (
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// usesMachineLearningNodes returns true if the cluster has nodes dedicated to the machine learning jobs
+func usesMachineLearningNodes(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if isMLNode(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMachineLearning returns whether the node runs machine learning jobs or an empty string
+// if the cluster has no ml nodes and all nodes keep the default
+func nodeMachineLearning(cluster *api.Elasticsearch, node api.ElasticsearchNode) string {
+	if !usesMachineLearningNodes(cluster) {
+		return ""
+	}
+	return strconv.FormatBool(isMLNode(node))
+}
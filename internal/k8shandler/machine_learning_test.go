@@ -0,0 +1,61 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Machine learning nodes", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		uuid    = "abc"
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleML}, NodeCount: 1, GenUUID: &uuid},
+				},
+			},
+		}
+	})
+
+	It("should run the machine learning jobs on the ml nodes only", func() {
+		Expect(nodeMachineLearning(cluster, cluster.Spec.Nodes[0])).To(Equal("false"))
+		Expect(nodeMachineLearning(cluster, cluster.Spec.Nodes[1])).To(Equal("true"))
+
+		envVars := newEnvVars("node", "", map[api.ElasticsearchNodeRole]bool{}, podTemplateOptions{
+			clusterName:     "elasticsearch",
+			machineLearning: nodeMachineLearning(cluster, cluster.Spec.Nodes[1]),
+		})
+		helpers.ExpectEnvVars(envVars).ToIncludeName("IS_ML").WithValue("true")
+
+		cluster.Spec.Nodes = cluster.Spec.Nodes[:1]
+		Expect(nodeMachineLearning(cluster, cluster.Spec.Nodes[0])).To(BeEmpty())
+	})
+
+	It("should run one deployment per ml node", func() {
+		request := &ElasticsearchRequest{
+			client:  fake.NewFakeClient(),
+			cluster: cluster,
+		}
+		Expect(isCoordinatingNode(cluster.Spec.Nodes[1])).To(BeFalse())
+		Expect(newLabels("elasticsearch", "node", getNodeRoleMap(cluster.Spec.Nodes[1]))).To(HaveKeyWithValue("es-node-ml", "true"))
+
+		nodes, err := request.GetNodeTypeInterface(uuid, cluster.Spec.Nodes[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0]).To(BeAssignableToTypeOf(&deploymentNode{}))
+		Expect(nodes[0].name()).To(Equal("elasticsearch-l-abc-1"))
+	})
+})
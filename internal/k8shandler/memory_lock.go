@@ -0,0 +1,51 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// podSecurityEnforceLabel is the namespace label setting the pod security admission level
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	// memoryLockCapability is required to lock the heap independently of the memlock ulimit
+	memoryLockCapability v1.Capability = "IPC_LOCK"
+)
+
+// memoryLockViolation returns the reason the pod security admission level of the namespace
+// rejects the pods of a cluster locking its memory or an empty string if it is allowed
+func (er *ElasticsearchRequest) memoryLockViolation() (string, error) {
+	cluster := er.cluster
+
+	if !cluster.Spec.MemoryLock {
+		return "", nil
+	}
+
+	namespace := &v1.Namespace{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Namespace}, namespace); err != nil {
+		return "", kverrors.Wrap(err, "failed to get namespace",
+			"namespace", cluster.Namespace)
+	}
+
+	// only the privileged level allows adding capabilities beyond the default set
+	switch level := namespace.GetLabels()[podSecurityEnforceLabel]; level {
+	case "baseline", "restricted":
+		return fmt.Sprintf("Memory lock requires the %s capability which the %s pod security level of namespace %s forbids",
+			memoryLockCapability, level, cluster.Namespace), nil
+	}
+	return "", nil
+}
+
+// newMemoryLockSecurityContext returns the security context of the Elasticsearch container
+// allowing it to lock the heap
+func newMemoryLockSecurityContext() *v1.SecurityContext {
+	return &v1.SecurityContext{
+		Capabilities: &v1.Capabilities{
+			Add: []v1.Capability{memoryLockCapability},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Memory lock", func() {
+	defer GinkgoRecover()
+
+	newRequest := func(level string) *ElasticsearchRequest {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec:       api.ElasticsearchSpec{MemoryLock: true},
+		}
+		namespace := &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "openshift-logging",
+				Labels: map[string]string{podSecurityEnforceLabel: level},
+			},
+		}
+		return &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, namespace),
+			cluster: cluster,
+		}
+	}
+
+	It("should allow locking the memory in a privileged namespace", func() {
+		Expect(newRequest("privileged").memoryLockViolation()).To(BeEmpty())
+	})
+
+	It("should reject locking the memory in a baseline namespace", func() {
+		Expect(newRequest("baseline").memoryLockViolation()).To(Equal(
+			"Memory lock requires the IPC_LOCK capability which the baseline pod security level of namespace openshift-logging forbids"))
+	})
+
+	It("should add the IPC_LOCK capability to the elasticsearch container", func() {
+		template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			memoryLock:  true,
+		})
+		Expect(template.Spec.Containers[0].SecurityContext.Capabilities.Add).To(ConsistOf(v1.Capability("IPC_LOCK")))
+	})
+})
@@ -0,0 +1,221 @@
+package k8shandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultMetadataBackupInterval = time.Hour
+	metadataBackupKey             = "metadata.json"
+	metadataChecksumAnnotation    = "elasticsearch.openshift.io/metadata-checksum"
+	// metadataSnapshotTimeFormat is appended to the name of the snapshots of the global state
+	metadataSnapshotTimeFormat = "20060102150405"
+	// maxConfigMapSize is the size limit of the data of a config map enforced by the API server
+	maxConfigMapSize = 1024 * 1024
+)
+
+// BackupClusterMetadata backs up the metadata of the cluster when the backup is due and the
+// metadata changed since the last backup, and records the result in the cluster status
+func (er *ElasticsearchRequest) BackupClusterMetadata() error {
+	cluster := er.cluster
+	spec := cluster.Spec.MetadataBackup
+
+	if spec == nil {
+		return er.updateMetadataBackupStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	interval := defaultMetadataBackupInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+
+	configMapName := metadataBackupConfigMapName(cluster)
+	now := time.Now()
+
+	previous := cluster.Status.MetadataBackup
+	if previous != nil && previous.ConfigMapName == configMapName &&
+		now.Sub(previous.LastChecked.Time) < interval {
+		return nil
+	}
+
+	status := &api.MetadataBackupStatus{
+		ConfigMapName: configMapName,
+		LastChecked:   metav1.NewTime(now),
+	}
+	if previous != nil && previous.ConfigMapName == configMapName {
+		status.Checksum = previous.Checksum
+		status.LastBackup = previous.LastBackup
+		status.Snapshot = previous.Snapshot
+	}
+
+	if err := er.backupChangedMetadata(status); err != nil {
+		er.L().Error(err, "failed to back up cluster metadata", "configmap", configMapName)
+		status.Message = elasticsearchErrorReason(err)
+	}
+
+	return er.updateMetadataBackupStatus(status)
+}
+
+// backupChangedMetadata writes the metadata to the config map and snapshots the global state of
+// the cluster unless the metadata matches the checksum of the last backup. The status is
+// updated in place on success
+func (er *ElasticsearchRequest) backupChangedMetadata(status *api.MetadataBackupStatus) error {
+	cluster := er.cluster
+	spec := cluster.Spec.MetadataBackup
+
+	metadata, err := er.esClient.GetClusterMetadata()
+	if err != nil {
+		return err
+	}
+	data, err := canonicalMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	checksum := fmt.Sprintf("%x", sha256.Sum256(data))
+	if checksum == status.Checksum {
+		return nil
+	}
+
+	if len(data) > maxConfigMapSize {
+		return kverrors.New("cluster metadata exceeds the size limit of a config map",
+			"size", len(data),
+			"limit", maxConfigMapSize)
+	}
+	if err := er.writeMetadataBackup(status.ConfigMapName, checksum, data); err != nil {
+		return err
+	}
+
+	if spec.SnapshotRepository != "" {
+		name := fmt.Sprintf("metadata-%s", status.LastChecked.UTC().Format(metadataSnapshotTimeFormat))
+		// "-*" excludes every index so the snapshot only holds the global state
+		snapshot := &estypes.CreateSnapshot{
+			Indices:            "-*",
+			IncludeGlobalState: true,
+		}
+		if err := er.esClient.CreateSnapshot(spec.SnapshotRepository, name, snapshot); err != nil {
+			return err
+		}
+		er.L().Info("Created snapshot of the cluster metadata", "repository", spec.SnapshotRepository, "snapshot", name)
+		status.Snapshot = name
+	}
+
+	backup := status.LastChecked
+	status.Checksum = checksum
+	status.LastBackup = &backup
+	return nil
+}
+
+func (er *ElasticsearchRequest) writeMetadataBackup(name, checksum string, data []byte) error {
+	cluster := er.cluster
+
+	// the config map is deliberately not owned by the cluster to outlive it
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    cluster.Labels,
+			Annotations: map[string]string{
+				metadataChecksumAnnotation: checksum,
+			},
+		},
+		Data: map[string]string{
+			metadataBackupKey: string(data),
+		},
+	}
+
+	err := er.client.Create(context.TODO(), configMap)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return kverrors.Wrap(err, "failed to create metadata backup config map",
+			"configmap", configMap.Name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.ConfigMap{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Data, configMap.Data) &&
+			current.Annotations[metadataChecksumAnnotation] == checksum {
+			return nil
+		}
+		current.Data = configMap.Data
+		current.Annotations = mergeSelectors(configMap.Annotations, current.Annotations)
+		return er.client.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update metadata backup config map",
+		"configmap", configMap.Name)
+}
+
+// canonicalMetadata encodes the metadata with sorted keys, so its checksum does not depend on the
+// order Elasticsearch returns the keys in
+func canonicalMetadata(metadata *estypes.ClusterMetadata) ([]byte, error) {
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to encode cluster metadata")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode cluster metadata")
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to encode cluster metadata")
+	}
+	return data, nil
+}
+
+func metadataBackupConfigMapName(cluster *api.Elasticsearch) string {
+	if name := cluster.Spec.MetadataBackup.ConfigMapName; name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s-metadata-backup", cluster.Name)
+}
+
+func (er *ElasticsearchRequest) updateMetadataBackupStatus(status *api.MetadataBackupStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.MetadataBackup, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.MetadataBackup, status) {
+			return nil
+		}
+
+		cluster.Status.MetadataBackup = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update metadata backup status")
+}
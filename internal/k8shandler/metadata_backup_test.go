@@ -0,0 +1,124 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Metadata backup", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		status  *api.MetadataBackupStatus
+	)
+
+	BeforeEach(func() {
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: api.ElasticsearchSpec{
+				MetadataBackup: &api.MetadataBackupSpec{
+					SnapshotRepository: "s3",
+				},
+			},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_template": {
+					{StatusCode: http.StatusOK, Body: `{"ocp-gen-app": {"order": 5, "index_patterns": ["app*"]}}`},
+					{StatusCode: http.StatusOK, Body: `{"ocp-gen-app": {"index_patterns": ["app*"], "order": 5}}`},
+				},
+				"_alias": {
+					{StatusCode: http.StatusOK, Body: `{"app-000001": {"aliases": {"app-write": {"is_write_index": true}}}}`},
+					{StatusCode: http.StatusOK, Body: `{"app-000001": {"aliases": {"app-write": {"is_write_index": true}}}}`},
+				},
+				"_cluster/settings?filter_path=persistent": {
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"enable": "all"}}}}}`},
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"enable": "all"}}}}}`},
+				},
+				"_ilm/status": {
+					{StatusCode: http.StatusBadRequest, Body: `{}`},
+					{StatusCode: http.StatusBadRequest, Body: `{}`},
+				},
+				"_snapshot/s3/metadata-20261015120000": {
+					{StatusCode: http.StatusOK, Body: `{"accepted": true}`},
+				},
+			},
+		)
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClient(),
+			cluster: cluster,
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		status = &api.MetadataBackupStatus{
+			ConfigMapName: "elasticsearch-metadata-backup",
+			LastChecked:   metav1.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC),
+		}
+	})
+
+	It("should write the metadata to the config map and snapshot the global state", func() {
+		Expect(request.backupChangedMetadata(status)).To(Succeed())
+
+		configMap := &v1.ConfigMap{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-metadata-backup", Namespace: "openshift-logging"}, configMap)).To(Succeed())
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+		Expect(configMap.Annotations).To(HaveKeyWithValue(metadataChecksumAnnotation, status.Checksum))
+		helpers.ExpectJSON(configMap.Data[metadataBackupKey]).ToEqual(`{
+			"index_templates": {"ocp-gen-app": {"index_patterns": ["app*"], "order": 5}},
+			"aliases": {"app-000001": {"aliases": {"app-write": {"is_write_index": true}}}},
+			"settings": {"persistent": {"cluster": {"routing": {"allocation": {"enable": "all"}}}}}
+		}`)
+
+		snapshotRequest, found := chatter.GetRequest("_snapshot/s3/metadata-20261015120000")
+		Expect(found).To(BeTrue())
+		snapshot := &estypes.CreateSnapshot{}
+		Expect(json.Unmarshal([]byte(snapshotRequest.Body), snapshot)).To(Succeed())
+		Expect(snapshot.Indices).To(Equal("-*"))
+		Expect(snapshot.IncludeGlobalState).To(BeTrue())
+
+		Expect(status.Checksum).NotTo(BeEmpty())
+		Expect(status.Snapshot).To(Equal("metadata-20261015120000"))
+		Expect(status.LastBackup).NotTo(BeNil())
+	})
+
+	It("should not back up metadata unchanged since the last backup", func() {
+		Expect(request.backupChangedMetadata(status)).To(Succeed())
+		Expect(request.client.Delete(context.TODO(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-metadata-backup", Namespace: "openshift-logging"},
+		})).To(Succeed())
+		checksum := status.Checksum
+
+		// the keys of the template are returned in another order
+		Expect(request.backupChangedMetadata(status)).To(Succeed())
+
+		configMap := &v1.ConfigMap{}
+		err := request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-metadata-backup", Namespace: "openshift-logging"}, configMap)
+		Expect(err).To(HaveOccurred())
+		Expect(status.Checksum).To(Equal(checksum))
+	})
+
+	It("should default the name of the config map", func() {
+		Expect(metadataBackupConfigMapName(request.cluster)).To(Equal("elasticsearch-metadata-backup"))
+
+		request.cluster.Spec.MetadataBackup.ConfigMapName = "metadata"
+		Expect(metadataBackupConfigMapName(request.cluster)).To(Equal("metadata"))
+	})
+})
@@ -0,0 +1,293 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultRemoteReindexBatchSize = 1000
+	remoteReindexWhitelistSetting = "reindex.remote.whitelist"
+)
+
+// remoteReindexWhitelist returns the host:port of the external clusters the cluster migrates or
+// reindexes from to be added to reindex.remote.whitelist
+func remoteReindexWhitelist(cluster *api.Elasticsearch, reindexes []api.ElasticsearchReindex) string {
+	hosts := sets.NewString()
+	if cluster.Spec.Migration != nil && cluster.Spec.Migration.RemoteReindex != nil {
+		if host := remoteReindexHost(cluster.Spec.Migration.RemoteReindex.Host); host != "" {
+			hosts.Insert(host)
+		}
+	}
+	for _, reindex := range reindexes {
+		if reindex.Spec.ElasticsearchName != cluster.Name || reindex.Spec.Source.Remote == nil {
+			continue
+		}
+		if host := remoteReindexHost(reindex.Spec.Source.Remote.Host); host != "" {
+			hosts.Insert(host)
+		}
+	}
+	return strings.Join(hosts.List(), ",")
+}
+
+// remoteReindexHost returns the host:port of the URL of an external cluster
+func remoteReindexHost(rawURL string) string {
+	host, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return host.Host
+}
+
+// ReconcileRemoteReindexMigration copies the spec'd indices of an external cluster one at a time
+// and tracks the progress of each reindex task in the cluster status
+func (er *ElasticsearchRequest) ReconcileRemoteReindexMigration() error {
+	cluster := er.cluster
+
+	var spec *api.RemoteReindexSpec
+	if cluster.Spec.Migration != nil {
+		spec = cluster.Spec.Migration.RemoteReindex
+	}
+	if spec == nil {
+		return er.updateMigrationStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	var previous []api.RemoteReindexStatus
+	if cluster.Status.Migration != nil {
+		previous = cluster.Status.Migration.RemoteReindex
+	}
+
+	statuses := make([]api.RemoteReindexStatus, 0, len(spec.Indices))
+	for _, index := range spec.Indices {
+		if status := getRemoteReindexStatus(index, previous); status != nil {
+			statuses = append(statuses, *status)
+			continue
+		}
+		statuses = append(statuses, api.RemoteReindexStatus{
+			Name:  index,
+			State: api.RemoteReindexStatePending,
+		})
+	}
+
+	running := false
+	for i := range statuses {
+		if statuses[i].State != api.RemoteReindexStateRunning {
+			continue
+		}
+		if err := er.refreshRemoteReindex(&statuses[i]); err != nil {
+			return err
+		}
+		if statuses[i].State == api.RemoteReindexStateRunning {
+			running = true
+		}
+	}
+
+	if !running {
+		for i := range statuses {
+			if statuses[i].State != api.RemoteReindexStatePending {
+				continue
+			}
+			if err := er.startRemoteReindex(spec, &statuses[i]); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	return er.updateMigrationStatus(&api.ElasticsearchMigrationStatus{RemoteReindex: statuses})
+}
+
+// startRemoteReindex starts the reindex task of a pending index once all nodes allow
+// reindexing from the external cluster
+func (er *ElasticsearchRequest) startRemoteReindex(spec *api.RemoteReindexSpec, status *api.RemoteReindexStatus) error {
+	message, err := er.remoteReindexWhitelistMessage(remoteReindexHost(spec.Host))
+	if err != nil || message != "" {
+		status.Message = message
+		return err
+	}
+
+	remote, message, err := er.newRemoteHost(spec.Host, spec.Secret)
+	if err != nil || message != "" {
+		status.Message = message
+		return err
+	}
+
+	batchSize := spec.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultRemoteReindexBatchSize
+	}
+
+	taskID, err := er.esClient.StartReIndex(&estypes.ReIndexRequest{
+		Conflicts: "proceed",
+		Source: estypes.ReIndexSource{
+			Remote: remote,
+			Index:  status.Name,
+			Size:   batchSize,
+		},
+		Dest: estypes.ReIndexDest{
+			Index:  status.Name,
+			OpType: "create",
+		},
+	})
+	if err != nil {
+		reason, _ := kverrors.KVs(err)[elasticsearch.ErrorReasonKey].(string)
+		if reason == "" {
+			return err
+		}
+		er.L().Error(err, "remote reindex rejected", "index", status.Name)
+		status.State = api.RemoteReindexStateFailed
+		status.Message = reason
+		return nil
+	}
+
+	now := metav1.Now()
+	status.State = api.RemoteReindexStateRunning
+	status.TaskID = taskID
+	status.StartTime = &now
+	status.Message = ""
+	return nil
+}
+
+// refreshRemoteReindex updates the progress of a running reindex task
+func (er *ElasticsearchRequest) refreshRemoteReindex(status *api.RemoteReindexStatus) error {
+	task, err := er.esClient.GetReIndexTask(status.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if task == nil {
+		status.State = api.RemoteReindexStateFailed
+		status.Message = fmt.Sprintf("Reindex task %s no longer exists", status.TaskID)
+		return nil
+	}
+
+	progress := task.Task.Status
+	if task.Response != nil {
+		progress = *task.Response
+	}
+	status.Total = progress.Total
+	status.Created = progress.Created
+	status.VersionConflicts = progress.VersionConflicts
+
+	if !task.Completed {
+		return nil
+	}
+
+	now := metav1.Now()
+	status.CompletionTime = &now
+
+	if failed, message := reindexTaskFailure(task, progress); failed {
+		status.State = api.RemoteReindexStateFailed
+		status.Message = message
+	} else {
+		status.State = api.RemoteReindexStateCompleted
+	}
+	return nil
+}
+
+// remoteReindexWhitelistMessage returns a message naming a node which does not allow
+// reindexing from the host yet or an empty string if all nodes allow it
+func (er *ElasticsearchRequest) remoteReindexWhitelistMessage(host string) (string, error) {
+	nodes, err := er.esClient.GetNodesSetting(remoteReindexWhitelistSetting)
+	if err != nil {
+		return "", err
+	}
+	for node, value := range nodes {
+		if !whitelistContains(value, host) {
+			return fmt.Sprintf("Waiting for node %s to allow reindex from %s", node, host), nil
+		}
+	}
+	return "", nil
+}
+
+// newRemoteHost returns the external cluster to reindex from with the credentials of the
+// secret or a message if the secret does not exist yet
+func (er *ElasticsearchRequest) newRemoteHost(host, secretName string) (*estypes.RemoteHost, string, error) {
+	remote := &estypes.RemoteHost{Host: host}
+	if secretName == "" {
+		return remote, "", nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Name: secretName, Namespace: er.cluster.Namespace}
+	if err := er.client.Get(context.TODO(), key, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, "", kverrors.Wrap(err, "failed to get remote reindex secret",
+				"secret", secretName)
+		}
+		return nil, fmt.Sprintf("Waiting for secret %s with the credentials of the external cluster", secretName), nil
+	}
+	remote.Username = string(secret.Data["username"])
+	remote.Password = string(secret.Data["password"])
+	return remote, "", nil
+}
+
+// reindexTaskFailure returns true and the reason if a completed reindex task failed
+func reindexTaskFailure(task *estypes.ReIndexTask, progress estypes.ReIndexStatus) (bool, string) {
+	switch {
+	case task.Error != nil:
+		reason, _ := task.Error["reason"].(string)
+		return true, reason
+	case len(progress.Failures) > 0:
+		return true, fmt.Sprintf("%d documents failed to be copied", len(progress.Failures))
+	}
+	return false, ""
+}
+
+func (er *ElasticsearchRequest) updateMigrationStatus(status *api.ElasticsearchMigrationStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.Migration, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.Migration, status) {
+			return nil
+		}
+
+		cluster.Status.Migration = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update migration status")
+}
+
+func getRemoteReindexStatus(name string, statuses []api.RemoteReindexStatus) *api.RemoteReindexStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func whitelistContains(whitelist, host string) bool {
+	for _, entry := range strings.Split(whitelist, ",") {
+		if strings.TrimSpace(entry) == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Remote reindex migration", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		spec    *api.RemoteReindexSpec
+	)
+
+	BeforeEach(func() {
+		spec = &api.RemoteReindexSpec{
+			Host:    "https://old-es.example.com:9200",
+			Secret:  "old-es",
+			Indices: []string{"app-2020.01.01"},
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "old-es", Namespace: "openshift-logging"},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("secret"),
+				},
+			}),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch",
+					Namespace: "openshift-logging",
+				},
+				Spec: api.ElasticsearchSpec{
+					Migration: &api.ElasticsearchMigrationSpec{RemoteReindex: spec},
+				},
+			},
+		}
+	})
+
+	It("should whitelist the host and port of the external cluster", func() {
+		Expect(remoteReindexWhitelist(request.cluster, nil)).To(Equal("old-es.example.com:9200"))
+	})
+
+	Describe("#startRemoteReindex", func() {
+		var status *api.RemoteReindexStatus
+
+		BeforeEach(func() {
+			status = &api.RemoteReindexStatus{Name: "app-2020.01.01", State: api.RemoteReindexStatePending}
+		})
+
+		Context("when a node does not allow reindex from the external cluster", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_nodes/settings?flat_settings=true": {
+							{StatusCode: http.StatusOK, Body: `{"nodes": {
+								"a": {"name": "elasticsearch-cdm-1", "settings": {"reindex.remote.whitelist": "old-es.example.com:9200"}},
+								"b": {"name": "elasticsearch-cdm-2", "settings": {}}
+							}}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should keep the index pending", func() {
+				Expect(request.startRemoteReindex(spec, status)).To(Succeed())
+				Expect(status.State).To(Equal(api.RemoteReindexStatePending))
+				Expect(status.Message).To(Equal("Waiting for node elasticsearch-cdm-2 to allow reindex from old-es.example.com:9200"))
+				_, found := chatter.GetRequest("_reindex?wait_for_completion=false")
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when all nodes allow reindex from the external cluster", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_nodes/settings?flat_settings=true": {
+							{StatusCode: http.StatusOK, Body: `{"nodes": {
+								"a": {"name": "elasticsearch-cdm-1", "settings": {"reindex.remote.whitelist": "old-es.example.com:9200"}}
+							}}`},
+						},
+						"_reindex?wait_for_completion=false": {
+							{StatusCode: http.StatusOK, Body: `{"task": "node-a:42"}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should start the reindex with the credentials of the secret", func() {
+				Expect(request.startRemoteReindex(spec, status)).To(Succeed())
+				Expect(status.State).To(Equal(api.RemoteReindexStateRunning))
+				Expect(status.TaskID).To(Equal("node-a:42"))
+				Expect(status.StartTime).ToNot(BeNil())
+
+				req, _ := chatter.GetRequest("_reindex?wait_for_completion=false")
+				helpers.ExpectJSON(req.Body).ToEqual(`{
+					"conflicts": "proceed",
+					"source": {
+						"remote": {"host": "https://old-es.example.com:9200", "username": "admin", "password": "secret"},
+						"index": "app-2020.01.01",
+						"size": 1000
+					},
+					"dest": {"index": "app-2020.01.01", "op_type": "create"}
+				}`)
+			})
+		})
+	})
+
+	Describe("#refreshRemoteReindex", func() {
+		var status *api.RemoteReindexStatus
+
+		BeforeEach(func() {
+			status = &api.RemoteReindexStatus{Name: "app-2020.01.01", State: api.RemoteReindexStateRunning, TaskID: "node-a:42"}
+		})
+
+		It("should record the progress of a running task", func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_tasks/node-a:42": {
+						{StatusCode: http.StatusOK, Body: `{"completed": false, "task": {"status": {"total": 100, "created": 40, "version_conflicts": 2}}}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			Expect(request.refreshRemoteReindex(status)).To(Succeed())
+			Expect(status.State).To(Equal(api.RemoteReindexStateRunning))
+			Expect(status.Total).To(Equal(int64(100)))
+			Expect(status.Created).To(Equal(int64(40)))
+			Expect(status.VersionConflicts).To(Equal(int64(2)))
+		})
+
+		It("should complete the index when the task finished without failures", func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_tasks/node-a:42": {
+						{StatusCode: http.StatusOK, Body: `{"completed": true, "task": {"status": {"total": 100, "created": 90}},
+							"response": {"total": 100, "created": 98, "version_conflicts": 2, "failures": []}}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			Expect(request.refreshRemoteReindex(status)).To(Succeed())
+			Expect(status.State).To(Equal(api.RemoteReindexStateCompleted))
+			Expect(status.Created).To(Equal(int64(98)))
+			Expect(status.CompletionTime).ToNot(BeNil())
+		})
+
+		It("should fail the index when the task no longer exists", func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_tasks/node-a:42": {
+						{StatusCode: http.StatusNotFound, Body: `{"error": {"reason": "task not found"}}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			Expect(request.refreshRemoteReindex(status)).To(Succeed())
+			Expect(status.State).To(Equal(api.RemoteReindexStateFailed))
+		})
+	})
+})
@@ -0,0 +1,265 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	restPort      = 9200
+	proxyRestPort = 60000
+	metricsPort   = 60001
+	transportPort = 9300
+)
+
+func networkPolicyEnabled(cluster *api.Elasticsearch) bool {
+	return cluster.Spec.NetworkPolicy != nil && cluster.Spec.NetworkPolicy.Enabled
+}
+
+// CreateOrUpdateNetworkPolicies ensures the NetworkPolicies restricting the REST API of the nodes
+// to the operator, the index management jobs and the declared consumers, and the transport port
+// to the nodes of the cluster and of the clusters using it as remote cluster. The created policies
+// are recorded in the status and deleted once they are disabled
+func (er *ElasticsearchRequest) CreateOrUpdateNetworkPolicies() error {
+	cluster := er.cluster
+
+	if !networkPolicyEnabled(cluster) && len(cluster.Status.NetworkPolicies) == 0 {
+		return nil
+	}
+
+	desired := map[string]*networking.NetworkPolicy{}
+	if networkPolicyEnabled(cluster) {
+		remoteClients, err := er.getRemoteClusterClients()
+		if err != nil {
+			return err
+		}
+		desired = newNetworkPolicies(cluster, remoteClients)
+	}
+
+	names := []string{}
+	for name, policy := range desired {
+		cluster.AddOwnerRefTo(policy)
+		if err := er.createOrUpdateNetworkPolicy(policy); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range cluster.Status.NetworkPolicies {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+
+		er.L().Info("Deleting network policy of the cluster", "networkpolicy", name)
+		policy := &networking.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}}
+		if err := er.client.Delete(context.TODO(), policy); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete network policy",
+				"networkpolicy", name)
+		}
+	}
+
+	return er.updateNetworkPoliciesStatus(names)
+}
+
+func (er *ElasticsearchRequest) updateNetworkPoliciesStatus(names []string) error {
+	cluster := er.cluster
+	if len(names) == 0 {
+		names = nil
+	}
+
+	if reflect.DeepEqual(cluster.Status.NetworkPolicies, names) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.NetworkPolicies, names) {
+			return nil
+		}
+
+		cluster.Status.NetworkPolicies = names
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update network policies status")
+}
+
+// getRemoteClusterClients returns the names of the managed clusters in the namespace of the
+// cluster which declare it as remote cluster. Their nodes connect to its transport port
+func (er *ElasticsearchRequest) getRemoteClusterClients() ([]string, error) {
+	cluster := er.cluster
+
+	clusters := &api.ElasticsearchList{}
+	if err := er.client.List(context.TODO(), clusters, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list elasticsearch clusters",
+			"namespace", cluster.Namespace)
+	}
+
+	names := []string{}
+	for _, other := range clusters.Items {
+		if other.Name == cluster.Name {
+			continue
+		}
+		for _, remote := range other.Spec.RemoteClusters {
+			if remote.Elasticsearch == cluster.Name {
+				names = append(names, other.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (er *ElasticsearchRequest) createOrUpdateNetworkPolicy(policy *networking.NetworkPolicy) error {
+	err := er.client.Create(context.TODO(), policy)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(kverrors.Root(err)) {
+		return kverrors.Wrap(err, "failed to create network policy",
+			"networkpolicy", policy.Name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &networking.NetworkPolicy{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Spec, policy.Spec) {
+			return nil
+		}
+
+		current.Spec = policy.Spec
+		return er.client.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update network policy",
+		"networkpolicy", policy.Name)
+}
+
+// newNetworkPolicies returns the policies of the REST API and of the transport port of the nodes
+// by name. The metrics of the proxy stay reachable from anywhere for the monitoring stack. The
+// nodes of the remoteClients connect to the transport port for cross-cluster search and replication
+func newNetworkPolicies(cluster *api.Elasticsearch, remoteClients []string) map[string]*networking.NetworkPolicy {
+	nodes := metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"cluster-name": cluster.Name,
+			"component":    "elasticsearch",
+		},
+	}
+
+	restPeers := []networking.NetworkPolicyPeer{
+		{PodSelector: nodes.DeepCopy()},
+		{
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"name": "elasticsearch-operator"}},
+			NamespaceSelector: &metav1.LabelSelector{},
+		},
+		{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"component": "indexManagement"}},
+		},
+	}
+	for _, consumer := range cluster.Spec.NetworkPolicy.Consumers {
+		restPeers = append(restPeers, newNetworkPolicyPeer(consumer))
+	}
+
+	restPorts := []int{restPort, proxyRestPort}
+	for _, service := range cluster.Spec.LegacyClientServices {
+		restPorts = append(restPorts, int(service.Port))
+	}
+
+	transportPeers := []networking.NetworkPolicyPeer{{PodSelector: nodes.DeepCopy()}}
+	for _, name := range remoteClients {
+		transportPeers = append(transportPeers, networking.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster-name": name,
+					"component":    "elasticsearch",
+				},
+			},
+		})
+	}
+
+	policies := map[string]*networking.NetworkPolicy{}
+	policies[fmt.Sprintf("%s-http", cluster.Name)] = newNetworkPolicy(cluster, fmt.Sprintf("%s-http", cluster.Name), nodes,
+		[]networking.NetworkPolicyIngressRule{
+			{
+				From:  restPeers,
+				Ports: newNetworkPolicyPorts(restPorts...),
+			},
+			{
+				Ports: newNetworkPolicyPorts(metricsPort),
+			},
+		})
+	policies[fmt.Sprintf("%s-transport", cluster.Name)] = newNetworkPolicy(cluster, fmt.Sprintf("%s-transport", cluster.Name), nodes,
+		[]networking.NetworkPolicyIngressRule{
+			{
+				From:  transportPeers,
+				Ports: newNetworkPolicyPorts(transportPort),
+			},
+		})
+	return policies
+}
+
+func newNetworkPolicy(cluster *api.Elasticsearch, name string, selector metav1.LabelSelector, ingress []networking.NetworkPolicyIngressRule) *networking.NetworkPolicy {
+	return &networking.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: networking.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				"cluster-name": cluster.Name,
+				"component":    "elasticsearch",
+			},
+		},
+		Spec: networking.NetworkPolicySpec{
+			PodSelector: selector,
+			Ingress:     ingress,
+			PolicyTypes: []networking.PolicyType{networking.PolicyTypeIngress},
+		},
+	}
+}
+
+// newNetworkPolicyPeer returns the peer of a consumer. A consumer without a namespace selector is
+// restricted to the namespace of the cluster, one without a pod selector allows all pods
+func newNetworkPolicyPeer(consumer api.NetworkPolicyPeerSpec) networking.NetworkPolicyPeer {
+	peer := networking.NetworkPolicyPeer{
+		NamespaceSelector: consumer.NamespaceSelector.DeepCopy(),
+		PodSelector:       consumer.PodSelector.DeepCopy(),
+	}
+	if peer.NamespaceSelector == nil && peer.PodSelector == nil {
+		peer.PodSelector = &metav1.LabelSelector{}
+	}
+	return peer
+}
+
+func newNetworkPolicyPorts(ports ...int) []networking.NetworkPolicyPort {
+	protocol := v1.ProtocolTCP
+	policyPorts := []networking.NetworkPolicyPort{}
+	for _, port := range ports {
+		port := intstr.FromInt(port)
+		policyPorts = append(policyPorts, networking.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &port,
+		})
+	}
+	return policyPorts
+}
@@ -0,0 +1,167 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Network policies", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		request *ElasticsearchRequest
+	)
+
+	listPolicies := func() map[string]networking.NetworkPolicy {
+		list := &networking.NetworkPolicyList{}
+		Expect(request.client.List(context.TODO(), list)).To(Succeed())
+
+		policies := map[string]networking.NetworkPolicy{}
+		for _, policy := range list.Items {
+			policies[policy.Name] = policy
+		}
+		return policies
+	}
+
+	ports := func(rule networking.NetworkPolicyIngressRule) []intstr.IntOrString {
+		result := []intstr.IntOrString{}
+		for _, port := range rule.Ports {
+			result = append(result, *port.Port)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				NetworkPolicy: &api.NetworkPolicySpec{
+					Enabled: true,
+					Consumers: []api.NetworkPolicyPeerSpec{
+						{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"component": "kibana"}}},
+						{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"logging": "collector"}}},
+					},
+				},
+			},
+		}
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+	})
+
+	It("should restrict the REST API to the operator, the index management jobs and the consumers", func() {
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+
+		policies := listPolicies()
+		Expect(policies).To(HaveKey("elasticsearch-http"))
+
+		http := policies["elasticsearch-http"]
+		Expect(http.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"cluster-name": "elasticsearch", "component": "elasticsearch"}))
+		Expect(http.Spec.Ingress).To(HaveLen(2))
+		Expect(ports(http.Spec.Ingress[0])).To(ConsistOf(intstr.FromInt(9200), intstr.FromInt(60000)))
+		Expect(ports(http.Spec.Ingress[1])).To(ConsistOf(intstr.FromInt(60001)))
+		Expect(http.Spec.Ingress[1].From).To(BeEmpty())
+
+		peers := http.Spec.Ingress[0].From
+		Expect(peers).To(HaveLen(5))
+		Expect(peers[1].PodSelector.MatchLabels).To(Equal(map[string]string{"name": "elasticsearch-operator"}))
+		Expect(peers[1].NamespaceSelector).To(Equal(&metav1.LabelSelector{}))
+		Expect(peers[2].PodSelector.MatchLabels).To(Equal(map[string]string{"component": "indexManagement"}))
+		Expect(peers[3].NamespaceSelector).To(BeNil())
+		Expect(peers[3].PodSelector.MatchLabels).To(Equal(map[string]string{"component": "kibana"}))
+		Expect(peers[4].NamespaceSelector.MatchLabels).To(Equal(map[string]string{"logging": "collector"}))
+		Expect(peers[4].PodSelector).To(BeNil())
+	})
+
+	It("should restrict the transport port to the nodes of the cluster", func() {
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+
+		policies := listPolicies()
+		Expect(policies).To(HaveKey("elasticsearch-transport"))
+
+		transport := policies["elasticsearch-transport"]
+		Expect(transport.Spec.Ingress).To(HaveLen(1))
+		Expect(ports(transport.Spec.Ingress[0])).To(ConsistOf(intstr.FromInt(9300)))
+		Expect(transport.Spec.Ingress[0].From).To(HaveLen(1))
+		Expect(transport.Spec.Ingress[0].From[0].PodSelector.MatchLabels).To(Equal(transport.Spec.PodSelector.MatchLabels))
+		Expect(transport.Spec.Ingress[0].From[0].NamespaceSelector).To(BeNil())
+	})
+
+	It("should allow the nodes of the clusters using the cluster as remote cluster to connect to the transport port", func() {
+		search := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RemoteClusters: []api.RemoteClusterSpec{{Name: "logs", Elasticsearch: "elasticsearch"}},
+			},
+		}
+		other := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RemoteClusters: []api.RemoteClusterSpec{{Name: "logs", Elasticsearch: "search"}},
+			},
+		}
+		Expect(request.client.Create(context.TODO(), search)).To(Succeed())
+		Expect(request.client.Create(context.TODO(), other)).To(Succeed())
+
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+
+		transport := listPolicies()["elasticsearch-transport"]
+		Expect(transport.Spec.Ingress[0].From).To(HaveLen(2))
+		Expect(transport.Spec.Ingress[0].From[1].PodSelector.MatchLabels).To(Equal(map[string]string{"cluster-name": "search", "component": "elasticsearch"}))
+		Expect(transport.Spec.Ingress[0].From[1].NamespaceSelector).To(BeNil())
+	})
+
+	It("should update the policies for changed consumers", func() {
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+
+		cluster.Spec.NetworkPolicy.Consumers = cluster.Spec.NetworkPolicy.Consumers[:1]
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+
+		http := listPolicies()["elasticsearch-http"]
+		Expect(http.Spec.Ingress[0].From).To(HaveLen(4))
+	})
+
+	It("should delete the policies once disabled", func() {
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+		Expect(listPolicies()).To(HaveLen(2))
+
+		cluster.Spec.NetworkPolicy.Enabled = false
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+		Expect(listPolicies()).To(BeEmpty())
+		Expect(cluster.Status.NetworkPolicies).To(BeEmpty())
+	})
+
+	It("should only touch the policies recorded in the status", func() {
+		policy := newNetworkPolicy(cluster, "elasticsearch-custom", metav1.LabelSelector{}, nil)
+		Expect(request.client.Create(context.TODO(), policy)).To(Succeed())
+
+		cluster.Spec.NetworkPolicy.Enabled = false
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+		Expect(listPolicies()).To(HaveKey("elasticsearch-custom"))
+
+		cluster.Spec.NetworkPolicy.Enabled = true
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+		Expect(cluster.Status.NetworkPolicies).To(Equal([]string{"elasticsearch-http", "elasticsearch-transport"}))
+
+		cluster.Spec.NetworkPolicy.Enabled = false
+		Expect(request.CreateOrUpdateNetworkPolicies()).To(Succeed())
+		policies := listPolicies()
+		Expect(policies).To(HaveLen(1))
+		Expect(policies).To(HaveKey("elasticsearch-custom"))
+	})
+})
@@ -0,0 +1,82 @@
+package k8shandler
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	apps "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// updateNodeReplicas records the desired and ready replicas of the deployment or statefulset of
+// every node and reports the pods which are not ready in the eo_es_node_replica_gap metric
+func (er *ElasticsearchRequest) updateNodeReplicas(status *api.ElasticsearchStatus) error {
+	cluster := er.cluster
+	gaps := map[string]int32{}
+
+	for i := range status.Nodes {
+		node := &status.Nodes[i]
+
+		desired, ready, name, err := er.workloadReplicas(node)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+
+		node.DesiredReplicas = desired
+		node.ReadyReplicas = ready
+		gap := desired - ready
+		if gap < 0 {
+			gap = 0
+		}
+		gaps[name] = gap
+	}
+
+	metrics.SetNodeReplicaGaps(cluster.Name, cluster.Namespace, gaps)
+	return nil
+}
+
+// workloadReplicas returns the desired and ready replicas and the name of the workload of the
+// node. The name is empty if the workload does not exist
+func (er *ElasticsearchRequest) workloadReplicas(node *api.ElasticsearchNodeStatus) (int32, int32, string, error) {
+	namespace := er.cluster.Namespace
+
+	if node.StatefulSetName != "" {
+		statefulSet := &apps.StatefulSet{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: node.StatefulSetName, Namespace: namespace}, statefulSet); err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, 0, "", nil
+			}
+			return 0, 0, "", kverrors.Wrap(err, "failed to get statefulset",
+				"statefulset", node.StatefulSetName)
+		}
+		return desiredReplicas(statefulSet.Spec.Replicas), statefulSet.Status.ReadyReplicas, statefulSet.Name, nil
+	}
+
+	if node.DeploymentName != "" {
+		deployment := &apps.Deployment{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: node.DeploymentName, Namespace: namespace}, deployment); err != nil {
+			if apierrors.IsNotFound(err) {
+				return 0, 0, "", nil
+			}
+			return 0, 0, "", kverrors.Wrap(err, "failed to get deployment",
+				"deployment", node.DeploymentName)
+		}
+		return desiredReplicas(deployment.Spec.Replicas), deployment.Status.ReadyReplicas, deployment.Name, nil
+	}
+
+	return 0, 0, "", nil
+}
+
+// desiredReplicas returns the replicas of a workload which defaults to one replica
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
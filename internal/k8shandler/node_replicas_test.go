@@ -0,0 +1,51 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Node replicas", func() {
+	defer GinkgoRecover()
+
+	It("should record the desired and ready replicas of the workloads of the nodes", func() {
+		replicas := int32(3)
+		client := fake.NewFakeClient(
+			&apps.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1", Namespace: "openshift-logging"},
+				Status:     apps.DeploymentStatus{ReadyReplicas: 0},
+			},
+			&apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc", Namespace: "openshift-logging"},
+				Spec:       apps.StatefulSetSpec{Replicas: &replicas},
+				Status:     apps.StatefulSetStatus{ReadyReplicas: 2},
+			},
+		)
+		er := &ElasticsearchRequest{
+			client: client,
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			},
+		}
+		status := &api.ElasticsearchStatus{
+			Nodes: []api.ElasticsearchNodeStatus{
+				{DeploymentName: "elasticsearch-cdm-abc-1"},
+				{StatefulSetName: "elasticsearch-cd-abc"},
+				{DeploymentName: "elasticsearch-cdm-abc-2"},
+			},
+		}
+
+		Expect(er.updateNodeReplicas(status)).To(Succeed())
+
+		Expect(status.Nodes[0].DesiredReplicas).To(BeEquivalentTo(1))
+		Expect(status.Nodes[0].ReadyReplicas).To(BeEquivalentTo(0))
+		Expect(status.Nodes[1].DesiredReplicas).To(BeEquivalentTo(3))
+		Expect(status.Nodes[1].ReadyReplicas).To(BeEquivalentTo(2))
+		Expect(status.Nodes[2].DesiredReplicas).To(BeZero())
+	})
+})
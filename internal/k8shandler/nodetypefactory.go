@@ -13,7 +13,7 @@ import (
 type NodeTypeInterface interface {
 	state() api.ElasticsearchNodeStatus // this will get the current -- used for status
 	updateReference(node NodeTypeInterface)
-	populateReference(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client)
+	populateReference(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client) error
 
 	create() error // this will create the node in the case where it is new
 	isMissing() bool
@@ -33,7 +33,7 @@ type NodeTypeInterface interface {
 type NodeTypeFactory func(name, namespace string) NodeTypeInterface
 
 // this can potentially return a list if we have replicas > 1 for a data node
-func (er *ElasticsearchRequest) GetNodeTypeInterface(uuid string, node api.ElasticsearchNode) []NodeTypeInterface {
+func (er *ElasticsearchRequest) GetNodeTypeInterface(uuid string, node api.ElasticsearchNode) ([]NodeTypeInterface, error) {
 	nodes := []NodeTypeInterface{}
 
 	roleMap := getNodeRoleMap(node)
@@ -41,24 +41,40 @@ func (er *ElasticsearchRequest) GetNodeTypeInterface(uuid string, node api.Elast
 	// common spec => cluster.Spec.Spec
 	nodeName := fmt.Sprintf("%s-%s", er.cluster.Name, getNodeSuffix(uuid, roleMap))
 
-	// if we have a data node then we need to create one deployment per replica
-	if isDataNode(node) {
+	// if we have a data, coordinating, ingest or ml node then we need to create one deployment per replica
+	if !isStatefulSetNode(node) {
 		// for loop from 1 to replica as replicaIndex
 		//   it is 1 instead of 0 because of legacy code
 		for replicaIndex := int32(1); replicaIndex <= node.NodeCount; replicaIndex++ {
 			dataNodeName := addDataNodeSuffix(nodeName, replicaIndex)
-			node := newDeploymentNode(dataNodeName, node, er.cluster, roleMap, er.client, er.esClient)
+			node, err := newDeploymentNode(dataNodeName, node, er.cluster, roleMap, er.client, er.esClient)
+			if err != nil {
+				return nil, err
+			}
 			nodes = append(nodes, node)
 		}
 	} else {
-		node := newStatefulSetNode(nodeName, node, er.cluster, roleMap, er.client, er.esClient)
-		nodes = append(nodes, node)
+		// nodes with many pods may be split into several statefulsets
+		for _, shard := range statefulSetShards(nodeName, node) {
+			shardNode := node
+			shardNode.NodeCount = shard.replicas
+			node, err := newStatefulSetNode(shard.name, shardNode, er.cluster, roleMap, er.client, er.esClient)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
 	}
 
-	return nodes
+	return nodes, nil
 }
 
 func getNodeSuffix(uuid string, roleMap map[api.ElasticsearchNodeRole]bool) string {
+	return fmt.Sprintf("%s-%s", getRoleSuffix(roleMap), uuid)
+}
+
+// getRoleSuffix returns the letters of the roles of a node
+func getRoleSuffix(roleMap map[api.ElasticsearchNodeRole]bool) string {
 	suffix := ""
 	if roleMap[api.ElasticsearchRoleClient] {
 		suffix = fmt.Sprintf("%s%s", suffix, "c")
@@ -72,7 +88,20 @@ func getNodeSuffix(uuid string, roleMap map[api.ElasticsearchNodeRole]bool) stri
 		suffix = fmt.Sprintf("%s%s", suffix, "m")
 	}
 
-	return fmt.Sprintf("%s-%s", suffix, uuid)
+	if roleMap[api.ElasticsearchRoleIngest] {
+		suffix = fmt.Sprintf("%s%s", suffix, "i")
+	}
+
+	// "m" is taken by the master role
+	if roleMap[api.ElasticsearchRoleML] {
+		suffix = fmt.Sprintf("%s%s", suffix, "l")
+	}
+
+	if roleMap[api.ElasticsearchRoleVotingOnly] {
+		suffix = fmt.Sprintf("%s%s", suffix, "v")
+	}
+
+	return suffix
 }
 
 func addDataNodeSuffix(nodeName string, replicaNumber int32) string {
@@ -80,21 +109,25 @@ func addDataNodeSuffix(nodeName string, replicaNumber int32) string {
 }
 
 // newDeploymentNode constructs deploymentNode struct for data nodes
-func newDeploymentNode(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, esClient elasticsearch.Client) NodeTypeInterface {
+func newDeploymentNode(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, esClient elasticsearch.Client) (NodeTypeInterface, error) {
 	deploymentNode := deploymentNode{}
 
-	deploymentNode.populateReference(nodeName, node, cluster, roleMap, int32(1), client, esClient)
+	if err := deploymentNode.populateReference(nodeName, node, cluster, roleMap, int32(1), client, esClient); err != nil {
+		return nil, err
+	}
 
-	return &deploymentNode
+	return &deploymentNode, nil
 }
 
-// newStatefulSetNode constructs statefulSetNode struct for non-data nodes
-func newStatefulSetNode(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, esClient elasticsearch.Client) NodeTypeInterface {
+// newStatefulSetNode constructs statefulSetNode struct for master nodes and data nodes migrated to a StatefulSet
+func newStatefulSetNode(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, esClient elasticsearch.Client) (NodeTypeInterface, error) {
 	statefulSetNode := statefulSetNode{}
 
-	statefulSetNode.populateReference(nodeName, node, cluster, roleMap, node.NodeCount, client, esClient)
+	if err := statefulSetNode.populateReference(nodeName, node, cluster, roleMap, node.NodeCount, client, esClient); err != nil {
+		return nil, err
+	}
 
-	return &statefulSetNode
+	return &statefulSetNode, nil
 }
 
 func containsNodeTypeInterface(node NodeTypeInterface, list []NodeTypeInterface) (int, bool) {
@@ -0,0 +1,133 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	operatorCredentialsUsernameKey = "username"
+	operatorCredentialsPasswordKey = "password"
+	operatorCredentialsAPIKeyKey   = "apiKey"
+)
+
+// ReconcileOperatorCredentials sets the credentials the operator authenticates to the cluster with
+// from their secret. Without credentials the operator authenticates with its SA token and falls
+// back to its client certificate. Credentials missing from the secret are reported by the
+// InvalidCredentials condition
+func (er *ElasticsearchRequest) ReconcileOperatorCredentials() error {
+	cluster := er.cluster
+	spec := operatorCredentials(cluster)
+
+	if spec == nil {
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		return updateInvalidCredentialsCondition(cluster, v1.ConditionFalse, "", er.client)
+	}
+
+	secret, err := er.getOperatorCredentialsSecret(spec)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		// the missing secret is reported by the Blocked condition
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		return nil
+	}
+
+	authorization, violation := operatorAuthorization(spec, secret)
+	if violation != "" {
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		if err := updateInvalidCredentialsCondition(cluster, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set credentials status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid operator credentials of the cluster",
+			"reason", violation)
+	}
+
+	elasticsearch.SetCredentials(cluster.Name, cluster.Namespace, authorization)
+	return updateInvalidCredentialsCondition(cluster, v1.ConditionFalse, "", er.client)
+}
+
+// loadOperatorCredentials sets the credentials the operator authenticates to the cluster with
+// for the requests of the reconcilers of other resources, which may run before the cluster is
+// reconciled. Invalid credentials are left to ReconcileOperatorCredentials to report
+func (er *ElasticsearchRequest) loadOperatorCredentials() error {
+	cluster := er.cluster
+	spec := operatorCredentials(cluster)
+
+	if spec == nil {
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		return nil
+	}
+
+	secret, err := er.getOperatorCredentialsSecret(spec)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		return nil
+	}
+
+	authorization, violation := operatorAuthorization(spec, secret)
+	if violation != "" {
+		elasticsearch.ForgetCredentials(cluster.Name, cluster.Namespace)
+		return nil
+	}
+
+	elasticsearch.SetCredentials(cluster.Name, cluster.Namespace, authorization)
+	return nil
+}
+
+// getOperatorCredentialsSecret returns the secret of the credentials or nil if it does not exist
+func (er *ElasticsearchRequest) getOperatorCredentialsSecret(spec *api.OperatorCredentialsSpec) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	name := spec.SecretRef.Name
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: er.cluster.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get operator credentials secret",
+			"secret", name)
+	}
+	return secret, nil
+}
+
+func operatorCredentials(cluster *api.Elasticsearch) *api.OperatorCredentialsSpec {
+	if cluster.Spec.Security == nil {
+		return nil
+	}
+	return cluster.Spec.Security.OperatorCredentials
+}
+
+// operatorAuthorization returns the Authorization header value of the credentials in the secret or
+// the reason they are invalid
+func operatorAuthorization(spec *api.OperatorCredentialsSpec, secret *v1.Secret) (string, string) {
+	switch spec.Type {
+	case api.OperatorCredentialsBasic:
+		username := string(secret.Data[operatorCredentialsUsernameKey])
+		password := string(secret.Data[operatorCredentialsPasswordKey])
+		if username == "" || password == "" {
+			return "", fmt.Sprintf("Secret %q must hold the %s and %s keys for Basic credentials",
+				secret.Name, operatorCredentialsUsernameKey, operatorCredentialsPasswordKey)
+		}
+		return elasticsearch.BasicAuthorization(username, password), ""
+
+	case api.OperatorCredentialsAPIKey:
+		apiKey := string(secret.Data[operatorCredentialsAPIKeyKey])
+		if apiKey == "" {
+			return "", fmt.Sprintf("Secret %q must hold the %s key for APIKey credentials",
+				secret.Name, operatorCredentialsAPIKeyKey)
+		}
+		return elasticsearch.APIKeyAuthorization(apiKey), ""
+	}
+
+	return "", fmt.Sprintf("Unsupported operator credentials type %q", spec.Type)
+}
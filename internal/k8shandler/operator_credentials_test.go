@@ -0,0 +1,48 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Operator credentials", func() {
+	defer GinkgoRecover()
+
+	var secret *v1.Secret
+
+	BeforeEach(func() {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "operator-credentials", Namespace: "openshift-logging"},
+			Data: map[string][]byte{
+				"username": []byte("elastic"),
+				"password": []byte("changeme"),
+				"apiKey":   []byte("VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="),
+			},
+		}
+	})
+
+	It("should authenticate with the username and password of Basic credentials", func() {
+		spec := &api.OperatorCredentialsSpec{Type: api.OperatorCredentialsBasic}
+		authorization, violation := operatorAuthorization(spec, secret)
+		Expect(violation).To(BeEmpty())
+		Expect(authorization).To(Equal("Basic ZWxhc3RpYzpjaGFuZ2VtZQ=="))
+	})
+
+	It("should authenticate with the encoded key of APIKey credentials", func() {
+		spec := &api.OperatorCredentialsSpec{Type: api.OperatorCredentialsAPIKey}
+		authorization, violation := operatorAuthorization(spec, secret)
+		Expect(violation).To(BeEmpty())
+		Expect(authorization).To(Equal("ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="))
+	})
+
+	It("should report credentials missing from the secret", func() {
+		delete(secret.Data, "password")
+		spec := &api.OperatorCredentialsSpec{Type: api.OperatorCredentialsBasic}
+		_, violation := operatorAuthorization(spec, secret)
+		Expect(violation).To(Equal(`Secret "operator-credentials" must hold the username and password keys for Basic credentials`))
+	})
+})
@@ -0,0 +1,60 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/version"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// reconcileSchemaVersion is the version of the reconcile logic. Increase it whenever the resources
+// or settings the operator reconciles change in a way users could notice
+const reconcileSchemaVersion int32 = 1
+
+// RecordOperatorVersion records the version of the operator reconciling the cluster in its status
+// and emits an event when another operator version takes over the cluster
+func (er *ElasticsearchRequest) RecordOperatorVersion() error {
+	cluster := er.cluster
+	previous := cluster.Status.Operator
+
+	if previous != nil && previous.Version == version.Version && previous.ReconcileSchemaVersion == reconcileSchemaVersion {
+		return nil
+	}
+
+	status := &api.OperatorVersionStatus{
+		Version:                version.Version,
+		ReconcileSchemaVersion: reconcileSchemaVersion,
+		Since:                  metav1.Now(),
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Operator = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update operator version status")
+	}
+
+	previousVersion := "an unknown version"
+	if previous != nil {
+		previousVersion = fmt.Sprintf("version %s (reconcile schema %d)", previous.Version, previous.ReconcileSchemaVersion)
+	}
+	message := fmt.Sprintf("Operator version %s (reconcile schema %d) took over the cluster from %s",
+		status.Version, status.ReconcileSchemaVersion, previousVersion)
+
+	er.L().Info(message)
+	if er.recorder != nil {
+		er.recorder.Event(cluster, v1.EventTypeNormal, "OperatorVersionChanged", message)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Operator version", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster  *api.Elasticsearch
+		recorder *record.FakeRecorder
+	)
+
+	newRequest := func() *ElasticsearchRequest {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		return &ElasticsearchRequest{
+			client:   fake.NewFakeClientWithScheme(s, cluster),
+			cluster:  cluster,
+			recorder: recorder,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+		recorder = record.NewFakeRecorder(5)
+	})
+
+	It("should record the version of the operator taking over the cluster", func() {
+		er := newRequest()
+		Expect(er.RecordOperatorVersion()).To(Succeed())
+
+		current := &api.Elasticsearch{}
+		Expect(er.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, current)).To(Succeed())
+		Expect(current.Status.Operator).ToNot(BeNil())
+		Expect(current.Status.Operator.Version).To(Equal(version.Version))
+		Expect(current.Status.Operator.ReconcileSchemaVersion).To(Equal(reconcileSchemaVersion))
+		Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf(
+			"Normal OperatorVersionChanged Operator version %s (reconcile schema %d) took over the cluster from an unknown version",
+			version.Version, reconcileSchemaVersion))))
+	})
+
+	It("should name the previous version of the operator", func() {
+		cluster.Status.Operator = &api.OperatorVersionStatus{Version: "4.6.0", ReconcileSchemaVersion: 0}
+
+		Expect(newRequest().RecordOperatorVersion()).To(Succeed())
+		Expect(cluster.Status.Operator.Version).To(Equal(version.Version))
+		Expect(recorder.Events).To(Receive(HaveSuffix("from version 4.6.0 (reconcile schema 0)")))
+	})
+
+	It("should not update the status while the version is unchanged", func() {
+		since := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		cluster.Status.Operator = &api.OperatorVersionStatus{
+			Version:                version.Version,
+			ReconcileSchemaVersion: reconcileSchemaVersion,
+			Since:                  since,
+		}
+
+		Expect(newRequest().RecordOperatorVersion()).To(Succeed())
+		Expect(cluster.Status.Operator.Since).To(Equal(since))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
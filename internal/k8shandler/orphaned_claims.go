@@ -0,0 +1,184 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultOrphanedClaimGracePeriod = 24 * time.Hour
+	// orphanedSinceAnnotation records since when a claim of the cluster is unused
+	orphanedSinceAnnotation = "logging.openshift.io/orphaned-since"
+)
+
+// CollectOrphanedClaims deletes the claims labeled for the cluster which no node used for the
+// grace period and records an event for each deleted claim
+func (er *ElasticsearchRequest) CollectOrphanedClaims() error {
+	cluster := er.cluster
+	spec := cluster.Spec.OrphanedClaimCollection
+	if spec == nil {
+		return nil
+	}
+
+	gracePeriod := defaultOrphanedClaimGracePeriod
+	if spec.GracePeriod != nil && spec.GracePeriod.Duration > 0 {
+		gracePeriod = spec.GracePeriod.Duration
+	}
+
+	claims := &v1.PersistentVolumeClaimList{}
+	if err := er.client.List(context.TODO(), claims, client.InNamespace(cluster.Namespace), client.MatchingLabels{"logging-cluster": cluster.Name}); err != nil {
+		return kverrors.Wrap(err, "failed to list PVCs",
+			"cluster", cluster.Name)
+	}
+	if len(claims.Items) == 0 {
+		return nil
+	}
+
+	used, statefulSetPrefixes, err := er.usedClaims()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		orphaned := !used.Has(claim.Name) && !hasAnyPrefix(claim.Name, statefulSetPrefixes)
+		if err := er.collectOrphanedClaim(claim, orphaned, gracePeriod, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectOrphanedClaim records since when the claim is unused and deletes it after the grace period
+func (er *ElasticsearchRequest) collectOrphanedClaim(claim *v1.PersistentVolumeClaim, orphaned bool, gracePeriod time.Duration, now time.Time) error {
+	since, annotated := claim.Annotations[orphanedSinceAnnotation]
+
+	if !orphaned {
+		if !annotated {
+			return nil
+		}
+		delete(claim.Annotations, orphanedSinceAnnotation)
+		return kverrors.Wrap(er.client.Update(context.TODO(), claim), "failed to update PVC",
+			"claim", claim.Name)
+	}
+
+	orphanedSince, err := time.Parse(time.RFC3339, since)
+	if !annotated || err != nil {
+		er.L().Info("Found PVC no node uses", "pvc", claim.Name, "grace_period", gracePeriod.String())
+		if claim.Annotations == nil {
+			claim.Annotations = map[string]string{}
+		}
+		claim.Annotations[orphanedSinceAnnotation] = now.UTC().Format(time.RFC3339)
+		return kverrors.Wrap(er.client.Update(context.TODO(), claim), "failed to update PVC",
+			"claim", claim.Name)
+	}
+
+	if now.Sub(orphanedSince) < gracePeriod {
+		return nil
+	}
+
+	er.L().Info("Deleting PVC no node used", "pvc", claim.Name, "orphaned_since", since)
+	if err := er.client.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete PVC",
+			"claim", claim.Name)
+	}
+	if er.recorder != nil {
+		er.recorder.Event(er.cluster, v1.EventTypeNormal, "OrphanedClaimDeleted",
+			fmt.Sprintf("Deleted PVC %s which no node used since %s", claim.Name, since))
+	}
+	return nil
+}
+
+// usedClaims returns the claims used by the nodes of the cluster and the prefixes of the claims of
+// their StatefulSets. The claims of all ordinals of a StatefulSet are used to keep the claims of
+// scaled down pods according to the reclaim policy of the node
+func (er *ElasticsearchRequest) usedClaims() (sets.String, []string, error) {
+	cluster := er.cluster
+	used := sets.NewString()
+	prefixes := []string{}
+	labels := client.MatchingLabels{"cluster-name": cluster.Name}
+
+	// the claims of the nodes not created yet
+	for _, node := range cluster.Spec.Nodes {
+		if node.GenUUID == nil {
+			continue
+		}
+		nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+		if isStatefulSetNode(node) {
+			// the pods of data nodes get their own claim, the other pods share the claim of the node
+			for _, shard := range statefulSetShards(nodeName, node) {
+				prefixes = append(prefixes, fmt.Sprintf("%s-%s-", storageVolumeName, shard.name))
+				used.Insert(fmt.Sprintf("%s-%s", cluster.Name, shard.name))
+			}
+			continue
+		}
+		for replicaIndex := int32(1); replicaIndex <= node.NodeCount; replicaIndex++ {
+			used.Insert(fmt.Sprintf("%s-%s", cluster.Name, addDataNodeSuffix(nodeName, replicaIndex)))
+		}
+	}
+
+	deployments := &apps.DeploymentList{}
+	if err := er.client.List(context.TODO(), deployments, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to list deployments",
+			"cluster", cluster.Name)
+	}
+	for _, deployment := range deployments.Items {
+		used.Insert(volumeClaimNames(deployment.Spec.Template.Spec.Volumes)...)
+	}
+
+	statefulSets := &apps.StatefulSetList{}
+	if err := er.client.List(context.TODO(), statefulSets, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to list statefulsets",
+			"cluster", cluster.Name)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		used.Insert(volumeClaimNames(statefulSet.Spec.Template.Spec.Volumes)...)
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			prefixes = append(prefixes, fmt.Sprintf("%s-%s-", template.Name, statefulSet.Name))
+		}
+	}
+
+	pods := &v1.PodList{}
+	if err := er.client.List(context.TODO(), pods, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to list pods",
+			"cluster", cluster.Name)
+	}
+	for _, pod := range pods.Items {
+		used.Insert(volumeClaimNames(pod.Spec.Volumes)...)
+	}
+
+	return used, prefixes, nil
+}
+
+func volumeClaimNames(volumes []v1.Volume) []string {
+	names := []string{}
+	for _, volume := range volumes {
+		if volume.PersistentVolumeClaim != nil {
+			names = append(names, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return names
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package k8shandler
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Orphaned claims", func() {
+	defer GinkgoRecover()
+
+	var (
+		k8sClient client.Client
+		recorder  *record.FakeRecorder
+		request   *ElasticsearchRequest
+	)
+
+	newClaim := func(name string, annotations map[string]string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "openshift-logging",
+				Labels:      map[string]string{"logging-cluster": "elasticsearch"},
+				Annotations: annotations,
+			},
+		}
+	}
+
+	getClaim := func(name string) *v1.PersistentVolumeClaim {
+		claim := &v1.PersistentVolumeClaim{}
+		if err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "openshift-logging"}, claim); err != nil {
+			return nil
+		}
+		return claim
+	}
+
+	BeforeEach(func() {
+		uuid := "abc"
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+						NodeCount: 1,
+						GenUUID:   &uuid,
+						Workload:  api.ElasticsearchNodeWorkloadStatefulSet,
+					},
+				},
+				OrphanedClaimCollection: &api.OrphanedClaimCollectionSpec{
+					GracePeriod: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+		}
+		migrating := &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cd-abc-1",
+				Namespace: "openshift-logging",
+				Labels:    map[string]string{"cluster-name": "elasticsearch"},
+			},
+			Spec: apps.DeploymentSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Volumes: []v1.Volume{
+							{
+								Name: storageVolumeName,
+								VolumeSource: v1.VolumeSource{
+									PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "elasticsearch-elasticsearch-cd-abc-1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		expired := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		k8sClient = fake.NewFakeClientWithScheme(s, cluster, migrating,
+			newClaim("elasticsearch-storage-elasticsearch-cd-abc-3", nil),
+			newClaim("elasticsearch-elasticsearch-cd-abc-1", map[string]string{orphanedSinceAnnotation: expired}),
+			newClaim("elasticsearch-elasticsearch-cdm-removed-1", nil),
+			newClaim("elasticsearch-elasticsearch-cdm-removed-2", map[string]string{orphanedSinceAnnotation: expired}),
+		)
+		recorder = record.NewFakeRecorder(5)
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			recorder: recorder,
+		}
+	})
+
+	It("should delete the claims unused for the grace period", func() {
+		Expect(request.CollectOrphanedClaims()).To(Succeed())
+
+		Expect(getClaim("elasticsearch-elasticsearch-cdm-removed-2")).To(BeNil())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal OrphanedClaimDeleted Deleted PVC elasticsearch-elasticsearch-cdm-removed-2")))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should wait for the grace period of newly unused claims", func() {
+		Expect(request.CollectOrphanedClaims()).To(Succeed())
+
+		claim := getClaim("elasticsearch-elasticsearch-cdm-removed-1")
+		Expect(claim).NotTo(BeNil())
+		Expect(claim.Annotations).To(HaveKey(orphanedSinceAnnotation))
+	})
+
+	It("should keep the claims of the nodes", func() {
+		Expect(request.CollectOrphanedClaims()).To(Succeed())
+
+		Expect(getClaim("elasticsearch-storage-elasticsearch-cd-abc-3")).NotTo(BeNil())
+		claim := getClaim("elasticsearch-elasticsearch-cd-abc-1")
+		Expect(claim).NotTo(BeNil())
+		Expect(claim.Annotations).NotTo(HaveKey(orphanedSinceAnnotation))
+	})
+
+	It("should keep all claims unless requested", func() {
+		request.cluster.Spec.OrphanedClaimCollection = nil
+		Expect(request.CollectOrphanedClaims()).To(Succeed())
+
+		Expect(getClaim("elasticsearch-elasticsearch-cdm-removed-2")).NotTo(BeNil())
+	})
+})
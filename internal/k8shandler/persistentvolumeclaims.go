@@ -2,12 +2,17 @@ package k8shandler
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
 func createOrUpdatePersistentVolumeClaim(pvc v1.PersistentVolumeClaimSpec, newName, namespace, clusterName string, client client.Client) error {
 	// for some reason if the PVC already exists but creating it again would violate
 	// quota we get an error regarding quota not that it already exists
@@ -59,9 +66,17 @@ func updatePersistentVolumeClaim(claim *v1.PersistentVolumeClaim, client client.
 			)
 		}
 
+		changed, err := growStorageRequest(current, claim.Spec.Resources.Requests[v1.ResourceStorage], client)
+		if err != nil {
+			return err
+		}
+
 		if !reflect.DeepEqual(current.ObjectMeta.Labels, claim.ObjectMeta.Labels) {
 			current.ObjectMeta.Labels = claim.ObjectMeta.Labels
+			changed = true
+		}
 
+		if changed {
 			if err := client.Update(context.TODO(), current); err != nil {
 				return err
 			}
@@ -76,6 +91,112 @@ func updatePersistentVolumeClaim(claim *v1.PersistentVolumeClaim, client client.
 	return nil
 }
 
+// expandPersistentVolumeClaim grows the storage request of an existing claim to size
+func expandPersistentVolumeClaim(claimName, namespace string, size resource.Quantity, client client.Client) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.PersistentVolumeClaim{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: namespace}, current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return kverrors.Wrap(err, "failed to get PVC",
+				"claim", claimName,
+			)
+		}
+
+		changed, err := growStorageRequest(current, size, client)
+		if err != nil || !changed {
+			return err
+		}
+		return client.Update(context.TODO(), current)
+	})
+}
+
+// growStorageRequest sets the storage request of the claim to size if size is larger and the storage
+// class of the claim allows expanding volumes. Claims are never shrunk
+func growStorageRequest(claim *v1.PersistentVolumeClaim, size resource.Quantity, client client.Client) (bool, error) {
+	current := claim.Spec.Resources.Requests[v1.ResourceStorage]
+	if size.IsZero() || size.Cmp(current) <= 0 {
+		return false, nil
+	}
+
+	allowed, err := allowsVolumeExpansion(claim.Spec.StorageClassName, client)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		log.Info("Storage class of PVC does not allow volume expansion", "pvc", claim.Name, "size", current.String(), "desired", size.String())
+		return false, nil
+	}
+
+	log.Info("Expanding PVC", "pvc", claim.Name, "size", current.String(), "desired", size.String())
+	if claim.Spec.Resources.Requests == nil {
+		claim.Spec.Resources.Requests = v1.ResourceList{}
+	}
+	claim.Spec.Resources.Requests[v1.ResourceStorage] = size
+	return true, nil
+}
+
+// allowsVolumeExpansion returns true if the storage class, or the default one if the name is empty,
+// allows expanding volumes
+func allowsVolumeExpansion(className *string, c client.Client) (bool, error) {
+	var class *storagev1.StorageClass
+
+	if className != nil && *className != "" {
+		class = &storagev1.StorageClass{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: *className}, class); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, kverrors.Wrap(err, "failed to get storage class",
+				"storage_class", *className,
+			)
+		}
+	} else {
+		classes := &storagev1.StorageClassList{}
+		if err := c.List(context.TODO(), classes); err != nil {
+			return false, kverrors.Wrap(err, "failed to list storage classes")
+		}
+		for i := range classes.Items {
+			if classes.Items[i].Annotations[defaultStorageClassAnnotation] == "true" {
+				class = &classes.Items[i]
+				break
+			}
+		}
+	}
+
+	return class != nil && class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion, nil
+}
+
+// deleteStatefulSetClaims deletes the claims created from the claim template for the pods of the
+// StatefulSet from the ordinal on
+func deleteStatefulSetClaims(template, statefulSetName, namespace, clusterName string, fromOrdinal int32, c client.Client) error {
+	claims := &v1.PersistentVolumeClaimList{}
+	if err := c.List(context.TODO(), claims, client.InNamespace(namespace), client.MatchingLabels{"logging-cluster": clusterName}); err != nil {
+		return kverrors.Wrap(err, "failed to list PVCs",
+			"cluster", clusterName)
+	}
+
+	prefix := fmt.Sprintf("%s-%s-", template, statefulSetName)
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if !strings.HasPrefix(claim.Name, prefix) || claim.GetDeletionTimestamp() != nil {
+			continue
+		}
+		ordinal, err := strconv.ParseInt(strings.TrimPrefix(claim.Name, prefix), 10, 32)
+		if err != nil || int32(ordinal) < fromOrdinal {
+			continue
+		}
+
+		log.Info("Deleting PVC", "pvc", claim.Name)
+		if err := c.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete PVC",
+				"claim", claim.Name)
+		}
+	}
+	return nil
+}
+
 func createPersistentVolumeClaim(pvcName, namespace, clusterName string, volSpec v1.PersistentVolumeClaimSpec) *v1.PersistentVolumeClaim {
 	pvc := persistentVolumeClaim(pvcName, namespace, clusterName)
 	pvc.Spec = volSpec
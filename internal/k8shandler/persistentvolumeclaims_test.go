@@ -0,0 +1,97 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("PVC expansion", func() {
+	defer GinkgoRecover()
+
+	var (
+		expandable = "expandable"
+		fixed      = "fixed"
+		k8sClient  client.Client
+	)
+
+	newClaim := func(name, class, size string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"},
+			Spec: v1.PersistentVolumeClaimSpec{
+				StorageClassName: &class,
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	claimSize := func(name string) string {
+		claim := &v1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "openshift-logging"}, claim)).To(Succeed())
+		size := claim.Spec.Resources.Requests[v1.ResourceStorage]
+		return size.String()
+	}
+
+	BeforeEach(func() {
+		allow, deny := true, false
+		k8sClient = fake.NewFakeClient(
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: expandable}, AllowVolumeExpansion: &allow},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: fixed}, AllowVolumeExpansion: &deny},
+		)
+	})
+
+	It("should grow the claim of a node if its storage class allows it", func() {
+		Expect(k8sClient.Create(context.TODO(), newClaim("elasticsearch-cdm-1", expandable, "1Gi"))).To(Succeed())
+
+		Expect(updatePersistentVolumeClaim(newClaim("elasticsearch-cdm-1", expandable, "2Gi"), k8sClient)).To(Succeed())
+		Expect(claimSize("elasticsearch-cdm-1")).To(Equal("2Gi"))
+
+		Expect(updatePersistentVolumeClaim(newClaim("elasticsearch-cdm-1", expandable, "1Gi"), k8sClient)).To(Succeed())
+		Expect(claimSize("elasticsearch-cdm-1")).To(Equal("2Gi"))
+	})
+
+	It("should keep the size of the claim if its storage class does not allow expansion", func() {
+		Expect(k8sClient.Create(context.TODO(), newClaim("elasticsearch-cdm-1", fixed, "1Gi"))).To(Succeed())
+
+		Expect(updatePersistentVolumeClaim(newClaim("elasticsearch-cdm-1", fixed, "2Gi"), k8sClient)).To(Succeed())
+		Expect(claimSize("elasticsearch-cdm-1")).To(Equal("1Gi"))
+	})
+
+	It("should grow the claims of the pods of a StatefulSet and recreate it", func() {
+		replicas := int32(2)
+		newStatefulSet := func(size string) *apps.StatefulSet {
+			return &apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc", Namespace: "openshift-logging"},
+				Spec: apps.StatefulSetSpec{
+					Replicas:             &replicas,
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{*newClaim(storageVolumeName, expandable, size)},
+				},
+			}
+		}
+		Expect(k8sClient.Create(context.TODO(), newStatefulSet("1Gi"))).To(Succeed())
+		Expect(k8sClient.Create(context.TODO(), newClaim("elasticsearch-storage-elasticsearch-cd-abc-0", expandable, "1Gi"))).To(Succeed())
+		Expect(k8sClient.Create(context.TODO(), newClaim("elasticsearch-storage-elasticsearch-cd-abc-1", expandable, "1Gi"))).To(Succeed())
+
+		node := &statefulSetNode{self: *newStatefulSet("2Gi"), client: k8sClient}
+		Expect(node.expandStorage()).To(Succeed())
+
+		Expect(claimSize("elasticsearch-storage-elasticsearch-cd-abc-0")).To(Equal("2Gi"))
+		Expect(claimSize("elasticsearch-storage-elasticsearch-cd-abc-1")).To(Equal("2Gi"))
+		current := &apps.StatefulSet{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-cd-abc", Namespace: "openshift-logging"}, current)).To(Succeed())
+		size := current.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+		Expect(size.String()).To(Equal("2Gi"))
+	})
+})
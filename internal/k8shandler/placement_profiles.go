@@ -0,0 +1,88 @@
+package k8shandler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	k8sYAML "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const placementProfilesFilePath = "/etc/elasticsearch-operator/files/placement_profiles.yml"
+
+// placementProfile is a named combination of a node selector and tolerations of the operator
+// configuration, so the clusters of different teams land on the same nodes
+type placementProfile struct {
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	Tolerations  []v1.Toleration   `json:"tolerations,omitempty"`
+}
+
+type placementProfiles struct {
+	Profiles map[string]placementProfile `json:"profiles,omitempty"`
+}
+
+// loadPlacementProfiles returns the placement profiles of the file by name. There are no profiles
+// if the file does not exist
+func loadPlacementProfiles(filePath string) (map[string]placementProfile, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]placementProfile{}, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to open file", "filePath", filePath)
+	}
+	defer f.Close()
+
+	profiles := placementProfiles{}
+	if err := k8sYAML.NewYAMLOrJSONDecoder(f, 1000).Decode(&profiles); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode placement profiles from file", "filePath", filePath)
+	}
+	if profiles.Profiles == nil {
+		return map[string]placementProfile{}, nil
+	}
+	return profiles.Profiles, nil
+}
+
+// placementProfileName returns the name of the placement profile of the node, falling back to the
+// one of the node spec
+func placementProfileName(commonSpec api.ElasticsearchNodeSpec, node api.ElasticsearchNode) string {
+	if node.PlacementProfile != "" {
+		return node.PlacementProfile
+	}
+	return commonSpec.PlacementProfile
+}
+
+// nodePlacementProfile returns the placement profile of the node. Nodes without a profile get an
+// empty one
+func nodePlacementProfile(commonSpec api.ElasticsearchNodeSpec, node api.ElasticsearchNode) (placementProfile, error) {
+	name := placementProfileName(commonSpec, node)
+	if name == "" {
+		return placementProfile{}, nil
+	}
+
+	profiles, err := loadPlacementProfiles(utils.LookupEnvWithDefault("PLACEMENT_PROFILES_FILE", placementProfilesFilePath))
+	if err != nil {
+		return placementProfile{}, err
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return placementProfile{}, kverrors.New("unknown placement profile", "profile", name)
+	}
+	return profile, nil
+}
+
+// placementProfileViolation returns the reason the placement profile of a node is invalid or an
+// empty string
+func placementProfileViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		if _, err := nodePlacementProfile(cluster.Spec.Spec, node); err != nil {
+			return fmt.Sprintf("The placement profile %q of the nodes with the roles %v is not usable: %s",
+				placementProfileName(cluster.Spec.Spec, node), node.Roles, err.Error())
+		}
+	}
+	return ""
+}
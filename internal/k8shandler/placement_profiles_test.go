@@ -0,0 +1,94 @@
+package k8shandler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Placement profiles", func() {
+	defer GinkgoRecover()
+
+	var (
+		dir     string
+		cluster *api.Elasticsearch
+	)
+
+	newPodSpec := func(node api.ElasticsearchNode) v1.PodSpec {
+		return newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "test-cluster-name",
+			namespace:   "test-namespace-name",
+			commonSpec:  cluster.Spec.Spec,
+		}).Spec
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "placement-profiles")
+		Expect(err).ToNot(HaveOccurred())
+
+		profiles := `
+profiles:
+  dedicated-es-nodes:
+    nodeSelector:
+      node-role.kubernetes.io/infra: ""
+      disktype: ssd
+    tolerations:
+    - key: node-role.kubernetes.io/infra
+      operator: Exists
+      effect: NoSchedule
+`
+		filePath := filepath.Join(dir, "placement_profiles.yml")
+		Expect(ioutil.WriteFile(filePath, []byte(profiles), 0600)).To(Succeed())
+		Expect(os.Setenv("PLACEMENT_PROFILES_FILE", filePath)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			Spec: api.ElasticsearchSpec{
+				Spec: api.ElasticsearchNodeSpec{PlacementProfile: "dedicated-es-nodes"},
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("PLACEMENT_PROFILES_FILE")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should add the node selector and tolerations of the profile to the pods", func() {
+		node := api.ElasticsearchNode{NodeSelector: map[string]string{"disktype": "nvme"}}
+
+		podSpec := newPodSpec(node)
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("node-role.kubernetes.io/infra", ""))
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("disktype", "nvme"))
+		Expect(podSpec.Tolerations).To(ContainElement(v1.Toleration{
+			Key:      "node-role.kubernetes.io/infra",
+			Operator: v1.TolerationOpExists,
+			Effect:   v1.TaintEffectNoSchedule,
+		}))
+	})
+
+	It("should reject unknown profiles", func() {
+		Expect(placementProfileViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.Nodes[0].PlacementProfile = "shared-nodes"
+		Expect(placementProfileViolation(cluster)).To(ContainSubstring(`"shared-nodes"`))
+
+		podSpec := newPodSpec(cluster.Spec.Nodes[0])
+		Expect(podSpec.NodeSelector).ToNot(HaveKey("node-role.kubernetes.io/infra"))
+	})
+
+	It("should have no profiles without a configuration file", func() {
+		profiles, err := loadPlacementProfiles(filepath.Join(dir, "missing.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles).To(BeEmpty())
+	})
+})
@@ -0,0 +1,150 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// optionalRoleLabels are the role labels the pods only have if they have the role
+var optionalRoleLabels = []struct {
+	role  api.ElasticsearchNodeRole
+	label string
+}{
+	{api.ElasticsearchRoleIngest, "es-node-ingest"},
+	{api.ElasticsearchRoleML, "es-node-ml"},
+	{api.ElasticsearchRoleVotingOnly, "es-node-voting-only"},
+}
+
+// CreateOrUpdatePodDisruptionBudgets ensures a PodDisruptionBudget allowing one unavailable pod
+// for the nodes of every set of roles with more than one pod, so drains cannot take out the quorum
+// of the masters or all copies of a shard. The budgets of the sets of roles the cluster does not
+// use anymore are deleted
+func (er *ElasticsearchRequest) CreateOrUpdatePodDisruptionBudgets() error {
+	cluster := er.cluster
+
+	desired := newPodDisruptionBudgets(cluster)
+	for _, pdb := range desired {
+		cluster.AddOwnerRefTo(pdb)
+		if err := er.createOrUpdatePodDisruptionBudget(pdb); err != nil {
+			return err
+		}
+	}
+
+	current := &policy.PodDisruptionBudgetList{}
+	labels := client.MatchingLabels{"cluster-name": cluster.Name, "component": "elasticsearch"}
+	if err := er.client.List(context.TODO(), current, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return kverrors.Wrap(err, "failed to list pod disruption budgets",
+			"cluster", cluster.Name)
+	}
+
+	for i := range current.Items {
+		pdb := &current.Items[i]
+		if _, ok := desired[pdb.Name]; ok {
+			continue
+		}
+
+		er.L().Info("Deleting pod disruption budget of removed nodes", "pdb", pdb.Name)
+		if err := er.client.Delete(context.TODO(), pdb); err != nil && !apierrors.IsNotFound(err) {
+			return kverrors.Wrap(err, "failed to delete pod disruption budget",
+				"pdb", pdb.Name)
+		}
+	}
+	return nil
+}
+
+func (er *ElasticsearchRequest) createOrUpdatePodDisruptionBudget(pdb *policy.PodDisruptionBudget) error {
+	err := er.client.Create(context.TODO(), pdb)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(kverrors.Root(err)) {
+		return kverrors.Wrap(err, "failed to create pod disruption budget",
+			"pdb", pdb.Name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &policy.PodDisruptionBudget{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: pdb.Name, Namespace: pdb.Namespace}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Spec, pdb.Spec) {
+			return nil
+		}
+
+		current.Spec = pdb.Spec
+		return er.client.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update pod disruption budget",
+		"pdb", pdb.Name)
+}
+
+// newPodDisruptionBudgets returns the budgets of the sets of roles of the nodes by name. The pods
+// of the nodes with the same roles share a budget since nothing else tells them apart
+func newPodDisruptionBudgets(cluster *api.Elasticsearch) map[string]*policy.PodDisruptionBudget {
+	counts := map[string]int32{}
+	roleMaps := map[string]map[api.ElasticsearchNodeRole]bool{}
+	for _, node := range cluster.Spec.Nodes {
+		roleMap := getNodeRoleMap(node)
+		name := fmt.Sprintf("%s-%s", cluster.Name, getRoleSuffix(roleMap))
+		counts[name] += node.NodeCount
+		roleMaps[name] = roleMap
+	}
+
+	budgets := map[string]*policy.PodDisruptionBudget{}
+	for name, count := range counts {
+		// a single pod either blocks every drain or is not protected at all
+		if count < 2 {
+			continue
+		}
+
+		maxUnavailable := intstr.FromInt(1)
+		budgets[name] = &policy.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: policy.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					"cluster-name": cluster.Name,
+					"component":    "elasticsearch",
+				},
+			},
+			Spec: policy.PodDisruptionBudgetSpec{
+				MaxUnavailable: &maxUnavailable,
+				Selector:       newRolesSelector(cluster.Name, roleMaps[name]),
+			},
+		}
+	}
+	return budgets
+}
+
+// newRolesSelector returns a selector matching the pods of the nodes with exactly the roles
+func newRolesSelector(clusterName string, roleMap map[api.ElasticsearchNodeRole]bool) *metav1.LabelSelector {
+	labels := newLabels(clusterName, "", roleMap)
+	delete(labels, "node-name")
+
+	selector := &metav1.LabelSelector{MatchLabels: labels}
+	for _, optional := range optionalRoleLabels {
+		if roleMap[optional.role] {
+			continue
+		}
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      optional.label,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		})
+	}
+	return selector
+}
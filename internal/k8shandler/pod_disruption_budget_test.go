@@ -0,0 +1,93 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Pod disruption budgets", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		request *ElasticsearchRequest
+	)
+
+	listBudgets := func() map[string]policy.PodDisruptionBudget {
+		list := &policy.PodDisruptionBudgetList{}
+		Expect(request.client.List(context.TODO(), list)).To(Succeed())
+
+		budgets := map[string]policy.PodDisruptionBudget{}
+		for _, pdb := range list.Items {
+			budgets[pdb.Name] = pdb
+		}
+		return budgets
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 1},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 1},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleIngest}, NodeCount: 1},
+				},
+			},
+		}
+		stale := &policy.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cd",
+				Namespace: "openshift-logging",
+				Labels:    map[string]string{"cluster-name": "elasticsearch", "component": "elasticsearch"},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClient(stale),
+			cluster: cluster,
+		}
+	})
+
+	It("should allow one unavailable pod of the nodes with the same roles", func() {
+		Expect(request.CreateOrUpdatePodDisruptionBudgets()).To(Succeed())
+
+		budgets := listBudgets()
+		Expect(budgets).To(HaveLen(2))
+		Expect(budgets).To(HaveKey("elasticsearch-m"))
+		Expect(budgets).To(HaveKey("elasticsearch-d"))
+
+		pdb := budgets["elasticsearch-d"]
+		Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		Expect(err).ToNot(HaveOccurred())
+		dataPod := newLabels("elasticsearch", "elasticsearch-d-abc-1", getNodeRoleMap(cluster.Spec.Nodes[1]))
+		ingestDataPod := newLabels("elasticsearch", "elasticsearch-di-abc-1", map[api.ElasticsearchNodeRole]bool{
+			api.ElasticsearchRoleData:   true,
+			api.ElasticsearchRoleIngest: true,
+		})
+		Expect(selector.Matches(labels.Set(dataPod))).To(BeTrue())
+		Expect(selector.Matches(labels.Set(ingestDataPod))).To(BeFalse())
+	})
+
+	It("should delete the budget of nodes scaled down to a single pod", func() {
+		Expect(request.CreateOrUpdatePodDisruptionBudgets()).To(Succeed())
+
+		cluster.Spec.Nodes = cluster.Spec.Nodes[:2]
+		Expect(request.CreateOrUpdatePodDisruptionBudgets()).To(Succeed())
+
+		budgets := listBudgets()
+		Expect(budgets).To(HaveLen(1))
+		Expect(budgets).To(HaveKey("elasticsearch-m"))
+	})
+})
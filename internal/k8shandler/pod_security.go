@@ -0,0 +1,179 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	tmpVolumeName = "tmp"
+	tmpPath       = "/tmp"
+	// maxMapCountCheck is part of the message of the failed bootstrap check of vm.max_map_count
+	maxMapCountCheck = "vm.max_map_count"
+)
+
+// seccompProfiles are the values of the seccomp annotation of the pods by profile
+var seccompProfiles = map[api.SeccompProfileType]string{
+	api.SeccompProfileRuntimeDefault: v1.SeccompProfileRuntimeDefault,
+	api.SeccompProfileUnconfined:     "unconfined",
+}
+
+// podSecurityViolation returns the reason the pod security of the cluster is invalid or an empty
+// string. The init container tuning the hosts has to run as root
+func podSecurityViolation(cluster *api.Elasticsearch) string {
+	spec := cluster.Spec.PodSecurity
+	if spec == nil {
+		return ""
+	}
+
+	if spec.RunAsNonRoot && cluster.Spec.HostTuning {
+		return "Host tuning runs a privileged init container as root which runAsNonRoot of the pod security forbids"
+	}
+	return ""
+}
+
+// isPodSecurityManagedContainer returns true for the containers of the pods created by the
+// operator. The privileged init container tuning the hosts keeps its security context
+func isPodSecurityManagedContainer(name string) bool {
+	switch name {
+	case "elasticsearch", "proxy", keystoreName:
+		return true
+	case hostTuningName:
+		return false
+	}
+	for _, managed := range managedInitContainerNames {
+		if name == managed {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPodSecurity sets the security contexts of the pods of the template and of the containers
+// created by the operator. The Elasticsearch container reports the end of its log on failure, so
+// failed bootstrap checks are surfaced in the status of the pods
+func applyPodSecurity(cluster *api.Elasticsearch, template *v1.PodTemplateSpec) {
+	spec := cluster.Spec.PodSecurity
+	if spec == nil {
+		return
+	}
+
+	if profile, ok := seccompProfiles[spec.SeccompProfile]; ok {
+		template.Annotations = mergeSelectors(map[string]string{v1.SeccompPodAnnotationKey: profile}, template.Annotations)
+	}
+
+	podSpec := &template.Spec
+	if spec.RunAsNonRoot {
+		if podSpec.SecurityContext == nil {
+			podSpec.SecurityContext = &v1.PodSecurityContext{}
+		}
+		runAsNonRoot := true
+		podSpec.SecurityContext.RunAsNonRoot = &runAsNonRoot
+	}
+
+	if spec.ReadOnlyRootFilesystem {
+		podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+			Name: tmpVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	for _, containers := range [][]v1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			container := &containers[i]
+			if !isPodSecurityManagedContainer(container.Name) && !isLegacyClientContainer(cluster, container.Name) {
+				continue
+			}
+			applyContainerSecurity(container, spec)
+
+			if container.Name == "elasticsearch" {
+				container.TerminationMessagePolicy = v1.TerminationMessageFallbackToLogsOnError
+			}
+		}
+	}
+}
+
+func applyContainerSecurity(container *v1.Container, spec *api.PodSecuritySpec) {
+	if !spec.ReadOnlyRootFilesystem && !spec.DropAllCapabilities {
+		return
+	}
+
+	if container.SecurityContext == nil {
+		container.SecurityContext = &v1.SecurityContext{}
+	}
+	securityContext := container.SecurityContext
+
+	if spec.ReadOnlyRootFilesystem {
+		readOnly := true
+		securityContext.ReadOnlyRootFilesystem = &readOnly
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      tmpVolumeName,
+			MountPath: tmpPath,
+		})
+	}
+
+	if spec.DropAllCapabilities {
+		allowPrivilegeEscalation := false
+		securityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+		if securityContext.Capabilities == nil {
+			securityContext.Capabilities = &v1.Capabilities{}
+		}
+		securityContext.Capabilities.Drop = []v1.Capability{"ALL"}
+	}
+}
+
+// CheckMaxMapCount sets the MaxMapCountTooLow condition while Elasticsearch containers of the cluster
+// failed the bootstrap check of vm.max_map_count, which pods without host tuning cannot raise
+func (er *ElasticsearchRequest) CheckMaxMapCount() error {
+	cluster := er.cluster
+
+	pods := &v1.PodList{}
+	labels := client.MatchingLabels{"cluster-name": cluster.Name, "component": "elasticsearch"}
+	if err := er.client.List(context.TODO(), pods, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return kverrors.Wrap(err, "failed to list pods of cluster",
+			"cluster", cluster.Name)
+	}
+
+	failed := []string{}
+	for _, pod := range pods.Items {
+		if maxMapCountCheckFailed(pod) {
+			failed = append(failed, fmt.Sprintf("%s (node %s)", pod.Name, pod.Spec.NodeName))
+		}
+	}
+
+	if len(failed) == 0 {
+		return updateMaxMapCountTooLowCondition(cluster, v1.ConditionFalse, "", er.client)
+	}
+
+	message := fmt.Sprintf("The vm.max_map_count of the Kubernetes nodes of pods %s is below %d and Elasticsearch fails its bootstrap checks. ",
+		strings.Join(failed, ", "), minMaxMapCount)
+	if cluster.Spec.HostTuning {
+		message += "Check the logs of the host-tuning init containers, or raise vm.max_map_count on the nodes, e.g. with a tuning daemon set or machine config"
+	} else {
+		message += "Raise vm.max_map_count on the nodes, e.g. with a tuning daemon set or machine config, enable hostTuning if the namespace allows privileged containers, " +
+			"or disable memory mapping of the indices with node.store.allow_mmap: false in additionalConfig"
+	}
+	return updateMaxMapCountTooLowCondition(cluster, v1.ConditionTrue, message, er.client)
+}
+
+func maxMapCountCheckFailed(pod v1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != "elasticsearch" {
+			continue
+		}
+		for _, terminated := range []*v1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+			if terminated != nil && strings.Contains(terminated.Message, maxMapCountCheck) {
+				return true
+			}
+		}
+	}
+	return false
+}
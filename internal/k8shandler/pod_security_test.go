@@ -0,0 +1,169 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Pod security", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	newTemplate := func() v1.PodTemplateSpec {
+		template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			clockSkew:   &api.ClockSkewSpec{VerifyOnStartup: true},
+			memoryLock:  true,
+			hostTuning:  true,
+		})
+		applyPodSecurity(cluster, &template)
+		return template
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				PodSecurity: &api.PodSecuritySpec{
+					SeccompProfile:         api.SeccompProfileRuntimeDefault,
+					RunAsNonRoot:           true,
+					ReadOnlyRootFilesystem: true,
+					DropAllCapabilities:    true,
+				},
+			},
+		}
+	})
+
+	It("should leave the pods unchanged without pod security", func() {
+		cluster.Spec.PodSecurity = nil
+		template := newTemplate()
+		Expect(template.Annotations).To(BeEmpty())
+		Expect(template.Spec.SecurityContext).To(BeNil())
+		Expect(template.Spec.Containers[1].SecurityContext).To(BeNil())
+	})
+
+	It("should set the seccomp profile and run the pods as non-root", func() {
+		template := newTemplate()
+		Expect(template.Annotations).To(HaveKeyWithValue(v1.SeccompPodAnnotationKey, "runtime/default"))
+		Expect(*template.Spec.SecurityContext.RunAsNonRoot).To(BeTrue())
+	})
+
+	It("should restrict the containers of the operator", func() {
+		template := newTemplate()
+
+		for _, container := range append(template.Spec.Containers, template.Spec.InitContainers[1]) {
+			Expect(*container.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue(), container.Name)
+			Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse(), container.Name)
+			Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(v1.Capability("ALL")), container.Name)
+			Expect(container.VolumeMounts).To(ContainElement(v1.VolumeMount{Name: tmpVolumeName, MountPath: "/tmp"}), container.Name)
+		}
+		Expect(template.Spec.Volumes).To(ContainElement(v1.Volume{
+			Name:         tmpVolumeName,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		}))
+
+		es := template.Spec.Containers[0]
+		Expect(es.SecurityContext.Capabilities.Add).To(ConsistOf(v1.Capability("IPC_LOCK")))
+		Expect(es.TerminationMessagePolicy).To(Equal(v1.TerminationMessageFallbackToLogsOnError))
+	})
+
+	It("should keep the privileged security context of the host tuning container", func() {
+		template := newTemplate()
+		Expect(template.Spec.InitContainers[0].Name).To(Equal(hostTuningName))
+		Expect(*template.Spec.InitContainers[0].SecurityContext.Privileged).To(BeTrue())
+		Expect(template.Spec.InitContainers[0].SecurityContext.Capabilities).To(BeNil())
+	})
+
+	It("should reject host tuning for pods running as non-root", func() {
+		cluster.Spec.HostTuning = true
+		Expect(podSecurityViolation(cluster)).To(ContainSubstring("runAsNonRoot"))
+
+		cluster.Spec.PodSecurity.RunAsNonRoot = false
+		Expect(podSecurityViolation(cluster)).To(BeEmpty())
+	})
+
+	It("should roll out changes of the pod security", func() {
+		current := newTemplate()
+		cluster.Spec.PodSecurity.DropAllCapabilities = false
+		desired := newTemplate()
+
+		Expect(podTemplateSpecChanges(current, desired)).To(ContainElement("containers[proxy].securityContext.capabilities.drop"))
+	})
+
+	It("should ignore the capabilities pods drop on admission", func() {
+		desired := newTemplate()
+		pod := *desired.DeepCopy()
+		pod.Spec.Containers[1].SecurityContext.Capabilities.Drop = []v1.Capability{"ALL", "KILL", "MKNOD"}
+
+		Expect(ArePodSpecDifferent(pod.Spec, desired.Spec, false)).To(BeFalse())
+		Expect(ArePodSpecDifferent(pod.Spec, desired.Spec, true)).To(BeTrue())
+	})
+
+	Describe("vm.max_map_count", func() {
+		newRequest := func(message string) *ElasticsearchRequest {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch-cdm-1-abc",
+					Namespace: "openshift-logging",
+					Labels:    map[string]string{"cluster-name": "elasticsearch", "component": "elasticsearch"},
+				},
+				Spec: v1.PodSpec{NodeName: "worker-1"},
+				Status: v1.PodStatus{
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "elasticsearch",
+							LastTerminationState: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{ExitCode: 78, Message: message},
+							},
+						},
+					},
+				},
+			}
+			return &ElasticsearchRequest{
+				client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+				cluster: cluster,
+			}
+		}
+
+		condition := func(request *ElasticsearchRequest) *api.ClusterCondition {
+			current := &api.Elasticsearch{}
+			Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, current)).To(Succeed())
+			_, condition := getESNodeCondition(current.Status.Conditions, api.MaxMapCountTooLow)
+			return condition
+		}
+
+		It("should tell how to resolve a failed bootstrap check", func() {
+			request := newRequest("ERROR: [1] bootstrap checks failed\n[1]: max virtual memory areas vm.max_map_count [65530] is too low, increase to at least [262144]")
+			Expect(request.CheckMaxMapCount()).To(Succeed())
+
+			c := condition(request)
+			Expect(c.Status).To(Equal(v1.ConditionTrue))
+			Expect(c.Message).To(ContainSubstring("elasticsearch-cdm-1-abc (node worker-1)"))
+			Expect(c.Message).To(ContainSubstring("node.store.allow_mmap: false"))
+		})
+
+		It("should clear the condition for other failures", func() {
+			request := newRequest("OutOfMemoryError")
+			Expect(request.CheckMaxMapCount()).To(Succeed())
+			if c := condition(request); c != nil {
+				Expect(c.Status).To(Equal(v1.ConditionFalse))
+			}
+		})
+	})
+})
@@ -1,36 +1,124 @@
 package k8shandler
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
 	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ArePodTemplateSpecDifferent compares two v1.PodTemplateSpecs
 // and returns True or False
 func ArePodTemplateSpecDifferent(lhs, rhs v1.PodTemplateSpec) bool {
-	return ArePodSpecDifferent(lhs.Spec, rhs.Spec, true)
+	return len(podTemplateSpecChanges(lhs, rhs)) > 0
+}
+
+// podTemplateSpecChanges returns the fields of the current pod template lhs which differ
+// from the desired one rhs, e.g. "containers[elasticsearch].image: old -> new"
+func podTemplateSpecChanges(lhs, rhs v1.PodTemplateSpec) []string {
+	changes := podSpecChanges(lhs.Spec, rhs.Spec, true)
+
+	if !areSelectorsSame(lhs.Labels, rhs.Labels) {
+		changes = append(changes, "metadata.labels")
+	}
+
+	if !areSelectorsSame(lhs.Annotations, rhs.Annotations) {
+		changes = append(changes, "metadata.annotations")
+	}
+
+	return changes
+}
+
+// podTemplateMetadata returns the labels and annotations of the pods of the node. The ones of the
+// node override the ones of the node spec and the labels of the operator override both
+func podTemplateMetadata(commonSpec api.ElasticsearchNodeSpec, node api.ElasticsearchNode, labels map[string]string) (map[string]string, map[string]string) {
+	var commonMetadata, nodeMetadata api.ElasticsearchPodTemplateMetadata
+	if commonSpec.PodTemplate != nil {
+		commonMetadata = commonSpec.PodTemplate.Metadata
+	}
+	if node.PodTemplate != nil {
+		nodeMetadata = node.PodTemplate.Metadata
+	}
+
+	podLabels := mergeSelectors(labels, mergeSelectors(nodeMetadata.Labels, commonMetadata.Labels))
+
+	annotations := mergeSelectors(nodeMetadata.Annotations, commonMetadata.Annotations)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return podLabels, annotations
+}
+
+// mergeMetadata adds the labels and annotations of desired to current. The ones set on current by
+// others are kept
+func mergeMetadata(current *metav1.ObjectMeta, desired metav1.ObjectMeta) {
+	if len(desired.Labels) > 0 {
+		current.Labels = mergeSelectors(desired.Labels, current.Labels)
+	}
+	if len(desired.Annotations) > 0 {
+		current.Annotations = mergeSelectors(desired.Annotations, current.Annotations)
+	}
 }
 
 // Abstracted logic into comparing pod specs so that we can check if our change has been rolled out
 // yet or not
 func ArePodSpecDifferent(lhs, rhs v1.PodSpec, strictTolerations bool) bool {
-	changed := false
+	return len(podSpecChanges(lhs, rhs, strictTolerations)) > 0
+}
+
+func podSpecChanges(lhs, rhs v1.PodSpec, strictTolerations bool) []string {
+	changes := []string{}
 
 	if len(lhs.Containers) != len(rhs.Containers) {
-		changed = true
+		changes = append(changes, fmt.Sprintf("containers: %d -> %d", len(lhs.Containers), len(rhs.Containers)))
+	}
+
+	if len(lhs.InitContainers) != len(rhs.InitContainers) {
+		changes = append(changes, fmt.Sprintf("initContainers: %d -> %d", len(lhs.InitContainers), len(rhs.InitContainers)))
 	}
 
 	// check nodeselectors
 	if !areSelectorsSame(lhs.NodeSelector, rhs.NodeSelector) {
-		changed = true
+		changes = append(changes, "nodeSelector")
+	}
+
+	// the scheduler sets the node name of pods without one
+	if lhs.NodeName != rhs.NodeName && (strictTolerations || rhs.NodeName != "") {
+		changes = append(changes, fmt.Sprintf("nodeName: %s -> %s", lhs.NodeName, rhs.NodeName))
+	}
+
+	// the API server sets the default scheduler of pods without one
+	if lName, rName := schedulerName(lhs), schedulerName(rhs); lName != rName {
+		changes = append(changes, fmt.Sprintf("schedulerName: %s -> %s", lName, rName))
+	}
+
+	if lName, rName := stringValue(lhs.RuntimeClassName), stringValue(rhs.RuntimeClassName); lName != rName {
+		changes = append(changes, fmt.Sprintf("runtimeClassName: %s -> %s", lName, rName))
+	}
+
+	// the priority admission sets the default priority class of pods without one
+	if lhs.PriorityClassName != rhs.PriorityClassName && (strictTolerations || rhs.PriorityClassName != "") {
+		changes = append(changes, fmt.Sprintf("priorityClassName: %s -> %s", lhs.PriorityClassName, rhs.PriorityClassName))
+	}
+
+	if !reflect.DeepEqual(lhs.Affinity, rhs.Affinity) {
+		changes = append(changes, "affinity")
+	}
+
+	if !reflect.DeepEqual(lhs.TopologySpreadConstraints, rhs.TopologySpreadConstraints) {
+		changes = append(changes, "topologySpreadConstraints")
 	}
 
 	// check if volumes are the same
-	if !containsSameVolumes(lhs.Volumes, rhs.Volumes) {
-		changed = true
+	if names := changedVolumeNames(lhs.Volumes, rhs.Volumes); len(names) > 0 {
+		changes = append(changes, fmt.Sprintf("volumes: %s", strings.Join(names, ", ")))
 	}
 
 	// strictTolerations are for when we compare from the deployments or statefulsets
@@ -39,20 +127,36 @@ func ArePodSpecDifferent(lhs, rhs v1.PodSpec, strictTolerations bool) bool {
 	if strictTolerations {
 		// check tolerations
 		if !areTolerationsSame(lhs.Tolerations, rhs.Tolerations) {
-			changed = true
+			changes = append(changes, "tolerations")
 		}
 	} else {
 		// check tolerations
 		if !containsSameTolerations(lhs.Tolerations, rhs.Tolerations) {
-			changed = true
+			changes = append(changes, "tolerations")
 		}
 	}
 
+	// admission may set the security context of pods so only the runAsNonRoot of the operator is compared
+	if lValue, rValue := runAsNonRoot(lhs.SecurityContext), runAsNonRoot(rhs.SecurityContext); lValue != rValue && (strictTolerations || rValue) {
+		changes = append(changes, fmt.Sprintf("securityContext.runAsNonRoot: %t -> %t", lValue, rValue))
+	}
+
 	// check container fields
-	for _, lContainer := range lhs.Containers {
+	changes = append(changes, containerChanges("containers", lhs.Containers, rhs.Containers, strictTolerations)...)
+	changes = append(changes, containerChanges("initContainers", lhs.InitContainers, rhs.InitContainers, strictTolerations)...)
+	return changes
+}
+
+// containerChanges returns the fields of the current containers lhs of the kind, e.g. containers or
+// initContainers, which differ from the desired ones rhs with the same name. Unless strict, the
+// security contexts set by admission are not reported
+func containerChanges(kind string, lhs, rhs []v1.Container, strict bool) []string {
+	changes := []string{}
+	for _, lContainer := range lhs {
 		found := false
+		field := fmt.Sprintf("%s[%s]", kind, lContainer.Name)
 
-		for _, rContainer := range rhs.Containers {
+		for _, rContainer := range rhs {
 			// Only compare the images of containers with the same name
 			if lContainer.Name != rContainer.Name {
 				continue
@@ -63,35 +167,193 @@ func ArePodSpecDifferent(lhs, rhs v1.PodSpec, strictTolerations bool) bool {
 			// can't use reflect.DeepEqual here, due to k8s adding token mounts
 			// check that rContainer is all found within lContainer and that they match by name
 			if !containsSameVolumeMounts(lContainer.VolumeMounts, rContainer.VolumeMounts) {
-				changed = true
+				changes = append(changes, field+".volumeMounts")
 			}
 
 			if lContainer.Image != rContainer.Image {
-				changed = true
+				changes = append(changes, fmt.Sprintf("%s.image: %s -> %s", field, lContainer.Image, rContainer.Image))
 			}
 
 			if !comparators.EnvValueEqual(lContainer.Env, rContainer.Env) {
-				changed = true
+				changes = append(changes, fmt.Sprintf("%s.env: %s", field, strings.Join(changedEnvVarNames(lContainer.Env, rContainer.Env), ", ")))
+			}
+
+			if !reflect.DeepEqual(lContainer.Command, rContainer.Command) {
+				changes = append(changes, field+".command")
 			}
 
 			if !reflect.DeepEqual(lContainer.Args, rContainer.Args) {
-				changed = true
+				changes = append(changes, field+".args")
 			}
 
 			if !reflect.DeepEqual(lContainer.Ports, rContainer.Ports) {
-				changed = true
+				changes = append(changes, field+".ports")
 			}
 
 			if different, _ := utils.CompareResources(lContainer.Resources, rContainer.Resources); different {
-				changed = true
+				changes = append(changes, fmt.Sprintf("%s.resources: %s", field, strings.Join(changedResources(lContainer.Resources, rContainer.Resources), ", ")))
+			}
+
+			// only compare the added capabilities as admission may set the rest of the security context of pods
+			if !reflect.DeepEqual(addedCapabilities(lContainer), addedCapabilities(rContainer)) {
+				changes = append(changes, field+".capabilities")
+			}
+
+			changes = append(changes, securityContextChanges(field, lContainer, rContainer, strict)...)
+
+			if terminationMessagePolicy(lContainer) != terminationMessagePolicy(rContainer) {
+				changes = append(changes, fmt.Sprintf("%s.terminationMessagePolicy: %s -> %s", field, terminationMessagePolicy(lContainer), terminationMessagePolicy(rContainer)))
 			}
 		}
 
 		if !found {
-			changed = true
+			changes = append(changes, field+": removed")
+		}
+	}
+	return changes
+}
+
+// changedVolumeNames returns the names of the desired volumes rhs which the current volumes lhs
+// do not contain
+func changedVolumeNames(lhs, rhs []v1.Volume) []string {
+	names := []string{}
+	for _, rVolume := range rhs {
+		if !containsSameVolumes(lhs, []v1.Volume{rVolume}) {
+			names = append(names, rVolume.Name)
 		}
 	}
-	return changed
+	return names
+}
+
+// changedEnvVarNames returns the names of the environment variables added, removed or changed
+func changedEnvVarNames(lhs, rhs []v1.EnvVar) []string {
+	names := []string{}
+	for _, r := range rhs {
+		found := false
+		for _, l := range lhs {
+			if l.Name == r.Name {
+				found = comparators.EnvVarEqual(l, r)
+				break
+			}
+		}
+		if !found {
+			names = append(names, r.Name)
+		}
+	}
+	for _, l := range lhs {
+		found := false
+		for _, r := range rhs {
+			if l.Name == r.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			names = append(names, l.Name)
+		}
+	}
+	return names
+}
+
+// changedResources returns the compute resources which differ, e.g. "limits.memory 1Gi -> 2Gi"
+func changedResources(lhs, rhs v1.ResourceRequirements) []string {
+	changes := []string{}
+	compare := func(field string, current, desired *resource.Quantity) {
+		if current.Cmp(*desired) != 0 {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", field, current.String(), desired.String()))
+		}
+	}
+	compare("limits.cpu", lhs.Limits.Cpu(), rhs.Limits.Cpu())
+	compare("limits.memory", lhs.Limits.Memory(), rhs.Limits.Memory())
+	compare("requests.cpu", lhs.Requests.Cpu(), rhs.Requests.Cpu())
+	compare("requests.memory", lhs.Requests.Memory(), rhs.Requests.Memory())
+	return changes
+}
+
+func schedulerName(spec v1.PodSpec) string {
+	if spec.SchedulerName == "" {
+		return v1.DefaultSchedulerName
+	}
+	return spec.SchedulerName
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func addedCapabilities(container v1.Container) []v1.Capability {
+	if container.SecurityContext == nil || container.SecurityContext.Capabilities == nil {
+		return nil
+	}
+	return container.SecurityContext.Capabilities.Add
+}
+
+// securityContextChanges returns the fields of the security context the operator sets on the
+// containers which differ. Unless strict, only the fields set by the desired container rhs are
+// compared as admission may set the others
+func securityContextChanges(field string, lhs, rhs v1.Container, strict bool) []string {
+	changes := []string{}
+	lContext, rContext := lhs.SecurityContext, rhs.SecurityContext
+	if lContext == nil {
+		lContext = &v1.SecurityContext{}
+	}
+	if rContext == nil {
+		rContext = &v1.SecurityContext{}
+	}
+
+	compare := func(name string, lValue, rValue *bool) {
+		if reflect.DeepEqual(lValue, rValue) || (!strict && rValue == nil) {
+			return
+		}
+		changes = append(changes, fmt.Sprintf("%s.securityContext.%s", field, name))
+	}
+	compare("readOnlyRootFilesystem", lContext.ReadOnlyRootFilesystem, rContext.ReadOnlyRootFilesystem)
+	compare("allowPrivilegeEscalation", lContext.AllowPrivilegeEscalation, rContext.AllowPrivilegeEscalation)
+
+	// admission may drop further capabilities of pods
+	lDrop, rDrop := droppedCapabilities(lhs), droppedCapabilities(rhs)
+	if strict && !reflect.DeepEqual(lDrop, rDrop) || !strict && !containsCapabilities(lDrop, rDrop) {
+		changes = append(changes, field+".securityContext.capabilities.drop")
+	}
+	return changes
+}
+
+func containsCapabilities(lhs, rhs []v1.Capability) bool {
+	for _, r := range rhs {
+		found := false
+		for _, l := range lhs {
+			if l == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func droppedCapabilities(container v1.Container) []v1.Capability {
+	if container.SecurityContext == nil || container.SecurityContext.Capabilities == nil || len(container.SecurityContext.Capabilities.Drop) == 0 {
+		return nil
+	}
+	return container.SecurityContext.Capabilities.Drop
+}
+
+func runAsNonRoot(securityContext *v1.PodSecurityContext) bool {
+	return securityContext != nil && securityContext.RunAsNonRoot != nil && *securityContext.RunAsNonRoot
+}
+
+// terminationMessagePolicy returns the policy of the container defaulted by the API server
+func terminationMessagePolicy(container v1.Container) v1.TerminationMessagePolicy {
+	if container.TerminationMessagePolicy == "" {
+		return v1.TerminationMessageReadFile
+	}
+	return container.TerminationMessagePolicy
 }
 
 // check that all of rhs (desired) are contained within lhs (current)
@@ -140,6 +402,10 @@ func containsSameVolumes(lhs, rhs []v1.Volume) bool {
 					if lVolume.ConfigMap.Name != rVolume.ConfigMap.Name {
 						return false
 					}
+
+					if !reflect.DeepEqual(lVolume.ConfigMap.Items, rVolume.ConfigMap.Items) {
+						return false
+					}
 				}
 
 				if lVolume.Secret != nil || rVolume.Secret != nil {
@@ -188,10 +454,15 @@ func containsSameVolumes(lhs, rhs []v1.Volume) bool {
 							return false
 						}
 
-						if *lVolume.EmptyDir.SizeLimit != *rVolume.EmptyDir.SizeLimit {
+						// compare the values as the quantities read from the API server keep their formatting
+						if lVolume.EmptyDir.SizeLimit.Cmp(*rVolume.EmptyDir.SizeLimit) != 0 {
 							return false
 						}
 					}
+
+					if lVolume.EmptyDir.Medium != rVolume.EmptyDir.Medium {
+						return false
+					}
 				}
 			}
 		}
@@ -112,6 +112,24 @@ var _ = Describe("podtemplate", func() {
 		})
 	})
 
+	Context("different capabilities", func() {
+		JustBeforeEach(func() {
+			nodeContainer.SecurityContext = newMemoryLockSecurityContext()
+
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+				},
+			}
+		})
+
+		It("should recognize an added capability", func() {
+			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeTrue())
+		})
+	})
+
 	Context("different nodeselector", func() {
 		JustBeforeEach(func() {
 			rhs = v1.PodTemplateSpec{
@@ -131,6 +149,58 @@ var _ = Describe("podtemplate", func() {
 		})
 	})
 
+	Context("default scheduler", func() {
+		JustBeforeEach(func() {
+			lhs.Spec.SchedulerName = v1.DefaultSchedulerName
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+				},
+			}
+		})
+
+		It("should recognize podtemplates as the same", func() {
+			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeFalse())
+		})
+	})
+
+	Context("different scheduler", func() {
+		JustBeforeEach(func() {
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					SchedulerName: "kube-batch",
+				},
+			}
+		})
+
+		It("should recognize a schedulerName change", func() {
+			Expect(podTemplateSpecChanges(lhs, rhs)).To(ConsistOf("schedulerName: default-scheduler -> kube-batch"))
+		})
+	})
+
+	Context("different runtime class", func() {
+		JustBeforeEach(func() {
+			runtimeClassName := "gvisor"
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					RuntimeClassName: &runtimeClassName,
+				},
+			}
+		})
+
+		It("should recognize a runtimeClassName change", func() {
+			Expect(podTemplateSpecChanges(lhs, rhs)).To(ConsistOf("runtimeClassName:  -> gvisor"))
+		})
+	})
+
 	Context("different emptyDir volumes declared", func() {
 		JustBeforeEach(func() {
 			rhs = v1.PodTemplateSpec{
@@ -757,4 +827,35 @@ var _ = Describe("podtemplate", func() {
 			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeTrue())
 		})
 	})
+
+	Context("changes", func() {
+		JustBeforeEach(func() {
+			nodeContainer.Image = differentImageName
+			nodeContainer.Env = []v1.EnvVar{{Name: "HEAP_DUMP", Value: "true"}}
+			nodeContainer.Resources.Limits = v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+				v1.ResourceCPU:    resource.MustParse("600m"),
+			}
+
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					Volumes: []v1.Volume{
+						secretVolume,
+					},
+				},
+			}
+		})
+
+		It("should describe the changed fields", func() {
+			Expect(podTemplateSpecChanges(lhs, rhs)).To(Equal([]string{
+				"volumes: secretVolume",
+				"containers[testContainer].image: testImage -> testImage2",
+				"containers[testContainer].env: HEAP_DUMP",
+				"containers[testContainer].resources: limits.memory 2Gi -> 4Gi",
+			}))
+		})
+	})
 })
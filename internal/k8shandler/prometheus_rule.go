@@ -31,6 +31,7 @@ func (er *ElasticsearchRequest) CreateOrUpdatePrometheusRules() error {
 	if err != nil {
 		return kverrors.Wrap(err, "failed to build prometheus rule")
 	}
+	applyClusterAlerts(dpl, &rule.Spec)
 
 	dpl.AddOwnerRefTo(rule)
 
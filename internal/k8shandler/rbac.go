@@ -116,6 +116,10 @@ func (er *ElasticsearchRequest) CreateOrUpdateRBAC() error {
 	if err := createOrUpdateClusterRoleBinding(proxyRoleBinding, er.client); err != nil {
 		return err
 	}
+
+	if err := er.createOrUpdateZoneDiscoveryRBAC(); err != nil {
+		return err
+	}
 	return reconcileIndexManagmentRbac(dpl, er.client)
 }
 
@@ -0,0 +1,173 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const realmsSettingPrefix = "xpack.security.authc.realms"
+
+func authenticationRealms(cluster *api.Elasticsearch) []api.AuthenticationRealmSpec {
+	if cluster.Spec.Security == nil {
+		return nil
+	}
+	return cluster.Spec.Security.Realms
+}
+
+// realmSettingPrefix returns the prefix of the settings of the realm in elasticsearch.yml
+func realmSettingPrefix(realm api.AuthenticationRealmSpec) string {
+	return fmt.Sprintf("%s.%s.%s", realmsSettingPrefix, strings.ToLower(string(realm.Type)), realm.Name)
+}
+
+// realmsViolation returns the reason the authentication realms of the cluster are invalid or an
+// empty string. The names of the realms and of the settings of a realm must be unique and the
+// order is set by the order of the realm
+func realmsViolation(cluster *api.Elasticsearch) string {
+	names := map[string]bool{}
+	for _, realm := range authenticationRealms(cluster) {
+		if names[realm.Name] {
+			return fmt.Sprintf("The realm %q is defined more than once", realm.Name)
+		}
+		names[realm.Name] = true
+
+		settings := map[string]bool{}
+		for name := range realm.Settings {
+			if name == "" || strings.ContainsAny(name, " \t\n") {
+				return fmt.Sprintf("The realm %q has an invalid setting name %q", realm.Name, name)
+			}
+			if name == "order" {
+				return fmt.Sprintf("The order of the realm %q must be set by its order field", realm.Name)
+			}
+			settings[name] = true
+		}
+		for _, secure := range realm.SecureSettings {
+			if settings[secure.Name] {
+				return fmt.Sprintf("The setting %q of the realm %q is set more than once", secure.Name, realm.Name)
+			}
+			settings[secure.Name] = true
+		}
+	}
+	return ""
+}
+
+// renderRealms returns the authentication realms of the cluster to append to elasticsearch.yml.
+// The settings are rendered by their dotted names
+func renderRealms(cluster *api.Elasticsearch) (string, error) {
+	realms := authenticationRealms(cluster)
+	if len(realms) == 0 {
+		return "", nil
+	}
+
+	values := map[string]string{}
+	for _, realm := range realms {
+		prefix := realmSettingPrefix(realm)
+		values[prefix+".order"] = fmt.Sprintf("%d", realm.Order)
+		for name, value := range realm.Settings {
+			values[prefix+"."+name] = value
+		}
+	}
+
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	settings := yaml.MapSlice{}
+	for _, name := range names {
+		settings = append(settings, yaml.MapItem{Key: name, Value: values[name]})
+	}
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return "\n# authentication realms of the cluster\n" + string(out), nil
+}
+
+// realmSecureSettingsSources returns the Secrets holding the secure settings of the realms of the
+// cluster. Their keys are mounted by the full names of the settings
+func realmSecureSettingsSources(cluster *api.Elasticsearch) []secureSettingsSource {
+	sources := []secureSettingsSource{}
+	index := map[string]int{}
+	for _, realm := range authenticationRealms(cluster) {
+		for _, secure := range realm.SecureSettings {
+			name := secure.SecretRef.Name
+			i, found := index[name]
+			if !found {
+				i = len(sources)
+				index[name] = i
+				sources = append(sources, secureSettingsSource{secretName: name})
+			}
+			sources[i].items = append(sources[i].items, v1.KeyToPath{
+				Key:  secure.Key,
+				Path: realmSettingPrefix(realm) + "." + secure.Name,
+			})
+		}
+	}
+	return sources
+}
+
+// getRealmSecureSettingsSecrets returns the secure settings of the realms of the cluster as
+// Secrets keyed by the full names of the settings. Missing Secrets and keys are skipped as they
+// block the cluster
+func getRealmSecureSettingsSecrets(cluster *api.Elasticsearch, c client.Client) ([]v1.Secret, error) {
+	secrets := []v1.Secret{}
+	for _, source := range realmSecureSettingsSources(cluster) {
+		secret := v1.Secret{}
+		key := types.NamespacedName{Name: source.secretName, Namespace: cluster.Namespace}
+		if err := c.Get(context.TODO(), key, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, kverrors.Wrap(err, "failed to get realm secure settings secret",
+				"secret", source.secretName)
+		}
+
+		settings := v1.Secret{}
+		settings.Name = secret.Name
+		settings.Data = map[string][]byte{}
+		for _, item := range source.items {
+			if value, ok := secret.Data[item.Key]; ok {
+				settings.Data[item.Path] = value
+			}
+		}
+		secrets = append(secrets, settings)
+	}
+	return secrets, nil
+}
+
+// missingRealmSecretKeys returns the keys of the secure settings of the realms missing from their
+// existing Secrets. The nodes cannot mount the Secrets until the keys are added
+func (er *ElasticsearchRequest) missingRealmSecretKeys() ([]string, error) {
+	cluster := er.cluster
+	missing := []string{}
+	for _, source := range realmSecureSettingsSources(cluster) {
+		secret := v1.Secret{}
+		key := types.NamespacedName{Name: source.secretName, Namespace: cluster.Namespace}
+		if err := er.client.Get(context.TODO(), key, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, kverrors.Wrap(err, "failed to get realm secure settings secret",
+				"secret", source.secretName)
+		}
+
+		for _, item := range source.items {
+			if _, ok := secret.Data[item.Key]; !ok {
+				missing = append(missing, fmt.Sprintf("%s/%s", source.secretName, item.Key))
+			}
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,86 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Authentication realms", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Security: &api.SecuritySpec{
+					Realms: []api.AuthenticationRealmSpec{
+						{
+							Name:  "sso",
+							Type:  api.AuthenticationRealmOIDC,
+							Order: 2,
+							Settings: map[string]string{
+								"rp.client_id": "logging",
+								"op.issuer":    "https://sso.example.com",
+							},
+							SecureSettings: []api.RealmSecureSetting{
+								{Name: "rp.client_secret", SecretRef: api.CertSecretReference{Name: "sso"}, Key: "client-secret"},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should render the settings of the realms by their dotted names", func() {
+		realms, err := renderRealms(cluster)
+		Expect(err).To(BeNil())
+		Expect(realms).To(Equal(`
+# authentication realms of the cluster
+xpack.security.authc.realms.oidc.sso.op.issuer: https://sso.example.com
+xpack.security.authc.realms.oidc.sso.order: "2"
+xpack.security.authc.realms.oidc.sso.rp.client_id: logging
+`))
+	})
+
+	It("should reject realms defined more than once", func() {
+		cluster.Spec.Security.Realms = append(cluster.Spec.Security.Realms, api.AuthenticationRealmSpec{Name: "sso", Type: api.AuthenticationRealmSAML})
+		Expect(realmsViolation(cluster)).To(Equal(`The realm "sso" is defined more than once`))
+	})
+
+	It("should add the secure settings of the realms to the keystores by their full names", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sso", Namespace: "openshift-logging"},
+			Data:       map[string][]byte{"client-secret": []byte("secret")},
+		}
+		key := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-secure-settings-hash-key", Namespace: "openshift-logging"},
+			Data:       map[string][]byte{secureSettingsHashKey: []byte("key")},
+		}
+		c := fake.NewFakeClientWithScheme(s, secret, key)
+
+		settings, err := newSecureSettings(cluster, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).ToNot(BeNil())
+		Expect(newSecureSettingsVolumes(settings)[1].Secret.Items).To(Equal([]v1.KeyToPath{
+			{Key: "client-secret", Path: "xpack.security.authc.realms.oidc.sso.rp.client_secret"},
+		}))
+
+		secret.Data["client-secret"] = []byte("rotated")
+		c = fake.NewFakeClientWithScheme(s, secret, key)
+		rotated, err := newSecureSettings(cluster, c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotated.staticHash).ToNot(Equal(settings.staticHash))
+	})
+})
@@ -9,7 +9,9 @@ import (
 	elasticsearchv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -18,6 +20,7 @@ type ElasticsearchRequest struct {
 	client   client.Client
 	cluster  *elasticsearchv1.Elasticsearch
 	esClient elasticsearch.Client
+	recorder record.EventRecorder
 	ll       logr.Logger
 }
 
@@ -74,16 +77,101 @@ func SecretReconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClien
 	return nil
 }
 
-func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client) error {
+// newElasticsearchRequestFor returns a request for the named Elasticsearch cluster or nil if it does not exist
+func newElasticsearchRequestFor(clusterName, namespace string, requestClient client.Client) (*ElasticsearchRequest, error) {
+	cluster := &elasticsearchv1.Elasticsearch{}
+	key := types.NamespacedName{Name: clusterName, Namespace: namespace}
+	if err := requestClient.Get(context.TODO(), key, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get elasticsearch cluster",
+			"cluster", clusterName,
+			"namespace", namespace)
+	}
+
+	er := &ElasticsearchRequest{
+		client:   requestClient,
+		cluster:  cluster,
+		esClient: elasticsearch.NewClient(cluster.Name, cluster.Namespace, requestClient),
+		ll:       log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace),
+	}
+
+	// Ensure the requests to the cluster authenticate with the credentials of the operator if requested
+	if err := er.loadOperatorCredentials(); err != nil {
+		return nil, kverrors.Wrap(err, "failed to load operator credentials",
+			"cluster", clusterName,
+			"namespace", namespace)
+	}
+
+	return er, nil
+}
+
+func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client, recorder record.EventRecorder) error {
 	esClient := elasticsearch.NewClient(requestCluster.Name, requestCluster.Namespace, requestClient)
 
 	elasticsearchRequest := ElasticsearchRequest{
 		client:   requestClient,
 		cluster:  requestCluster,
 		esClient: esClient,
+		recorder: recorder,
 		ll:       log.WithValues("cluster", requestCluster.Name, "namespace", requestCluster.Namespace),
 	}
 
+	// Ensure the version of the operator reconciling the cluster is recorded
+	if err := elasticsearchRequest.RecordOperatorVersion(); err != nil {
+		return kverrors.Wrap(err, "Failed to record operator version for Elasticsearch cluster")
+	}
+
+	// Ensure the requests to the cluster use the scheme of its REST API
+	elasticsearch.SetHTTPTLS(requestCluster.Name, requestCluster.Namespace, httpTLSEnabled(requestCluster))
+
+	// Ensure the requests to the cluster are restricted to FIPS-approved TLS if requested
+	elasticsearch.SetFIPSMode(requestCluster.Name, requestCluster.Namespace, fipsModeEnabled(requestCluster))
+
+	// Ensure the requests to the cluster authenticate with the credentials of the operator if requested
+	if err := elasticsearchRequest.ReconcileOperatorCredentials(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile operator credentials for Elasticsearch cluster")
+	}
+
+	// Ensure the health of the cluster is shared by all its nodes
+	elasticsearch.WatchClusterHealth(requestCluster.Name, requestCluster.Namespace, requestClient)
+
+	// Ensure the deletion of the cluster waits for its shutdown if requested
+	if err := elasticsearchRequest.ReconcileShutdownFinalizer(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile shutdown finalizer for Elasticsearch cluster")
+	}
+
+	// Ensure the deletion of the cluster deletes the claims of the nodes if requested
+	if err := elasticsearchRequest.ReconcileStorageFinalizer(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile storage finalizer for Elasticsearch cluster")
+	}
+
+	// Ensure the certificates of the cluster are issued by cert-manager if requested
+	if err := elasticsearchRequest.ReconcileCertManagerCertificates(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile cert-manager certificates for Elasticsearch cluster")
+	}
+
+	// Ensure the external prerequisites of the cluster are reported
+	if err := elasticsearchRequest.CheckBlockingDependencies(); err != nil {
+		return kverrors.Wrap(err, "Failed to check blocking dependencies for Elasticsearch cluster")
+	}
+
+	// Ensure the certificates of the cluster supplied by the user are valid and in use
+	if err := elasticsearchRequest.ReconcileCustomCertificates(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile custom certificates for Elasticsearch cluster")
+	}
+
+	// Ensure the expiry of the certificates is reported and the nodes load them before they expire
+	if err := elasticsearchRequest.ReconcileCertificateExpiry(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile certificate expiry for Elasticsearch cluster")
+	}
+
+	// Ensure the data nodes add up to the count set through the scale subresource
+	if err := elasticsearchRequest.ApplyDataNodeCount(); err != nil {
+		return kverrors.Wrap(err, "Failed to apply data node count for Elasticsearch cluster")
+	}
+
 	// Ensure existence of servicesaccount
 	if err := elasticsearchRequest.CreateOrUpdateServiceAccount(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile ServiceAccount for Elasticsearch cluster")
@@ -103,15 +191,60 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		return kverrors.Wrap(err, "Failed to reconcile Services for Elasticsearch cluster")
 	}
 
+	// Ensure drains cannot take out more than one pod of the nodes with the same roles
+	if err := elasticsearchRequest.CreateOrUpdatePodDisruptionBudgets(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile PodDisruptionBudgets for Elasticsearch cluster")
+	}
+
+	// Ensure only the declared consumers reach the REST API and only the nodes the transport port
+	if err := elasticsearchRequest.CreateOrUpdateNetworkPolicies(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile NetworkPolicies for Elasticsearch cluster")
+	}
+
 	if err := elasticsearchRequest.CreateOrUpdateDashboards(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile Dashboards for Elasticsearch cluster")
 	}
 
+	// Ensure the hot data nodes are scaled up while the ingestion is backlogged
+	if err := elasticsearchRequest.ReconcileBacklogScaling(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile backlog scaling for Elasticsearch cluster")
+	}
+
+	// Surface nodes failing the bootstrap check of vm.max_map_count
+	if err := elasticsearchRequest.CheckMaxMapCount(); err != nil {
+		return kverrors.Wrap(err, "Failed to check vm.max_map_count of the nodes of the Elasticsearch cluster")
+	}
+
+	// Ensure the key of the hashes of the secure settings exists before the pod templates use it
+	if err := elasticsearchRequest.CreateSecureSettingsHashKey(); err != nil {
+		return kverrors.Wrap(err, "Failed to create the secure settings hash key for Elasticsearch cluster")
+	}
+
 	// Ensure Elasticsearch cluster itself is up to spec
 	if err := elasticsearchRequest.CreateOrUpdateElasticsearchCluster(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile Elasticsearch deployment spec")
 	}
 
+	// Ensure the nodes reload the changed reloadable secure settings
+	if err := elasticsearchRequest.ReconcileSecureSettings(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile secure settings for Elasticsearch cluster")
+	}
+
+	// Ensure the search analyzers load the changed reloadable analysis files
+	if err := elasticsearchRequest.ReconcileAnalysisFiles(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile analysis files for Elasticsearch cluster")
+	}
+
+	// Ensure data nodes migrating to a StatefulSet are drained and removed
+	if err := elasticsearchRequest.ReconcileWorkloadMigrations(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile workload migrations for Elasticsearch cluster")
+	}
+
+	// Ensure the claims no node uses anymore are deleted if requested
+	if err := elasticsearchRequest.CollectOrphanedClaims(); err != nil {
+		return kverrors.Wrap(err, "Failed to collect orphaned claims for Elasticsearch cluster")
+	}
+
 	// Ensure existence of service monitors
 	if err := elasticsearchRequest.CreateOrUpdateServiceMonitors(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile Service Monitors for Elasticsearch cluster")
@@ -119,12 +252,15 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 
 	// Ensure existence of prometheus rules
 	if err := elasticsearchRequest.CreateOrUpdatePrometheusRules(); err != nil {
-		// no need to error out here, we can just mark ourselves as degraded and report why
-		elasticsearchRequest.UpdateDegradedCondition(true, "Missing Prometheus Rules", err.Error())
+		// no need to error out here, the missing rules degrade the cluster and are reported
+		elasticsearchRequest.UpdatePrometheusRulesCondition(true, "Missing Prometheus Rules", err.Error())
 	} else {
-		// TODO: when we eventually add additional cases to be degraded we will need to adjust this
-		// so we don't inadvertently remove other degraded status messages
-		elasticsearchRequest.UpdateDegradedCondition(false, "", "")
+		elasticsearchRequest.UpdatePrometheusRulesCondition(false, "", "")
+	}
+
+	// Ensure new indices are allocated to the hot data tier
+	if err := elasticsearchRequest.ReconcileDataTierAllocation(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile data tier allocation for Elasticsearch cluster")
 	}
 
 	// Ensure index management is in place
@@ -132,5 +268,85 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		return kverrors.Wrap(err, "Failed to reconcile IndexMangement for Elasticsearch cluster")
 	}
 
+	// Ensure the component templates and the composable index templates are in place
+	if err := elasticsearchRequest.ReconcileComposableTemplates(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile composable templates for Elasticsearch cluster")
+	}
+
+	// Ensure rollover aliases are bootstrapped and rolled over
+	if err := elasticsearchRequest.ReconcileRolloverAliases(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile rollover aliases for Elasticsearch cluster")
+	}
+
+	// Ensure the refresh interval of the write indices follows their ingest rate
+	if err := elasticsearchRequest.ReconcileRefreshIntervals(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile refresh intervals for Elasticsearch cluster")
+	}
+
+	// Ensure snapshot repositories are periodically verified
+	if err := elasticsearchRequest.CheckSnapshotRepositories(); err != nil {
+		return kverrors.Wrap(err, "Failed to check snapshot repositories for Elasticsearch cluster")
+	}
+
+	// Ensure the disaster recovery manifest is periodically exported
+	if err := elasticsearchRequest.ExportDisasterRecoveryManifest(); err != nil {
+		return kverrors.Wrap(err, "Failed to export disaster recovery manifest for Elasticsearch cluster")
+	}
+
+	// Ensure changes of the cluster metadata are backed up
+	if err := elasticsearchRequest.BackupClusterMetadata(); err != nil {
+		return kverrors.Wrap(err, "Failed to back up metadata for Elasticsearch cluster")
+	}
+
+	// Ensure the accepted spec and the workloads generated for it are recorded in the topology history
+	if err := elasticsearchRequest.RecordTopologyHistory(); err != nil {
+		return kverrors.Wrap(err, "Failed to record topology history for Elasticsearch cluster")
+	}
+
+	// Ensure the clocks of the nodes are in sync
+	if err := elasticsearchRequest.CheckClockSkew(); err != nil {
+		return kverrors.Wrap(err, "Failed to check clock skew for Elasticsearch cluster")
+	}
+
+	// Ensure the shards dominating the load of their node are reported
+	if err := elasticsearchRequest.CheckHotShards(); err != nil {
+		return kverrors.Wrap(err, "Failed to check hot shards for Elasticsearch cluster")
+	}
+
+	// Ensure the critical indices keep enough replicas while a zone is unavailable
+	if err := elasticsearchRequest.ReconcileZoneOutageReplicas(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile zone outage replicas for Elasticsearch cluster")
+	}
+
+	// Close and delete indices according to the retention rules
+	if err := elasticsearchRequest.ReconcileRetention(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile retention rules for Elasticsearch cluster")
+	}
+
+	// Ensure the least critical write aliases are blocked before a node reaches the flood stage
+	if err := elasticsearchRequest.ReconcileDiskPressure(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile disk pressure for Elasticsearch cluster")
+	}
+
+	// Ensure the oldest indices are deleted before a node reaches the flood stage if requested
+	if err := elasticsearchRequest.ReconcileEmergencyRetention(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile emergency retention for Elasticsearch cluster")
+	}
+
+	// Ensure the client certificates are issued and renewed
+	if err := elasticsearchRequest.ReconcileClientCertificates(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile client certificates for Elasticsearch cluster")
+	}
+
+	// Ensure the remote clusters are connected and followed
+	if err := elasticsearchRequest.ReconcileRemoteClusters(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile remote clusters for Elasticsearch cluster")
+	}
+
+	// Ensure data is migrated from the external cluster
+	if err := elasticsearchRequest.ReconcileRemoteReindexMigration(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile remote reindex migration for Elasticsearch cluster")
+	}
+
 	return nil
 }
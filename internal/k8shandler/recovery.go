@@ -76,6 +76,10 @@ func (er *ElasticsearchRequest) podSpecMatchNode(node loggingv1.ElasticsearchNod
 		return false
 	}
 
+	if node.NodeName != podSpec.NodeName {
+		return false
+	}
+
 	tolerations := appendTolerations(node.Tolerations, er.cluster.Spec.Spec.Tolerations)
 
 	if !containsSameTolerations(podSpec.Tolerations, tolerations) {
@@ -182,10 +186,19 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 				break
 			case loggingv1.ElasticsearchRoleMaster:
 				selector["es-node-master"] = "true"
+				break
+			case loggingv1.ElasticsearchRoleIngest:
+				selector["es-node-ingest"] = "true"
+				break
+			case loggingv1.ElasticsearchRoleML:
+				selector["es-node-ml"] = "true"
+				break
+			case loggingv1.ElasticsearchRoleVotingOnly:
+				selector["es-node-voting-only"] = "true"
 			}
 		}
 
-		if isDataNode(node) {
+		if !isStatefulSetNode(node) {
 			var deploymentList *appsv1.DeploymentList
 			deploymentList, err := GetDeploymentList(er.cluster.Namespace, selector, er.client)
 			if err != nil {
@@ -282,14 +295,17 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 	// go through the nodesToMatch and match it based on the roles for the pvc
 	for nodeIndex, node := range nodesToMatch {
 
-		// if the node doesn't have storage defined, skip it
-		if node.Storage.StorageClassName == nil {
+		// if the node doesn't have persistent storage defined, skip it
+		if node.Storage.StorageClassName == nil || usesEphemeralStorage(node) {
 			continue
 		}
 
 		isClientNode := false
 		isDataNode := false
 		isMasterNode := false
+		isIngestNode := false
+		isMLNode := false
+		isVotingOnlyNode := false
 
 		for _, role := range node.Roles {
 			switch role {
@@ -301,6 +317,15 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 				break
 			case loggingv1.ElasticsearchRoleMaster:
 				isMasterNode = true
+				break
+			case loggingv1.ElasticsearchRoleIngest:
+				isIngestNode = true
+				break
+			case loggingv1.ElasticsearchRoleML:
+				isMLNode = true
+				break
+			case loggingv1.ElasticsearchRoleVotingOnly:
+				isVotingOnlyNode = true
 			}
 		}
 
@@ -330,6 +355,18 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 				continue
 			}
 
+			if isIngestNode != strings.Contains(role, "i") {
+				continue
+			}
+
+			if isMLNode != strings.Contains(role, "l") {
+				continue
+			}
+
+			if isVotingOnlyNode != strings.Contains(role, "v") {
+				continue
+			}
+
 			if node.NodeCount != uuidCounts[uuid] {
 				continue
 			}
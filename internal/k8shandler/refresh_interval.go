@@ -0,0 +1,206 @@
+package k8shandler
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const defaultBusyRefreshInterval = "30s"
+
+var (
+	// indexingSamples holds the last sample of the indexing totals of the write indices per cluster
+	indexingSamples      = map[string]*indexingSample{}
+	indexingSamplesMutex sync.Mutex
+)
+
+type indexingSample struct {
+	taken  time.Time
+	totals map[string]int64
+}
+
+// ReconcileRefreshIntervals raises the refresh interval of the write indices with a high ingest rate
+// and restores it once the rate dropped or the index was rolled over
+func (er *ElasticsearchRequest) ReconcileRefreshIntervals() error {
+	cluster := er.cluster
+	policies := cluster.Spec.RefreshIntervalPolicies
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+
+	if len(policies) == 0 && len(cluster.Status.RaisedRefreshIntervals) == 0 {
+		indexingSamplesMutex.Lock()
+		delete(indexingSamples, key)
+		indexingSamplesMutex.Unlock()
+		return nil
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	writeIndices, err := er.esClient.ListWriteIndices()
+	if err != nil {
+		return err
+	}
+
+	rates := map[string]int64{}
+	if len(policies) > 0 {
+		patterns := make([]string, 0, len(policies))
+		for _, policy := range policies {
+			patterns = append(patterns, policy.IndexPattern)
+		}
+		totals, err := er.esClient.GetIndexingTotals(strings.Join(patterns, ","))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		indexingSamplesMutex.Lock()
+		previous := indexingSamples[key]
+		indexingSamples[key] = &indexingSample{taken: now, totals: totals}
+		indexingSamplesMutex.Unlock()
+
+		if previous == nil {
+			return nil
+		}
+		rates = indexingRates(previous, totals, now)
+	}
+
+	raised := map[string]api.RaisedRefreshIntervalStatus{}
+	for _, status := range cluster.Status.RaisedRefreshIntervals {
+		raised[status.Index] = status
+	}
+
+	statuses := []api.RaisedRefreshIntervalStatus{}
+	for _, index := range writeIndices.List() {
+		policy := refreshIntervalPolicy(policies, index)
+		if policy == nil {
+			continue
+		}
+		rate, sampled := rates[index]
+
+		if status, ok := raised[index]; ok {
+			delete(raised, index)
+			if !sampled || rate >= lowIngestRate(policy) {
+				statuses = append(statuses, status)
+				continue
+			}
+			if err := er.restoreRefreshInterval(status, rate); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !sampled || rate < policy.HighIngestRate {
+			continue
+		}
+		status, err := er.raiseRefreshInterval(index, policy, rate)
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, status)
+	}
+
+	// the indices rolled over or no longer matching a policy
+	for _, status := range raised {
+		if err := er.restoreRefreshInterval(status, 0); err != nil {
+			return err
+		}
+	}
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	return er.updateRaisedRefreshIntervals(statuses)
+}
+
+// indexingRates returns the documents indexed per second into each index between the samples
+func indexingRates(previous *indexingSample, totals map[string]int64, now time.Time) map[string]int64 {
+	rates := map[string]int64{}
+	elapsed := now.Sub(previous.taken).Seconds()
+	if elapsed <= 0 {
+		return rates
+	}
+	for index, total := range totals {
+		before, ok := previous.totals[index]
+		if !ok || total < before {
+			continue
+		}
+		rates[index] = int64(float64(total-before) / elapsed)
+	}
+	return rates
+}
+
+// refreshIntervalPolicy returns the first policy whose pattern matches the index
+func refreshIntervalPolicy(policies []api.RefreshIntervalPolicySpec, index string) *api.RefreshIntervalPolicySpec {
+	for i := range policies {
+		if matched, _ := path.Match(policies[i].IndexPattern, index); matched {
+			return &policies[i]
+		}
+	}
+	return nil
+}
+
+func lowIngestRate(policy *api.RefreshIntervalPolicySpec) int64 {
+	if policy.LowIngestRate != nil {
+		return *policy.LowIngestRate
+	}
+	return policy.HighIngestRate / 2
+}
+
+func (er *ElasticsearchRequest) raiseRefreshInterval(index string, policy *api.RefreshIntervalPolicySpec, rate int64) (api.RaisedRefreshIntervalStatus, error) {
+	interval := policy.RefreshInterval
+	if interval == "" {
+		interval = defaultBusyRefreshInterval
+	}
+
+	intervals, err := er.esClient.GetRefreshIntervals(index)
+	if err != nil {
+		return api.RaisedRefreshIntervalStatus{}, err
+	}
+
+	er.L().Info("Raising refresh interval of write index during high ingest", "index", index, "rate", rate, "interval", interval)
+	if err := er.esClient.SetRefreshInterval(index, interval); err != nil {
+		return api.RaisedRefreshIntervalStatus{}, err
+	}
+	return api.RaisedRefreshIntervalStatus{
+		Index:                   index,
+		OriginalRefreshInterval: intervals[index],
+		Since:                   metav1.Now(),
+	}, nil
+}
+
+func (er *ElasticsearchRequest) restoreRefreshInterval(status api.RaisedRefreshIntervalStatus, rate int64) error {
+	er.L().Info("Restoring refresh interval of index", "index", status.Index, "rate", rate, "interval", status.OriginalRefreshInterval)
+	return er.esClient.SetRefreshInterval(status.Index, status.OriginalRefreshInterval)
+}
+
+func (er *ElasticsearchRequest) updateRaisedRefreshIntervals(statuses []api.RaisedRefreshIntervalStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.RaisedRefreshIntervals, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.RaisedRefreshIntervals, statuses) {
+			return nil
+		}
+
+		cluster.Status.RaisedRefreshIntervals = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update raised refresh intervals")
+}
@@ -0,0 +1,130 @@
+package k8shandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Refresh intervals", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	newRequest := func(raised []api.RaisedRefreshIntervalStatus, indexed int64) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RefreshIntervalPolicies: []api.RefreshIntervalPolicySpec{
+					{IndexPattern: "app-*", HighIngestRate: 1000},
+				},
+			},
+			Status: api.ElasticsearchStatus{RaisedRefreshIntervals: raised},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_alias": {
+					{StatusCode: http.StatusOK, Body: `{
+						"app-000001": {"aliases": {"app": {"is_write_index": false}}},
+						"app-000002": {"aliases": {"app": {"is_write_index": true}}}
+					}`},
+				},
+				"app-*/_stats/indexing?filter_path=indices.*.primaries.indexing.index_total": {
+					{StatusCode: http.StatusOK, Body: `{"indices": {
+						"app-000001": {"primaries": {"indexing": {"index_total": 5000}}},
+						"app-000002": {"primaries": {"indexing": {"index_total": 100000}}}
+					}}`},
+				},
+				"app-000002/_settings/index.refresh_interval": {
+					{StatusCode: http.StatusOK, Body: `{"app-000002": {"settings": {"index": {"refresh_interval": "5s"}}}}`},
+				},
+				"app-000001/_settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+				"app-000002/_settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+			},
+		)
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		indexingSamples[nodeMapKey(cluster.Name, cluster.Namespace)] = &indexingSample{
+			taken:  time.Now().Add(-10 * time.Second),
+			totals: map[string]int64{"app-000001": 5000, "app-000002": 100000 - indexed},
+		}
+	}
+
+	AfterEach(func() {
+		delete(indexingSamples, nodeMapKey(cluster.Name, cluster.Namespace))
+	})
+
+	It("should raise the refresh interval of a write index during high ingest", func() {
+		newRequest(nil, 50000)
+
+		Expect(request.ReconcileRefreshIntervals()).To(Succeed())
+		req, found := chatter.GetRequest("app-000002/_settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"index.refresh_interval": "30s"}`)
+		Expect(cluster.Status.RaisedRefreshIntervals).To(HaveLen(1))
+		Expect(cluster.Status.RaisedRefreshIntervals[0].Index).To(Equal("app-000002"))
+		Expect(cluster.Status.RaisedRefreshIntervals[0].OriginalRefreshInterval).To(Equal("5s"))
+	})
+
+	It("should restore the refresh interval once the ingest dropped", func() {
+		newRequest([]api.RaisedRefreshIntervalStatus{
+			{Index: "app-000002", OriginalRefreshInterval: "5s"},
+		}, 1000)
+
+		Expect(request.ReconcileRefreshIntervals()).To(Succeed())
+		req, found := chatter.GetRequest("app-000002/_settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"index.refresh_interval": "5s"}`)
+		Expect(cluster.Status.RaisedRefreshIntervals).To(BeEmpty())
+	})
+
+	It("should restore the default refresh interval of a rolled over index", func() {
+		newRequest([]api.RaisedRefreshIntervalStatus{
+			{Index: "app-000001"},
+		}, 1000)
+
+		Expect(request.ReconcileRefreshIntervals()).To(Succeed())
+		req, found := chatter.GetRequest("app-000001/_settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"index.refresh_interval": null}`)
+		Expect(cluster.Status.RaisedRefreshIntervals).To(BeEmpty())
+	})
+})
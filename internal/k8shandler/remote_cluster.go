@@ -0,0 +1,364 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	remoteCAImportName         = "remote-ca-import"
+	remoteCAVolumeName         = "remote-ca"
+	remoteCAPath               = "/etc/elasticsearch/remote-ca"
+	remoteTruststoreVolumeName = "remote-truststore"
+	remoteTruststorePath       = "/etc/elasticsearch/remote-truststore"
+	transportTruststoreFile    = "searchguard.truststore"
+
+	// remoteCASecretKey is the key of the CA certificate in the secret of an external remote cluster
+	remoteCASecretKey = "ca.crt"
+	// clusterCASecretKey is the key of the CA certificate in the secret of a managed cluster
+	clusterCASecretKey = "admin-ca"
+)
+
+// remoteCAImportScript adds the CA certificates of the remote clusters to a copy of the transport truststore
+const remoteCAImportScript = `
+cp /etc/openshift/elasticsearch/secret/searchguard.truststore /etc/elasticsearch/remote-truststore/searchguard.truststore
+for ca in /etc/elasticsearch/remote-ca/*.crt ; do
+  [ -e "$ca" ] || continue
+  keytool -importcert -noprompt -alias "remote-$(basename "$ca" .crt)" -file "$ca" \
+    -keystore /etc/elasticsearch/remote-truststore/searchguard.truststore -storepass tspass
+done
+`
+
+// ReconcileRemoteClusters connects the cluster to the spec'd remote clusters and creates the
+// follower indices once the connection is established
+func (er *ElasticsearchRequest) ReconcileRemoteClusters() error {
+	cluster := er.cluster
+
+	if len(cluster.Spec.RemoteClusters) == 0 && len(cluster.Status.RemoteClusters) == 0 {
+		return nil
+	}
+
+	trustMessages, err := er.reconcileRemoteClusterTrust()
+	if err != nil {
+		return err
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	info, err := er.esClient.GetRemoteClusterInfo()
+	if err != nil {
+		return err
+	}
+
+	var followers map[string]estypes.FollowerIndexInfo
+	var statuses []api.RemoteClusterStatus
+	for _, spec := range cluster.Spec.RemoteClusters {
+		status := er.reconcileRemoteCluster(spec, info, trustMessages[spec.Name])
+		if status.Ready && len(spec.Followers) > 0 {
+			if followers == nil {
+				if followers, err = er.listFollowerIndices(); err != nil {
+					status.Message = elasticsearchErrorReason(err)
+					statuses = append(statuses, status)
+					continue
+				}
+			}
+			status.Followers = er.reconcileFollowerIndices(spec, followers)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, previous := range cluster.Status.RemoteClusters {
+		if getRemoteClusterStatus(previous.Name, statuses) == nil {
+			if err := er.esClient.UpdateRemoteClusterSettings(previous.Name, nil, nil); err != nil {
+				er.L().Error(err, "failed to remove remote cluster", "remote_cluster", previous.Name)
+			}
+		}
+	}
+
+	return er.updateRemoteClusterStatus(statuses)
+}
+
+// reconcileRemoteCluster updates the settings of the remote cluster and validates the connection
+func (er *ElasticsearchRequest) reconcileRemoteCluster(spec api.RemoteClusterSpec, info map[string]estypes.RemoteClusterInfo, trustMessage string) api.RemoteClusterStatus {
+	status := api.RemoteClusterStatus{Name: spec.Name}
+
+	if trustMessage != "" {
+		status.Message = trustMessage
+		return status
+	}
+
+	seeds := remoteClusterSeeds(spec, er.cluster.Namespace)
+	if len(seeds) == 0 {
+		status.Message = "Either an Elasticsearch cluster or the seeds of an external cluster are required"
+		return status
+	}
+
+	skipUnavailable := spec.SkipUnavailable != nil && *spec.SkipUnavailable
+
+	current, ok := info[spec.Name]
+	if !ok || !reflect.DeepEqual(current.Seeds, seeds) || current.SkipUnavailable != skipUnavailable {
+		if err := er.esClient.UpdateRemoteClusterSettings(spec.Name, seeds, spec.SkipUnavailable); err != nil {
+			er.L().Error(err, "failed to update remote cluster settings", "remote_cluster", spec.Name)
+			status.Message = elasticsearchErrorReason(err)
+			return status
+		}
+		status.Message = "Connecting to the remote cluster"
+		return status
+	}
+
+	status.ConnectedNodes = current.NumNodesConnected
+	status.Ready = current.Connected && current.NumNodesConnected > 0
+	if !status.Ready {
+		status.Message = "Waiting for the connection to the remote cluster. Nodes trust the CA certificate of the remote cluster after their next restart"
+	}
+	return status
+}
+
+// listFollowerIndices returns the follower indices of the cluster by name or an error
+// if cross-cluster replication is not available
+func (er *ElasticsearchRequest) listFollowerIndices() (map[string]estypes.FollowerIndexInfo, error) {
+	available, err := er.esClient.IsCrossClusterReplicationAvailable()
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, kverrors.New("Cross-cluster replication is not available in this cluster")
+	}
+	followers := map[string]estypes.FollowerIndexInfo{}
+
+	indices, err := er.esClient.ListFollowerIndices()
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		followers[index.FollowerIndex] = index
+	}
+	return followers, nil
+}
+
+// reconcileFollowerIndices creates the missing follower indices of the remote cluster
+func (er *ElasticsearchRequest) reconcileFollowerIndices(spec api.RemoteClusterSpec, followers map[string]estypes.FollowerIndexInfo) []api.FollowerIndexStatus {
+	var statuses []api.FollowerIndexStatus
+	for _, follower := range spec.Followers {
+		status := api.FollowerIndexStatus{Name: follower.Name}
+
+		leaderIndex := follower.LeaderIndex
+		if leaderIndex == "" {
+			leaderIndex = follower.Name
+		}
+
+		if current, ok := followers[follower.Name]; ok {
+			status.Status = current.Status
+			if current.RemoteCluster != spec.Name || current.LeaderIndex != leaderIndex {
+				status.Message = fmt.Sprintf("Index follows %s of remote cluster %s", current.LeaderIndex, current.RemoteCluster)
+			}
+			statuses = append(statuses, status)
+			continue
+		}
+
+		err := er.esClient.FollowIndex(follower.Name, &estypes.FollowIndex{
+			RemoteCluster: spec.Name,
+			LeaderIndex:   leaderIndex,
+		})
+		if err != nil {
+			er.L().Error(err, "failed to follow index", "remote_cluster", spec.Name, "index", follower.Name)
+			status.Message = elasticsearchErrorReason(err)
+		} else {
+			status.Status = "active"
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// reconcileRemoteClusterTrust seeds the CA certificates of the remote clusters into a secret imported
+// into the transport truststore of the nodes and returns a message for each remote cluster missing its CA
+func (er *ElasticsearchRequest) reconcileRemoteClusterTrust() (map[string]string, error) {
+	cluster := er.cluster
+
+	messages := map[string]string{}
+	data := map[string][]byte{}
+	for _, spec := range cluster.Spec.RemoteClusters {
+		secretName, key := spec.Secret, remoteCASecretKey
+		if spec.Elasticsearch != "" {
+			secretName, key = spec.Elasticsearch, clusterCASecretKey
+		}
+		if secretName == "" {
+			continue
+		}
+
+		secret := &v1.Secret{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cluster.Namespace}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, kverrors.Wrap(err, "failed to get secret of remote cluster",
+					"remote_cluster", spec.Name,
+					"secret", secretName)
+			}
+			messages[spec.Name] = fmt.Sprintf("Waiting for secret %s with the CA certificate of the remote cluster", secretName)
+			continue
+		}
+		ca, ok := secret.Data[key]
+		if !ok {
+			messages[spec.Name] = fmt.Sprintf("Secret %s has no CA certificate in key %s", secretName, key)
+			continue
+		}
+		data[fmt.Sprintf("%s.crt", spec.Name)] = ca
+	}
+
+	if len(cluster.Spec.RemoteClusters) == 0 {
+		return messages, nil
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteCASecretName(cluster.Name),
+			Namespace: cluster.Namespace,
+			Labels:    cluster.Labels,
+		},
+		Data: data,
+	}
+	cluster.AddOwnerRefTo(secret)
+
+	err := er.client.Create(context.TODO(), secret)
+	if err == nil {
+		return messages, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, kverrors.Wrap(err, "failed to create remote cluster CA secret",
+			"secret", secret.Name)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.Secret{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Data, secret.Data) {
+			return nil
+		}
+		current.Data = secret.Data
+		return er.client.Update(context.TODO(), current)
+	})
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to update remote cluster CA secret",
+			"secret", secret.Name)
+	}
+	return messages, nil
+}
+
+func (er *ElasticsearchRequest) updateRemoteClusterStatus(statuses []api.RemoteClusterStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.RemoteClusters, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.RemoteClusters, statuses) {
+			return nil
+		}
+
+		cluster.Status.RemoteClusters = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update remote cluster status")
+}
+
+// remoteClusterSeeds returns the transport addresses of the remote cluster
+func remoteClusterSeeds(spec api.RemoteClusterSpec, namespace string) []string {
+	if spec.Elasticsearch != "" {
+		return []string{fmt.Sprintf("%s-cluster.%s.svc:9300", spec.Elasticsearch, namespace)}
+	}
+	return spec.Seeds
+}
+
+// transportTruststore returns the truststore used to verify the transport certificates of the nodes
+func transportTruststore(cluster *api.Elasticsearch) string {
+	if len(cluster.Spec.RemoteClusters) > 0 {
+		return path.Join(remoteTruststorePath, transportTruststoreFile)
+	}
+	return path.Join("/etc/elasticsearch/secret", transportTruststoreFile)
+}
+
+// newRemoteCAImportContainer returns an init container adding the CA certificates of the remote
+// clusters to the transport truststore
+func newRemoteCAImportContainer(imageName string) v1.Container {
+	return v1.Container{
+		Name:            remoteCAImportName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", remoteCAImportScript},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      "certificates",
+				MountPath: elasticsearchCertsPath,
+			},
+			{
+				Name:      remoteCAVolumeName,
+				MountPath: remoteCAPath,
+			},
+			{
+				Name:      remoteTruststoreVolumeName,
+				MountPath: remoteTruststorePath,
+			},
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	}
+}
+
+func newRemoteTrustVolumes(clusterName string) []v1.Volume {
+	optional := true
+	return []v1.Volume{
+		{
+			Name: remoteCAVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: remoteCASecretName(clusterName),
+					Optional:   &optional,
+				},
+			},
+		},
+		{
+			Name: remoteTruststoreVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+}
+
+func remoteCASecretName(clusterName string) string {
+	return fmt.Sprintf("%s-remote-ca", clusterName)
+}
+
+func getRemoteClusterStatus(name string, statuses []api.RemoteClusterStatus) *api.RemoteClusterStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
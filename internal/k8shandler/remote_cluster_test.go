@@ -0,0 +1,163 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Remote clusters", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		spec    api.RemoteClusterSpec
+	)
+
+	BeforeEach(func() {
+		spec = api.RemoteClusterSpec{
+			Name:          "leader",
+			Elasticsearch: "elasticsearch-leader",
+			Followers:     []api.FollowerIndexSpec{{Name: "audit-follower", LeaderIndex: "audit"}},
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch",
+					Namespace: "openshift-logging",
+				},
+				Spec: api.ElasticsearchSpec{
+					RemoteClusters: []api.RemoteClusterSpec{spec},
+				},
+			},
+		}
+	})
+
+	It("should use the transport service of a managed remote cluster as seed", func() {
+		Expect(remoteClusterSeeds(spec, "openshift-logging")).To(Equal([]string{"elasticsearch-leader-cluster.openshift-logging.svc:9300"}))
+	})
+
+	It("should trust the transport certificates of the remote clusters", func() {
+		Expect(transportTruststore(request.cluster)).To(Equal("/etc/elasticsearch/remote-truststore/searchguard.truststore"))
+
+		template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			remoteTrust: true,
+		})
+		Expect(template.Spec.InitContainers).To(HaveLen(1))
+		Expect(template.Spec.InitContainers[0].Name).To(Equal(remoteCAImportName))
+		Expect(template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      remoteTruststoreVolumeName,
+			MountPath: remoteTruststorePath,
+		}))
+		Expect(template.Spec.Volumes).To(ContainElement(newRemoteTrustVolumes("elasticsearch")[0]))
+	})
+
+	Describe("#reconcileRemoteCluster", func() {
+		It("should configure the seeds of a new remote cluster", func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_cluster/settings": {
+						{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			status := request.reconcileRemoteCluster(spec, map[string]estypes.RemoteClusterInfo{}, "")
+
+			Expect(status.Ready).To(BeFalse())
+			Expect(status.Message).To(Equal("Connecting to the remote cluster"))
+			req, _ := chatter.GetRequest("_cluster/settings")
+			helpers.ExpectJSON(req.Body).ToEqual(`{"persistent": {"cluster.remote.leader.seeds": ["elasticsearch-leader-cluster.openshift-logging.svc:9300"], "cluster.remote.leader.skip_unavailable": null}}`)
+		})
+
+		It("should skip an unavailable search-only remote cluster if configured", func() {
+			skipUnavailable := true
+			search := api.RemoteClusterSpec{
+				Name:            "search",
+				Seeds:           []string{"es.example.com:9300"},
+				SkipUnavailable: &skipUnavailable,
+			}
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_cluster/settings": {
+						{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			status := request.reconcileRemoteCluster(search, map[string]estypes.RemoteClusterInfo{
+				"search": {
+					Seeds:             []string{"es.example.com:9300"},
+					Connected:         true,
+					NumNodesConnected: 1,
+				},
+			}, "")
+
+			Expect(status.Ready).To(BeFalse())
+			req, _ := chatter.GetRequest("_cluster/settings")
+			helpers.ExpectJSON(req.Body).ToEqual(`{"persistent": {"cluster.remote.search.seeds": ["es.example.com:9300"], "cluster.remote.search.skip_unavailable": true}}`)
+		})
+
+		It("should be ready once connected to the remote cluster", func() {
+			status := request.reconcileRemoteCluster(spec, map[string]estypes.RemoteClusterInfo{
+				"leader": {
+					Seeds:             []string{"elasticsearch-leader-cluster.openshift-logging.svc:9300"},
+					Connected:         true,
+					NumNodesConnected: 3,
+				},
+			}, "")
+
+			Expect(status.Ready).To(BeTrue())
+			Expect(status.ConnectedNodes).To(Equal(int32(3)))
+			Expect(status.Message).To(BeEmpty())
+		})
+
+		It("should not connect before the CA certificate of the remote cluster is available", func() {
+			status := request.reconcileRemoteCluster(spec, map[string]estypes.RemoteClusterInfo{}, "Waiting for secret elasticsearch-leader")
+
+			Expect(status.Ready).To(BeFalse())
+			Expect(status.Message).To(Equal("Waiting for secret elasticsearch-leader"))
+		})
+	})
+
+	Describe("#reconcileFollowerIndices", func() {
+		It("should follow the leader index of the remote cluster", func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"audit-follower/_ccr/follow": {
+						{StatusCode: http.StatusOK, Body: `{"follow_index_created": true}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			statuses := request.reconcileFollowerIndices(spec, map[string]estypes.FollowerIndexInfo{})
+
+			Expect(statuses).To(Equal([]api.FollowerIndexStatus{{Name: "audit-follower", Status: "active"}}))
+			req, _ := chatter.GetRequest("audit-follower/_ccr/follow")
+			helpers.ExpectJSON(req.Body).ToEqual(`{"remote_cluster": "leader", "leader_index": "audit"}`)
+		})
+
+		It("should report the status of an existing follower index", func() {
+			statuses := request.reconcileFollowerIndices(spec, map[string]estypes.FollowerIndexInfo{
+				"audit-follower": {FollowerIndex: "audit-follower", RemoteCluster: "leader", LeaderIndex: "audit", Status: "paused"},
+			})
+
+			Expect(statuses).To(Equal([]api.FollowerIndexStatus{{Name: "audit-follower", Status: "paused"}}))
+		})
+	})
+})
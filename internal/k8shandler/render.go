@@ -0,0 +1,93 @@
+package k8shandler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// renderedNodeUUID stands in for the generated UUID of nodes which have not been reconciled yet
+const renderedNodeUUID = "<uuid>"
+
+// RenderConfiguration writes the configuration files and the environment of the Elasticsearch
+// container of every node of the cluster without applying them. The JVM heap is the half of
+// INSTANCE_RAM. The hosts of ElasticsearchReindex resources are not part of the
+// reindex whitelist as they are only known to the running operator
+func RenderConfiguration(cluster *api.Elasticsearch, w io.Writer) error {
+	configmap := newClusterConfigMap(cluster, nil)
+	if configmap == nil {
+		return kverrors.New("failed to render elasticsearch configmap",
+			"cluster", cluster.Name)
+	}
+
+	for _, file := range []string{esConfig, log4jConfig, indexSettingsConfig} {
+		if _, err := fmt.Fprintf(w, "# %s\n%s\n", file, configmap.Data[file]); err != nil {
+			return kverrors.Wrap(err, "failed to write configuration file", "file", file)
+		}
+	}
+
+	for _, node := range cluster.Spec.Nodes {
+		roleMap := getNodeRoleMap(node)
+		uuid := renderedNodeUUID
+		if node.GenUUID != nil {
+			uuid = *node.GenUUID
+		}
+		nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(uuid, roleMap))
+		resources := newESResourceRequirements(node.Resources, cluster.Spec.Spec.Resources)
+
+		templateOptions, err := newPodTemplateOptions(cluster, node, nil)
+		if err != nil {
+			return err
+		}
+		envVars := newEnvVars(nodeName, newInstanceRAM(nodeHeapSize(node, resources)), roleMap, templateOptions)
+		if nodeScratchSpace(cluster.Spec.Spec, node) != nil {
+			envVars = append(envVars, newScratchSpaceEnvVar())
+		}
+		envVars = append(envVars, newThreadPoolEnvVars(cluster, node)...)
+		envVars = appendEnvVars(envVars, mergeEnvVars(node.Env, cluster.Spec.Spec.Env))
+
+		if _, err := fmt.Fprintf(w, "# environment of %s\n%s\n", nodeName, renderEnvVars(envVars)); err != nil {
+			return kverrors.Wrap(err, "failed to write node environment", "node", nodeName)
+		}
+
+		if options := nodeJVMOptions(node); len(options) > 0 {
+			if _, err := fmt.Fprintf(w, "# jvm options of %s\n%s\n", nodeName, renderJVMOptions(options)); err != nil {
+				return kverrors.Wrap(err, "failed to write node jvm options", "node", nodeName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderEnvVars returns the environment variables one per line sorted by name. Variables set
+// from a field of the pod show the path of the field, the ones set from a Secret or ConfigMap
+// show the name and key instead of the value
+func renderEnvVars(envVars []v1.EnvVar) string {
+	lines := make([]string, 0, len(envVars))
+	for _, envVar := range envVars {
+		value := envVar.Value
+		if envVar.ValueFrom != nil && envVar.ValueFrom.FieldRef != nil {
+			value = fmt.Sprintf("<%s>", envVar.ValueFrom.FieldRef.FieldPath)
+		}
+		if envVar.ValueFrom != nil && envVar.ValueFrom.ResourceFieldRef != nil {
+			value = fmt.Sprintf("<%s>", envVar.ValueFrom.ResourceFieldRef.Resource)
+		}
+		if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+			ref := envVar.ValueFrom.SecretKeyRef
+			value = fmt.Sprintf("<secret %s/%s>", ref.Name, ref.Key)
+		}
+		if envVar.ValueFrom != nil && envVar.ValueFrom.ConfigMapKeyRef != nil {
+			ref := envVar.ValueFrom.ConfigMapKeyRef
+			value = fmt.Sprintf("<configmap %s/%s>", ref.Name, ref.Key)
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s\n", envVar.Name, value))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,56 @@
+package k8shandler
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Rendering the configuration", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		uuid := "abcd1234"
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy: api.SingleRedundancy,
+				Spec: api.ElasticsearchNodeSpec{
+					Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+					Caches: &api.ElasticsearchCacheSpec{FielddataSize: "20%"},
+				},
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3, GenUUID: &uuid},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 2},
+				},
+			},
+		}
+	})
+
+	It("should write the configuration files and the environment of every node", func() {
+		buf := &bytes.Buffer{}
+		Expect(RenderConfiguration(cluster, buf)).To(Succeed())
+
+		rendered := buf.String()
+		Expect(rendered).To(ContainSubstring("# elasticsearch.yml\n"))
+		Expect(rendered).To(ContainSubstring("ping.unicast.hosts: elasticsearch-cluster.openshift-logging.svc"))
+		Expect(rendered).To(ContainSubstring("fielddata.cache.size: ${FIELDDATA_CACHE_SIZE}"))
+		Expect(rendered).To(ContainSubstring("# log4j2.properties\n"))
+		Expect(rendered).To(ContainSubstring("# index_settings\n"))
+		Expect(rendered).To(ContainSubstring("# environment of elasticsearch-m-abcd1234\n"))
+		Expect(rendered).To(ContainSubstring("# environment of elasticsearch-d-<uuid>\n"))
+		Expect(rendered).To(ContainSubstring("INSTANCE_RAM=4096Mi\n"))
+		Expect(rendered).To(ContainSubstring("FIELDDATA_CACHE_SIZE=20%\n"))
+		Expect(rendered).To(ContainSubstring("POD_IP=<status.podIP>\n"))
+	})
+})
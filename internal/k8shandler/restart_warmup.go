@@ -0,0 +1,51 @@
+package k8shandler
+
+import (
+	"fmt"
+)
+
+const defaultWarmupIterations = 1
+
+// warmUpAfter returns the recovery of a node restart followed by the warm-up queries of the
+// cluster against the restarted node. Failed queries are logged and do not hold up the restart
+func (er *ElasticsearchRequest) warmUpAfter(recovery func() error, node NodeTypeInterface) func() error {
+	return func() error {
+		if err := recovery(); err != nil {
+			return err
+		}
+
+		spec := er.cluster.Spec.RestartWarmup
+		if spec == nil {
+			return nil
+		}
+
+		iterations := int32(defaultWarmupIterations)
+		if spec.Iterations != nil && *spec.Iterations > 0 {
+			iterations = *spec.Iterations
+		}
+
+		names := warmupNodeNames(node)
+		failed := 0
+		for _, query := range spec.Queries {
+			for i := int32(0); i < iterations; i++ {
+				if err := er.esClient.SearchOnNodes(query.IndexPattern, names, query.Query); err != nil {
+					er.L().Error(err, "Failed to run warm-up query against restarted node", "node", node.name(), "pattern", query.IndexPattern)
+					failed++
+					break
+				}
+			}
+		}
+
+		er.L().Info("Warmed up restarted node", "node", node.name(), "queries", len(spec.Queries), "failed", failed)
+		return nil
+	}
+}
+
+// warmupNodeNames returns the names of the Elasticsearch nodes run by the node. The pods of a
+// StatefulSet are numbered
+func warmupNodeNames(node NodeTypeInterface) []string {
+	if _, ok := node.(*statefulSetNode); ok {
+		return []string{fmt.Sprintf("%s-*", node.name())}
+	}
+	return []string{node.name()}
+}
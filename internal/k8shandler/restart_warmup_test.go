@@ -0,0 +1,80 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("restart warm-up", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RestartWarmup: &api.RestartWarmupSpec{
+					Queries: []api.WarmupQuerySpec{
+						{IndexPattern: "app-*", Query: `{"size": 0, "aggs": {"namespaces": {"terms": {"field": "kubernetes.namespace_name"}}}}`},
+						{IndexPattern: "infra-*"},
+					},
+					Iterations: pointer.Int32Ptr(2),
+				},
+			},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"app-*/_search?preference=_only_nodes:elasticsearch-cdm-abc-1&ignore_unavailable=true&allow_no_indices=true": {
+				{StatusCode: http.StatusOK, Body: `{"hits": {"hits": []}}`},
+				{StatusCode: http.StatusOK, Body: `{"hits": {"hits": []}}`},
+			},
+			"infra-*/_search?preference=_only_nodes:elasticsearch-cdm-abc-1&ignore_unavailable=true&allow_no_indices=true": {
+				{StatusCode: http.StatusInternalServerError, Body: `{"error": {"reason": "boom"}}`},
+			},
+		})
+		c := fake.NewFakeClientWithScheme(s, cluster)
+		request = &ElasticsearchRequest{
+			client:   c,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", c, chatter),
+		}
+	})
+
+	It("should run the warm-up queries against the restarted node once it recovered", func() {
+		node := &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1"}}}
+		recovered := false
+		recovery := request.warmUpAfter(func() error {
+			recovered = true
+			return nil
+		}, node)
+
+		Expect(recovery()).To(Succeed())
+		Expect(recovered).To(BeTrue())
+		Expect(chatter.Requests["app-*/_search?preference=_only_nodes:elasticsearch-cdm-abc-1&ignore_unavailable=true&allow_no_indices=true"]).To(HaveLen(2))
+		Expect(chatter.Requests["infra-*/_search?preference=_only_nodes:elasticsearch-cdm-abc-1&ignore_unavailable=true&allow_no_indices=true"]).To(HaveLen(1))
+	})
+
+	It("should name the pods of a StatefulSet", func() {
+		node := &statefulSetNode{self: apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-def"}}}
+		Expect(warmupNodeNames(node)).To(Equal([]string{"elasticsearch-cdm-def-*"}))
+	})
+})
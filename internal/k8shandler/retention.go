@@ -0,0 +1,190 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+const defaultRetentionInterval = time.Hour
+
+var reRetentionAge = regexp.MustCompile(`^([0-9]+)([wdhHms])$`)
+
+// ReconcileRetention executes the retention rules of the cluster once per interval
+func (er *ElasticsearchRequest) ReconcileRetention() error {
+	cluster := er.cluster
+
+	if cluster.Spec.Retention == nil || len(cluster.Spec.Retention.Rules) == 0 {
+		if len(cluster.Status.Retention) == 0 {
+			return nil
+		}
+		return er.updateRetentionStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	interval := defaultRetentionInterval
+	if cluster.Spec.Retention.Interval != nil && cluster.Spec.Retention.Interval.Duration > 0 {
+		interval = cluster.Spec.Retention.Interval.Duration
+	}
+
+	now := time.Now()
+	var writeIndices sets.String
+	var statuses []api.RetentionRuleStatus
+	for _, rule := range cluster.Spec.Retention.Rules {
+		previous := getRetentionRuleStatus(rule.Name, cluster.Status.Retention)
+		if previous != nil && now.Sub(previous.LastRun.Time) < interval {
+			statuses = append(statuses, *previous)
+			continue
+		}
+
+		if writeIndices == nil {
+			var err error
+			if writeIndices, err = er.esClient.ListWriteIndices(); err != nil {
+				return err
+			}
+		}
+		statuses = append(statuses, er.executeRetentionRule(rule, writeIndices, now))
+	}
+
+	return er.updateRetentionStatus(statuses)
+}
+
+// executeRetentionRule deletes or closes the indices matching the rule which are older than
+// the configured ages. Indices written to through an alias are never touched
+func (er *ElasticsearchRequest) executeRetentionRule(rule api.RetentionRuleSpec, writeIndices sets.String, now time.Time) api.RetentionRuleStatus {
+	status := api.RetentionRuleStatus{
+		Name:    rule.Name,
+		LastRun: metav1.NewTime(now),
+	}
+
+	closeAfter, err := retentionAge(rule.CloseAfter)
+	if err != nil {
+		status.Message = fmt.Sprintf("Unsupported closeAfter %q, expected a number of weeks, days, hours, minutes or seconds", rule.CloseAfter)
+		return status
+	}
+	deleteAfter, err := retentionAge(rule.DeleteAfter)
+	if err != nil {
+		status.Message = fmt.Sprintf("Unsupported deleteAfter %q, expected a number of weeks, days, hours, minutes or seconds", rule.DeleteAfter)
+		return status
+	}
+
+	indices, err := er.esClient.ListIndicesCreationDate(rule.IndexPattern)
+	if err != nil {
+		er.L().Error(err, "failed to list indices of retention rule", "rule", rule.Name)
+		status.Message = elasticsearchErrorReason(err)
+		return status
+	}
+
+	var failed []string
+	for _, index := range indices {
+		if writeIndices.Has(index.Index) {
+			continue
+		}
+		if strings.HasPrefix(index.Index, ".") && !strings.HasPrefix(rule.IndexPattern, ".") {
+			continue
+		}
+
+		millis, err := strconv.ParseInt(index.CreationDate, 10, 64)
+		if err != nil {
+			continue
+		}
+		age := now.Sub(time.Unix(0, millis*int64(time.Millisecond)))
+
+		switch {
+		case deleteAfter > 0 && age >= deleteAfter:
+			if err := er.esClient.DeleteIndex(index.Index); err != nil {
+				er.L().Error(err, "failed to delete index of retention rule", "rule", rule.Name, "index", index.Index)
+				failed = append(failed, index.Index)
+				continue
+			}
+			status.Deleted++
+		case closeAfter > 0 && age >= closeAfter && index.Status == "open":
+			if err := er.esClient.CloseIndex(index.Index); err != nil {
+				er.L().Error(err, "failed to close index of retention rule", "rule", rule.Name, "index", index.Index)
+				failed = append(failed, index.Index)
+				continue
+			}
+			status.Closed++
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		status.Message = fmt.Sprintf("Failed to curate indices %s", strings.Join(failed, ", "))
+	}
+	return status
+}
+
+func (er *ElasticsearchRequest) updateRetentionStatus(statuses []api.RetentionRuleStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.Retention, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.Retention, statuses) {
+			return nil
+		}
+
+		cluster.Status.Retention = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update retention status")
+}
+
+// retentionAge converts the time unit to a duration or zero if the unit is empty
+func retentionAge(unit api.TimeUnit) (time.Duration, error) {
+	if unit == "" {
+		return 0, nil
+	}
+	match := reRetentionAge.FindStringSubmatch(string(unit))
+	if match == nil {
+		return 0, kverrors.New("unsupported retention age",
+			"age", unit)
+	}
+	number, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, kverrors.Wrap(err, "unable to parse retention age", "age", unit)
+	}
+	switch match[2] {
+	case "w":
+		return time.Duration(number) * 7 * 24 * time.Hour, nil
+	case "d":
+		return time.Duration(number) * 24 * time.Hour, nil
+	case "h", "H":
+		return time.Duration(number) * time.Hour, nil
+	case "m":
+		return time.Duration(number) * time.Minute, nil
+	default:
+		return time.Duration(number) * time.Second, nil
+	}
+}
+
+func getRetentionRuleStatus(name string, statuses []api.RetentionRuleStatus) *api.RetentionRuleStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package k8shandler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Retention", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		rule    api.RetentionRuleSpec
+		now     = time.Now()
+	)
+
+	createdDaysAgo := func(days int) string {
+		return fmt.Sprintf("%d", now.Add(-time.Duration(days)*24*time.Hour).UnixNano()/int64(time.Millisecond))
+	}
+
+	BeforeEach(func() {
+		rule = api.RetentionRuleSpec{
+			Name:         "app",
+			IndexPattern: "app-*",
+			CloseAfter:   "7d",
+			DeleteAfter:  "30d",
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch",
+					Namespace: "openshift-logging",
+				},
+			},
+		}
+	})
+
+	Describe("#executeRetentionRule", func() {
+		BeforeEach(func() {
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_cat/indices/app-*?h=index,status,creation.date&format=json": {
+						{StatusCode: http.StatusOK, Body: fmt.Sprintf(`[
+							{"index": "app-000001", "status": "close", "creation.date": "%s"},
+							{"index": "app-000002", "status": "open", "creation.date": "%s"},
+							{"index": "app-000003", "status": "close", "creation.date": "%s"},
+							{"index": "app-000004", "status": "open", "creation.date": "%s"},
+							{"index": "app-000005", "status": "open", "creation.date": "%s"}
+						]`, createdDaysAgo(40), createdDaysAgo(10), createdDaysAgo(9), createdDaysAgo(1), createdDaysAgo(50))},
+					},
+					"app-000001": {
+						{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					},
+					"app-000002/_close": {
+						{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+		})
+
+		It("should delete and close the indices older than the configured ages", func() {
+			status := request.executeRetentionRule(rule, sets.NewString("app-000005"), now)
+
+			Expect(status.Message).To(BeEmpty())
+			Expect(status.Deleted).To(Equal(int32(1)))
+			Expect(status.Closed).To(Equal(int32(1)))
+
+			_, found := chatter.GetRequest("app-000003/_close")
+			Expect(found).To(BeFalse())
+			_, found = chatter.GetRequest("app-000004/_close")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should never delete a write index", func() {
+			status := request.executeRetentionRule(rule, sets.NewString("app-000005"), now)
+
+			Expect(status.Message).To(BeEmpty())
+			_, found := chatter.GetRequest("app-000005")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should report an unsupported age", func() {
+			rule.DeleteAfter = "1M"
+			status := request.executeRetentionRule(rule, sets.NewString(), now)
+
+			Expect(status.Message).To(Equal(`Unsupported deleteAfter "1M", expected a number of weeks, days, hours, minutes or seconds`))
+			_, found := chatter.GetRequest("app-000001")
+			Expect(found).To(BeFalse())
+		})
+	})
+})
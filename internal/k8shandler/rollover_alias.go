@@ -0,0 +1,261 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+var reByteSize = regexp.MustCompile(`^([0-9]+)(b|kb|mb|gb|tb|pb)$`)
+
+const (
+	defaultRolloverCheckInterval = 15 * time.Minute
+	rolloverTemplatePrefix       = "ocp-rollover"
+)
+
+// ReconcileRolloverAliases bootstraps the spec'd write aliases and rolls over their
+// write index when due, unless a lifecycle policy takes care of it
+func (er *ElasticsearchRequest) ReconcileRolloverAliases() error {
+	cluster := er.cluster
+
+	if len(cluster.Spec.RolloverAliases) == 0 && len(cluster.Status.RolloverAliases) == 0 {
+		return nil
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	lifecycleAvailable := false
+	for _, spec := range cluster.Spec.RolloverAliases {
+		if spec.LifecyclePolicy == "" {
+			continue
+		}
+		var err error
+		if lifecycleAvailable, err = er.esClient.IsLifecycleManagementAvailable(); err != nil {
+			return err
+		}
+		break
+	}
+
+	now := time.Now()
+	var statuses []api.RolloverAliasStatus
+	for _, spec := range cluster.Spec.RolloverAliases {
+		interval := defaultRolloverCheckInterval
+		if spec.Interval != nil && spec.Interval.Duration > 0 {
+			interval = spec.Interval.Duration
+		}
+
+		mode := api.RolloverModeOperator
+		if spec.LifecyclePolicy != "" && lifecycleAvailable {
+			mode = api.RolloverModeLifecycle
+		}
+
+		previous := getRolloverAliasStatus(spec.Name, cluster.Status.RolloverAliases)
+		if previous != nil && previous.Mode == mode && now.Sub(previous.LastChecked.Time) < interval {
+			statuses = append(statuses, *previous)
+			continue
+		}
+
+		statuses = append(statuses, er.reconcileRolloverAlias(spec, mode, previous, now))
+	}
+
+	for _, previous := range cluster.Status.RolloverAliases {
+		if getRolloverAliasStatus(previous.Name, statuses) == nil {
+			if err := er.esClient.DeleteIndexTemplate(formatRolloverTemplateName(previous.Name)); err != nil {
+				er.L().Error(err, "failed to delete index template of removed rollover alias", "alias", previous.Name)
+			}
+		}
+	}
+
+	return er.updateRolloverAliasStatus(statuses)
+}
+
+// reconcileRolloverAlias ensures the index template and first index of the alias exist and
+// calls the rollover API when the operator manages the rollover
+func (er *ElasticsearchRequest) reconcileRolloverAlias(spec api.RolloverAliasSpec, mode api.RolloverMode, previous *api.RolloverAliasStatus, now time.Time) api.RolloverAliasStatus {
+	status := api.RolloverAliasStatus{
+		Name:        spec.Name,
+		Mode:        mode,
+		LastChecked: metav1.NewTime(now),
+	}
+	if previous != nil {
+		status.WriteIndex = previous.WriteIndex
+		status.LastRollover = previous.LastRollover
+	}
+
+	if err := er.reconcileRolloverAliasTemplate(spec, mode); err != nil {
+		er.L().Error(err, "failed to reconcile index template of rollover alias", "alias", spec.Name)
+		status.Message = elasticsearchErrorReason(err)
+		return status
+	}
+
+	writeIndex, err := er.esClient.GetWriteIndex(spec.Name)
+	if err != nil {
+		status.Message = elasticsearchErrorReason(err)
+		return status
+	}
+
+	if writeIndex == "" {
+		writeIndex = fmt.Sprintf("%s-000001", spec.Name)
+		index := &estypes.IndexDefinition{
+			Settings: map[string]interface{}{
+				indexSettingNumberOfShards:   strconv.Itoa(calculatePrimaryCount(er.cluster)),
+				indexSettingNumberOfReplicas: strconv.Itoa(calculateReplicaCount(er.cluster)),
+			},
+			Aliases: map[string]estypes.IndexAlias{
+				spec.Name: {IsWriteIndex: utils.GetBool(true)},
+			},
+		}
+		if mode == api.RolloverModeLifecycle {
+			index.Settings["index.lifecycle.name"] = spec.LifecyclePolicy
+			index.Settings["index.lifecycle.rollover_alias"] = spec.Name
+		}
+		if err := er.esClient.CreateIndexDefinition(writeIndex, index); err != nil {
+			er.L().Error(err, "failed to bootstrap the first index of rollover alias", "alias", spec.Name)
+			status.Message = elasticsearchErrorReason(err)
+			return status
+		}
+		status.WriteIndex = writeIndex
+		return status
+	}
+	status.WriteIndex = writeIndex
+
+	if mode == api.RolloverModeLifecycle {
+		return status
+	}
+
+	conditions := estypes.RolloverConditions{
+		MaxAge:  string(spec.Conditions.MaxAge),
+		MaxSize: spec.Conditions.MaxSize,
+		MaxDocs: spec.Conditions.MaxDocs,
+	}
+	if spec.Conditions.MaxShardSize != "" {
+		maxSize, err := er.rolloverMaxSizeForShardSize(writeIndex, spec.Conditions.MaxShardSize)
+		if err != nil {
+			er.L().Error(err, "failed to compute the maximum size of the write index", "alias", spec.Name, "index", writeIndex)
+			status.Message = elasticsearchErrorReason(err)
+			return status
+		}
+		conditions.MaxSize = maxSize
+	}
+	if conditions == (estypes.RolloverConditions{}) {
+		status.Message = "No rollover conditions defined"
+		return status
+	}
+
+	response, err := er.esClient.RolloverAlias(spec.Name, conditions)
+	if err != nil {
+		er.L().Error(err, "failed to rollover alias", "alias", spec.Name)
+		status.Message = elasticsearchErrorReason(err)
+		return status
+	}
+	if response.RolledOver {
+		rolledOver := metav1.NewTime(now)
+		status.WriteIndex = response.NewIndex
+		status.LastRollover = &rolledOver
+	}
+	return status
+}
+
+// rolloverMaxSizeForShardSize returns the size of the write index at which its primary shards reach
+// the target size. Indices created before a change of the primary shard count keep their own count
+func (er *ElasticsearchRequest) rolloverMaxSizeForShardSize(writeIndex, shardSize string) (string, error) {
+	match := reByteSize.FindStringSubmatch(shardSize)
+	if match == nil {
+		return "", kverrors.New("invalid shard size", "size", shardSize)
+	}
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to parse shard size", "size", shardSize)
+	}
+
+	primaryShards := calculatePrimaryCount(er.cluster)
+	indices, err := er.esClient.GetAllIndices(writeIndex)
+	if err != nil {
+		return "", err
+	}
+	for _, index := range indices {
+		if index.Index != writeIndex {
+			continue
+		}
+		if primaryShards, err = strconv.Atoi(index.Primaries); err != nil {
+			return "", kverrors.Wrap(err, "failed to parse primary shard count",
+				"index", writeIndex,
+				"primaries", index.Primaries)
+		}
+	}
+
+	return fmt.Sprintf("%d%s", size*int64(primaryShards), match[2]), nil
+}
+
+// reconcileRolloverAliasTemplate creates the index template applied to the indices of the
+// alias and points it to the lifecycle policy when delegating the rollover
+func (er *ElasticsearchRequest) reconcileRolloverAliasTemplate(spec api.RolloverAliasSpec, mode api.RolloverMode) error {
+	name := formatRolloverTemplateName(spec.Name)
+
+	templates, err := er.esClient.ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	if !templates.Has(name) {
+		primaryShards := int32(calculatePrimaryCount(er.cluster))
+		replicas := int32(calculateReplicaCount(er.cluster))
+		template := estypes.NewIndexTemplate(fmt.Sprintf("%s-*", spec.Name), nil, primaryShards, replicas)
+		if err := er.esClient.CreateIndexTemplate(name, template); err != nil {
+			return err
+		}
+	}
+
+	if mode == api.RolloverModeLifecycle {
+		return er.esClient.SetTemplateLifecyclePolicy(name, spec.LifecyclePolicy, spec.Name)
+	}
+	return nil
+}
+
+func (er *ElasticsearchRequest) updateRolloverAliasStatus(statuses []api.RolloverAliasStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.RolloverAliases, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.RolloverAliases, statuses) {
+			return nil
+		}
+
+		cluster.Status.RolloverAliases = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update rollover alias status")
+}
+
+func getRolloverAliasStatus(name string, statuses []api.RolloverAliasStatus) *api.RolloverAliasStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func formatRolloverTemplateName(alias string) string {
+	return fmt.Sprintf("%s-%s", rolloverTemplatePrefix, alias)
+}
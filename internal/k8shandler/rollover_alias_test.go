@@ -0,0 +1,156 @@
+package k8shandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Rollover aliases", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		spec    api.RolloverAliasSpec
+		now     = time.Now()
+	)
+
+	BeforeEach(func() {
+		spec = api.RolloverAliasSpec{
+			Name: "audit",
+			Conditions: api.RolloverConditionsSpec{
+				MaxAge:  "7d",
+				MaxDocs: 1000000,
+			},
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch",
+					Namespace: "openshift-logging",
+				},
+				Spec: api.ElasticsearchSpec{
+					RedundancyPolicy: api.ZeroRedundancy,
+					Nodes: []api.ElasticsearchNode{
+						{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 1},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("#reconcileRolloverAlias", func() {
+		Context("when the alias does not exist", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_template": {
+							{StatusCode: http.StatusOK, Body: `{"ocp-rollover-audit": {}}`},
+						},
+						"_alias/audit": {
+							{StatusCode: http.StatusNotFound, Body: `{"error": "alias [audit] missing"}`},
+						},
+						"audit-000001?include_type_name=false": {
+							{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should bootstrap the first index as the write index", func() {
+				status := request.reconcileRolloverAlias(spec, api.RolloverModeOperator, nil, now)
+
+				Expect(status.Message).To(BeEmpty())
+				Expect(status.WriteIndex).To(Equal("audit-000001"))
+
+				req, found := chatter.GetRequest("audit-000001?include_type_name=false")
+				Expect(found).To(BeTrue())
+				helpers.ExpectJSON(req.Body).ToEqual(`{
+					"settings": {"index.number_of_shards": "1", "index.number_of_replicas": "0"},
+					"aliases": {"audit": {"is_write_index": true}}
+				}`)
+			})
+		})
+
+		Context("when the write index meets a condition", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_template": {
+							{StatusCode: http.StatusOK, Body: `{"ocp-rollover-audit": {}}`},
+						},
+						"_alias/audit": {
+							{StatusCode: http.StatusOK, Body: `{
+								"audit-000001": {"aliases": {"audit": {"is_write_index": false}}},
+								"audit-000002": {"aliases": {"audit": {"is_write_index": true}}}
+							}`},
+						},
+						"audit/_rollover": {
+							{StatusCode: http.StatusOK, Body: `{"old_index": "audit-000002", "new_index": "audit-000003", "rolled_over": true}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should roll over to the new write index", func() {
+				status := request.reconcileRolloverAlias(spec, api.RolloverModeOperator, nil, now)
+
+				Expect(status.Message).To(BeEmpty())
+				Expect(status.WriteIndex).To(Equal("audit-000003"))
+				Expect(status.LastRollover).ToNot(BeNil())
+
+				req, _ := chatter.GetRequest("audit/_rollover")
+				helpers.ExpectJSON(req.Body).ToEqual(`{"conditions": {"max_age": "7d", "max_docs": 1000000}}`)
+			})
+
+			It("should compute the maximum size from the target shard size", func() {
+				spec.Conditions.MaxShardSize = "30gb"
+				chatter.Responses["_cat/indices/audit-000002?format=json"] = helpers.FakeElasticsearchResponses{
+					{StatusCode: http.StatusOK, Body: `[{"index": "audit-000002", "pri": "3"}]`},
+				}
+
+				status := request.reconcileRolloverAlias(spec, api.RolloverModeOperator, nil, now)
+
+				Expect(status.Message).To(BeEmpty())
+				req, _ := chatter.GetRequest("audit/_rollover")
+				helpers.ExpectJSON(req.Body).ToEqual(`{"conditions": {"max_age": "7d", "max_size": "90gb", "max_docs": 1000000}}`)
+			})
+
+			It("should not call the rollover API when delegating to a lifecycle policy", func() {
+				spec.LifecyclePolicy = "audit-policy"
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_template": {
+							{StatusCode: http.StatusOK, Body: `{"ocp-rollover-audit": {}}`},
+						},
+						"_template/ocp-rollover-audit": {
+							{StatusCode: http.StatusOK, Body: `{"ocp-rollover-audit": {"settings": {"index": {"lifecycle": {"name": "audit-policy", "rollover_alias": "audit"}}}}}`},
+						},
+						"_alias/audit": {
+							{StatusCode: http.StatusOK, Body: `{"audit-000002": {"aliases": {"audit": {}}}}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+				status := request.reconcileRolloverAlias(spec, api.RolloverModeLifecycle, nil, now)
+
+				Expect(status.Message).To(BeEmpty())
+				Expect(status.WriteIndex).To(Equal("audit-000002"))
+				_, found := chatter.GetRequest("audit/_rollover")
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,54 @@
+package k8shandler
+
+import (
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	scratchSpaceVolumeName = "elasticsearch-tmp"
+	scratchSpacePath       = "/elasticsearch/tmp"
+)
+
+var defaultScratchSpaceSizeLimit = resource.MustParse("1Gi")
+
+// nodeScratchSpace returns the scratch space of the node overriding the one of the node spec, or
+// nil if the node keeps its temporary files in the container
+func nodeScratchSpace(commonSpec api.ElasticsearchNodeSpec, node api.ElasticsearchNode) *api.ElasticsearchScratchSpaceSpec {
+	if node.ScratchSpace != nil {
+		return node.ScratchSpace
+	}
+	return commonSpec.ScratchSpace
+}
+
+func newScratchSpaceEnvVar() v1.EnvVar {
+	return v1.EnvVar{
+		Name:  "ES_TMPDIR",
+		Value: scratchSpacePath,
+	}
+}
+
+func newScratchSpaceVolumeMount() v1.VolumeMount {
+	return v1.VolumeMount{
+		Name:      scratchSpaceVolumeName,
+		MountPath: scratchSpacePath,
+	}
+}
+
+func newScratchSpaceVolume(spec *api.ElasticsearchScratchSpaceSpec) v1.Volume {
+	sizeLimit := defaultScratchSpaceSizeLimit.DeepCopy()
+	if spec.SizeLimit != nil && !spec.SizeLimit.IsZero() {
+		sizeLimit = spec.SizeLimit.DeepCopy()
+	}
+
+	return v1.Volume{
+		Name: scratchSpaceVolumeName,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{
+				Medium:    spec.Medium,
+				SizeLimit: &sizeLimit,
+			},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package k8shandler
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Scratch space", func() {
+	defer GinkgoRecover()
+
+	newTemplate := func(node api.ElasticsearchNode, commonSpec api.ElasticsearchNodeSpec) v1.PodTemplateSpec {
+		return newPodTemplateSpec("node", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			commonSpec:  commonSpec,
+		})
+	}
+
+	findVolume := func(template v1.PodTemplateSpec) *v1.Volume {
+		for i, volume := range template.Spec.Volumes {
+			if volume.Name == scratchSpaceVolumeName {
+				return &template.Spec.Volumes[i]
+			}
+		}
+		return nil
+	}
+
+	It("should keep the temporary files in the container by default", func() {
+		template := newTemplate(api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{})
+		Expect(findVolume(template)).To(BeNil())
+		Expect(containsEnvVar("ES_TMPDIR", template.Spec.Containers[0].Env)).To(BeFalse())
+	})
+
+	It("should mount a size-limited emptyDir as the temporary directory", func() {
+		template := newTemplate(api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{ScratchSpace: &api.ElasticsearchScratchSpaceSpec{}})
+
+		volume := findVolume(template)
+		Expect(volume).ToNot(BeNil())
+		Expect(volume.EmptyDir.SizeLimit.String()).To(Equal("1Gi"))
+
+		container := template.Spec.Containers[0]
+		Expect(container.VolumeMounts).To(ContainElement(v1.VolumeMount{Name: scratchSpaceVolumeName, MountPath: scratchSpacePath}))
+		Expect(container.Env).To(ContainElement(v1.EnvVar{Name: "ES_TMPDIR", Value: scratchSpacePath}))
+	})
+
+	It("should use the scratch space of the node over the one of the node spec", func() {
+		sizeLimit := resource.MustParse("5Gi")
+		node := api.ElasticsearchNode{
+			ScratchSpace: &api.ElasticsearchScratchSpaceSpec{SizeLimit: &sizeLimit, Medium: v1.StorageMediumMemory},
+		}
+		template := newTemplate(node, api.ElasticsearchNodeSpec{ScratchSpace: &api.ElasticsearchScratchSpaceSpec{}})
+
+		volume := findVolume(template)
+		Expect(volume.EmptyDir.SizeLimit.String()).To(Equal("5Gi"))
+		Expect(volume.EmptyDir.Medium).To(Equal(v1.StorageMediumMemory))
+	})
+
+	It("should not differ from the pod template read from the API server", func() {
+		roleMap := map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}
+		template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, roleMap, nil, podTemplateOptions{
+			clusterName: "elasticsearch",
+			namespace:   "openshift-logging",
+			commonSpec:  api.ElasticsearchNodeSpec{ScratchSpace: &api.ElasticsearchScratchSpaceSpec{}},
+		})
+
+		data, err := json.Marshal(template)
+		Expect(err).ToNot(HaveOccurred())
+		current := v1.PodTemplateSpec{}
+		Expect(json.Unmarshal(data, &current)).To(Succeed())
+
+		Expect(podTemplateSpecChanges(current, template)).To(BeEmpty())
+	})
+})
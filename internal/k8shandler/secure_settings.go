@@ -0,0 +1,405 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	keystoreBuildName          = "keystore-build"
+	keystoreName               = "keystore"
+	keystoreVolumeName         = "elasticsearch-keystore"
+	keystorePath               = "/etc/elasticsearch/keystore"
+	keystoreFile               = "elasticsearch.keystore"
+	secureSettingsVolumePrefix = "elasticsearch-secure-settings-"
+	secureSettingsPath         = "/etc/elasticsearch/secure-settings"
+	secureSettingsHashKey      = "key"
+	// secureSettingsPropagation is the time the kubelets take to update the Secrets mounted by the
+	// pods and the keystore containers take to rebuild the keystores
+	secureSettingsPropagation = 2 * time.Minute
+)
+
+// reloadableSecureSettings are the prefixes of the secure settings the nodes reload without a restart
+var reloadableSecureSettings = []string{"s3.client.", "gcs.client.", "azure.client."}
+
+// keystoreFunctions build the keystore of a node from the keys of the mounted Secrets. The keystore
+// is rewritten in place as the Elasticsearch container mounts the file itself
+const keystoreFunctions = `
+checksum() {
+  for file in "$SECURE_SETTINGS_PATH"/*/* ; do
+    echo "$file"
+    cat "$file"
+  done | sha256sum
+}
+
+build_keystore() {
+  sum=$(checksum)
+  config=$(mktemp -d) || return 1
+  ES_PATH_CONF="$config" "$ES_HOME/bin/elasticsearch-keystore" create || return 1
+  for file in "$SECURE_SETTINGS_PATH"/*/* ; do
+    [ -f "$file" ] || continue
+    ES_PATH_CONF="$config" "$ES_HOME/bin/elasticsearch-keystore" add-file -f "$(basename "$file")" "$file" || return 1
+  done
+  cat "$config/elasticsearch.keystore" > "$KEYSTORE_PATH/elasticsearch.keystore" || return 1
+  echo "$sum" > "$KEYSTORE_PATH/checksum"
+  rm -rf "$config"
+}
+`
+
+// keystoreBuildScript builds the keystore before Elasticsearch starts
+const keystoreBuildScript = keystoreFunctions + `
+build_keystore
+`
+
+// keystoreWatchScript rebuilds the keystore once the kubelet updates the mounted Secrets
+const keystoreWatchScript = keystoreFunctions + `
+last=$(cat "$KEYSTORE_PATH/checksum")
+while true ; do
+  sleep 10
+  current=$(checksum)
+  if [ "$current" != "$last" ] && build_keystore ; then
+    echo "Rebuilt the keystore from the updated secure settings"
+    last=$current
+  fi
+done
+`
+
+// secureSettings are the Secrets added to the keystores of the nodes
+type secureSettings struct {
+	sources []secureSettingsSource
+	// staticHash is the hash of the settings the nodes only load on startup. Changing them changes
+	// the pod template and rolls out the nodes
+	staticHash string
+}
+
+// secureSettingsSource is a Secret mounted into the keystore containers. Its keys are the names of
+// the settings unless items map the keys to the names
+type secureSettingsSource struct {
+	secretName string
+	items      []v1.KeyToPath
+}
+
+func hasSecureSettings(cluster *api.Elasticsearch) bool {
+	return len(cluster.Spec.SecureSettings) > 0 || len(realmSecureSettingsSources(cluster)) > 0
+}
+
+// newSecureSettings returns the secure settings of the cluster or nil if it has none. It fails if
+// the hash of the settings cannot be computed, since rolling out the nodes for a wrong hash would
+// restart them for no change
+func newSecureSettings(cluster *api.Elasticsearch, client client.Client) (*secureSettings, error) {
+	if !hasSecureSettings(cluster) {
+		return nil, nil
+	}
+
+	settings := &secureSettings{}
+	for _, spec := range cluster.Spec.SecureSettings {
+		settings.sources = append(settings.sources, secureSettingsSource{secretName: spec.SecretName})
+	}
+	settings.sources = append(settings.sources, realmSecureSettingsSources(cluster)...)
+
+	secrets, err := getSecureSettingsSecrets(cluster, client)
+	if err != nil {
+		return nil, err
+	}
+	realmSecrets, err := getRealmSecureSettingsSecrets(cluster, client)
+	if err != nil {
+		return nil, err
+	}
+	key, err := getSecureSettingsHashKey(cluster, client)
+	if err != nil {
+		return nil, err
+	}
+	settings.staticHash = secureSettingsHash(key, append(secrets, realmSecrets...), false)
+	return settings, nil
+}
+
+func secureSettingsHashKeySecretName(clusterName string) string {
+	return fmt.Sprintf("%s-secure-settings-hash-key", clusterName)
+}
+
+// getSecureSettingsHashKey returns the key of the hashes of the secure settings of the cluster.
+// The hashes are published in the pod templates and the status of the cluster, which are readable
+// by more users than the Secrets. The key is created by CreateSecureSettingsHashKey
+func getSecureSettingsHashKey(cluster *api.Elasticsearch, c client.Client) ([]byte, error) {
+	name := secureSettingsHashKeySecretName(cluster.Name)
+	secret := &v1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret); err != nil {
+		return nil, kverrors.Wrap(err, "failed to get secure settings hash key secret",
+			"secret", name)
+	}
+	return secret.Data[secureSettingsHashKey], nil
+}
+
+// CreateSecureSettingsHashKey creates the Secret with the key of the hashes of the secure settings
+// of the cluster before the pod templates of the nodes are built. An existing key is kept
+func (er *ElasticsearchRequest) CreateSecureSettingsHashKey() error {
+	cluster := er.cluster
+	if !hasSecureSettings(cluster) {
+		return nil
+	}
+
+	name := secureSettingsHashKeySecretName(cluster.Name)
+	err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, &v1.Secret{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to get secure settings hash key secret",
+			"secret", name)
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return kverrors.Wrap(err, "failed to generate secure settings hash key")
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    cluster.Labels,
+		},
+		Data: map[string][]byte{secureSettingsHashKey: key},
+	}
+	cluster.AddOwnerRefTo(secret)
+
+	err = er.client.Create(context.TODO(), secret)
+	if apierrors.IsAlreadyExists(err) {
+		// another reconciliation created the key first
+		return nil
+	}
+	return kverrors.Wrap(err, "failed to create secure settings hash key secret",
+		"secret", name)
+}
+
+// getSecureSettingsSecrets returns the Secrets of the secure settings of the cluster. Missing
+// Secrets are skipped as they block the cluster
+func getSecureSettingsSecrets(cluster *api.Elasticsearch, c client.Client) ([]v1.Secret, error) {
+	secrets := []v1.Secret{}
+	for _, spec := range cluster.Spec.SecureSettings {
+		secret := v1.Secret{}
+		key := types.NamespacedName{Name: spec.SecretName, Namespace: cluster.Namespace}
+		if err := c.Get(context.TODO(), key, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, kverrors.Wrap(err, "failed to get secure settings secret",
+				"secret", spec.SecretName)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func isReloadableSecureSetting(name string) bool {
+	for _, prefix := range reloadableSecureSettings {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// secureSettingsHash returns the HMAC of either the reloadable or the static settings of the
+// Secrets. Unlike a plain hash it does not allow guessing weak values offline without the key
+func secureSettingsHash(key []byte, secrets []v1.Secret, reloadable bool) string {
+	hash := hmac.New(sha256.New, key)
+	for _, secret := range secrets {
+		keys := []string{}
+		for key := range secret.Data {
+			if isReloadableSecureSetting(key) == reloadable {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(hash, "%s/%s=%d:", secret.Name, key, len(secret.Data[key]))
+			hash.Write(secret.Data[key])
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// newKeystoreBuildContainer returns the init container building the keystore of a node
+func newKeystoreBuildContainer(imageName string, settings *secureSettings) v1.Container {
+	container := newKeystoreContainer(imageName, settings)
+	container.Name = keystoreBuildName
+	container.Command = []string{"/bin/bash", "-c", keystoreBuildScript}
+	container.Env = append(container.Env, v1.EnvVar{
+		Name:  "SECURE_SETTINGS_HASH",
+		Value: settings.staticHash,
+	})
+	return container
+}
+
+// newKeystoreContainer returns the container rebuilding the keystore of a node for the changes of
+// the reloadable settings
+func newKeystoreContainer(imageName string, settings *secureSettings) v1.Container {
+	mounts := []v1.VolumeMount{
+		{
+			Name:      keystoreVolumeName,
+			MountPath: keystorePath,
+		},
+	}
+	for i := range settings.sources {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      fmt.Sprintf("%s%d", secureSettingsVolumePrefix, i),
+			MountPath: fmt.Sprintf("%s/%d", secureSettingsPath, i),
+			ReadOnly:  true,
+		})
+	}
+
+	return v1.Container{
+		Name:            keystoreName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", keystoreWatchScript},
+		Env: []v1.EnvVar{
+			{
+				Name:  "KEYSTORE_PATH",
+				Value: keystorePath,
+			},
+			{
+				Name:  "SECURE_SETTINGS_PATH",
+				Value: secureSettingsPath,
+			},
+			{
+				Name:  "ES_JAVA_OPTS",
+				Value: "-Xms4m -Xmx64m",
+			},
+		},
+		VolumeMounts: mounts,
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+	}
+}
+
+// newKeystoreVolumeMount mounts the keystore into the configuration of Elasticsearch
+func newKeystoreVolumeMount() v1.VolumeMount {
+	return v1.VolumeMount{
+		Name:      keystoreVolumeName,
+		MountPath: elasticsearchConfigPath + "/" + keystoreFile,
+		SubPath:   keystoreFile,
+	}
+}
+
+func newSecureSettingsVolumes(settings *secureSettings) []v1.Volume {
+	volumes := []v1.Volume{
+		{
+			Name: keystoreVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	for i, source := range settings.sources {
+		volumes = append(volumes, v1.Volume{
+			Name: fmt.Sprintf("%s%d", secureSettingsVolumePrefix, i),
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: source.secretName,
+					Items:      source.items,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// ReconcileSecureSettings reloads the reloadable secure settings of the nodes once their keystores
+// are rebuilt from the changed Secrets. Changes of the other settings roll out the nodes instead
+func (er *ElasticsearchRequest) ReconcileSecureSettings() error {
+	return er.reconcileSecureSettings(time.Now())
+}
+
+func (er *ElasticsearchRequest) reconcileSecureSettings(now time.Time) error {
+	cluster := er.cluster
+
+	if len(cluster.Spec.SecureSettings) == 0 {
+		return er.updateSecureSettingsStatus(nil)
+	}
+
+	secrets, err := getSecureSettingsSecrets(cluster, er.client)
+	if err != nil {
+		return err
+	}
+	key, err := getSecureSettingsHashKey(cluster, er.client)
+	if err != nil {
+		return err
+	}
+	hash := secureSettingsHash(key, secrets, true)
+
+	// the nodes load the current settings on startup
+	if cluster.Status.SecureSettings == nil {
+		return er.updateSecureSettingsStatus(&api.SecureSettingsStatus{Hash: hash})
+	}
+
+	status := cluster.Status.SecureSettings.DeepCopy()
+	if status.Hash != hash {
+		er.L().Info("Reloadable secure settings changed, waiting for the keystores of the nodes to be rebuilt")
+		status.Hash = hash
+		status.ChangedSince = &metav1.Time{Time: now}
+	}
+	if err := er.updateSecureSettingsStatus(status); err != nil {
+		return err
+	}
+
+	if status.ChangedSince == nil || now.Before(status.ChangedSince.Add(secureSettingsPropagation)) {
+		return nil
+	}
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	if err := er.esClient.ReloadSecureSettings(); err != nil {
+		return err
+	}
+
+	er.L().Info("Reloaded the secure settings of the nodes")
+	status = status.DeepCopy()
+	status.ChangedSince = nil
+	return er.updateSecureSettingsStatus(status)
+}
+
+func (er *ElasticsearchRequest) updateSecureSettingsStatus(status *api.SecureSettingsStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.SecureSettings, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.SecureSettings, status) {
+			return nil
+		}
+
+		cluster.Status.SecureSettings = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update secure settings status")
+}
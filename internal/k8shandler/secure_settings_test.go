@@ -0,0 +1,181 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Secure settings", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		secret  *v1.Secret
+		now     time.Time
+	)
+
+	updateSecret := func(key, value string) {
+		secret.Data[key] = []byte(value)
+		Expect(request.client.Update(context.TODO(), secret)).To(Succeed())
+	}
+
+	newTemplate := func() v1.PodTemplateSpec {
+		settings, err := newSecureSettings(cluster, request.client)
+		Expect(err).ToNot(HaveOccurred())
+
+		node := cluster.Spec.Nodes[0]
+		return newPodTemplateSpec("elasticsearch-cdm-abc-1", node, map[string]string{}, getNodeRoleMap(node), request.client, podTemplateOptions{
+			clusterName:    cluster.Name,
+			namespace:      cluster.Namespace,
+			commonSpec:     cluster.Spec.Spec,
+			secureSettings: settings,
+		})
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		now = time.Now()
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+					},
+				},
+				SecureSettings: []api.SecureSettingsSpec{{SecretName: "repository-credentials"}},
+			},
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "repository-credentials", Namespace: "openshift-logging"},
+			Data: map[string][]byte{
+				"s3.client.default.access_key": []byte("access"),
+				"s3.client.default.secret_key": []byte("secret"),
+				"bootstrap.password":           []byte("password"),
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-abc-1-7c9f",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, secret, pod),
+			cluster: cluster,
+		}
+
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_nodes/reload_secure_settings": {
+				{StatusCode: http.StatusOK, Body: `{"_nodes": {"total": 1, "successful": 1, "failed": 0}, "nodes": {"abc": {"name": "elasticsearch-cdm-abc-1"}}}`},
+			},
+		})
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+		Expect(request.CreateSecureSettingsHashKey()).To(Succeed())
+	})
+
+	It("should build the keystore of the nodes from the secrets", func() {
+		spec := newTemplate().Spec
+
+		Expect(spec.InitContainers).To(HaveLen(1))
+		Expect(spec.InitContainers[0].Name).To(Equal(keystoreBuildName))
+		Expect(spec.Containers).To(HaveLen(3))
+		Expect(spec.Containers[2].Name).To(Equal(keystoreName))
+		Expect(spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      keystoreVolumeName,
+			MountPath: "/usr/share/java/elasticsearch/config/elasticsearch.keystore",
+			SubPath:   "elasticsearch.keystore",
+		}))
+		Expect(spec.Volumes).To(ContainElement(v1.Volume{
+			Name: "elasticsearch-secure-settings-0",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: "repository-credentials"},
+			},
+		}))
+	})
+
+	It("should roll out the nodes for the changes of static settings only", func() {
+		template := newTemplate()
+
+		updateSecret("s3.client.default.secret_key", "rotated")
+		Expect(podSpecChanges(template.Spec, newTemplate().Spec, true)).To(BeEmpty())
+
+		updateSecret("bootstrap.password", "rotated")
+		Expect(podSpecChanges(template.Spec, newTemplate().Spec, true)).To(ConsistOf("initContainers[keystore-build].env: SECURE_SETTINGS_HASH"))
+	})
+
+	It("should key the hashes of the settings with a secret of the cluster", func() {
+		Expect(request.reconcileSecureSettings(now)).To(Succeed())
+
+		keySecret := &v1.Secret{}
+		name := types.NamespacedName{Name: "elasticsearch-secure-settings-hash-key", Namespace: "openshift-logging"}
+		Expect(request.client.Get(context.TODO(), name, keySecret)).To(Succeed())
+		key := keySecret.Data[secureSettingsHashKey]
+		Expect(key).To(HaveLen(32))
+
+		Expect(cluster.Status.SecureSettings.Hash).To(Equal(secureSettingsHash(key, []v1.Secret{*secret}, true)))
+		Expect(cluster.Status.SecureSettings.Hash).ToNot(Equal(secureSettingsHash(nil, []v1.Secret{*secret}, true)))
+		settings, err := newSecureSettings(cluster, request.client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.staticHash).To(Equal(secureSettingsHash(key, []v1.Secret{*secret}, false)))
+
+		Expect(request.CreateSecureSettingsHashKey()).To(Succeed())
+		Expect(request.client.Get(context.TODO(), name, keySecret)).To(Succeed())
+		Expect(keySecret.Data[secureSettingsHashKey]).To(Equal(key))
+	})
+
+	It("should not build the keystore of the nodes without the key of the hashes", func() {
+		keySecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-secure-settings-hash-key", Namespace: "openshift-logging"}}
+		Expect(request.client.Delete(context.TODO(), keySecret)).To(Succeed())
+
+		_, err := newSecureSettings(cluster, request.client)
+		Expect(err).To(HaveOccurred())
+		_, err = newPodTemplateOptions(cluster, cluster.Spec.Nodes[0], request.client)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reload the changed reloadable settings once the keystores are rebuilt", func() {
+		Expect(request.reconcileSecureSettings(now)).To(Succeed())
+		Expect(cluster.Status.SecureSettings.ChangedSince).To(BeNil())
+		hash := cluster.Status.SecureSettings.Hash
+
+		updateSecret("s3.client.default.secret_key", "rotated")
+		Expect(request.reconcileSecureSettings(now)).To(Succeed())
+		Expect(cluster.Status.SecureSettings.Hash).ToNot(Equal(hash))
+		Expect(cluster.Status.SecureSettings.ChangedSince).ToNot(BeNil())
+
+		Expect(request.reconcileSecureSettings(now.Add(time.Minute))).To(Succeed())
+		_, found := chatter.GetRequest("_nodes/reload_secure_settings")
+		Expect(found).To(BeFalse())
+
+		Expect(request.reconcileSecureSettings(now.Add(3 * time.Minute))).To(Succeed())
+		_, found = chatter.GetRequest("_nodes/reload_secure_settings")
+		Expect(found).To(BeTrue())
+		Expect(cluster.Status.SecureSettings.ChangedSince).To(BeNil())
+	})
+})
@@ -56,23 +56,26 @@ func (er *ElasticsearchRequest) CreateOrUpdateServices() error {
 		return errCtx.Wrap(err, "failed to create service")
 	}
 
-	// legacy metrics service that likely can be rolled into the single service that goes through the proxy
-	annotations["service.beta.openshift.io/serving-cert-secret-name"] = fmt.Sprintf("%s-%s", dpl.Name, "metrics")
-	err = er.createOrUpdateService(
-		fmt.Sprintf("%s-%s", dpl.Name, "metrics"),
-		dpl.Namespace,
-		dpl.Name,
-		"metrics",
-		60001,
-		selectorForES("es-node-client", dpl.Name),
-		annotations,
-		false,
-		map[string]string{
-			"scrape-metrics": "enabled",
-		},
-	)
-	if err != nil {
-		return errCtx.Wrap(err, "failed to create service")
+	if err := er.createOrUpdateMetricsService(); err != nil {
+		return errCtx.Wrap(err, "failed to reconcile metrics service")
+	}
+
+	if err := er.createOrUpdateCoordinatingService(); err != nil {
+		return kverrors.Wrap(err, "failed to reconcile coordinating service",
+			"cluster", er.cluster.Name,
+			"namespace", er.cluster.Namespace)
+	}
+
+	if err := er.createOrUpdateIngestService(); err != nil {
+		return kverrors.Wrap(err, "failed to reconcile ingest service",
+			"cluster", er.cluster.Name,
+			"namespace", er.cluster.Namespace)
+	}
+
+	if err := er.CreateOrUpdateLegacyClientServices(); err != nil {
+		return kverrors.Wrap(err, "failed to reconcile legacy client services",
+			"cluster", er.cluster.Name,
+			"namespace", er.cluster.Namespace)
 	}
 	return nil
 }
@@ -5,7 +5,11 @@ import (
 	"fmt"
 
 	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,39 +19,145 @@ const (
 	prometheusCAFile = "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt"
 )
 
+// monitoringEnabled returns true if the metrics Service and the ServiceMonitor of the cluster
+// are created
+func monitoringEnabled(cluster *api.Elasticsearch) bool {
+	monitoring := cluster.Spec.Monitoring
+	return monitoring == nil || monitoring.Enabled == nil || *monitoring.Enabled
+}
+
+func metricsServiceName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "metrics")
+}
+
+func serviceMonitorName(clusterName string) string {
+	return fmt.Sprintf("monitor-%s-%s", clusterName, "cluster")
+}
+
+// metricsServiceLabels returns the labels of the metrics Service selected by the ServiceMonitor
+func metricsServiceLabels(clusterName string) map[string]string {
+	return map[string]string{
+		"cluster-name":   clusterName,
+		"scrape-metrics": "enabled",
+	}
+}
+
+// createOrUpdateMetricsService ensures the existence of the metrics Service of the proxy of the
+// client nodes. The Service is kept if monitoring is disabled, since the proxy serves its metrics
+// with the certificate issued for it, but it is no longer labeled for scraping
+func (er *ElasticsearchRequest) createOrUpdateMetricsService() error {
+	dpl := er.cluster
+	name := metricsServiceName(dpl.Name)
+
+	labels := map[string]string{}
+	if monitoringEnabled(dpl) {
+		labels["scrape-metrics"] = "enabled"
+	}
+
+	// legacy metrics service that likely can be rolled into the single service that goes through the proxy
+	annotations := map[string]string{
+		"service.beta.openshift.io/serving-cert-secret-name": name,
+	}
+	return er.createOrUpdateService(
+		name,
+		dpl.Namespace,
+		dpl.Name,
+		"metrics",
+		metricsPort,
+		selectorForES("es-node-client", dpl.Name),
+		annotations,
+		false,
+		labels,
+	)
+}
+
+// ReconcileMonitoring reconciles the metrics Service and the ServiceMonitor of the cluster for
+// changes of the monitoring spec
+func (er *ElasticsearchRequest) ReconcileMonitoring() error {
+	if err := er.createOrUpdateMetricsService(); err != nil {
+		return kverrors.Wrap(err, "failed to reconcile metrics service")
+	}
+	return er.CreateOrUpdateServiceMonitors()
+}
+
 // CreateOrUpdateServiceMonitors ensures the existence of ServiceMonitors for Elasticsearch cluster
+// if monitoring is enabled and deletes them otherwise
 func (er *ElasticsearchRequest) CreateOrUpdateServiceMonitors() error {
 	dpl := er.cluster
-	serviceMonitorName := fmt.Sprintf("monitor-%s-%s", dpl.Name, "cluster")
+	name := serviceMonitorName(dpl.Name)
+
+	if !monitoringEnabled(dpl) {
+		monitor := serviceMonitor(name, dpl.Namespace, nil)
+		err := er.client.Delete(context.TODO(), monitor)
+		// a cluster without the Prometheus Operator has no ServiceMonitors to delete
+		if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return kverrors.Wrap(err, "failed to delete Elasticsearch ServiceMonitor",
+				"name", name)
+		}
+		return nil
+	}
 
-	labelsWithDefault := appendDefaultLabel(dpl.Name, dpl.Labels)
-	labelsWithDefault["scrape-metrics"] = "enabled"
+	labels := map[string]string{}
+	for key, value := range dpl.Labels {
+		labels[key] = value
+	}
+	if dpl.Spec.Monitoring != nil {
+		for key, value := range dpl.Spec.Monitoring.Labels {
+			labels[key] = value
+		}
+	}
+	for key, value := range metricsServiceLabels(dpl.Name) {
+		labels[key] = value
+	}
 
-	elasticsearchScMonitor := createServiceMonitor(serviceMonitorName, dpl.Name, dpl.Namespace, labelsWithDefault)
+	interval := ""
+	if dpl.Spec.Monitoring != nil {
+		interval = dpl.Spec.Monitoring.Interval
+	}
+
+	elasticsearchScMonitor := createServiceMonitor(name, dpl.Name, dpl.Namespace, labels, interval)
 	dpl.AddOwnerRefTo(elasticsearchScMonitor)
 
 	err := er.client.Create(context.TODO(), elasticsearchScMonitor)
-	if err != nil && !apierrors.IsAlreadyExists(err) {
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
 		return kverrors.Wrap(err, "failed to construct Elasticsearch ServiceMonitor")
 	}
 
-	return nil
+	current := &monitoringv1.ServiceMonitor{}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: dpl.Namespace}, current); err != nil {
+			return err
+		}
+
+		current.Labels = elasticsearchScMonitor.Labels
+		current.Spec = elasticsearchScMonitor.Spec
+		return er.client.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update Elasticsearch ServiceMonitor",
+		"name", name)
 }
 
-func createServiceMonitor(serviceMonitorName, clusterName, namespace string, labels map[string]string) *monitoringv1.ServiceMonitor {
+// createServiceMonitor returns the ServiceMonitor scraping the metrics of the proxy and of
+// Elasticsearch through the metrics Service. Prometheus authenticates to the proxy with the token
+// of its service account and verifies the certificate of the Service issued by the service CA
+func createServiceMonitor(serviceMonitorName, clusterName, namespace string, labels map[string]string, interval string) *monitoringv1.ServiceMonitor {
 	svcMonitor := serviceMonitor(serviceMonitorName, namespace, labels)
 	labelSelector := metav1.LabelSelector{
-		MatchLabels: labels,
+		MatchLabels: metricsServiceLabels(clusterName),
 	}
 	tlsConfig := monitoringv1.TLSConfig{
 		CAFile:     prometheusCAFile,
-		ServerName: fmt.Sprintf("%s-%s.%s.svc", clusterName, "metrics", namespace),
+		ServerName: fmt.Sprintf("%s.%s.svc", metricsServiceName(clusterName), namespace),
 		// ServerName can be e.g. elasticsearch-metrics.openshift-logging.svc
 	}
 	proxy := monitoringv1.Endpoint{
 		Port:            clusterName,
 		Path:            "/metrics",
 		Scheme:          "https",
+		Interval:        interval,
 		BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
 		TLSConfig:       &tlsConfig,
 	}
@@ -55,6 +165,7 @@ func createServiceMonitor(serviceMonitorName, clusterName, namespace string, lab
 		Port:            clusterName,
 		Path:            "/_prometheus/metrics",
 		Scheme:          "https",
+		Interval:        interval,
 		BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
 		TLSConfig:       &tlsConfig,
 	}
@@ -0,0 +1,108 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Monitoring", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		request *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+				Labels:    map[string]string{"team": "logging"},
+			},
+		}
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(monitoringv1.AddToScheme(s)).To(Succeed())
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s),
+			cluster: cluster,
+		}
+	})
+
+	getServiceMonitor := func() (*monitoringv1.ServiceMonitor, error) {
+		monitor := &monitoringv1.ServiceMonitor{}
+		key := types.NamespacedName{Name: "monitor-elasticsearch-cluster", Namespace: "openshift-logging"}
+		return monitor, request.client.Get(context.TODO(), key, monitor)
+	}
+
+	It("should select the metrics service regardless of the labels of the cluster", func() {
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+
+		service := &v1.Service{}
+		key := types.NamespacedName{Name: "elasticsearch-metrics", Namespace: "openshift-logging"}
+		Expect(request.client.Get(context.TODO(), key, service)).To(Succeed())
+
+		monitor, err := getServiceMonitor()
+		Expect(err).To(BeNil())
+		Expect(monitor.Labels).To(HaveKeyWithValue("team", "logging"))
+		for label, value := range monitor.Spec.Selector.MatchLabels {
+			Expect(service.Labels).To(HaveKeyWithValue(label, value))
+		}
+		Expect(monitor.Spec.Endpoints).To(HaveLen(2))
+		for _, endpoint := range monitor.Spec.Endpoints {
+			Expect(endpoint.Port).To(Equal(service.Spec.Ports[0].Name))
+			Expect(endpoint.Scheme).To(Equal("https"))
+			Expect(endpoint.BearerTokenFile).To(Equal("/var/run/secrets/kubernetes.io/serviceaccount/token"))
+			Expect(endpoint.TLSConfig.ServerName).To(Equal("elasticsearch-metrics.openshift-logging.svc"))
+		}
+		Expect(cluster.Labels).To(Equal(map[string]string{"team": "logging"}))
+	})
+
+	It("should update the ServiceMonitor for the changed monitoring spec", func() {
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+
+		cluster.Spec.Monitoring = &api.MonitoringSpec{
+			Interval: "30s",
+			Labels:   map[string]string{"prometheus": "logging"},
+		}
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+
+		monitor, err := getServiceMonitor()
+		Expect(err).To(BeNil())
+		Expect(monitor.Labels).To(HaveKeyWithValue("prometheus", "logging"))
+		Expect(monitor.Spec.Endpoints[0].Interval).To(Equal("30s"))
+		Expect(monitor.Spec.Endpoints[1].Interval).To(Equal("30s"))
+	})
+
+	It("should delete the ServiceMonitor and unlabel the metrics service if monitoring is disabled", func() {
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+
+		disabled := false
+		cluster.Spec.Monitoring = &api.MonitoringSpec{Enabled: &disabled}
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+
+		_, err := getServiceMonitor()
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		service := &v1.Service{}
+		key := types.NamespacedName{Name: "elasticsearch-metrics", Namespace: "openshift-logging"}
+		Expect(request.client.Get(context.TODO(), key, service)).To(Succeed())
+		Expect(service.Labels).ToNot(HaveKey("scrape-metrics"))
+
+		Expect(request.ReconcileMonitoring()).To(Succeed())
+	})
+})
@@ -0,0 +1,241 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	shutdownFinalizer      = "logging.openshift.io/elasticsearch-shutdown"
+	defaultShutdownTimeout = 10 * time.Minute
+)
+
+// Shutdown stops the ingestion, flushes the indices and takes the final snapshot of a deleted cluster
+// whose deletion policy asks for it. It returns true once the workloads can be deleted
+func Shutdown(cluster *api.Elasticsearch, requestClient client.Client) (bool, error) {
+	er := &ElasticsearchRequest{
+		client:   requestClient,
+		cluster:  cluster,
+		esClient: elasticsearch.NewClient(cluster.Name, cluster.Namespace, requestClient),
+		ll:       log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace),
+	}
+
+	if !utils.ContainsString(cluster.GetFinalizers(), shutdownFinalizer) {
+		return true, nil
+	}
+
+	if cluster.Spec.ManagementState != api.ManagementStateUnmanaged && isShutdownPolicy(cluster) {
+		done, err := er.shutdown(time.Now())
+		if err != nil || !done {
+			return false, err
+		}
+	}
+
+	return true, er.updateFinalizer(shutdownFinalizer, false)
+}
+
+// ReconcileShutdownFinalizer ensures the deletion of the cluster waits for its shutdown if
+// the deletion policy asks for it
+func (er *ElasticsearchRequest) ReconcileShutdownFinalizer() error {
+	return er.updateFinalizer(shutdownFinalizer, isShutdownPolicy(er.cluster))
+}
+
+// shutdown advances the shutdown through its phases until it has to wait and returns true once it completed
+func (er *ElasticsearchRequest) shutdown(now time.Time) (bool, error) {
+	cluster := er.cluster
+	policy := cluster.Spec.DeletionPolicy
+
+	status := &api.ShutdownStatus{
+		Phase:     api.ShutdownPhaseStoppingIngestion,
+		StartTime: metav1.NewTime(now),
+	}
+	if cluster.Status.Shutdown != nil {
+		status = cluster.Status.Shutdown.DeepCopy()
+	}
+
+	timeout := defaultShutdownTimeout
+	if policy.Timeout != nil && policy.Timeout.Duration > 0 {
+		timeout = policy.Timeout.Duration
+	}
+
+	for status.Phase != api.ShutdownPhaseCompleted {
+		if now.Sub(status.StartTime.Time) > timeout {
+			status.Message = fmt.Sprintf("Shutdown timed out after %s in phase %s", timeout, status.Phase)
+			status.Phase = api.ShutdownPhaseCompleted
+			break
+		}
+		if !er.AnyNodeReady() {
+			status.Message = "No Elasticsearch node is ready to shut down"
+			status.Phase = api.ShutdownPhaseCompleted
+			break
+		}
+		if !er.advanceShutdown(status, now) {
+			break
+		}
+	}
+
+	if err := er.updateShutdownStatus(status); err != nil {
+		return false, err
+	}
+	return status.Phase == api.ShutdownPhaseCompleted, nil
+}
+
+// advanceShutdown executes the current phase of the shutdown and returns true if it moved on to the next phase
+func (er *ElasticsearchRequest) advanceShutdown(status *api.ShutdownStatus, now time.Time) bool {
+	cluster := er.cluster
+	repository := cluster.Spec.DeletionPolicy.FinalSnapshotRepository
+
+	switch status.Phase {
+	case api.ShutdownPhaseStoppingIngestion:
+		if err := er.removeManagedWriteAliases(); err != nil {
+			er.L().Error(err, "failed to stop the ingestion")
+			status.Message = elasticsearchErrorReason(err)
+			return false
+		}
+		status.Phase = api.ShutdownPhaseFlushing
+
+	case api.ShutdownPhaseFlushing:
+		if flushed, err := er.esClient.DoSynchronizedFlush(); err != nil || !flushed {
+			status.Message = "Waiting for all shards to be flushed"
+			return false
+		}
+		if repository == "" {
+			status.Phase = api.ShutdownPhaseCompleted
+			break
+		}
+		status.Snapshot = fmt.Sprintf("%s-final-%s", cluster.Name, now.UTC().Format("20060102150405"))
+		err := er.esClient.CreateSnapshot(repository, status.Snapshot, &estypes.CreateSnapshot{
+			Indices:            "*",
+			IncludeGlobalState: true,
+		})
+		if err != nil {
+			er.L().Error(err, "failed to take the final snapshot", "repository", repository)
+			status.Message = elasticsearchErrorReason(err)
+			return false
+		}
+		status.Phase = api.ShutdownPhaseSnapshotting
+
+	case api.ShutdownPhaseSnapshotting:
+		snapshot, err := er.esClient.GetSnapshot(repository, status.Snapshot)
+		if err != nil {
+			status.Message = elasticsearchErrorReason(err)
+			return false
+		}
+		if snapshot == nil || snapshot.State == "IN_PROGRESS" {
+			status.Message = fmt.Sprintf("Waiting for snapshot %s to complete", status.Snapshot)
+			return false
+		}
+		status.Message = ""
+		if snapshot.State != "SUCCESS" {
+			status.Message = fmt.Sprintf("Final snapshot %s completed with state %s", status.Snapshot, snapshot.State)
+		}
+		status.Phase = api.ShutdownPhaseCompleted
+		return true
+	}
+
+	status.Message = ""
+	return true
+}
+
+// removeManagedWriteAliases removes the write aliases managed by the operator from their write index
+// to make the writers fail instead of writing to a cluster being deleted
+func (er *ElasticsearchRequest) removeManagedWriteAliases() error {
+	cluster := er.cluster
+
+	var aliases []string
+	if cluster.Spec.IndexManagement != nil {
+		for _, mapping := range cluster.Spec.IndexManagement.Mappings {
+			aliases = append(aliases, formatWriteAlias(mapping))
+		}
+	}
+	for _, spec := range cluster.Spec.RolloverAliases {
+		aliases = append(aliases, spec.Name)
+	}
+
+	actions := estypes.AliasActions{}
+	for _, alias := range aliases {
+		index, err := er.esClient.GetWriteIndex(alias)
+		if err != nil {
+			return err
+		}
+		if index == "" {
+			continue
+		}
+		actions.Actions = append(actions.Actions, estypes.AliasAction{
+			Remove: &estypes.AliasRef{Index: index, Alias: alias},
+		})
+	}
+	if len(actions.Actions) == 0 {
+		return nil
+	}
+
+	er.L().Info("Removing write aliases to stop the ingestion", "aliases", strings.Join(aliases, ","))
+	return er.esClient.UpdateAlias(actions)
+}
+
+// updateFinalizer adds the finalizer to the cluster or removes it
+func (er *ElasticsearchRequest) updateFinalizer(finalizer string, present bool) error {
+	cluster := er.cluster
+
+	if utils.ContainsString(cluster.GetFinalizers(), finalizer) == present {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if utils.ContainsString(cluster.GetFinalizers(), finalizer) == present {
+			return nil
+		}
+
+		if present {
+			cluster.SetFinalizers(append(cluster.GetFinalizers(), finalizer))
+		} else {
+			cluster.SetFinalizers(utils.RemoveString(cluster.GetFinalizers(), finalizer))
+		}
+		return er.client.Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update elasticsearch finalizers")
+}
+
+func (er *ElasticsearchRequest) updateShutdownStatus(status *api.ShutdownStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.Shutdown, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.Shutdown, status) {
+			return nil
+		}
+
+		cluster.Status.Shutdown = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update shutdown status")
+}
+
+func isShutdownPolicy(cluster *api.Elasticsearch) bool {
+	return cluster.Spec.DeletionPolicy != nil && cluster.Spec.DeletionPolicy.Type == api.DeletionPolicyShutdown
+}
@@ -0,0 +1,130 @@
+package k8shandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Shutdown", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		now     = time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "elasticsearch",
+				Namespace:  "openshift-logging",
+				Finalizers: []string{shutdownFinalizer},
+			},
+			Spec: api.ElasticsearchSpec{
+				DeletionPolicy: &api.DeletionPolicySpec{
+					Type:                    api.DeletionPolicyShutdown,
+					FinalSnapshotRepository: "backups",
+				},
+				RolloverAliases: []api.RolloverAliasSpec{{Name: "audit"}},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+	})
+
+	It("should stop the ingestion, flush and take a final snapshot", func() {
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_alias/audit": {
+					{StatusCode: http.StatusOK, Body: `{"audit-000002": {"aliases": {"audit": {"is_write_index": true}}}}`},
+				},
+				"_aliases": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+				"_flush/synced": {
+					{StatusCode: http.StatusOK, Body: `{"_shards": {"total": 2, "successful": 2, "failed": 0}}`},
+				},
+				"_snapshot/backups/elasticsearch-final-20210102030405": {
+					{StatusCode: http.StatusOK, Body: `{"accepted": true}`},
+					{StatusCode: http.StatusOK, Body: `{"snapshots": [{"snapshot": "elasticsearch-final-20210102030405", "state": "IN_PROGRESS"}]}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		done, err := request.shutdown(now)
+		Expect(err).To(BeNil())
+		Expect(done).To(BeFalse())
+		Expect(cluster.Status.Shutdown.Phase).To(Equal(api.ShutdownPhaseSnapshotting))
+		Expect(cluster.Status.Shutdown.Message).To(Equal("Waiting for snapshot elasticsearch-final-20210102030405 to complete"))
+
+		req, _ := chatter.GetRequest("_aliases")
+		helpers.ExpectJSON(req.Body).ToEqual(`{"actions": [{"remove": {"index": "audit-000002", "alias": "audit"}}]}`)
+	})
+
+	It("should complete once the final snapshot finished", func() {
+		cluster.Status.Shutdown = &api.ShutdownStatus{
+			Phase:     api.ShutdownPhaseSnapshotting,
+			StartTime: metav1.NewTime(now),
+			Snapshot:  "elasticsearch-final-20210102030405",
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_snapshot/backups/elasticsearch-final-20210102030405": {
+					{StatusCode: http.StatusOK, Body: `{"snapshots": [{"snapshot": "elasticsearch-final-20210102030405", "state": "SUCCESS"}]}`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		done, err := request.shutdown(now.Add(time.Minute))
+		Expect(err).To(BeNil())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Shutdown.Phase).To(Equal(api.ShutdownPhaseCompleted))
+		Expect(cluster.Status.Shutdown.Message).To(BeEmpty())
+	})
+
+	It("should give up once the timeout elapsed", func() {
+		cluster.Status.Shutdown = &api.ShutdownStatus{
+			Phase:     api.ShutdownPhaseFlushing,
+			StartTime: metav1.NewTime(now),
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, helpers.NewFakeElasticsearchChatter(nil))
+
+		done, err := request.shutdown(now.Add(time.Hour))
+		Expect(err).To(BeNil())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Shutdown.Message).To(Equal("Shutdown timed out after 10m0s in phase Flushing"))
+	})
+})
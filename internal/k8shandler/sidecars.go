@@ -0,0 +1,33 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// managedContainerNames are the names of the containers of the nodes managed by the operator
+var managedContainerNames = []string{"elasticsearch", "proxy", keystoreName}
+
+// sidecarsViolation returns the reason the sidecars of a node are invalid or an empty string. The
+// names of the sidecars must differ from each other and from the containers of the operator
+func sidecarsViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		names := map[string]bool{}
+		for _, name := range managedContainerNames {
+			names[name] = true
+		}
+		for _, service := range cluster.Spec.LegacyClientServices {
+			names[legacyClientContainerName(service)] = true
+		}
+
+		for _, sidecar := range node.Sidecars {
+			if names[sidecar.Name] {
+				return fmt.Sprintf("The sidecar %q of the nodes with the roles %v must be named differently from the other containers of the pods",
+					sidecar.Name, node.Roles)
+			}
+			names[sidecar.Name] = true
+		}
+	}
+	return ""
+}
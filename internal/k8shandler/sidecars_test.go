@@ -0,0 +1,60 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Sidecars of nodes", func() {
+	defer GinkgoRecover()
+
+	var node api.ElasticsearchNode
+
+	newTemplate := func() v1.PodTemplateSpec {
+		return newPodTemplateSpec("test-node-name", node, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{clusterName: "test-cluster-name", namespace: "test-namespace-name"})
+	}
+
+	BeforeEach(func() {
+		node = api.ElasticsearchNode{
+			Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData},
+			NodeCount: 3,
+			Sidecars: []v1.Container{
+				{Name: "exporter", Image: "quay.io/prometheuscommunity/elasticsearch-exporter:v1.1.0", Command: []string{"/bin/elasticsearch_exporter"}},
+			},
+		}
+	})
+
+	It("should run the sidecars after the containers of the operator", func() {
+		containers := newTemplate().Spec.Containers
+		Expect(containers).To(HaveLen(3))
+		Expect(containers[0].Name).To(Equal("elasticsearch"))
+		Expect(containers[2].Name).To(Equal("exporter"))
+	})
+
+	It("should roll out changed, added and removed sidecars", func() {
+		current := newTemplate()
+
+		node.Sidecars[0].Image = "quay.io/prometheuscommunity/elasticsearch-exporter:v1.2.0"
+		node.Sidecars[0].Command = []string{"/bin/elasticsearch_exporter", "--es.all"}
+		changes := podTemplateSpecChanges(current, newTemplate())
+		Expect(changes).To(ContainElement(ContainSubstring("containers[exporter].image")))
+		Expect(changes).To(ContainElement("containers[exporter].command"))
+
+		node.Sidecars = append(node.Sidecars, v1.Container{Name: "shipper", Image: "fluent/fluent-bit:1.6"})
+		Expect(podTemplateSpecChanges(current, newTemplate())).To(ContainElement("containers: 3 -> 4"))
+
+		node.Sidecars = nil
+		Expect(podTemplateSpecChanges(current, newTemplate())).To(ContainElement("containers[exporter]: removed"))
+	})
+
+	It("should reject sidecars named like the containers of the operator", func() {
+		cluster := &api.Elasticsearch{Spec: api.ElasticsearchSpec{Nodes: []api.ElasticsearchNode{node}}}
+		Expect(sidecarsViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.Nodes[0].Sidecars = append(cluster.Spec.Nodes[0].Sidecars, v1.Container{Name: "proxy"})
+		Expect(sidecarsViolation(cluster)).To(ContainSubstring(`"proxy"`))
+	})
+})
@@ -0,0 +1,191 @@
+package k8shandler
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	defaultSnapshotRepositoryCheckInterval = time.Hour
+	snapshotStateSuccess                   = "SUCCESS"
+)
+
+// CheckSnapshotRepositories verifies the snapshot repositories that are due for a health check
+// and records the results in the cluster status and metrics
+func (er *ElasticsearchRequest) CheckSnapshotRepositories() error {
+	cluster := er.cluster
+	spec := cluster.Spec.SnapshotRepositoryHealthCheck
+
+	if spec == nil {
+		return er.updateSnapshotRepositoryStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	interval := defaultSnapshotRepositoryCheckInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+
+	now := time.Now()
+	repositories := spec.Repositories
+	if len(repositories) == 0 {
+		var err error
+		if repositories, err = er.esClient.ListSnapshotRepositories(); err != nil {
+			// the health check is optional and must not stop the rest of the reconciliation
+			er.L().Error(err, "failed to list snapshot repositories for their health check")
+			return er.updateSnapshotRepositoryStatus(er.failedSnapshotRepositoryChecks(elasticsearchErrorReason(err), interval, now))
+		}
+	}
+
+	var statuses []api.SnapshotRepositoryStatus
+	for _, repository := range repositories {
+		previous := getSnapshotRepositoryStatus(repository, cluster.Status.SnapshotRepositories)
+		if previous != nil && now.Sub(previous.LastChecked.Time) < interval {
+			er.setSnapshotRepositoryMetrics(previous)
+			statuses = append(statuses, *previous)
+			continue
+		}
+
+		status := er.checkSnapshotRepository(repository, previous, now)
+		er.setSnapshotRepositoryMetrics(&status)
+		if !status.Healthy {
+			metrics.IncSnapshotRepositoryCheckFailures(cluster.Name, cluster.Namespace, repository)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return er.updateSnapshotRepositoryStatus(statuses)
+}
+
+// failedSnapshotRepositoryChecks records the failure to list the repositories for the known
+// repositories that are due for a health check
+func (er *ElasticsearchRequest) failedSnapshotRepositoryChecks(message string, interval time.Duration, now time.Time) []api.SnapshotRepositoryStatus {
+	cluster := er.cluster
+
+	var statuses []api.SnapshotRepositoryStatus
+	for _, previous := range cluster.Status.SnapshotRepositories {
+		status := previous
+		if now.Sub(previous.LastChecked.Time) >= interval {
+			status.Healthy = false
+			status.LastChecked = metav1.NewTime(now)
+			status.Message = message
+			metrics.IncSnapshotRepositoryCheckFailures(cluster.Name, cluster.Namespace, status.Name)
+		}
+		er.setSnapshotRepositoryMetrics(&status)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkSnapshotRepository verifies the repository on all nodes, lists its snapshots and reads the
+// metadata of its last successful snapshot
+func (er *ElasticsearchRequest) checkSnapshotRepository(repository string, previous *api.SnapshotRepositoryStatus, now time.Time) api.SnapshotRepositoryStatus {
+	status := api.SnapshotRepositoryStatus{
+		Name:        repository,
+		LastChecked: metav1.NewTime(now),
+	}
+	if previous != nil {
+		status.LastSuccessfulCheck = previous.LastSuccessfulCheck
+		status.LatestSnapshot = previous.LatestSnapshot
+		status.Snapshots = previous.Snapshots
+	}
+
+	err := er.esClient.VerifySnapshotRepository(repository)
+	if err == nil {
+		var snapshots []estypes.Snapshot
+		if snapshots, err = er.esClient.ListSnapshots(repository); err == nil {
+			status.Snapshots = int32(len(snapshots))
+			if name := lastSuccessfulSnapshot(snapshots); name != "" {
+				// reading the metadata of a single snapshot is the read test of the repository
+				var snapshot *estypes.Snapshot
+				if snapshot, err = er.esClient.GetSnapshot(repository, name); err == nil && snapshot != nil && snapshot.EndTimeInMillis > 0 {
+					end := metav1.NewTime(time.Unix(0, snapshot.EndTimeInMillis*int64(time.Millisecond)))
+					if status.LatestSnapshot == nil || end.After(status.LatestSnapshot.Time) {
+						status.LatestSnapshot = &end
+					}
+				}
+			}
+		}
+	}
+
+	if err != nil {
+		er.L().Error(err, "snapshot repository health check failed", "repository", repository)
+		status.Healthy = false
+		status.Message = elasticsearchErrorReason(err)
+		return status
+	}
+
+	checked := status.LastChecked
+	status.Healthy = true
+	status.LastSuccessfulCheck = &checked
+	return status
+}
+
+func (er *ElasticsearchRequest) setSnapshotRepositoryMetrics(status *api.SnapshotRepositoryStatus) {
+	var latest *time.Time
+	if status.LatestSnapshot != nil {
+		latest = &status.LatestSnapshot.Time
+	}
+	metrics.SetSnapshotRepositoryHealth(er.cluster.Name, er.cluster.Namespace, status.Name, status.Healthy, status.LastChecked.Time, latest)
+}
+
+func (er *ElasticsearchRequest) updateSnapshotRepositoryStatus(statuses []api.SnapshotRepositoryStatus) error {
+	cluster := er.cluster
+
+	for _, previous := range cluster.Status.SnapshotRepositories {
+		if getSnapshotRepositoryStatus(previous.Name, statuses) == nil {
+			metrics.DeleteSnapshotRepositoryHealth(cluster.Name, cluster.Namespace, previous.Name)
+		}
+	}
+
+	if reflect.DeepEqual(cluster.Status.SnapshotRepositories, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.SnapshotRepositories, statuses) {
+			return nil
+		}
+
+		cluster.Status.SnapshotRepositories = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update snapshot repository status")
+}
+
+func getSnapshotRepositoryStatus(name string, statuses []api.SnapshotRepositoryStatus) *api.SnapshotRepositoryStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// lastSuccessfulSnapshot returns the name of the last successful snapshot of the listing. Listings
+// without details are sorted by name, which is the order of creation of timestamped names only.
+// Reading the times of all snapshots would read the metadata of every snapshot of the repository
+func lastSuccessfulSnapshot(snapshots []estypes.Snapshot) string {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].State == snapshotStateSuccess {
+			return snapshots[i].Snapshot
+		}
+	}
+	return ""
+}
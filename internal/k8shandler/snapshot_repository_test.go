@@ -0,0 +1,156 @@
+package k8shandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Snapshot repository health checks", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		now     = time.Now()
+	)
+
+	BeforeEach(func() {
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch",
+					Namespace: "openshift-logging",
+				},
+			},
+		}
+	})
+
+	Describe("#checkSnapshotRepository", func() {
+		Context("when the repository can be verified and read", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_snapshot/backups/_verify": {
+							{StatusCode: http.StatusOK, Body: `{"nodes": {}}`},
+						},
+						"_snapshot/backups/_all?verbose=false": {
+							{StatusCode: http.StatusOK, Body: `{"snapshots": [
+								{"snapshot": "a", "state": "SUCCESS"},
+								{"snapshot": "b", "state": "SUCCESS"},
+								{"snapshot": "c", "state": "FAILED"}
+							]}`},
+						},
+						"_snapshot/backups/b": {
+							{StatusCode: http.StatusOK, Body: `{"snapshots": [
+								{"snapshot": "b", "state": "SUCCESS", "end_time_in_millis": 3000}
+							]}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should record the repository as healthy with its latest successful snapshot", func() {
+				status := request.checkSnapshotRepository("backups", nil, now)
+
+				Expect(status.Healthy).To(BeTrue())
+				Expect(status.Snapshots).To(Equal(int32(3)))
+				Expect(status.LastSuccessfulCheck).ToNot(BeNil())
+				Expect(status.LatestSnapshot.Time.Equal(time.Unix(3, 0))).To(BeTrue())
+				Expect(status.Message).To(BeEmpty())
+				_, found := chatter.GetRequest("_snapshot/backups/a")
+				Expect(found).To(BeFalse(), "to only read the last successful snapshot")
+			})
+		})
+
+		Context("when the repository fails verification", func() {
+			var previous *api.SnapshotRepositoryStatus
+
+			BeforeEach(func() {
+				lastSuccess := metav1.NewTime(now.Add(-2 * time.Hour))
+				previous = &api.SnapshotRepositoryStatus{
+					Name:                "backups",
+					Healthy:             true,
+					LastChecked:         lastSuccess,
+					LastSuccessfulCheck: &lastSuccess,
+					Snapshots:           2,
+				}
+				chatter = helpers.NewFakeElasticsearchChatter(
+					map[string]helpers.FakeElasticsearchResponses{
+						"_snapshot/backups/_verify": {
+							{StatusCode: http.StatusInternalServerError, Body: `{"error": {"reason": "[backups] store location is not accessible"}}`},
+						},
+					},
+				)
+				request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			})
+
+			It("should record the failure and keep the previous freshness information", func() {
+				status := request.checkSnapshotRepository("backups", previous, now)
+
+				Expect(status.Healthy).To(BeFalse())
+				Expect(status.Message).To(Equal("[backups] store location is not accessible"))
+				Expect(status.LastSuccessfulCheck).To(Equal(previous.LastSuccessfulCheck))
+				Expect(status.Snapshots).To(Equal(int32(2)))
+				_, found := chatter.GetRequest("_snapshot/backups/_all?verbose=false")
+				Expect(found).To(BeFalse(), "to not read from a repository that failed verification")
+			})
+		})
+	})
+
+	Describe("#CheckSnapshotRepositories", func() {
+		It("should record a failure to list the repositories without failing the reconciliation", func() {
+			s := runtime.NewScheme()
+			Expect(scheme.AddToScheme(s)).To(Succeed())
+			Expect(api.AddToScheme(s)).To(Succeed())
+
+			lastCheck := metav1.NewTime(now.Add(-2 * time.Hour))
+			cluster := request.cluster
+			cluster.Spec.SnapshotRepositoryHealthCheck = &api.SnapshotRepositoryHealthCheckSpec{}
+			cluster.Status.SnapshotRepositories = []api.SnapshotRepositoryStatus{
+				{Name: "backups", Healthy: true, LastChecked: lastCheck, LastSuccessfulCheck: &lastCheck},
+			}
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elasticsearch-cdm-abc-1-7c9f",
+					Namespace: "openshift-logging",
+					Labels: map[string]string{
+						"component":    "elasticsearch",
+						"cluster-name": "elasticsearch",
+						"es-node-data": "true",
+					},
+				},
+				Status: v1.PodStatus{Phase: v1.PodRunning},
+			}
+			request.client = fake.NewFakeClientWithScheme(s, cluster, pod)
+			chatter = helpers.NewFakeElasticsearchChatter(
+				map[string]helpers.FakeElasticsearchResponses{
+					"_snapshot/_all": {
+						{StatusCode: http.StatusInternalServerError, Body: `{"error": {"reason": "boom"}}`},
+					},
+				},
+			)
+			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+			Expect(request.CheckSnapshotRepositories()).To(Succeed())
+			Expect(cluster.Status.SnapshotRepositories).To(HaveLen(1))
+			status := cluster.Status.SnapshotRepositories[0]
+			Expect(status.Healthy).To(BeFalse())
+			Expect(status.Message).To(Equal("failed to list snapshot repositories"))
+			Expect(status.LastChecked.Time.After(lastCheck.Time)).To(BeTrue())
+			Expect(status.LastSuccessfulCheck.Time.Equal(lastCheck.Time)).To(BeTrue())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package k8shandler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
@@ -32,6 +33,8 @@ type statefulSetNode struct {
 
 	replicas int32
 
+	reclaimPolicy api.StorageReclaimPolicy
+
 	client client.Client
 
 	esClient elasticsearch.Client
@@ -49,8 +52,14 @@ func (n *statefulSetNode) L() logr.Logger {
 	return n.l
 }
 
-func (n *statefulSetNode) populateReference(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client) {
+func (n *statefulSetNode) populateReference(nodeName string, node api.ElasticsearchNode, cluster *api.Elasticsearch, roleMap map[api.ElasticsearchNodeRole]bool, replicas int32, client client.Client, esClient elasticsearch.Client) error {
+	options, err := newPodTemplateOptions(cluster, node, client)
+	if err != nil {
+		return err
+	}
+
 	labels := newLabels(cluster.Name, nodeName, roleMap)
+	workloadLabels, annotations := podTemplateMetadata(cluster.Spec.Spec, node, labels)
 
 	statefulSet := apps.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
@@ -58,22 +67,31 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 			APIVersion: apps.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      nodeName,
-			Namespace: cluster.Namespace,
-			Labels:    labels,
+			Name:        nodeName,
+			Namespace:   cluster.Namespace,
+			Labels:      workloadLabels,
+			Annotations: annotations,
 		},
 	}
 
 	n.replicas = replicas
+	n.reclaimPolicy = node.Storage.ReclaimPolicy
+
+	// each pod of data nodes gets its own claim instead of the claim of the node
+	podNode := node
+	claims := newStorageClaimTemplates(cluster.Name, node)
+	if len(claims) > 0 {
+		podNode.Storage = api.ElasticsearchStorageSpec{}
+	}
 
 	partition := int32(0)
-	logConfig := getLogConfig(cluster.GetAnnotations())
 	statefulSet.Spec = apps.StatefulSetSpec{
 		Replicas: &replicas,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: newLabelSelector(cluster.Name, nodeName, roleMap),
 		},
-		Template: newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, node, cluster.Spec.Spec, labels, roleMap, client, logConfig),
+		Template:             newPodTemplateSpec(nodeName, podNode, labels, roleMap, client, options),
+		VolumeClaimTemplates: claims,
 		UpdateStrategy: apps.StatefulSetUpdateStrategy{
 			Type: apps.RollingUpdateStatefulSetStrategyType,
 			RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
@@ -82,6 +100,15 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 		},
 	}
 	statefulSet.Spec.Template.Spec.Containers[0].ReadinessProbe = nil
+	applyHTTPTLS(cluster, &statefulSet.Spec.Template.Spec)
+	applyFIPSMode(cluster, &statefulSet.Spec.Template.Spec)
+	applyThreadPools(cluster, node, &statefulSet.Spec.Template.Spec)
+	applyAuditLogVolume(cluster, &statefulSet.Spec.Template.Spec)
+	applyLegacyClientProxies(cluster, &statefulSet.Spec.Template)
+	applyPodSecurity(cluster, &statefulSet.Spec.Template)
+	if len(claims) > 0 {
+		removeVolume(&statefulSet.Spec.Template.Spec, storageVolumeName)
+	}
 
 	cluster.AddOwnerRefTo(&statefulSet)
 
@@ -90,6 +117,7 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 
 	n.client = client
 	n.esClient = esClient
+	return nil
 }
 
 func (n *statefulSetNode) updateReference(desired NodeTypeInterface) {
@@ -113,7 +141,8 @@ func (n *statefulSetNode) state() api.ElasticsearchNodeStatus {
 	var rolloutForCertReload v1.ConditionStatus
 
 	// see if we need to update the deployment object
-	if n.isChanged() {
+	changes := n.pendingChanges()
+	if len(changes) > 0 {
 		rolloutForUpdate = v1.ConditionTrue
 	}
 
@@ -136,6 +165,7 @@ func (n *statefulSetNode) state() api.ElasticsearchNodeStatus {
 		UpgradeStatus: api.ElasticsearchNodeUpgradeStatus{
 			ScheduledForUpgrade:      rolloutForUpdate,
 			ScheduledForCertRedeploy: rolloutForCertReload,
+			ScheduledChanges:         changes,
 		},
 	}
 }
@@ -154,8 +184,23 @@ func (n *statefulSetNode) waitForNodeRejoinCluster() (bool, error) {
 
 		return n.replicas <= clusterSize, nil
 	})
+	if err == nil {
+		return true, nil
+	}
 
-	return err == nil, err
+	// pods bound to a local volume can only start on the host of the volume
+	pod, host, hostErr := unavailableLocalVolumeHost(n.client, n.self.Namespace, n.self.Spec.Selector.MatchLabels)
+	if hostErr != nil {
+		n.L().Error(hostErr, "Unable to check the hosts of the local volumes of the node")
+	}
+	if host != "" {
+		n.L().Info("Waiting for the host of the local volume of pod to become schedulable", "pod", pod, "host", host)
+		return false, kverrors.Wrap(ErrWaitingForLocalVolumeHost, "pod is pinned to an unavailable host",
+			"pod", pod,
+			"host", host)
+	}
+
+	return false, err
 }
 
 func (n *statefulSetNode) waitForNodeLeaveCluster() (bool, error) {
@@ -280,7 +325,19 @@ func (n *statefulSetNode) isMissing() bool {
 }
 
 func (n *statefulSetNode) delete() error {
-	return n.client.Delete(context.TODO(), &n.self)
+	if err := n.client.Delete(context.TODO(), &n.self); err != nil {
+		return err
+	}
+	return n.reclaimStorage(0)
+}
+
+// reclaimStorage deletes the claims of the pods from the ordinal on if the reclaim policy of the
+// node asks for it. Claims still in use are deleted by Kubernetes once their pod is gone
+func (n *statefulSetNode) reclaimStorage(fromOrdinal int32) error {
+	if n.reclaimPolicy != api.StorageReclaimPolicyDelete || len(n.self.Spec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	return deleteStatefulSetClaims(n.self.Spec.VolumeClaimTemplates[0].Name, n.self.Name, n.self.Namespace, n.clusterName, fromOrdinal, n.client)
 }
 
 func (n *statefulSetNode) create() error {
@@ -290,8 +347,11 @@ func (n *statefulSetNode) create() error {
 			if !apierrors.IsAlreadyExists(err) {
 				return kverrors.Wrap(err, "could not create node resource")
 			} else {
+				if err := n.expandStorage(); err != nil {
+					return err
+				}
 				n.scale()
-				return nil
+				return n.reclaimStorage(n.replicas)
 			}
 		}
 
@@ -299,12 +359,89 @@ func (n *statefulSetNode) create() error {
 		n.configmapHash = getConfigmapDataHash(n.clusterName, n.self.Namespace, n.client)
 		n.secretHash = getSecretDataHash(n.clusterName, n.self.Namespace, n.client)
 	} else {
+		if err := n.expandStorage(); err != nil {
+			return err
+		}
 		n.scale()
+		return n.reclaimStorage(n.replicas)
 	}
 
 	return nil
 }
 
+// expandStorage grows the claims of the pods when the storage size of the node increased and the
+// storage class allows expanding volumes. The claim templates of a StatefulSet are immutable, so the
+// StatefulSet is recreated orphaning its pods which the new StatefulSet adopts
+func (n *statefulSetNode) expandStorage() error {
+	if len(n.self.Spec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	desired := n.self.Spec.VolumeClaimTemplates[0]
+	size := desired.Spec.Resources.Requests[v1.ResourceStorage]
+
+	current := &apps.StatefulSet{}
+	if err := n.client.Get(context.TODO(), types.NamespacedName{Name: n.self.Name, Namespace: n.self.Namespace}, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to get node resource",
+			"node", n.self.Name)
+	}
+	// wait for the orphaning deletion of a previous expansion to finish
+	if current.GetDeletionTimestamp() != nil || len(current.Spec.VolumeClaimTemplates) == 0 {
+		return nil
+	}
+	currentSize := current.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage]
+	if size.Cmp(currentSize) <= 0 {
+		return nil
+	}
+
+	allowed, err := allowsVolumeExpansion(desired.Spec.StorageClassName, n.client)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		n.L().Info("Storage class does not allow volume expansion, keeping the size of the claims", "size", currentSize.String(), "desired", size.String())
+		return nil
+	}
+
+	for ordinal := int32(0); current.Spec.Replicas != nil && ordinal < *current.Spec.Replicas; ordinal++ {
+		claimName := fmt.Sprintf("%s-%s-%d", desired.Name, current.Name, ordinal)
+		if err := expandPersistentVolumeClaim(claimName, current.Namespace, size, n.client); err != nil {
+			return kverrors.Wrap(err, "failed to expand claim of node",
+				"node", n.self.Name,
+				"claim", claimName)
+		}
+	}
+
+	if err := n.client.Delete(context.TODO(), current, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete node resource to update its claim templates",
+			"node", n.self.Name)
+	}
+
+	recreated := current.DeepCopy()
+	recreated.ObjectMeta = metav1.ObjectMeta{
+		Name:            current.Name,
+		Namespace:       current.Namespace,
+		Labels:          current.Labels,
+		Annotations:     current.Annotations,
+		OwnerReferences: current.OwnerReferences,
+	}
+	recreated.Spec.VolumeClaimTemplates = n.self.Spec.VolumeClaimTemplates
+	recreated.Status = apps.StatefulSetStatus{}
+	if err := n.client.Create(context.TODO(), recreated); err != nil {
+		// the deletion has not finished yet, the node resource is created once it is gone
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to recreate node resource with expanded claim templates",
+			"node", n.self.Name)
+	}
+
+	n.L().Info("Recreated node resource with expanded claim templates", "size", size.String())
+	return nil
+}
+
 func (n *statefulSetNode) executeUpdate() error {
 	// see if we need to update the deployment object and verify we have latest to update
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -320,6 +457,7 @@ func (n *statefulSetNode) executeUpdate() error {
 		if ArePodTemplateSpecDifferent(currentStatefulSet.Spec.Template, n.self.Spec.Template) {
 
 			currentStatefulSet.Spec.Template = n.self.Spec.Template
+			mergeMetadata(&currentStatefulSet.ObjectMeta, n.self.ObjectMeta)
 
 			if updateErr := n.client.Update(context.TODO(), &currentStatefulSet); updateErr != nil {
 				n.L().Error(err, "Failed to update node resource")
@@ -362,17 +500,22 @@ func (n *statefulSetNode) scale() {
 }
 
 func (n *statefulSetNode) isChanged() bool {
+	return len(n.pendingChanges()) > 0
+}
+
+// pendingChanges returns the fields of the pod template which differ from the statefulset
+func (n *statefulSetNode) pendingChanges() []string {
 	desiredTemplate := n.self.Spec.Template
 	currentStatefulSet := apps.StatefulSet{}
 
 	err := n.client.Get(context.TODO(), types.NamespacedName{Name: n.self.Name, Namespace: n.self.Namespace}, &currentStatefulSet)
 	// error check that it exists, etc
 	if err != nil {
-		// if it doesn't exist, return true
-		return false
+		// if it doesn't exist, there is nothing to change
+		return nil
 	}
 
-	return ArePodTemplateSpecDifferent(currentStatefulSet.Spec.Template, desiredTemplate)
+	return podTemplateSpecChanges(currentStatefulSet.Spec.Template, desiredTemplate)
 }
 
 func (n *statefulSetNode) progressNodeChanges() error {
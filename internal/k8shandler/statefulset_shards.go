@@ -0,0 +1,55 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// statefulSetShard is one of the StatefulSets running the pods of a node
+type statefulSetShard struct {
+	name     string
+	replicas int32
+}
+
+// statefulSetShards splits the pods of the node into StatefulSets of at most the maximum replicas
+// of the node. The first StatefulSet keeps the name of the node and the StatefulSets are filled in
+// order, so changing the node count only scales or removes the last StatefulSets
+func statefulSetShards(nodeName string, node api.ElasticsearchNode) []statefulSetShard {
+	max := node.MaxStatefulSetReplicas
+	if max <= 0 || node.NodeCount <= max {
+		return []statefulSetShard{{name: nodeName, replicas: node.NodeCount}}
+	}
+
+	shards := []statefulSetShard{}
+	for remaining := node.NodeCount; remaining > 0; remaining -= max {
+		replicas := remaining
+		if replicas > max {
+			replicas = max
+		}
+		shards = append(shards, statefulSetShard{
+			name:     statefulSetShardName(nodeName, len(shards)),
+			replicas: replicas,
+		})
+	}
+	return shards
+}
+
+// statefulSetShardName returns the name of the StatefulSet at the index of the StatefulSets of a node
+func statefulSetShardName(nodeName string, index int) string {
+	if index == 0 {
+		return nodeName
+	}
+	return fmt.Sprintf("%s-s%d", nodeName, index+1)
+}
+
+// statefulSetPodName returns the name of the pod at the index of all pods of a node running as
+// StatefulSets
+func statefulSetPodName(nodeName string, node api.ElasticsearchNode, index int32) string {
+	max := node.MaxStatefulSetReplicas
+	if max <= 0 {
+		return fmt.Sprintf("%s-%d", nodeName, index)
+	}
+	// the pods of a statefulset are numbered from zero
+	return fmt.Sprintf("%s-%d", statefulSetShardName(nodeName, int(index/max)), index%max)
+}
@@ -0,0 +1,118 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("StatefulSet shards", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		node    api.ElasticsearchNode
+		uuid    = "abc"
+	)
+
+	BeforeEach(func() {
+		node = api.ElasticsearchNode{
+			Roles:                  []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+			NodeCount:              45,
+			GenUUID:                &uuid,
+			Workload:               api.ElasticsearchNodeWorkloadStatefulSet,
+			MaxStatefulSetReplicas: 20,
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClient(),
+			cluster: &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+				Spec:       api.ElasticsearchSpec{Nodes: []api.ElasticsearchNode{node}},
+			},
+		}
+	})
+
+	replicas := func(nodes []NodeTypeInterface, err error) map[string]int32 {
+		Expect(err).ToNot(HaveOccurred())
+		counts := map[string]int32{}
+		for _, node := range nodes {
+			statefulSet := node.(*statefulSetNode).self
+			counts[statefulSet.Name] = *statefulSet.Spec.Replicas
+		}
+		return counts
+	}
+
+	It("should fill the statefulsets of the node in order", func() {
+		Expect(replicas(request.GetNodeTypeInterface(uuid, node))).To(Equal(map[string]int32{
+			"elasticsearch-cd-abc":    20,
+			"elasticsearch-cd-abc-s2": 20,
+			"elasticsearch-cd-abc-s3": 5,
+		}))
+	})
+
+	It("should keep a single statefulset for nodes within the maximum", func() {
+		node.NodeCount = 20
+		Expect(replicas(request.GetNodeTypeInterface(uuid, node))).To(Equal(map[string]int32{
+			"elasticsearch-cd-abc": 20,
+		}))
+
+		node.NodeCount = 45
+		node.MaxStatefulSetReplicas = 0
+		Expect(replicas(request.GetNodeTypeInterface(uuid, node))).To(Equal(map[string]int32{
+			"elasticsearch-cd-abc": 45,
+		}))
+	})
+
+	It("should name the pods after their statefulset", func() {
+		Expect(statefulSetPodName("elasticsearch-cd-abc", node, 19)).To(Equal("elasticsearch-cd-abc-19"))
+		Expect(statefulSetPodName("elasticsearch-cd-abc", node, 20)).To(Equal("elasticsearch-cd-abc-s2-0"))
+		Expect(statefulSetPodName("elasticsearch-cd-abc", node, 45)).To(Equal("elasticsearch-cd-abc-s3-5"))
+	})
+
+	Describe("changes of the maximum", func() {
+		statefulSet := func(name string, replicas int32) *apps.StatefulSet {
+			return &apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"},
+				Spec:       apps.StatefulSetSpec{Replicas: &replicas},
+			}
+		}
+
+		It("should reject a maximum below the pods of an existing statefulset", func() {
+			request.client = fake.NewFakeClient(statefulSet("elasticsearch-cd-abc", 60))
+			request.cluster.Spec.Nodes[0].NodeCount = 60
+
+			Expect(request.workloadViolation()).To(Equal(
+				"The maxStatefulSetReplicas of nodes elasticsearch-cd-abc cannot be lower than the 60 pods of their existing StatefulSet"))
+
+			node.MaxStatefulSetReplicas = 60
+			Expect(request.maxStatefulSetReplicasViolation("elasticsearch-cd-abc", node)).To(BeEmpty())
+			node.MaxStatefulSetReplicas = 0
+			Expect(request.maxStatefulSetReplicasViolation("elasticsearch-cd-abc", node)).To(BeEmpty())
+		})
+
+		It("should reject changing the maximum once the pods are split", func() {
+			request.client = fake.NewFakeClient(
+				statefulSet("elasticsearch-cd-abc", 20),
+				statefulSet("elasticsearch-cd-abc-s2", 20),
+				statefulSet("elasticsearch-cd-abc-s3", 5),
+			)
+
+			Expect(request.maxStatefulSetReplicasViolation("elasticsearch-cd-abc", node)).To(BeEmpty())
+
+			node.MaxStatefulSetReplicas = 30
+			Expect(request.maxStatefulSetReplicasViolation("elasticsearch-cd-abc", node)).To(Equal(
+				"The maxStatefulSetReplicas of nodes elasticsearch-cd-abc cannot change from 20 to 30 once their pods are split into several StatefulSets"))
+
+			node.MaxStatefulSetReplicas = 0
+			Expect(request.maxStatefulSetReplicasViolation("elasticsearch-cd-abc", node)).ToNot(BeEmpty())
+		})
+
+		It("should accept a maximum for nodes without statefulset", func() {
+			Expect(request.workloadViolation()).To(BeEmpty())
+		})
+	})
+})
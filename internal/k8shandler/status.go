@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
@@ -63,11 +64,32 @@ func (er *ElasticsearchRequest) UpdateClusterStatus() error {
 		}
 	}
 
+	// if the cluster isn't ready keep the last known version
+	if er.AnyNodeReady() {
+		if version, err := esClient.GetLowestClusterVersion(); err == nil {
+			clusterStatus.Version = version
+		}
+	}
+
+	clusterStatus.ElectedMaster = ""
+	if er.AnyNodeReady() {
+		if pod := er.electedMaster(); pod != nil {
+			clusterStatus.ElectedMaster = pod.Name
+		}
+	}
+
 	clusterStatus.Pods = rolePodStateMap(cluster.Namespace, cluster.Name, er.client)
 	updateStatusConditions(clusterStatus)
 	if err := er.updateNodeConditions(clusterStatus); err != nil {
 		return err
 	}
+	if err := er.updateNodeReplicas(clusterStatus); err != nil {
+		return err
+	}
+	reportUpgradePhases(cluster, clusterStatus, time.Now())
+	clusterStatus.EffectiveConfig = newEffectiveConfig(cluster, clusterStatus.Version)
+	updateSummaryConditions(cluster, clusterStatus)
+	clusterStatus.Phase = clusterPhase(clusterStatus)
 
 	if !reflect.DeepEqual(clusterStatus, cluster.Status) {
 		nretries := -1
@@ -82,6 +104,11 @@ func (er *ElasticsearchRequest) UpdateClusterStatus() error {
 			cluster.Status.Pods = clusterStatus.Pods
 			cluster.Status.ShardAllocationEnabled = clusterStatus.ShardAllocationEnabled
 			cluster.Status.Nodes = clusterStatus.Nodes
+			cluster.Status.Version = clusterStatus.Version
+			cluster.Status.Phase = clusterStatus.Phase
+			cluster.Status.ElectedMaster = clusterStatus.ElectedMaster
+			cluster.Status.EffectiveConfig = clusterStatus.EffectiveConfig
+			cluster.Status.ObservedGeneration = clusterStatus.ObservedGeneration
 
 			if err := er.client.Status().Update(context.TODO(), cluster); err != nil {
 				return err
@@ -117,6 +144,7 @@ func (er *ElasticsearchRequest) setNodeStatus(node NodeTypeInterface, nodeStatus
 
 func (er *ElasticsearchRequest) updateNodeStatus(status api.ElasticsearchStatus) error {
 	cluster := er.cluster
+	updateSummaryConditions(cluster, &status)
 	// if there is nothing to update, don't
 	if reflect.DeepEqual(cluster.Status, status) {
 		return nil
@@ -680,6 +708,32 @@ func updateStatusConditions(status *api.ElasticsearchStatus) {
 	}
 }
 
+// clusterPhase summarizes the state of the cluster from its health and conditions
+func clusterPhase(status *api.ElasticsearchStatus) api.ElasticsearchPhase {
+	for _, condition := range status.Conditions {
+		if strings.HasPrefix(string(condition.Type), "Invalid") && condition.Status == v1.ConditionTrue {
+			return api.ElasticsearchPhaseInvalid
+		}
+	}
+
+	if containsClusterCondition(api.Restarting, v1.ConditionTrue, status) {
+		return api.ElasticsearchPhaseUpgrading
+	}
+	for _, node := range status.Nodes {
+		if node.UpgradeStatus.UnderUpgrade == v1.ConditionTrue {
+			return api.ElasticsearchPhaseUpgrading
+		}
+	}
+
+	switch {
+	case status.Cluster.Status == healthUnknown || status.Cluster.Status == "":
+		return api.ElasticsearchPhasePending
+	case status.Cluster.Status == "red" || containsClusterCondition(api.DegradedState, v1.ConditionTrue, status):
+		return api.ElasticsearchPhaseDegraded
+	}
+	return api.ElasticsearchPhaseRunning
+}
+
 func isPodUnschedulableConditionTrue(conditions []api.ClusterCondition) bool {
 	_, condition := getESNodeCondition(conditions, api.Unschedulable)
 	return condition != nil && condition.Status == v1.ConditionTrue
@@ -763,7 +817,8 @@ func updateConditionWithRetry(dpl *api.Elasticsearch, value v1.ConditionStatus,
 			return err
 		}
 
-		if changed := executeUpdateCondition(&dpl.Status, value); !changed {
+		changed := executeUpdateCondition(&dpl.Status, value)
+		if summarized := updateSummaryConditions(dpl, &dpl.Status); !changed && !summarized {
 			return nil
 		}
 
@@ -835,6 +890,426 @@ func updateInvalidUUIDChangeCondition(cluster *api.Elasticsearch, value v1.Condi
 	)
 }
 
+func updateInvalidMemoryLockCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidMemoryLock,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidWorkloadCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidWorkload,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidVotingOnlyCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidVotingOnly,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidAffinityCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidAffinity,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidPlacementProfileCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidPlacementProfile,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidSidecarsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidSidecars,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidInitContainersCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidInitContainers,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidHostTuningCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidHostTuning,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidJVMHeapCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidJVMHeap,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidJVMOptionsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidJVMOptions,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidAdditionalConfigCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidAdditionalConfig,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidLoggingCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidLogging,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidCertificatesCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidCertificates,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidTLSCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidTLS,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidCredentialsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidCredentials,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidRealmsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidRealms,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidPodSecurityCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidPodSecurity,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateMaxMapCountTooLowCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Bootstrap Check Failed"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.MaxMapCountTooLow,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateFailedUpgradeCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "RetryBudgetExhausted"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.FailedUpgrade,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateConfigValidationFailedCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Configuration"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.ConfigValidationFailed,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
 func updateInvalidReplicationCondition(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
 	var message string
 	var reason string
@@ -853,7 +1328,9 @@ func updateInvalidReplicationCondition(status *api.ElasticsearchStatus, value v1
 	})
 }
 
-func (er *ElasticsearchRequest) UpdateDegradedCondition(value bool, reason, message string) {
+// UpdatePrometheusRulesCondition reports the Prometheus rules of the cluster missing, which
+// degrades the cluster
+func (er *ElasticsearchRequest) UpdatePrometheusRulesCondition(value bool, reason, message string) {
 	cluster := er.cluster
 
 	statusValue := v1.ConditionFalse
@@ -866,8 +1343,8 @@ func (er *ElasticsearchRequest) UpdateDegradedCondition(value bool, reason, mess
 		cluster,
 		statusValue,
 		func(status *api.ElasticsearchStatus, statusValue v1.ConditionStatus) bool {
-			return updateESNodeCondition(&cluster.Status, &api.ClusterCondition{
-				Type:    api.DegradedState,
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.PrometheusRulesMissing,
 				Status:  statusValue,
 				Reason:  reason,
 				Message: message,
@@ -918,3 +1395,95 @@ func updateUpdatingESSettingsCondition(status *api.ElasticsearchStatus, value v1
 		Status: value,
 	})
 }
+
+func updateInvalidLegacyClientsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidLegacyClients,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidAuditLoggingCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidAuditLogging,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidThreadPoolsCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Settings"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.InvalidThreadPools,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+// UpdateReconcileSuspendedCondition flags the cluster with the ReconcileSuspended condition when
+// its reconciliation failed with an error of the user, e.g. an invalid spec or a missing Secret.
+// A nil error clears the condition
+func UpdateReconcileSuspendedCondition(cluster *api.Elasticsearch, reconcileErr error, client client.Client) error {
+	value := v1.ConditionFalse
+	var reason, message string
+	if reconcileErr != nil {
+		value = v1.ConditionTrue
+		reason = "Invalid Settings"
+		message = reconcileErr.Error()
+	} else if _, condition := getESNodeCondition(cluster.Status.Conditions, api.ReconcileSuspended); condition == nil {
+		return nil
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.ReconcileSuspended,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
@@ -445,3 +445,56 @@ func TestUnschedulableNodesConditions(t *testing.T) {
 		t.Errorf("Expected cluster node statuses to be same. Diff is %s", diff)
 	}
 }
+
+func TestClusterPhase(t *testing.T) {
+	tests := []struct {
+		desc   string
+		status loggingv1.ElasticsearchStatus
+		want   loggingv1.ElasticsearchPhase
+	}{
+		{
+			desc:   "no node ready",
+			status: loggingv1.ElasticsearchStatus{Cluster: loggingv1.ClusterHealth{Status: healthUnknown}},
+			want:   loggingv1.ElasticsearchPhasePending,
+		},
+		{
+			desc:   "green cluster",
+			status: loggingv1.ElasticsearchStatus{Cluster: loggingv1.ClusterHealth{Status: "green"}},
+			want:   loggingv1.ElasticsearchPhaseRunning,
+		},
+		{
+			desc:   "red cluster",
+			status: loggingv1.ElasticsearchStatus{Cluster: loggingv1.ClusterHealth{Status: "red"}},
+			want:   loggingv1.ElasticsearchPhaseDegraded,
+		},
+		{
+			desc: "node under upgrade",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "yellow"},
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{UpgradeStatus: loggingv1.ElasticsearchNodeUpgradeStatus{UnderUpgrade: corev1.ConditionTrue}},
+				},
+			},
+			want: loggingv1.ElasticsearchPhaseUpgrading,
+		},
+		{
+			desc: "invalid spec",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Conditions: []loggingv1.ClusterCondition{
+					{Type: loggingv1.InvalidMasters, Status: corev1.ConditionTrue},
+				},
+			},
+			want: loggingv1.ElasticsearchPhaseInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			if got := clusterPhase(&test.status); got != test.want {
+				t.Errorf("Exp. phase %q but got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const storageFinalizer = "logging.openshift.io/elasticsearch-storage"
+
+// ReclaimStorage deletes the claims of the data nodes whose reclaim policy asks for it once the
+// cluster is deleted and releases the deletion of the cluster
+func ReclaimStorage(cluster *api.Elasticsearch, requestClient client.Client) error {
+	er := &ElasticsearchRequest{
+		client:  requestClient,
+		cluster: cluster,
+		ll:      log.WithValues("cluster", cluster.Name, "namespace", cluster.Namespace),
+	}
+
+	if !utils.ContainsString(cluster.GetFinalizers(), storageFinalizer) {
+		return nil
+	}
+
+	if cluster.Spec.ManagementState != api.ManagementStateUnmanaged {
+		for _, node := range cluster.Spec.Nodes {
+			if !reclaimsStorage(cluster, node) || node.GenUUID == nil {
+				continue
+			}
+			nodeName := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+			for _, shard := range statefulSetShards(nodeName, node) {
+				if err := deleteStatefulSetClaims(storageVolumeName, shard.name, cluster.Namespace, cluster.Name, 0, requestClient); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return er.updateFinalizer(storageFinalizer, false)
+}
+
+// ReconcileStorageFinalizer ensures the deletion of the cluster deletes the claims of the data nodes
+// whose reclaim policy asks for it
+func (er *ElasticsearchRequest) ReconcileStorageFinalizer() error {
+	present := false
+	for _, node := range er.cluster.Spec.Nodes {
+		if reclaimsStorage(er.cluster, node) {
+			present = true
+			break
+		}
+	}
+	return er.updateFinalizer(storageFinalizer, present)
+}
+
+// reclaimsStorage returns true if the node has claims per pod that are deleted with the node
+func reclaimsStorage(cluster *api.Elasticsearch, node api.ElasticsearchNode) bool {
+	return node.Storage.ReclaimPolicy == api.StorageReclaimPolicyDelete &&
+		isStatefulSetNode(node) &&
+		len(newStorageClaimTemplates(cluster.Name, node)) > 0
+}
@@ -0,0 +1,109 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Storage reclaim", func() {
+	defer GinkgoRecover()
+
+	var (
+		k8sClient client.Client
+		cluster   *api.Elasticsearch
+	)
+
+	newClaim := func(name string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "openshift-logging",
+				Labels:    map[string]string{"logging-cluster": "elasticsearch"},
+			},
+		}
+	}
+
+	claimNames := func() []string {
+		claims := &v1.PersistentVolumeClaimList{}
+		Expect(k8sClient.List(context.TODO(), claims, client.InNamespace("openshift-logging"))).To(Succeed())
+		names := []string{}
+		for _, claim := range claims.Items {
+			names = append(names, claim.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		uuid := "abc"
+		size := resource.MustParse("1Gi")
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "elasticsearch",
+				Namespace:  "openshift-logging",
+				Finalizers: []string{storageFinalizer},
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData},
+						NodeCount: 2,
+						GenUUID:   &uuid,
+						Workload:  api.ElasticsearchNodeWorkloadStatefulSet,
+						Storage: api.ElasticsearchStorageSpec{
+							Size:          &size,
+							ReclaimPolicy: api.StorageReclaimPolicyDelete,
+						},
+					},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		k8sClient = fake.NewFakeClientWithScheme(s, cluster,
+			newClaim("elasticsearch-storage-elasticsearch-cd-abc-0"),
+			newClaim("elasticsearch-storage-elasticsearch-cd-abc-1"),
+			newClaim("elasticsearch-storage-elasticsearch-cd-abc-2"),
+			newClaim("elasticsearch-storage-elasticsearch-cd-def-0"),
+		)
+	})
+
+	It("should delete the claims of the pods removed by a scale down", func() {
+		Expect(deleteStatefulSetClaims(storageVolumeName, "elasticsearch-cd-abc", "openshift-logging", "elasticsearch", 2, k8sClient)).To(Succeed())
+		Expect(claimNames()).To(ConsistOf(
+			"elasticsearch-storage-elasticsearch-cd-abc-0",
+			"elasticsearch-storage-elasticsearch-cd-abc-1",
+			"elasticsearch-storage-elasticsearch-cd-def-0",
+		))
+	})
+
+	It("should delete the claims of the nodes with the delete policy with the cluster", func() {
+		Expect(ReclaimStorage(cluster, k8sClient)).To(Succeed())
+		Expect(claimNames()).To(ConsistOf("elasticsearch-storage-elasticsearch-cd-def-0"))
+
+		current := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, current)).To(Succeed())
+		Expect(current.GetFinalizers()).To(BeEmpty())
+	})
+
+	It("should retain the claims by default", func() {
+		cluster.Spec.Nodes[0].Storage.ReclaimPolicy = ""
+		Expect(reclaimsStorage(cluster, cluster.Spec.Nodes[0])).To(BeFalse())
+
+		Expect(ReclaimStorage(cluster, k8sClient)).To(Succeed())
+		Expect(claimNames()).To(HaveLen(4))
+	})
+})
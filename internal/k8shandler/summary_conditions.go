@@ -0,0 +1,237 @@
+package k8shandler
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The reasons of the Ready, Progressing and Degraded conditions. They are part of the API
+// documented in docs/conditions.md and must not be renamed
+const (
+	reasonClusterReady       = "ClusterReady"
+	reasonIdle               = "Idle"
+	reasonHealthy            = "Healthy"
+	reasonStarting           = "Starting"
+	reasonUpgrading          = "Upgrading"
+	reasonScaling            = "Scaling"
+	reasonUpdatingSettings   = "UpdatingSettings"
+	reasonRecovering         = "Recovering"
+	reasonMigrating          = "Migrating"
+	reasonShuttingDown       = "ShuttingDown"
+	reasonInvalidSpec        = "InvalidSpec"
+	reasonBlocked            = "Blocked"
+	reasonUpgradeFailed      = "UpgradeFailed"
+	reasonClusterRed         = "ClusterRed"
+	reasonClusterUnavailable = "ClusterUnavailable"
+	reasonNodesUnavailable   = "NodesUnavailable"
+	reasonStorageExhausted   = "StorageExhausted"
+	reasonClockSkew          = "ClockSkew"
+	reasonMonitoring         = "PrometheusRulesMissing"
+)
+
+// failedContainerReasons are the reasons of waiting containers which do not resolve by themselves
+var failedContainerReasons = []string{
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+}
+
+// updateSummaryConditions summarizes the conditions of the status into the Degraded, Progressing
+// and Ready conditions for GitOps health checks and records the observed generation of the spec.
+// Unlike the other conditions they are kept with status False. It returns true if the status
+// changed
+func updateSummaryConditions(cluster *api.Elasticsearch, status *api.ElasticsearchStatus) bool {
+	changed := status.ObservedGeneration != cluster.Generation
+	status.ObservedGeneration = cluster.Generation
+
+	// the conditions may share their array with the status of the cluster
+	status.Conditions = append(api.ClusterConditions{}, status.Conditions...)
+
+	degradedReason, degradedMessage := degradedReason(status)
+	progressingReason, progressingMessage := progressingReason(status)
+	if degradedReason == reasonClusterUnavailable {
+		progressingReason, progressingMessage = "", ""
+	}
+
+	degraded := api.ClusterCondition{Type: api.DegradedState, Status: v1.ConditionFalse, Reason: reasonHealthy}
+	if degradedReason != "" {
+		degraded = api.ClusterCondition{Type: api.DegradedState, Status: v1.ConditionTrue, Reason: degradedReason, Message: degradedMessage}
+	}
+	progressing := api.ClusterCondition{Type: api.ProgressingState, Status: v1.ConditionFalse, Reason: reasonIdle}
+	if progressingReason != "" {
+		progressing = api.ClusterCondition{Type: api.ProgressingState, Status: v1.ConditionTrue, Reason: progressingReason, Message: progressingMessage}
+	}
+
+	ready := api.ClusterCondition{Type: api.ReadyState, Status: v1.ConditionTrue, Reason: reasonClusterReady}
+	switch {
+	case degradedReason != "":
+		ready = api.ClusterCondition{Type: api.ReadyState, Status: v1.ConditionFalse, Reason: degradedReason, Message: degradedMessage}
+	case progressingReason != "":
+		ready = api.ClusterCondition{Type: api.ReadyState, Status: v1.ConditionFalse, Reason: progressingReason, Message: progressingMessage}
+	}
+
+	for _, condition := range []api.ClusterCondition{degraded, progressing, ready} {
+		if setSummaryCondition(status, condition) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// setSummaryCondition sets the condition and keeps its transition time unless its status changed
+func setSummaryCondition(status *api.ElasticsearchStatus, condition api.ClusterCondition) bool {
+	index, current := getESNodeCondition(status.Conditions, condition.Type)
+	if current == nil {
+		condition.LastTransitionTime = metav1.Now()
+		status.Conditions = append(status.Conditions, condition)
+		return true
+	}
+
+	condition.LastTransitionTime = current.LastTransitionTime
+	if condition.Status != current.Status {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	if condition == *current {
+		return false
+	}
+	status.Conditions[index] = condition
+	return true
+}
+
+// degradedReason returns the reason and message of the first failure of the cluster which requires
+// an intervention or an empty reason
+func degradedReason(status *api.ElasticsearchStatus) (string, string) {
+	for _, condition := range status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		if strings.HasPrefix(string(condition.Type), "Invalid") || condition.Type == api.ConfigValidationFailed {
+			return reasonInvalidSpec, condition.Message
+		}
+	}
+
+	for _, check := range []struct {
+		condition api.ClusterConditionType
+		reason    string
+	}{
+		{api.Blocked, reasonBlocked},
+		{api.ReconcileSuspended, reasonInvalidSpec},
+		{api.FailedUpgrade, reasonUpgradeFailed},
+	} {
+		if _, condition := getESNodeCondition(status.Conditions, check.condition); condition != nil && condition.Status == v1.ConditionTrue {
+			return check.reason, condition.Message
+		}
+	}
+
+	switch status.Cluster.Status {
+	case "red":
+		return reasonClusterRed, "The cluster health is red"
+	case healthUnknown, "":
+		// an unavailable cluster stays degraded until it is healthy again
+		if _, ready := getESNodeCondition(status.Conditions, api.ReadyState); ready != nil &&
+			(ready.Status == v1.ConditionTrue || ready.Reason == reasonClusterUnavailable) {
+			return reasonClusterUnavailable, "No node of the cluster is ready"
+		}
+	}
+
+	for _, node := range status.Nodes {
+		name := node.DeploymentName
+		if name == "" {
+			name = node.StatefulSetName
+		}
+		for _, condition := range node.Conditions {
+			if condition.Status != v1.ConditionTrue {
+				continue
+			}
+			switch {
+			case condition.Type == api.Unschedulable:
+				return reasonNodesUnavailable, fmt.Sprintf("Node %s is unschedulable: %s", name, condition.Message)
+			case condition.Type == api.ESContainerWaiting || condition.Type == api.ProxyContainerWaiting:
+				for _, reason := range failedContainerReasons {
+					if condition.Reason == reason {
+						return reasonNodesUnavailable, fmt.Sprintf("Node %s is not starting: %s", name, condition.Reason)
+					}
+				}
+			case condition.Type == api.NodeStorage && condition.Reason == "Disk Watermark High":
+				return reasonStorageExhausted, fmt.Sprintf("Node %s: %s", name, condition.Message)
+			}
+		}
+	}
+
+	for _, check := range []struct {
+		condition api.ClusterConditionType
+		reason    string
+	}{
+		{api.ClockSkewDetected, reasonClockSkew},
+		{api.PrometheusRulesMissing, reasonMonitoring},
+	} {
+		if _, condition := getESNodeCondition(status.Conditions, check.condition); condition != nil && condition.Status == v1.ConditionTrue {
+			return check.reason, condition.Message
+		}
+	}
+	return "", ""
+}
+
+// progressingReason returns the reason and message of the first long-running operation in progress
+// or an empty reason
+func progressingReason(status *api.ElasticsearchStatus) (string, string) {
+	if status.Shutdown != nil && status.Shutdown.Phase != api.ShutdownPhaseCompleted {
+		return reasonShuttingDown, fmt.Sprintf("The cluster is shutting down: %s", status.Shutdown.Phase)
+	}
+
+	if containsClusterCondition(api.Restarting, v1.ConditionTrue, status) ||
+		containsClusterCondition(api.FullRestartScheduled, v1.ConditionTrue, status) {
+		return reasonUpgrading, "The nodes are restarted"
+	}
+	for _, node := range status.Nodes {
+		upgrade := node.UpgradeStatus
+		if upgrade.UnderUpgrade == v1.ConditionTrue ||
+			upgrade.ScheduledForUpgrade == v1.ConditionTrue ||
+			upgrade.ScheduledForRedeploy == v1.ConditionTrue ||
+			upgrade.ScheduledForCertRedeploy == v1.ConditionTrue {
+			return reasonUpgrading, "The nodes are upgraded one after the other"
+		}
+	}
+
+	if containsClusterCondition(api.ScalingUp, v1.ConditionTrue, status) ||
+		containsClusterCondition(api.ScalingDown, v1.ConditionTrue, status) {
+		return reasonScaling, "The nodes of the cluster are scaled"
+	}
+
+	if containsClusterCondition(api.UpdatingSettings, v1.ConditionTrue, status) ||
+		containsClusterCondition(api.UpdatingESSettings, v1.ConditionTrue, status) ||
+		containsClusterCondition(api.SpecChangeQueued, v1.ConditionTrue, status) {
+		return reasonUpdatingSettings, "The settings of the cluster are updated"
+	}
+
+	if len(status.WorkloadMigrations) > 0 {
+		return reasonMigrating, "The nodes are migrated to StatefulSets"
+	}
+	if status.Migration != nil {
+		for _, index := range status.Migration.RemoteReindex {
+			if index.State == api.RemoteReindexStatePending || index.State == api.RemoteReindexStateRunning {
+				return reasonMigrating, "The indices are reindexed from the remote cluster"
+			}
+		}
+	}
+
+	if containsClusterCondition(api.Recovering, v1.ConditionTrue, status) {
+		return reasonRecovering, "The shards of the cluster are recovering"
+	}
+
+	if status.Cluster.Status == healthUnknown || status.Cluster.Status == "" {
+		return reasonStarting, "No node of the cluster is ready yet"
+	}
+	for _, node := range status.Nodes {
+		if node.ReadyReplicas < node.DesiredReplicas {
+			return reasonStarting, "The nodes of the cluster are starting"
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,111 @@
+package k8shandler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+func TestUpdateSummaryConditions(t *testing.T) {
+	type summary struct {
+		ready, progressing, degraded string
+	}
+
+	tests := []struct {
+		desc   string
+		status loggingv1.ElasticsearchStatus
+		want   summary
+	}{
+		{
+			desc:   "green cluster",
+			status: loggingv1.ElasticsearchStatus{Cluster: loggingv1.ClusterHealth{Status: "green"}},
+			want:   summary{reasonClusterReady, reasonIdle, reasonHealthy},
+		},
+		{
+			desc:   "new cluster",
+			status: loggingv1.ElasticsearchStatus{Cluster: loggingv1.ClusterHealth{Status: healthUnknown}},
+			want:   summary{reasonStarting, reasonStarting, reasonHealthy},
+		},
+		{
+			desc: "cluster no longer available",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: healthUnknown},
+				Conditions: []loggingv1.ClusterCondition{
+					{Type: loggingv1.ReadyState, Status: corev1.ConditionTrue, Reason: reasonClusterReady},
+				},
+			},
+			want: summary{reasonClusterUnavailable, reasonIdle, reasonClusterUnavailable},
+		},
+		{
+			desc: "node under upgrade",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "yellow"},
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{UpgradeStatus: loggingv1.ElasticsearchNodeUpgradeStatus{UnderUpgrade: corev1.ConditionTrue}},
+				},
+			},
+			want: summary{reasonUpgrading, reasonUpgrading, reasonHealthy},
+		},
+		{
+			desc: "node crashing during upgrade",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "yellow"},
+				Nodes: []loggingv1.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-abc-1",
+						UpgradeStatus:  loggingv1.ElasticsearchNodeUpgradeStatus{UnderUpgrade: corev1.ConditionTrue},
+						Conditions: []loggingv1.ClusterCondition{
+							{Type: loggingv1.ESContainerWaiting, Status: corev1.ConditionTrue, Reason: "CrashLoopBackOff"},
+						},
+					},
+				},
+			},
+			want: summary{reasonNodesUnavailable, reasonUpgrading, reasonNodesUnavailable},
+		},
+		{
+			desc: "invalid spec",
+			status: loggingv1.ElasticsearchStatus{
+				Cluster: loggingv1.ClusterHealth{Status: "green"},
+				Conditions: []loggingv1.ClusterCondition{
+					{Type: loggingv1.InvalidMasters, Status: corev1.ConditionTrue},
+				},
+			},
+			want: summary{reasonInvalidSpec, reasonIdle, reasonInvalidSpec},
+		},
+	}
+
+	cluster := &loggingv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			if !updateSummaryConditions(cluster, &test.status) {
+				t.Errorf("Exp. the status to change")
+			}
+			if test.status.ObservedGeneration != 3 {
+				t.Errorf("Exp. observed generation 3 but got %d", test.status.ObservedGeneration)
+			}
+
+			got := summary{}
+			for _, condition := range test.status.Conditions {
+				switch condition.Type {
+				case loggingv1.ReadyState:
+					got.ready = condition.Reason
+				case loggingv1.ProgressingState:
+					got.progressing = condition.Reason
+				case loggingv1.DegradedState:
+					got.degraded = condition.Reason
+				}
+			}
+			if got != test.want {
+				t.Errorf("Exp. summary %+v but got %+v", test.want, got)
+			}
+
+			if updateSummaryConditions(cluster, &test.status) {
+				t.Errorf("Exp. the summary to be stable")
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package k8shandler
+
+import (
+	"fmt"
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	nodeProcessorsEnvVar      = "NODE_PROCESSORS"
+	writeThreadPoolSizeEnvVar = "WRITE_THREAD_POOL_SIZE"
+)
+
+// usesThreadPoolSizing returns true if the processors of the nodes are rendered, i.e. the node
+// spec or a node sets the thread pools or a node has a CPU limit
+func usesThreadPoolSizing(cluster *api.Elasticsearch) bool {
+	if cluster.Spec.Spec.ThreadPools != nil {
+		return true
+	}
+	for _, node := range cluster.Spec.Nodes {
+		if node.ThreadPools != nil {
+			return true
+		}
+		if _, ok := nodeCPULimit(cluster, node); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// usesWriteThreadPoolSize returns true if the node spec or a node sizes the write thread pool
+func usesWriteThreadPoolSize(cluster *api.Elasticsearch) bool {
+	if spec := cluster.Spec.Spec.ThreadPools; spec != nil && spec.WriteSize != nil {
+		return true
+	}
+	for _, node := range cluster.Spec.Nodes {
+		if node.ThreadPools != nil && node.ThreadPools.WriteSize != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeThreadPools returns the thread pool sizing of the node overriding the one of the node spec
+func nodeThreadPools(cluster *api.Elasticsearch, node api.ElasticsearchNode) api.ElasticsearchThreadPoolSpec {
+	pools := api.ElasticsearchThreadPoolSpec{}
+	for _, spec := range []*api.ElasticsearchThreadPoolSpec{cluster.Spec.Spec.ThreadPools, node.ThreadPools} {
+		if spec == nil {
+			continue
+		}
+		if spec.Processors != nil {
+			pools.Processors = spec.Processors
+		}
+		if spec.WriteSize != nil {
+			pools.WriteSize = spec.WriteSize
+		}
+	}
+	return pools
+}
+
+// nodeCPULimit returns the CPU limit of the Elasticsearch container of the node
+func nodeCPULimit(cluster *api.Elasticsearch, node api.ElasticsearchNode) (resource.Quantity, bool) {
+	resources := newESResourceRequirements(node.Resources, cluster.Spec.Spec.Resources)
+	limit, ok := resources.Limits[v1.ResourceCPU]
+	if !ok || limit.IsZero() {
+		return resource.Quantity{}, false
+	}
+	return limit, true
+}
+
+// nodeProcessors returns the processors of the node if they are known to the operator, i.e. set
+// explicitly or by the CPU limit rounded up
+func nodeProcessors(cluster *api.Elasticsearch, node api.ElasticsearchNode) (int32, bool) {
+	if processors := nodeThreadPools(cluster, node).Processors; processors != nil {
+		return *processors, true
+	}
+	if limit, ok := nodeCPULimit(cluster, node); ok {
+		return int32((limit.MilliValue() + 999) / 1000), true
+	}
+	return 0, false
+}
+
+// threadPoolsViolation returns the reason the thread pool sizing of the cluster is invalid or an
+// empty string. Elasticsearch refuses to start with a write thread pool exceeding the processors
+// by more than one
+func threadPoolsViolation(cluster *api.Elasticsearch) string {
+	for _, node := range cluster.Spec.Nodes {
+		writeSize := nodeThreadPools(cluster, node).WriteSize
+		if writeSize == nil {
+			continue
+		}
+		if processors, ok := nodeProcessors(cluster, node); ok && *writeSize > processors+1 {
+			return fmt.Sprintf("The write thread pool size %d of the node with roles %v exceeds its %d processors by more than one",
+				*writeSize, node.Roles, processors)
+		}
+	}
+	return ""
+}
+
+// newThreadPoolEnvVars returns the environment variables of the processors and the write thread
+// pool size of the node. Processors not known to the operator are read from the CPU limit of the
+// container, which is the allocatable CPUs of the host without a limit
+func newThreadPoolEnvVars(cluster *api.Elasticsearch, node api.ElasticsearchNode) []v1.EnvVar {
+	if !usesThreadPoolSizing(cluster) {
+		return nil
+	}
+
+	processors := v1.EnvVar{Name: nodeProcessorsEnvVar}
+	if count, ok := nodeProcessors(cluster, node); ok {
+		processors.Value = strconv.Itoa(int(count))
+	} else {
+		processors.ValueFrom = &v1.EnvVarSource{
+			ResourceFieldRef: &v1.ResourceFieldSelector{
+				ContainerName: "elasticsearch",
+				Resource:      "limits.cpu",
+				Divisor:       resource.MustParse("1"),
+			},
+		}
+	}
+	envVars := []v1.EnvVar{processors}
+
+	if usesWriteThreadPoolSize(cluster) {
+		writeSize := v1.EnvVar{
+			Name:  writeThreadPoolSizeEnvVar,
+			Value: fmt.Sprintf("$(%s)", nodeProcessorsEnvVar),
+		}
+		if size := nodeThreadPools(cluster, node).WriteSize; size != nil {
+			writeSize.Value = strconv.Itoa(int(*size))
+		}
+		envVars = append(envVars, writeSize)
+	}
+	return envVars
+}
+
+// applyThreadPools sets the environment variables of the thread pool sizing of the node in the
+// Elasticsearch container, replacing the ones of the user with the same names
+func applyThreadPools(cluster *api.Elasticsearch, node api.ElasticsearchNode, spec *v1.PodSpec) {
+	envVars := newThreadPoolEnvVars(cluster, node)
+	if len(envVars) == 0 {
+		return
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		if container.Name != "elasticsearch" {
+			continue
+		}
+		env := []v1.EnvVar{}
+		for _, envVar := range container.Env {
+			if !containsEnvVar(envVar.Name, envVars) {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = append(env, envVars...)
+	}
+}
@@ -0,0 +1,118 @@
+package k8shandler
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Thread pools", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	int32Ptr := func(value int32) *int32 { return &value }
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 3},
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+						NodeCount: 3,
+						Resources: v1.ResourceRequirements{
+							Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3500m")},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should keep the processors of the host without CPU limits", func() {
+		cluster.Spec.Nodes[1].Resources = v1.ResourceRequirements{}
+		Expect(usesThreadPoolSizing(cluster)).To(BeFalse())
+		Expect(newThreadPoolEnvVars(cluster, cluster.Spec.Nodes[0])).To(BeEmpty())
+	})
+
+	It("should derive the processors from the CPU limit rounded up", func() {
+		envVars := newThreadPoolEnvVars(cluster, cluster.Spec.Nodes[1])
+		helpers.ExpectEnvVars(envVars).ToIncludeName("NODE_PROCESSORS").WithValue("4")
+		Expect(envVars).To(HaveLen(1))
+	})
+
+	It("should read the processors of nodes without CPU limit from the container", func() {
+		envVars := newThreadPoolEnvVars(cluster, cluster.Spec.Nodes[0])
+		Expect(envVars).To(Equal([]v1.EnvVar{
+			{
+				Name: "NODE_PROCESSORS",
+				ValueFrom: &v1.EnvVarSource{
+					ResourceFieldRef: &v1.ResourceFieldSelector{
+						ContainerName: "elasticsearch",
+						Resource:      "limits.cpu",
+						Divisor:       resource.MustParse("1"),
+					},
+				},
+			},
+		}))
+	})
+
+	It("should override the thread pools of the node spec with the ones of the node", func() {
+		cluster.Spec.Spec.ThreadPools = &api.ElasticsearchThreadPoolSpec{Processors: int32Ptr(2), WriteSize: int32Ptr(3)}
+		cluster.Spec.Nodes[1].ThreadPools = &api.ElasticsearchThreadPoolSpec{Processors: int32Ptr(8)}
+
+		envVars := newThreadPoolEnvVars(cluster, cluster.Spec.Nodes[1])
+		helpers.ExpectEnvVars(envVars).ToIncludeName("NODE_PROCESSORS").WithValue("8")
+		helpers.ExpectEnvVars(envVars).ToIncludeName("WRITE_THREAD_POOL_SIZE").WithValue("3")
+
+		cluster.Spec.Spec.ThreadPools = nil
+		cluster.Spec.Nodes[1].ThreadPools.WriteSize = int32Ptr(4)
+		envVars = newThreadPoolEnvVars(cluster, cluster.Spec.Nodes[0])
+		helpers.ExpectEnvVars(envVars).ToIncludeName("WRITE_THREAD_POOL_SIZE").WithValue("$(NODE_PROCESSORS)")
+	})
+
+	It("should reject a write thread pool exceeding the processors by more than one", func() {
+		cluster.Spec.Nodes[1].ThreadPools = &api.ElasticsearchThreadPoolSpec{WriteSize: int32Ptr(5)}
+		Expect(threadPoolsViolation(cluster)).To(BeEmpty())
+
+		cluster.Spec.Nodes[1].ThreadPools.WriteSize = int32Ptr(6)
+		Expect(threadPoolsViolation(cluster)).To(ContainSubstring("exceeds its 4 processors"))
+	})
+
+	It("should replace the environment variables of the user", func() {
+		spec := &v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "elasticsearch", Env: []v1.EnvVar{{Name: "NODE_PROCESSORS", Value: "64"}, {Name: "OTHER", Value: "kept"}}},
+			},
+		}
+		applyThreadPools(cluster, cluster.Spec.Nodes[1], spec)
+		Expect(spec.Containers[0].Env).To(Equal([]v1.EnvVar{
+			{Name: "OTHER", Value: "kept"},
+			{Name: "NODE_PROCESSORS", Value: "4"},
+		}))
+	})
+
+	It("should render the processors and the write thread pool size", func() {
+		buf := &bytes.Buffer{}
+		Expect(renderEsYml(buf, esYmlStruct{
+			EsUnicastHost:        "my.unicast.host",
+			NodeQuorum:           "2",
+			RecoverExpectedNodes: "3",
+			SystemCallFilter:     "false",
+			HTTPTLS:              true,
+			ThreadPools:          true,
+			WriteThreadPool:      true,
+		})).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("\nprocessors: ${NODE_PROCESSORS}\n"))
+		Expect(buf.String()).To(ContainSubstring("\nthread_pool.write.size: ${WRITE_THREAD_POOL_SIZE}\n"))
+	})
+})
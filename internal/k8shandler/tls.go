@@ -0,0 +1,85 @@
+package k8shandler
+
+import (
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// proxyPlainUpstream is the REST API of the node the proxy forwards to without TLS
+	proxyPlainUpstream = "http://localhost:9200"
+)
+
+func tlsSpec(cluster *api.Elasticsearch) *api.TLSSpec {
+	if cluster.Spec.Security == nil {
+		return nil
+	}
+	return cluster.Spec.Security.TLS
+}
+
+func layerTLSEnabled(spec *api.LayerTLSSpec) bool {
+	return spec == nil || spec.Enabled == nil || *spec.Enabled
+}
+
+// transportTLSEnabled returns true if the nodes communicate with each other over TLS
+func transportTLSEnabled(cluster *api.Elasticsearch) bool {
+	spec := tlsSpec(cluster)
+	return spec == nil || layerTLSEnabled(spec.Transport)
+}
+
+// httpTLSEnabled returns true if the REST API of the nodes is served over TLS
+func httpTLSEnabled(cluster *api.Elasticsearch) bool {
+	spec := tlsSpec(cluster)
+	return spec == nil || layerTLSEnabled(spec.HTTP)
+}
+
+// tlsViolation returns the reason the TLS of the cluster is invalid or an empty string. The
+// security plugin refuses to start without TLS on the transport layer and a certificate of a
+// layer without TLS would never be used
+func tlsViolation(cluster *api.Elasticsearch) string {
+	if !transportTLSEnabled(cluster) {
+		return "The TLS of the transport layer cannot be disabled, the security plugin of the nodes requires it"
+	}
+
+	if !httpTLSEnabled(cluster) {
+		if spec := customCertificates(cluster); spec != nil && spec.HTTP != nil {
+			return "The certificate of the HTTP layer cannot be supplied while its TLS is disabled"
+		}
+	}
+	return ""
+}
+
+// applyHTTPTLS serves the REST API of the pods of the template without TLS if it is disabled. The
+// proxy listens and forwards over plain HTTP and the readiness of the node is probed on its port
+// since the readiness script of the image requires TLS
+func applyHTTPTLS(cluster *api.Elasticsearch, spec *v1.PodSpec) {
+	if httpTLSEnabled(cluster) {
+		return
+	}
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		switch container.Name {
+		case "elasticsearch":
+			if container.ReadinessProbe != nil {
+				container.ReadinessProbe.Handler = v1.Handler{
+					TCPSocket: &v1.TCPSocketAction{
+						Port: intstr.FromInt(9200),
+					},
+				}
+			}
+		case "proxy":
+			args := []string{}
+			for _, arg := range container.Args {
+				if strings.HasPrefix(arg, "--tls-") {
+					continue
+				}
+				args = append(args, arg)
+			}
+			container.Args = append(args, "--upstream="+proxyPlainUpstream)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("TLS", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	disabled := func() *api.LayerTLSSpec {
+		enabled := false
+		return &api.LayerTLSSpec{Enabled: &enabled}
+	}
+
+	podSpec := func() *v1.PodSpec {
+		spec := newPodTemplateSpec("node", cluster.Spec.Nodes[0], map[string]string{}, getNodeRoleMap(cluster.Spec.Nodes[0]), nil, podTemplateOptions{
+			clusterName: cluster.Name,
+			namespace:   cluster.Namespace,
+			commonSpec:  cluster.Spec.Spec,
+		}).Spec
+		applyHTTPTLS(cluster, &spec)
+		return &spec
+	}
+
+	It("should serve the REST API over TLS by default", func() {
+		Expect(httpTLSEnabled(cluster)).To(BeTrue())
+		Expect(tlsViolation(cluster)).To(BeEmpty())
+		Expect(newClusterConfigMap(cluster, nil).Data[esConfig]).To(ContainSubstring("\n    http:\n      enabled: true\n"))
+
+		spec := podSpec()
+		Expect(spec.Containers[0].ReadinessProbe.Exec).ToNot(BeNil())
+		Expect(spec.Containers[1].Args).To(ContainElement("--tls-cert=/etc/proxy/elasticsearch/logging-es.crt"))
+		Expect(spec.Containers[1].Args).ToNot(ContainElement("--upstream=" + proxyPlainUpstream))
+	})
+
+	It("should serve the REST API over plain HTTP when disabled", func() {
+		cluster.Spec.Security = &api.SecuritySpec{TLS: &api.TLSSpec{HTTP: disabled()}}
+
+		Expect(tlsViolation(cluster)).To(BeEmpty())
+		Expect(newClusterConfigMap(cluster, nil).Data[esConfig]).To(ContainSubstring("\n    http:\n      enabled: false\n"))
+
+		spec := podSpec()
+		Expect(spec.Containers[0].ReadinessProbe.Exec).To(BeNil())
+		Expect(spec.Containers[0].ReadinessProbe.TCPSocket.Port.IntValue()).To(Equal(9200))
+		Expect(spec.Containers[1].Args).ToNot(ContainElement("--tls-cert=/etc/proxy/elasticsearch/logging-es.crt"))
+		Expect(spec.Containers[1].Args).To(ContainElement("--upstream=" + proxyPlainUpstream))
+		Expect(spec.Containers[1].Args).To(ContainElement("--metrics-tls-cert=/etc/proxy/secrets/tls.crt"))
+	})
+
+	It("should restart all nodes at once for enabling or disabling TLS of the REST API", func() {
+		current := newClusterConfigMap(cluster, nil).Data
+		cluster.Spec.Security = &api.SecuritySpec{TLS: &api.TLSSpec{HTTP: disabled()}}
+
+		restart, settings := configChangeRestart(current, newClusterConfigMap(cluster, nil).Data)
+		Expect(restart).To(Equal(configRestartFullCluster))
+		Expect(settings).To(ConsistOf("opendistro_security.ssl.http.enabled"))
+	})
+
+	It("should reject disabling TLS of the transport layer", func() {
+		cluster.Spec.Security = &api.SecuritySpec{TLS: &api.TLSSpec{Transport: disabled()}}
+
+		Expect(tlsViolation(cluster)).To(ContainSubstring("transport layer cannot be disabled"))
+	})
+
+	It("should reject a certificate of the HTTP layer without TLS", func() {
+		cluster.Spec.Security = &api.SecuritySpec{
+			TLS:   &api.TLSSpec{HTTP: disabled()},
+			Certs: &api.CertsSpec{HTTP: &api.CertSource{SecretRef: api.CertSecretReference{Name: "http-tls"}}},
+		}
+
+		Expect(tlsViolation(cluster)).To(ContainSubstring("HTTP layer cannot be supplied"))
+	})
+})
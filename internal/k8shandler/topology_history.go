@@ -0,0 +1,234 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/objectstore"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultTopologyHistoryRegion = "us-east-1"
+	// topologyHistoryRetryInterval between attempts to record a generation after a failure
+	topologyHistoryRetryInterval      = time.Minute
+	topologyHistoryAccessKeyIDKey     = "access_key_id"
+	topologyHistorySecretAccessKeyKey = "secret_access_key"
+	topologyHistoryKeyTimeFormat      = "20060102T150405Z"
+)
+
+// topologyRecord is the object written for every generation of the spec of the cluster
+type topologyRecord struct {
+	Generation    int64                  `json:"generation"`
+	RecordedAt    metav1.Time            `json:"recordedAt"`
+	Elasticsearch map[string]interface{} `json:"elasticsearch"`
+	Deployments   []topologyWorkload     `json:"deployments,omitempty"`
+	StatefulSets  []topologyWorkload     `json:"statefulSets,omitempty"`
+	ConfigMap     map[string]string      `json:"configMap,omitempty"`
+}
+
+// topologyWorkload is a workload generated for a node of the cluster
+type topologyWorkload struct {
+	Name     string             `json:"name"`
+	Labels   map[string]string  `json:"labels,omitempty"`
+	Replicas *int32             `json:"replicas,omitempty"`
+	Template v1.PodTemplateSpec `json:"template"`
+}
+
+// RecordTopologyHistory writes the spec of the cluster and the workloads generated for it to the
+// bucket of the topology history once per generation and records the result in the cluster status
+func (er *ElasticsearchRequest) RecordTopologyHistory() error {
+	cluster := er.cluster
+	spec := cluster.Spec.TopologyHistory
+
+	if spec == nil {
+		return er.updateTopologyHistoryStatus(nil)
+	}
+
+	now := time.Now()
+	previous := cluster.Status.TopologyHistory
+	if previous != nil && previous.LastRecorded != nil && previous.RecordedGeneration == cluster.Generation {
+		return nil
+	}
+	if previous != nil && previous.Message != "" && now.Sub(previous.LastAttempt.Time) < topologyHistoryRetryInterval {
+		return nil
+	}
+
+	status := &api.TopologyHistoryStatus{
+		LastAttempt: metav1.NewTime(now),
+	}
+	if previous != nil {
+		status.RecordedGeneration = previous.RecordedGeneration
+		status.LastKey = previous.LastKey
+		status.LastRecorded = previous.LastRecorded
+	}
+
+	key := topologyHistoryKey(cluster, now)
+	if err := er.writeTopologyRecord(key, status.LastAttempt); err != nil {
+		er.L().Error(err, "failed to record topology history", "bucket", spec.Bucket, "key", key)
+		status.Message = elasticsearchErrorReason(err)
+	} else {
+		recorded := status.LastAttempt
+		status.RecordedGeneration = cluster.Generation
+		status.LastKey = key
+		status.LastRecorded = &recorded
+	}
+
+	return er.updateTopologyHistoryStatus(status)
+}
+
+func (er *ElasticsearchRequest) writeTopologyRecord(key string, recordedAt metav1.Time) error {
+	cluster := er.cluster
+	spec := cluster.Spec.TopologyHistory
+
+	store, err := er.newTopologyHistoryStore()
+	if err != nil {
+		return err
+	}
+
+	record, err := er.newTopologyRecord(recordedAt)
+	if err != nil {
+		return err
+	}
+	body, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return kverrors.Wrap(err, "failed to encode topology record")
+	}
+
+	return store.PutObject(spec.Bucket, key, "application/json", body)
+}
+
+func (er *ElasticsearchRequest) newTopologyHistoryStore() (*objectstore.S3Client, error) {
+	cluster := er.cluster
+	spec := cluster.Spec.TopologyHistory
+
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Name: spec.CredentialsSecretRef.Name, Namespace: cluster.Namespace}
+	if err := er.client.Get(context.TODO(), key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, kverrors.New(fmt.Sprintf("Waiting for the credentials secret %q of the topology history", key.Name))
+		}
+		return nil, kverrors.Wrap(err, "failed to get topology history credentials",
+			"secret", key.Name)
+	}
+
+	credentials := objectstore.Credentials{
+		AccessKeyID:     string(secret.Data[topologyHistoryAccessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[topologyHistorySecretAccessKeyKey]),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, kverrors.New(fmt.Sprintf("Secret %q must hold the keys %s and %s",
+			key.Name, topologyHistoryAccessKeyIDKey, topologyHistorySecretAccessKeyKey))
+	}
+
+	region := spec.Region
+	if region == "" {
+		region = defaultTopologyHistoryRegion
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+		Timeout:   30 * time.Second,
+	}
+	return objectstore.NewS3Client(spec.Endpoint, region, credentials, httpClient)
+}
+
+// newTopologyRecord returns the spec of the cluster with the deployments, statefulsets and the
+// config map currently generated for it
+func (er *ElasticsearchRequest) newTopologyRecord(recordedAt metav1.Time) (*topologyRecord, error) {
+	cluster := er.cluster
+	record := &topologyRecord{
+		Generation:    cluster.Generation,
+		RecordedAt:    recordedAt,
+		Elasticsearch: newDisasterRecoverySpec(cluster),
+	}
+
+	labels := client.MatchingLabels{"cluster-name": cluster.Name, "component": "elasticsearch"}
+
+	deployments := &apps.DeploymentList{}
+	if err := er.client.List(context.TODO(), deployments, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list deployments of cluster",
+			"cluster", cluster.Name)
+	}
+	for _, dpl := range deployments.Items {
+		record.Deployments = append(record.Deployments, topologyWorkload{
+			Name:     dpl.Name,
+			Labels:   dpl.Labels,
+			Replicas: dpl.Spec.Replicas,
+			Template: dpl.Spec.Template,
+		})
+	}
+
+	statefulSets := &apps.StatefulSetList{}
+	if err := er.client.List(context.TODO(), statefulSets, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list statefulsets of cluster",
+			"cluster", cluster.Name)
+	}
+	for _, sts := range statefulSets.Items {
+		record.StatefulSets = append(record.StatefulSets, topologyWorkload{
+			Name:     sts.Name,
+			Labels:   sts.Labels,
+			Replicas: sts.Spec.Replicas,
+			Template: sts.Spec.Template,
+		})
+	}
+
+	sort.Slice(record.Deployments, func(i, j int) bool { return record.Deployments[i].Name < record.Deployments[j].Name })
+	sort.Slice(record.StatefulSets, func(i, j int) bool { return record.StatefulSets[i].Name < record.StatefulSets[j].Name })
+
+	configMap := &v1.ConfigMap{}
+	err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, kverrors.Wrap(err, "failed to get config map of cluster",
+			"cluster", cluster.Name)
+	}
+	record.ConfigMap = configMap.Data
+
+	return record, nil
+}
+
+// topologyHistoryKey returns the key of the record written at the time. The keys of a cluster sort
+// by the time they were recorded
+func topologyHistoryKey(cluster *api.Elasticsearch, now time.Time) string {
+	prefix := strings.Trim(cluster.Spec.TopologyHistory.Prefix, "/")
+	if prefix == "" {
+		prefix = fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
+	}
+	return fmt.Sprintf("%s/%s-%d.json", prefix, now.UTC().Format(topologyHistoryKeyTimeFormat), cluster.Generation)
+}
+
+func (er *ElasticsearchRequest) updateTopologyHistoryStatus(status *api.TopologyHistoryStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.TopologyHistory, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.TopologyHistory, status) {
+			return nil
+		}
+
+		cluster.Status.TopologyHistory = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update topology history status")
+}
@@ -0,0 +1,149 @@
+package k8shandler
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Topology history", func() {
+	defer GinkgoRecover()
+
+	var (
+		server  *httptest.Server
+		objects map[string][]byte
+		request *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		objects = map[string][]byte{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		}))
+
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "elasticsearch",
+				Namespace:  "openshift-logging",
+				Generation: 3,
+			},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy: api.SingleRedundancy,
+				TopologyHistory: &api.TopologyHistorySpec{
+					Endpoint:             server.URL,
+					Bucket:               "history",
+					CredentialsSecretRef: api.CertSecretReference{Name: "history-credentials"},
+				},
+			},
+		}
+		replicas := int32(2)
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClientWithScheme(s,
+				cluster,
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "history-credentials", Namespace: "openshift-logging"},
+					Data: map[string][]byte{
+						topologyHistoryAccessKeyIDKey:     []byte("key"),
+						topologyHistorySecretAccessKeyKey: []byte("secret"),
+					},
+				},
+				&apps.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "elasticsearch-cdm-1",
+						Namespace: "openshift-logging",
+						Labels:    map[string]string{"cluster-name": "elasticsearch", "component": "elasticsearch"},
+					},
+					Spec: apps.DeploymentSpec{Replicas: &replicas},
+				},
+				&v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+					Data:       map[string]string{"elasticsearch.yml": "cluster.name: elasticsearch"},
+				},
+			),
+			cluster: cluster,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record the spec and the generated workloads once per generation", func() {
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+
+		status := request.cluster.Status.TopologyHistory
+		Expect(status).ToNot(BeNil())
+		Expect(status.Message).To(BeEmpty())
+		Expect(status.RecordedGeneration).To(Equal(int64(3)))
+		Expect(status.LastKey).To(MatchRegexp(`^openshift-logging/elasticsearch/\d{8}T\d{6}Z-3\.json$`))
+		Expect(objects).To(HaveKey("/history/" + status.LastKey))
+
+		record := &topologyRecord{}
+		Expect(json.Unmarshal(objects["/history/"+status.LastKey], record)).To(Succeed())
+		Expect(record.Generation).To(Equal(int64(3)))
+		Expect(record.Elasticsearch).To(HaveKeyWithValue("kind", "Elasticsearch"))
+		Expect(record.Deployments).To(HaveLen(1))
+		Expect(record.Deployments[0].Name).To(Equal("elasticsearch-cdm-1"))
+		Expect(*record.Deployments[0].Replicas).To(Equal(int32(2)))
+		Expect(record.ConfigMap).To(HaveKeyWithValue("elasticsearch.yml", "cluster.name: elasticsearch"))
+
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+		Expect(objects).To(HaveLen(1))
+	})
+
+	It("should report missing credentials and retry after a failure", func() {
+		request.cluster.Spec.TopologyHistory.CredentialsSecretRef.Name = "missing"
+
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+		status := request.cluster.Status.TopologyHistory
+		Expect(status.Message).To(ContainSubstring(`"missing"`))
+		Expect(status.LastRecorded).To(BeNil())
+		Expect(objects).To(BeEmpty())
+
+		request.cluster.Spec.TopologyHistory.CredentialsSecretRef.Name = "history-credentials"
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+		Expect(objects).To(BeEmpty())
+
+		request.cluster.Status.TopologyHistory.LastAttempt = metav1.NewTime(time.Now().Add(-topologyHistoryRetryInterval))
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+		Expect(request.cluster.Status.TopologyHistory.Message).To(BeEmpty())
+		Expect(objects).To(HaveLen(1))
+	})
+
+	It("should clear the status when the history is disabled", func() {
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+
+		request.cluster.Spec.TopologyHistory = nil
+		Expect(request.RecordTopologyHistory()).To(Succeed())
+
+		cluster := &api.Elasticsearch{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, cluster)).To(Succeed())
+		Expect(cluster.Status.TopologyHistory).To(BeNil())
+	})
+
+	It("should honor the prefix of the keys", func() {
+		request.cluster.Spec.TopologyHistory.Prefix = "/prod/logging/"
+		now := time.Date(2020, time.March, 3, 10, 4, 5, 0, time.UTC)
+		Expect(topologyHistoryKey(request.cluster, now)).To(Equal("prod/logging/20200303T100405Z-3.json"))
+	})
+})
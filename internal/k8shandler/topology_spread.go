@@ -0,0 +1,46 @@
+package k8shandler
+
+import (
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTopologySpreadConstraints returns the constraints of the node or spreads the pods of the nodes
+// with the same roles across zones and hosts. The default constraints are preferences so single zone
+// clusters and clusters with more nodes than hosts keep scheduling
+func newTopologySpreadConstraints(clusterName string, node api.ElasticsearchNode, roleMap map[api.ElasticsearchNodeRole]bool, zoneAwareness *api.ZoneAwarenessSpec) []v1.TopologySpreadConstraint {
+	if len(node.TopologySpreadConstraints) > 0 {
+		return node.TopologySpreadConstraints
+	}
+
+	zoneKey := defaultZoneTopologyKey
+	if zoneAwareness != nil {
+		zoneKey = zoneTopologyKey(zoneAwareness)
+	}
+
+	// the pods of a node group run in one deployment per node, so they are selected by their roles
+	// instead of their node name
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"cluster-name":   clusterName,
+			"component":      "elasticsearch",
+			"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
+			"es-node-data":   strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
+			"es-node-master": strconv.FormatBool(roleMap[api.ElasticsearchRoleMaster]),
+		},
+	}
+
+	constraints := []v1.TopologySpreadConstraint{}
+	for _, key := range []string{zoneKey, hostnameLabel} {
+		constraints = append(constraints, v1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: v1.ScheduleAnyway,
+			LabelSelector:     selector.DeepCopy(),
+		})
+	}
+	return constraints
+}
@@ -0,0 +1,227 @@
+package k8shandler
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// targetedHandler reconciles the spec fields only it depends on, so a change limited to these
+// fields does not require a full reconcile of the cluster
+type targetedHandler struct {
+	name string
+	// field returns a pointer to the spec field the handler depends on
+	field     func(spec *api.ElasticsearchSpec) interface{}
+	reconcile func(er *ElasticsearchRequest) error
+}
+
+var targetedHandlers = []targetedHandler{
+	{
+		name:      "retention",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.Retention },
+		reconcile: (*ElasticsearchRequest).ReconcileRetention,
+	},
+	{
+		name:      "refreshIntervals",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.RefreshIntervalPolicies },
+		reconcile: (*ElasticsearchRequest).ReconcileRefreshIntervals,
+	},
+	{
+		name:      "rolloverAliases",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.RolloverAliases },
+		reconcile: (*ElasticsearchRequest).ReconcileRolloverAliases,
+	},
+	{
+		name:      "snapshotRepositories",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.SnapshotRepositoryHealthCheck },
+		reconcile: (*ElasticsearchRequest).CheckSnapshotRepositories,
+	},
+	{
+		name:      "disasterRecoveryExport",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.DisasterRecoveryExport },
+		reconcile: (*ElasticsearchRequest).ExportDisasterRecoveryManifest,
+	},
+	{
+		name:      "metadataBackup",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.MetadataBackup },
+		reconcile: (*ElasticsearchRequest).BackupClusterMetadata,
+	},
+	{
+		name:      "hotShards",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.HotShardDetection },
+		reconcile: (*ElasticsearchRequest).CheckHotShards,
+	},
+	{
+		name:      "diskPressure",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.DiskPressure },
+		reconcile: (*ElasticsearchRequest).ReconcileDiskPressure,
+	},
+	{
+		name:      "emergencyRetention",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.EmergencyRetention },
+		reconcile: (*ElasticsearchRequest).ReconcileEmergencyRetention,
+	},
+	{
+		name:      "orphanedClaims",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.OrphanedClaimCollection },
+		reconcile: (*ElasticsearchRequest).CollectOrphanedClaims,
+	},
+	{
+		name:      "monitoring",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.Monitoring },
+		reconcile: (*ElasticsearchRequest).ReconcileMonitoring,
+	},
+	{
+		name:      "alerts",
+		field:     func(spec *api.ElasticsearchSpec) interface{} { return &spec.Alerts },
+		reconcile: (*ElasticsearchRequest).CreateOrUpdatePrometheusRules,
+	},
+}
+
+// targetedAnnotations maps the annotations of the cluster only a targeted handler depends on to it
+var targetedAnnotations = map[string]string{
+	disasterRecoveryExportAnnotation: "disasterRecoveryExport",
+}
+
+// relevantAnnotationPrefixes of the annotations of the cluster the operator depends on. Changes
+// to other annotations and to the labels do not trigger a reconcile
+var relevantAnnotationPrefixes = []string{
+	"elasticsearch.openshift.io/",
+	"logging.openshift.io/",
+}
+
+// UpdateScope is the part of the reconcile an update of a cluster requires
+type UpdateScope struct {
+	// Full when the update requires a full reconcile
+	Full bool
+	// Handlers are the names of the targeted handlers the update requires otherwise
+	Handlers []string
+}
+
+// IsEmpty returns true if the update does not require a reconcile, e.g. it only changed the status
+func (scope UpdateScope) IsEmpty() bool {
+	return !scope.Full && len(scope.Handlers) == 0
+}
+
+// Merge returns the scope covering both updates
+func (scope UpdateScope) Merge(other UpdateScope) UpdateScope {
+	if scope.Full || other.Full {
+		return UpdateScope{Full: true}
+	}
+
+	merged := UpdateScope{Handlers: append([]string{}, scope.Handlers...)}
+	for _, name := range other.Handlers {
+		if !utils.ContainsString(merged.Handlers, name) {
+			merged.Handlers = append(merged.Handlers, name)
+		}
+	}
+	sort.Strings(merged.Handlers)
+	return merged
+}
+
+// ScopeOfUpdate returns the part of the reconcile the update of the cluster requires. Spec changes
+// limited to the fields of targeted handlers only require these handlers
+func ScopeOfUpdate(oldCluster, newCluster *api.Elasticsearch) UpdateScope {
+	if (oldCluster.GetDeletionTimestamp() == nil) != (newCluster.GetDeletionTimestamp() == nil) ||
+		!reflect.DeepEqual(oldCluster.GetFinalizers(), newCluster.GetFinalizers()) {
+		return UpdateScope{Full: true}
+	}
+
+	scope := scopeOfAnnotations(oldCluster.GetAnnotations(), newCluster.GetAnnotations())
+	if scope.Full || oldCluster.GetGeneration() == newCluster.GetGeneration() {
+		return scope
+	}
+
+	oldSpec := oldCluster.Spec.DeepCopy()
+	newSpec := newCluster.Spec.DeepCopy()
+	for _, handler := range targetedHandlers {
+		oldField := reflect.ValueOf(handler.field(oldSpec)).Elem()
+		newField := reflect.ValueOf(handler.field(newSpec)).Elem()
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			scope = scope.Merge(UpdateScope{Handlers: []string{handler.name}})
+		}
+		oldField.Set(reflect.Zero(oldField.Type()))
+		newField.Set(reflect.Zero(newField.Type()))
+	}
+
+	if !reflect.DeepEqual(oldSpec, newSpec) {
+		return UpdateScope{Full: true}
+	}
+	return scope
+}
+
+func scopeOfAnnotations(oldAnnotations, newAnnotations map[string]string) UpdateScope {
+	scope := UpdateScope{}
+	for _, key := range changedKeys(oldAnnotations, newAnnotations) {
+		if !isRelevantAnnotation(key) {
+			continue
+		}
+		name, ok := targetedAnnotations[key]
+		if !ok {
+			return UpdateScope{Full: true}
+		}
+		scope = scope.Merge(UpdateScope{Handlers: []string{name}})
+	}
+	return scope
+}
+
+func changedKeys(oldValues, newValues map[string]string) []string {
+	keys := []string{}
+	for key, value := range oldValues {
+		if newValue, ok := newValues[key]; !ok || newValue != value {
+			keys = append(keys, key)
+		}
+	}
+	for key := range newValues {
+		if _, ok := oldValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func isRelevantAnnotation(key string) bool {
+	for _, prefix := range relevantAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconcileHandlers runs the named targeted handlers for the cluster instead of a full reconcile
+func ReconcileHandlers(requestCluster *api.Elasticsearch, requestClient client.Client, recorder record.EventRecorder, names []string) error {
+	elasticsearchRequest := &ElasticsearchRequest{
+		client:   requestClient,
+		cluster:  requestCluster,
+		esClient: elasticsearch.NewClient(requestCluster.Name, requestCluster.Namespace, requestClient),
+		recorder: recorder,
+		ll:       log.WithValues("cluster", requestCluster.Name, "namespace", requestCluster.Namespace),
+	}
+
+	for _, handler := range targetedHandlers {
+		if !utils.ContainsString(names, handler.name) {
+			continue
+		}
+		elasticsearchRequest.L().V(1).Info("Running targeted reconcile", "handler", handler.name)
+		if err := handler.reconcile(elasticsearchRequest); err != nil {
+			return kverrors.Wrap(err, "Failed to run targeted reconcile for Elasticsearch cluster",
+				"handler", handler.name)
+		}
+	}
+
+	// the topology history records every accepted generation, including the ones only changing
+	// fields of targeted handlers
+	if err := elasticsearchRequest.RecordTopologyHistory(); err != nil {
+		return kverrors.Wrap(err, "Failed to record topology history for Elasticsearch cluster")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package k8shandler
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Update scope", func() {
+	defer GinkgoRecover()
+
+	var (
+		oldCluster *api.Elasticsearch
+		newCluster *api.Elasticsearch
+	)
+
+	BeforeEach(func() {
+		oldCluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "elasticsearch",
+				Namespace:   "openshift-logging",
+				Generation:  1,
+				Annotations: map[string]string{"elasticsearch.openshift.io/loglevel": "info"},
+			},
+			Spec: api.ElasticsearchSpec{
+				ManagementState: api.ManagementStateManaged,
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+		newCluster = oldCluster.DeepCopy()
+	})
+
+	It("should skip updates of the status", func() {
+		newCluster.Status.ClusterHealth = "green"
+		Expect(ScopeOfUpdate(oldCluster, newCluster).IsEmpty()).To(BeTrue())
+	})
+
+	It("should skip updates of the labels and unrelated annotations", func() {
+		newCluster.Labels = map[string]string{"team": "logging"}
+		newCluster.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = "{}"
+		Expect(ScopeOfUpdate(oldCluster, newCluster).IsEmpty()).To(BeTrue())
+	})
+
+	It("should require a full reconcile for the annotations of the operator", func() {
+		newCluster.Annotations["elasticsearch.openshift.io/loglevel"] = "debug"
+		Expect(ScopeOfUpdate(oldCluster, newCluster)).To(Equal(UpdateScope{Full: true}))
+	})
+
+	It("should require a full reconcile when the cluster is deleted", func() {
+		now := metav1.NewTime(time.Now())
+		newCluster.DeletionTimestamp = &now
+		Expect(ScopeOfUpdate(oldCluster, newCluster)).To(Equal(UpdateScope{Full: true}))
+	})
+
+	It("should only require the handlers of the changed fields", func() {
+		newCluster.Generation = 2
+		newCluster.Spec.Retention = &api.RetentionSpec{Interval: &metav1.Duration{Duration: time.Hour}}
+		newCluster.Spec.DiskPressure = &api.DiskPressureSpec{}
+		newCluster.Annotations[disasterRecoveryExportAnnotation] = "now"
+
+		Expect(ScopeOfUpdate(oldCluster, newCluster)).To(Equal(UpdateScope{
+			Handlers: []string{"disasterRecoveryExport", "diskPressure", "retention"},
+		}))
+	})
+
+	It("should require a full reconcile when other fields changed too", func() {
+		newCluster.Generation = 2
+		newCluster.Spec.Retention = &api.RetentionSpec{Interval: &metav1.Duration{Duration: time.Hour}}
+		newCluster.Spec.Nodes[0].NodeCount = 5
+
+		Expect(ScopeOfUpdate(oldCluster, newCluster)).To(Equal(UpdateScope{Full: true}))
+	})
+
+	It("should merge targeted scopes and keep full ones", func() {
+		scope := UpdateScope{Handlers: []string{"retention"}}.Merge(UpdateScope{Handlers: []string{"hotShards", "retention"}})
+		Expect(scope).To(Equal(UpdateScope{Handlers: []string{"hotShards", "retention"}}))
+		Expect(scope.Merge(UpdateScope{Full: true})).To(Equal(UpdateScope{Full: true}))
+	})
+})
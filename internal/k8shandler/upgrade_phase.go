@@ -0,0 +1,47 @@
+package k8shandler
+
+import (
+	"time"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setUpgradePhase moves the node to the upgrade phase and records the time it entered it
+func setUpgradePhase(nodeStatus *api.ElasticsearchNodeStatus, phase api.ElasticsearchUpgradePhase) {
+	if nodeStatus.UpgradeStatus.UpgradePhase == phase && nodeStatus.UpgradeStatus.PhaseStartTime != nil {
+		return
+	}
+	nodeStatus.UpgradeStatus.UpgradePhase = phase
+	nodeStatus.UpgradeStatus.PhaseStartTime = &metav1.Time{Time: time.Now()}
+}
+
+// isUpgradingPhase returns true if a node in the phase is still being upgraded
+func isUpgradingPhase(phase api.ElasticsearchUpgradePhase) bool {
+	return phase != "" && phase != api.ControllerUpdated
+}
+
+// reportUpgradePhases reports the time the upgrading nodes spent in their current phase in the
+// eo_es_node_upgrade_phase_seconds metric
+func reportUpgradePhases(cluster *api.Elasticsearch, status *api.ElasticsearchStatus, now time.Time) {
+	phases := map[string]metrics.NodeUpgradePhase{}
+
+	for _, node := range status.Nodes {
+		upgrade := node.UpgradeStatus
+		if !isUpgradingPhase(upgrade.UpgradePhase) || upgrade.PhaseStartTime == nil {
+			continue
+		}
+
+		name := node.DeploymentName
+		if name == "" {
+			name = node.StatefulSetName
+		}
+		phases[name] = metrics.NodeUpgradePhase{
+			Phase:    string(upgrade.UpgradePhase),
+			Duration: now.Sub(upgrade.PhaseStartTime.Time),
+		}
+	}
+
+	metrics.SetNodeUpgradePhases(cluster.Name, cluster.Namespace, phases)
+}
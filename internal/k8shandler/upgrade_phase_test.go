@@ -0,0 +1,34 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("Upgrade phase", func() {
+	defer GinkgoRecover()
+
+	It("should record the time the node entered its current phase", func() {
+		status := &api.ElasticsearchNodeStatus{}
+
+		setUpgradePhase(status, api.PreparationComplete)
+		Expect(status.UpgradeStatus.UpgradePhase).To(Equal(api.PreparationComplete))
+		Expect(status.UpgradeStatus.PhaseStartTime).ToNot(BeNil())
+		started := status.UpgradeStatus.PhaseStartTime
+
+		setUpgradePhase(status, api.PreparationComplete)
+		Expect(status.UpgradeStatus.PhaseStartTime).To(BeIdenticalTo(started))
+
+		setUpgradePhase(status, api.NodeRestarting)
+		Expect(status.UpgradeStatus.PhaseStartTime).ToNot(BeIdenticalTo(started))
+	})
+
+	It("should only consider the nodes being upgraded", func() {
+		Expect(isUpgradingPhase("")).To(BeFalse())
+		Expect(isUpgradingPhase(api.ControllerUpdated)).To(BeFalse())
+		Expect(isUpgradingPhase(api.NodeRestarting)).To(BeTrue())
+		Expect(isUpgradingPhase(api.RecoveringData)).To(BeTrue())
+	})
+})
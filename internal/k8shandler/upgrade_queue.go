@@ -0,0 +1,108 @@
+package k8shandler
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Changes of spec.nodes submitted while an upgrade is in flight are not interleaved with it. The
+// queue is not stored: populateNodes derives it from the current spec on every reconciliation, so
+// several edits during one upgrade collapse into the last one and nothing is rejected. While any
+// node is in flight
+//
+//   - add: a node without a status entry is tracked but only created by the step creating the
+//     missing nodes, which runs once no node upgrade is in progress
+//   - update: a node in flight keeps the pod template and replicas its upgrade started with. The
+//     desired ones replace them on the first reconciliation after the node left the upgrade, which
+//     schedules another upgrade of the node if they differ. Nodes not in flight are updated
+//     immediately and scheduled behind the in-flight ones as before
+//   - remove: a node no longer in the spec is kept and deleted on the first reconciliation
+//     without a node in flight, so the upgrade does not lose a node it counts on
+//
+// The queued changes are reported by the SpecChangeQueued condition, which is reset to False once
+// nothing is queued.
+
+// inFlightNodeNames returns the names of the nodes under upgrade or all nodes while the whole
+// cluster restarts
+func (er *ElasticsearchRequest) inFlightNodeNames() sets.String {
+	cluster := er.cluster
+	names := sets.NewString()
+
+	if containsClusterCondition(api.Restarting, v1.ConditionTrue, &cluster.Status) {
+		for _, node := range nodes[nodeMapKey(cluster.Name, cluster.Namespace)] {
+			names.Insert(node.name())
+		}
+	}
+
+	for _, node := range cluster.Status.Nodes {
+		if node.UpgradeStatus.UnderUpgrade != v1.ConditionTrue {
+			continue
+		}
+		if node.DeploymentName != "" {
+			names.Insert(node.DeploymentName)
+		}
+		if node.StatefulSetName != "" {
+			names.Insert(node.StatefulSetName)
+		}
+	}
+
+	return names
+}
+
+// desiredNodeChanged returns true if the desired pod template or replicas of the node differ
+func desiredNodeChanged(current, desired NodeTypeInterface) bool {
+	switch current := current.(type) {
+	case *deploymentNode:
+		desired, ok := desired.(*deploymentNode)
+		return !ok || current.replicas != desired.replicas ||
+			ArePodTemplateSpecDifferent(current.self.Spec.Template, desired.self.Spec.Template)
+	case *statefulSetNode:
+		desired, ok := desired.(*statefulSetNode)
+		return !ok || current.replicas != desired.replicas ||
+			ArePodTemplateSpecDifferent(current.self.Spec.Template, desired.self.Spec.Template)
+	}
+	return false
+}
+
+// updateSpecChangeQueuedCondition reports the changes to the nodes which wait for the upgrade in
+// flight to complete
+func (er *ElasticsearchRequest) updateSpecChangeQueuedCondition(inFlight sets.String, queued []string) error {
+	value := v1.ConditionFalse
+	var reason, message string
+	if len(queued) > 0 {
+		value = v1.ConditionTrue
+		reason = "UpgradeInProgress"
+		message = fmt.Sprintf("Changes are applied once the upgrade of %s completes: %s",
+			strings.Join(inFlight.List(), ", "), strings.Join(queued, ", "))
+	}
+
+	condition := &api.ClusterCondition{
+		Type:    api.SpecChangeQueued,
+		Status:  value,
+		Reason:  reason,
+		Message: message,
+	}
+
+	// avoid the update of the status on every reconcile
+	if _, current := getESNodeCondition(er.cluster.Status.Conditions, api.SpecChangeQueued); current == nil && value == v1.ConditionFalse {
+		return nil
+	} else if current != nil && current.Status == value && current.Reason == reason && current.Message == message {
+		return nil
+	}
+
+	if len(queued) > 0 {
+		er.L().Info("Queued changes to nodes until the upgrade completes", "in_flight", inFlight.List(), "queued", queued)
+	}
+	return updateConditionWithRetry(
+		er.cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, condition)
+		},
+		er.client,
+	)
+}
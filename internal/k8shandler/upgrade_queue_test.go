@@ -0,0 +1,108 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Spec changes during an upgrade", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster *api.Elasticsearch
+		er      *ElasticsearchRequest
+		key     = nodeMapKey("elasticsearch", "openshift-logging")
+		memory  = resource.MustParse("8Gi")
+	)
+
+	BeforeEach(func() {
+		uuid := "abc"
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 2,
+						GenUUID:   &uuid,
+					},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-abc-1",
+						UpgradeStatus:  api.ElasticsearchNodeUpgradeStatus{UnderUpgrade: v1.ConditionTrue},
+					},
+					{DeploymentName: "elasticsearch-cdm-abc-2"},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		er = &ElasticsearchRequest{client: fake.NewFakeClientWithScheme(s, cluster), cluster: cluster}
+
+		if nodes == nil {
+			nodes = map[string][]NodeTypeInterface{}
+		}
+		nodes[key] = nil
+		Expect(er.populateNodes()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		delete(nodes, key)
+	})
+
+	It("should keep the desired state of the node under upgrade", func() {
+		cluster.Spec.Spec.Resources.Limits = v1.ResourceList{v1.ResourceMemory: memory}
+		Expect(er.client.Update(context.TODO(), cluster)).To(Succeed())
+		Expect(er.populateNodes()).To(Succeed())
+
+		Expect(nodes[key]).To(HaveLen(2))
+		inFlight := nodes[key][0].(*deploymentNode)
+		Expect(inFlight.self.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String()).ToNot(Equal("8Gi"))
+		scheduled := nodes[key][1].(*deploymentNode)
+		Expect(scheduled.self.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String()).To(Equal("8Gi"))
+
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.SpecChangeQueued)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Message).To(Equal("Changes are applied once the upgrade of elasticsearch-cdm-abc-1 completes: update elasticsearch-cdm-abc-1"))
+	})
+
+	It("should keep the removed nodes until the upgrade completes", func() {
+		cluster.Spec.Nodes[0].NodeCount = 1
+		Expect(er.client.Update(context.TODO(), cluster)).To(Succeed())
+		Expect(er.populateNodes()).To(Succeed())
+
+		Expect(nodes[key]).To(HaveLen(2))
+		_, condition := getESNodeCondition(cluster.Status.Conditions, api.SpecChangeQueued)
+		Expect(condition.Message).To(HaveSuffix("remove elasticsearch-cdm-abc-2"))
+	})
+
+	It("should apply the queued changes once the upgrade completes", func() {
+		cluster.Spec.Spec.Resources.Limits = v1.ResourceList{v1.ResourceMemory: memory}
+		Expect(er.client.Update(context.TODO(), cluster)).To(Succeed())
+		Expect(er.populateNodes()).To(Succeed())
+
+		cluster.Status.Nodes[0].UpgradeStatus.UnderUpgrade = ""
+		Expect(er.client.Status().Update(context.TODO(), cluster)).To(Succeed())
+		Expect(er.populateNodes()).To(Succeed())
+
+		inFlight := nodes[key][0].(*deploymentNode)
+		Expect(inFlight.self.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String()).To(Equal("8Gi"))
+		Expect(containsClusterCondition(api.SpecChangeQueued, v1.ConditionTrue, &cluster.Status)).To(BeFalse())
+	})
+})
@@ -0,0 +1,100 @@
+package k8shandler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// upgradeAttemptPhase returns the upgrade phase the node is retrying
+func upgradeAttemptPhase(nodeStatus *api.ElasticsearchNodeStatus) api.ElasticsearchUpgradePhase {
+	if nodeStatus.UpgradeStatus.UpgradePhase == "" {
+		return api.ControllerUpdated
+	}
+	return nodeStatus.UpgradeStatus.UpgradePhase
+}
+
+// upgradeMaxAttempts returns the failed attempts allowed for the phase or 0 if the phase is
+// retried indefinitely
+func upgradeMaxAttempts(budget *api.UpgradeRetryBudgetSpec, phase api.ElasticsearchUpgradePhase) int32 {
+	if budget == nil {
+		return 0
+	}
+	if attempts, ok := budget.PhaseMaxAttempts[phase]; ok {
+		return attempts
+	}
+	return budget.MaxAttempts
+}
+
+// checkUpgradeRetryBudget returns an error if the current phase of the node upgrade exhausted its
+// retry budget. The upgrade resumes once the budget is raised or the pod template of the node changes
+func (er *ElasticsearchRequest) checkUpgradeRetryBudget(node NodeTypeInterface, nodeStatus *api.ElasticsearchNodeStatus) error {
+	phase := upgradeAttemptPhase(nodeStatus)
+	maxAttempts := upgradeMaxAttempts(er.cluster.Spec.UpgradeRetryBudget, phase)
+
+	if maxAttempts > 0 && nodeStatus.UpgradeStatus.PhaseAttempts >= maxAttempts {
+		// the pod template changes once the node spec is fixed, e.g. the image which could not be pulled
+		changes := node.state().UpgradeStatus.ScheduledChanges
+		if len(changes) == 0 || reflect.DeepEqual(changes, nodeStatus.UpgradeStatus.ScheduledChanges) {
+			return kverrors.New("upgrade phase exhausted its retry budget",
+				"node", node.name(),
+				"phase", phase,
+				"attempts", nodeStatus.UpgradeStatus.PhaseAttempts)
+		}
+
+		er.L().Info("Resuming upgrade of node after its spec changed", "node", node.name(), "phase", phase, "changes", changes)
+		nodeStatus.UpgradeStatus.PhaseAttempts = 0
+		nodeStatus.UpgradeStatus.ScheduledChanges = changes
+		if err := er.setNodeStatus(node, nodeStatus, er.cluster.Status.DeepCopy()); err != nil {
+			return err
+		}
+	}
+
+	if containsClusterCondition(api.FailedUpgrade, v1.ConditionTrue, &er.cluster.Status) {
+		return updateFailedUpgradeCondition(er.cluster, v1.ConditionFalse, "", er.client)
+	}
+	return nil
+}
+
+// recordFailedUpgradeAttempt counts the failed attempt of the current phase of the node upgrade and
+// fails the upgrade once the phase exhausted its retry budget
+func (er *ElasticsearchRequest) recordFailedUpgradeAttempt(node NodeTypeInterface, nodeStatus *api.ElasticsearchNodeStatus, cause error) error {
+	// the upgrade did not begin yet if the precheck failed
+	if nodeStatus.UpgradeStatus.UnderUpgrade != v1.ConditionTrue {
+		return cause
+	}
+
+	// waiting for the host of a local volume is not a failure of the upgrade
+	if errors.Is(cause, ErrWaitingForLocalVolumeHost) {
+		return cause
+	}
+
+	phase := upgradeAttemptPhase(nodeStatus)
+	nodeStatus.UpgradeStatus.PhaseAttempts++
+	if err := er.setNodeStatus(node, nodeStatus, er.cluster.Status.DeepCopy()); err != nil {
+		er.L().Error(err, "unable to update node status", "node", node.name())
+	}
+
+	attempts := nodeStatus.UpgradeStatus.PhaseAttempts
+	maxAttempts := upgradeMaxAttempts(er.cluster.Spec.UpgradeRetryBudget, phase)
+	if maxAttempts == 0 || attempts < maxAttempts {
+		return cause
+	}
+
+	message := fmt.Sprintf("Upgrade of node %s failed %d times in phase %s: %v. "+
+		"Resolve the cause, e.g. an image which cannot be pulled, and update the node spec "+
+		"or raise spec.upgradeRetryBudget to resume the upgrade",
+		node.name(), attempts, phase, cause)
+	if err := updateFailedUpgradeCondition(er.cluster, v1.ConditionTrue, message, er.client); err != nil {
+		er.L().Error(err, "unable to update failed upgrade condition", "node", node.name())
+	}
+
+	return kverrors.Wrap(cause, "upgrade phase exhausted its retry budget",
+		"node", node.name(),
+		"phase", phase,
+		"attempts", attempts)
+}
@@ -0,0 +1,95 @@
+package k8shandler
+
+import (
+	"errors"
+
+	"github.com/ViaQ/logerr/kverrors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Upgrade retry budget", func() {
+	defer GinkgoRecover()
+
+	var (
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		node    *deploymentNode
+		cause   = errors.New("node did not rejoin the cluster")
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				UpgradeRetryBudget: &api.UpgradeRetryBudgetSpec{
+					MaxAttempts:      5,
+					PhaseMaxAttempts: map[api.ElasticsearchUpgradePhase]int32{api.NodeRestarting: 2},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{
+						DeploymentName: "elasticsearch-cdm-abc-1",
+						UpgradeStatus: api.ElasticsearchNodeUpgradeStatus{
+							UnderUpgrade:  v1.ConditionTrue,
+							UpgradePhase:  api.NodeRestarting,
+							PhaseAttempts: 1,
+						},
+					},
+				},
+			},
+		}
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster),
+			cluster: cluster,
+		}
+		node = &deploymentNode{
+			self:   apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1", Namespace: "openshift-logging"}},
+			client: request.client,
+		}
+	})
+
+	It("should fail the upgrade once the phase exhausted its budget", func() {
+		Expect(request.recordFailedUpgradeAttempt(node, request.getNodeState(node), cause)).NotTo(Succeed())
+		Expect(request.getNodeState(node).UpgradeStatus.PhaseAttempts).To(Equal(int32(2)))
+		Expect(containsClusterCondition(api.FailedUpgrade, v1.ConditionTrue, &cluster.Status)).To(BeTrue())
+
+		Expect(request.checkUpgradeRetryBudget(node, request.getNodeState(node))).NotTo(Succeed())
+	})
+
+	It("should resume the upgrade once the budget is raised", func() {
+		Expect(request.recordFailedUpgradeAttempt(node, request.getNodeState(node), cause)).NotTo(Succeed())
+
+		cluster.Spec.UpgradeRetryBudget.PhaseMaxAttempts = nil
+		Expect(request.checkUpgradeRetryBudget(node, request.getNodeState(node))).To(Succeed())
+		Expect(containsClusterCondition(api.FailedUpgrade, v1.ConditionTrue, &cluster.Status)).To(BeFalse())
+	})
+
+	It("should not count the attempts before the upgrade begins", func() {
+		nodeStatus := request.getNodeState(node)
+		nodeStatus.UpgradeStatus.UnderUpgrade = ""
+
+		Expect(request.recordFailedUpgradeAttempt(node, nodeStatus, cause)).To(Equal(cause))
+		Expect(nodeStatus.UpgradeStatus.PhaseAttempts).To(Equal(int32(1)))
+	})
+	It("should not count the attempts waiting for the host of a local volume", func() {
+		nodeStatus := request.getNodeState(node)
+		waiting := kverrors.Wrap(ErrWaitingForLocalVolumeHost, "pod is pinned to an unavailable host")
+
+		Expect(request.recordFailedUpgradeAttempt(node, nodeStatus, waiting)).To(Equal(waiting))
+		Expect(nodeStatus.UpgradeStatus.PhaseAttempts).To(Equal(int32(1)))
+	})
+})
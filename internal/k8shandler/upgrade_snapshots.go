@@ -0,0 +1,156 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	volumeSnapshotAPIVersion = "snapshot.storage.k8s.io/v1beta1"
+	volumeSnapshotKind       = "VolumeSnapshot"
+	// upgradeSnapshotTimeFormat is appended to the name of the claim to name its snapshot
+	upgradeSnapshotTimeFormat = "20060102150405"
+)
+
+// snapshotBeforeUpgrade returns a precheck taking snapshots of the claims of the data nodes once the
+// given precheck passed, so the nodes are not restarted before their data is snapshotted
+func (er *ElasticsearchRequest) snapshotBeforeUpgrade(precheck func() error, nodes []NodeTypeInterface) func() error {
+	return func() error {
+		if err := precheck(); err != nil {
+			return err
+		}
+
+		if er.cluster.Spec.UpgradeSnapshots == nil {
+			return nil
+		}
+
+		for _, node := range nodes {
+			if err := er.snapshotNodeClaims(node); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// snapshotNodeClaims creates a VolumeSnapshot of every claim used by the data pods of the node and
+// records the snapshots in the cluster status
+func (er *ElasticsearchRequest) snapshotNodeClaims(node NodeTypeInterface) error {
+	cluster := er.cluster
+
+	pods := &v1.PodList{}
+	labels := client.MatchingLabels{
+		"cluster-name": cluster.Name,
+		"node-name":    node.name(),
+		"es-node-data": "true",
+	}
+	if err := er.client.List(context.TODO(), pods, client.InNamespace(cluster.Namespace), labels); err != nil {
+		return kverrors.Wrap(err, "failed to list pods of node",
+			"node", node.name())
+	}
+
+	claims := sets.NewString()
+	for _, pod := range pods.Items {
+		claims.Insert(volumeClaimNames(pod.Spec.Volumes)...)
+	}
+	if claims.Len() == 0 {
+		return nil
+	}
+
+	now := metav1.Now()
+	snapshots := []api.UpgradeSnapshotStatus{}
+	for _, claim := range claims.List() {
+		name := fmt.Sprintf("%s-%s", claim, now.UTC().Format(upgradeSnapshotTimeFormat))
+		snapshot := newVolumeSnapshot(name, claim, cluster.Namespace, cluster.Spec.UpgradeSnapshots.VolumeSnapshotClassName, cluster.Labels)
+
+		if err := er.client.Create(context.TODO(), snapshot); err != nil {
+			return kverrors.Wrap(err, "failed to create volume snapshot",
+				"claim", claim,
+				"snapshot", name)
+		}
+
+		er.L().Info("Created volume snapshot before upgrade", "node", node.name(), "pvc", claim, "snapshot", name)
+		snapshots = append(snapshots, api.UpgradeSnapshotStatus{
+			Node:         node.name(),
+			ClaimName:    claim,
+			SnapshotName: name,
+			CreationTime: now,
+		})
+	}
+
+	if er.recorder != nil {
+		er.recorder.Event(cluster, v1.EventTypeNormal, "UpgradeSnapshotCreated",
+			fmt.Sprintf("Snapshotted %d claim(s) of node %s before its upgrade", len(snapshots), node.name()))
+	}
+
+	return er.updateUpgradeSnapshotStatus(mergeUpgradeSnapshots(cluster.Status.UpgradeSnapshots, snapshots))
+}
+
+// mergeUpgradeSnapshots replaces the snapshots of the claims snapshotted again
+func mergeUpgradeSnapshots(current, snapshots []api.UpgradeSnapshotStatus) []api.UpgradeSnapshotStatus {
+	merged := []api.UpgradeSnapshotStatus{}
+	snapshotted := sets.NewString()
+	for _, snapshot := range snapshots {
+		snapshotted.Insert(snapshot.ClaimName)
+	}
+	for _, snapshot := range current {
+		if !snapshotted.Has(snapshot.ClaimName) {
+			merged = append(merged, snapshot)
+		}
+	}
+	return append(merged, snapshots...)
+}
+
+func newVolumeSnapshot(name, claim, namespace, className string, labels map[string]string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": claim,
+		},
+	}
+	if className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	snapshot.SetAPIVersion(volumeSnapshotAPIVersion)
+	snapshot.SetKind(volumeSnapshotKind)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(namespace)
+	snapshot.SetLabels(labels)
+
+	// the snapshot is deliberately not owned by the cluster to outlive it
+	return snapshot
+}
+
+func (er *ElasticsearchRequest) updateUpgradeSnapshotStatus(snapshots []api.UpgradeSnapshotStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.UpgradeSnapshots, snapshots) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.UpgradeSnapshots = snapshots
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update upgrade snapshot status")
+}
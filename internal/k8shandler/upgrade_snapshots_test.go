@@ -0,0 +1,110 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Upgrade snapshots", func() {
+	defer GinkgoRecover()
+
+	var (
+		k8sClient client.Client
+		cluster   *api.Elasticsearch
+		er        *ElasticsearchRequest
+		node      *deploymentNode
+	)
+
+	newPod := func(name, nodeName, data, claim string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"cluster-name": "elasticsearch",
+					"node-name":    nodeName,
+					"es-node-data": data,
+				},
+			},
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{
+					{
+						Name: "elasticsearch-storage",
+						VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				UpgradeSnapshots: &api.UpgradeSnapshotSpec{VolumeSnapshotClassName: "csi-snapclass"},
+			},
+			Status: api.ElasticsearchStatus{
+				UpgradeSnapshots: []api.UpgradeSnapshotStatus{
+					{Node: "elasticsearch-cd-abc-1", ClaimName: "elasticsearch-elasticsearch-cd-abc-1", SnapshotName: "old"},
+					{Node: "elasticsearch-cd-abc-2", ClaimName: "elasticsearch-elasticsearch-cd-abc-2", SnapshotName: "other"},
+				},
+			},
+		}
+
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+		k8sClient = fake.NewFakeClientWithScheme(s, cluster,
+			newPod("elasticsearch-cd-abc-1-1", "elasticsearch-cd-abc-1", "true", "elasticsearch-elasticsearch-cd-abc-1"),
+			newPod("elasticsearch-m-abc-1", "elasticsearch-cd-abc-1", "false", "elasticsearch-elasticsearch-m-abc-1"),
+		)
+
+		er = &ElasticsearchRequest{client: k8sClient, cluster: cluster}
+		node = &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cd-abc-1"}}}
+	})
+
+	It("should not snapshot the claims if the precheck fails", func() {
+		precheck := er.snapshotBeforeUpgrade(func() error { return ErrFlushShardsFailed }, []NodeTypeInterface{node})
+		Expect(precheck()).To(MatchError(ErrFlushShardsFailed))
+		Expect(cluster.Status.UpgradeSnapshots[0].SnapshotName).To(Equal("old"))
+	})
+
+	It("should snapshot the claims of the data pods and record the snapshots", func() {
+		precheck := er.snapshotBeforeUpgrade(func() error { return nil }, []NodeTypeInterface{node})
+		Expect(precheck()).To(Succeed())
+
+		current := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, current)).To(Succeed())
+		Expect(current.Status.UpgradeSnapshots).To(HaveLen(2))
+		Expect(current.Status.UpgradeSnapshots[0].SnapshotName).To(Equal("other"))
+
+		recorded := current.Status.UpgradeSnapshots[1]
+		Expect(recorded.Node).To(Equal("elasticsearch-cd-abc-1"))
+		Expect(recorded.ClaimName).To(Equal("elasticsearch-elasticsearch-cd-abc-1"))
+
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetAPIVersion(volumeSnapshotAPIVersion)
+		snapshot.SetKind(volumeSnapshotKind)
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: recorded.SnapshotName, Namespace: "openshift-logging"}, snapshot)).To(Succeed())
+
+		claim, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		Expect(claim).To(Equal("elasticsearch-elasticsearch-cd-abc-1"))
+		class, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+		Expect(class).To(Equal("csi-snapclass"))
+	})
+})
@@ -2,12 +2,14 @@ package k8shandler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
@@ -26,10 +28,39 @@ type LogConfig struct {
 	ServerLoglevel string
 	// ServerAppender where to log messages
 	ServerAppender string
+	// DeprecationLoglevel of the deprecation logger
+	DeprecationLoglevel string
+	// SearchSlowlogLoglevel of the search slowlog logger
+	SearchSlowlogLoglevel string
+	// IndexingSlowlogLoglevel of the indexing slowlog logger
+	IndexingSlowlogLoglevel string
+	// Loggers are the levels of additional loggers
+	Loggers []api.ElasticsearchLoggerSpec
+	// RollingMaxFileSize at which the log file of the rolling appender is rolled over
+	RollingMaxFileSize string
+	// RollingMaxFiles to keep of the rolling appender
+	RollingMaxFiles int32
+	// AuditLogger the audit events are written to if they are written by log4j
+	AuditLogger string
+	// AuditAppender where to log the audit events
+	AuditAppender string
+	// AuditMaxFileSize at which the audit log file is rolled over if it is written to the audit log volume
+	AuditMaxFileSize string
+	// AuditMaxFiles to keep in the audit log volume
+	AuditMaxFiles int32
 }
 
 func getLogConfig(annotations map[string]string) LogConfig {
-	config := LogConfig{"info", "info", "console"}
+	config := LogConfig{
+		LogLevel:                "info",
+		ServerLoglevel:          "info",
+		ServerAppender:          "console",
+		DeprecationLoglevel:     "warn",
+		SearchSlowlogLoglevel:   "trace",
+		IndexingSlowlogLoglevel: "trace",
+		RollingMaxFileSize:      "100MB",
+		RollingMaxFiles:         5,
+	}
 	if value, found := annotations[loglevelAnnotation]; found {
 		if strings.TrimSpace(value) != "" {
 			config.LogLevel = value
@@ -81,16 +112,51 @@ func areSelectorsSame(lhs, rhs map[string]string) bool {
 	return true
 }
 
+// mergeSelectors returns the common selectors overridden by the ones of the node. The common
+// selectors are copied as they are shared by all nodes
 func mergeSelectors(nodeSelectors, commonSelectors map[string]string) map[string]string {
-	if commonSelectors == nil {
-		commonSelectors = make(map[string]string)
-	}
+	selectors := make(map[string]string, len(commonSelectors)+len(nodeSelectors))
 
+	for k, v := range commonSelectors {
+		selectors[k] = v
+	}
 	for k, v := range nodeSelectors {
-		commonSelectors[k] = v
+		selectors[k] = v
 	}
 
-	return commonSelectors
+	return selectors
+}
+
+// mergeEnvVars returns the common environment variables with the ones of the node added or
+// replacing the ones with the same name
+func mergeEnvVars(nodeEnvVars, commonEnvVars []v1.EnvVar) []v1.EnvVar {
+	envVars := []v1.EnvVar{}
+	for _, envVar := range commonEnvVars {
+		if !containsEnvVar(envVar.Name, nodeEnvVars) {
+			envVars = appendEnvVars(envVars, []v1.EnvVar{envVar})
+		}
+	}
+	return appendEnvVars(envVars, nodeEnvVars)
+}
+
+// appendEnvVars appends the environment variables of extra not named like one of envVars, so
+// they cannot override the variables of the operator. The first of duplicated names is kept
+func appendEnvVars(envVars, extra []v1.EnvVar) []v1.EnvVar {
+	for _, envVar := range extra {
+		if !containsEnvVar(envVar.Name, envVars) {
+			envVars = append(envVars, envVar)
+		}
+	}
+	return envVars
+}
+
+func containsEnvVar(name string, envVars []v1.EnvVar) bool {
+	for _, envVar := range envVars {
+		if envVar.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func areTolerationsSame(lhs, rhs []v1.Toleration) bool {
@@ -144,12 +210,13 @@ func isTolerationSame(lhs, rhs v1.Toleration) bool {
 		tolerationSecondsBool
 }
 
+// appendTolerations returns the common tolerations followed by the ones of the node without
+// modifying the common tolerations shared by all nodes
 func appendTolerations(nodeTolerations, commonTolerations []v1.Toleration) []v1.Toleration {
-	if commonTolerations == nil {
-		commonTolerations = []v1.Toleration{}
-	}
+	tolerations := make([]v1.Toleration, 0, len(commonTolerations)+len(nodeTolerations))
+	tolerations = append(tolerations, commonTolerations...)
 
-	return append(commonTolerations, nodeTolerations...)
+	return append(tolerations, nodeTolerations...)
 }
 
 func getMasterCount(dpl *api.Elasticsearch) int32 {
@@ -173,6 +240,16 @@ func getDataCount(dpl *api.Elasticsearch) int32 {
 	return dataCount
 }
 
+func getNodeCount(dpl *api.Elasticsearch) int32 {
+	nodeCount := int32(0)
+	for _, node := range dpl.Spec.Nodes {
+		nodeCount += node.NodeCount
+	}
+	return nodeCount
+}
+
+// TODO: move the rules spanning several fields to CEL rules of the CRD once controller-gen and
+// the supported Kubernetes versions allow it, see docs/validation.md
 func isValidMasterCount(dpl *api.Elasticsearch) bool {
 	if len(dpl.Spec.Nodes) == 0 {
 		return true
@@ -204,6 +281,19 @@ func isValidRedundancyPolicy(dpl *api.Elasticsearch) bool {
 	}
 }
 
+// ErrInvalidConfiguration indicates the cluster cannot be reconciled until the user fixes its spec
+var ErrInvalidConfiguration = kverrors.New("invalid configuration")
+
+// IsUserError returns true if the error is caused by the spec of the cluster rather than by a transient
+// failure and retrying before the user made a change is pointless
+func IsUserError(err error) bool {
+	if errors.Is(err, ErrInvalidConfiguration) {
+		return true
+	}
+	root := kverrors.Root(err)
+	return apierrors.IsInvalid(root) || apierrors.IsBadRequest(root)
+}
+
 func (er *ElasticsearchRequest) isValidConf() error {
 	dpl := er.cluster
 
@@ -211,7 +301,7 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidMasterCountCondition, er.client); err != nil {
 			return err
 		}
-		return kverrors.New("invalid master nodes count. Please ensure the total nodes with master roles is less than the maximum",
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid master nodes count. Please ensure the total nodes with master roles is less than the maximum",
 			"maximum", maxMasterCount)
 	} else {
 		if err := updateConditionWithRetry(dpl, v1.ConditionFalse, updateInvalidMasterCountCondition, er.client); err != nil {
@@ -223,7 +313,7 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidDataCountCondition, er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set data count status")
 		}
-		return kverrors.New("no data nodes requested. Please ensure there is at least 1 node with data roles")
+		return kverrors.Wrap(ErrInvalidConfiguration, "no data nodes requested. Please ensure there is at least 1 node with data roles")
 	} else {
 		if err := updateConditionWithRetry(dpl, v1.ConditionFalse, updateInvalidDataCountCondition, er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set data count status")
@@ -234,7 +324,7 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidReplicationCondition, er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set replication status")
 		}
-		return kverrors.New("wrong RedundancyPolicy selected. Choose different RedundancyPolicy or add more nodes with data roles",
+		return kverrors.Wrap(ErrInvalidConfiguration, "wrong RedundancyPolicy selected. Choose different RedundancyPolicy or add more nodes with data roles",
 			"policy", dpl.Spec.RedundancyPolicy)
 	} else {
 		if err := updateConditionWithRetry(dpl, v1.ConditionFalse, updateInvalidReplicationCondition, er.client); err != nil {
@@ -247,13 +337,242 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		if err := updateInvalidUUIDChangeCondition(dpl, v1.ConditionTrue, err.Error(), er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set UUID change status")
 		}
-		return kverrors.Wrap(err, "unsupported change to UUIDs made")
+		return kverrors.Wrap(ErrInvalidConfiguration, "unsupported change to UUIDs made",
+			"reason", err.Error())
 	} else {
 		if err := updateInvalidUUIDChangeCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set UUID change status")
 		}
 	}
 
+	violation, err := er.memoryLockViolation()
+	if err != nil {
+		return err
+	}
+	if violation != "" {
+		if err := updateInvalidMemoryLockCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set memory lock status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "memory lock is not allowed in the namespace",
+			"reason", violation)
+	} else {
+		if err := updateInvalidMemoryLockCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set memory lock status")
+		}
+	}
+
+	violation, err = er.hostTuningViolation()
+	if err != nil {
+		return err
+	}
+	if violation != "" {
+		if err := updateInvalidHostTuningCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set host tuning status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "host tuning is not allowed in the namespace",
+			"reason", violation)
+	} else {
+		if err := updateInvalidHostTuningCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set host tuning status")
+		}
+	}
+
+	violation, err = er.workloadViolation()
+	if err != nil {
+		return err
+	}
+	if violation != "" {
+		if err := updateInvalidWorkloadCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set workload status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid change of the workload of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidWorkloadCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set workload status")
+		}
+	}
+
+	if violation := votingOnlyViolation(dpl); violation != "" {
+		if err := updateInvalidVotingOnlyCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set voting only status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid voting only nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidVotingOnlyCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set voting only status")
+		}
+	}
+
+	if violation := affinityViolation(dpl); violation != "" {
+		if err := updateInvalidAffinityCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set affinity status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid affinity of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidAffinityCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set affinity status")
+		}
+	}
+
+	if violation := placementProfileViolation(dpl); violation != "" {
+		if err := updateInvalidPlacementProfileCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set placement profile status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid placement profile of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidPlacementProfileCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set placement profile status")
+		}
+	}
+
+	if violation := sidecarsViolation(dpl); violation != "" {
+		if err := updateInvalidSidecarsCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set sidecars status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid sidecars of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidSidecarsCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set sidecars status")
+		}
+	}
+
+	if violation := initContainersViolation(dpl); violation != "" {
+		if err := updateInvalidInitContainersCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set init containers status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid init containers of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidInitContainersCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set init containers status")
+		}
+	}
+
+	if violation := jvmHeapViolation(dpl); violation != "" {
+		if err := updateInvalidJVMHeapCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set jvm heap status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid jvm heap of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidJVMHeapCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set jvm heap status")
+		}
+	}
+
+	if violation := jvmOptionsViolation(dpl); violation != "" {
+		if err := updateInvalidJVMOptionsCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set jvm options status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid jvm options of the nodes",
+			"reason", violation)
+	} else {
+		if err := updateInvalidJVMOptionsCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set jvm options status")
+		}
+	}
+
+	if violation := additionalConfigViolation(dpl); violation != "" {
+		if err := updateInvalidAdditionalConfigCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set additional config status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid additional configuration of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidAdditionalConfigCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set additional config status")
+		}
+	}
+
+	if violation := loggingViolation(dpl); violation != "" {
+		if err := updateInvalidLoggingCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set logging status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid logging of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidLoggingCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set logging status")
+		}
+	}
+
+	if violation := tlsViolation(dpl); violation != "" {
+		if err := updateInvalidTLSCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set tls status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid tls of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidTLSCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set tls status")
+		}
+	}
+
+	if violation := realmsViolation(dpl); violation != "" {
+		if err := updateInvalidRealmsCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set realms status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid authentication realms of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidRealmsCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set realms status")
+		}
+	}
+
+	if violation := podSecurityViolation(dpl); violation != "" {
+		if err := updateInvalidPodSecurityCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set pod security status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid pod security of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidPodSecurityCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set pod security status")
+		}
+	}
+
+	if violation := legacyClientsViolation(dpl); violation != "" {
+		if err := updateInvalidLegacyClientsCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set legacy client services status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid legacy client services of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidLegacyClientsCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set legacy client services status")
+		}
+	}
+
+	if violation := auditLoggingViolation(dpl); violation != "" {
+		if err := updateInvalidAuditLoggingCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set audit logging status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid audit logging of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidAuditLoggingCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set audit logging status")
+		}
+	}
+
+	if violation := threadPoolsViolation(dpl); violation != "" {
+		if err := updateInvalidThreadPoolsCondition(dpl, v1.ConditionTrue, violation, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set thread pools status")
+		}
+		return kverrors.Wrap(ErrInvalidConfiguration, "invalid thread pools of the cluster",
+			"reason", violation)
+	} else {
+		if err := updateInvalidThreadPoolsCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set thread pools status")
+		}
+	}
+
 	return nil
 }
 
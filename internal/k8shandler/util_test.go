@@ -3,11 +3,14 @@ package k8shandler
 import (
 	"testing"
 
+	"github.com/ViaQ/logerr/kverrors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 var _ = Describe("util.go", func() {
@@ -129,6 +132,21 @@ func TestSelectorsCommonOverwritten(t *testing.T) {
 	}
 }
 
+func TestSelectorsKeepCommonUnchanged(t *testing.T) {
+	commonSelector := map[string]string{
+		"common": "test",
+	}
+
+	mergeSelectors(map[string]string{"node-role.kubernetes.io/infra": ""}, commonSelector)
+
+	expected := map[string]string{
+		"common": "test",
+	}
+	if !areSelectorsSame(commonSelector, expected) {
+		t.Errorf("Expected the common selectors to remain %v but got %v", expected, commonSelector)
+	}
+}
+
 func TestInvalidRedundancyPolicySpecified(t *testing.T) {
 	esNode := api.ElasticsearchNode{
 		Roles:     []api.ElasticsearchNodeRole{"data"},
@@ -457,3 +475,20 @@ func TestTolerations(t *testing.T) {
 		t.Errorf("Expected %v but got %v", expected, actual)
 	}
 }
+
+func TestIsUserError(t *testing.T) {
+	invalid := kverrors.Wrap(kverrors.Wrap(ErrInvalidConfiguration, "no data nodes requested"), "Failed to reconcile Elasticsearch deployment spec")
+	if !IsUserError(invalid) {
+		t.Error("Expected an invalid configuration to be a user error")
+	}
+
+	rejected := kverrors.Wrap(apierrors.NewInvalid(schema.GroupKind{Kind: "Deployment"}, "elasticsearch-cdm-1", nil), "failed to create deployment")
+	if !IsUserError(rejected) {
+		t.Error("Expected an object rejected by the API server to be a user error")
+	}
+
+	conflict := kverrors.Wrap(apierrors.NewConflict(schema.GroupResource{Resource: "deployments"}, "elasticsearch-cdm-1", nil), "failed to update deployment")
+	if IsUserError(conflict) {
+		t.Error("Expected a conflict to be retried as transient error")
+	}
+}
@@ -0,0 +1,53 @@
+package k8shandler
+
+import (
+	"strconv"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// usesVotingOnlyNodes returns true if the cluster has master eligible nodes which only vote
+func usesVotingOnlyNodes(cluster *api.Elasticsearch) bool {
+	for _, node := range cluster.Spec.Nodes {
+		if isVotingOnlyNode(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeVotingOnly returns whether the node only votes for the master or an empty string
+// if the cluster has no voting only nodes and all nodes keep the default
+func nodeVotingOnly(cluster *api.Elasticsearch, node api.ElasticsearchNode) string {
+	if !usesVotingOnlyNodes(cluster) {
+		return ""
+	}
+	return strconv.FormatBool(isVotingOnlyNode(node))
+}
+
+// votingOnlyViolation returns the reason the voting only nodes of the cluster are invalid or an
+// empty string. Voting only nodes count for the quorum of the masters but cannot be elected, so
+// the cluster needs other master nodes. They are kept out of the shard allocation by holding no data
+func votingOnlyViolation(cluster *api.Elasticsearch) string {
+	electable := false
+	for _, node := range cluster.Spec.Nodes {
+		if !isVotingOnlyNode(node) {
+			if isMasterNode(node) {
+				electable = true
+			}
+			continue
+		}
+
+		if !isMasterNode(node) {
+			return "Nodes with the voting_only role require the master role"
+		}
+		if isDataNode(node) {
+			return "Nodes with the voting_only role cannot have the data role"
+		}
+	}
+
+	if usesVotingOnlyNodes(cluster) && !electable {
+		return "At least one node with the master role must not have the voting_only role"
+	}
+	return ""
+}
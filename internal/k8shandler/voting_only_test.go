@@ -0,0 +1,63 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Voting only nodes", func() {
+	defer GinkgoRecover()
+
+	var cluster *api.Elasticsearch
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster}, NodeCount: 2},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleVotingOnly}, NodeCount: 1},
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+			},
+		}
+	})
+
+	It("should render the voting only setting of the nodes", func() {
+		Expect(nodeVotingOnly(cluster, cluster.Spec.Nodes[0])).To(Equal("false"))
+		Expect(nodeVotingOnly(cluster, cluster.Spec.Nodes[1])).To(Equal("true"))
+
+		envVars := newEnvVars("node", "", map[api.ElasticsearchNodeRole]bool{}, podTemplateOptions{
+			clusterName: "elasticsearch",
+			votingOnly:  nodeVotingOnly(cluster, cluster.Spec.Nodes[1]),
+		})
+		helpers.ExpectEnvVars(envVars).ToIncludeName("IS_VOTING_ONLY").WithValue("true")
+
+		cluster.Spec.Nodes = []api.ElasticsearchNode{cluster.Spec.Nodes[0], cluster.Spec.Nodes[2]}
+		Expect(nodeVotingOnly(cluster, cluster.Spec.Nodes[0])).To(BeEmpty())
+	})
+
+	It("should count the voting only nodes for the quorum", func() {
+		Expect(getMasterCount(cluster)).To(Equal(int32(3)))
+		Expect(votingOnlyViolation(cluster)).To(BeEmpty())
+	})
+
+	It("should require the master role", func() {
+		cluster.Spec.Nodes[1].Roles = []api.ElasticsearchNodeRole{api.ElasticsearchRoleVotingOnly}
+		Expect(votingOnlyViolation(cluster)).To(Equal("Nodes with the voting_only role require the master role"))
+	})
+
+	It("should keep the voting only nodes out of the shard allocation", func() {
+		cluster.Spec.Nodes[1].Roles = append(cluster.Spec.Nodes[1].Roles, api.ElasticsearchRoleData)
+		Expect(votingOnlyViolation(cluster)).To(Equal("Nodes with the voting_only role cannot have the data role"))
+	})
+
+	It("should require a master node which can be elected", func() {
+		cluster.Spec.Nodes[0].Roles = []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient}
+		Expect(votingOnlyViolation(cluster)).To(Equal("At least one node with the master role must not have the voting_only role"))
+	})
+})
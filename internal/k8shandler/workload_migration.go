@@ -0,0 +1,364 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const storageVolumeName = "elasticsearch-storage"
+
+// isStatefulSetNode returns true if the nodes run as a StatefulSet. Master nodes without the
+// data role always do while coordinating, ingest and ml nodes never do
+func isStatefulSetNode(node api.ElasticsearchNode) bool {
+	if isDataNode(node) {
+		return node.Workload == api.ElasticsearchNodeWorkloadStatefulSet
+	}
+	return isMasterNode(node)
+}
+
+// ReconcileWorkloadMigrations moves the shards off the deployments of data nodes once the
+// StatefulSet replacing them is ready and removes the deployments once they hold no shards
+func (er *ElasticsearchRequest) ReconcileWorkloadMigrations() error {
+	cluster := er.cluster
+
+	statuses := []api.WorkloadMigrationStatus{}
+	for _, node := range cluster.Spec.Nodes {
+		if node.GenUUID == nil || !isDataNode(node) || !isStatefulSetNode(node) {
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+		deployments, err := er.listMigratedDeployments(name)
+		if err != nil {
+			return err
+		}
+		if len(deployments) == 0 {
+			continue
+		}
+
+		status := api.WorkloadMigrationStatus{
+			StatefulSetName: name,
+			DeploymentNames: deployments,
+			Phase:           api.WorkloadMigrationPhaseCreatingNodes,
+		}
+
+		ready := true
+		for _, shard := range statefulSetShards(name, node) {
+			shardReady, err := er.isStatefulSetReady(shard.name, shard.replicas)
+			if err != nil {
+				return err
+			}
+			ready = ready && shardReady
+		}
+		if ready {
+			status.Phase = api.WorkloadMigrationPhaseDraining
+		} else {
+			status.Message = fmt.Sprintf("Waiting for the %d nodes of StatefulSet %s to be ready", node.NodeCount, name)
+		}
+		statuses = append(statuses, status)
+	}
+
+	excluded := drainedDeployments(statuses)
+	released := drainedDeployments(cluster.Status.WorkloadMigrations).Difference(excluded)
+
+	if excluded.Len() == 0 && released.Len() == 0 {
+		return er.updateWorkloadMigrationStatus(statuses)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	if err := er.updateAllocationExclusion(excluded.List(), released.List()); err != nil {
+		return err
+	}
+
+	if excluded.Len() == 0 {
+		return er.updateWorkloadMigrationStatus(statuses)
+	}
+
+	counts, err := er.esClient.GetNodeShardCounts()
+	if err != nil {
+		return err
+	}
+
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Phase != api.WorkloadMigrationPhaseDraining {
+			continue
+		}
+
+		status.RemainingShards = 0
+		status.Message = ""
+		for _, name := range status.DeploymentNames {
+			shards, ok := counts[name]
+			if !ok {
+				status.Message = fmt.Sprintf("Waiting for node %s to join the cluster", name)
+			}
+			status.RemainingShards += shards
+		}
+		if status.Message != "" || status.RemainingShards > 0 {
+			continue
+		}
+
+		for _, name := range status.DeploymentNames {
+			if err := er.deleteMigratedDeployment(name); err != nil {
+				return err
+			}
+		}
+		status.Message = "Removed the deployments"
+	}
+
+	return er.updateWorkloadMigrationStatus(statuses)
+}
+
+// workloadViolation returns the reason the workload of a node cannot be changed or an
+// empty string if all changes are allowed
+func (er *ElasticsearchRequest) workloadViolation() (string, error) {
+	cluster := er.cluster
+
+	for _, node := range cluster.Spec.Nodes {
+		if node.GenUUID == nil || !isDataNode(node) {
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%s", cluster.Name, getNodeSuffix(*node.GenUUID, getNodeRoleMap(node)))
+		if !isStatefulSetNode(node) {
+			statefulSet := &apps.StatefulSet{}
+			err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, statefulSet)
+			if err == nil {
+				return fmt.Sprintf("Nodes %s cannot migrate from a StatefulSet back to deployments", name), nil
+			}
+			if !apierrors.IsNotFound(err) {
+				return "", kverrors.Wrap(err, "failed to get statefulset",
+					"statefulset", name)
+			}
+			continue
+		}
+
+		if violation, err := er.maxStatefulSetReplicasViolation(name, node); err != nil || violation != "" {
+			return violation, err
+		}
+
+		if !isMasterNode(node) {
+			continue
+		}
+
+		// the master eligible nodes of both workloads would not agree on a quorum
+		deployments, err := er.listMigratedDeployments(name)
+		if err != nil {
+			return "", err
+		}
+		if len(deployments) > 0 {
+			return fmt.Sprintf("Nodes %s with the master role cannot migrate to a StatefulSet", name), nil
+		}
+	}
+	return "", nil
+}
+
+// maxStatefulSetReplicasViolation rejects a maximum of replicas that would move the running pods of
+// the node between its StatefulSets. Scaling the first StatefulSet down to a lower maximum would
+// remove its pods at once without draining them, and their replacements in the other
+// StatefulSets would start without data
+func (er *ElasticsearchRequest) maxStatefulSetReplicasViolation(name string, node api.ElasticsearchNode) (string, error) {
+	cluster := er.cluster
+
+	first := &apps.StatefulSet{}
+	err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, first)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to get statefulset",
+			"statefulset", name)
+	}
+	current := int32(1)
+	if first.Spec.Replicas != nil {
+		current = *first.Spec.Replicas
+	}
+
+	second := statefulSetShardName(name, 1)
+	err = er.client.Get(context.TODO(), types.NamespacedName{Name: second, Namespace: cluster.Namespace}, &apps.StatefulSet{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", kverrors.Wrap(err, "failed to get statefulset",
+			"statefulset", second)
+	}
+
+	max := node.MaxStatefulSetReplicas
+	if err == nil {
+		// the pods are already split, so the first statefulset holds the maximum they were split by
+		if max != current {
+			return fmt.Sprintf("The maxStatefulSetReplicas of nodes %s cannot change from %d to %d once their pods are split into several StatefulSets",
+				name, current, max), nil
+		}
+		return "", nil
+	}
+
+	if max > 0 && max < current {
+		return fmt.Sprintf("The maxStatefulSetReplicas of nodes %s cannot be lower than the %d pods of their existing StatefulSet",
+			name, current), nil
+	}
+	return "", nil
+}
+
+// getMigratedNodeTypeInterfaces returns the deployment nodes left to migrate to the
+// StatefulSet of the node
+func (er *ElasticsearchRequest) getMigratedNodeTypeInterfaces(uuid string, node api.ElasticsearchNode) ([]NodeTypeInterface, error) {
+	if !isDataNode(node) || !isStatefulSetNode(node) {
+		return nil, nil
+	}
+
+	roleMap := getNodeRoleMap(node)
+	nodeName := fmt.Sprintf("%s-%s", er.cluster.Name, getNodeSuffix(uuid, roleMap))
+
+	names, err := er.listMigratedDeployments(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []NodeTypeInterface{}
+	for _, name := range names {
+		deploymentNode, err := newDeploymentNode(name, node, er.cluster, roleMap, er.client, er.esClient)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, deploymentNode)
+	}
+	return nodes, nil
+}
+
+// listMigratedDeployments returns the names of the deployments of the data nodes
+// replaced by the StatefulSet
+func (er *ElasticsearchRequest) listMigratedDeployments(statefulSetName string) ([]string, error) {
+	deployments := &apps.DeploymentList{}
+	opts := []client.ListOption{
+		client.InNamespace(er.cluster.Namespace),
+		client.MatchingLabels{"cluster-name": er.cluster.Name},
+	}
+	if err := er.client.List(context.TODO(), deployments, opts...); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list deployments",
+			"cluster", er.cluster.Name)
+	}
+
+	names := []string{}
+	for _, deployment := range deployments.Items {
+		if strings.HasPrefix(deployment.Name, statefulSetName+"-") {
+			names = append(names, deployment.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (er *ElasticsearchRequest) isStatefulSetReady(name string, nodeCount int32) (bool, error) {
+	statefulSet := &apps.StatefulSet{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: er.cluster.Namespace}, statefulSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, kverrors.Wrap(err, "failed to get statefulset",
+			"statefulset", name)
+	}
+	return statefulSet.Status.ReadyReplicas >= nodeCount, nil
+}
+
+func (er *ElasticsearchRequest) deleteMigratedDeployment(name string) error {
+	deployment := &apps.Deployment{}
+	if err := er.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: er.cluster.Namespace}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to get deployment",
+			"deployment", name)
+	}
+
+	er.L().Info("Removing migrated node", "deployment", name)
+	if err := er.client.Delete(context.TODO(), deployment); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete deployment",
+			"deployment", name)
+	}
+	return nil
+}
+
+func (er *ElasticsearchRequest) updateWorkloadMigrationStatus(statuses []api.WorkloadMigrationStatus) error {
+	cluster := er.cluster
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+
+	if reflect.DeepEqual(cluster.Status.WorkloadMigrations, statuses) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.WorkloadMigrations, statuses) {
+			return nil
+		}
+
+		cluster.Status.WorkloadMigrations = statuses
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update workload migration status")
+}
+
+// drainedDeployments returns the names of the deployments the shards are moved off by the migrations
+func drainedDeployments(statuses []api.WorkloadMigrationStatus) sets.String {
+	names := sets.NewString()
+	for _, status := range statuses {
+		if status.Phase == api.WorkloadMigrationPhaseDraining {
+			names.Insert(status.DeploymentNames...)
+		}
+	}
+	return names
+}
+
+// newStorageClaimTemplates returns the claim of each pod of a StatefulSet of data nodes
+// which cannot share the claim of the node
+func newStorageClaimTemplates(clusterName string, node api.ElasticsearchNode) []v1.PersistentVolumeClaim {
+	if !isDataNode(node) || usesEphemeralStorage(node) {
+		return nil
+	}
+
+	claim := createPersistentVolumeClaim(storageVolumeName, "", clusterName, v1.PersistentVolumeClaimSpec{
+		AccessModes: []v1.PersistentVolumeAccessMode{
+			v1.ReadWriteOnce,
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceStorage: *node.Storage.Size,
+			},
+		},
+		StorageClassName: node.Storage.StorageClassName,
+	})
+	claim.TypeMeta = metav1.TypeMeta{}
+	return []v1.PersistentVolumeClaim{*claim}
+}
+
+func removeVolume(spec *v1.PodSpec, name string) {
+	volumes := []v1.Volume{}
+	for _, volume := range spec.Volumes {
+		if volume.Name != name {
+			volumes = append(volumes, volume)
+		}
+	}
+	spec.Volumes = volumes
+}
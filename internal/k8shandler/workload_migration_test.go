@@ -0,0 +1,192 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Workload migration", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+		uuid    = "abc"
+	)
+
+	newDeployment := func(name string) *apps.Deployment {
+		return &apps.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "openshift-logging",
+				Labels:    map[string]string{"cluster-name": "elasticsearch"},
+			},
+		}
+	}
+
+	newRequest := func(roles []api.ElasticsearchNodeRole, readyReplicas int32) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     roles,
+						NodeCount: 2,
+						GenUUID:   &uuid,
+						Workload:  api.ElasticsearchNodeWorkloadStatefulSet,
+					},
+				},
+			},
+		}
+		name := "elasticsearch-" + getNodeSuffix(uuid, getNodeRoleMap(cluster.Spec.Nodes[0]))
+		statefulSet := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-logging"},
+			Status:     apps.StatefulSetStatus{ReadyReplicas: readyReplicas},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cd-abc-0",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		request = &ElasticsearchRequest{
+			client: fake.NewFakeClientWithScheme(s, cluster, statefulSet, pod,
+				newDeployment(name+"-1"), newDeployment(name+"-2")),
+			cluster: cluster,
+		}
+	}
+
+	It("should run the data nodes as a StatefulSet with a claim per pod", func() {
+		newRequest([]api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData}, 0)
+		size := resource.MustParse("10Gi")
+		cluster.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{Size: &size}
+
+		nodes, err := request.GetNodeTypeInterface(uuid, cluster.Spec.Nodes[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodes).To(HaveLen(1))
+		statefulSet := nodes[0].(*statefulSetNode).self
+		Expect(statefulSet.Name).To(Equal("elasticsearch-cd-abc"))
+		Expect(*statefulSet.Spec.Replicas).To(Equal(int32(2)))
+		Expect(statefulSet.Spec.VolumeClaimTemplates).To(HaveLen(1))
+		Expect(statefulSet.Spec.VolumeClaimTemplates[0].Name).To(Equal(storageVolumeName))
+		for _, volume := range statefulSet.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal(storageVolumeName))
+		}
+
+		migrated, err := request.getMigratedNodeTypeInterfaces(uuid, cluster.Spec.Nodes[0])
+		Expect(err).To(BeNil())
+		Expect(migrated).To(HaveLen(2))
+		Expect(migrated[0].name()).To(Equal("elasticsearch-cd-abc-1"))
+	})
+
+	It("should wait for the StatefulSet to be ready before draining the deployments", func() {
+		newRequest([]api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData}, 1)
+
+		Expect(request.ReconcileWorkloadMigrations()).To(Succeed())
+		Expect(cluster.Status.WorkloadMigrations).To(HaveLen(1))
+		Expect(cluster.Status.WorkloadMigrations[0].Phase).To(Equal(api.WorkloadMigrationPhaseCreatingNodes))
+	})
+
+	It("should keep the deployments while they hold shards", func() {
+		newRequest([]api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData}, 2)
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_cluster/settings?flat_settings=true&filter_path=persistent": {
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster.routing.allocation.exclude._name": "retired-node"}}`},
+				},
+				"_cluster/settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+				"_cat/allocation?h=node,shards&format=json": {
+					{StatusCode: http.StatusOK, Body: `[
+						{"node": "elasticsearch-cd-abc", "shards": "4"},
+						{"node": "elasticsearch-cd-abc-1", "shards": "3"},
+						{"node": "elasticsearch-cd-abc-2", "shards": "0"}
+					]`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.ReconcileWorkloadMigrations()).To(Succeed())
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "retired-node,elasticsearch-cd-abc-1,elasticsearch-cd-abc-2"}
+		}`)
+		Expect(cluster.Status.WorkloadMigrations).To(HaveLen(1))
+		Expect(cluster.Status.WorkloadMigrations[0].Phase).To(Equal(api.WorkloadMigrationPhaseDraining))
+		Expect(cluster.Status.WorkloadMigrations[0].RemainingShards).To(Equal(int32(3)))
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-cd-abc-1", Namespace: "openshift-logging"}, &apps.Deployment{})).To(Succeed())
+	})
+
+	It("should remove the deployments once they hold no shards", func() {
+		newRequest([]api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData}, 2)
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_cluster/settings?flat_settings=true&filter_path=persistent": {
+					{StatusCode: http.StatusOK, Body: `{"persistent": {}}`},
+					{StatusCode: http.StatusOK, Body: `{"persistent": {"cluster.routing.allocation.exclude._name": "retired-node,elasticsearch-cd-abc-1,elasticsearch-cd-abc-2"}}`},
+				},
+				"_cluster/settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+				"_cat/allocation?h=node,shards&format=json": {
+					{StatusCode: http.StatusOK, Body: `[
+						{"node": "elasticsearch-cd-abc", "shards": "7"},
+						{"node": "elasticsearch-cd-abc-1", "shards": "0"},
+						{"node": "elasticsearch-cd-abc-2", "shards": "0"}
+					]`},
+				},
+			},
+		)
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+
+		Expect(request.ReconcileWorkloadMigrations()).To(Succeed())
+		err := request.client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-cd-abc-1", Namespace: "openshift-logging"}, &apps.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		Expect(request.ReconcileWorkloadMigrations()).To(Succeed())
+		Expect(cluster.Status.WorkloadMigrations).To(BeEmpty())
+		_, _ = chatter.GetRequest("_cluster/settings")
+		req, found := chatter.GetRequest("_cluster/settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{
+			"persistent": {"cluster.routing.allocation.exclude._name": "retired-node"}
+		}`)
+	})
+
+	It("should reject migrating master eligible nodes", func() {
+		newRequest([]api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, 0)
+
+		Expect(request.workloadViolation()).To(Equal(
+			"Nodes elasticsearch-dm-abc with the master role cannot migrate to a StatefulSet"))
+	})
+})
@@ -0,0 +1,148 @@
+package k8shandler
+
+import (
+	"context"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	v1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	zoneDiscoveryName      = "zone-discovery"
+	zoneConfigVolumeName   = "elasticsearch-zone-config"
+	zoneConfigTemplatePath = "/etc/elasticsearch/config-template"
+	defaultZoneTopologyKey = "topology.kubernetes.io/zone"
+	zoneDiscoveryRoleName  = "elasticsearch-zone-discovery"
+)
+
+// zoneDiscoveryScript copies the configuration of the cluster and adds the zone of the Kubernetes node
+// running the pod. Environment variables are resolved when Elasticsearch starts, so the zone is written
+// to a copy of elasticsearch.yml replacing the read-only configmap mount
+const zoneDiscoveryScript = `
+cp -L /etc/elasticsearch/config-template/* /usr/share/java/elasticsearch/config/
+token=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token)
+node=$(curl -s -f --max-time 10 --cacert /var/run/secrets/kubernetes.io/serviceaccount/ca.crt \
+  -H "Authorization: Bearer $token" "https://kubernetes.default.svc/api/v1/nodes/${NODE_NAME}")
+if [ -z "$node" ] ; then
+  echo "Unable to read Kubernetes node ${NODE_NAME}"
+  exit 1
+fi
+zone=$(echo "$node" | sed -n "s|.*\"${TOPOLOGY_KEY//./\\.}\": *\"\([^\"]*\)\".*|\1|p" | head -n 1)
+if [ -z "$zone" ] ; then
+  echo "Kubernetes node ${NODE_NAME} has no ${TOPOLOGY_KEY} label"
+  exit 1
+fi
+echo "Elasticsearch node is in zone ${zone}"
+printf '\nnode.attr.zone: %s\n' "$zone" >> /usr/share/java/elasticsearch/config/elasticsearch.yml
+`
+
+// createOrUpdateZoneDiscoveryRBAC allows the pods of the clusters with zone awareness to read the zone
+// of their Kubernetes node
+func (er *ElasticsearchRequest) createOrUpdateZoneDiscoveryRBAC() error {
+	role := newClusterRole(
+		zoneDiscoveryRoleName,
+		newPolicyRules(
+			newPolicyRule(
+				[]string{""},
+				[]string{"nodes"},
+				[]string{},
+				[]string{"get"},
+				[]string{},
+			),
+		),
+	)
+
+	if err := createOrUpdateClusterRole(role, er.client); err != nil {
+		return err
+	}
+
+	esList := &api.ElasticsearchList{}
+	if err := er.client.List(context.TODO(), esList); err != nil {
+		return err
+	}
+
+	subjects := []rbac.Subject{}
+	for _, es := range esList.Items {
+		if es.Spec.ZoneAwareness == nil {
+			continue
+		}
+		subject := newSubject(
+			"ServiceAccount",
+			es.Name,
+			es.Namespace,
+		)
+		subject.APIGroup = ""
+		subjects = append(subjects, subject)
+	}
+
+	return createOrUpdateClusterRoleBinding(
+		newClusterRoleBinding(zoneDiscoveryRoleName, zoneDiscoveryRoleName, subjects),
+		er.client,
+	)
+}
+
+func zoneTopologyKey(spec *api.ZoneAwarenessSpec) string {
+	if spec.TopologyKey == "" {
+		return defaultZoneTopologyKey
+	}
+	return spec.TopologyKey
+}
+
+func newZoneDiscoveryContainer(imageName string, spec *api.ZoneAwarenessSpec) v1.Container {
+	return v1.Container{
+		Name:            zoneDiscoveryName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", zoneDiscoveryScript},
+		Env: []v1.EnvVar{
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &v1.EnvVarSource{
+					FieldRef: &v1.ObjectFieldSelector{
+						FieldPath: "spec.nodeName",
+					},
+				},
+			},
+			{
+				Name:  "TOPOLOGY_KEY",
+				Value: zoneTopologyKey(spec),
+			},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      "elasticsearch-config",
+				MountPath: zoneConfigTemplatePath,
+			},
+			{
+				Name:      zoneConfigVolumeName,
+				MountPath: elasticsearchConfigPath,
+			},
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+}
+
+// useZoneConfig mounts the configuration written by the zone discovery init container
+func useZoneConfig(container *v1.Container) {
+	for i, mount := range container.VolumeMounts {
+		if mount.MountPath == elasticsearchConfigPath {
+			container.VolumeMounts[i].Name = zoneConfigVolumeName
+		}
+	}
+}
+
+func newZoneConfigVolume() v1.Volume {
+	return v1.Volume{
+		Name: zoneConfigVolumeName,
+		VolumeSource: v1.VolumeSource{
+			EmptyDir: &v1.EmptyDirVolumeSource{},
+		},
+	}
+}
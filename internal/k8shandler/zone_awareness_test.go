@@ -0,0 +1,63 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Zone awareness", func() {
+	defer GinkgoRecover()
+
+	It("should discover the zone of the node before starting elasticsearch", func() {
+		template := newPodTemplateSpec("node", api.ElasticsearchNode{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, podTemplateOptions{
+			clusterName:   "elasticsearch",
+			namespace:     "openshift-logging",
+			zoneAwareness: &api.ZoneAwarenessSpec{TopologyKey: "example.com/rack"},
+		})
+
+		Expect(template.Spec.InitContainers).To(HaveLen(1))
+		Expect(template.Spec.InitContainers[0].Name).To(Equal(zoneDiscoveryName))
+		helpers.ExpectEnvVars(template.Spec.InitContainers[0].Env).ToIncludeName("TOPOLOGY_KEY").WithValue("example.com/rack")
+		Expect(template.Spec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      zoneConfigVolumeName,
+			MountPath: elasticsearchConfigPath,
+		}))
+		Expect(template.Spec.Volumes).To(ContainElement(newZoneConfigVolume()))
+	})
+
+	It("should allow the service accounts of the clusters with zone awareness to read the nodes", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		aware := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "aware", Namespace: "openshift-logging"},
+			Spec:       api.ElasticsearchSpec{ZoneAwareness: &api.ZoneAwarenessSpec{}},
+		}
+		unaware := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "unaware", Namespace: "openshift-logging"},
+		}
+		request := &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, aware, unaware),
+			cluster: aware,
+		}
+
+		Expect(request.createOrUpdateZoneDiscoveryRBAC()).To(Succeed())
+
+		binding := &rbac.ClusterRoleBinding{}
+		Expect(request.client.Get(context.TODO(), types.NamespacedName{Name: zoneDiscoveryRoleName}, binding)).To(Succeed())
+		Expect(binding.Subjects).To(ConsistOf(rbac.Subject{Kind: "ServiceAccount", Name: "aware", Namespace: "openshift-logging"}))
+	})
+})
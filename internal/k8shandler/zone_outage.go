@@ -0,0 +1,122 @@
+package k8shandler
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"github.com/ViaQ/logerr/kverrors"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+)
+
+const zoneAttributeSetting = "node.attr.zone"
+
+// ReconcileZoneOutageReplicas tracks the zones of the nodes and raises the replicas of the
+// critical indices while a zone has no nodes in the cluster
+func (er *ElasticsearchRequest) ReconcileZoneOutageReplicas() error {
+	cluster := er.cluster
+
+	previous := api.ZoneAwarenessStatus{}
+	if cluster.Status.ZoneAwareness != nil {
+		previous = *cluster.Status.ZoneAwareness
+	}
+
+	if cluster.Spec.ZoneAwareness == nil && len(previous.RaisedIndexPatterns) == 0 {
+		return er.updateZoneAwarenessStatus(nil)
+	}
+
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	nodeZones, err := er.esClient.GetNodesSetting(zoneAttributeSetting)
+	if err != nil {
+		return err
+	}
+
+	available := sets.NewString()
+	for _, zone := range nodeZones {
+		if zone != "" {
+			available.Insert(zone)
+		}
+	}
+
+	// a zone without nodes is unavailable until all nodes joined the cluster again
+	zones := sets.NewString(previous.Zones...).Union(available)
+	if int32(len(nodeZones)) >= getNodeCount(cluster) {
+		zones = available
+	}
+	unavailable := zones.Difference(available)
+
+	status := &api.ZoneAwarenessStatus{
+		Zones:            zones.List(),
+		UnavailableZones: unavailable.List(),
+	}
+
+	var spec *api.ZoneOutageReplicasSpec
+	if cluster.Spec.ZoneAwareness != nil {
+		spec = cluster.Spec.ZoneAwareness.OutageReplicas
+	}
+
+	if unavailable.Len() > 0 && spec != nil {
+		replicas := strconv.Itoa(int(spec.Replicas))
+		for _, pattern := range spec.IndexPatterns {
+			if err := er.setIndexReplicas(pattern, replicas); err != nil {
+				return err
+			}
+		}
+		status.RaisedIndexPatterns = spec.IndexPatterns
+	}
+
+	lowered := sets.NewString(previous.RaisedIndexPatterns...).Difference(sets.NewString(status.RaisedIndexPatterns...))
+	replicas := strconv.Itoa(calculateReplicaCount(cluster))
+	for _, pattern := range lowered.List() {
+		if err := er.setIndexReplicas(pattern, replicas); err != nil {
+			return err
+		}
+	}
+
+	if cluster.Spec.ZoneAwareness == nil {
+		status = nil
+	}
+	return er.updateZoneAwarenessStatus(status)
+}
+
+// hasRaisedReplicas returns true if the replicas of critical indices are raised during a zone outage
+func hasRaisedReplicas(cluster *api.Elasticsearch) bool {
+	return cluster.Status.ZoneAwareness != nil && len(cluster.Status.ZoneAwareness.RaisedIndexPatterns) > 0
+}
+
+func (er *ElasticsearchRequest) setIndexReplicas(pattern, replicas string) error {
+	err := er.esClient.PutIndexSettings(pattern, map[string]string{
+		"index.number_of_replicas": replicas,
+	})
+	return kverrors.Wrap(err, "failed to update replicas of indices",
+		"pattern", pattern,
+		"replicas", replicas)
+}
+
+func (er *ElasticsearchRequest) updateZoneAwarenessStatus(status *api.ZoneAwarenessStatus) error {
+	cluster := er.cluster
+
+	if reflect.DeepEqual(cluster.Status.ZoneAwareness, status) {
+		return nil
+	}
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(cluster.Status.ZoneAwareness, status) {
+			return nil
+		}
+
+		cluster.Status.ZoneAwareness = status
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+	return kverrors.Wrap(retryErr, "failed to update zone awareness status")
+}
@@ -0,0 +1,123 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Zone outage replicas", func() {
+	defer GinkgoRecover()
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+		cluster *api.Elasticsearch
+	)
+
+	newRequest := func(status *api.ZoneAwarenessStatus, nodeSettings string) {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		Expect(api.AddToScheme(s)).To(Succeed())
+
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Nodes: []api.ElasticsearchNode{
+					{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}, NodeCount: 3},
+				},
+				ZoneAwareness: &api.ZoneAwarenessSpec{
+					OutageReplicas: &api.ZoneOutageReplicasSpec{
+						IndexPatterns: []string{"app-*"},
+						Replicas:      2,
+					},
+				},
+			},
+			Status: api.ElasticsearchStatus{ZoneAwareness: status},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cdm-1",
+				Namespace: "openshift-logging",
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+					"es-node-data": "true",
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(
+			map[string]helpers.FakeElasticsearchResponses{
+				"_nodes/settings?flat_settings=true": {
+					{StatusCode: http.StatusOK, Body: nodeSettings},
+				},
+				"app-*/_settings": {
+					{StatusCode: http.StatusOK, Body: `{"acknowledged": true}`},
+				},
+			},
+		)
+		request = &ElasticsearchRequest{
+			client:  fake.NewFakeClientWithScheme(s, cluster, pod),
+			cluster: cluster,
+		}
+		request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+	}
+
+	It("should raise the replicas of the critical indices while a zone has no nodes", func() {
+		newRequest(&api.ZoneAwarenessStatus{Zones: []string{"a", "b", "c"}}, `{"nodes": {
+			"1": {"name": "elasticsearch-cdm-1", "settings": {"node.attr.zone": "a"}},
+			"2": {"name": "elasticsearch-cdm-2", "settings": {"node.attr.zone": "b"}}
+		}}`)
+
+		Expect(request.ReconcileZoneOutageReplicas()).To(Succeed())
+		req, found := chatter.GetRequest("app-*/_settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"index.number_of_replicas": "2"}`)
+		Expect(cluster.Status.ZoneAwareness).To(Equal(&api.ZoneAwarenessStatus{
+			Zones:               []string{"a", "b", "c"},
+			UnavailableZones:    []string{"c"},
+			RaisedIndexPatterns: []string{"app-*"},
+		}))
+	})
+
+	It("should lower the replicas once all zones are back", func() {
+		newRequest(&api.ZoneAwarenessStatus{
+			Zones:               []string{"a", "b", "c"},
+			UnavailableZones:    []string{"c"},
+			RaisedIndexPatterns: []string{"app-*"},
+		}, `{"nodes": {
+			"1": {"name": "elasticsearch-cdm-1", "settings": {"node.attr.zone": "a"}},
+			"2": {"name": "elasticsearch-cdm-2", "settings": {"node.attr.zone": "b"}},
+			"3": {"name": "elasticsearch-cdm-3", "settings": {"node.attr.zone": "c"}}
+		}}`)
+
+		Expect(request.ReconcileZoneOutageReplicas()).To(Succeed())
+		req, found := chatter.GetRequest("app-*/_settings")
+		Expect(found).To(BeTrue())
+		helpers.ExpectJSON(req.Body).ToEqual(`{"index.number_of_replicas": "1"}`)
+		Expect(hasRaisedReplicas(cluster)).To(BeFalse())
+	})
+
+	It("should forget the zones without nodes once all nodes joined the cluster", func() {
+		newRequest(&api.ZoneAwarenessStatus{Zones: []string{"a", "b", "c"}}, `{"nodes": {
+			"1": {"name": "elasticsearch-cdm-1", "settings": {"node.attr.zone": "a"}},
+			"2": {"name": "elasticsearch-cdm-2", "settings": {"node.attr.zone": "b"}},
+			"3": {"name": "elasticsearch-cdm-3", "settings": {"node.attr.zone": "b"}}
+		}}`)
+
+		Expect(request.ReconcileZoneOutageReplicas()).To(Succeed())
+		_, found := chatter.GetRequest("app-*/_settings")
+		Expect(found).To(BeFalse())
+		Expect(cluster.Status.ZoneAwareness.Zones).To(Equal([]string{"a", "b"}))
+	})
+})
@@ -0,0 +1,329 @@
+// Package metrics defines the operator metrics exposed on the manager metrics endpoint
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "eo"
+	metricsSubsystem = "es"
+)
+
+var (
+	snapshotRepositoryHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "snapshot_repository_healthy",
+			Help:      "Whether the last verification and read test of the snapshot repository succeeded.",
+		},
+		[]string{"cluster", "namespace", "repository"},
+	)
+
+	snapshotRepositoryLastCheck = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "snapshot_repository_last_check_timestamp_seconds",
+			Help:      "Time of the last health check of the snapshot repository.",
+		},
+		[]string{"cluster", "namespace", "repository"},
+	)
+
+	snapshotRepositoryLatestSnapshot = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "snapshot_repository_latest_snapshot_timestamp_seconds",
+			Help:      "End time of the most recent successful snapshot in the repository.",
+		},
+		[]string{"cluster", "namespace", "repository"},
+	)
+
+	snapshotRepositoryFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "snapshot_repository_check_failures_total",
+			Help:      "Number of failed health checks of the snapshot repository.",
+		},
+		[]string{"cluster", "namespace", "repository"},
+	)
+
+	nodeClockSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "node_clock_skew_seconds",
+			Help:      "Offset of the node clock from the median clock of the cluster nodes.",
+		},
+		[]string{"cluster", "namespace", "node"},
+	)
+
+	hotShardLoad = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "hot_shard_load_ratio",
+			Help:      "Share of the indexing and search operations of the node served by a hot shard.",
+		},
+		[]string{"cluster", "namespace", "index", "shard", "node", "action"},
+	)
+
+	nodeReplicaGap = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "node_replica_gap",
+			Help:      "Number of desired pods of the node which are not ready.",
+		},
+		[]string{"cluster", "namespace", "node"},
+	)
+
+	nodeUpgradePhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "node_upgrade_phase_seconds",
+			Help:      "Time the node spent in its current upgrade phase.",
+		},
+		[]string{"cluster", "namespace", "node", "phase"},
+	)
+
+	certificateExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "certificate_expiry_timestamp_seconds",
+			Help:      "Expiry time of the certificate stored under the key of the secret of the cluster.",
+		},
+		[]string{"cluster", "namespace", "key"},
+	)
+
+	managedClusterInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "managed_cluster_info",
+			Help:      "Inventory of the clusters managed by the operator. The value is always 1.",
+		},
+		[]string{"cluster", "namespace", "version", "health", "phase", "owner", "operator_version"},
+	)
+
+	managedClusterPendingOperations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "managed_cluster_pending_operations",
+			Help:      "Number of operations the operator has pending on the managed cluster.",
+		},
+		[]string{"cluster", "namespace"},
+	)
+
+	// nodeReplicaGapLabels holds the labels of the nodes reported per cluster to remove the
+	// nodes that were deleted
+	nodeReplicaGapLabels      = map[string][]prometheus.Labels{}
+	nodeReplicaGapLabelsMutex sync.Mutex
+
+	// hotShardLabels holds the labels of the hot shards reported per cluster to remove the
+	// shards that cooled down
+	hotShardLabels      = map[string][]prometheus.Labels{}
+	hotShardLabelsMutex sync.Mutex
+
+	// nodeUpgradePhaseLabels holds the labels of the upgrading nodes reported per cluster to
+	// remove the nodes that completed their upgrade
+	nodeUpgradePhaseLabels      = map[string][]prometheus.Labels{}
+	nodeUpgradePhaseLabelsMutex sync.Mutex
+
+	// certificateExpiryLabels holds the labels of the certificates reported per cluster to remove
+	// the keys that were removed from the secret
+	certificateExpiryLabels      = map[string][]prometheus.Labels{}
+	certificateExpiryLabelsMutex sync.Mutex
+
+	// managedClusterMutex serializes the replacement of the reported inventory
+	managedClusterMutex sync.Mutex
+)
+
+// ManagedCluster is the inventory entry of a cluster reported by the managed cluster metrics
+type ManagedCluster struct {
+	Name              string
+	Namespace         string
+	Version           string
+	Health            string
+	Phase             string
+	Owner             string
+	OperatorVersion   string
+	PendingOperations int
+}
+
+func init() {
+	metrics.Registry.MustRegister(
+		snapshotRepositoryHealthy,
+		snapshotRepositoryLastCheck,
+		snapshotRepositoryLatestSnapshot,
+		snapshotRepositoryFailures,
+		nodeClockSkew,
+		hotShardLoad,
+		nodeReplicaGap,
+		nodeUpgradePhase,
+		certificateExpiry,
+		managedClusterInfo,
+		managedClusterPendingOperations,
+	)
+}
+
+// SetSnapshotRepositoryHealth records the outcome of the last snapshot repository health check
+func SetSnapshotRepositoryHealth(cluster, namespace, repository string, healthy bool, checked time.Time, latestSnapshot *time.Time) {
+	labels := prometheus.Labels{"cluster": cluster, "namespace": namespace, "repository": repository}
+
+	snapshotRepositoryLastCheck.With(labels).Set(float64(checked.Unix()))
+	if latestSnapshot != nil {
+		snapshotRepositoryLatestSnapshot.With(labels).Set(float64(latestSnapshot.Unix()))
+	}
+
+	if healthy {
+		snapshotRepositoryHealthy.With(labels).Set(1)
+	} else {
+		snapshotRepositoryHealthy.With(labels).Set(0)
+	}
+}
+
+// IncSnapshotRepositoryCheckFailures counts a failed snapshot repository health check
+func IncSnapshotRepositoryCheckFailures(cluster, namespace, repository string) {
+	snapshotRepositoryFailures.With(prometheus.Labels{"cluster": cluster, "namespace": namespace, "repository": repository}).Inc()
+}
+
+// DeleteSnapshotRepositoryHealth removes the metrics of a repository that is no longer checked
+func DeleteSnapshotRepositoryHealth(cluster, namespace, repository string) {
+	labels := prometheus.Labels{"cluster": cluster, "namespace": namespace, "repository": repository}
+
+	snapshotRepositoryHealthy.Delete(labels)
+	snapshotRepositoryLastCheck.Delete(labels)
+	snapshotRepositoryLatestSnapshot.Delete(labels)
+	snapshotRepositoryFailures.Delete(labels)
+}
+
+// SetNodeClockSkew records the offset of the node clock from the other nodes of the cluster
+func SetNodeClockSkew(cluster, namespace, node string, skew time.Duration) {
+	nodeClockSkew.With(prometheus.Labels{"cluster": cluster, "namespace": namespace, "node": node}).Set(skew.Seconds())
+}
+
+// SetHotShards records the load of the hot shards of the cluster replacing the previously reported ones
+func SetHotShards(cluster, namespace string, shards []api.HotShard) {
+	hotShardLabelsMutex.Lock()
+	defer hotShardLabelsMutex.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, labels := range hotShardLabels[key] {
+		hotShardLoad.Delete(labels)
+	}
+	delete(hotShardLabels, key)
+
+	for _, shard := range shards {
+		labels := prometheus.Labels{
+			"cluster":   cluster,
+			"namespace": namespace,
+			"index":     shard.Index,
+			"shard":     strconv.Itoa(int(shard.Shard)),
+			"node":      shard.Node,
+			"action":    string(shard.Action),
+		}
+		hotShardLoad.With(labels).Set(float64(shard.LoadPercent) / 100)
+		hotShardLabels[key] = append(hotShardLabels[key], labels)
+	}
+}
+
+// SetNodeReplicaGaps records the number of desired pods of every node of the cluster which are not
+// ready, replacing the previously reported nodes
+func SetNodeReplicaGaps(cluster, namespace string, gaps map[string]int32) {
+	nodeReplicaGapLabelsMutex.Lock()
+	defer nodeReplicaGapLabelsMutex.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, labels := range nodeReplicaGapLabels[key] {
+		nodeReplicaGap.Delete(labels)
+	}
+	delete(nodeReplicaGapLabels, key)
+
+	for node, gap := range gaps {
+		labels := prometheus.Labels{"cluster": cluster, "namespace": namespace, "node": node}
+		nodeReplicaGap.With(labels).Set(float64(gap))
+		nodeReplicaGapLabels[key] = append(nodeReplicaGapLabels[key], labels)
+	}
+}
+
+// NodeUpgradePhase is the upgrade phase of a node and the time spent in it
+type NodeUpgradePhase struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// SetNodeUpgradePhases records the time the upgrading nodes of the cluster spent in their current
+// upgrade phase, replacing the previously reported nodes
+func SetNodeUpgradePhases(cluster, namespace string, phases map[string]NodeUpgradePhase) {
+	nodeUpgradePhaseLabelsMutex.Lock()
+	defer nodeUpgradePhaseLabelsMutex.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, labels := range nodeUpgradePhaseLabels[key] {
+		nodeUpgradePhase.Delete(labels)
+	}
+	delete(nodeUpgradePhaseLabels, key)
+
+	for node, phase := range phases {
+		labels := prometheus.Labels{"cluster": cluster, "namespace": namespace, "node": node, "phase": phase.Phase}
+		nodeUpgradePhase.With(labels).Set(phase.Duration.Seconds())
+		nodeUpgradePhaseLabels[key] = append(nodeUpgradePhaseLabels[key], labels)
+	}
+}
+
+// SetCertificateExpiries records the expiry of the certificates of the secret of the cluster by
+// their keys, replacing the previously reported certificates
+func SetCertificateExpiries(cluster, namespace string, expiries map[string]time.Time) {
+	certificateExpiryLabelsMutex.Lock()
+	defer certificateExpiryLabelsMutex.Unlock()
+
+	key := namespace + "/" + cluster
+	for _, labels := range certificateExpiryLabels[key] {
+		certificateExpiry.Delete(labels)
+	}
+	delete(certificateExpiryLabels, key)
+
+	for certificate, expiry := range expiries {
+		labels := prometheus.Labels{"cluster": cluster, "namespace": namespace, "key": certificate}
+		certificateExpiry.With(labels).Set(float64(expiry.Unix()))
+		certificateExpiryLabels[key] = append(certificateExpiryLabels[key], labels)
+	}
+}
+
+// SetManagedClusters records the inventory of the clusters managed by the operator, replacing the
+// previously reported inventory
+func SetManagedClusters(clusters []ManagedCluster) {
+	managedClusterMutex.Lock()
+	defer managedClusterMutex.Unlock()
+
+	managedClusterInfo.Reset()
+	managedClusterPendingOperations.Reset()
+
+	for _, cluster := range clusters {
+		managedClusterInfo.With(prometheus.Labels{
+			"cluster":          cluster.Name,
+			"namespace":        cluster.Namespace,
+			"version":          cluster.Version,
+			"health":           cluster.Health,
+			"phase":            cluster.Phase,
+			"owner":            cluster.Owner,
+			"operator_version": cluster.OperatorVersion,
+		}).Set(1)
+
+		managedClusterPendingOperations.With(prometheus.Labels{"cluster": cluster.Name, "namespace": cluster.Namespace}).
+			Set(float64(cluster.PendingOperations))
+	}
+}
@@ -0,0 +1,201 @@
+// Package objectstore writes objects to S3 compatible object storage. Requests are signed with
+// AWS Signature Version 4 so any S3 compatible endpoint (AWS, MinIO, Ceph RGW, ...) can be used
+// without an SDK
+package objectstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	amzDateFormat    = "20060102T150405Z"
+	amzDayFormat     = "20060102"
+)
+
+// Credentials of an S3 access key
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Client writes objects to the buckets of an S3 compatible endpoint using path style addressing
+type S3Client struct {
+	endpoint    *url.URL
+	region      string
+	credentials Credentials
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewS3Client returns a client of the endpoint signing the requests for the region
+func NewS3Client(endpoint, region string, credentials Credentials, httpClient *http.Client) (*S3Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse object storage endpoint", "endpoint", endpoint)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, kverrors.New("object storage endpoint must be an http or https URL", "endpoint", endpoint)
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, kverrors.New("object storage credentials are incomplete")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &S3Client{
+		endpoint:    u,
+		region:      region,
+		credentials: credentials,
+		httpClient:  httpClient,
+		now:         time.Now,
+	}, nil
+}
+
+// PutObject writes the body to the key of the bucket, replacing any object with the same key
+func (c *S3Client) PutObject(bucket, key, contentType string, body []byte) error {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + strings.TrimPrefix(key, "/")
+	u.RawPath = ""
+
+	req, err := http.NewRequest(http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return kverrors.Wrap(err, "failed to create object storage request", "bucket", bucket, "key", key)
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, body)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to write object", "bucket", bucket, "key", key)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		// the body of an S3 error is a small XML document naming the error code
+		message, _ := ioutil.ReadAll(resp.Body)
+		return kverrors.New("object storage rejected the object",
+			"bucket", bucket,
+			"key", key,
+			"status", resp.StatusCode,
+			"response", strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request
+func (c *S3Client) sign(req *http.Request, body []byte) {
+	now := c.now().UTC()
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	scope := strings.Join([]string{now.Format(amzDayFormat), c.region, s3Service, "aws4_request"}, "/")
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+	stringToSign := strings.Join([]string{signingAlgorithm, now.Format(amzDateFormat), scope, hashHex([]byte(canonical))}, "\n")
+
+	key := signingKey(c.credentials.SecretAccessKey, now.Format(amzDayFormat), c.region, s3Service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalRequest returns the canonical form of the request and the names of its signed headers
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// canonicalURI encodes each segment of the path once as S3 expects
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	pairs := []string{}
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters of RFC 3986
+func uriEncode(value string) string {
+	encoded := &strings.Builder{}
+	for _, b := range []byte(value) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			encoded.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(encoded, "%%%02X", b)
+	}
+	return encoded.String()
+}
+
+func signingKey(secret, day, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -1,5 +1,7 @@
 package elasticsearch
 
+import "encoding/json"
+
 func NewIndexTemplate(pattern string, aliases []string, shards, replicas int32) *IndexTemplate {
 	template := IndexTemplate{
 		Template: pattern,
@@ -30,7 +32,7 @@ func NewIndex(name string, shards, replicas int32) *Index {
 func (index *Index) AddAlias(name string, isWriteIndex bool) *Index {
 	alias := IndexAlias{}
 	if isWriteIndex {
-		alias.IsWriteIndex = true
+		alias.IsWriteIndex = &isWriteIndex
 	}
 	index.Aliases[name] = alias
 	return index
@@ -44,6 +46,33 @@ type Index struct {
 	Mappings map[string]interface{} `json:"mappings,omitempty"`
 }
 
+// IndexDefinition is an index with flat settings and typeless mappings
+type IndexDefinition struct {
+	Settings map[string]interface{} `json:"settings,omitempty"`
+	Mappings map[string]interface{} `json:"mappings,omitempty"`
+	Aliases  map[string]IndexAlias  `json:"aliases,omitempty"`
+}
+
+// ComponentTemplate is a component template of composable index templates
+type ComponentTemplate struct {
+	Template IndexDefinition `json:"template"`
+}
+
+// ComposableIndexTemplate is an index template composed of component templates
+type ComposableIndexTemplate struct {
+	IndexPatterns []string        `json:"index_patterns"`
+	ComposedOf    []string        `json:"composed_of,omitempty"`
+	Priority      int32           `json:"priority,omitempty"`
+	Template      IndexDefinition `json:"template,omitempty"`
+}
+
+// ComponentTemplatesResponse is the response of the get component template API
+type ComponentTemplatesResponse struct {
+	ComponentTemplates []struct {
+		Name string `json:"name"`
+	} `json:"component_templates"`
+}
+
 type IndexTemplate struct {
 	Template string                `json:"template,omitempty"`
 	Settings IndexSettings         `json:"settings,omitempty"`
@@ -85,8 +114,10 @@ type TranslogIndexSetting struct {
 type Aliases struct {
 }
 
+// IndexAlias is an alias of an index. IsWriteIndex is nil unless set explicitly, since
+// Elasticsearch treats an unset flag differently from false
 type IndexAlias struct {
-	IsWriteIndex bool `json:"is_write_index,omitempty"`
+	IsWriteIndex *bool `json:"is_write_index,omitempty"`
 }
 
 type IndexSettings struct {
@@ -100,6 +131,7 @@ type IndexingSettings struct {
 	Blocks  *IndexBlocksSettings  `json:"blocks,omitempty"`
 	Mapper  *IndexMapperSettings  `json:"mapper,omitempty"`
 	Mapping *IndexMappingSettings `json:"mapping,omitempty"`
+	Routing *IndexRoutingSettings `json:"routing,omitempty"`
 }
 
 type IndexBlocksSettings struct {
@@ -114,6 +146,14 @@ type IndexMappingSettings struct {
 	SingleType bool `json:"single_type"`
 }
 
+type IndexRoutingSettings struct {
+	Allocation IndexAllocationSettings `json:"allocation"`
+}
+
+type IndexAllocationSettings struct {
+	Require map[string]string `json:"require,omitempty"`
+}
+
 type ReIndex struct {
 	Source IndexRef      `json:"source"`
 	Dest   IndexRef      `json:"dest"`
@@ -129,6 +169,91 @@ type IndexRef struct {
 	Index string `json:"index"`
 }
 
+// ReIndexRequest copies the documents of a local index or an index of an external cluster
+type ReIndexRequest struct {
+	Conflicts string        `json:"conflicts,omitempty"`
+	Source    ReIndexSource `json:"source"`
+	Dest      ReIndexDest   `json:"dest"`
+}
+
+type ReIndexSource struct {
+	Remote *RemoteHost `json:"remote,omitempty"`
+	Index  string      `json:"index"`
+	Size   int32       `json:"size,omitempty"`
+}
+
+type RemoteHost struct {
+	Host     string `json:"host"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type ReIndexDest struct {
+	Index  string `json:"index"`
+	OpType string `json:"op_type,omitempty"`
+}
+
+// ReIndexTask is the state of a reindex started without waiting for its completion
+type ReIndexTask struct {
+	Completed bool                   `json:"completed"`
+	Task      ReIndexTaskInfo        `json:"task"`
+	Response  *ReIndexStatus         `json:"response,omitempty"`
+	Error     map[string]interface{} `json:"error,omitempty"`
+}
+
+type ReIndexTaskInfo struct {
+	Status ReIndexStatus `json:"status"`
+}
+
+type ReIndexStatus struct {
+	Total            int64                    `json:"total"`
+	Created          int64                    `json:"created"`
+	Updated          int64                    `json:"updated"`
+	VersionConflicts int64                    `json:"version_conflicts"`
+	Failures         []map[string]interface{} `json:"failures,omitempty"`
+}
+
+type Rollover struct {
+	Conditions RolloverConditions `json:"conditions"`
+}
+
+type RolloverConditions struct {
+	MaxAge  string `json:"max_age,omitempty"`
+	MaxSize string `json:"max_size,omitempty"`
+	MaxDocs int64  `json:"max_docs,omitempty"`
+}
+
+type RolloverResponse struct {
+	OldIndex   string `json:"old_index"`
+	NewIndex   string `json:"new_index"`
+	RolledOver bool   `json:"rolled_over"`
+}
+
+// RemoteClusterInfo is the connection state of a remote cluster
+type RemoteClusterInfo struct {
+	Seeds             []string `json:"seeds"`
+	Connected         bool     `json:"connected"`
+	NumNodesConnected int32    `json:"num_nodes_connected"`
+	SkipUnavailable   bool     `json:"skip_unavailable"`
+}
+
+// FollowIndex replicates a leader index of a remote cluster into a follower index
+type FollowIndex struct {
+	RemoteCluster string `json:"remote_cluster"`
+	LeaderIndex   string `json:"leader_index"`
+}
+
+type FollowerIndicesInfo struct {
+	FollowerIndices []FollowerIndexInfo `json:"follower_indices"`
+}
+
+type FollowerIndexInfo struct {
+	FollowerIndex string `json:"follower_index"`
+	RemoteCluster string `json:"remote_cluster"`
+	LeaderIndex   string `json:"leader_index"`
+	Status        string `json:"status"`
+}
+
 type AliasActions struct {
 	Actions []AliasAction `json:"actions"`
 }
@@ -136,17 +261,37 @@ type AliasActions struct {
 type AliasAction struct {
 	Add         *AddAliasAction    `json:"add,omitempty"`
 	RemoveIndex *RemoveAliasAction `json:"remove_index,omitempty"`
+	Remove      *AliasRef          `json:"remove,omitempty"`
 }
 
-type AddAliasAction struct {
+type AliasRef struct {
 	Index string `json:"index"`
 	Alias string `json:"alias"`
 }
 
+type AddAliasAction struct {
+	Index        string `json:"index"`
+	Alias        string `json:"alias"`
+	IsWriteIndex *bool  `json:"is_write_index,omitempty"`
+}
+
 type RemoveAliasAction struct {
 	Index string `json:"index"`
 }
 
+type CatShardsResponses []CatShardsResponse
+
+// CatShardsResponse holds the allocation and operation counters of a shard copy
+type CatShardsResponse struct {
+	Index         string `json:"index"`
+	Shard         string `json:"shard"`
+	PriRep        string `json:"prirep"`
+	State         string `json:"state"`
+	Node          string `json:"node,omitempty"`
+	IndexingTotal string `json:"indexing.index_total,omitempty"`
+	SearchTotal   string `json:"search.query_total,omitempty"`
+}
+
 type CatIndicesResponses []CatIndicesResponse
 
 type CatIndicesResponse struct {
@@ -160,6 +305,7 @@ type CatIndicesResponse struct {
 	DocsDeleted      string `json:"docs.deleted,omitempty"`
 	StoreSize        string `json:"store.size,omitempty"`
 	PrimaryStoreSize string `json:"pri.store.size,omitempty"`
+	CreationDate     string `json:"creation.date,omitempty"`
 }
 
 type MasterNodeAndNodeStateResponse struct {
@@ -187,3 +333,84 @@ type StatsNode struct {
 	Versions []string       `json:"versions,omitempty"`
 	Count    map[string]int `json:"count,omitempty"`
 }
+
+type LifecyclePolicy struct {
+	Policy LifecyclePolicyBody `json:"policy"`
+}
+
+type LifecyclePolicyBody struct {
+	Phases map[string]LifecyclePhase `json:"phases"`
+}
+
+type LifecyclePhase struct {
+	MinAge  string                 `json:"min_age,omitempty"`
+	Actions map[string]interface{} `json:"actions"`
+}
+
+// InternalUser is a user of the internal user database of the security plugin
+type InternalUser struct {
+	Password      string            `json:"password"`
+	BackendRoles  []string          `json:"backend_roles,omitempty"`
+	SecurityRoles []string          `json:"opendistro_security_roles,omitempty"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+// SecurityRole is a role of the security plugin
+type SecurityRole struct {
+	ClusterPermissions []string                  `json:"cluster_permissions,omitempty"`
+	IndexPermissions   []SecurityIndexPermission `json:"index_permissions,omitempty"`
+}
+
+type SecurityIndexPermission struct {
+	IndexPatterns  []string `json:"index_patterns"`
+	DLS            string   `json:"dls,omitempty"`
+	FLS            []string `json:"fls,omitempty"`
+	MaskedFields   []string `json:"masked_fields,omitempty"`
+	AllowedActions []string `json:"allowed_actions,omitempty"`
+}
+
+// CreateSnapshot takes a snapshot of the given indices
+type CreateSnapshot struct {
+	Indices            string `json:"indices,omitempty"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+}
+
+type SnapshotsResponse struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+type Snapshot struct {
+	Snapshot          string   `json:"snapshot"`
+	State             string   `json:"state,omitempty"`
+	Indices           []string `json:"indices,omitempty"`
+	StartTimeInMillis int64    `json:"start_time_in_millis,omitempty"`
+	EndTimeInMillis   int64    `json:"end_time_in_millis,omitempty"`
+}
+
+// ClusterMetadata holds the metadata of a cluster as returned by Elasticsearch
+type ClusterMetadata struct {
+	IndexTemplates    json.RawMessage `json:"index_templates"`
+	Aliases           json.RawMessage `json:"aliases"`
+	LifecyclePolicies json.RawMessage `json:"lifecycle_policies,omitempty"`
+	Settings          json.RawMessage `json:"settings"`
+}
+
+// Document is a document of an index with its source
+type Document struct {
+	Index  string          `json:"_index,omitempty"`
+	ID     string          `json:"_id"`
+	Found  *bool           `json:"found,omitempty"`
+	Source json.RawMessage `json:"_source,omitempty"`
+}
+
+type SearchResponse struct {
+	Hits SearchHits `json:"hits"`
+}
+
+type SearchHits struct {
+	Hits []Document `json:"hits"`
+}
+
+type MultiGetResponse struct {
+	Docs []Document `json:"docs"`
+}
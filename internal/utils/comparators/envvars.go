@@ -4,6 +4,7 @@ import (
 	"reflect"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 /**
@@ -102,6 +103,16 @@ func EnvFieldRefEqual(lhs, rhs v1.ObjectFieldSelector) bool {
 func EnvResourceFieldRefEqual(lhs, rhs v1.ResourceFieldSelector) bool {
 	// taken from https://godoc.org/k8s.io/api/core/v1#ResourceFieldSelector
 	// divisor's default value is "1"
+	defaultDivisor := resource.MustParse("1")
+
+	if lhs.Divisor.IsZero() {
+		lhs.Divisor = defaultDivisor
+	}
+
+	if rhs.Divisor.IsZero() {
+		rhs.Divisor = defaultDivisor
+	}
+
 	if lhs.Divisor.Cmp(rhs.Divisor) != 0 {
 		return false
 	}
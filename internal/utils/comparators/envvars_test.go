@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestEnvVarEqualEqual(t *testing.T) {
@@ -92,3 +93,39 @@ func TestEnvVarEqualNotEqual2(t *testing.T) {
 		t.Errorf("EnvVarEqual returned true when the desired is longer than the current")
 	}
 }
+
+func TestEnvVarEqualCheckSecretKeyRef(t *testing.T) {
+	newEnv := func(key string) []v1.EnvVar {
+		return []v1.EnvVar{
+			{Name: "S3_ACCESS_KEY", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "s3-credentials"},
+				Key:                  key,
+			}}},
+		}
+	}
+
+	if !EnvValueEqual(newEnv("access-key"), newEnv("access-key")) {
+		t.Errorf("EnvVarEqual returned false for the equal inputs")
+	}
+	if EnvValueEqual(newEnv("access-key"), newEnv("secret-key")) {
+		t.Errorf("EnvVarEqual returned true for the not equal inputs")
+	}
+}
+
+func TestEnvVarEqualDefaultDivisor(t *testing.T) {
+	currentenv := []v1.EnvVar{
+		{Name: "MEMORY_LIMIT", ValueFrom: &v1.EnvVarSource{ResourceFieldRef: &v1.ResourceFieldSelector{
+			Resource: "limits.memory",
+			Divisor:  resource.MustParse("1"),
+		}}},
+	}
+	desiredenv := []v1.EnvVar{
+		{Name: "MEMORY_LIMIT", ValueFrom: &v1.EnvVarSource{ResourceFieldRef: &v1.ResourceFieldSelector{
+			Resource: "limits.memory",
+		}}},
+	}
+
+	if !EnvValueEqual(currentenv, desiredenv) {
+		t.Errorf("EnvVarEqual returned false for the default divisor")
+	}
+}
@@ -27,12 +27,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
+	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	controllers "github.com/openshift/elasticsearch-operator/controllers/logging"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
 	"github.com/openshift/elasticsearch-operator/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -61,12 +64,23 @@ func init() {
 
 func main() {
 	var enableLeaderElection bool
+	var renderConfig string
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&renderConfig, "render-config", "",
+		"Print the configuration of the nodes of the Elasticsearch custom resource in the given file and exit.")
 	flag.Parse()
 
 	log.MustInit("elasticsearch-operator")
+
+	if renderConfig != "" {
+		if err := renderConfiguration(renderConfig); err != nil {
+			log.Error(err, "Failed to render configuration", "file", renderConfig)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	log.Info("starting up...",
 		"operator_version", version.Version,
 		"go_version", runtime.Version(),
@@ -106,9 +120,10 @@ func main() {
 	}
 
 	if err = (&controllers.ElasticsearchReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Elasticsearch"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Elasticsearch"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("elasticsearch-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Elasticsearch")
 		os.Exit(1)
@@ -129,6 +144,56 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Secret")
 		os.Exit(1)
 	}
+	if err = (&controllers.IndexLifecyclePolicyReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ElasticsearchIndexLifecyclePolicy"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("elasticsearch-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchIndexLifecyclePolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchIndexReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ElasticsearchIndex"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchIndex")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchReindexReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ElasticsearchReindex"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchReindex")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchAliasCutoverReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ElasticsearchAliasCutover"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchAliasCutover")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchUserReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ElasticsearchUser"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.ElasticsearchRoleReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ElasticsearchRole"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ElasticsearchRole")
+		os.Exit(1)
+	}
+	addInventory(mgr, namespace)
 	// +kubebuilder:scaffold:builder
 
 	// Add the Metrics Service
@@ -143,6 +208,49 @@ func main() {
 	}
 }
 
+// addInventory registers the controller listing the managed clusters into a ConfigMap of the
+// operator namespace. The inventory only covers all clusters when the operator watches all
+// namespaces and its cache would not see the ConfigMap otherwise
+func addInventory(mgr ctrl.Manager, watchNamespace string) {
+	if watchNamespace != "" {
+		log.Info("Skipping cluster inventory; the operator does not watch all namespaces.")
+		return
+	}
+
+	operatorNs, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		log.Info("Skipping cluster inventory; could not get the operator namespace.", "error", err)
+		return
+	}
+
+	if err := (&controllers.InventoryReconciler{
+		Client:    mgr.GetClient(),
+		Log:       ctrl.Log.WithName("controllers").WithName("Inventory"),
+		Scheme:    mgr.GetScheme(),
+		Namespace: operatorNs,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Inventory")
+		os.Exit(1)
+	}
+}
+
+// renderConfiguration prints the configuration the operator would apply for the Elasticsearch
+// custom resource in the file without connecting to a cluster
+func renderConfiguration(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to read custom resource")
+	}
+	defer f.Close()
+
+	cluster := &loggingv1.Elasticsearch{}
+	if err := yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(cluster); err != nil {
+		return kverrors.Wrap(err, "failed to decode custom resource")
+	}
+
+	return k8shandler.RenderConfiguration(cluster, os.Stdout)
+}
+
 // addMetrics will create the Services and Service Monitors to allow the operator export the metrics by using
 // the Prometheus operator
 func addMetrics(ctx context.Context, cfg *rest.Config) {